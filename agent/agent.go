@@ -19,10 +19,13 @@ import (
 	"fmt"
 	"iter"
 
+	"github.com/google/jsonschema-go/jsonschema"
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/artifact"
 	agentinternal "google.golang.org/adk/internal/agent"
+	"google.golang.org/adk/internal/metrics"
+	"google.golang.org/adk/internal/telemetry"
 	"google.golang.org/adk/memory"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/session"
@@ -55,6 +58,14 @@ func New(cfg Config) (Agent, error) {
 		}
 		subAgentSet[subAgent] = true
 	}
+	inputSchema, err := resolveSchema(cfg.InputSchema)
+	if err != nil {
+		return nil, fmt.Errorf("error creating agent: invalid input schema: %w", err)
+	}
+	outputSchema, err := resolveSchema(cfg.OutputSchema)
+	if err != nil {
+		return nil, fmt.Errorf("error creating agent: invalid output schema: %w", err)
+	}
 	return &agent{
 		name:                 cfg.Name,
 		description:          cfg.Description,
@@ -63,11 +74,20 @@ func New(cfg Config) (Agent, error) {
 		run:                  cfg.Run,
 		afterAgentCallbacks:  cfg.AfterAgentCallbacks,
 		State: agentinternal.State{
-			AgentType: agentinternal.TypeCustomAgent,
+			AgentType:          agentinternal.TypeCustomAgent,
+			ServedInputSchema:  inputSchema,
+			ServedOutputSchema: outputSchema,
 		},
 	}, nil
 }
 
+func resolveSchema(s *jsonschema.Schema) (*jsonschema.Resolved, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return s.Resolve(nil)
+}
+
 // Config is the configuration for creating a new Agent.
 type Config struct {
 	// Name must be a non-empty string, unique within the agent tree.
@@ -99,6 +119,16 @@ type Config struct {
 	// created from the content or error of that callback and the remaining
 	// callbacks will be skipped.
 	AfterAgentCallbacks []AfterAgentCallback
+
+	// InputSchema, if set, is the JSON schema of the messages accepted by the
+	// agent when it is served by the REST API or over A2A. The text of the
+	// messages must be a JSON value matching it, other messages are
+	// rejected. Optional.
+	InputSchema *jsonschema.Schema
+	// OutputSchema, if set, is the JSON schema of the final response of the
+	// invocations when the agent is served by the REST API or over A2A.
+	// A final response that doesn't match it fails the request. Optional.
+	OutputSchema *jsonschema.Schema
 }
 
 // Artifacts interface provides methods to work with artifacts of the current
@@ -156,9 +186,21 @@ func (a *agent) SubAgents() []Agent {
 
 func (a *agent) Run(ctx InvocationContext) iter.Seq2[*session.Event, error] {
 	return func(yield func(*session.Event, error) bool) {
+		spanCtx, spans := telemetry.StartTrace(ctx, "invoke_agent "+a.Name())
+		var traceErr error
+		defer func() {
+			telemetry.EndTrace(spans, traceErr)
+			metrics.RecordAgentRun(a.Name(), traceErr != nil)
+		}()
+		var sessionID string
+		if ctx.Session() != nil {
+			sessionID = ctx.Session().ID()
+		}
+		telemetry.TraceAgentRun(spans, a.Name(), a.Description(), sessionID, ctx.InvocationID())
+
 		// TODO: verify&update the setup here. Should we branch etc.
 		ctx := &invocationContext{
-			Context:   ctx,
+			Context:   spanCtx,
 			agent:     a,
 			artifacts: ctx.Artifacts(),
 			memory:    ctx.Memory(),
@@ -173,6 +215,7 @@ func (a *agent) Run(ctx InvocationContext) iter.Seq2[*session.Event, error] {
 
 		event, err := runBeforeAgentCallbacks(ctx)
 		if event != nil || err != nil {
+			traceErr = err
 			if !yield(event, err) {
 				return
 			}
@@ -183,6 +226,9 @@ func (a *agent) Run(ctx InvocationContext) iter.Seq2[*session.Event, error] {
 		}
 
 		for event, err := range a.run(ctx) {
+			if err != nil {
+				traceErr = err
+			}
 			if event != nil && event.Author == "" {
 				event.Author = getAuthorForEvent(ctx, event)
 			}
@@ -197,6 +243,7 @@ func (a *agent) Run(ctx InvocationContext) iter.Seq2[*session.Event, error] {
 
 		event, err = runAfterAgentCallbacks(ctx)
 		if event != nil || err != nil {
+			traceErr = err
 			yield(event, err)
 		}
 	}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/jsonschema-go/jsonschema"
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/model"
@@ -113,7 +114,8 @@ func TestAgentCallbacks(t *testing.T) {
 			}
 
 			ctx := &invocationContext{
-				agent: testAgent,
+				Context: t.Context(),
+				agent:   testAgent,
 			}
 			var gotEvents []*session.Event
 			for event, err := range testAgent.Run(ctx) {
@@ -159,6 +161,7 @@ func TestEndInvocation_EndsBeforeMainCall(t *testing.T) {
 	}
 
 	ctx := &invocationContext{
+		Context:       t.Context(),
 		agent:         testAgent,
 		endInvocation: true,
 	}
@@ -192,7 +195,8 @@ func TestEndInvocation_EndsAfterMainCall(t *testing.T) {
 	}
 
 	ctx := &invocationContext{
-		agent: testAgent,
+		Context: t.Context(),
+		agent:   testAgent,
 	}
 	var gotEvents []*session.Event
 	for event, err := range testAgent.Run(ctx) {
@@ -242,3 +246,15 @@ func (a *customAgent) Run(ctx InvocationContext) iter.Seq2[*session.Event, error
 		}, nil)
 	}
 }
+
+func TestNew_InvalidSchema(t *testing.T) {
+	invalid := &jsonschema.Schema{Ref: "#/missing"}
+	for _, cfg := range []Config{
+		{Name: "test", InputSchema: invalid},
+		{Name: "test", OutputSchema: invalid},
+	} {
+		if _, err := New(cfg); err == nil {
+			t.Errorf("New() succeeded, want error")
+		}
+	}
+}
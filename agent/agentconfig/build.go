@@ -0,0 +1,266 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agentconfig
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/agent/remoteagent"
+	"google.golang.org/adk/agent/workflowagents/loopagent"
+	"google.golang.org/adk/agent/workflowagents/parallelagent"
+	"google.golang.org/adk/agent/workflowagents/sequentialagent"
+)
+
+// Load builds the agent tree defined in the file at path.
+func Load(ctx context.Context, path string, reg *Registry) (agent.Agent, error) {
+	cfg, err := ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	b := &builder{reg: reg, loading: []string{absPath(path)}}
+	return b.build(ctx, cfg)
+}
+
+// LoadFS builds the agent tree defined in the file name of fsys, e.g. an
+// embedded file system. The paths of the sub-agent configs are resolved in
+// fsys, and the agent cards of remote agents must be http(s) URLs.
+func LoadFS(ctx context.Context, fsys fs.FS, name string, reg *Registry) (agent.Agent, error) {
+	b := &builder{reg: reg, fsys: fsys, loading: []string{path.Clean(name)}}
+	cfg, err := b.readFS(name)
+	if err != nil {
+		return nil, err
+	}
+	return b.build(ctx, cfg)
+}
+
+// Build builds the agent tree defined by cfg.
+func Build(ctx context.Context, cfg *Config, reg *Registry) (agent.Agent, error) {
+	b := &builder{reg: reg}
+	return b.build(ctx, cfg)
+}
+
+// BuildInline builds the agent tree defined in data, e.g. received from a
+// client. The definition must be self-contained: the sub-agents defined in
+// other files and the agent cards of remote agents read from files are
+// rejected.
+func BuildInline(ctx context.Context, data []byte, reg *Registry) (agent.Agent, error) {
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	b := &builder{reg: reg, inline: true}
+	return b.build(ctx, cfg)
+}
+
+type builder struct {
+	reg *Registry
+	// loading are the config files being loaded, to detect cycles.
+	loading []string
+	// inline is set if the files of the server must not be read.
+	inline bool
+	// fsys, if set, is the file system the configs are read from instead of
+	// the files of the server.
+	fsys fs.FS
+}
+
+// readFS reads the agent definition stored in the file name of b.fsys.
+func (b *builder) readFS(name string) (*Config, error) {
+	data, err := fs.ReadFile(b.fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent config: %w", err)
+	}
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	cfg.dir = path.Dir(name)
+	return cfg, nil
+}
+
+func (b *builder) build(ctx context.Context, cfg *Config) (agent.Agent, error) {
+	subAgents := make([]agent.Agent, 0, len(cfg.SubAgents))
+	for _, sub := range cfg.SubAgents {
+		if sub == nil {
+			continue
+		}
+		a, err := b.buildSubAgent(ctx, cfg, sub)
+		if err != nil {
+			return nil, err
+		}
+		subAgents = append(subAgents, a)
+	}
+
+	agentCfg := agent.Config{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		SubAgents:   subAgents,
+	}
+	var a agent.Agent
+	var err error
+	switch cfg.AgentClass {
+	case "", LLMAgentClass:
+		a, err = b.buildLLMAgent(ctx, cfg, subAgents)
+	case SequentialAgentClass:
+		a, err = sequentialagent.New(sequentialagent.Config{AgentConfig: agentCfg})
+	case ParallelAgentClass:
+		a, err = parallelagent.New(parallelagent.Config{AgentConfig: agentCfg})
+	case LoopAgentClass:
+		a, err = loopagent.New(loopagent.Config{AgentConfig: agentCfg, MaxIterations: cfg.MaxIterations})
+	case RemoteA2AAgentClass:
+		a, err = b.buildRemoteA2AAgent(cfg, subAgents)
+	default:
+		return nil, fmt.Errorf("agent %q: unknown agent class %q", cfg.Name, cfg.AgentClass)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create agent %q: %w", cfg.Name, err)
+	}
+	return a, nil
+}
+
+func (b *builder) buildSubAgent(ctx context.Context, parent *Config, sub *SubAgentConfig) (agent.Agent, error) {
+	if sub.ConfigPath == "" {
+		cfg := sub.Config
+		cfg.dir = parent.dir
+		return b.build(ctx, &cfg)
+	}
+	if b.inline {
+		return nil, fmt.Errorf("agent %q: sub-agents must be defined inline, got config path %s", parent.Name, sub.ConfigPath)
+	}
+	if b.fsys != nil {
+		name := path.Join(parent.dir, sub.ConfigPath)
+		if slices.Contains(b.loading, name) {
+			return nil, fmt.Errorf("agent %q: config %s includes itself", parent.Name, sub.ConfigPath)
+		}
+		cfg, err := b.readFS(name)
+		if err != nil {
+			return nil, fmt.Errorf("agent %q: %w", parent.Name, err)
+		}
+		b.loading = append(b.loading, name)
+		defer func() { b.loading = b.loading[:len(b.loading)-1] }()
+		return b.build(ctx, cfg)
+	}
+	file := sub.ConfigPath
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(parent.dir, file)
+	}
+	if slices.Contains(b.loading, absPath(file)) {
+		return nil, fmt.Errorf("agent %q: config %s includes itself", parent.Name, sub.ConfigPath)
+	}
+	cfg, err := ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("agent %q: %w", parent.Name, err)
+	}
+	b.loading = append(b.loading, absPath(file))
+	defer func() { b.loading = b.loading[:len(b.loading)-1] }()
+	return b.build(ctx, cfg)
+}
+
+func (b *builder) buildLLMAgent(ctx context.Context, cfg *Config, subAgents []agent.Agent) (agent.Agent, error) {
+	llmCfg := llmagent.Config{
+		Name:                     cfg.Name,
+		Description:              cfg.Description,
+		SubAgents:                subAgents,
+		Instruction:              cfg.Instruction,
+		GlobalInstruction:        cfg.GlobalInstruction,
+		OutputKey:                cfg.OutputKey,
+		IncludeContents:          llmagent.IncludeContents(cfg.IncludeContents),
+		DisallowTransferToParent: cfg.DisallowTransferToParent,
+		DisallowTransferToPeers:  cfg.DisallowTransferToPeers,
+	}
+	if cfg.Model != "" {
+		m, err := b.reg.model(ctx, cfg.Model)
+		if err != nil {
+			return nil, fmt.Errorf("agent %q: %w", cfg.Name, err)
+		}
+		llmCfg.Model = m
+	}
+	for _, tc := range cfg.Tools {
+		if tc == nil {
+			continue
+		}
+		t, ts, err := b.reg.lookupTool(tc.Name)
+		if err != nil {
+			return nil, fmt.Errorf("agent %q: %w", cfg.Name, err)
+		}
+		if t != nil {
+			llmCfg.Tools = append(llmCfg.Tools, t)
+		} else {
+			llmCfg.Toolsets = append(llmCfg.Toolsets, ts)
+		}
+	}
+	if len(cfg.GenerateContentConfig) > 0 {
+		gcc, err := generateContentConfig(cfg.GenerateContentConfig)
+		if err != nil {
+			return nil, fmt.Errorf("agent %q: %w", cfg.Name, err)
+		}
+		llmCfg.GenerateContentConfig = gcc
+	}
+	return llmagent.New(llmCfg)
+}
+
+func (b *builder) buildRemoteA2AAgent(cfg *Config, subAgents []agent.Agent) (agent.Agent, error) {
+	if len(subAgents) > 0 {
+		return nil, fmt.Errorf("agent %q: remote agents can't have sub-agents", cfg.Name)
+	}
+	if cfg.AgentCard == "" {
+		return nil, fmt.Errorf("agent %q: agent_card is required", cfg.Name)
+	}
+	source := cfg.AgentCard
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		if b.inline || b.fsys != nil {
+			return nil, fmt.Errorf("agent %q: agent_card must be an http(s) URL, got %s", cfg.Name, source)
+		}
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(cfg.dir, source)
+		}
+	}
+	return remoteagent.NewA2A(remoteagent.A2AConfig{
+		Name:            cfg.Name,
+		Description:     cfg.Description,
+		AgentCardSource: source,
+	})
+}
+
+// generateContentConfig converts the generate_content_config field into a
+// genai.GenerateContentConfig.
+func generateContentConfig(m map[string]any) (*genai.GenerateContentConfig, error) {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("invalid generate_content_config: %w", err)
+	}
+	var gcc genai.GenerateContentConfig
+	if err := json.Unmarshal(b, &gcc); err != nil {
+		return nil, fmt.Errorf("invalid generate_content_config: %w", err)
+	}
+	return &gcc, nil
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
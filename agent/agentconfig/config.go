@@ -0,0 +1,150 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package agentconfig builds agent trees from declarative YAML or JSON
+// definitions, so that agents can be changed without recompiling.
+//
+// A definition names the agent class, the model, the instructions, the tools
+// and the sub-agents of an agent:
+//
+//	agent_class: LlmAgent
+//	name: assistant
+//	model: gemini-2.5-flash
+//	instruction: You answer questions about the weather.
+//	tools:
+//	  - name: get_weather
+//	sub_agents:
+//	  - config_path: forecaster.yaml
+//
+// Tools are referenced by name and looked up in a [Registry], where the Go
+// function tools of the application are registered:
+//
+//	reg := agentconfig.NewRegistry()
+//	if err := reg.RegisterTool(getWeather); err != nil {
+//		...
+//	}
+//	a, err := agentconfig.Load(ctx, "agents/assistant.yaml", reg)
+//
+// A remote A2A agent is referenced by its agent card:
+//
+//	agent_class: RemoteA2aAgent
+//	name: billing
+//	agent_card: https://billing.example.com/.well-known/agent-card.json
+//
+// A [DirectoryLoader] serves a directory of apps, e.g. to the REST server,
+// and reloads them when their definitions change.
+package agentconfig
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The agent classes.
+const (
+	LLMAgentClass        = "LlmAgent"
+	SequentialAgentClass = "SequentialAgent"
+	ParallelAgentClass   = "ParallelAgent"
+	LoopAgentClass       = "LoopAgent"
+	// RemoteA2AAgentClass defines an agent served by a remote A2A server,
+	// see remoteagent.NewA2A.
+	RemoteA2AAgentClass = "RemoteA2aAgent"
+)
+
+// Config is the declarative definition of an agent.
+type Config struct {
+	// AgentClass is the type of the agent. If empty, LLMAgentClass is used.
+	AgentClass string `yaml:"agent_class,omitempty"`
+	// Name of the agent, see agent.Config.
+	Name string `yaml:"name"`
+	// Description of the agent, see agent.Config.
+	Description string `yaml:"description,omitempty"`
+	// SubAgents of the agent.
+	SubAgents []*SubAgentConfig `yaml:"sub_agents,omitempty"`
+
+	// The fields below apply to LLM agents, see llmagent.Config.
+
+	// Model is the name of the model used by the agent. The model is created
+	// by the registry. By default, the resource name of a Vertex AI
+	// endpoint, "projects/{project}/locations/{location}/endpoints/{endpoint}",
+	// selects the model deployed to the endpoint.
+	Model                    string        `yaml:"model,omitempty"`
+	Instruction              string        `yaml:"instruction,omitempty"`
+	GlobalInstruction        string        `yaml:"global_instruction,omitempty"`
+	Tools                    []*ToolConfig `yaml:"tools,omitempty"`
+	OutputKey                string        `yaml:"output_key,omitempty"`
+	IncludeContents          string        `yaml:"include_contents,omitempty"`
+	DisallowTransferToParent bool          `yaml:"disallow_transfer_to_parent,omitempty"`
+	DisallowTransferToPeers  bool          `yaml:"disallow_transfer_to_peers,omitempty"`
+	// GenerateContentConfig holds the fields of genai.GenerateContentConfig,
+	// with their JSON names, e.g. temperature or maxOutputTokens.
+	GenerateContentConfig map[string]any `yaml:"generate_content_config,omitempty"`
+
+	// MaxIterations applies to loop agents, see loopagent.Config.
+	MaxIterations uint `yaml:"max_iterations,omitempty"`
+
+	// AgentCard applies to remote A2A agents. It is the URL or the path of
+	// the agent card of the remote agent.
+	AgentCard string `yaml:"agent_card,omitempty"`
+
+	// dir is the directory of the file the config was read from, used to
+	// resolve the paths of the sub-agent configs.
+	dir string
+}
+
+// SubAgentConfig defines a sub-agent, either inline or in another file.
+type SubAgentConfig struct {
+	// ConfigPath is the path of the file defining the sub-agent, relative
+	// to the file of the parent agent.
+	ConfigPath string `yaml:"config_path,omitempty"`
+	// Config defines the sub-agent inline, if ConfigPath is not set.
+	Config `yaml:",inline"`
+}
+
+// ToolConfig references a tool of the registry.
+type ToolConfig struct {
+	// Name of the tool or the tool set in the registry.
+	Name string `yaml:"name"`
+}
+
+// Parse decodes an agent definition in YAML or JSON. Unknown fields are
+// rejected. The paths of the sub-agent configs are resolved relative to the
+// current directory.
+func Parse(data []byte) (*Config, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var cfg Config
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode agent config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// ReadFile reads the agent definition stored in the file at path.
+func ReadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent config: %w", err)
+	}
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	cfg.dir = filepath.Dir(path)
+	return cfg, nil
+}
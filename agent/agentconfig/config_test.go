@@ -0,0 +1,258 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agentconfig_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/agentconfig"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/adk/tool/geminitool"
+)
+
+func newRegistry(t *testing.T, m model.LLM) *agentconfig.Registry {
+	t.Helper()
+	type Args struct {
+		City string `json:"city"`
+	}
+	getWeather, err := functiontool.New(functiontool.Config{Name: "get_weather", Description: "returns the weather"}, func(tool.Context, Args) (map[string]any, error) {
+		return map[string]any{"weather": "sunny"}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg := agentconfig.NewRegistry()
+	if err := reg.RegisterTool(getWeather); err != nil {
+		t.Fatal(err)
+	}
+	reg.RegisterModel("test-model", m)
+	return reg
+}
+
+// tree returns the names of the agents of the tree rooted at a.
+func tree(a agent.Agent) string {
+	if len(a.SubAgents()) == 0 {
+		return a.Name()
+	}
+	var subs []string
+	for _, sub := range a.SubAgents() {
+		subs = append(subs, tree(sub))
+	}
+	return a.Name() + "(" + strings.Join(subs, " ") + ")"
+}
+
+func TestLoad(t *testing.T) {
+	m := &testutil.MockModel{Responses: []*genai.Content{genai.NewContentFromText("sunny", genai.RoleModel)}}
+	a, err := agentconfig.Load(t.Context(), "testdata/root.yaml", newRegistry(t, m))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if diff := cmp.Diff("assistant(pipeline(refiner(writer)) greeter)", tree(a)); diff != "" {
+		t.Errorf("agent tree mismatch (-want +got):\n%s", diff)
+	}
+	if got := a.Description(); got != "Answers questions." {
+		t.Errorf("Description() = %q, want %q", got, "Answers questions.")
+	}
+
+	if _, err := testutil.CollectEvents(testutil.NewTestAgentRunner(t, a).Run(t, "session", "weather?")); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	req := m.Requests[0]
+	if si := req.Config.SystemInstruction; si == nil || !strings.Contains(si.Parts[0].Text, "You answer questions about the weather.") {
+		t.Errorf("SystemInstruction = %v, want the configured instruction", si)
+	}
+	if got := req.Config.Temperature; got == nil || *got != 0.5 {
+		t.Errorf("Temperature = %v, want 0.5", got)
+	}
+	for _, name := range []string{"get_weather", "exit_loop", "transfer_to_agent"} {
+		if _, ok := req.Tools[name]; !ok {
+			t.Errorf("request has no tool %q", name)
+		}
+	}
+}
+
+func TestLoadFS(t *testing.T) {
+	m := &testutil.MockModel{}
+	a, err := agentconfig.LoadFS(t.Context(), os.DirFS("testdata"), "root.yaml", newRegistry(t, m))
+	if err != nil {
+		t.Fatalf("LoadFS() error = %v", err)
+	}
+	if diff := cmp.Diff("assistant(pipeline(refiner(writer)) greeter)", tree(a)); diff != "" {
+		t.Errorf("agent tree mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		fsys    fstest.MapFS
+		wantErr string
+	}{
+		{
+			name: "cycle",
+			fsys: fstest.MapFS{
+				"root.yaml":  {Data: []byte("name: a\nsub_agents:\n  - config_path: sub/b.yaml\n")},
+				"sub/b.yaml": {Data: []byte("name: b\nsub_agents:\n  - config_path: ../root.yaml\n")},
+			},
+			wantErr: "includes itself",
+		},
+		{
+			name:    "agent card file",
+			fsys:    fstest.MapFS{"root.yaml": {Data: []byte("name: a\nagent_class: RemoteA2aAgent\nagent_card: card.json\n")}},
+			wantErr: "agent_card must be an http(s) URL",
+		},
+		{
+			name:    "missing file",
+			fsys:    fstest.MapFS{},
+			wantErr: "failed to read agent config",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := agentconfig.LoadFS(t.Context(), tc.fsys, "root.yaml", agentconfig.NewRegistry())
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("LoadFS() error = %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name:    "unknown field",
+			config:  "name: a\ninstructions: typo\n",
+			wantErr: "field instructions not found",
+		},
+		{
+			name:    "unknown class",
+			config:  "name: a\nagent_class: MagicAgent\n",
+			wantErr: `unknown agent class "MagicAgent"`,
+		},
+		{
+			name:    "unknown tool",
+			config:  "name: a\ntools:\n  - name: missing\n",
+			wantErr: `unknown tool "missing"`,
+		},
+		{
+			name:    "missing sub-agent file",
+			config:  "name: a\nsub_agents:\n  - config_path: testdata/missing.yaml\n",
+			wantErr: "failed to read agent config",
+		},
+		{
+			name:    "cycle",
+			config:  "name: a\nsub_agents:\n  - config_path: testdata/cycle.yaml\n",
+			wantErr: "includes itself",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := agentconfig.Parse([]byte(tc.config))
+			if err == nil {
+				_, err = agentconfig.Build(t.Context(), cfg, agentconfig.NewRegistry())
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("error = %v, want it to contain %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	reg := agentconfig.NewRegistry()
+	if err := reg.RegisterTool(geminitool.GoogleSearch{}); err == nil {
+		t.Errorf("RegisterTool() of a built-in tool name succeeded, want error")
+	}
+
+	var created []string
+	reg.SetModelFactory(func(ctx context.Context, name string) (model.LLM, error) {
+		created = append(created, name)
+		return &testutil.MockModel{}, nil
+	})
+	cfg, err := agentconfig.Parse([]byte("name: a\nmodel: m1\nsub_agents:\n  - name: b\n    model: m1\n  - name: c\n    model: m2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := agentconfig.Build(t.Context(), cfg, reg); err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	// The models are created once.
+	if diff := cmp.Diff([]string{"m1", "m2"}, created); diff != "" {
+		t.Errorf("created models mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBuildInline(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		config   string
+		wantTree string
+		wantErr  string
+	}{
+		{
+			name:     "inline sub-agents",
+			config:   "name: root\nagent_class: SequentialAgent\nsub_agents:\n  - name: first\n    agent_class: LoopAgent\n",
+			wantTree: "root(first)",
+		},
+		{
+			name:     "remote agent",
+			config:   "name: billing\nagent_class: RemoteA2aAgent\nagent_card: https://billing.example.com/.well-known/agent-card.json\n",
+			wantTree: "billing",
+		},
+		{
+			name:    "sub-agent file",
+			config:  "name: root\nsub_agents:\n  - config_path: testdata/root.yaml\n",
+			wantErr: "sub-agents must be defined inline",
+		},
+		{
+			name:    "agent card file",
+			config:  "name: billing\nagent_class: RemoteA2aAgent\nagent_card: /etc/card.json\n",
+			wantErr: "must be an http(s) URL",
+		},
+		{
+			name:    "no agent card",
+			config:  "name: billing\nagent_class: RemoteA2aAgent\n",
+			wantErr: "agent_card is required",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a, err := agentconfig.BuildInline(t.Context(), []byte(tc.config), agentconfig.NewRegistry())
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Errorf("BuildInline() error = %v, want it to contain %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BuildInline() error = %v", err)
+			}
+			if diff := cmp.Diff(tc.wantTree, tree(a)); diff != "" {
+				t.Errorf("agent tree mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agentconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"plugin"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/adk/agent"
+)
+
+const (
+	// RootAgentFile is the name of the file defining the root agent of an
+	// app directory.
+	RootAgentFile = "root_agent.yaml"
+	// PluginFile is the name of the Go plugin defining the root agent of an
+	// app directory. The plugin must export a RootAgent variable of type
+	// agent.Agent.
+	PluginFile = "agent.so"
+)
+
+// errNoApp is returned by loadApp for directories that don't define an app.
+var errNoApp = errors.New("no app definition")
+
+// DirectoryLoader is an [agent.ReloadableLoader] serving the apps found in
+// the subdirectories of a directory, one app per subdirectory, named after
+// it:
+//
+//	agents/
+//	  weather/
+//	    root_agent.yaml
+//	    forecaster.yaml
+//	  support/
+//	    agent.so
+//
+// An app is defined either by a RootAgentFile, or by a PluginFile built with
+// "go build -buildmode=plugin". Go plugins can't be unloaded, so a changed
+// plugin is only used after a restart. Subdirectories without a definition
+// are ignored.
+type DirectoryLoader struct {
+	dir string
+	reg *Registry
+
+	mu   sync.RWMutex
+	apps map[string]*app
+	// stamp identifies the state of the definition files the apps were
+	// built from.
+	stamp string
+}
+
+type app struct {
+	root agent.Agent
+	// stamp identifies the state of the definition files of the app.
+	stamp string
+}
+
+// NewDirectoryLoader returns a loader serving the apps found in dir.
+func NewDirectoryLoader(ctx context.Context, dir string, reg *Registry) (*DirectoryLoader, error) {
+	l := &DirectoryLoader{dir: dir, reg: reg}
+	if err := l.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// ListAgents implements agent.Loader. It returns the names of the apps.
+func (l *DirectoryLoader) ListAgents() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	names := make([]string, 0, len(l.apps))
+	for name := range l.apps {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// LoadAgent implements agent.Loader. It returns the root agent of the app
+// with the given name.
+func (l *DirectoryLoader) LoadAgent(name string) (agent.Agent, error) {
+	l.mu.RLock()
+	a, ok := l.apps[name]
+	l.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("app %s not found. Please specify one of those: %v", name, l.ListAgents())
+	}
+	return a.root, nil
+}
+
+// RootAgent implements agent.Loader. It returns the root agent of the first
+// app in alphabetical order, or nil if there are no apps.
+func (l *DirectoryLoader) RootAgent() agent.Agent {
+	names := l.ListAgents()
+	if len(names) == 0 {
+		return nil
+	}
+	a, err := l.LoadAgent(names[0])
+	if err != nil {
+		return nil
+	}
+	return a
+}
+
+// Reload implements agent.ReloadableLoader. Only the apps whose definitions
+// changed are rebuilt. If an app can't be built, its previous version is
+// kept and the error is reported after the other apps are reloaded.
+func (l *DirectoryLoader) Reload(ctx context.Context) error {
+	stamp, err := dirStamp(l.dir)
+	if err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read the apps directory: %w", err)
+	}
+
+	l.mu.RLock()
+	previous := l.apps
+	l.mu.RUnlock()
+
+	apps := make(map[string]*app)
+	var errs []error
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		name := e.Name()
+		a, err := l.loadApp(ctx, filepath.Join(l.dir, name), previous[name])
+		switch {
+		case errors.Is(err, errNoApp):
+		case err != nil:
+			errs = append(errs, fmt.Errorf("failed to load app %s: %w", name, err))
+			if p, ok := previous[name]; ok {
+				apps[name] = p
+			}
+		default:
+			apps[name] = a
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.apps = apps
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	l.stamp = stamp
+	return nil
+}
+
+// loadApp builds the app defined in dir, reusing previous if its
+// definitions didn't change.
+func (l *DirectoryLoader) loadApp(ctx context.Context, dir string, previous *app) (*app, error) {
+	stamp, err := dirStamp(dir)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil && previous.stamp == stamp {
+		return previous, nil
+	}
+
+	path := filepath.Join(dir, RootAgentFile)
+	if _, err := os.Stat(path); err == nil {
+		root, err := Load(ctx, path, l.reg)
+		if err != nil {
+			return nil, err
+		}
+		return &app{root: root, stamp: stamp}, nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	path = filepath.Join(dir, PluginFile)
+	if _, err := os.Stat(path); err == nil {
+		root, err := loadPlugin(path)
+		if err != nil {
+			return nil, err
+		}
+		return &app{root: root, stamp: stamp}, nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return nil, errNoApp
+}
+
+// loadPlugin returns the RootAgent exported by the Go plugin at path.
+func loadPlugin(path string) (agent.Agent, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open plugin: %w", err)
+	}
+	sym, err := p.Lookup("RootAgent")
+	if err != nil {
+		return nil, err
+	}
+	root, ok := sym.(*agent.Agent)
+	if !ok {
+		return nil, fmt.Errorf("plugin RootAgent has type %T, want agent.Agent", sym)
+	}
+	if *root == nil {
+		return nil, errors.New("plugin RootAgent is nil")
+	}
+	return *root, nil
+}
+
+// Watch implements agent.ReloadableLoader. Apps added to the directory are
+// served once they are found. Reload failures are logged, and retried on the
+// next change.
+func (l *DirectoryLoader) Watch(ctx context.Context, interval time.Duration) {
+	watch(ctx, interval, l.dir, &l.mu, &l.stamp, l.Reload)
+}
+
+var _ agent.ReloadableLoader = (*DirectoryLoader)(nil)
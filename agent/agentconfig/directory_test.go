@@ -0,0 +1,114 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agentconfig_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/agent/agentconfig"
+	"google.golang.org/adk/internal/testutil"
+)
+
+// writeApp defines an app with a root agent named name in dir/app.
+func writeApp(t *testing.T, dir, app, name string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, app), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	writeDefinition(t, filepath.Join(dir, app, agentconfig.RootAgentFile), name)
+}
+
+func TestDirectoryLoader(t *testing.T) {
+	dir := t.TempDir()
+	writeApp(t, dir, "weather", "forecaster")
+	writeApp(t, dir, "support", "helper")
+	if err := os.Mkdir(filepath.Join(dir, "docs"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := agentconfig.NewDirectoryLoader(t.Context(), dir, newRegistry(t, &testutil.MockModel{}))
+	if err != nil {
+		t.Fatalf("NewDirectoryLoader() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"support", "weather"}, l.ListAgents()); diff != "" {
+		t.Errorf("ListAgents() mismatch (-want +got):\n%s", diff)
+	}
+	weather, err := l.LoadAgent("weather")
+	if err != nil {
+		t.Fatalf("LoadAgent() error = %v", err)
+	}
+	if got := weather.Name(); got != "forecaster" {
+		t.Errorf("LoadAgent(%q).Name() = %q, want %q", "weather", got, "forecaster")
+	}
+	if got := l.RootAgent().Name(); got != "helper" {
+		t.Errorf("RootAgent().Name() = %q, want %q", got, "helper")
+	}
+	if _, err := l.LoadAgent("docs"); err == nil {
+		t.Errorf("LoadAgent(%q) succeeded, want error", "docs")
+	}
+
+	writeApp(t, dir, "support", "assistant")
+	writeApp(t, dir, "billing", "cashier")
+	if err := l.Reload(t.Context()); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"billing", "support", "weather"}, l.ListAgents()); diff != "" {
+		t.Errorf("ListAgents() after reload mismatch (-want +got):\n%s", diff)
+	}
+	support, err := l.LoadAgent("support")
+	if err != nil {
+		t.Fatalf("LoadAgent() error = %v", err)
+	}
+	if got := support.Name(); got != "assistant" {
+		t.Errorf("LoadAgent(%q).Name() = %q, want %q", "support", got, "assistant")
+	}
+	if got, err := l.LoadAgent("weather"); err != nil || got != weather {
+		t.Errorf("LoadAgent(%q) = %v, %v, want the unchanged agent", "weather", got, err)
+	}
+}
+
+func TestDirectoryLoader_InvalidApp(t *testing.T) {
+	dir := t.TempDir()
+	writeApp(t, dir, "weather", "forecaster")
+	l, err := agentconfig.NewDirectoryLoader(t.Context(), dir, newRegistry(t, &testutil.MockModel{}))
+	if err != nil {
+		t.Fatalf("NewDirectoryLoader() error = %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "weather", agentconfig.RootAgentFile), []byte("name: [invalid"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "support"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "support", agentconfig.PluginFile), []byte("not a plugin"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	writeApp(t, dir, "billing", "cashier")
+	if err := l.Reload(t.Context()); err == nil {
+		t.Errorf("Reload() succeeded with invalid apps, want error")
+	}
+
+	if diff := cmp.Diff([]string{"billing", "weather"}, l.ListAgents()); diff != "" {
+		t.Errorf("ListAgents() mismatch (-want +got):\n%s", diff)
+	}
+	if a, err := l.LoadAgent("weather"); err != nil || a.Name() != "forecaster" {
+		t.Errorf("LoadAgent(%q) = %v, %v, want the previous agent", "weather", a, err)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agentconfig
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/logging"
+)
+
+// FileLoader is an [agent.ReloadableLoader] serving the agent tree defined
+// in a file. The definitions are watched in the directory of the file and
+// its subdirectories.
+type FileLoader struct {
+	path string
+	reg  *Registry
+
+	mu     sync.RWMutex
+	loader agent.Loader
+	// stamp identifies the state of the definition files the loader was
+	// built from.
+	stamp string
+}
+
+// NewFileLoader returns a loader serving the agent tree defined in the file
+// at path.
+func NewFileLoader(ctx context.Context, path string, reg *Registry) (*FileLoader, error) {
+	l := &FileLoader{path: path, reg: reg}
+	if err := l.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// ListAgents implements agent.Loader.
+func (l *FileLoader) ListAgents() []string {
+	return l.current().ListAgents()
+}
+
+// LoadAgent implements agent.Loader.
+func (l *FileLoader) LoadAgent(name string) (agent.Agent, error) {
+	return l.current().LoadAgent(name)
+}
+
+// RootAgent implements agent.Loader.
+func (l *FileLoader) RootAgent() agent.Agent {
+	return l.current().RootAgent()
+}
+
+func (l *FileLoader) current() agent.Loader {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.loader
+}
+
+// Reload implements agent.ReloadableLoader.
+func (l *FileLoader) Reload(ctx context.Context) error {
+	stamp, err := dirStamp(filepath.Dir(l.path))
+	if err != nil {
+		return err
+	}
+	a, err := Load(ctx, l.path, l.reg)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.loader = agent.NewSingleLoader(a)
+	l.stamp = stamp
+	return nil
+}
+
+// Watch implements agent.ReloadableLoader. Reload failures are logged, and
+// retried on the next change.
+func (l *FileLoader) Watch(ctx context.Context, interval time.Duration) {
+	watch(ctx, interval, filepath.Dir(l.path), &l.mu, &l.stamp, l.Reload)
+}
+
+// watch calls reload whenever the stamp of dir differs from *stamp, checking
+// every interval until ctx is done.
+func watch(ctx context.Context, interval time.Duration, dir string, mu *sync.RWMutex, stamp *string, reload func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	failed := ""
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		current, err := dirStamp(dir)
+		if err != nil {
+			logging.FromContext(ctx).Warn("failed to check the agent definitions", "dir", dir, "error", err)
+			continue
+		}
+		mu.RLock()
+		changed := current != *stamp && current != failed
+		mu.RUnlock()
+		if !changed {
+			continue
+		}
+		if err := reload(ctx); err != nil {
+			// Don't retry until the definitions change again.
+			failed = current
+			logging.FromContext(ctx).Error("failed to reload the agents", "dir", dir, "error", err)
+			continue
+		}
+		failed = ""
+		logging.FromContext(ctx).Info("reloaded the agents", "dir", dir)
+	}
+}
+
+// dirStamp returns a value that changes when the agent definition files in
+// dir or its subdirectories are added, removed or modified.
+func dirStamp(dir string) (string, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isDefinitionFile(path) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%s %d %d\n", path, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to scan agent definitions: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func isDefinitionFile(path string) bool {
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml", ".json", ".so":
+		return true
+	}
+	return false
+}
+
+var _ agent.ReloadableLoader = (*FileLoader)(nil)
@@ -0,0 +1,92 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agentconfig_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/adk/agent/agentconfig"
+	"google.golang.org/adk/internal/testutil"
+)
+
+// writeDefinition writes an agent definition named name to path, moving its
+// modification time forward so that the change is detected even on file
+// systems with a coarse time resolution.
+func writeDefinition(t *testing.T, path, name string) {
+	t.Helper()
+	data := "name: " + name + "\nmodel: test-model\ninstruction: You help.\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Now().Add(time.Duration(len(name)) * time.Second)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileLoader_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.yaml")
+	writeDefinition(t, path, "first")
+	l, err := agentconfig.NewFileLoader(t.Context(), path, newRegistry(t, &testutil.MockModel{}))
+	if err != nil {
+		t.Fatalf("NewFileLoader() error = %v", err)
+	}
+	if got := l.RootAgent().Name(); got != "first" {
+		t.Fatalf("RootAgent().Name() = %q, want %q", got, "first")
+	}
+
+	writeDefinition(t, path, "second")
+	if err := l.Reload(t.Context()); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if got := l.RootAgent().Name(); got != "second" {
+		t.Errorf("RootAgent().Name() = %q, want %q", got, "second")
+	}
+	if _, err := l.LoadAgent("second"); err != nil {
+		t.Errorf("LoadAgent() error = %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("name: [invalid"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Reload(t.Context()); err == nil {
+		t.Errorf("Reload() succeeded with an invalid definition, want error")
+	}
+	if got := l.RootAgent().Name(); got != "second" {
+		t.Errorf("RootAgent().Name() = %q after a failed reload, want %q", got, "second")
+	}
+}
+
+func TestFileLoader_Watch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent.yaml")
+	writeDefinition(t, path, "first")
+	l, err := agentconfig.NewFileLoader(t.Context(), path, newRegistry(t, &testutil.MockModel{}))
+	if err != nil {
+		t.Fatalf("NewFileLoader() error = %v", err)
+	}
+	go l.Watch(t.Context(), 10*time.Millisecond)
+
+	writeDefinition(t, path, "second")
+	deadline := time.Now().Add(5 * time.Second)
+	for l.RootAgent().Name() != "second" {
+		if time.Now().After(deadline) {
+			t.Fatalf("RootAgent().Name() = %q, the change was not picked up", l.RootAgent().Name())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
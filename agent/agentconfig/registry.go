@@ -0,0 +1,158 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agentconfig
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/model/gemini"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/exitlooptool"
+	"google.golang.org/adk/tool/geminitool"
+	"google.golang.org/adk/tool/loadartifactstool"
+)
+
+// ModelFactory creates the model with the given name.
+type ModelFactory func(ctx context.Context, name string) (model.LLM, error)
+
+// Registry holds the tools, tool sets and models that agent definitions
+// reference by name. It is safe for concurrent use.
+type Registry struct {
+	mu       sync.RWMutex
+	tools    map[string]tool.Tool
+	toolsets map[string]tool.Toolset
+	models   map[string]model.LLM
+	factory  ModelFactory
+}
+
+// NewRegistry returns a registry with the built-in tools: google_search,
+// exit_loop and load_artifacts. Models that are not registered are created
+// as Gemini models configured from the environment, see genai.NewClient.
+func NewRegistry() *Registry {
+	r := &Registry{
+		tools:    make(map[string]tool.Tool),
+		toolsets: make(map[string]tool.Toolset),
+		models:   make(map[string]model.LLM),
+	}
+	exitLoop, err := exitlooptool.New()
+	if err != nil {
+		// The tool is statically defined.
+		panic(err)
+	}
+	for _, t := range []tool.Tool{geminitool.GoogleSearch{}, exitLoop, loadartifactstool.New()} {
+		r.tools[t.Name()] = t
+	}
+	return r
+}
+
+// RegisterTool makes the tool available to the agent definitions under its
+// name.
+func (r *Registry) RegisterTool(t tool.Tool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.checkNameLocked(t.Name()); err != nil {
+		return err
+	}
+	r.tools[t.Name()] = t
+	return nil
+}
+
+// RegisterToolset makes the tool set available to the agent definitions
+// under its name.
+func (r *Registry) RegisterToolset(ts tool.Toolset) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.checkNameLocked(ts.Name()); err != nil {
+		return err
+	}
+	r.toolsets[ts.Name()] = ts
+	return nil
+}
+
+func (r *Registry) checkNameLocked(name string) error {
+	if name == "" {
+		return fmt.Errorf("tool name is required")
+	}
+	_, isTool := r.tools[name]
+	_, isToolset := r.toolsets[name]
+	if isTool || isToolset {
+		return fmt.Errorf("tool %q is already registered", name)
+	}
+	return nil
+}
+
+// RegisterModel makes m available to the agent definitions under the given
+// name, instead of the model created by the model factory.
+func (r *Registry) RegisterModel(name string, m model.LLM) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.models[name] = m
+}
+
+// SetModelFactory sets the function creating the models that are not
+// registered.
+func (r *Registry) SetModelFactory(f ModelFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factory = f
+}
+
+// model returns the registered model with the given name, creating it if
+// needed. Created models are registered, so they are shared by the agents.
+func (r *Registry) model(ctx context.Context, name string) (model.LLM, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if m, ok := r.models[name]; ok {
+		return m, nil
+	}
+	factory := r.factory
+	if factory == nil {
+		factory = defaultModelFactory
+	}
+	m, err := factory(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create model %q: %w", name, err)
+	}
+	r.models[name] = m
+	return m, nil
+}
+
+// defaultModelFactory creates Gemini models, or the models of Vertex AI
+// endpoints given their resource name.
+func defaultModelFactory(ctx context.Context, name string) (model.LLM, error) {
+	if strings.HasPrefix(name, "projects/") && strings.Contains(name, "/endpoints/") {
+		return gemini.NewEndpointModel(ctx, gemini.EndpointConfig{EndpointID: name})
+	}
+	return gemini.NewModel(ctx, name, &genai.ClientConfig{})
+}
+
+// lookupTool returns the tool or the tool set with the given name.
+func (r *Registry) lookupTool(name string) (tool.Tool, tool.Toolset, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if t, ok := r.tools[name]; ok {
+		return t, nil, nil
+	}
+	if ts, ok := r.toolsets[name]; ok {
+		return nil, ts, nil
+	}
+	return nil, nil, fmt.Errorf("unknown tool %q", name)
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"sync"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+// ErrLiveRequestQueueClosed is returned when sending to a closed
+// LiveRequestQueue.
+var ErrLiveRequestQueueClosed = errors.New("live request queue is closed")
+
+// LiveRequestQueue passes the user input to an agent running in live mode.
+//
+// The queue is unbounded, sending never blocks. Closing the queue ends the
+// live session once the queued requests are delivered.
+type LiveRequestQueue struct {
+	mu      sync.Mutex
+	pending []*model.LiveRequest
+	closed  bool
+	notify  chan struct{}
+}
+
+// NewLiveRequestQueue creates an empty LiveRequestQueue.
+func NewLiveRequestQueue() *LiveRequestQueue {
+	return &LiveRequestQueue{notify: make(chan struct{}, 1)}
+}
+
+// Send adds req to the queue.
+func (q *LiveRequestQueue) Send(req *model.LiveRequest) error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return ErrLiveRequestQueueClosed
+	}
+	q.pending = append(q.pending, req)
+	q.mu.Unlock()
+	q.wake()
+	return nil
+}
+
+// SendContent sends content as a complete user turn.
+func (q *LiveRequestQueue) SendContent(content *genai.Content) error {
+	return q.Send(&model.LiveRequest{Content: content})
+}
+
+// SendRealtime sends realtime input, e.g. an audio chunk.
+func (q *LiveRequestQueue) SendRealtime(blob *genai.Blob) error {
+	return q.Send(&model.LiveRequest{Blob: blob})
+}
+
+// SendActivityStart signals the start of the user activity.
+func (q *LiveRequestQueue) SendActivityStart() error {
+	return q.Send(&model.LiveRequest{ActivityStart: true})
+}
+
+// SendActivityEnd signals the end of the user activity.
+func (q *LiveRequestQueue) SendActivityEnd() error {
+	return q.Send(&model.LiveRequest{ActivityEnd: true})
+}
+
+// Close closes the queue. It is safe to call Close multiple times.
+func (q *LiveRequestQueue) Close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.wake()
+}
+
+// Receive returns the queued requests in order. The sequence ends when the
+// queue is closed and drained, or when ctx is done. The queue must have a
+// single consumer at a time.
+func (q *LiveRequestQueue) Receive(ctx context.Context) iter.Seq[*model.LiveRequest] {
+	return func(yield func(*model.LiveRequest) bool) {
+		for {
+			q.mu.Lock()
+			if len(q.pending) > 0 {
+				req := q.pending[0]
+				q.pending = q.pending[1:]
+				q.mu.Unlock()
+				if !yield(req) {
+					return
+				}
+				continue
+			}
+			closed := q.closed
+			q.mu.Unlock()
+			if closed {
+				return
+			}
+			select {
+			case <-q.notify:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+func (q *LiveRequestQueue) wake() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+)
+
+func TestLiveRequestQueue(t *testing.T) {
+	q := agent.NewLiveRequestQueue()
+	blob := &genai.Blob{MIMEType: "audio/pcm", Data: []byte{1, 2}}
+	for _, send := range []func() error{
+		q.SendActivityStart,
+		func() error { return q.SendRealtime(blob) },
+		q.SendActivityEnd,
+	} {
+		if err := send(); err != nil {
+			t.Fatalf("send failed: %v", err)
+		}
+	}
+	q.Close()
+	if err := q.SendActivityStart(); !errors.Is(err, agent.ErrLiveRequestQueueClosed) {
+		t.Errorf("send after Close() error = %v, want %v", err, agent.ErrLiveRequestQueueClosed)
+	}
+
+	var got []*model.LiveRequest
+	for req := range q.Receive(t.Context()) {
+		got = append(got, req)
+	}
+	want := []*model.LiveRequest{
+		{ActivityStart: true},
+		{Blob: blob},
+		{ActivityEnd: true},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Receive() mismatch (-want +got):\n%s", diff)
+	}
+}
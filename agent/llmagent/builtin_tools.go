@@ -0,0 +1,177 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmagent
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/internal/toolinternal/toolutils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+// BuiltinToolsMode defines how the agent handles the built-in tools of the
+// model, e.g. geminitool.GoogleSearch, when it also has function tools,
+// toolsets or sub-agents. Most models don't accept built-in tools and
+// function declarations in the same request.
+type BuiltinToolsMode int
+
+const (
+	// SplitBuiltinTools replaces each built-in tool with a function tool of
+	// the same name, which runs the built-in tool in a separate model
+	// request and returns the text of the response.
+	SplitBuiltinTools BuiltinToolsMode = iota
+	// RejectBuiltinTools makes New return an error.
+	RejectBuiltinTools
+	// KeepBuiltinTools passes the built-in tools to the model unchanged, for
+	// the models supporting the combination.
+	KeepBuiltinTools
+)
+
+// planBuiltinTools returns the tools of the agent with its built-in tools
+// handled according to cfg.BuiltinTools.
+func planBuiltinTools(cfg *Config) ([]tool.Tool, error) {
+	var builtins []string
+	hasFunctions := len(cfg.Toolsets) > 0 || len(cfg.SubAgents) > 0
+	for _, t := range cfg.Tools {
+		if _, ok := t.(toolinternal.FunctionTool); ok {
+			hasFunctions = true
+		} else if bt, ok := t.(toolinternal.BuiltinTool); ok && bt.IsBuiltin() {
+			builtins = append(builtins, t.Name())
+		}
+	}
+	if len(builtins) == 0 || !hasFunctions {
+		return cfg.Tools, nil
+	}
+
+	switch cfg.BuiltinTools {
+	case SplitBuiltinTools:
+		tools := make([]tool.Tool, len(cfg.Tools))
+		for i, t := range cfg.Tools {
+			if bt, ok := t.(toolinternal.BuiltinTool); ok && bt.IsBuiltin() {
+				t = &builtinToolCaller{tool: bt, model: cfg.Model}
+			}
+			tools[i] = t
+		}
+		return tools, nil
+	case RejectBuiltinTools:
+		return nil, fmt.Errorf("built-in tools %q can't be combined with function tools, toolsets or sub-agents, use SplitBuiltinTools or move them to a separate agent", builtins)
+	case KeepBuiltinTools:
+		return cfg.Tools, nil
+	}
+	return nil, fmt.Errorf("invalid built-in tools mode %d", cfg.BuiltinTools)
+}
+
+// builtinToolCaller is a function tool running a built-in tool in its own
+// model request.
+type builtinToolCaller struct {
+	tool  toolinternal.BuiltinTool
+	model model.LLM
+}
+
+// Name implements tool.Tool.
+func (c *builtinToolCaller) Name() string {
+	return c.tool.Name()
+}
+
+// Description implements tool.Tool.
+func (c *builtinToolCaller) Description() string {
+	return c.tool.Description()
+}
+
+// IsLongRunning implements tool.Tool.
+func (c *builtinToolCaller) IsLongRunning() bool {
+	return false
+}
+
+// Declaration returns the GenAI FunctionDeclaration of the tool.
+func (c *builtinToolCaller) Declaration() *genai.FunctionDeclaration {
+	return &genai.FunctionDeclaration{
+		Name:        c.tool.Name(),
+		Description: c.tool.Description(),
+		Parameters: &genai.Schema{
+			Type: genai.TypeObject,
+			Properties: map[string]*genai.Schema{
+				"request": {
+					Type:        genai.TypeString,
+					Description: "The request to fulfill with the tool.",
+				},
+			},
+			Required: []string{"request"},
+		},
+	}
+}
+
+// ProcessRequest packs the tool into the LLM request.
+func (c *builtinToolCaller) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	return toolutils.PackTool(req, c)
+}
+
+// Run sends the request to the model with the built-in tool only. It returns
+// the text of the response and, if the response is grounded, its web
+// sources.
+func (c *builtinToolCaller) Run(ctx tool.Context, args any) (map[string]any, error) {
+	m, ok := args.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected args type, got: %T", args)
+	}
+	request, _ := m["request"].(string)
+	if request == "" {
+		return nil, errors.New("request is required")
+	}
+	if c.model == nil {
+		return nil, fmt.Errorf("no model to run the built-in tool %q", c.tool.Name())
+	}
+
+	req := &model.LLMRequest{
+		Model:    c.model.Name(),
+		Contents: []*genai.Content{genai.NewContentFromText(request, genai.RoleUser)},
+		Config:   &genai.GenerateContentConfig{},
+	}
+	if err := c.tool.ProcessRequest(ctx, req); err != nil {
+		return nil, fmt.Errorf("failed to add the built-in tool to the request: %w", err)
+	}
+	var text strings.Builder
+	var sources []map[string]any
+	for resp, err := range c.model.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return nil, err
+		}
+		if resp.Content != nil {
+			for _, p := range resp.Content.Parts {
+				if p.Text != "" && !p.Thought {
+					text.WriteString(p.Text)
+				}
+			}
+		}
+		if resp.GroundingMetadata != nil {
+			for _, chunk := range resp.GroundingMetadata.GroundingChunks {
+				if chunk != nil && chunk.Web != nil {
+					sources = append(sources, map[string]any{"title": chunk.Web.Title, "uri": chunk.Web.URI})
+				}
+			}
+		}
+	}
+	result := map[string]any{"result": text.String()}
+	if len(sources) > 0 {
+		result["sources"] = sources
+	}
+	return result, nil
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmagent
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/session"
+)
+
+// ContentsProcessor selects the session events which are turned into the
+// contents of the model requests. It is called before each model call with
+// the events of the session, in order, where the compacted events are
+// replaced by their summaries. The selected events are then filtered by
+// branch and rearranged as usual.
+type ContentsProcessor interface {
+	ProcessEvents(ctx agent.ReadonlyContext, events []*session.Event) ([]*session.Event, error)
+}
+
+// ContentsProcessorFunc is a function implementing ContentsProcessor.
+type ContentsProcessorFunc func(ctx agent.ReadonlyContext, events []*session.Event) ([]*session.Event, error)
+
+// ProcessEvents implements ContentsProcessor.
+func (f ContentsProcessorFunc) ProcessEvents(ctx agent.ReadonlyContext, events []*session.Event) ([]*session.Event, error) {
+	return f(ctx, events)
+}
+
+// ChainContentsProcessors returns a ContentsProcessor applying the given
+// processors in order.
+func ChainContentsProcessors(processors ...ContentsProcessor) ContentsProcessor {
+	return ContentsProcessorFunc(func(ctx agent.ReadonlyContext, events []*session.Event) ([]*session.Event, error) {
+		var err error
+		for _, p := range processors {
+			events, err = p.ProcessEvents(ctx, events)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return events, nil
+	})
+}
+
+// SlidingWindow keeps the events of the most recent turns. A turn starts
+// with a message of the user. The most recent turn is always kept.
+type SlidingWindow struct {
+	// MaxTurns is the maximum number of turns kept. Zero means no limit.
+	MaxTurns int
+	// MaxTokens is the maximum number of tokens of the kept events, estimated
+	// from the size of their contents. Zero means no limit.
+	MaxTokens int
+}
+
+// ProcessEvents implements ContentsProcessor.
+func (w SlidingWindow) ProcessEvents(ctx agent.ReadonlyContext, events []*session.Event) ([]*session.Event, error) {
+	var turnStarts []int
+	for i, ev := range events {
+		if isUserMessage(ev) {
+			turnStarts = append(turnStarts, i)
+		}
+	}
+	if len(turnStarts) == 0 {
+		return events, nil
+	}
+
+	// Index in turnStarts of the oldest kept turn.
+	first := 0
+	if w.MaxTurns > 0 && len(turnStarts) > w.MaxTurns {
+		first = len(turnStarts) - w.MaxTurns
+	}
+	if w.MaxTokens > 0 {
+		tokens := 0
+		for i := len(events) - 1; i >= turnStarts[first]; i-- {
+			tokens += estimateTokens(events[i])
+		}
+		for first < len(turnStarts)-1 && tokens > w.MaxTokens {
+			for i := turnStarts[first]; i < turnStarts[first+1]; i++ {
+				tokens -= estimateTokens(events[i])
+			}
+			first++
+		}
+	}
+	return events[turnStarts[first]:], nil
+}
+
+func isUserMessage(ev *session.Event) bool {
+	if ev.Author != "user" || ev.Content == nil || ev.Content.Role != genai.RoleUser {
+		return false
+	}
+	return !slices.ContainsFunc(ev.Content.Parts, func(p *genai.Part) bool { return p.FunctionResponse != nil })
+}
+
+// estimateTokens approximates the number of tokens of the event content with
+// four characters per token.
+func estimateTokens(ev *session.Event) int {
+	if ev.Content == nil {
+		return 0
+	}
+	chars := 0
+	for _, p := range ev.Content.Parts {
+		chars += len(p.Text)
+		if p.FunctionCall != nil {
+			args, _ := json.Marshal(p.FunctionCall.Args)
+			chars += len(p.FunctionCall.Name) + len(args)
+		}
+		if p.FunctionResponse != nil {
+			resp, _ := json.Marshal(p.FunctionResponse.Response)
+			chars += len(p.FunctionResponse.Name) + len(resp)
+		}
+	}
+	return (chars + 3) / 4
+}
+
+// AuthorFilter selects the events by author. The messages of the user are
+// always kept.
+type AuthorFilter struct {
+	// Include, if not empty, keeps only the events of these authors.
+	Include []string
+	// Exclude drops the events of these authors.
+	Exclude []string
+}
+
+// ProcessEvents implements ContentsProcessor.
+func (f AuthorFilter) ProcessEvents(ctx agent.ReadonlyContext, events []*session.Event) ([]*session.Event, error) {
+	return slices.DeleteFunc(slices.Clone(events), func(ev *session.Event) bool {
+		if ev.Author == "user" {
+			return false
+		}
+		if len(f.Include) > 0 && !slices.Contains(f.Include, ev.Author) {
+			return true
+		}
+		return slices.Contains(f.Exclude, ev.Author)
+	}), nil
+}
+
+// BranchFilter keeps the events of the given branches and of their
+// sub-branches, e.g. the events of the agents of a parallel agent run. The
+// events without a branch, like the messages of the user, are always kept.
+type BranchFilter struct {
+	Branches []string
+}
+
+// ProcessEvents implements ContentsProcessor.
+func (f BranchFilter) ProcessEvents(ctx agent.ReadonlyContext, events []*session.Event) ([]*session.Event, error) {
+	return slices.DeleteFunc(slices.Clone(events), func(ev *session.Event) bool {
+		if ev.Branch == "" {
+			return false
+		}
+		return !slices.ContainsFunc(f.Branches, func(b string) bool {
+			return ev.Branch == b || strings.HasPrefix(ev.Branch, b+".")
+		})
+	}), nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmagent_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+func textEvent(author, branch, text string) *session.Event {
+	role := genai.RoleModel
+	if author == "user" {
+		role = genai.RoleUser
+	}
+	return &session.Event{
+		Author:      author,
+		Branch:      branch,
+		LLMResponse: model.LLMResponse{Content: genai.NewContentFromText(text, genai.Role(role))},
+	}
+}
+
+func eventTexts(events []*session.Event) []string {
+	var texts []string
+	for _, ev := range events {
+		texts = append(texts, ev.Content.Parts[0].Text)
+	}
+	return texts
+}
+
+func TestContentsProcessors(t *testing.T) {
+	events := []*session.Event{
+		textEvent("user", "", "q1"),
+		textEvent("agent", "root", "a1"),
+		textEvent("user", "", "q2"),
+		textEvent("helper", "root.helper", strings.Repeat("x", 40)),
+		textEvent("agent", "root", "a2"),
+		textEvent("user", "", "q3"),
+		textEvent("other", "other", "a3"),
+	}
+
+	testCases := []struct {
+		name      string
+		processor llmagent.ContentsProcessor
+		want      []string
+	}{
+		{
+			name:      "last turns",
+			processor: llmagent.SlidingWindow{MaxTurns: 2},
+			want:      []string{"q2", strings.Repeat("x", 40), "a2", "q3", "a3"},
+		},
+		{
+			name:      "token budget",
+			processor: llmagent.SlidingWindow{MaxTokens: 5},
+			want:      []string{"q3", "a3"},
+		},
+		{
+			name:      "token budget keeping two turns",
+			processor: llmagent.SlidingWindow{MaxTokens: 15},
+			want:      []string{"q2", strings.Repeat("x", 40), "a2", "q3", "a3"},
+		},
+		{
+			name:      "include authors",
+			processor: llmagent.AuthorFilter{Include: []string{"agent"}},
+			want:      []string{"q1", "a1", "q2", "a2", "q3"},
+		},
+		{
+			name:      "exclude authors",
+			processor: llmagent.AuthorFilter{Exclude: []string{"helper", "other"}},
+			want:      []string{"q1", "a1", "q2", "a2", "q3"},
+		},
+		{
+			name:      "branches",
+			processor: llmagent.BranchFilter{Branches: []string{"root"}},
+			want:      []string{"q1", "a1", "q2", strings.Repeat("x", 40), "a2", "q3"},
+		},
+		{
+			name: "chain",
+			processor: llmagent.ChainContentsProcessors(
+				llmagent.BranchFilter{Branches: []string{"root"}},
+				llmagent.SlidingWindow{MaxTurns: 1},
+			),
+			want: []string{"q3"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.processor.ProcessEvents(nil, events)
+			if err != nil {
+				t.Fatalf("ProcessEvents() error = %v", err)
+			}
+			if diff := cmp.Diff(tc.want, eventTexts(got)); diff != "" {
+				t.Errorf("ProcessEvents() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLLMAgent_ContentsProcessor(t *testing.T) {
+	mockModel := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromText("a1", genai.RoleModel),
+		genai.NewContentFromText("a2", genai.RoleModel),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:              "agent",
+		Model:             mockModel,
+		ContentsProcessor: llmagent.SlidingWindow{MaxTurns: 1},
+	})
+	if err != nil {
+		t.Fatalf("llmagent.New() error = %v", err)
+	}
+	runner := testutil.NewTestAgentRunner(t, a)
+	for _, q := range []string{"q1", "q2"} {
+		if _, err := testutil.CollectEvents(runner.Run(t, "session", q)); err != nil {
+			t.Fatalf("run failed: %v", err)
+		}
+	}
+
+	var got []string
+	for _, c := range mockModel.Requests[1].Contents {
+		got = append(got, c.Parts[0].Text)
+	}
+	if diff := cmp.Diff([]string{"q2"}, got); diff != "" {
+		t.Errorf("model request contents mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -15,23 +15,52 @@
 package llmagent
 
 import (
+	"context"
 	"fmt"
 	"iter"
+	"reflect"
 	"strings"
 
+	"github.com/google/jsonschema-go/jsonschema"
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
 	agentinternal "google.golang.org/adk/internal/agent"
 	icontext "google.golang.org/adk/internal/context"
 	"google.golang.org/adk/internal/llminternal"
+	"google.golang.org/adk/internal/logging"
 	"google.golang.org/adk/model"
+	"google.golang.org/adk/planner"
 	"google.golang.org/adk/session"
 	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/retrievaltool"
 )
 
 // New is a constructor for LLMAgent.
 func New(cfg Config) (agent.Agent, error) {
+	outputSchema, outputJSONSchema, err := resolveOutputSchema(cfg.OutputSchema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output schema: %w", err)
+	}
+	if err := validateGenerateContentConfig(cfg.GenerateContentConfig); err != nil {
+		return nil, fmt.Errorf("invalid generate content config: %w", err)
+	}
+	partConverter, err := cfg.MediaConversion.partConverter()
+	if err != nil {
+		return nil, fmt.Errorf("invalid media conversion config: %w", err)
+	}
+	toolResponseSaver, err := cfg.ToolResponseArtifacts.responseSaver()
+	if err != nil {
+		return nil, fmt.Errorf("invalid tool response artifacts config: %w", err)
+	}
+	if cfg.Model != nil && len(cfg.ModelMiddlewares) > 0 {
+		cfg.Model = model.Chain(cfg.Model, cfg.ModelMiddlewares...)
+	}
+	tools, err := planBuiltinTools(&cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tools: %w", err)
+	}
+
 	beforeModelCallbacks := make([]llminternal.BeforeModelCallback, 0, len(cfg.BeforeModelCallbacks))
 	for _, c := range cfg.BeforeModelCallbacks {
 		beforeModelCallbacks = append(beforeModelCallbacks, llminternal.BeforeModelCallback(c))
@@ -60,17 +89,17 @@ func New(cfg Config) (agent.Agent, error) {
 		afterToolCallbacks:   afterToolCallbacks,
 		instruction:          cfg.Instruction,
 		inputSchema:          cfg.InputSchema,
-		outputSchema:         cfg.OutputSchema,
 
 		State: llminternal.State{
 			Model:                    cfg.Model,
 			GenerateContentConfig:    cfg.GenerateContentConfig,
-			Tools:                    cfg.Tools,
+			Tools:                    tools,
 			Toolsets:                 cfg.Toolsets,
 			DisallowTransferToParent: cfg.DisallowTransferToParent,
 			DisallowTransferToPeers:  cfg.DisallowTransferToPeers,
 			InputSchema:              cfg.InputSchema,
-			OutputSchema:             cfg.OutputSchema,
+			OutputSchema:             outputSchema,
+			OutputJSONSchema:         outputJSONSchema,
 			// TODO: internal type for includeContents
 			IncludeContents:           string(cfg.IncludeContents),
 			Instruction:               cfg.Instruction,
@@ -78,6 +107,12 @@ func New(cfg Config) (agent.Agent, error) {
 			GlobalInstruction:         cfg.GlobalInstruction,
 			GlobalInstructionProvider: llminternal.InstructionProvider(cfg.GlobalInstructionProvider),
 			OutputKey:                 cfg.OutputKey,
+			ContextProvider:           cfg.Retrieval.contextProvider(),
+			Planner:                   cfg.Planner,
+			EventsProcessor:           eventsProcessor(cfg.ContentsProcessor),
+			PartConverter:             partConverter,
+			ToolResponseSaver:         toolResponseSaver,
+			CacheConfig:               cfg.CacheConfig,
 		},
 	}
 
@@ -131,13 +166,18 @@ type Config struct {
 	AfterAgentCallbacks []agent.AfterAgentCallback
 
 	// GenerateContentConfig is for the additional content generation
-	// configuration.
+	// configuration. A copy of it is the base config of every model request
+	// of the agent, in both regular and live mode.
 	//
-	// NOTE: not all fields are usable, e.g. tools must be configured via
-	// `tools`.
+	// For example: use this config to adjust model temperature, top-p, the
+	// maximum number of output tokens, the thinking budget, configure safety
+	// settings, etc.
 	//
-	// For example: use this config to adjust model temperature, configure
-	// safety settings, etc.
+	// Its SystemInstruction, if set, comes first in the system instruction
+	// of the requests, followed by GlobalInstruction and Instruction.
+	//
+	// NOTE: not all fields are usable. Tools must be configured via Tools
+	// and the response schema via OutputSchema; New fails if they are set.
 	GenerateContentConfig *genai.GenerateContentConfig
 
 	// BeforeModelCallbacks will be called in the order they are provided until
@@ -150,6 +190,11 @@ type Config struct {
 	BeforeModelCallbacks []BeforeModelCallback
 	// Model that is used by the agent.
 	Model model.LLM
+	// ModelMiddlewares wrap Model, e.g. to log, retry, rate-limit or count
+	// the tokens of its calls, see [model.Chain]. The first middleware is the
+	// outermost one. Unlike the model callbacks, the middlewares wrap the
+	// model itself and can change how it is called. Optional.
+	ModelMiddlewares []model.Middleware
 	// AfterModelCallbacks will be called in the order they are provided until
 	// there's a callback that returns a non-nil LLMResponse or error. Then
 	// actual LLM response is replaced with the returned response/error.
@@ -217,11 +262,20 @@ type Config struct {
 	// TODO(ngeorgy): consider to switch to jsonschema for input and output schema.
 	// The input schema when agent is used as a tool.
 	InputSchema *genai.Schema
-	// The output schema when agent replies.
+	// The output schema when agent replies. The model is constrained to reply
+	// with JSON matching the schema.
+	//
+	// OutputSchema can be a *genai.Schema, a *jsonschema.Schema, or a value of
+	// a Go type, e.g. MyOutput{} or (*MyOutput)(nil), in which case the schema
+	// is inferred from the type the same way as for function tools.
+	//
+	// If OutputKey is set, the reply is validated against the schema and the
+	// decoded JSON value is stored in the session state. A reply that doesn't
+	// match the schema fails the agent run.
 	//
 	// NOTE: when this is set, agent can only reply and cannot use any tools,
 	// such as function tools, RAGs, agent transfer, etc.
-	OutputSchema *genai.Schema
+	OutputSchema any
 
 	// Callbacks are executed in the order they are provided.
 	// The execution of the callback chain stops at the first callback that returns a non-nil
@@ -246,8 +300,13 @@ type Config struct {
 	//     in the sequence.
 	AfterToolCallbacks []AfterToolCallback
 	// Toolsets will be used by llmagent to extract tools and pass to the
-	// underlying LLM.
+	// underlying LLM. Their tools are listed before each model call, see
+	// tool.Toolset.
 	Toolsets []tool.Toolset
+	// BuiltinTools defines how the built-in tools of the model are handled
+	// when they are combined with function tools, toolsets or sub-agents.
+	// Defaults to SplitBuiltinTools.
+	BuiltinTools BuiltinToolsMode
 
 	// OutputKey is an optional parameter to specify the key in session state for the agent output.
 	//
@@ -255,6 +314,71 @@ type Config struct {
 	// - Extracts agent reply for later use, such as in tools, callbacks, etc.
 	// - Connects agents to coordinate with each other.
 	OutputKey string
+
+	// ToolResponseArtifacts, if set, saves the large tool responses as
+	// artifacts and gives the model a reference and a summary instead.
+	ToolResponseArtifacts *ToolResponseArtifactsConfig
+
+	// Retrieval, if set, makes the agent look up context relevant to the
+	// user's message before each model call and append it to the
+	// instructions. Unlike a retrieval tool, the model does not decide
+	// whether to search.
+	Retrieval *RetrievalConfig
+
+	// MediaConversion, if set, converts the audio, image and PDF parts of
+	// the user messages to text before each model call, e.g. for models
+	// which only accept text.
+	MediaConversion *MediaConversionConfig
+
+	// Planner, if set, makes the agent plan before acting. See the planner
+	// package for the available planners.
+	Planner planner.Planner
+
+	// ContentsProcessor, if set, selects the session events included in the
+	// model requests, e.g. SlidingWindow to bound the conversation history.
+	// It is not used if IncludeContents is IncludeContentsNone.
+	ContentsProcessor ContentsProcessor
+
+	// CacheConfig, if set, makes the model cache the system instruction and
+	// the tool declarations across the requests of a session, which reduces
+	// the cost of agents with large static prompts. The cache is recreated
+	// when the instructions or the tools change. Models that don't support
+	// context caching ignore it.
+	CacheConfig *model.CacheConfig
+}
+
+// RetrievalConfig configures automatic injection of retrieved context into
+// the model request.
+type RetrievalConfig struct {
+	// Retriever is used to look up context for the user's message.
+	Retriever retrievaltool.Retriever
+	// TopK is the maximum number of chunks added to the request.
+	// If zero, retrievaltool.DefaultTopK is used.
+	TopK int
+}
+
+func eventsProcessor(p ContentsProcessor) llminternal.EventsProcessor {
+	if p == nil {
+		return nil
+	}
+	return p.ProcessEvents
+}
+
+func (c *RetrievalConfig) contextProvider() llminternal.ContextProvider {
+	if c == nil || c.Retriever == nil {
+		return nil
+	}
+	topK := c.TopK
+	if topK <= 0 {
+		topK = retrievaltool.DefaultTopK
+	}
+	return func(ctx agent.ReadonlyContext, query string) (string, error) {
+		chunks, err := retrievaltool.Retrieve(ctx, c.Retriever, query, topK)
+		if err != nil {
+			return "", err
+		}
+		return retrievaltool.FormatChunks(chunks), nil
+	}
 }
 
 // BeforeModelCallback that is called before sending a request to the model.
@@ -311,8 +435,7 @@ type llmAgent struct {
 	beforeToolCallbacks []llminternal.BeforeToolCallback
 	afterToolCallbacks  []llminternal.AfterToolCallback
 
-	inputSchema  *genai.Schema
-	outputSchema *genai.Schema
+	inputSchema *genai.Schema
 }
 
 type agentState = agentinternal.State
@@ -327,6 +450,8 @@ func (a *llmAgent) run(ctx agent.InvocationContext) iter.Seq2[*session.Event, er
 		Agent:       a,
 		UserContent: ctx.UserContent(),
 		RunConfig:   ctx.RunConfig(),
+		// The events of the agent belong to the invocation of the caller.
+		InvocationID: ctx.InvocationID(),
 	})
 
 	f := &llminternal.Flow{
@@ -341,7 +466,9 @@ func (a *llmAgent) run(ctx agent.InvocationContext) iter.Seq2[*session.Event, er
 
 	return func(yield func(*session.Event, error) bool) {
 		for ev, err := range f.Run(ctx) {
-			a.maybeSaveOutputToState(ev)
+			if err == nil {
+				err = a.maybeSaveOutputToState(ctx, ev)
+			}
 			if !yield(ev, err) {
 				return
 			}
@@ -351,13 +478,13 @@ func (a *llmAgent) run(ctx agent.InvocationContext) iter.Seq2[*session.Event, er
 
 // maybeSaveOutputToState saves the model output to state if needed. skip if the event
 // was authored by some other agent (e.g. current agent transferred to another agent)
-func (a *llmAgent) maybeSaveOutputToState(event *session.Event) {
+func (a *llmAgent) maybeSaveOutputToState(ctx context.Context, event *session.Event) error {
 	if event == nil {
-		return
+		return nil
 	}
 	if event.Author != a.Name() {
-		// TODO: log "Skipping output save for agent %s: event authored by %s"
-		return
+		logging.FromContext(ctx).Debug("skipping output save of an event authored by another agent", "agent", a.Name(), "author", event.Author)
+		return nil
 	}
 	if a.OutputKey != "" && !event.Partial && event.Content != nil && len(event.Content.Parts) > 0 {
 		var sb strings.Builder
@@ -366,16 +493,20 @@ func (a *llmAgent) maybeSaveOutputToState(event *session.Event) {
 				sb.WriteString(part.Text)
 			}
 		}
-		result := sb.String()
+		var result any = sb.String()
 
-		// TODO: add output schema validation and unmarshalling
-		if a.OutputSchema != nil {
+		if a.OutputSchema != nil || a.OutputJSONSchema != nil {
 			// If the result from the final chunk is just whitespace or empty,
 			// it means this is an empty final chunk of a stream.
 			// Do not attempt to parse it as JSON.
-			if strings.TrimSpace(result) == "" {
-				return
+			if strings.TrimSpace(sb.String()) == "" {
+				return nil
 			}
+			parsed, err := a.ParseOutput(sb.String())
+			if err != nil {
+				return fmt.Errorf("agent %q output doesn't match the output schema: %w", a.Name(), err)
+			}
+			result = parsed
 		}
 
 		if event.Actions.StateDelta == nil {
@@ -384,6 +515,55 @@ func (a *llmAgent) maybeSaveOutputToState(event *session.Event) {
 
 		event.Actions.StateDelta[a.OutputKey] = result
 	}
+	return nil
+}
+
+// validateGenerateContentConfig rejects the fields of the generation config
+// that are set from other fields of Config.
+func validateGenerateContentConfig(cfg *genai.GenerateContentConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	if len(cfg.Tools) > 0 {
+		return fmt.Errorf("tools must be set via Config.Tools")
+	}
+	if cfg.ResponseSchema != nil || cfg.ResponseJsonSchema != nil {
+		return fmt.Errorf("response schema must be set via Config.OutputSchema")
+	}
+	return nil
+}
+
+// resolveOutputSchema converts the OutputSchema config value into the
+// schema used by the flow.
+func resolveOutputSchema(v any) (*genai.Schema, *jsonschema.Resolved, error) {
+	switch s := v.(type) {
+	case nil:
+		return nil, nil, nil
+	case *genai.Schema:
+		return s, nil, nil
+	case *jsonschema.Schema:
+		if s == nil {
+			return nil, nil, nil
+		}
+		resolved, err := s.Resolve(nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, resolved, nil
+	}
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	schema, err := jsonschema.ForType(t, &jsonschema.ForOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	resolved, err := schema.Resolve(nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, resolved, nil
 }
 
 // InstructionProvider allows to create instructions dynamically. It is called
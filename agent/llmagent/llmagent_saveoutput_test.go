@@ -56,6 +56,7 @@ func TestLlmAgent_MaybeSaveOutputToState(t *testing.T) {
 		agentConfig      Config
 		event            *session.Event
 		wantStateDelta   map[string]any
+		wantErr          bool
 		customEventParts []*genai.Part // For multi-part test
 	}{
 		{
@@ -105,7 +106,48 @@ func TestLlmAgent_MaybeSaveOutputToState(t *testing.T) {
 			event:          createTestEvent("testagent", "Test response", true),
 			wantStateDelta: map[string]any{},
 		},
-		// TODO tests with OutputSchema
+		{
+			name:           "decodes output matching go type schema",
+			agentConfig:    Config{Name: "test_agent", OutputKey: "result", OutputSchema: MockOutputSchema{}},
+			event:          createTestEvent("test_agent", `{"message": "hi", "confidence": 0.5}`, true),
+			wantStateDelta: map[string]any{"result": map[string]any{"message": "hi", "confidence": 0.5}},
+		},
+		{
+			name:           "decodes output matching go pointer type schema",
+			agentConfig:    Config{Name: "test_agent", OutputKey: "result", OutputSchema: (*MockOutputSchema)(nil)},
+			event:          createTestEvent("test_agent", `{"message": "hi", "confidence": 1}`, true),
+			wantStateDelta: map[string]any{"result": map[string]any{"message": "hi", "confidence": 1.0}},
+		},
+		{
+			name: "decodes output matching genai schema",
+			agentConfig: Config{Name: "test_agent", OutputKey: "result", OutputSchema: &genai.Schema{
+				Type:       genai.TypeObject,
+				Properties: map[string]*genai.Schema{"message": {Type: genai.TypeString}},
+				Required:   []string{"message"},
+			}},
+			event:          createTestEvent("test_agent", `{"message": "hi"}`, true),
+			wantStateDelta: map[string]any{"result": map[string]any{"message": "hi"}},
+		},
+		{
+			name:           "fails on output not matching schema",
+			agentConfig:    Config{Name: "test_agent", OutputKey: "result", OutputSchema: MockOutputSchema{}},
+			event:          createTestEvent("test_agent", `{"message": 1}`, true),
+			wantStateDelta: map[string]any{},
+			wantErr:        true,
+		},
+		{
+			name:           "fails on output that is not json",
+			agentConfig:    Config{Name: "test_agent", OutputKey: "result", OutputSchema: MockOutputSchema{}},
+			event:          createTestEvent("test_agent", "not json", true),
+			wantStateDelta: map[string]any{},
+			wantErr:        true,
+		},
+		{
+			name:           "skips empty final chunk with schema",
+			agentConfig:    Config{Name: "test_agent", OutputKey: "result", OutputSchema: MockOutputSchema{}},
+			event:          createTestEvent("test_agent", "  ", true),
+			wantStateDelta: map[string]any{},
+		},
 	}
 
 	// Iterate over the test cases
@@ -126,7 +168,10 @@ func TestLlmAgent_MaybeSaveOutputToState(t *testing.T) {
 			if !ok {
 				t.Fatalf("failed to convert to llmagent")
 			}
-			createdLlmAgent.maybeSaveOutputToState(tc.event)
+			err = createdLlmAgent.maybeSaveOutputToState(t.Context(), tc.event)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("maybeSaveOutputToState() error = %v, wantErr %v", err, tc.wantErr)
+			}
 
 			// --- Assertion ---
 			gotStateDelta := tc.event.Actions.StateDelta
@@ -15,6 +15,7 @@
 package llmagent_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"iter"
@@ -22,19 +23,23 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/artifact"
 	"google.golang.org/adk/internal/httprr"
 	"google.golang.org/adk/internal/testutil"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/model/gemini"
+	"google.golang.org/adk/runner"
 	"google.golang.org/adk/session"
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/adk/tool/geminitool"
 )
 
 const modelName = "gemini-2.0-flash"
@@ -685,6 +690,308 @@ func TestInstructionProvider(t *testing.T) {
 	}
 }
 
+func TestGenerateContentConfig(t *testing.T) {
+	echo, err := functiontool.New(functiontool.Config{Name: "echo", Description: "echoes the input"},
+		func(_ tool.Context, args map[string]any) (map[string]any, error) { return args, nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcc := &genai.GenerateContentConfig{
+		Temperature:       genai.Ptr[float32](0.2),
+		TopP:              genai.Ptr[float32](0.9),
+		MaxOutputTokens:   256,
+		SafetySettings:    []*genai.SafetySetting{{Category: genai.HarmCategoryHarassment, Threshold: genai.HarmBlockThresholdBlockOnlyHigh}},
+		ThinkingConfig:    &genai.ThinkingConfig{ThinkingBudget: genai.Ptr[int32](1024)},
+		SystemInstruction: genai.NewContentFromText("config instruction", genai.RoleUser),
+	}
+	model := &testutil.MockModel{
+		Responses: []*genai.Content{
+			genai.NewContentFromFunctionCall("echo", map[string]any{"text": "hi"}, genai.RoleModel),
+			genai.NewContentFromText("done", genai.RoleModel),
+		},
+	}
+	a, err := llmagent.New(llmagent.Config{
+		Name:                  "test_agent",
+		Model:                 model,
+		Instruction:           "agent instruction",
+		Tools:                 []tool.Tool{echo},
+		GenerateContentConfig: gcc,
+	})
+	if err != nil {
+		t.Fatalf("failed to create LLM Agent: %v", err)
+	}
+
+	if _, err := testutil.CollectTextParts(testutil.NewTestAgentRunner(t, a).Run(t, "session", "user input")); err != nil {
+		t.Fatalf("agent run failed: %v", err)
+	}
+
+	if len(model.Requests) != 2 {
+		t.Fatalf("got %d model requests, want 2", len(model.Requests))
+	}
+	wantInstruction := &genai.Content{
+		Parts: []*genai.Part{genai.NewPartFromText("config instruction"), genai.NewPartFromText("agent instruction")},
+		Role:  genai.RoleUser,
+	}
+	for i, req := range model.Requests {
+		got := req.Config
+		if diff := cmp.Diff(gcc.Temperature, got.Temperature); diff != "" {
+			t.Errorf("request %d temperature mismatch (-want +got):\n%s", i, diff)
+		}
+		if diff := cmp.Diff(gcc.TopP, got.TopP); diff != "" {
+			t.Errorf("request %d top-p mismatch (-want +got):\n%s", i, diff)
+		}
+		if got.MaxOutputTokens != gcc.MaxOutputTokens {
+			t.Errorf("request %d max output tokens = %d, want %d", i, got.MaxOutputTokens, gcc.MaxOutputTokens)
+		}
+		if diff := cmp.Diff(gcc.SafetySettings, got.SafetySettings); diff != "" {
+			t.Errorf("request %d safety settings mismatch (-want +got):\n%s", i, diff)
+		}
+		if diff := cmp.Diff(gcc.ThinkingConfig, got.ThinkingConfig); diff != "" {
+			t.Errorf("request %d thinking config mismatch (-want +got):\n%s", i, diff)
+		}
+		if diff := cmp.Diff(wantInstruction, got.SystemInstruction); diff != "" {
+			t.Errorf("request %d system instruction mismatch (-want +got):\n%s", i, diff)
+		}
+	}
+	// The requests must not modify the agent config.
+	if diff := cmp.Diff(genai.NewContentFromText("config instruction", genai.RoleUser), gcc.SystemInstruction); diff != "" {
+		t.Errorf("config system instruction was modified (-want +got):\n%s", diff)
+	}
+	if len(gcc.Tools) != 0 {
+		t.Errorf("config tools were modified: %v", gcc.Tools)
+	}
+}
+
+func TestGenerateContentConfig_Invalid(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		gcc  *genai.GenerateContentConfig
+	}{
+		{name: "tools", gcc: &genai.GenerateContentConfig{Tools: []*genai.Tool{{GoogleSearch: &genai.GoogleSearch{}}}}},
+		{name: "response schema", gcc: &genai.GenerateContentConfig{ResponseSchema: &genai.Schema{Type: genai.TypeString}}},
+		{name: "response JSON schema", gcc: &genai.GenerateContentConfig{ResponseJsonSchema: map[string]any{"type": "string"}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := llmagent.New(llmagent.Config{Name: "test_agent", GenerateContentConfig: tc.gcc}); err == nil {
+				t.Error("llmagent.New() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestModelMiddlewares(t *testing.T) {
+	var calls []string
+	trace := func(name string) model.Middleware {
+		return func(llm model.LLM) model.LLM {
+			return model.Wrap(llm, func(ctx context.Context, req *model.LLMRequest, stream bool, next model.GenerateFunc) iter.Seq2[*model.LLMResponse, error] {
+				calls = append(calls, name)
+				return next(ctx, req, stream)
+			})
+		}
+	}
+	var counter model.TokenCounter
+	mockModel := &testutil.MockModel{Responses: []*genai.Content{genai.NewContentFromText("hello", genai.RoleModel)}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:             "test_agent",
+		Model:            mockModel,
+		ModelMiddlewares: []model.Middleware{trace("outer"), model.CountTokens(&counter), trace("inner")},
+	})
+	if err != nil {
+		t.Fatalf("failed to create LLM Agent: %v", err)
+	}
+
+	got, err := testutil.CollectTextParts(testutil.NewTestAgentRunner(t, a).Run(t, "session", "user input"))
+	if err != nil {
+		t.Fatalf("agent run failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"hello"}, got); diff != "" {
+		t.Errorf("agent response mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"outer", "inner"}, calls); diff != "" {
+		t.Errorf("middleware calls mismatch (-want +got):\n%s", diff)
+	}
+	if got := counter.Usage().Calls; got != 1 {
+		t.Errorf("counted %d model calls, want 1", got)
+	}
+	if len(mockModel.Requests) != 1 {
+		t.Errorf("got %d model requests, want 1", len(mockModel.Requests))
+	}
+}
+
+func TestMediaConversion(t *testing.T) {
+	ctx := t.Context()
+	captioner := &testutil.MockModel{Responses: []*genai.Content{genai.NewContentFromText("A cat on a sofa.", genai.RoleModel)}}
+	mockModel := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromText("Nice cat!", genai.RoleModel),
+		genai.NewContentFromText("It is grey.", genai.RoleModel),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:  "test_agent",
+		Model: mockModel,
+		MediaConversion: &llmagent.MediaConversionConfig{
+			Model:         captioner,
+			SaveOriginals: true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create LLM Agent: %v", err)
+	}
+	sessionService := session.InMemoryService()
+	artifactService := artifact.InMemoryService()
+	r, err := runner.New(runner.Config{AppName: "app", Agent: a, SessionService: sessionService, ArtifactService: artifactService})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	image := genai.NewPartFromBytes([]byte("png"), "image/png")
+	for _, msg := range []*genai.Content{
+		genai.NewContentFromParts([]*genai.Part{genai.NewPartFromText("Look at my cat"), image}, genai.RoleUser),
+		genai.NewContentFromText("What color is it?", genai.RoleUser),
+	} {
+		if _, err := r.RunAndCollect(ctx, "user", "session", msg, agent.RunConfig{}); err != nil {
+			t.Fatalf("RunAndCollect() failed: %v", err)
+		}
+	}
+
+	// The image is converted once and sent as text in both requests.
+	if len(captioner.Requests) != 1 {
+		t.Fatalf("got %d conversion requests, want 1", len(captioner.Requests))
+	}
+	if diff := cmp.Diff(image, captioner.Requests[0].Contents[0].Parts[1]); diff != "" {
+		t.Errorf("conversion request part mismatch (-want +got):\n%s", diff)
+	}
+	files, err := artifactService.List(ctx, &artifact.ListRequest{AppName: "app", UserID: "user", SessionID: "session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files.FileNames) != 1 || !strings.HasPrefix(files.FileNames[0], "upload_") || !strings.HasSuffix(files.FileNames[0], ".png") {
+		t.Fatalf("saved artifacts = %v, want one upload_*.png artifact", files.FileNames)
+	}
+	want := fmt.Sprintf("[Attached image/png file, saved as artifact %q, converted to text:]\nA cat on a sofa.", files.FileNames[0])
+	for i, req := range mockModel.Requests {
+		if got := req.Contents[0].Parts[1].Text; got != want {
+			t.Errorf("request %d image part = %q, want %q", i, got, want)
+		}
+	}
+
+	// The session keeps the original image.
+	resp, err := sessionService.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Session.Events().At(0).Content.Parts[1]; got.InlineData == nil {
+		t.Errorf("session event part = %v, want the original image", got)
+	}
+}
+
+func TestMediaConversion_Invalid(t *testing.T) {
+	_, err := llmagent.New(llmagent.Config{
+		Name:            "test_agent",
+		MediaConversion: &llmagent.MediaConversionConfig{},
+	})
+	if err == nil {
+		t.Errorf("llmagent.New() succeeded, want error")
+	}
+}
+
+func TestToolResponseArtifacts(t *testing.T) {
+	ctx := t.Context()
+	type Args struct {
+		Size int `json:"size"`
+	}
+	fetch, err := functiontool.New(functiontool.Config{Name: "fetch", Description: "fetches a page"}, func(_ tool.Context, args Args) (map[string]any, error) {
+		return map[string]any{"page": strings.Repeat("x", args.Size)}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mockModel := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromParts([]*genai.Part{
+			{FunctionCall: &genai.FunctionCall{ID: "small", Name: "fetch", Args: map[string]any{"size": 10}}},
+			{FunctionCall: &genai.FunctionCall{ID: "large", Name: "fetch", Args: map[string]any{"size": 100}}},
+		}, genai.RoleModel),
+		genai.NewContentFromText("done", genai.RoleModel),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:  "test_agent",
+		Model: mockModel,
+		Tools: []tool.Tool{fetch},
+		ToolResponseArtifacts: &llmagent.ToolResponseArtifactsConfig{
+			MaxSize: 50,
+			Summarize: func(ctx tool.Context, t tool.Tool, response map[string]any) (string, error) {
+				return fmt.Sprintf("a page of %d characters", len(response["page"].(string))), nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create LLM Agent: %v", err)
+	}
+	sessionService := session.InMemoryService()
+	artifactService := artifact.InMemoryService()
+	r, err := runner.New(runner.Config{AppName: "app", Agent: a, SessionService: sessionService, ArtifactService: artifactService})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := r.RunAndCollect(ctx, "user", "session", genai.NewContentFromText("fetch", genai.RoleUser), agent.RunConfig{})
+	if err != nil {
+		t.Fatalf("RunAndCollect() failed: %v", err)
+	}
+
+	const name = "tool_response_fetch_large.json"
+	var responseEvent *session.Event
+	for _, ev := range result.Events {
+		if ev.Content != nil && len(ev.Content.Parts) > 0 && ev.Content.Parts[0].FunctionResponse != nil {
+			responseEvent = ev
+		}
+	}
+	if responseEvent == nil {
+		t.Fatalf("no function response event in %v", result.Events)
+	}
+	got := map[string]map[string]any{}
+	for _, p := range responseEvent.Content.Parts {
+		got[p.FunctionResponse.ID] = p.FunctionResponse.Response
+	}
+	want := map[string]map[string]any{
+		"small": {"page": strings.Repeat("x", 10)},
+		"large": {
+			"artifact": name,
+			"version":  int64(1),
+			"size":     111,
+			"summary":  "a page of 100 characters",
+			"note":     "The response was too large and was saved as an artifact. Load the artifact to read the full response.",
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("function responses mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]int64{name: 1}, responseEvent.Actions.ArtifactDelta); diff != "" {
+		t.Errorf("artifact delta mismatch (-want +got):\n%s", diff)
+	}
+
+	saved, err := artifactService.Load(ctx, &artifact.LoadRequest{AppName: "app", UserID: "user", SessionID: "session", FileName: name})
+	if err != nil {
+		t.Fatalf("failed to load the saved response: %v", err)
+	}
+	if got, want := string(saved.Part.InlineData.Data), `{"page":"`+strings.Repeat("x", 100)+`"}`; got != want {
+		t.Errorf("saved response = %q, want %q", got, want)
+	}
+}
+
+func TestToolResponseArtifacts_Invalid(t *testing.T) {
+	_, err := llmagent.New(llmagent.Config{
+		Name:                  "test_agent",
+		ToolResponseArtifacts: &llmagent.ToolResponseArtifactsConfig{MaxSize: -1},
+	})
+	if err == nil {
+		t.Errorf("llmagent.New() succeeded, want error")
+	}
+}
+
 func TestFunctionTool(t *testing.T) {
 	model := newGeminiModel(t, modelName, nil)
 
@@ -968,3 +1275,534 @@ type roundTripperFunc func(*http.Request) (*http.Response, error)
 func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
 	return fn(req)
 }
+
+func TestOutputSchemaFromGoType(t *testing.T) {
+	type Recipe struct {
+		Name        string   `json:"name"`
+		Ingredients []string `json:"ingredients"`
+	}
+	testLLM := &testutil.MockModel{
+		Responses: []*genai.Content{
+			genai.NewContentFromText(`{"name": "pancakes", "ingredients": ["flour", "milk"]}`, genai.RoleModel),
+		},
+	}
+	a, err := llmagent.New(llmagent.Config{
+		Name:         "chef",
+		Model:        testLLM,
+		OutputSchema: Recipe{},
+		OutputKey:    "recipe",
+	})
+	if err != nil {
+		t.Fatalf("failed to create agent: %v", err)
+	}
+
+	events, err := testutil.CollectEvents(testutil.NewTestAgentRunner(t, a).Run(t, "session", "pancakes please"))
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	if len(testLLM.Requests) != 1 {
+		t.Fatalf("got %d model requests, want 1", len(testLLM.Requests))
+	}
+	cfg := testLLM.Requests[0].Config
+	if cfg.ResponseMIMEType != "application/json" {
+		t.Errorf("ResponseMIMEType = %q, want %q", cfg.ResponseMIMEType, "application/json")
+	}
+	if cfg.ResponseJsonSchema == nil {
+		t.Errorf("ResponseJsonSchema is not set")
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	want := map[string]any{"recipe": map[string]any{"name": "pancakes", "ingredients": []any{"flour", "milk"}}}
+	if diff := cmp.Diff(want, events[0].Actions.StateDelta); diff != "" {
+		t.Errorf("state delta mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunConfigCallLimits(t *testing.T) {
+	type Args struct{}
+	ping, err := functiontool.New(functiontool.Config{Name: "ping"}, func(tool.Context, Args) (map[string]any, error) {
+		return map[string]any{"result": "pong"}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name string
+		cfg  agent.RunConfig
+		want []string
+	}{
+		{
+			name: "max llm calls",
+			cfg:  agent.RunConfig{MaxLLMCalls: 2},
+			want: []string{"call", "response", "call", "response", agent.MaxLLMCallsExceededErrorCode},
+		},
+		{
+			name: "max tool calls",
+			cfg:  agent.RunConfig{MaxToolCalls: 1},
+			want: []string{"call", "response", "call", agent.MaxToolCallsExceededErrorCode},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// The model never stops calling the tool.
+			var responses []*genai.Content
+			for range 10 {
+				responses = append(responses, genai.NewContentFromFunctionCall("ping", map[string]any{}, genai.RoleModel))
+			}
+			a, err := llmagent.New(llmagent.Config{
+				Name:  "agent",
+				Model: &testutil.MockModel{Responses: responses},
+				Tools: []tool.Tool{ping},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for ev, err := range testutil.NewTestAgentRunner(t, a).RunContentWithConfig(t, "session", genai.NewContentFromText("go", genai.RoleUser), tc.cfg) {
+				if err != nil {
+					t.Fatalf("run failed: %v", err)
+				}
+				switch {
+				case ev.ErrorCode != "":
+					got = append(got, ev.ErrorCode)
+				case ev.Content.Parts[0].FunctionCall != nil:
+					got = append(got, "call")
+				case ev.Content.Parts[0].FunctionResponse != nil:
+					got = append(got, "response")
+				}
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("events mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLLMAgentLive(t *testing.T) {
+	ctx := t.Context()
+	type Args struct {
+		Text string `json:"text"`
+	}
+	echo, err := functiontool.New(functiontool.Config{Name: "echo", Description: "echoes the text"},
+		func(_ tool.Context, args Args) (map[string]any, error) {
+			return map[string]any{"text": args.Text}, nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	liveModel := &testutil.MockLiveModel{
+		Respond: func(req *model.LiveRequest) []*model.LLMResponse {
+			switch {
+			case req.ActivityStart:
+				return []*model.LLMResponse{{Interrupted: true}}
+			case req.Content != nil && req.Content.Parts[0].FunctionResponse != nil:
+				return []*model.LLMResponse{
+					{Content: genai.NewContentFromText("echoed", genai.RoleModel), Partial: true},
+					{Content: genai.NewContentFromText("echoed", genai.RoleModel)},
+					{TurnComplete: true},
+				}
+			case req.Content != nil:
+				return []*model.LLMResponse{{
+					Content: genai.NewContentFromFunctionCall("echo", map[string]any{"text": req.Content.Parts[0].Text}, genai.RoleModel),
+				}}
+			}
+			return nil
+		},
+	}
+	a, err := llmagent.New(llmagent.Config{
+		Name:        "live_agent",
+		Model:       liveModel,
+		Instruction: "echo the user",
+		Tools:       []tool.Tool{echo},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	r, err := runner.New(runner.Config{AppName: "app", Agent: a, SessionService: sessionService})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queue := agent.NewLiveRequestQueue()
+	if err := queue.SendContent(genai.NewContentFromText("hello", genai.RoleUser)); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for ev, err := range r.RunLive(ctx, "user", "session", queue, agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("RunLive() failed: %v", err)
+		}
+		switch {
+		case ev.TurnComplete:
+			got = append(got, "turn_complete")
+			// Barge in, the model is interrupted.
+			if err := queue.SendActivityStart(); err != nil {
+				t.Fatal(err)
+			}
+		case ev.Interrupted:
+			got = append(got, "interrupted")
+			queue.Close()
+		case ev.Content != nil:
+			p := ev.Content.Parts[0]
+			switch {
+			case p.FunctionCall != nil:
+				got = append(got, "call:"+p.FunctionCall.Args["text"].(string))
+			case p.FunctionResponse != nil:
+				got = append(got, "response:"+p.FunctionResponse.Response["text"].(string))
+			case ev.Partial:
+				got = append(got, "partial:"+p.Text)
+			default:
+				got = append(got, "text:"+p.Text)
+			}
+		}
+	}
+
+	want := []string{"call:hello", "response:hello", "partial:echoed", "text:echoed", "turn_complete", "interrupted"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("events mismatch (-want +got):\n%s", diff)
+	}
+	if len(liveModel.ConnectRequests) != 1 {
+		t.Fatalf("got %d connections, want 1", len(liveModel.ConnectRequests))
+	}
+	if _, ok := liveModel.ConnectRequests[0].Tools["echo"]; !ok {
+		t.Errorf("connect request tools = %v, want echo", liveModel.ConnectRequests[0].Tools)
+	}
+
+	resp, err := sessionService.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for ev := range resp.Session.Events().All() {
+		if ev.Partial {
+			t.Errorf("partial event %v stored in session", ev)
+		}
+	}
+}
+
+func TestLLMAgentLive_ModelNotSupported(t *testing.T) {
+	a, err := llmagent.New(llmagent.Config{Name: "agent", Model: &testutil.MockModel{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(t.Context(), &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	r, err := runner.New(runner.Config{AppName: "app", Agent: a, SessionService: sessionService})
+	if err != nil {
+		t.Fatal(err)
+	}
+	queue := agent.NewLiveRequestQueue()
+	defer queue.Close()
+	var gotErr error
+	for _, err := range r.RunLive(t.Context(), "user", "session", queue, agent.RunConfig{}) {
+		if err != nil {
+			gotErr = err
+			break
+		}
+	}
+	if gotErr == nil {
+		t.Errorf("RunLive() succeeded, want error")
+	}
+}
+
+func TestLLMAgentLive_Transcription(t *testing.T) {
+	ctx := t.Context()
+	liveModel := &testutil.MockLiveModel{
+		Respond: func(req *model.LiveRequest) []*model.LLMResponse {
+			if req.Blob == nil {
+				return nil
+			}
+			return []*model.LLMResponse{
+				{InputTranscription: &genai.Transcription{Text: "hello", Finished: true}},
+				{Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{{InlineData: &genai.Blob{MIMEType: "audio/pcm", Data: []byte("hi")}}}}, Partial: true},
+				{OutputTranscription: &genai.Transcription{Text: "hi"}, Partial: true},
+				{OutputTranscription: &genai.Transcription{Text: "hi", Finished: true}},
+			}
+		},
+	}
+	a, err := llmagent.New(llmagent.Config{Name: "live_agent", Model: liveModel})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	r, err := runner.New(runner.Config{AppName: "app", Agent: a, SessionService: sessionService})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queue := agent.NewLiveRequestQueue()
+	if err := queue.SendRealtime(&genai.Blob{MIMEType: "audio/pcm", Data: []byte("hello")}); err != nil {
+		t.Fatal(err)
+	}
+	cfg := agent.RunConfig{
+		ResponseModalities:       []genai.Modality{genai.ModalityAudio},
+		SpeechConfig:             &genai.SpeechConfig{LanguageCode: "en-US"},
+		InputAudioTranscription:  &genai.AudioTranscriptionConfig{},
+		OutputAudioTranscription: &genai.AudioTranscriptionConfig{},
+	}
+	type transcript struct {
+		Author, Input, Output string
+		Partial               bool
+	}
+	var got []transcript
+	for ev, err := range r.RunLive(ctx, "user", "session", queue, cfg) {
+		if err != nil {
+			t.Fatalf("RunLive() failed: %v", err)
+		}
+		switch {
+		case ev.InputTranscription != nil:
+			got = append(got, transcript{Author: ev.Author, Input: ev.InputTranscription.Text, Partial: ev.Partial})
+		case ev.OutputTranscription != nil:
+			got = append(got, transcript{Author: ev.Author, Output: ev.OutputTranscription.Text, Partial: ev.Partial})
+			if !ev.Partial {
+				queue.Close()
+			}
+		}
+	}
+
+	want := []transcript{
+		{Author: "user", Input: "hello"},
+		{Author: "live_agent", Output: "hi", Partial: true},
+		{Author: "live_agent", Output: "hi"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("transcripts mismatch (-want +got):\n%s", diff)
+	}
+	wantConfig := &genai.LiveConnectConfig{
+		ResponseModalities:       cfg.ResponseModalities,
+		SpeechConfig:             cfg.SpeechConfig,
+		InputAudioTranscription:  cfg.InputAudioTranscription,
+		OutputAudioTranscription: cfg.OutputAudioTranscription,
+	}
+	if diff := cmp.Diff(wantConfig, liveModel.ConnectRequests[0].LiveConnectConfig); diff != "" {
+		t.Errorf("live connect config mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// cachingModel reports that a new cache is used whenever the request carries
+// no cache metadata.
+type cachingModel struct {
+	requests []*model.LLMRequest
+}
+
+func (m *cachingModel) Name() string { return "caching-model" }
+
+func (m *cachingModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		m.requests = append(m.requests, req)
+		meta := req.CacheMetadata
+		if meta == nil {
+			meta = &model.CacheMetadata{
+				Name:        fmt.Sprintf("cachedContents/c%d", len(m.requests)),
+				Fingerprint: "fp",
+				ExpireTime:  time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+			}
+		}
+		yield(&model.LLMResponse{
+			Content:       genai.NewContentFromText("ok", genai.RoleModel),
+			CacheMetadata: meta,
+		}, nil)
+	}
+}
+
+func TestCacheConfig(t *testing.T) {
+	m := &cachingModel{}
+	cacheConfig := &model.CacheConfig{TTL: time.Hour}
+	a, err := llmagent.New(llmagent.Config{
+		Name:        "agent",
+		Model:       m,
+		Instruction: "Be helpful.",
+		CacheConfig: cacheConfig,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := testutil.NewTestAgentRunner(t, a)
+	for _, msg := range []string{"first", "second"} {
+		if _, err := testutil.CollectEvents(r.Run(t, "session", msg)); err != nil {
+			t.Fatalf("run failed: %v", err)
+		}
+	}
+
+	if len(m.requests) != 2 {
+		t.Fatalf("got %d model requests, want 2", len(m.requests))
+	}
+	if m.requests[0].CacheConfig != cacheConfig {
+		t.Errorf("CacheConfig = %v, want %v", m.requests[0].CacheConfig, cacheConfig)
+	}
+	if m.requests[0].CacheMetadata != nil {
+		t.Errorf("first request CacheMetadata = %+v, want nil", m.requests[0].CacheMetadata)
+	}
+	want := &model.CacheMetadata{
+		Name:        "cachedContents/c1",
+		Fingerprint: "fp",
+		ExpireTime:  time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	if diff := cmp.Diff(want, m.requests[1].CacheMetadata); diff != "" {
+		t.Errorf("second request CacheMetadata mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// requestTools returns the names of the function declarations and built-in
+// tools of req.
+func requestTools(req *model.LLMRequest) []string {
+	var names []string
+	if req.Config == nil {
+		return nil
+	}
+	for _, t := range req.Config.Tools {
+		for _, fd := range t.FunctionDeclarations {
+			names = append(names, fd.Name)
+		}
+		if t.GoogleSearch != nil {
+			names = append(names, "builtin:google_search")
+		}
+	}
+	return names
+}
+
+func TestBuiltinTools(t *testing.T) {
+	echo, err := functiontool.New(functiontool.Config{Name: "echo", Description: "echoes the input"}, func(_ tool.Context, args map[string]any) (map[string]any, error) {
+		return args, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name             string
+		mode             llmagent.BuiltinToolsMode
+		tools            []tool.Tool
+		responses        []*genai.Content
+		wantRequestTools [][]string
+		wantResponse     map[string]any
+	}{
+		{
+			name:  "split",
+			mode:  llmagent.SplitBuiltinTools,
+			tools: []tool.Tool{geminitool.GoogleSearch{}, echo},
+			responses: []*genai.Content{
+				genai.NewContentFromFunctionCall("google_search", map[string]any{"request": "what is adk"}, genai.RoleModel),
+				genai.NewContentFromText("ADK is an agent development kit.", genai.RoleModel),
+				genai.NewContentFromText("done", genai.RoleModel),
+			},
+			wantRequestTools: [][]string{
+				{"google_search", "echo"},
+				{"builtin:google_search"},
+				{"google_search", "echo"},
+			},
+			wantResponse: map[string]any{"result": "ADK is an agent development kit."},
+		},
+		{
+			name:  "keep",
+			mode:  llmagent.KeepBuiltinTools,
+			tools: []tool.Tool{geminitool.GoogleSearch{}, echo},
+			responses: []*genai.Content{
+				genai.NewContentFromText("done", genai.RoleModel),
+			},
+			wantRequestTools: [][]string{{"builtin:google_search", "echo"}},
+		},
+		{
+			name:  "builtin only",
+			mode:  llmagent.RejectBuiltinTools,
+			tools: []tool.Tool{geminitool.GoogleSearch{}},
+			responses: []*genai.Content{
+				genai.NewContentFromText("done", genai.RoleModel),
+			},
+			wantRequestTools: [][]string{{"builtin:google_search"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockModel := &testutil.MockModel{Responses: tc.responses}
+			a, err := llmagent.New(llmagent.Config{
+				Name:         "test_agent",
+				Model:        mockModel,
+				Tools:        tc.tools,
+				BuiltinTools: tc.mode,
+			})
+			if err != nil {
+				t.Fatalf("failed to create LLM Agent: %v", err)
+			}
+			events, err := testutil.CollectEvents(testutil.NewTestAgentRunner(t, a).Run(t, "session", "question"))
+			if err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+
+			var gotRequestTools [][]string
+			for _, req := range mockModel.Requests {
+				gotRequestTools = append(gotRequestTools, requestTools(req))
+			}
+			if diff := cmp.Diff(tc.wantRequestTools, gotRequestTools); diff != "" {
+				t.Errorf("request tools mismatch (-want +got):\n%s", diff)
+			}
+			var gotResponse map[string]any
+			for _, ev := range events {
+				if ev.Content == nil {
+					continue
+				}
+				for _, p := range ev.Content.Parts {
+					if p.FunctionResponse != nil {
+						gotResponse = p.FunctionResponse.Response
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.wantResponse, gotResponse); diff != "" {
+				t.Errorf("function response mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBuiltinTools_Reject(t *testing.T) {
+	echo, err := functiontool.New(functiontool.Config{Name: "echo"}, func(_ tool.Context, args map[string]any) (map[string]any, error) {
+		return args, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sub, err := llmagent.New(llmagent.Config{Name: "sub"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name string
+		cfg  llmagent.Config
+	}{
+		{
+			name: "function tool",
+			cfg:  llmagent.Config{Tools: []tool.Tool{geminitool.GoogleSearch{}, echo}},
+		},
+		{
+			name: "toolset",
+			cfg: llmagent.Config{
+				Tools:    []tool.Tool{geminitool.New("search", &genai.Tool{GoogleSearch: &genai.GoogleSearch{}})},
+				Toolsets: []tool.Toolset{tool.NewToolset("set", func(agent.ReadonlyContext) ([]tool.Tool, error) { return nil, nil })},
+			},
+		},
+		{
+			name: "sub-agent",
+			cfg:  llmagent.Config{Tools: []tool.Tool{geminitool.GoogleSearch{}}, SubAgents: []agent.Agent{sub}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.cfg.Name = "test_agent"
+			tc.cfg.Model = &testutil.MockModel{}
+			tc.cfg.BuiltinTools = llmagent.RejectBuiltinTools
+			if _, err := llmagent.New(tc.cfg); err == nil {
+				t.Errorf("New() succeeded, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmagent
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"path"
+	"strings"
+	"sync"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/llminternal"
+	"google.golang.org/adk/model"
+)
+
+// MediaConverter converts a media part of a user message, with inline data
+// or a file URI, to text.
+type MediaConverter func(ctx context.Context, part *genai.Part) (string, error)
+
+// MediaConversionConfig configures the conversion of the audio, image and
+// PDF parts of the user messages to text before they are sent to the model,
+// so that text-only models can serve multimodal users.
+type MediaConversionConfig struct {
+	// Model is the multimodal model used by the default converters, which
+	// transcribe audio, caption images and extract the text of PDFs.
+	Model model.LLM
+	// Converters override or extend the default converters. They are keyed
+	// by MIME type patterns, as supported by [path.Match], e.g. "image/*"
+	// or "application/pdf". The parts with no matching converter are sent
+	// to the model unchanged.
+	Converters map[string]MediaConverter
+	// SaveOriginals saves the converted parts with inline data as
+	// artifacts, so that tools can still access the original files.
+	SaveOriginals bool
+}
+
+const (
+	transcribePrompt = "Transcribe this audio verbatim. Only output the transcript."
+	captionPrompt    = "Describe this image in detail, including any text it contains. Only output the description."
+	pdfPrompt        = "Extract the full text of this document, keeping its structure. Only output the text."
+
+	// maxConvertedParts bounds the number of conversions kept in memory.
+	maxConvertedParts = 1000
+)
+
+func (c *MediaConversionConfig) partConverter() (llminternal.PartConverter, error) {
+	if c == nil {
+		return nil, nil
+	}
+	converters := map[string]MediaConverter{}
+	if c.Model != nil {
+		converters["audio/*"] = modelConverter(c.Model, transcribePrompt)
+		converters["image/*"] = modelConverter(c.Model, captionPrompt)
+		converters["application/pdf"] = modelConverter(c.Model, pdfPrompt)
+	}
+	for pattern, conv := range c.Converters {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid MIME type pattern %q: %w", pattern, err)
+		}
+		if conv == nil {
+			return nil, fmt.Errorf("nil converter for %q", pattern)
+		}
+		converters[pattern] = conv
+	}
+	if len(converters) == 0 {
+		return nil, errors.New("media conversion requires a model or converters")
+	}
+	m := &mediaConverter{
+		converters:    converters,
+		saveOriginals: c.SaveOriginals,
+		texts:         map[string]string{},
+	}
+	return m.convert, nil
+}
+
+type mediaConverter struct {
+	converters    map[string]MediaConverter
+	saveOriginals bool
+
+	mu sync.Mutex
+	// texts caches the conversions by part hash, as the parts of the
+	// conversation history are converted again by every model call.
+	texts map[string]string
+}
+
+func (m *mediaConverter) convert(ctx agent.InvocationContext, part *genai.Part) (*genai.Part, error) {
+	var mimeType string
+	var data []byte
+	if part.InlineData != nil {
+		mimeType, data = part.InlineData.MIMEType, part.InlineData.Data
+	} else {
+		mimeType, data = part.FileData.MIMEType, []byte(part.FileData.FileURI)
+	}
+	conv := m.converter(mimeType)
+	if conv == nil {
+		return nil, nil
+	}
+	hash := sha256.New()
+	hash.Write([]byte(mimeType + "\x00"))
+	hash.Write(data)
+	key := hex.EncodeToString(hash.Sum(nil))
+
+	m.mu.Lock()
+	text, ok := m.texts[key]
+	m.mu.Unlock()
+	if !ok {
+		var err error
+		text, err = conv(ctx, part)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert %s part: %w", mimeType, err)
+		}
+		m.mu.Lock()
+		if len(m.texts) >= maxConvertedParts {
+			clear(m.texts)
+		}
+		m.texts[key] = text
+		m.mu.Unlock()
+	}
+
+	header := fmt.Sprintf("[Attached %s file", mimeType)
+	if m.saveOriginals && part.InlineData != nil && ctx.Artifacts() != nil {
+		name, err := saveOriginal(ctx, key, part)
+		if err != nil {
+			return nil, err
+		}
+		header += fmt.Sprintf(", saved as artifact %q", name)
+	}
+	return genai.NewPartFromText(header + ", converted to text:]\n" + text), nil
+}
+
+func (m *mediaConverter) converter(mimeType string) MediaConverter {
+	// Exact patterns take precedence over wildcards.
+	if conv, ok := m.converters[mimeType]; ok {
+		return conv
+	}
+	for pattern, conv := range m.converters {
+		if ok, _ := path.Match(pattern, mimeType); ok {
+			return conv
+		}
+	}
+	return nil
+}
+
+// saveOriginal saves the part as an artifact named after its hash, unless
+// it is already saved, and returns the artifact name.
+func saveOriginal(ctx agent.InvocationContext, key string, part *genai.Part) (string, error) {
+	name := "upload_" + key[:16]
+	if exts, _ := mime.ExtensionsByType(part.InlineData.MIMEType); len(exts) > 0 {
+		name += exts[0]
+	} else if _, subtype, ok := strings.Cut(part.InlineData.MIMEType, "/"); ok && subtype != "" {
+		name += "." + subtype
+	}
+	if _, err := ctx.Artifacts().Load(ctx, name); err == nil {
+		return name, nil
+	}
+	if _, err := ctx.Artifacts().Save(ctx, name, part); err != nil {
+		return "", fmt.Errorf("failed to save artifact %q: %w", name, err)
+	}
+	return name, nil
+}
+
+// modelConverter returns a MediaConverter asking llm to convert the part
+// following prompt.
+func modelConverter(llm model.LLM, prompt string) MediaConverter {
+	return func(ctx context.Context, part *genai.Part) (string, error) {
+		req := &model.LLMRequest{
+			Model:    llm.Name(),
+			Contents: []*genai.Content{genai.NewContentFromParts([]*genai.Part{genai.NewPartFromText(prompt), part}, genai.RoleUser)},
+			Config:   &genai.GenerateContentConfig{},
+		}
+		var sb strings.Builder
+		for resp, err := range llm.GenerateContent(ctx, req, false) {
+			if err != nil {
+				return "", err
+			}
+			if resp.ErrorCode != "" {
+				return "", fmt.Errorf("model error %s: %s", resp.ErrorCode, resp.ErrorMessage)
+			}
+			if resp.Content == nil {
+				continue
+			}
+			for _, p := range resp.Content.Parts {
+				if p.Text != "" && !p.Thought {
+					sb.WriteString(p.Text)
+				}
+			}
+		}
+		return strings.TrimSpace(sb.String()), nil
+	}
+}
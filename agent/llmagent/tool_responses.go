@@ -0,0 +1,118 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmagent
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/llminternal"
+	"google.golang.org/adk/tool"
+)
+
+const (
+	// DefaultToolResponseMaxSize is the size, in bytes, above which the
+	// tool responses are saved as artifacts when no limit is configured.
+	DefaultToolResponseMaxSize = 32 << 10
+	// DefaultToolResponseSummaryLength is the number of characters of the
+	// default summary of the saved tool responses.
+	DefaultToolResponseSummaryLength = 1024
+)
+
+// ToolResponseSummarizer returns the summary of a tool response given to
+// the model in place of the response.
+type ToolResponseSummarizer func(ctx tool.Context, t tool.Tool, response map[string]any) (string, error)
+
+// ToolResponseArtifactsConfig configures the saving of the large tool
+// responses as artifacts, so that a single tool call can't fill the context
+// window of the model. The model gets the name of the artifact and a
+// summary of the response instead, and can read the full response with the
+// load_artifacts tool, see loadartifactstool.
+//
+// The responses are only saved if the runner has an artifact service.
+type ToolResponseArtifactsConfig struct {
+	// MaxSize is the size, in bytes, of the JSON encoding of a tool response
+	// above which the response is saved. If zero,
+	// DefaultToolResponseMaxSize is used.
+	MaxSize int
+	// Summarize, if set, returns the summaries of the saved responses. By
+	// default, the summary is the start of the JSON encoding of the
+	// response, truncated to DefaultToolResponseSummaryLength characters.
+	Summarize ToolResponseSummarizer
+}
+
+func (c *ToolResponseArtifactsConfig) responseSaver() (llminternal.ToolResponseSaver, error) {
+	if c == nil {
+		return nil, nil
+	}
+	if c.MaxSize < 0 {
+		return nil, fmt.Errorf("invalid MaxSize %d", c.MaxSize)
+	}
+	maxSize := c.MaxSize
+	if maxSize == 0 {
+		maxSize = DefaultToolResponseMaxSize
+	}
+	summarize := c.Summarize
+	if summarize == nil {
+		summarize = truncatedSummary
+	}
+	return func(ctx tool.Context, t tool.Tool, response map[string]any) (map[string]any, error) {
+		data, err := json.Marshal(response)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode the response: %w", err)
+		}
+		if len(data) <= maxSize {
+			return nil, nil
+		}
+		summary, err := summarize(ctx, t, response)
+		if err != nil {
+			return nil, fmt.Errorf("failed to summarize the response: %w", err)
+		}
+		name := toolResponseArtifactName(t.Name(), ctx.FunctionCallID())
+		resp, err := ctx.Artifacts().Save(ctx, name, genai.NewPartFromBytes(data, "application/json"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to save the response as artifact: %w", err)
+		}
+		return map[string]any{
+			"artifact": name,
+			"version":  resp.Version,
+			"size":     len(data),
+			"summary":  summary,
+			"note":     "The response was too large and was saved as an artifact. Load the artifact to read the full response.",
+		}, nil
+	}, nil
+}
+
+// toolResponseArtifactName returns the name of the artifact storing the
+// response of a function call.
+func toolResponseArtifactName(toolName, functionCallID string) string {
+	return fmt.Sprintf("tool_response_%s_%s.json", toolName, functionCallID)
+}
+
+func truncatedSummary(ctx tool.Context, t tool.Tool, response map[string]any) (string, error) {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return "", err
+	}
+	s := string(data)
+	if utf8.RuneCountInString(s) <= DefaultToolResponseSummaryLength {
+		return s, nil
+	}
+	runes := []rune(s)
+	return string(runes[:DefaultToolResponseSummaryLength]) + "...", nil
+}
@@ -15,7 +15,21 @@
 package agent
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrAgentExists is returned by MutableLoader.Register if an agent is
+	// already served under the name.
+	ErrAgentExists = errors.New("agent already exists")
+	// ErrAgentNotFound is returned by MutableLoader.Unregister if no agent
+	// was registered under the name.
+	ErrAgentNotFound = errors.New("agent not found")
 )
 
 // Loader allows to load a particular agent by name and get the root agent
@@ -28,6 +42,31 @@ type Loader interface {
 	RootAgent() Agent
 }
 
+// ReloadableLoader is a Loader whose agents can be rebuilt while they are
+// in use, e.g. from changed declarative definitions. The agents returned
+// after a reload are used by the next invocations.
+type ReloadableLoader interface {
+	Loader
+	// Reload rebuilds the agents. On error, the previous agents are kept.
+	Reload(ctx context.Context) error
+	// Watch reloads the agents whenever their definitions change, checking
+	// every interval, until ctx is done.
+	Watch(ctx context.Context, interval time.Duration)
+}
+
+// MutableLoader is a Loader whose agents can be added and removed while
+// they are in use, e.g. to register apps at runtime.
+type MutableLoader interface {
+	Loader
+	// Register serves a under name. It returns an error wrapping
+	// ErrAgentExists if an agent is already served under name.
+	Register(name string, a Agent) error
+	// Unregister stops serving the agent registered under name. It returns
+	// an error wrapping ErrAgentNotFound if no agent was registered under
+	// name. The agents of the base loader can't be unregistered.
+	Unregister(name string) error
+}
+
 // multiLoader should be used when you have multiple agents
 type multiLoader struct {
 	agentMap map[string]Agent
@@ -105,3 +144,99 @@ func (m *multiLoader) LoadAgent(name string) (Agent, error) {
 func (m *multiLoader) RootAgent() Agent {
 	return m.root
 }
+
+// NewMutableLoader returns a MutableLoader serving the agents of base, which
+// may be nil, and the agents registered at runtime.
+func NewMutableLoader(base Loader) MutableLoader {
+	return &mutableLoader{base: base, agents: make(map[string]Agent)}
+}
+
+type mutableLoader struct {
+	base Loader
+
+	mu     sync.RWMutex
+	agents map[string]Agent
+}
+
+// ListAgents implements Loader. The agents of the base loader are listed
+// first, followed by the registered agents in alphabetical order.
+func (m *mutableLoader) ListAgents() []string {
+	var names []string
+	if m.base != nil {
+		names = m.base.ListAgents()
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	registered := make([]string, 0, len(m.agents))
+	for name := range m.agents {
+		registered = append(registered, name)
+	}
+	slices.Sort(registered)
+	return append(names, registered...)
+}
+
+// LoadAgent implements Loader.
+func (m *mutableLoader) LoadAgent(name string) (Agent, error) {
+	m.mu.RLock()
+	a, ok := m.agents[name]
+	m.mu.RUnlock()
+	if ok {
+		return a, nil
+	}
+	if m.base == nil {
+		return nil, fmt.Errorf("agent %s not found. Please specify one of those: %v", name, m.ListAgents())
+	}
+	return m.base.LoadAgent(name)
+}
+
+// RootAgent implements Loader. It returns the root agent of the base
+// loader, or if there is none, the first registered agent in alphabetical
+// order.
+func (m *mutableLoader) RootAgent() Agent {
+	if m.base != nil {
+		if root := m.base.RootAgent(); root != nil {
+			return root
+		}
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if len(m.agents) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(m.agents))
+	for name := range m.agents {
+		names = append(names, name)
+	}
+	return m.agents[slices.Min(names)]
+}
+
+// Register implements MutableLoader.
+func (m *mutableLoader) Register(name string, a Agent) error {
+	if name == "" {
+		return errors.New("agent name is required")
+	}
+	if a == nil {
+		return errors.New("agent is required")
+	}
+	if m.base != nil && slices.Contains(m.base.ListAgents(), name) {
+		return fmt.Errorf("%w: %s", ErrAgentExists, name)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.agents[name]; ok {
+		return fmt.Errorf("%w: %s", ErrAgentExists, name)
+	}
+	m.agents[name] = a
+	return nil
+}
+
+// Unregister implements MutableLoader.
+func (m *mutableLoader) Unregister(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.agents[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrAgentNotFound, name)
+	}
+	delete(m.agents, name)
+	return nil
+}
@@ -15,9 +15,12 @@
 package agent
 
 import (
+	"errors"
 	"iter"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
 	"google.golang.org/adk/session"
 )
 
@@ -103,3 +106,57 @@ func TestDuplicateName(t *testing.T) {
 		}
 	}
 }
+
+func TestMutableLoader(t *testing.T) {
+	root := &testAgent{name: "root"}
+	loader := NewMutableLoader(NewSingleLoader(root))
+
+	for _, name := range []string{"zeta", "alpha"} {
+		if err := loader.Register(name, &testAgent{name: name}); err != nil {
+			t.Fatalf("Register(%q) error = %v", name, err)
+		}
+	}
+	for _, name := range []string{"root", "alpha"} {
+		if err := loader.Register(name, &testAgent{name: name}); !errors.Is(err, ErrAgentExists) {
+			t.Errorf("Register(%q) error = %v, want ErrAgentExists", name, err)
+		}
+	}
+	if diff := cmp.Diff([]string{"root", "alpha", "zeta"}, loader.ListAgents()); diff != "" {
+		t.Errorf("ListAgents() mismatch (-want +got):\n%s", diff)
+	}
+	if a, err := loader.LoadAgent("alpha"); err != nil || a.Name() != "alpha" {
+		t.Errorf("LoadAgent(alpha) = %v, %v, want alpha", a, err)
+	}
+	if got := loader.RootAgent(); got != root {
+		t.Errorf("RootAgent() = %v, want root", got)
+	}
+
+	if err := loader.Unregister("alpha"); err != nil {
+		t.Fatalf("Unregister(alpha) error = %v", err)
+	}
+	for _, name := range []string{"alpha", "root"} {
+		if err := loader.Unregister(name); !errors.Is(err, ErrAgentNotFound) {
+			t.Errorf("Unregister(%q) error = %v, want ErrAgentNotFound", name, err)
+		}
+	}
+	if _, err := loader.LoadAgent("alpha"); err == nil {
+		t.Errorf("LoadAgent(alpha) succeeded after Unregister")
+	}
+}
+
+func TestMutableLoader_NoBase(t *testing.T) {
+	loader := NewMutableLoader(nil)
+	if got := loader.RootAgent(); got != nil {
+		t.Errorf("RootAgent() = %v, want nil", got)
+	}
+	b := &testAgent{name: "b"}
+	a := &testAgent{name: "a"}
+	for _, ag := range []Agent{b, a} {
+		if err := loader.Register(ag.Name(), ag); err != nil {
+			t.Fatalf("Register(%q) error = %v", ag.Name(), err)
+		}
+	}
+	if got := loader.RootAgent(); got != a {
+		t.Errorf("RootAgent() = %v, want a", got)
+	}
+}
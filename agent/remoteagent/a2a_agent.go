@@ -15,18 +15,25 @@
 package remoteagent
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"iter"
+	"net/http"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/a2aproject/a2a-go/a2a"
 	"github.com/a2aproject/a2a-go/a2aclient"
 	"github.com/a2aproject/a2a-go/a2aclient/agentcard"
 
 	"google.golang.org/adk/agent"
+	agentinternal "google.golang.org/adk/internal/agent"
 	"google.golang.org/adk/internal/converters"
+	"google.golang.org/adk/internal/logging"
+	"google.golang.org/adk/internal/telemetry"
 	"google.golang.org/adk/server/adka2a"
 	"google.golang.org/adk/session"
 )
@@ -47,6 +54,24 @@ type A2AConfig struct {
 	ClientFactory *a2aclient.Factory
 	// MessageSendConfig is attached to a2a.MessageSendParams sent on every agent invocation.
 	MessageSendConfig *a2a.MessageSendConfig
+	// Auth, if set, attaches credentials to the agent card resolution and to the A2A calls.
+	Auth A2AAuth
+
+	// Retry, if set, retries the calls failing before any event is received.
+	Retry *RetryPolicy
+	// Timeout, if positive, bounds the duration of an invocation, including the retries.
+	Timeout time.Duration
+	// CircuitBreaker, if set, rejects the calls for a while after consecutive failures.
+	CircuitBreaker *CircuitBreakerConfig
+
+	// TaskPollInterval is the interval between the task status checks made when the agent
+	// card does not advertise streaming support. Defaults to 1 second.
+	TaskPollInterval time.Duration
+
+	// SaveFilesAsArtifacts, if set, stores the inline files received from the agent using the
+	// artifact service of the invocation. The files are replaced by text placeholders in the
+	// events and the saved versions are reported in EventActions.ArtifactDelta.
+	SaveFilesAsArtifacts bool
 }
 
 // NewA2A creates a remote A2A agent. A2A (Agent-To-Agent) protocol is used for communication with an
@@ -56,88 +81,217 @@ func NewA2A(cfg A2AConfig) (agent.Agent, error) {
 		return nil, fmt.Errorf("either AgentCard or AgentCardSource must be provided")
 	}
 
-	remoteAgent := &a2aAgent{resolvedCard: cfg.AgentCard}
-	return agent.New(agent.Config{
+	remoteAgent := &a2aAgent{resolvedCard: cfg.AgentCard, breaker: newCircuitBreaker(cfg.CircuitBreaker)}
+	a, err := agent.New(agent.Config{
 		Name:        cfg.Name,
 		Description: cfg.Description,
 		Run: func(ic agent.InvocationContext) iter.Seq2[*session.Event, error] {
 			return remoteAgent.run(ic, cfg)
 		},
 	})
+	if err != nil {
+		return nil, err
+	}
+	internalAgent, ok := a.(agentinternal.Agent)
+	if !ok {
+		return nil, fmt.Errorf("internal error: failed to convert to internal agent")
+	}
+	state := agentinternal.Reveal(internalAgent)
+	state.AgentType = agentinternal.TypeRemoteA2AAgent
+	state.Config = cfg
+	return a, nil
 }
 
 type a2aAgent struct {
 	resolvedCard *a2a.AgentCard
+	breaker      *circuitBreaker
 }
 
 func (a *a2aAgent) run(ctx agent.InvocationContext, cfg A2AConfig) iter.Seq2[*session.Event, error] {
 	return func(yield func(*session.Event, error) bool) {
-		card, err := resolveAgentCard(ctx, cfg)
-		if err != nil {
-			yield(toErrorEvent(ctx, fmt.Errorf("agent card resolution failed: %w", err)), nil)
-			return
+		callCtx := context.Context(ctx)
+		if cfg.Timeout > 0 {
+			var cancel context.CancelFunc
+			callCtx, cancel = context.WithTimeout(ctx, cfg.Timeout)
+			defer cancel()
 		}
-		a.resolvedCard = card
 
-		var client *a2aclient.Client
-		if cfg.ClientFactory != nil {
-			client, err = cfg.ClientFactory.CreateFromCard(ctx, card)
-		} else {
-			client, err = a2aclient.NewFromCard(ctx, card)
+		for attempt := 1; ; attempt++ {
+			if err := a.breaker.allow(); err != nil {
+				yield(toErrorEvent(ctx, fmt.Errorf("remote agent unavailable: %w", err)), nil)
+				return
+			}
+			res := a.send(ctx, callCtx, cfg, attempt, yield)
+			a.breaker.record(res.err != nil && cfg.Retry.retryable(res.err))
+			if res.err == nil {
+				return
+			}
+			if res.started || !cfg.Retry.retries(attempt, res.err) {
+				event := toErrorEvent(ctx, res.err)
+				updateCustomMetadata(event, res.req, nil)
+				setAttemptMetadata(event, attempt)
+				yield(event, nil)
+				return
+			}
+
+			delay := cfg.Retry.backoff(attempt)
+			logging.FromContext(ctx).Warn("retrying A2A call", "attempt", attempt, "delay", delay, "error", res.err)
+			timer := time.NewTimer(delay)
+			select {
+			case <-callCtx.Done():
+				timer.Stop()
+				event := toErrorEvent(ctx, fmt.Errorf("%w: %w", res.err, context.Cause(callCtx)))
+				updateCustomMetadata(event, res.req, nil)
+				setAttemptMetadata(event, attempt)
+				yield(event, nil)
+				return
+			case <-timer.C:
+			}
 		}
+	}
+}
+
+// sendResult is the outcome of an attempt to call the remote agent.
+type sendResult struct {
+	// started is set once an event was yielded.
+	started bool
+	// req is the request sent to the agent, if any.
+	req *a2a.MessageSendParams
+	err error
+}
+
+// send makes an attempt to call the remote agent and yields the received
+// events. The A2A calls use callCtx, which is bound by the invocation timeout.
+func (a *a2aAgent) send(ctx agent.InvocationContext, callCtx context.Context, cfg A2AConfig, attempt int, yield func(*session.Event, error) bool) sendResult {
+	card, err := resolveAgentCard(callCtx, cfg)
+	if err != nil {
+		return sendResult{err: fmt.Errorf("agent card resolution failed: %w", err)}
+	}
+	a.resolvedCard = card
+
+	var opts []a2aclient.FactoryOption
+	if cfg.Auth != nil {
+		opts = append(opts, a2aclient.WithInterceptors(&authInterceptor{auth: cfg.Auth}))
+	}
+	var client *a2aclient.Client
+	if cfg.ClientFactory != nil {
+		client, err = a2aclient.WithAdditionalOptions(cfg.ClientFactory, opts...).CreateFromCard(callCtx, card)
+	} else {
+		client, err = a2aclient.NewFromCard(callCtx, card, opts...)
+	}
+	if err != nil {
+		return sendResult{err: fmt.Errorf("client creation failed: %w", err)}
+	}
+	defer destroy(ctx, client)
+
+	msg, err := newMessage(ctx)
+	if err != nil {
+		return sendResult{err: fmt.Errorf("message creation failed: %w", err)}
+	}
+
+	if len(msg.Parts) == 0 {
+		yield(adka2a.NewRemoteAgentEvent(ctx), nil)
+		return sendResult{started: true}
+	}
+
+	spanCtx, spans := telemetry.StartTrace(callCtx, "send_a2a_message "+card.Name)
+	var traceErr error
+	defer func() { telemetry.EndTrace(spans, traceErr) }()
+	telemetry.TraceA2ACall(spans, card.Name, card.URL, ctx.Session().ID(), ctx.InvocationID())
+
+	logger := logging.FromContext(ctx).With("remote_agent", card.Name, "url", card.URL, "message_id", msg.ID)
+	logger.Debug("sending A2A message", "attempt", attempt)
+
+	res := sendResult{req: &a2a.MessageSendParams{Message: msg, Config: cfg.MessageSendConfig}}
+	var files *fileSaver
+	if cfg.SaveFilesAsArtifacts {
+		files = &fileSaver{}
+	}
+	var a2aEvents iter.Seq2[a2a.Event, error]
+	if card.Capabilities.Streaming {
+		a2aEvents = client.SendStreamingMessage(spanCtx, res.req)
+	} else {
+		logger.Debug("agent does not support streaming, falling back to unary call")
+		a2aEvents = sendMessage(spanCtx, client, res.req, cfg.TaskPollInterval)
+	}
+	for a2aEvent, err := range a2aEvents {
 		if err != nil {
-			yield(toErrorEvent(ctx, fmt.Errorf("client creation failed: %w", err)), nil)
-			return
+			traceErr = err
+			logger.Warn("A2A call failed", "error", err)
+			res.err = err
+			return res
 		}
-		defer destroy(client)
 
-		msg, err := newMessage(ctx)
+		logger.Debug("received A2A event", "type", fmt.Sprintf("%T", a2aEvent))
+		event, err := adka2a.ToSessionEvent(ctx, a2aEvent)
 		if err != nil {
-			yield(toErrorEvent(ctx, fmt.Errorf("message creation failed: %w", err)), nil)
-			return
+			logger.Warn("failed to convert A2A event", "error", err)
+			res.err = fmt.Errorf("failed to convert a2aEvent: %w", err)
+			return res
 		}
 
-		if len(msg.Parts) == 0 {
-			yield(adka2a.NewRemoteAgentEvent(ctx), nil)
-			return
+		if event == nil {
+			continue
 		}
 
-		req := &a2a.MessageSendParams{Message: msg, Config: cfg.MessageSendConfig}
-		for a2aEvent, err := range client.SendStreamingMessage(ctx, req) {
-			if err != nil {
-				event := toErrorEvent(ctx, err)
-				updateCustomMetadata(event, req, nil)
-				yield(event, nil)
-				return
+		if files != nil {
+			if err := files.save(ctx, event); err != nil {
+				logger.Warn("failed to save received files", "error", err)
+				res.err = err
+				return res
 			}
+		}
 
-			event, err := adka2a.ToSessionEvent(ctx, a2aEvent)
-			if err != nil {
-				event := toErrorEvent(ctx, fmt.Errorf("failed to convert a2aEvent: %w", err))
-				updateCustomMetadata(event, req, nil)
-				yield(event, nil)
-				return
-			}
+		updateCustomMetadata(event, res.req, a2aEvent)
+		setAttemptMetadata(event, attempt)
+		res.started = true
+		if !yield(event, nil) {
+			return res
+		}
+	}
 
-			if event == nil {
-				continue
-			}
+	if files != nil && len(files.pending) > 0 {
+		// The files were received in partial events only, report the saved versions
+		// in a dedicated event so that they get persisted.
+		event := adka2a.NewRemoteAgentEvent(ctx)
+		files.flush(event)
+		yield(event, nil)
+	}
+	return res
+}
 
-			updateCustomMetadata(event, req, a2aEvent)
-			if !yield(event, nil) {
-				break
-			}
-		}
+// setAttemptMetadata records on the event the attempt which produced it, if
+// the call was retried.
+func setAttemptMetadata(event *session.Event, attempt int) {
+	if attempt < 2 {
+		return
 	}
+	if event.CustomMetadata == nil {
+		event.CustomMetadata = map[string]any{}
+	}
+	event.CustomMetadata[adka2a.ToADKMetaKey("attempt")] = attempt
 }
 
-func resolveAgentCard(ctx agent.InvocationContext, cfg A2AConfig) (*a2a.AgentCard, error) {
+func resolveAgentCard(ctx context.Context, cfg A2AConfig) (*a2a.AgentCard, error) {
 	if cfg.AgentCard != nil {
 		return cfg.AgentCard, nil
 	}
 
 	if strings.HasPrefix(cfg.AgentCardSource, "http://") || strings.HasPrefix(cfg.AgentCardSource, "https://") {
-		card, err := agentcard.DefaultResolver.Resolve(ctx, cfg.AgentCardSource, cfg.CardResolveOptions...)
+		opts := cfg.CardResolveOptions
+		if cfg.Auth != nil {
+			header := http.Header{}
+			if err := cfg.Auth.Apply(ctx, nil, header); err != nil {
+				return nil, fmt.Errorf("failed to apply credentials: %w", err)
+			}
+			opts = slices.Clone(opts)
+			for name, values := range header {
+				for _, v := range values {
+					opts = append(opts, agentcard.WithRequestHeader(name, v))
+				}
+			}
+		}
+		card, err := agentcard.DefaultResolver.Resolve(ctx, cfg.AgentCardSource, opts...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to fetch an agent card: %w", err)
 		}
@@ -204,7 +358,8 @@ func updateCustomMetadata(event *session.Event, request *a2a.MessageSendParams,
 	}
 }
 
-func destroy(client *a2aclient.Client) {
-	// TODO(yarolegovich): log ignored error
-	_ = client.Destroy()
+func destroy(ctx context.Context, client *a2aclient.Client) {
+	if err := client.Destroy(); err != nil {
+		logging.FromContext(ctx).Warn("failed to destroy A2A client", "error", err)
+	}
 }
@@ -0,0 +1,122 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remoteagent
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/session"
+)
+
+// fileSaver saves the files received from a remote agent to the artifact
+// service of the invocation.
+type fileSaver struct {
+	// pending holds the versions of the artifacts saved from partial events,
+	// which are not persisted in the session. They are reported on the next
+	// persisted event.
+	pending map[string]int64
+	// last holds the last saved version of each artifact. Task snapshots repeat
+	// the artifacts already received, they are not saved again.
+	last  map[string]savedFile
+	saved int
+}
+
+type savedFile struct {
+	blob    *genai.Blob
+	version int64
+}
+
+// save replaces the inline files of the event with text placeholders after
+// storing them as artifacts.
+func (s *fileSaver) save(ctx agent.InvocationContext, event *session.Event) error {
+	artifacts := ctx.Artifacts()
+	if artifacts != nil && event.Content != nil {
+		for i, part := range event.Content.Parts {
+			if part.InlineData == nil {
+				continue
+			}
+			fileName, version, err := s.saveFile(ctx, artifacts, part)
+			if err != nil {
+				return err
+			}
+			event.Content.Parts[i] = &genai.Part{
+				Text: fmt.Sprintf("Received file: %s. It has been saved to the artifacts (version %d)", fileName, version),
+			}
+		}
+	}
+	if !event.Partial {
+		s.flush(event)
+	}
+	return nil
+}
+
+func (s *fileSaver) saveFile(ctx agent.InvocationContext, artifacts agent.Artifacts, part *genai.Part) (string, int64, error) {
+	blob := part.InlineData
+	fileName := remoteFileName(blob.DisplayName)
+	if fileName == "" {
+		fileName = fmt.Sprintf("artifact_%s_%d", ctx.InvocationID(), s.saved)
+	}
+	if last, ok := s.last[fileName]; ok && last.blob.MIMEType == blob.MIMEType && bytes.Equal(last.blob.Data, blob.Data) {
+		return fileName, last.version, nil
+	}
+
+	resp, err := artifacts.Save(ctx, fileName, part)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to save artifact %s: %w", fileName, err)
+	}
+	s.saved++
+	if s.pending == nil {
+		s.pending = map[string]int64{}
+	}
+	if s.last == nil {
+		s.last = map[string]savedFile{}
+	}
+	s.pending[fileName] = resp.Version
+	s.last[fileName] = savedFile{blob: blob, version: resp.Version}
+	return fileName, resp.Version, nil
+}
+
+// remoteFileName turns the name of a file sent by the remote agent into a
+// valid artifact name. The files are saved in the scope of the session: the
+// remote agent can't overwrite the artifacts of the user.
+func remoteFileName(name string) string {
+	for strings.HasPrefix(name, "user:") {
+		name = strings.TrimPrefix(name, "user:")
+	}
+	if strings.Trim(name, "/. ") == "" {
+		return ""
+	}
+	return artifact.SlugFileName(name)
+}
+
+// flush records the saved artifact versions in the event actions.
+func (s *fileSaver) flush(event *session.Event) {
+	if len(s.pending) == 0 {
+		return
+	}
+	if event.Actions.ArtifactDelta == nil {
+		event.Actions.ArtifactDelta = map[string]int64{}
+	}
+	for name, version := range s.pending {
+		event.Actions.ArtifactDelta[name] = version
+	}
+	s.pending = nil
+}
@@ -0,0 +1,201 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remoteagent
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/a2aproject/a2a-go/a2asrv/eventqueue"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/artifact"
+	artifactinternal "google.golang.org/adk/internal/artifact"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/session"
+)
+
+func newInvocationContextWithArtifacts(t *testing.T, service artifact.Service) agent.InvocationContext {
+	t.Helper()
+	ic := newInvocationContext(t, []*session.Event{newUserHello()})
+	return icontext.NewInvocationContext(ic, icontext.InvocationContextParams{
+		Session: ic.Session(),
+		Artifacts: &artifactinternal.Artifacts{
+			Service:   service,
+			AppName:   ic.Session().AppName(),
+			UserID:    ic.Session().UserID(),
+			SessionID: ic.Session().ID(),
+		},
+	})
+}
+
+func TestRemoteAgent_SaveFilesAsArtifacts(t *testing.T) {
+	fileBytes := []byte("quarterly report")
+	executor := &mockA2AExecutor{
+		executeFn: func(ctx context.Context, reqCtx *a2asrv.RequestContext, queue eventqueue.Queue) error {
+			artifactEvents := []*a2a.TaskArtifactUpdateEvent{
+				a2a.NewArtifactEvent(reqCtx, a2a.FilePart{File: a2a.FileBytes{
+					FileMeta: a2a.FileMeta{Name: "report.txt", MimeType: "text/plain"},
+					Bytes:    base64.StdEncoding.EncodeToString(fileBytes),
+				}}),
+				a2a.NewArtifactEvent(reqCtx, a2a.FilePart{File: a2a.FileURI{URI: "gs://bucket/file.pdf"}}),
+			}
+			// The server updates the last queued task in place on artifact
+			// events, the task snapshot repeating the files is queued after them
+			// with its own copy of the artifacts.
+			snapshot := a2a.NewSubmittedTask(reqCtx, reqCtx.Message)
+			var events []a2a.Event
+			for _, event := range artifactEvents {
+				artifact := *event.Artifact
+				snapshot.Artifacts = append(snapshot.Artifacts, &artifact)
+				events = append(events, event)
+			}
+			events = append(events, snapshot)
+			final := a2a.NewStatusUpdateEvent(reqCtx, a2a.TaskStateCompleted, nil)
+			final.Final = true
+			for _, event := range append(events, final) {
+				if err := queue.Write(ctx, event); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	server := httptest.NewServer(a2asrv.NewJSONRPCHandler(a2asrv.NewHandler(executor)))
+	defer server.Close()
+
+	remoteAgent, err := NewA2A(A2AConfig{Name: "a2a", AgentCard: newJSONRPCCard(server.URL), SaveFilesAsArtifacts: true})
+	if err != nil {
+		t.Fatalf("remoteagent.NewA2A() error = %v", err)
+	}
+	artifacts := artifact.InMemoryService()
+	ic := newInvocationContextWithArtifacts(t, artifacts)
+	gotEvents, err := runAndCollect(ic, remoteAgent)
+	if err != nil {
+		t.Fatalf("agent.Run() error = %v", err)
+	}
+
+	// The file can be repeated by task snapshots, it must be saved once.
+	wantText := "Received file: report.txt. It has been saved to the artifacts (version 1)"
+	var gotTexts []string
+	for _, text := range eventTexts(gotEvents) {
+		if text != "" && !slices.Contains(gotTexts, text) {
+			gotTexts = append(gotTexts, text)
+		}
+	}
+	if diff := cmp.Diff([]string{wantText}, gotTexts); diff != "" {
+		t.Errorf("agent.Run() texts mismatch (-want +got):\n%s", diff)
+	}
+	var gotFileURIs []string
+	for _, event := range gotEvents {
+		if event.Content == nil {
+			continue
+		}
+		for _, part := range event.Content.Parts {
+			if part.InlineData != nil {
+				t.Errorf("event part has inline data %+v, want it replaced", part.InlineData)
+			}
+			if part.FileData != nil {
+				gotFileURIs = append(gotFileURIs, part.FileData.FileURI)
+			}
+		}
+	}
+	if diff := cmp.Diff([]string{"gs://bucket/file.pdf"}, slices.Compact(gotFileURIs)); diff != "" {
+		t.Errorf("file references mismatch (-want +got):\n%s", diff)
+	}
+
+	last := gotEvents[len(gotEvents)-1]
+	if last.Partial {
+		t.Fatalf("last event is partial, want the artifact delta on a persisted event")
+	}
+	if diff := cmp.Diff(map[string]int64{"report.txt": 1}, last.Actions.ArtifactDelta); diff != "" {
+		t.Errorf("ArtifactDelta mismatch (-want +got):\n%s", diff)
+	}
+
+	sess := ic.Session()
+	resp, err := artifacts.Load(t.Context(), &artifact.LoadRequest{AppName: sess.AppName(), UserID: sess.UserID(), SessionID: sess.ID(), FileName: "report.txt"})
+	if err != nil {
+		t.Fatalf("artifacts.Load() error = %v", err)
+	}
+	want := &genai.Part{InlineData: &genai.Blob{Data: fileBytes, MIMEType: "text/plain", DisplayName: "report.txt"}}
+	if diff := cmp.Diff(want, resp.Part); diff != "" {
+		t.Errorf("saved artifact mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestFileSaver_NonPartialEvent(t *testing.T) {
+	artifacts := artifact.InMemoryService()
+	ic := newInvocationContextWithArtifacts(t, artifacts)
+	event := session.NewEvent(ic.InvocationID())
+	event.Content = genai.NewContentFromParts([]*genai.Part{
+		genai.NewPartFromText("here you go"),
+		genai.NewPartFromBytes([]byte("png"), "image/png"),
+	}, genai.RoleModel)
+
+	saver := &fileSaver{}
+	if err := saver.save(ic, event); err != nil {
+		t.Fatalf("save() error = %v", err)
+	}
+
+	name := "artifact_" + ic.InvocationID() + "_0"
+	if diff := cmp.Diff(map[string]int64{name: 1}, event.Actions.ArtifactDelta); diff != "" {
+		t.Errorf("ArtifactDelta mismatch (-want +got):\n%s", diff)
+	}
+	if event.Content.Parts[1].InlineData != nil {
+		t.Errorf("inline data was not replaced")
+	}
+	if len(saver.pending) != 0 {
+		t.Errorf("pending = %v, want empty after a non-partial event", saver.pending)
+	}
+}
+
+func TestFileSaver_RemoteFileNames(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		want string
+	}{
+		{name: "report.txt", want: "report.txt"},
+		{name: "user:profile.png", want: "profile.png"},
+		{name: "user:user:profile.png", want: "profile.png"},
+		{name: "../../etc/passwd", want: "etc/passwd"},
+		{name: "a:b.txt", want: "a_b.txt"},
+		{name: "user:", want: "artifact_{invocation}_0"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			artifacts := artifact.InMemoryService()
+			ic := newInvocationContextWithArtifacts(t, artifacts)
+			event := session.NewEvent(ic.InvocationID())
+			event.Content = genai.NewContentFromParts([]*genai.Part{
+				{InlineData: &genai.Blob{Data: []byte("data"), MIMEType: "text/plain", DisplayName: tc.name}},
+			}, genai.RoleModel)
+
+			if err := (&fileSaver{}).save(ic, event); err != nil {
+				t.Fatalf("save() error = %v", err)
+			}
+			want := strings.ReplaceAll(tc.want, "{invocation}", ic.InvocationID())
+			if diff := cmp.Diff(map[string]int64{want: 1}, event.Actions.ArtifactDelta); diff != "" {
+				t.Errorf("ArtifactDelta mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remoteagent
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2aclient"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// A2AAuth attaches credentials to the requests sent to a remote agent,
+// including the agent card resolution.
+type A2AAuth interface {
+	// Apply sets the credentials on the headers of a request. The card is
+	// nil while the card itself is being resolved, otherwise its security
+	// schemes tell which credentials the agent accepts.
+	Apply(ctx context.Context, card *a2a.AgentCard, header http.Header) error
+}
+
+// AuthFunc is an [A2AAuth] computing the credentials of every request.
+type AuthFunc func(ctx context.Context, card *a2a.AgentCard, header http.Header) error
+
+// Apply implements A2AAuth.
+func (f AuthFunc) Apply(ctx context.Context, card *a2a.AgentCard, header http.Header) error {
+	return f(ctx, card, header)
+}
+
+// BearerToken returns an [A2AAuth] sending token in the Authorization
+// header. If the card declares security schemes, the token is only sent if
+// one of them accepts bearer tokens.
+func BearerToken(token string) A2AAuth {
+	return TokenSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}))
+}
+
+// TokenSource returns an [A2AAuth] sending the tokens of ts as bearer tokens,
+// e.g. to use OAuth2 tokens refreshed when they expire. If the card declares
+// security schemes, the token is only sent if one of them accepts bearer
+// tokens.
+func TokenSource(ts oauth2.TokenSource) A2AAuth {
+	return &tokenAuth{ts: oauth2.ReuseTokenSource(nil, ts)}
+}
+
+// OAuth2ClientCredentials returns an [A2AAuth] sending the tokens obtained
+// with the OAuth2 client credentials flow. The tokens are refreshed when they
+// expire. If cfg.TokenURL is empty, the token URL of the client credentials
+// flow declared in the card is used.
+func OAuth2ClientCredentials(cfg clientcredentials.Config) A2AAuth {
+	return &clientCredentialsAuth{config: cfg}
+}
+
+// APIKey returns an [A2AAuth] sending key in the header declared by the API
+// key security scheme of the card. The header named header is used if the
+// card declares no such scheme.
+func APIKey(header, key string) A2AAuth {
+	return &apiKeyAuth{header: header, key: key}
+}
+
+type tokenAuth struct {
+	ts oauth2.TokenSource
+}
+
+func (a *tokenAuth) Apply(ctx context.Context, card *a2a.AgentCard, header http.Header) error {
+	if !acceptsBearer(card) {
+		return nil
+	}
+	token, err := a.ts.Token()
+	if err != nil {
+		return fmt.Errorf("failed to get token: %w", err)
+	}
+	token.SetAuthHeader(&http.Request{Header: header})
+	return nil
+}
+
+type clientCredentialsAuth struct {
+	config clientcredentials.Config
+
+	mu sync.Mutex
+	ts oauth2.TokenSource
+}
+
+func (a *clientCredentialsAuth) Apply(ctx context.Context, card *a2a.AgentCard, header http.Header) error {
+	if !acceptsBearer(card) {
+		return nil
+	}
+	ts := a.tokenSource(ctx, card)
+	if ts == nil {
+		// The card is needed to find the token URL.
+		return nil
+	}
+	token, err := ts.Token()
+	if err != nil {
+		return fmt.Errorf("failed to get OAuth2 token: %w", err)
+	}
+	token.SetAuthHeader(&http.Request{Header: header})
+	return nil
+}
+
+func (a *clientCredentialsAuth) tokenSource(ctx context.Context, card *a2a.AgentCard) oauth2.TokenSource {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.ts != nil {
+		return a.ts
+	}
+	cfg := a.config
+	if cfg.TokenURL == "" {
+		cfg.TokenURL = clientCredentialsTokenURL(card)
+	}
+	if cfg.TokenURL == "" {
+		return nil
+	}
+	// The token source outlives the request, the context only carries the
+	// values used by the token requests.
+	a.ts = cfg.TokenSource(context.WithoutCancel(ctx))
+	return a.ts
+}
+
+type apiKeyAuth struct {
+	header string
+	key    string
+}
+
+func (a *apiKeyAuth) Apply(ctx context.Context, card *a2a.AgentCard, header http.Header) error {
+	name, in := a.header, a2a.APIKeySecuritySchemeInHeader
+	if card != nil && len(card.SecuritySchemes) > 0 {
+		scheme, ok := findScheme[a2a.APIKeySecurityScheme](card)
+		if !ok {
+			return nil
+		}
+		name, in = scheme.Name, scheme.In
+	}
+	switch in {
+	case a2a.APIKeySecuritySchemeInHeader:
+		header.Set(name, a.key)
+	case a2a.APIKeySecuritySchemeInCookie:
+		header.Add("Cookie", (&http.Cookie{Name: name, Value: a.key}).String())
+	default:
+		return fmt.Errorf("unsupported API key location %q", in)
+	}
+	return nil
+}
+
+// acceptsBearer reports whether the agent accepts bearer tokens. An agent
+// without security schemes is assumed to accept them.
+func acceptsBearer(card *a2a.AgentCard) bool {
+	if card == nil || len(card.SecuritySchemes) == 0 {
+		return true
+	}
+	for _, scheme := range card.SecuritySchemes {
+		switch s := scheme.(type) {
+		case a2a.HTTPAuthSecurityScheme:
+			if strings.EqualFold(s.Scheme, "bearer") {
+				return true
+			}
+		case a2a.OAuth2SecurityScheme, a2a.OpenIDConnectSecurityScheme:
+			return true
+		}
+	}
+	return false
+}
+
+// clientCredentialsTokenURL returns the token URL of the OAuth2 client
+// credentials flow declared in the card.
+func clientCredentialsTokenURL(card *a2a.AgentCard) string {
+	if card == nil {
+		return ""
+	}
+	scheme, ok := findScheme[a2a.OAuth2SecurityScheme](card)
+	if !ok {
+		return ""
+	}
+	if flow := scheme.Flows.ClientCredentials; flow != nil {
+		return flow.TokenURL
+	}
+	return ""
+}
+
+func findScheme[S a2a.SecurityScheme](card *a2a.AgentCard) (S, bool) {
+	for _, scheme := range card.SecuritySchemes {
+		if s, ok := scheme.(S); ok {
+			return s, true
+		}
+	}
+	var zero S
+	return zero, false
+}
+
+// authInterceptor applies the credentials to the A2A client calls.
+type authInterceptor struct {
+	a2aclient.PassthroughInterceptor
+	auth A2AAuth
+}
+
+func (i *authInterceptor) Before(ctx context.Context, req *a2aclient.Request) (context.Context, error) {
+	header := http.Header{}
+	if err := i.auth.Apply(ctx, req.Card, header); err != nil {
+		return ctx, err
+	}
+	for k, v := range header {
+		req.Meta[k] = v
+	}
+	return ctx, nil
+}
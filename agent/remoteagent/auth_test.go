@@ -0,0 +1,185 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remoteagent
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/oauth2/clientcredentials"
+
+	"google.golang.org/adk/session"
+)
+
+func TestA2AAuth_Apply(t *testing.T) {
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.Form.Get("grant_type") != "client_credentials" {
+			t.Errorf("token request form = %v, %v, want client_credentials grant", r.Form, err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token": "oauth-token", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	defer tokenServer.Close()
+
+	bearerCard := &a2a.AgentCard{SecuritySchemes: a2a.NamedSecuritySchemes{
+		"bearer": a2a.HTTPAuthSecurityScheme{Scheme: "Bearer"},
+	}}
+	apiKeyCard := &a2a.AgentCard{SecuritySchemes: a2a.NamedSecuritySchemes{
+		"key": a2a.APIKeySecurityScheme{Name: "X-Agent-Key", In: a2a.APIKeySecuritySchemeInHeader},
+	}}
+	cookieCard := &a2a.AgentCard{SecuritySchemes: a2a.NamedSecuritySchemes{
+		"key": a2a.APIKeySecurityScheme{Name: "session", In: a2a.APIKeySecuritySchemeInCookie},
+	}}
+	oauthCard := &a2a.AgentCard{SecuritySchemes: a2a.NamedSecuritySchemes{
+		"oauth": a2a.OAuth2SecurityScheme{Flows: a2a.OAuthFlows{
+			ClientCredentials: &a2a.ClientCredentialsOAuthFlow{TokenURL: tokenServer.URL},
+		}},
+	}}
+
+	testCases := []struct {
+		name string
+		auth A2AAuth
+		card *a2a.AgentCard
+		want http.Header
+	}{
+		{
+			name: "bearer without card",
+			auth: BearerToken("token"),
+			want: http.Header{"Authorization": {"Bearer token"}},
+		},
+		{
+			name: "bearer accepted by card",
+			auth: BearerToken("token"),
+			card: bearerCard,
+			want: http.Header{"Authorization": {"Bearer token"}},
+		},
+		{
+			name: "bearer not accepted by card",
+			auth: BearerToken("token"),
+			card: apiKeyCard,
+			want: http.Header{},
+		},
+		{
+			name: "api key without card",
+			auth: APIKey("X-API-Key", "key"),
+			want: http.Header{"X-Api-Key": {"key"}},
+		},
+		{
+			name: "api key header from card",
+			auth: APIKey("X-API-Key", "key"),
+			card: apiKeyCard,
+			want: http.Header{"X-Agent-Key": {"key"}},
+		},
+		{
+			name: "api key cookie from card",
+			auth: APIKey("X-API-Key", "key"),
+			card: cookieCard,
+			want: http.Header{"Cookie": {"session=key"}},
+		},
+		{
+			name: "api key not accepted by card",
+			auth: APIKey("X-API-Key", "key"),
+			card: bearerCard,
+			want: http.Header{},
+		},
+		{
+			name: "client credentials with token URL from card",
+			auth: OAuth2ClientCredentials(clientcredentials.Config{ClientID: "id", ClientSecret: "secret"}),
+			card: oauthCard,
+			want: http.Header{"Authorization": {"Bearer oauth-token"}},
+		},
+		{
+			name: "client credentials without token URL",
+			auth: OAuth2ClientCredentials(clientcredentials.Config{ClientID: "id", ClientSecret: "secret"}),
+			want: http.Header{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := http.Header{}
+			if err := tc.auth.Apply(t.Context(), tc.card, got); err != nil {
+				t.Fatalf("Apply() error = %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Apply() headers mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRemoteAgent_Auth(t *testing.T) {
+	remoteEvents := []a2a.Event{a2a.NewMessage(a2a.MessageRoleAgent, a2a.TextPart{Text: "Hello!"})}
+	jsonrpcHandler := a2asrv.NewJSONRPCHandler(a2asrv.NewHandler(newA2AEventReplay(t, remoteEvents)))
+
+	var mu sync.Mutex
+	gotAuth := map[string]string{}
+	record := func(r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		gotAuth[r.URL.Path] = r.Header.Get("Authorization")
+	}
+	mux := http.NewServeMux()
+	var server *httptest.Server
+	mux.HandleFunc("/.well-known/agent-card.json", func(w http.ResponseWriter, r *http.Request) {
+		record(r)
+		card := &a2a.AgentCard{
+			PreferredTransport: a2a.TransportProtocolJSONRPC,
+			URL:                server.URL + "/invoke",
+			Capabilities:       a2a.AgentCapabilities{Streaming: true},
+			SecuritySchemes:    a2a.NamedSecuritySchemes{"bearer": a2a.HTTPAuthSecurityScheme{Scheme: "Bearer"}},
+			Security:           []a2a.SecurityRequirements{{"bearer": {}}},
+		}
+		if err := json.NewEncoder(w).Encode(card); err != nil {
+			t.Errorf("json.Encode(agentCard) error = %v", err)
+		}
+	})
+	mux.HandleFunc("/invoke", func(w http.ResponseWriter, r *http.Request) {
+		record(r)
+		jsonrpcHandler.ServeHTTP(w, r)
+	})
+	server = httptest.NewServer(mux)
+	defer server.Close()
+
+	remoteAgent, err := NewA2A(A2AConfig{Name: "a2a", AgentCardSource: server.URL, Auth: BearerToken("token")})
+	if err != nil {
+		t.Fatalf("remoteagent.NewA2A() error = %v", err)
+	}
+	ictx := newInvocationContext(t, []*session.Event{newUserHello()})
+	gotEvents, err := runAndCollect(ictx, remoteAgent)
+	if err != nil {
+		t.Fatalf("agent.Run() error = %v", err)
+	}
+	for _, ev := range gotEvents {
+		if ev.ErrorMessage != "" {
+			t.Fatalf("agent.Run() error event: %s", ev.ErrorMessage)
+		}
+	}
+
+	want := map[string]string{
+		"/.well-known/agent-card.json": "Bearer token",
+		"/invoke":                      "Bearer token",
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if diff := cmp.Diff(want, gotAuth); diff != "" {
+		t.Errorf("Authorization headers mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remoteagent
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/adk/agent"
+)
+
+// NewA2ALoader returns an agent.MutableLoader serving the agents of base,
+// which may be nil, and a remote A2A agent per config, named after the
+// config. It lets a local server, e.g. the REST API used by the web UI, serve
+// remote agents as its own apps: the sessions of the apps are stored by the
+// local session service and the files received from the remote agents are
+// always saved as artifacts of the local artifact service.
+func NewA2ALoader(base agent.Loader, cfgs ...A2AConfig) (agent.MutableLoader, error) {
+	loader := agent.NewMutableLoader(base)
+	for _, cfg := range cfgs {
+		if cfg.Name == "" {
+			return nil, errors.New("remote agent name is required")
+		}
+		cfg.SaveFilesAsArtifacts = true
+		a, err := NewA2A(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create remote agent %q: %w", cfg.Name, err)
+		}
+		if err := loader.Register(cfg.Name, a); err != nil {
+			return nil, err
+		}
+	}
+	return loader, nil
+}
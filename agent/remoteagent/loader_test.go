@@ -0,0 +1,115 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remoteagent
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/a2aproject/a2a-go/a2asrv/eventqueue"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+)
+
+func TestNewA2ALoader(t *testing.T) {
+	executor := &mockA2AExecutor{
+		executeFn: func(ctx context.Context, reqCtx *a2asrv.RequestContext, queue eventqueue.Queue) error {
+			msg := a2a.NewMessageForTask(a2a.MessageRoleAgent, reqCtx, a2a.FilePart{File: a2a.FileBytes{
+				FileMeta: a2a.FileMeta{Name: "report.txt", MimeType: "text/plain"},
+				Bytes:    base64.StdEncoding.EncodeToString([]byte("report")),
+			}})
+			return queue.Write(ctx, msg)
+		},
+	}
+	server := httptest.NewServer(a2asrv.NewJSONRPCHandler(a2asrv.NewHandler(executor)))
+	defer server.Close()
+
+	local, err := agent.New(agent.Config{Name: "local"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	loader, err := NewA2ALoader(agent.NewSingleLoader(local), A2AConfig{Name: "billing", AgentCard: newJSONRPCCard(server.URL)})
+	if err != nil {
+		t.Fatalf("NewA2ALoader() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"local", "billing"}, loader.ListAgents()); diff != "" {
+		t.Errorf("ListAgents() mismatch (-want +got):\n%s", diff)
+	}
+	remote, err := loader.LoadAgent("billing")
+	if err != nil {
+		t.Fatalf("LoadAgent() error = %v", err)
+	}
+
+	ctx := t.Context()
+	sessions := session.InMemoryService()
+	artifacts := artifact.InMemoryService()
+	r, err := runner.New(runner.Config{AppName: "billing", Agent: remote, SessionService: sessions, ArtifactService: artifacts})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessions.Create(ctx, &session.CreateRequest{AppName: "billing", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range r.Run(ctx, "user", "session", genai.NewContentFromText("report?", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	}
+
+	resp, err := sessions.Get(ctx, &session.GetRequest{AppName: "billing", UserID: "user", SessionID: "session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Session.Events().Len(); got < 2 {
+		t.Errorf("session has %d events, want the user message and the remote response", got)
+	}
+	files, err := artifacts.List(ctx, &artifact.ListRequest{AppName: "billing", UserID: "user", SessionID: "session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"report.txt"}, files.FileNames); diff != "" {
+		t.Errorf("artifacts mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewA2ALoader_Errors(t *testing.T) {
+	local, err := agent.New(agent.Config{Name: "local"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name string
+		cfgs []A2AConfig
+	}{
+		{name: "no name", cfgs: []A2AConfig{{AgentCardSource: "https://example.com"}}},
+		{name: "no card", cfgs: []A2AConfig{{Name: "remote"}}},
+		{name: "duplicate", cfgs: []A2AConfig{{Name: "local", AgentCardSource: "https://example.com"}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewA2ALoader(agent.NewSingleLoader(local), tc.cfgs...); err == nil {
+				t.Errorf("NewA2ALoader() succeeded, want error")
+			}
+		})
+	}
+}
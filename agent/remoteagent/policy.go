@@ -0,0 +1,186 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remoteagent
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"regexp"
+	"sync"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy configures how the failed calls to a remote agent are retried.
+// A call is only retried if it failed before any event was received.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one.
+	// Values lower than 2 disable the retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry. If zero, 200ms is used.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts. If zero, 5s is used.
+	MaxBackoff time.Duration
+	// Multiplier is applied to the delay after each retry. If zero, 2 is used.
+	Multiplier float64
+	// Retryable reports whether a failed call can be retried. If nil,
+	// [IsTransientError] is used.
+	Retryable func(err error) bool
+}
+
+// retries reports whether the call failed with err at the given attempt can
+// be retried.
+func (p *RetryPolicy) retries(attempt int, err error) bool {
+	return p != nil && attempt < p.MaxAttempts && p.retryable(err)
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	if p != nil && p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return IsTransientError(err)
+}
+
+// backoff returns the delay before the attempt following the given one. The
+// delay is randomized between half and all of the exponential backoff.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := float64(cmp.Or(p.InitialBackoff, 200*time.Millisecond))
+	maxDelay := float64(cmp.Or(p.MaxBackoff, 5*time.Second))
+	multiplier := p.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+	for range attempt - 1 {
+		delay *= multiplier
+		if delay >= maxDelay {
+			break
+		}
+	}
+	delay = min(delay, maxDelay)
+	return time.Duration(delay/2 + rand.Float64()*delay/2)
+}
+
+// httpStatusError matches the errors of the JSON-RPC transport reporting an
+// HTTP status which can be retried.
+var httpStatusError = regexp.MustCompile(`unexpected HTTP status: (429|502|503|504)\b`)
+
+// IsTransientError reports whether err is a network or server failure which
+// may succeed if retried.
+func IsTransientError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	if s, ok := status.FromError(err); ok {
+		switch s.Code() {
+		case codes.Unavailable, codes.ResourceExhausted, codes.Aborted, codes.DeadlineExceeded:
+			return true
+		}
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	return httpStatusError.MatchString(err.Error())
+}
+
+// CircuitBreakerConfig configures the circuit breaker of a remote agent. After
+// FailureThreshold consecutive failed calls, the circuit opens and the calls
+// fail immediately for OpenDuration. A single trial call is then let through,
+// its success closes the circuit again.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures opening the
+	// circuit. If zero, 5 is used.
+	FailureThreshold int
+	// OpenDuration is the time the circuit stays open. If zero, 30s is used.
+	OpenDuration time.Duration
+}
+
+// ErrCircuitOpen is reported when a call is rejected by an open circuit.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// circuitBreaker implements the circuit breaker of a remote agent. Only the
+// failures deemed retryable, i.e. caused by the remote agent availability,
+// are counted. A nil circuitBreaker lets all the calls through.
+type circuitBreaker struct {
+	threshold    int
+	openDuration time.Duration
+	now          func() time.Time
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	// probing is set while the trial call of a half-open circuit runs.
+	probing bool
+}
+
+func newCircuitBreaker(cfg *CircuitBreakerConfig) *circuitBreaker {
+	if cfg == nil {
+		return nil
+	}
+	threshold := cfg.FailureThreshold
+	if threshold <= 0 {
+		threshold = 5
+	}
+	return &circuitBreaker{
+		threshold:    threshold,
+		openDuration: cmp.Or(cfg.OpenDuration, 30*time.Second),
+		now:          time.Now,
+	}
+}
+
+// allow returns ErrCircuitOpen if the call must be rejected.
+func (b *circuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.openUntil.IsZero() {
+		return nil
+	}
+	if b.probing || b.now().Before(b.openUntil) {
+		return ErrCircuitOpen
+	}
+	b.probing = true
+	return nil
+}
+
+// record updates the circuit with the outcome of a call.
+func (b *circuitBreaker) record(failed bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !failed {
+		b.failures, b.openUntil, b.probing = 0, time.Time{}, false
+		return
+	}
+	b.failures++
+	if b.probing || b.failures >= b.threshold {
+		b.failures, b.openUntil, b.probing = 0, b.now().Add(b.openDuration), false
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remoteagent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"google.golang.org/adk/server/adka2a"
+	"google.golang.org/adk/session"
+)
+
+func TestIsTransientError(t *testing.T) {
+	testCases := []struct {
+		err  error
+		want bool
+	}{
+		{err: status.Error(codes.Unavailable, "unavailable"), want: true},
+		{err: status.Error(codes.ResourceExhausted, "quota"), want: true},
+		{err: status.Error(codes.InvalidArgument, "invalid"), want: false},
+		{err: fmt.Errorf("failed to send HTTP request: %w", syscall.ECONNREFUSED), want: true},
+		{err: fmt.Errorf("unexpected HTTP status: 503 Service Unavailable"), want: true},
+		{err: fmt.Errorf("unexpected HTTP status: 500 Internal Server Error"), want: false},
+		{err: a2a.ErrInvalidParams, want: false},
+		{err: fmt.Errorf("call: %w", context.Canceled), want: false},
+		{err: nil, want: false},
+	}
+	for _, tc := range testCases {
+		if got := IsTransientError(tc.err); got != tc.want {
+			t.Errorf("IsTransientError(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 3}
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 300 * time.Millisecond, 3: 900 * time.Millisecond, 4: time.Second} {
+		got := p.backoff(attempt)
+		if got < want/2 || got > want {
+			t.Errorf("backoff(%d) = %v, want in [%v, %v]", attempt, got, want/2, want)
+		}
+	}
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	b := newCircuitBreaker(&CircuitBreakerConfig{FailureThreshold: 2, OpenDuration: time.Minute})
+	b.now = func() time.Time { return now }
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		failed  bool
+		wantErr error
+	}{
+		{name: "closed", failed: true},
+		{name: "below threshold", failed: true},
+		{name: "open", wantErr: ErrCircuitOpen},
+		{name: "trial after open duration", advance: time.Minute, failed: true},
+		{name: "reopened by failed trial", wantErr: ErrCircuitOpen},
+		{name: "second trial", advance: time.Minute, failed: false},
+		{name: "closed by successful trial", failed: false},
+	}
+	for _, step := range steps {
+		now = now.Add(step.advance)
+		err := b.allow()
+		if !errors.Is(err, step.wantErr) {
+			t.Fatalf("%s: allow() error = %v, want %v", step.name, err, step.wantErr)
+		}
+		if err == nil {
+			b.record(step.failed)
+		}
+	}
+}
+
+// newFlakyA2AServer returns a JSON-RPC A2A server failing the first failures
+// calls with 503 and the number of received calls.
+func newFlakyA2AServer(t *testing.T, failures int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	remoteEvents := []a2a.Event{a2a.NewMessage(a2a.MessageRoleAgent, a2a.TextPart{Text: "Hello!"})}
+	handler := a2asrv.NewJSONRPCHandler(a2asrv.NewHandler(newA2AEventReplay(t, remoteEvents)))
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func newJSONRPCCard(url string) *a2a.AgentCard {
+	return &a2a.AgentCard{PreferredTransport: a2a.TransportProtocolJSONRPC, URL: url, Capabilities: a2a.AgentCapabilities{Streaming: true}}
+}
+
+func TestRemoteAgent_Retry(t *testing.T) {
+	server, calls := newFlakyA2AServer(t, 2)
+	remoteAgent, err := NewA2A(A2AConfig{
+		Name:      "a2a",
+		AgentCard: newJSONRPCCard(server.URL),
+		Retry:     &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("remoteagent.NewA2A() error = %v", err)
+	}
+
+	gotEvents, err := runAndCollect(newInvocationContext(t, []*session.Event{newUserHello()}), remoteAgent)
+	if err != nil {
+		t.Fatalf("agent.Run() error = %v", err)
+	}
+	if len(gotEvents) != 1 || gotEvents[0].ErrorMessage != "" {
+		t.Fatalf("agent.Run() events = %+v, want a single successful event", gotEvents)
+	}
+	if got := gotEvents[0].CustomMetadata[adka2a.ToADKMetaKey("attempt")]; got != 3 {
+		t.Errorf("event attempt metadata = %v, want 3", got)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("server calls = %d, want 3", got)
+	}
+}
+
+func TestRemoteAgent_RetriesExhausted(t *testing.T) {
+	server, calls := newFlakyA2AServer(t, 10)
+	remoteAgent, err := NewA2A(A2AConfig{
+		Name:      "a2a",
+		AgentCard: newJSONRPCCard(server.URL),
+		Retry:     &RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("remoteagent.NewA2A() error = %v", err)
+	}
+
+	gotEvents, err := runAndCollect(newInvocationContext(t, []*session.Event{newUserHello()}), remoteAgent)
+	if err != nil {
+		t.Fatalf("agent.Run() error = %v", err)
+	}
+	if len(gotEvents) != 1 || !strings.Contains(gotEvents[0].ErrorMessage, "503") {
+		t.Fatalf("agent.Run() events = %+v, want a single 503 error event", gotEvents)
+	}
+	if got := gotEvents[0].CustomMetadata[adka2a.ToADKMetaKey("attempt")]; got != 2 {
+		t.Errorf("event attempt metadata = %v, want 2", got)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("server calls = %d, want 2", got)
+	}
+}
+
+func TestRemoteAgent_CircuitBreaker(t *testing.T) {
+	server, calls := newFlakyA2AServer(t, 10)
+	remoteAgent, err := NewA2A(A2AConfig{
+		Name:           "a2a",
+		AgentCard:      newJSONRPCCard(server.URL),
+		CircuitBreaker: &CircuitBreakerConfig{FailureThreshold: 2, OpenDuration: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("remoteagent.NewA2A() error = %v", err)
+	}
+
+	var lastEvents []*session.Event
+	for range 3 {
+		lastEvents, err = runAndCollect(newInvocationContext(t, []*session.Event{newUserHello()}), remoteAgent)
+		if err != nil {
+			t.Fatalf("agent.Run() error = %v", err)
+		}
+	}
+	if len(lastEvents) != 1 || !strings.Contains(lastEvents[0].ErrorMessage, ErrCircuitOpen.Error()) {
+		t.Fatalf("agent.Run() events = %+v, want a single circuit open error event", lastEvents)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("server calls = %d, want 2", got)
+	}
+}
+
+func TestRemoteAgent_Timeout(t *testing.T) {
+	block := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-block:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(block)
+
+	remoteAgent, err := NewA2A(A2AConfig{Name: "a2a", AgentCard: newJSONRPCCard(server.URL), Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("remoteagent.NewA2A() error = %v", err)
+	}
+	gotEvents, err := runAndCollect(newInvocationContext(t, []*session.Event{newUserHello()}), remoteAgent)
+	if err != nil {
+		t.Fatalf("agent.Run() error = %v", err)
+	}
+	if len(gotEvents) != 1 || !strings.Contains(gotEvents[0].ErrorMessage, context.DeadlineExceeded.Error()) {
+		t.Fatalf("agent.Run() events = %+v, want a single deadline exceeded error event", gotEvents)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remoteagent
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2aclient"
+
+	"google.golang.org/adk/internal/logging"
+)
+
+// defaultTaskPollInterval is used when A2AConfig.TaskPollInterval is not set.
+const defaultTaskPollInterval = time.Second
+
+// sendMessage is used with agents which do not support streaming. It sends
+// the message using a unary call and, if the agent responds with a task which
+// is still in progress, polls the task until it is done or requires an input.
+// Only the final message or task is yielded.
+func sendMessage(ctx context.Context, client *a2aclient.Client, req *a2a.MessageSendParams, pollInterval time.Duration) iter.Seq2[a2a.Event, error] {
+	return func(yield func(a2a.Event, error) bool) {
+		result, err := client.SendMessage(ctx, req)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		task, ok := result.(*a2a.Task)
+		if !ok {
+			yield(result, nil)
+			return
+		}
+
+		if pollInterval <= 0 {
+			pollInterval = defaultTaskPollInterval
+		}
+		for !taskSettled(task) {
+			timer := time.NewTimer(pollInterval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				yield(nil, fmt.Errorf("task %s polling interrupted: %w", task.ID, context.Cause(ctx)))
+				return
+			case <-timer.C:
+			}
+
+			polled, err := client.GetTask(ctx, &a2a.TaskQueryParams{ID: task.ID})
+			if err != nil {
+				if IsTransientError(err) {
+					logging.FromContext(ctx).Warn("failed to poll A2A task", "task_id", task.ID, "error", err)
+					continue
+				}
+				yield(nil, fmt.Errorf("failed to poll task %s: %w", task.ID, err))
+				return
+			}
+			task = polled
+		}
+		yield(task, nil)
+	}
+}
+
+// taskSettled reports whether the task is in a state which will not change
+// without a new message from the client.
+func taskSettled(task *a2a.Task) bool {
+	state := task.Status.State
+	return state.Terminal() || state == a2a.TaskStateInputRequired || state == a2a.TaskStateAuthRequired
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remoteagent
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/a2aproject/a2a-go/a2asrv/eventqueue"
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/session"
+)
+
+// newUnaryA2AServer starts a JSON-RPC A2A server and returns a card which does
+// not advertise streaming, along with the number of GetTask calls received.
+func newUnaryA2AServer(t *testing.T, executor a2asrv.AgentExecutor) (*a2a.AgentCard, *atomic.Int32) {
+	t.Helper()
+	var getTaskCalls atomic.Int32
+	handler := &countingHandler{RequestHandler: a2asrv.NewHandler(executor), getTaskCalls: &getTaskCalls}
+	server := httptest.NewServer(a2asrv.NewJSONRPCHandler(handler))
+	t.Cleanup(server.Close)
+	card := &a2a.AgentCard{PreferredTransport: a2a.TransportProtocolJSONRPC, URL: server.URL}
+	return card, &getTaskCalls
+}
+
+type countingHandler struct {
+	a2asrv.RequestHandler
+	getTaskCalls *atomic.Int32
+}
+
+func (h *countingHandler) OnGetTask(ctx context.Context, query *a2a.TaskQueryParams) (*a2a.Task, error) {
+	h.getTaskCalls.Add(1)
+	return h.RequestHandler.OnGetTask(ctx, query)
+}
+
+func eventTexts(events []*session.Event) []string {
+	var texts []string
+	for _, event := range events {
+		if event.Content == nil {
+			continue
+		}
+		for _, part := range event.Content.Parts {
+			texts = append(texts, part.Text)
+		}
+	}
+	return texts
+}
+
+func TestRemoteAgent_NonStreamingMessage(t *testing.T) {
+	remoteEvents := []a2a.Event{a2a.NewMessage(a2a.MessageRoleAgent, a2a.TextPart{Text: "Hello!"})}
+	card, getTaskCalls := newUnaryA2AServer(t, newA2AEventReplay(t, remoteEvents))
+	remoteAgent, err := NewA2A(A2AConfig{Name: "a2a", AgentCard: card})
+	if err != nil {
+		t.Fatalf("remoteagent.NewA2A() error = %v", err)
+	}
+
+	gotEvents, err := runAndCollect(newInvocationContext(t, []*session.Event{newUserHello()}), remoteAgent)
+	if err != nil {
+		t.Fatalf("agent.Run() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"Hello!"}, eventTexts(gotEvents)); diff != "" {
+		t.Errorf("agent.Run() texts mismatch (-want +got):\n%s", diff)
+	}
+	if got := getTaskCalls.Load(); got != 0 {
+		t.Errorf("GetTask calls = %d, want 0", got)
+	}
+}
+
+func TestRemoteAgent_NonStreamingTaskPolling(t *testing.T) {
+	executor := &mockA2AExecutor{
+		executeFn: func(ctx context.Context, reqCtx *a2asrv.RequestContext, queue eventqueue.Queue) error {
+			task := &a2a.Task{ID: reqCtx.TaskID, ContextID: reqCtx.ContextID, Status: a2a.TaskStatus{State: a2a.TaskStateWorking}}
+			if err := queue.Write(ctx, task); err != nil {
+				return err
+			}
+			time.Sleep(50 * time.Millisecond)
+			if err := queue.Write(ctx, a2a.NewArtifactEvent(reqCtx, a2a.TextPart{Text: "result"})); err != nil {
+				return err
+			}
+			msg := a2a.NewMessageForTask(a2a.MessageRoleAgent, reqCtx, a2a.TextPart{Text: "done"})
+			final := a2a.NewStatusUpdateEvent(reqCtx, a2a.TaskStateCompleted, msg)
+			final.Final = true
+			return queue.Write(ctx, final)
+		},
+	}
+	card, getTaskCalls := newUnaryA2AServer(t, executor)
+	remoteAgent, err := NewA2A(A2AConfig{
+		Name:              "a2a",
+		AgentCard:         card,
+		MessageSendConfig: &a2a.MessageSendConfig{Blocking: new(bool)},
+		TaskPollInterval:  10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("remoteagent.NewA2A() error = %v", err)
+	}
+
+	gotEvents, err := runAndCollect(newInvocationContext(t, []*session.Event{newUserHello()}), remoteAgent)
+	if err != nil {
+		t.Fatalf("agent.Run() error = %v", err)
+	}
+	if len(gotEvents) != 1 {
+		t.Fatalf("agent.Run() returned %d events, want 1", len(gotEvents))
+	}
+	if gotEvents[0].Partial {
+		t.Errorf("event.Partial = true, want false for a completed task")
+	}
+	if diff := cmp.Diff([]string{"result", "done"}, eventTexts(gotEvents)); diff != "" {
+		t.Errorf("agent.Run() texts mismatch (-want +got):\n%s", diff)
+	}
+	if got := getTaskCalls.Load(); got == 0 {
+		t.Errorf("GetTask calls = 0, want the task to be polled")
+	}
+}
+
+func TestTaskSettled(t *testing.T) {
+	for state, want := range map[a2a.TaskState]bool{
+		a2a.TaskStateSubmitted:     false,
+		a2a.TaskStateWorking:       false,
+		a2a.TaskStateInputRequired: true,
+		a2a.TaskStateAuthRequired:  true,
+		a2a.TaskStateCompleted:     true,
+		a2a.TaskStateFailed:        true,
+		a2a.TaskStateCanceled:      true,
+	} {
+		if got := taskSettled(&a2a.Task{Status: a2a.TaskStatus{State: state}}); got != want {
+			t.Errorf("taskSettled(%s) = %v, want %v", state, got, want)
+		}
+	}
+}
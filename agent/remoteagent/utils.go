@@ -22,6 +22,7 @@ import (
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/logging"
 	"google.golang.org/adk/server/adka2a"
 	"google.golang.org/adk/session"
 )
@@ -91,23 +92,22 @@ func getFunctionResponseCallID(event *session.Event) (string, bool) {
 // any of the previous one. In this case a new contextID will be generated on the remote server.
 func toMissingRemoteSessionParts(ctx agent.InvocationContext, events session.Events) ([]a2a.Part, string) {
 	partCount, contextID := 0, ""
-	// only events after this index are not in the remote session
-	lastRemoteResponseIndex := -1
-	for i := events.Len() - 1; i >= 0; i-- {
-		event := events.At(i)
-		if event.LLMResponse.Content != nil {
-			partCount += len(event.Content.Parts)
-		}
+	// only events after the last remote response are not in the remote session
+	var missing []*session.Event
+	for event := range session.ReverseAll(events) {
 		if event.Author == ctx.Agent().Name() {
-			lastRemoteResponseIndex = i
 			_, contextID = adka2a.GetA2ATaskInfo(event)
 			break
 		}
+		if event.LLMResponse.Content != nil {
+			partCount += len(event.Content.Parts)
+		}
+		missing = append(missing, event)
 	}
+	slices.Reverse(missing)
 
 	result := make([]a2a.Part, 0, partCount)
-	for i := lastRemoteResponseIndex + 1; i < events.Len(); i++ {
-		event := events.At(i)
+	for _, event := range missing {
 		if event.Author != "user" && event.Author != ctx.Agent().Name() {
 			event = presentAsUserMessage(ctx, event)
 		}
@@ -116,7 +116,7 @@ func toMissingRemoteSessionParts(ctx agent.InvocationContext, events session.Eve
 		}
 		parts, err := adka2a.ToA2AParts(event.Content.Parts, event.LongRunningToolIDs)
 		if err != nil {
-			// TODO(yarolegovich): log error
+			logging.FromContext(ctx).Warn("failed to convert event parts for the remote agent", "event_id", event.ID, "error", err)
 			continue
 		}
 		result = append(result, parts...)
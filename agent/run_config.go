@@ -14,6 +14,8 @@
 
 package agent
 
+import "google.golang.org/genai"
+
 // StreamingMode defines the streaming mode for agent execution.
 type StreamingMode string
 
@@ -23,6 +25,10 @@ const (
 	// StreamingModeSSE enables server-sent events streaming, one-way, where
 	// LLM response parts are streamed immediately as they are generated.
 	StreamingModeSSE StreamingMode = "sse"
+	// StreamingModeBidi enables bidirectional streaming, where the user input
+	// and the model output are exchanged over a live connection. It is set
+	// by runner.RunLive.
+	StreamingModeBidi StreamingMode = "bidi"
 )
 
 // RunConfig controls runtime behavior of an agent.
@@ -32,4 +38,40 @@ type RunConfig struct {
 	// If true, ADK runner will save each part of the user input that is a blob
 	// (e.g., images, files) as an artifact.
 	SaveInputBlobsAsArtifacts bool
+	// TokenBudget is the maximum number of tokens the model calls of a single
+	// invocation may use. When it is exceeded, the runner ends the invocation
+	// with an event whose ErrorCode is runner.TokenBudgetExceededErrorCode.
+	// Zero means no limit.
+	TokenBudget int64
+	// MaxLLMCalls is the maximum number of model calls in a single
+	// invocation. When it is exceeded, the invocation ends with an event
+	// whose ErrorCode is MaxLLMCallsExceededErrorCode. Zero means no limit.
+	MaxLLMCalls int
+	// MaxToolCalls is the maximum number of tool calls in a single
+	// invocation. When the tool calls requested by a model response would
+	// exceed it, none of them is run and the invocation ends with an event
+	// whose ErrorCode is MaxToolCallsExceededErrorCode. Zero means no limit.
+	MaxToolCalls int
+
+	// The following settings only apply to bidi streaming, see
+	// runner.RunLive.
+
+	// ResponseModalities are the modalities of the model output, e.g.
+	// genai.ModalityAudio. If empty, the model default is used.
+	ResponseModalities []genai.Modality
+	// SpeechConfig configures the speech synthesis of the audio output.
+	SpeechConfig *genai.SpeechConfig
+	// InputAudioTranscription enables the transcription of the user audio.
+	// The transcripts are sent as events authored by the user.
+	InputAudioTranscription *genai.AudioTranscriptionConfig
+	// OutputAudioTranscription enables the transcription of the model audio.
+	// The transcripts are sent along with the audio chunks.
+	OutputAudioTranscription *genai.AudioTranscriptionConfig
 }
+
+// Error codes of the events that end an invocation which exceeded a limit
+// set in RunConfig.
+const (
+	MaxLLMCallsExceededErrorCode  = "MAX_LLM_CALLS_EXCEEDED"
+	MaxToolCallsExceededErrorCode = "MAX_TOOL_CALLS_EXCEEDED"
+)
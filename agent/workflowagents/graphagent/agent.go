@@ -0,0 +1,227 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package graphagent provides a workflow agent that runs its sub-agents as
+// nodes of a graph, choosing the next node with conditional edges.
+package graphagent
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+
+	"google.golang.org/adk/agent"
+	agentinternal "google.golang.org/adk/internal/agent"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/session"
+)
+
+// End is the name of the virtual node that finishes the graph run. Use it as
+// the target of an edge to stop the workflow.
+const End = "__end__"
+
+// DefaultMaxSteps is the number of node runs allowed when Config.MaxSteps is
+// zero.
+const DefaultMaxSteps = 100
+
+// ErrMaxStepsExceeded is returned when the graph runs more nodes than allowed
+// by Config.MaxSteps.
+var ErrMaxStepsExceeded = errors.New("graph agent exceeded the maximum number of steps")
+
+// Condition decides whether an edge is followed.
+//
+// ctx gives access to the session state, events holds the events emitted by
+// the node that has just finished.
+type Condition func(ctx agent.ReadonlyContext, events []*session.Event) bool
+
+// Edge connects two nodes of the graph.
+type Edge struct {
+	// From is the name of the sub-agent the edge starts from.
+	From string
+	// To is the name of the sub-agent the edge leads to, or End.
+	To string
+	// Condition must hold for the edge to be followed. A nil Condition is
+	// always true.
+	Condition Condition
+}
+
+// Config defines the configuration for a GraphAgent.
+type Config struct {
+	// Basic agent setup. SubAgents are the nodes of the graph.
+	AgentConfig agent.Config
+
+	// Start is the name of the first node to run. If empty, the first
+	// sub-agent is used.
+	Start string
+	// Edges define the transitions between nodes. After a node finishes,
+	// its outgoing edges are evaluated in the order they are listed and the
+	// first one whose condition holds is followed. If no edge matches, the
+	// graph run ends.
+	Edges []Edge
+	// MaxSteps limits the total number of node runs in one invocation,
+	// protecting against runaway cycles. If zero, DefaultMaxSteps is used.
+	MaxSteps uint
+}
+
+// New creates a GraphAgent.
+//
+// GraphAgent runs its sub-agents one at a time, starting with Config.Start.
+// After each sub-agent completes, the edges leaving it decide which
+// sub-agent runs next, based on the session state and the events it
+// produced. The run stops when the End node is reached, no edge matches,
+// a sub-agent escalates, or MaxSteps is exceeded.
+//
+// Use the GraphAgent for branch-and-merge workflows that cannot be expressed
+// with sequential, parallel and loop agents.
+func New(cfg Config) (agent.Agent, error) {
+	if cfg.AgentConfig.Run != nil {
+		return nil, fmt.Errorf("GraphAgent doesn't allow custom Run implementations")
+	}
+
+	g, err := newGraph(cfg)
+	if err != nil {
+		return nil, err
+	}
+	cfg.AgentConfig.Run = g.Run
+
+	graphAgent, err := agent.New(cfg.AgentConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create base agent: %w", err)
+	}
+
+	internalAgent, ok := graphAgent.(agentinternal.Agent)
+	if !ok {
+		return nil, fmt.Errorf("internal error: failed to convert to internal agent")
+	}
+	state := agentinternal.Reveal(internalAgent)
+	state.AgentType = agentinternal.TypeGraphAgent
+	state.Config = cfg
+
+	return graphAgent, nil
+}
+
+type graph struct {
+	start    string
+	nodes    map[string]agent.Agent
+	edges    map[string][]Edge
+	maxSteps uint
+}
+
+func newGraph(cfg Config) (*graph, error) {
+	g := &graph{
+		start:    cfg.Start,
+		nodes:    make(map[string]agent.Agent),
+		edges:    make(map[string][]Edge),
+		maxSteps: cfg.MaxSteps,
+	}
+	if g.maxSteps == 0 {
+		g.maxSteps = DefaultMaxSteps
+	}
+	for _, a := range cfg.AgentConfig.SubAgents {
+		if a == nil {
+			continue
+		}
+		g.nodes[a.Name()] = a
+	}
+	if g.start == "" && len(cfg.AgentConfig.SubAgents) > 0 && cfg.AgentConfig.SubAgents[0] != nil {
+		g.start = cfg.AgentConfig.SubAgents[0].Name()
+	}
+	if _, ok := g.nodes[g.start]; g.start != "" && !ok {
+		return nil, fmt.Errorf("start node %q is not a sub-agent", g.start)
+	}
+	for _, e := range cfg.Edges {
+		if _, ok := g.nodes[e.From]; !ok {
+			return nil, fmt.Errorf("edge source %q is not a sub-agent", e.From)
+		}
+		if _, ok := g.nodes[e.To]; !ok && e.To != End {
+			return nil, fmt.Errorf("edge target %q is not a sub-agent", e.To)
+		}
+		g.edges[e.From] = append(g.edges[e.From], e)
+	}
+	if err := g.checkUnconditionalCycles(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// checkUnconditionalCycles rejects graphs in which a node would be re-entered
+// through edges that are always followed, since such a run can only end by
+// hitting MaxSteps.
+func (g *graph) checkUnconditionalCycles() error {
+	for name := range g.nodes {
+		visited := map[string]bool{}
+		for cur := name; cur != End; {
+			if visited[cur] {
+				return fmt.Errorf("graph has an unconditional cycle through %q", cur)
+			}
+			visited[cur] = true
+			edges := g.edges[cur]
+			if len(edges) == 0 || edges[0].Condition != nil {
+				break
+			}
+			cur = edges[0].To
+		}
+	}
+	return nil
+}
+
+func (g *graph) Run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		current := g.start
+		for step := uint(0); current != "" && current != End; step++ {
+			if step >= g.maxSteps {
+				yield(nil, fmt.Errorf("%w: %d", ErrMaxStepsExceeded, g.maxSteps))
+				return
+			}
+			node, ok := g.nodes[current]
+			if !ok {
+				yield(nil, fmt.Errorf("unknown graph node %q", current))
+				return
+			}
+
+			var events []*session.Event
+			escalate := false
+			for event, err := range node.Run(ctx) {
+				if !yield(event, err) {
+					return
+				}
+				if err != nil {
+					return
+				}
+				if event == nil {
+					continue
+				}
+				events = append(events, event)
+				if event.Actions.Escalate {
+					escalate = true
+				}
+			}
+			if escalate || ctx.Ended() {
+				return
+			}
+			current = g.next(ctx, current, events)
+		}
+	}
+}
+
+// next returns the node to run after from, or End.
+func (g *graph) next(ctx agent.InvocationContext, from string, events []*session.Event) string {
+	readonlyCtx := icontext.NewReadonlyContext(ctx)
+	for _, e := range g.edges[from] {
+		if e.Condition == nil || e.Condition(readonlyCtx, events) {
+			return e.To
+		}
+	}
+	return End
+}
@@ -0,0 +1,202 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphagent_test
+
+import (
+	"errors"
+	"iter"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/workflowagents/graphagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+)
+
+// newNode creates an agent that replies with its name and applies stateDelta.
+func newNode(t *testing.T, name string, stateDelta map[string]any) agent.Agent {
+	t.Helper()
+	a, err := agent.New(agent.Config{
+		Name: name,
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				ev := session.NewEvent(ctx.InvocationID())
+				ev.Author = name
+				ev.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText(name, genai.RoleModel)}
+				for k, v := range stateDelta {
+					ev.Actions.StateDelta[k] = v
+				}
+				yield(ev, nil)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func stateEquals(key string, want any) graphagent.Condition {
+	return func(ctx agent.ReadonlyContext, events []*session.Event) bool {
+		got, err := ctx.ReadonlyState().Get(key)
+		return err == nil && got == want
+	}
+}
+
+func run(t *testing.T, a agent.Agent) ([]string, error) {
+	t.Helper()
+	ctx := t.Context()
+	sessionService := session.InMemoryService()
+	r, err := runner.New(runner.Config{AppName: "app", Agent: a, SessionService: sessionService})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	var authors []string
+	for ev, err := range r.Run(ctx, "user", "session", genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			return authors, err
+		}
+		authors = append(authors, ev.Author)
+	}
+	return authors, nil
+}
+
+func TestGraphAgent_Branches(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		route string
+		want  []string
+	}{
+		{name: "branch a", route: "a", want: []string{"classifier", "a", "merge"}},
+		{name: "branch b", route: "b", want: []string{"classifier", "b", "merge"}},
+		{name: "no matching edge", route: "c", want: []string{"classifier"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a, err := graphagent.New(graphagent.Config{
+				AgentConfig: agent.Config{
+					Name: "graph",
+					SubAgents: []agent.Agent{
+						newNode(t, "classifier", map[string]any{"route": tc.route}),
+						newNode(t, "a", nil),
+						newNode(t, "b", nil),
+						newNode(t, "merge", nil),
+					},
+				},
+				Edges: []graphagent.Edge{
+					{From: "classifier", To: "a", Condition: stateEquals("route", "a")},
+					{From: "classifier", To: "b", Condition: stateEquals("route", "b")},
+					{From: "a", To: "merge"},
+					{From: "b", To: "merge"},
+					{From: "merge", To: graphagent.End},
+				},
+			})
+			if err != nil {
+				t.Fatalf("New() failed: %v", err)
+			}
+			got, err := run(t, a)
+			if err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("authors mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGraphAgent_ConditionSeesEvents(t *testing.T) {
+	a, err := graphagent.New(graphagent.Config{
+		AgentConfig: agent.Config{
+			Name:      "graph",
+			SubAgents: []agent.Agent{newNode(t, "first", nil), newNode(t, "second", nil)},
+		},
+		Start: "first",
+		Edges: []graphagent.Edge{
+			{From: "first", To: "second", Condition: func(ctx agent.ReadonlyContext, events []*session.Event) bool {
+				return len(events) == 1 && events[0].Content.Parts[0].Text == "first"
+			}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	got, err := run(t, a)
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"first", "second"}, got); diff != "" {
+		t.Errorf("authors mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGraphAgent_MaxSteps(t *testing.T) {
+	always := func(agent.ReadonlyContext, []*session.Event) bool { return true }
+	a, err := graphagent.New(graphagent.Config{
+		AgentConfig: agent.Config{
+			Name:      "graph",
+			SubAgents: []agent.Agent{newNode(t, "ping", nil), newNode(t, "pong", nil)},
+		},
+		Edges: []graphagent.Edge{
+			{From: "ping", To: "pong", Condition: always},
+			{From: "pong", To: "ping", Condition: always},
+		},
+		MaxSteps: 3,
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	got, err := run(t, a)
+	if !errors.Is(err, graphagent.ErrMaxStepsExceeded) {
+		t.Errorf("run error = %v, want %v", err, graphagent.ErrMaxStepsExceeded)
+	}
+	if diff := cmp.Diff([]string{"ping", "pong", "ping"}, got); diff != "" {
+		t.Errorf("authors mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		start string
+		edges []graphagent.Edge
+	}{
+		{name: "unknown start", start: "missing"},
+		{name: "unknown source", edges: []graphagent.Edge{{From: "missing", To: "a"}}},
+		{name: "unknown target", edges: []graphagent.Edge{{From: "a", To: "missing"}}},
+		{name: "unconditional cycle", edges: []graphagent.Edge{{From: "a", To: "b"}, {From: "b", To: "a"}}},
+		{name: "unconditional self loop", edges: []graphagent.Edge{{From: "a", To: "a"}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := graphagent.New(graphagent.Config{
+				AgentConfig: agent.Config{
+					Name:      "graph",
+					SubAgents: []agent.Agent{newNode(t, "a", nil), newNode(t, "b", nil)},
+				},
+				Start: tc.start,
+				Edges: tc.edges,
+			})
+			if err == nil {
+				t.Errorf("New() succeeded, want error")
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 
 	"google.golang.org/adk/agent"
 	agentinternal "google.golang.org/adk/internal/agent"
+	icontext "google.golang.org/adk/internal/context"
 	"google.golang.org/adk/session"
 )
 
@@ -33,8 +34,18 @@ type Config struct {
 	// If MaxIterations == 0, then LoopAgent runs indefinitely or until any
 	// sub-agent escalates.
 	MaxIterations uint
+
+	// ExitCondition, if set, is called for every event emitted by the
+	// sub-agents. If it returns true, the loop stops after that event, as if
+	// the sub-agent had escalated.
+	ExitCondition ExitCondition
 }
 
+// ExitCondition decides whether the loop should stop after the given event.
+// ctx gives access to the session state, which already includes the state
+// delta of the event.
+type ExitCondition func(ctx agent.ReadonlyContext, event *session.Event) bool
+
 // New creates a LoopAgent.
 //
 // LoopAgent repeatedly runs its sub-agents in sequence for a specified number
@@ -42,6 +53,10 @@ type Config struct {
 //
 // Use the LoopAgent when your workflow involves repetition or iterative
 // refinement, such as like revising code.
+//
+// The loop terminates early when a sub-agent escalates by setting
+// EventActions.Escalate (e.g. with exitlooptool), or when Config.ExitCondition
+// returns true.
 func New(cfg Config) (agent.Agent, error) {
 	if cfg.AgentConfig.Run != nil {
 		return nil, fmt.Errorf("LoopAgent doesn't allow custom Run implementations")
@@ -49,6 +64,7 @@ func New(cfg Config) (agent.Agent, error) {
 
 	loopAgentImpl := &loopAgent{
 		maxIterations: cfg.MaxIterations,
+		exitCondition: cfg.ExitCondition,
 	}
 	cfg.AgentConfig.Run = loopAgentImpl.Run
 
@@ -70,6 +86,7 @@ func New(cfg Config) (agent.Agent, error) {
 
 type loopAgent struct {
 	maxIterations uint
+	exitCondition ExitCondition
 }
 
 func (a *loopAgent) Run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
@@ -85,9 +102,15 @@ func (a *loopAgent) Run(ctx agent.InvocationContext) iter.Seq2[*session.Event, e
 						return
 					}
 
+					if event == nil {
+						continue
+					}
 					if event.Actions.Escalate {
 						shouldExit = true
 					}
+					if a.exitCondition != nil && a.exitCondition(icontext.NewReadonlyContext(ctx), event) {
+						shouldExit = true
+					}
 				}
 				if shouldExit {
 					return
@@ -267,6 +267,81 @@ func newCustomAgent(t *testing.T, id int) agent.Agent {
 	return a
 }
 
+func TestLoopAgent_ExitCondition(t *testing.T) {
+	ctx := t.Context()
+
+	counter := must(agent.New(agent.Config{
+		Name: "counter",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				count := 0
+				if v, err := ctx.Session().State().Get("count"); err == nil {
+					count = v.(int)
+				}
+				ev := session.NewEvent(ctx.InvocationID())
+				ev.LLMResponse = model.LLMResponse{
+					Content: genai.NewContentFromText(fmt.Sprintf("count %d", count+1), genai.RoleModel),
+				}
+				ev.Actions.StateDelta["count"] = count + 1
+				yield(ev, nil)
+			}
+		},
+	}))
+	other := newCustomAgent(t, 1)
+
+	loopAgent, err := loopagent.New(loopagent.Config{
+		MaxIterations: 10,
+		AgentConfig: agent.Config{
+			Name:      "test_agent",
+			SubAgents: []agent.Agent{counter, other},
+		},
+		ExitCondition: func(ctx agent.ReadonlyContext, event *session.Event) bool {
+			count, err := ctx.ReadonlyState().Get("count")
+			return err == nil && count.(int) >= 3 && event.Author == "counter"
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sessionService := session.InMemoryService()
+	agentRunner, err := runner.New(runner.Config{
+		AppName:        "test_app",
+		Agent:          loopAgent,
+		SessionService: sessionService,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{
+		AppName:   "test_app",
+		UserID:    "user_id",
+		SessionID: "session_id",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for event, err := range agentRunner.Run(ctx, "user_id", "session_id", genai.NewContentFromText("user input", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("got unexpected error: %v", err)
+		}
+		got = append(got, event.Content.Parts[0].Text)
+	}
+
+	want := []string{"count 1", "hello 1", "count 2", "hello 1", "count 3"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("events mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func must[T agent.Agent](a T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
 // TODO: create test util allowing to create custom agents, agent trees for
 type customAgent struct {
 	id          int
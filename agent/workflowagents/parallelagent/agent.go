@@ -16,8 +16,12 @@
 package parallelagent
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"iter"
+	"strings"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
@@ -31,6 +35,63 @@ import (
 type Config struct {
 	// Basic agent setup.
 	AgentConfig agent.Config
+
+	// MaxConcurrency limits the number of sub-agents running at the same
+	// time. If zero, all sub-agents run concurrently.
+	MaxConcurrency int
+	// BranchTimeout limits the run time of each sub-agent. A sub-agent that
+	// doesn't finish in time is cancelled and reported with an event whose
+	// ErrorCode is BranchTimeoutErrorCode, while the other sub-agents keep
+	// running. If zero, sub-agents have no timeout.
+	BranchTimeout time.Duration
+
+	// Merge, if set, is called once all sub-agents have finished successfully.
+	// The event it returns is emitted after the events of the sub-agents and
+	// consolidates their results.
+	Merge MergeFunc
+	// Aggregator, if set, is run once all sub-agents have finished
+	// successfully, e.g. an LLM agent that summarizes the results of the
+	// sub-agents. It must not be one of the sub-agents.
+	//
+	// Merge and Aggregator are mutually exclusive.
+	Aggregator agent.Agent
+
+	// IsolateBranchState makes every sub-agent write to its own state keys.
+	// The keys of the state deltas emitted by a sub-agent are rewritten with
+	// BranchStateKey, so that concurrent sub-agents don't overwrite each
+	// other's state. The merge step can then read them back by sub-agent name.
+	//
+	// Within a branch, the state keys are resolved to the ones of the
+	// sub-agent first, so that it reads back the values it wrote, and to the
+	// shared keys otherwise. Each event of a sub-agent is processed by the
+	// runner before the sub-agent continues.
+	IsolateBranchState bool
+}
+
+// MergeFunc consolidates the results of the sub-agents into a single event.
+// It returns nil if there is nothing to emit.
+type MergeFunc func(ctx agent.InvocationContext, results []*BranchResult) (*session.Event, error)
+
+// BranchResult holds the events emitted by one sub-agent.
+type BranchResult struct {
+	// Agent is the name of the sub-agent.
+	Agent string
+	// Branch of the sub-agent invocation.
+	Branch string
+	// Events emitted by the sub-agent, in order.
+	Events []*session.Event
+}
+
+// BranchStateKey returns the state key under which the value of key written
+// by the named sub-agent is stored when Config.IsolateBranchState is set.
+// State scope prefixes such as "user:" are preserved.
+func BranchStateKey(agentName, key string) string {
+	for _, prefix := range []string{session.KeyPrefixApp, session.KeyPrefixUser, session.KeyPrefixTemp} {
+		if rest, ok := strings.CutPrefix(key, prefix); ok {
+			return prefix + agentName + "_" + rest
+		}
+	}
+	return agentName + "_" + key
 }
 
 // New creates a ParallelAgent.
@@ -45,8 +106,23 @@ func New(cfg Config) (agent.Agent, error) {
 	if cfg.AgentConfig.Run != nil {
 		return nil, fmt.Errorf("ParallelAgent doesn't allow custom Run implementations")
 	}
+	if cfg.Merge != nil && cfg.Aggregator != nil {
+		return nil, errors.New("ParallelAgent doesn't allow both Merge and Aggregator")
+	}
+	for _, sa := range cfg.AgentConfig.SubAgents {
+		if cfg.Aggregator != nil && sa == cfg.Aggregator {
+			return nil, fmt.Errorf("aggregator %q must not be a sub-agent", sa.Name())
+		}
+	}
 
-	cfg.AgentConfig.Run = run
+	a := &parallelAgent{
+		maxConcurrency:     cfg.MaxConcurrency,
+		branchTimeout:      cfg.BranchTimeout,
+		merge:              cfg.Merge,
+		aggregator:         cfg.Aggregator,
+		isolateBranchState: cfg.IsolateBranchState,
+	}
+	cfg.AgentConfig.Run = a.run
 
 	parallelAgent, err := agent.New(cfg.AgentConfig)
 	if err != nil {
@@ -64,7 +140,21 @@ func New(cfg Config) (agent.Agent, error) {
 	return parallelAgent, nil
 }
 
-func run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+// BranchTimeoutErrorCode is the ErrorCode of the event emitted for a
+// sub-agent that exceeded Config.BranchTimeout.
+const BranchTimeoutErrorCode = "BRANCH_TIMEOUT"
+
+var errBranchTimeout = errors.New("branch timeout")
+
+type parallelAgent struct {
+	maxConcurrency     int
+	branchTimeout      time.Duration
+	merge              MergeFunc
+	aggregator         agent.Agent
+	isolateBranchState bool
+}
+
+func (a *parallelAgent) run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
 	curAgent := ctx.Agent()
 
 	var (
@@ -72,74 +162,262 @@ func run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
 		doneChan              = make(chan bool)
 		resultsChan           = make(chan result)
 	)
+	if a.maxConcurrency > 0 {
+		errGroup.SetLimit(a.maxConcurrency)
+	}
 
-	for _, sa := range ctx.Agent().SubAgents() {
-		branch := fmt.Sprintf("%s.%s", curAgent.Name(), sa.Name())
-		if ctx.Branch() != "" {
-			branch = fmt.Sprintf("%s.%s", ctx.Branch(), branch)
-		}
-		subAgent := sa
-		errGroup.Go(func() error {
-			subCtx := icontext.NewInvocationContext(errGroupCtx, icontext.InvocationContextParams{
-				Artifacts:   ctx.Artifacts(),
-				Memory:      ctx.Memory(),
-				Session:     ctx.Session(),
-				Branch:      branch,
-				Agent:       subAgent,
-				UserContent: ctx.UserContent(),
-				RunConfig:   ctx.RunConfig(),
-			})
+	subAgents := ctx.Agent().SubAgents()
 
-			if err := runSubAgent(subCtx, subAgent, resultsChan, doneChan); err != nil {
-				return fmt.Errorf("failed to run sub-agent %q: %w", subAgent.Name(), err)
+	// Sub-agents are started from a separate goroutine, since with a
+	// concurrency limit errGroup.Go blocks until a running sub-agent finishes,
+	// which requires its results to be consumed.
+	go func() {
+		defer close(resultsChan)
+		for _, sa := range subAgents {
+			branch := fmt.Sprintf("%s.%s", curAgent.Name(), sa.Name())
+			if ctx.Branch() != "" {
+				branch = fmt.Sprintf("%s.%s", ctx.Branch(), branch)
 			}
+			subAgent := sa
+			errGroup.Go(func() error {
+				select {
+				case <-doneChan:
+					return nil
+				case <-errGroupCtx.Done():
+					return nil
+				default:
+				}
 
-			return nil
-		})
-	}
+				branchCtx := context.Context(errGroupCtx)
+				if a.branchTimeout > 0 {
+					var cancel context.CancelFunc
+					branchCtx, cancel = context.WithTimeoutCause(errGroupCtx, a.branchTimeout, errBranchTimeout)
+					defer cancel()
+				}
+				sess := ctx.Session()
+				if a.isolateBranchState {
+					sess = &branchSession{Session: sess, agent: subAgent.Name()}
+				}
+				subCtx := icontext.NewInvocationContext(branchCtx, icontext.InvocationContextParams{
+					Artifacts:   ctx.Artifacts(),
+					Memory:      ctx.Memory(),
+					Session:     sess,
+					Branch:      branch,
+					Agent:       subAgent,
+					UserContent: ctx.UserContent(),
+					RunConfig:   ctx.RunConfig(),
+				})
 
-	go func() {
+				if err := a.runSubAgent(subCtx, subAgent, resultsChan, doneChan); err != nil {
+					return fmt.Errorf("failed to run sub-agent %q: %w", subAgent.Name(), err)
+				}
+
+				return nil
+			})
+		}
 		_ = errGroup.Wait() // this error is already sent to the user via iterator
-		close(resultsChan)
 	}()
 
 	return func(yield func(*session.Event, error) bool) {
 		defer close(doneChan)
 
+		aggregate := a.merge != nil || a.aggregator != nil
+		branchResults := make(map[string]*BranchResult)
+		failed := false
 		for res := range resultsChan {
 			if !yield(res.event, res.err) {
-				break
+				return
+			}
+			if res.processed != nil {
+				close(res.processed)
+			}
+			if res.err != nil {
+				failed = true
+			}
+			if aggregate && res.event != nil {
+				br, ok := branchResults[res.agent]
+				if !ok {
+					br = &BranchResult{Agent: res.agent, Branch: res.branch}
+					branchResults[res.agent] = br
+				}
+				br.Events = append(br.Events, res.event)
+			}
+		}
+		if !aggregate || failed || ctx.Err() != nil {
+			return
+		}
+
+		if a.merge != nil {
+			// Report results in the order of the sub-agents, not of completion.
+			results := make([]*BranchResult, 0, len(subAgents))
+			for _, sa := range subAgents {
+				if br, ok := branchResults[sa.Name()]; ok {
+					results = append(results, br)
+				} else {
+					results = append(results, &BranchResult{Agent: sa.Name()})
+				}
+			}
+			event, err := a.merge(ctx, results)
+			if err != nil {
+				yield(nil, fmt.Errorf("failed to merge sub-agent results: %w", err))
+				return
+			}
+			if event != nil {
+				if event.InvocationID == "" {
+					event.InvocationID = ctx.InvocationID()
+				}
+				if event.Branch == "" {
+					event.Branch = ctx.Branch()
+				}
+				yield(event, nil)
+			}
+			return
+		}
+
+		for event, err := range a.aggregator.Run(ctx) {
+			if !yield(event, err) {
+				return
 			}
 		}
 	}
 }
 
-func runSubAgent(ctx agent.InvocationContext, agent agent.Agent, results chan<- result, done <-chan bool) error {
+func (a *parallelAgent) runSubAgent(ctx agent.InvocationContext, agent agent.Agent, results chan<- result, done <-chan bool) error {
 	for event, err := range agent.Run(ctx) {
+		timedOut := false
+		if err != nil && errors.Is(context.Cause(ctx), errBranchTimeout) {
+			event, err, timedOut = a.timeoutEvent(ctx, agent), nil, true
+		}
+		if a.isolateBranchState && event != nil && len(event.Actions.StateDelta) > 0 {
+			scoped := make(map[string]any, len(event.Actions.StateDelta))
+			for k, v := range event.Actions.StateDelta {
+				scoped[BranchStateKey(agent.Name(), k)] = v
+			}
+			event.Actions.StateDelta = scoped
+		}
+		res := result{
+			agent:  agent.Name(),
+			branch: ctx.Branch(),
+			event:  event,
+			err:    err,
+		}
+		// The state delta of the event is applied once the runner processed
+		// it, the sub-agent waits for it to read back its state.
+		if a.isolateBranchState && event != nil && err == nil && !timedOut {
+			res.processed = make(chan struct{})
+		}
 		select {
 		case <-done:
 			return nil
 		case <-ctx.Done():
+			res = result{
+				agent:  agent.Name(),
+				branch: ctx.Branch(),
+				err:    ctx.Err(),
+			}
+			if errors.Is(context.Cause(ctx), errBranchTimeout) {
+				res.event, res.err = a.timeoutEvent(ctx, agent), nil
+			}
 			select {
 			case <-done:
-			case results <- result{
-				err: ctx.Err(),
-			}:
-			}
-			return ctx.Err()
-		case results <- result{
-			event: event,
-			err:   err,
-		}:
+			case results <- res:
+			}
+			return res.err
+		case results <- res:
 			if err != nil {
 				return err
 			}
+			if timedOut {
+				return nil
+			}
+		}
+		if res.processed != nil {
+			select {
+			case <-done:
+				return nil
+			case <-res.processed:
+			}
 		}
 	}
 	return nil
 }
 
+// timeoutEvent returns the event reported in place of a sub-agent that didn't
+// finish within the branch timeout.
+func (a *parallelAgent) timeoutEvent(ctx agent.InvocationContext, agent agent.Agent) *session.Event {
+	event := session.NewEvent(ctx.InvocationID())
+	event.Author = agent.Name()
+	event.Branch = ctx.Branch()
+	event.ErrorCode = BranchTimeoutErrorCode
+	event.ErrorMessage = fmt.Sprintf("branch %q timed out after %v", ctx.Branch(), a.branchTimeout)
+	return event
+}
+
 type result struct {
-	event *session.Event
-	err   error
+	agent  string
+	branch string
+	event  *session.Event
+	err    error
+	// processed, if set, is closed once the event has been yielded.
+	processed chan struct{}
+}
+
+// branchSession is the session of a sub-agent whose state is isolated: its
+// state keys are resolved with BranchStateKey.
+type branchSession struct {
+	session.Session
+	agent string
+}
+
+func (s *branchSession) State() session.State {
+	return &branchState{State: s.Session.State(), agent: s.agent}
+}
+
+type branchState struct {
+	session.State
+	agent string
+}
+
+// Get returns the value written by the sub-agent, or the shared value of key
+// if the sub-agent did not write it.
+func (s *branchState) Get(key string) (any, error) {
+	value, err := s.State.Get(BranchStateKey(s.agent, key))
+	if errors.Is(err, session.ErrStateKeyNotExist) {
+		return s.State.Get(key)
+	}
+	return value, err
+}
+
+func (s *branchState) Set(key string, value any) error {
+	return s.State.Set(BranchStateKey(s.agent, key), value)
+}
+
+// All returns the state as seen by the sub-agent: its own values replace the
+// shared ones.
+func (s *branchState) All() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for key, value := range s.State.All() {
+			if _, err := s.State.Get(BranchStateKey(s.agent, key)); err == nil {
+				// Shadowed by the value of the sub-agent.
+				continue
+			}
+			if own, ok := unbranchStateKey(s.agent, key); ok {
+				key = own
+			}
+			if !yield(key, value) {
+				return
+			}
+		}
+	}
+}
+
+// unbranchStateKey is the inverse of BranchStateKey.
+func unbranchStateKey(agentName, key string) (string, bool) {
+	for _, prefix := range []string{session.KeyPrefixApp, session.KeyPrefixUser, session.KeyPrefixTemp} {
+		if rest, ok := strings.CutPrefix(key, prefix); ok {
+			rest, ok = strings.CutPrefix(rest, agentName+"_")
+			return prefix + rest, ok
+		}
+	}
+	return strings.CutPrefix(key, agentName+"_")
 }
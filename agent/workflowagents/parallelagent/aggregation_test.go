@@ -0,0 +1,250 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parallelagent_test
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/workflowagents/parallelagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+)
+
+func newStateAgent(t *testing.T, name string) agent.Agent {
+	t.Helper()
+	return must(agent.New(agent.Config{
+		Name: name,
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				ev := session.NewEvent(ctx.InvocationID())
+				ev.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText("result of "+name, genai.RoleModel)}
+				ev.Actions.StateDelta["result"] = name
+				ev.Actions.StateDelta["user:seen"] = true
+				yield(ev, nil)
+			}
+		},
+	}))
+}
+
+func runAgent(t *testing.T, a agent.Agent) ([]*session.Event, session.State) {
+	t.Helper()
+	ctx := t.Context()
+	sessionService := session.InMemoryService()
+	r, err := runner.New(runner.Config{AppName: "app", Agent: a, SessionService: sessionService})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	var events []*session.Event
+	for ev, err := range r.Run(ctx, "user", "session", genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("run failed: %v", err)
+		}
+		events = append(events, ev)
+	}
+	resp, err := sessionService.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return events, resp.Session.State()
+}
+
+func TestParallelAgent_Merge(t *testing.T) {
+	a, err := parallelagent.New(parallelagent.Config{
+		AgentConfig: agent.Config{
+			Name:      "parallel",
+			SubAgents: []agent.Agent{newStateAgent(t, "a"), newStateAgent(t, "b"), newStateAgent(t, "c")},
+		},
+		IsolateBranchState: true,
+		Merge: func(ctx agent.InvocationContext, results []*parallelagent.BranchResult) (*session.Event, error) {
+			var parts []string
+			for _, r := range results {
+				parts = append(parts, fmt.Sprintf("%s:%d:%s", r.Agent, len(r.Events), r.Branch))
+			}
+			ev := session.NewEvent(ctx.InvocationID())
+			ev.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText(strings.Join(parts, ","), genai.RoleModel)}
+			return ev, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	events, state := runAgent(t, a)
+
+	if len(events) != 4 {
+		t.Fatalf("got %d events, want 4", len(events))
+	}
+	last := events[len(events)-1]
+	if got, want := last.Author, "parallel"; got != want {
+		t.Errorf("merge event author = %q, want %q", got, want)
+	}
+	if diff := cmp.Diff("a:1:parallel.a,b:1:parallel.b,c:1:parallel.c", last.Content.Parts[0].Text); diff != "" {
+		t.Errorf("merge event mismatch (-want +got):\n%s", diff)
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		for _, key := range []string{parallelagent.BranchStateKey(name, "result"), "user:" + name + "_seen"} {
+			if _, err := state.Get(key); err != nil {
+				t.Errorf("state.Get(%q) failed: %v", key, err)
+			}
+		}
+	}
+	if _, err := state.Get("result"); err == nil {
+		t.Errorf("state.Get(%q) succeeded, want unscoped key to be absent", "result")
+	}
+}
+
+func TestParallelAgent_Aggregator(t *testing.T) {
+	aggregator := must(agent.New(agent.Config{
+		Name: "aggregator",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				var results []string
+				for _, name := range []string{"a", "b"} {
+					v, err := ctx.Session().State().Get(parallelagent.BranchStateKey(name, "result"))
+					if err != nil {
+						yield(nil, err)
+						return
+					}
+					results = append(results, fmt.Sprint(v))
+				}
+				ev := session.NewEvent(ctx.InvocationID())
+				ev.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText(strings.Join(results, "+"), genai.RoleModel)}
+				yield(ev, nil)
+			}
+		},
+	}))
+	a, err := parallelagent.New(parallelagent.Config{
+		AgentConfig: agent.Config{
+			Name:      "parallel",
+			SubAgents: []agent.Agent{newStateAgent(t, "a"), newStateAgent(t, "b")},
+		},
+		IsolateBranchState: true,
+		Aggregator:         aggregator,
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	events, _ := runAgent(t, a)
+
+	if len(events) != 3 {
+		t.Fatalf("got %d events, want 3", len(events))
+	}
+	last := events[len(events)-1]
+	if got, want := last.Author, "aggregator"; got != want {
+		t.Errorf("aggregator event author = %q, want %q", got, want)
+	}
+	if got, want := last.Content.Parts[0].Text, "a+b"; got != want {
+		t.Errorf("aggregator event text = %q, want %q", got, want)
+	}
+}
+
+func TestParallelAgent_IsolatedStateReadAfterWrite(t *testing.T) {
+	// Each sub-agent writes its name and reads it back, both from the state
+	// and from the state listing.
+	newAgent := func(name string) agent.Agent {
+		return must(agent.New(agent.Config{
+			Name: name,
+			Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+				return func(yield func(*session.Event, error) bool) {
+					ev := session.NewEvent(ctx.InvocationID())
+					ev.Actions.StateDelta["result"] = name
+					if !yield(ev, nil) {
+						return
+					}
+					state := ctx.Session().State()
+					got, err := state.Get("result")
+					if err != nil {
+						yield(nil, err)
+						return
+					}
+					listed := map[string]any{}
+					for k, v := range state.All() {
+						listed[k] = v
+					}
+					ev = session.NewEvent(ctx.InvocationID())
+					ev.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText(fmt.Sprintf("%s:%v:%v", name, got, listed["result"]), genai.RoleModel)}
+					yield(ev, nil)
+				}
+			},
+		}))
+	}
+	a, err := parallelagent.New(parallelagent.Config{
+		AgentConfig: agent.Config{
+			Name:      "parallel",
+			SubAgents: []agent.Agent{newAgent("a"), newAgent("b")},
+		},
+		IsolateBranchState: true,
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	events, state := runAgent(t, a)
+
+	var got []string
+	for _, ev := range events {
+		if ev.Content != nil {
+			got = append(got, ev.Content.Parts[0].Text)
+		}
+	}
+	slices.Sort(got)
+	if diff := cmp.Diff([]string{"a:a:a", "b:b:b"}, got); diff != "" {
+		t.Errorf("read values mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := state.Get("result"); err == nil {
+		t.Errorf("state.Get(%q) succeeded, want unscoped key to be absent", "result")
+	}
+}
+
+func TestParallelAgent_MergeAndAggregator(t *testing.T) {
+	_, err := parallelagent.New(parallelagent.Config{
+		AgentConfig: agent.Config{Name: "parallel"},
+		Merge: func(agent.InvocationContext, []*parallelagent.BranchResult) (*session.Event, error) {
+			return nil, nil
+		},
+		Aggregator: newStateAgent(t, "aggregator"),
+	})
+	if err == nil {
+		t.Errorf("New() succeeded, want error")
+	}
+}
+
+func TestBranchStateKey(t *testing.T) {
+	for _, tc := range []struct {
+		key, want string
+	}{
+		{key: "result", want: "agent_result"},
+		{key: "user:pref", want: "user:agent_pref"},
+		{key: "app:config", want: "app:agent_config"},
+		{key: "temp:scratch", want: "temp:agent_scratch"},
+	} {
+		if got := parallelagent.BranchStateKey("agent", tc.key); got != tc.want {
+			t.Errorf("BranchStateKey(%q) = %q, want %q", tc.key, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parallelagent_test
+
+import (
+	"fmt"
+	"iter"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/workflowagents/parallelagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+func TestParallelAgent_MaxConcurrency(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	var subAgents []agent.Agent
+	for i := range 6 {
+		subAgents = append(subAgents, must(agent.New(agent.Config{
+			Name: fmt.Sprintf("sub%d", i),
+			Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+				return func(yield func(*session.Event, error) bool) {
+					n := running.Add(1)
+					defer running.Add(-1)
+					for {
+						m := maxRunning.Load()
+						if n <= m || maxRunning.CompareAndSwap(m, n) {
+							break
+						}
+					}
+					time.Sleep(5 * time.Millisecond)
+					yield(&session.Event{
+						LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("done", genai.RoleModel)},
+					}, nil)
+				}
+			},
+		})))
+	}
+	a, err := parallelagent.New(parallelagent.Config{
+		AgentConfig:    agent.Config{Name: "parallel", SubAgents: subAgents},
+		MaxConcurrency: 2,
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	events, _ := runAgent(t, a)
+
+	if len(events) != len(subAgents) {
+		t.Errorf("got %d events, want %d", len(events), len(subAgents))
+	}
+	if got := maxRunning.Load(); got > 2 {
+		t.Errorf("max concurrently running sub-agents = %d, want <= 2", got)
+	}
+}
+
+func TestParallelAgent_BranchTimeout(t *testing.T) {
+	slow := must(agent.New(agent.Config{
+		Name: "slow",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				select {
+				case <-ctx.Done():
+					yield(nil, ctx.Err())
+				case <-time.After(10 * time.Second):
+					yield(&session.Event{
+						LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("too late", genai.RoleModel)},
+					}, nil)
+				}
+			}
+		},
+	}))
+	a, err := parallelagent.New(parallelagent.Config{
+		AgentConfig:   agent.Config{Name: "parallel", SubAgents: []agent.Agent{slow, newStateAgent(t, "fast")}},
+		BranchTimeout: 50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	events, _ := runAgent(t, a)
+
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	var timeoutEvent *session.Event
+	for _, ev := range events {
+		if ev.Author == "slow" {
+			timeoutEvent = ev
+		}
+	}
+	if timeoutEvent == nil {
+		t.Fatalf("no event from the timed out branch")
+	}
+	if got, want := timeoutEvent.ErrorCode, parallelagent.BranchTimeoutErrorCode; got != want {
+		t.Errorf("ErrorCode = %q, want %q", got, want)
+	}
+	if got, want := timeoutEvent.Branch, "parallel.slow"; got != want {
+		t.Errorf("Branch = %q, want %q", got, want)
+	}
+}
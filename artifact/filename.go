@@ -0,0 +1,202 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxFileNameLength is the maximum length in bytes of an artifact file name,
+// without its "user:" prefix.
+const MaxFileNameLength = 255
+
+// ErrInvalidFileName is returned for artifact file names which are unsafe to
+// use in URLs or as file paths.
+var ErrInvalidFileName = errors.New("invalid artifact file name")
+
+// userScopePrefix is the prefix of the file names of the artifacts shared by
+// all the sessions of a user.
+const userScopePrefix = "user:"
+
+// reservedChars can't be used in file names on Windows, or have a meaning in
+// paths. The slash is allowed as a separator of the segments of a name.
+const reservedChars = `<>:"\|?*`
+
+// windowsDeviceNames are the names Windows reserves for devices, whatever
+// their extension.
+var windowsDeviceNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// ValidateFileName checks that an artifact file name is safe to use in URLs
+// and as a relative file path on any OS. The name, after its optional
+// "user:" prefix, is made of segments separated by slashes, which must not
+// be empty, "." or "..", contain reserved or control characters, end with a
+// dot or a space, or be a Windows device name. The errors wrap
+// ErrInvalidFileName.
+func ValidateFileName(name string) error {
+	if reason := checkFileName(strings.TrimPrefix(name, userScopePrefix)); reason != "" {
+		return fmt.Errorf("%w %q: %s", ErrInvalidFileName, name, reason)
+	}
+	return nil
+}
+
+// checkFileName returns why name is invalid, or "" if it is valid.
+func checkFileName(name string) string {
+	switch {
+	case name == "":
+		return "empty name"
+	case len(name) > MaxFileNameLength:
+		return fmt.Sprintf("longer than %d bytes", MaxFileNameLength)
+	case !utf8.ValidString(name):
+		return "invalid UTF-8"
+	}
+	for seg := range strings.SplitSeq(name, "/") {
+		switch {
+		case seg == "":
+			return "empty path segment"
+		case seg == "." || seg == "..":
+			return "relative path segment"
+		case strings.IndexFunc(seg, isReservedRune) >= 0:
+			return "reserved character"
+		case strings.HasSuffix(seg, ".") || strings.HasSuffix(seg, " "):
+			return "path segment ending with a dot or a space"
+		case isDeviceName(seg):
+			return "reserved device name"
+		}
+	}
+	return ""
+}
+
+func isReservedRune(r rune) bool {
+	return r < 0x20 || r == 0x7f || strings.ContainsRune(reservedChars, r)
+}
+
+// isDeviceName reports whether Windows reserves seg for a device, e.g.
+// "nul" or "COM1.txt".
+func isDeviceName(seg string) bool {
+	base, _, _ := strings.Cut(seg, ".")
+	return windowsDeviceNames[strings.ToUpper(strings.TrimRight(base, " "))]
+}
+
+// SlugFileName turns name into a valid file name, replacing the reserved
+// characters with underscores, dropping the empty and relative path
+// segments, and truncating it to MaxFileNameLength while keeping its
+// extension. Valid names are returned unchanged.
+func SlugFileName(name string) string {
+	if ValidateFileName(name) == nil {
+		return name
+	}
+	prefix := ""
+	if strings.HasPrefix(name, userScopePrefix) {
+		prefix, name = userScopePrefix, strings.TrimPrefix(name, userScopePrefix)
+	}
+	name = strings.ToValidUTF8(name, "_")
+	name = strings.Map(func(r rune) rune {
+		if isReservedRune(r) {
+			return '_'
+		}
+		return r
+	}, name)
+	var segs []string
+	for seg := range strings.SplitSeq(name, "/") {
+		seg = strings.TrimRight(seg, ". ")
+		if seg == "" {
+			continue
+		}
+		if isDeviceName(seg) {
+			seg = "_" + seg
+		}
+		segs = append(segs, seg)
+	}
+	name = strings.Join(segs, "/")
+	if len(name) > MaxFileNameLength {
+		name = truncateFileName(name)
+	}
+	if name == "" {
+		name = "_"
+	}
+	return prefix + name
+}
+
+// truncateFileName shortens name to MaxFileNameLength bytes, keeping its
+// extension if it is short.
+func truncateFileName(name string) string {
+	ext := path.Ext(name)
+	if len(ext) > 16 || strings.Contains(ext, "/") {
+		ext = ""
+	}
+	stem := strings.TrimSuffix(name, ext)
+	n := MaxFileNameLength - len(ext)
+	for n > 0 && !utf8.RuneStart(stem[n]) {
+		n--
+	}
+	return strings.TrimRight(stem[:n], "/. ") + ext
+}
+
+// NewSlugService returns a Service passing the requests to s with their file
+// names turned into valid ones by SlugFileName, for callers which can't
+// control the names, e.g. names derived from user uploads.
+func NewSlugService(s Service) Service {
+	return &slugService{s: s}
+}
+
+type slugService struct {
+	s Service
+}
+
+func (s *slugService) Save(ctx context.Context, req *SaveRequest) (*SaveResponse, error) {
+	r := *req
+	r.FileName = slugRequestName(r.FileName)
+	return s.s.Save(ctx, &r)
+}
+
+func (s *slugService) Load(ctx context.Context, req *LoadRequest) (*LoadResponse, error) {
+	r := *req
+	r.FileName = slugRequestName(r.FileName)
+	return s.s.Load(ctx, &r)
+}
+
+func (s *slugService) Delete(ctx context.Context, req *DeleteRequest) error {
+	r := *req
+	r.FileName = slugRequestName(r.FileName)
+	return s.s.Delete(ctx, &r)
+}
+
+func (s *slugService) List(ctx context.Context, req *ListRequest) (*ListResponse, error) {
+	return s.s.List(ctx, req)
+}
+
+func (s *slugService) Versions(ctx context.Context, req *VersionsRequest) (*VersionsResponse, error) {
+	r := *req
+	r.FileName = slugRequestName(r.FileName)
+	return s.s.Versions(ctx, &r)
+}
+
+// slugRequestName slugs the file name of a request, leaving the missing
+// names to the validation of the request.
+func slugRequestName(name string) string {
+	if name == "" {
+		return ""
+	}
+	return SlugFileName(name)
+}
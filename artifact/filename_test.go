@@ -0,0 +1,148 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifact_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+)
+
+func TestValidateFileName(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		wantErr bool
+	}{
+		{name: "report.txt"},
+		{name: "user:profile.png"},
+		{name: "workspace/src/main.go"},
+		{name: "résumé 2025.pdf"},
+		{name: ".config"},
+		{name: strings.Repeat("a", artifact.MaxFileNameLength)},
+		{name: "user:" + strings.Repeat("a", artifact.MaxFileNameLength)},
+		{name: strings.Repeat("a", artifact.MaxFileNameLength+1), wantErr: true},
+		{name: "user:", wantErr: true},
+		{name: "../secret", wantErr: true},
+		{name: "a/../../secret", wantErr: true},
+		{name: "./file", wantErr: true},
+		{name: "/etc/passwd", wantErr: true},
+		{name: "dir//file", wantErr: true},
+		{name: "dir/", wantErr: true},
+		{name: `..\secret`, wantErr: true},
+		{name: `C:\Windows\win.ini`, wantErr: true},
+		{name: "C:file", wantErr: true},
+		{name: "file.txt:stream", wantErr: true},
+		{name: "user:user:file", wantErr: true},
+		{name: "what?.txt", wantErr: true},
+		{name: `a"b`, wantErr: true},
+		{name: "a<b>", wantErr: true},
+		{name: "a|b", wantErr: true},
+		{name: "a*", wantErr: true},
+		{name: "line\nbreak", wantErr: true},
+		{name: "nul\x00byte", wantErr: true},
+		{name: "file.", wantErr: true},
+		{name: "file ", wantErr: true},
+		{name: "dir./file", wantErr: true},
+		{name: "CON", wantErr: true},
+		{name: "nul.txt", wantErr: true},
+		{name: "dir/Com1.log", wantErr: true},
+		{name: "lpt9", wantErr: true},
+		{name: "console.txt"},
+		{name: "com10"},
+		{name: "bad\xffutf8", wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := artifact.ValidateFileName(tc.name)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ValidateFileName(%q) error = %v, wantErr %v", tc.name, err, tc.wantErr)
+			}
+			if err != nil && !errors.Is(err, artifact.ErrInvalidFileName) {
+				t.Errorf("ValidateFileName(%q) error = %v, want ErrInvalidFileName", tc.name, err)
+			}
+		})
+	}
+}
+
+func TestSlugFileName(t *testing.T) {
+	long := strings.Repeat("é", artifact.MaxFileNameLength)
+	for _, tc := range []struct {
+		name string
+		want string
+	}{
+		{name: "report.txt", want: "report.txt"},
+		{name: "user:profile.png", want: "user:profile.png"},
+		{name: "../../etc/passwd", want: "etc/passwd"},
+		{name: "/abs//dir/./file", want: "abs/dir/file"},
+		{name: `C:\Windows\win.ini`, want: "C__Windows_win.ini"},
+		{name: "user:what?.txt", want: "user:what_.txt"},
+		{name: "line\nbreak", want: "line_break"},
+		{name: "trailing. . ", want: "trailing"},
+		{name: "CON", want: "_CON"},
+		{name: "dir/nul.txt", want: "dir/_nul.txt"},
+		{name: "bad\xffutf8", want: "bad_utf8"},
+		{name: "..", want: "_"},
+		{name: "user:../", want: "user:_"},
+		{name: long + ".txt", want: strings.Repeat("é", (artifact.MaxFileNameLength-4)/2) + ".txt"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := artifact.SlugFileName(tc.name)
+			if got != tc.want {
+				t.Errorf("SlugFileName(%q) = %q, want %q", tc.name, got, tc.want)
+			}
+			if err := artifact.ValidateFileName(got); err != nil {
+				t.Errorf("SlugFileName(%q) is invalid: %v", tc.name, err)
+			}
+		})
+	}
+}
+
+func TestSlugService(t *testing.T) {
+	ctx := t.Context()
+	inner := artifact.InMemoryService()
+	s := artifact.NewSlugService(inner)
+
+	if _, err := s.Save(ctx, &artifact.SaveRequest{
+		AppName: "app", UserID: "user", SessionID: "session", FileName: "../notes?.txt",
+		Part: genai.NewPartFromText("hello"),
+	}); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	list, err := inner.List(ctx, &artifact.ListRequest{AppName: "app", UserID: "user", SessionID: "session"})
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"notes_.txt"}, list.FileNames); diff != "" {
+		t.Errorf("stored file names mismatch (-want +got):\n%s", diff)
+	}
+	loaded, err := s.Load(ctx, &artifact.LoadRequest{AppName: "app", UserID: "user", SessionID: "session", FileName: "../notes?.txt"})
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if diff := cmp.Diff(genai.NewPartFromText("hello"), loaded.Part); diff != "" {
+		t.Errorf("Load() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := inner.Save(ctx, &artifact.SaveRequest{
+		AppName: "app", UserID: "user", SessionID: "session", FileName: "../notes?.txt",
+		Part: genai.NewPartFromText("hello"),
+	}); !errors.Is(err, artifact.ErrInvalidFileName) {
+		t.Errorf("Save() on the inner service error = %v, want ErrInvalidFileName", err)
+	}
+}
@@ -33,6 +33,9 @@ import (
 
 // inMemoryService is an in-memory implementation of the Service.
 // It is primarily for testing and demonstration purposes.
+//
+// It is safe for concurrent use. The parts are copied when saved and loaded,
+// so that the callers cannot change the stored artifacts.
 type inMemoryService struct {
 	mu sync.RWMutex
 	// ordered(appName, userID, sessionID) -> session
@@ -79,7 +82,6 @@ func (ak *artifactKey) Decode(key string) error {
 
 // scan returns an iterator over all key-value pairs
 // in the range begin ≤ key ≤ end.
-// TODO: add a concurrent tests.
 func (s *inMemoryService) scan(lo, hi string) iter.Seq2[artifactKey, *genai.Part] {
 	return func(yield func(key artifactKey, val *genai.Part) bool) {
 		for k, val := range s.artifacts.Scan(lo, hi) {
@@ -145,7 +147,7 @@ func (s *inMemoryService) Save(ctx context.Context, req *SaveRequest) (*SaveResp
 		return nil, fmt.Errorf("request validation failed: %w", err)
 	}
 	appName, userID, sessionID, fileName := req.AppName, req.UserID, req.SessionID, req.FileName
-	artifact := req.Part
+	artifact := clonePart(req.Part)
 	// If file is user scoped, store it under user scope path
 	if fileHasUserNamespace(fileName) {
 		sessionID = userScopedArtifactKey
@@ -211,14 +213,14 @@ func (s *inMemoryService) Load(ctx context.Context, req *LoadRequest) (*LoadResp
 		if !ok {
 			return nil, fmt.Errorf("artifact not found: %w", fs.ErrNotExist)
 		}
-		return &LoadResponse{Part: artifact}, nil
+		return &LoadResponse{Part: clonePart(artifact)}, nil
 	}
 	// pick the latest version
 	_, artifact, ok := s.find(appName, userID, sessionID, fileName)
 	if !ok {
 		return nil, fmt.Errorf("artifact not found: %w", fs.ErrNotExist)
 	}
-	return &LoadResponse{Part: artifact}, nil
+	return &LoadResponse{Part: clonePart(artifact)}, nil
 }
 
 // List implements [artifact.Service]
@@ -286,3 +288,17 @@ func (s *inMemoryService) Versions(ctx context.Context, req *VersionsRequest) (*
 }
 
 var _ Service = (*inMemoryService)(nil)
+
+// clonePart returns a copy of the part, with a copy of its inline data.
+func clonePart(p *genai.Part) *genai.Part {
+	if p == nil {
+		return nil
+	}
+	clone := *p
+	if p.InlineData != nil {
+		blob := *p.InlineData
+		blob.Data = slices.Clone(p.InlineData.Data)
+		clone.InlineData = &blob
+	}
+	return &clone
+}
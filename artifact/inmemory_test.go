@@ -15,8 +15,13 @@
 package artifact_test
 
 import (
+	"slices"
+	"sync"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
 	"google.golang.org/adk/artifact"
 	"google.golang.org/adk/internal/artifact/tests"
 )
@@ -27,3 +32,63 @@ func TestInMemoryArtifactService(t *testing.T) {
 	}
 	tests.TestArtifactService(t, "InMemory", factory)
 }
+
+func TestInMemoryService_ConcurrentSaves(t *testing.T) {
+	ctx := t.Context()
+	s := artifact.InMemoryService()
+	const n = 50
+	versions := make([]int64, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := s.Save(ctx, &artifact.SaveRequest{
+				AppName: "app", UserID: "user", SessionID: "session", FileName: "file.txt", Part: genai.NewPartFromText("data"),
+			})
+			if err != nil {
+				t.Errorf("Save() failed: %v", err)
+				return
+			}
+			versions[i] = resp.Version
+			if _, err := s.Load(ctx, &artifact.LoadRequest{AppName: "app", UserID: "user", SessionID: "session", FileName: "file.txt"}); err != nil {
+				t.Errorf("Load() failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	slices.Sort(versions)
+	want := make([]int64, n)
+	for i := range want {
+		want[i] = int64(i + 1)
+	}
+	if diff := cmp.Diff(want, versions); diff != "" {
+		t.Errorf("saved versions mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestInMemoryService_CopiesParts(t *testing.T) {
+	ctx := t.Context()
+	s := artifact.InMemoryService()
+	part := genai.NewPartFromBytes([]byte("data"), "text/plain")
+	if _, err := s.Save(ctx, &artifact.SaveRequest{AppName: "app", UserID: "user", SessionID: "session", FileName: "file.txt", Part: part}); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+	part.InlineData.Data[0] = 'X'
+
+	load := func() *genai.Part {
+		resp, err := s.Load(ctx, &artifact.LoadRequest{AppName: "app", UserID: "user", SessionID: "session", FileName: "file.txt"})
+		if err != nil {
+			t.Fatalf("Load() failed: %v", err)
+		}
+		return resp.Part
+	}
+	loaded := load()
+	loaded.InlineData.Data[1] = 'Y'
+	loaded.InlineData.MIMEType = "image/png"
+
+	if diff := cmp.Diff(genai.NewPartFromBytes([]byte("data"), "text/plain"), load()); diff != "" {
+		t.Errorf("stored artifact changed (-want +got):\n%s", diff)
+	}
+}
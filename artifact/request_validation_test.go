@@ -104,6 +104,18 @@ func TestSaveRequest_Validate(t *testing.T) {
 			wantErr:    true,
 			wantErrMsg: "invalid save request: Part.InlineData or Part.Text has to be set",
 		},
+		{
+			name: "Path traversal",
+			req: &SaveRequest{
+				AppName:   "MyApp",
+				UserID:    "user-123",
+				SessionID: "sess-abc",
+				FileName:  "../secret.txt",
+				Part:      genai.NewPartFromText("data"),
+			},
+			wantErr:    true,
+			wantErrMsg: `invalid save request: invalid artifact file name "../secret.txt": relative path segment`,
+		},
 		{
 			name:       "Completely empty request",
 			req:        &SaveRequest{},
@@ -138,6 +150,16 @@ func TestLoadRequest_Validate(t *testing.T) {
 			wantErr:    true,
 			wantErrMsg: "invalid load request: missing required fields: AppName",
 		},
+		{
+			name: "Name saved before the validation",
+			req: &LoadRequest{
+				AppName:   "MyApp",
+				UserID:    "user-123",
+				SessionID: "sess-abc",
+				FileName:  "notes: draft?.txt",
+			},
+			wantErr: false,
+		},
 		{
 			name: "Missing multiple fields",
 			req: &LoadRequest{
@@ -0,0 +1,82 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifact
+
+import (
+	"context"
+	"fmt"
+)
+
+// NewAppRouter returns a Service sending the requests of the apps of apps,
+// keyed by app name, to their service, and the requests of the other apps
+// to fallback. If fallback is nil, the requests of the other apps fail.
+func NewAppRouter(fallback Service, apps map[string]Service) Service {
+	return &appRouter{fallback: fallback, apps: apps}
+}
+
+type appRouter struct {
+	fallback Service
+	apps     map[string]Service
+}
+
+func (r *appRouter) service(appName string) (Service, error) {
+	if s, ok := r.apps[appName]; ok {
+		return s, nil
+	}
+	if r.fallback == nil {
+		return nil, fmt.Errorf("no artifact service for app %q", appName)
+	}
+	return r.fallback, nil
+}
+
+func (r *appRouter) Save(ctx context.Context, req *SaveRequest) (*SaveResponse, error) {
+	s, err := r.service(req.AppName)
+	if err != nil {
+		return nil, err
+	}
+	return s.Save(ctx, req)
+}
+
+func (r *appRouter) Load(ctx context.Context, req *LoadRequest) (*LoadResponse, error) {
+	s, err := r.service(req.AppName)
+	if err != nil {
+		return nil, err
+	}
+	return s.Load(ctx, req)
+}
+
+func (r *appRouter) Delete(ctx context.Context, req *DeleteRequest) error {
+	s, err := r.service(req.AppName)
+	if err != nil {
+		return err
+	}
+	return s.Delete(ctx, req)
+}
+
+func (r *appRouter) List(ctx context.Context, req *ListRequest) (*ListResponse, error) {
+	s, err := r.service(req.AppName)
+	if err != nil {
+		return nil, err
+	}
+	return s.List(ctx, req)
+}
+
+func (r *appRouter) Versions(ctx context.Context, req *VersionsRequest) (*VersionsResponse, error) {
+	s, err := r.service(req.AppName)
+	if err != nil {
+		return nil, err
+	}
+	return s.Versions(ctx, req)
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifact_test
+
+import (
+	"testing"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+)
+
+func TestAppRouter(t *testing.T) {
+	ctx := t.Context()
+	fallback := artifact.InMemoryService()
+	billing := artifact.InMemoryService()
+	router := artifact.NewAppRouter(fallback, map[string]artifact.Service{"billing": billing})
+
+	for _, app := range []string{"billing", "weather"} {
+		if _, err := router.Save(ctx, &artifact.SaveRequest{AppName: app, UserID: "user", SessionID: "s", FileName: "f.txt", Part: genai.NewPartFromText(app)}); err != nil {
+			t.Fatalf("Save(%q) error = %v", app, err)
+		}
+	}
+
+	for _, tc := range []struct {
+		app, other string
+		service    artifact.Service
+	}{
+		{app: "billing", other: "weather", service: billing},
+		{app: "weather", other: "billing", service: fallback},
+	} {
+		got, err := tc.service.Load(ctx, &artifact.LoadRequest{AppName: tc.app, UserID: "user", SessionID: "s", FileName: "f.txt"})
+		if err != nil {
+			t.Fatalf("Load(%q) error = %v, want the artifact in its service", tc.app, err)
+		}
+		if got.Part.Text != tc.app {
+			t.Errorf("Load(%q) = %q, want %q", tc.app, got.Part.Text, tc.app)
+		}
+		if _, err := tc.service.Load(ctx, &artifact.LoadRequest{AppName: tc.other, UserID: "user", SessionID: "s", FileName: "f.txt"}); err == nil {
+			t.Errorf("the artifact of app %q is in the service of app %q", tc.other, tc.app)
+		}
+	}
+
+	router = artifact.NewAppRouter(nil, map[string]artifact.Service{"billing": billing})
+	if _, err := router.List(ctx, &artifact.ListRequest{AppName: "weather", UserID: "user", SessionID: "s"}); err == nil {
+		t.Errorf("List() succeeded for an app without a service, want error")
+	}
+}
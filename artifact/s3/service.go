@@ -0,0 +1,326 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package s3 provides an [artifact.Service] backed by Amazon S3 or
+// any S3-compatible object store, such as MinIO.
+//
+// Artifacts are stored as objects named
+// "{appName}/{userID}/{sessionID}/{fileName}/{version}". User scoped
+// artifacts, whose file name starts with "user:", use "user" in place of the
+// session ID. Versions are encoded in the object key, so bucket versioning
+// does not need to be enabled. New versions are written with conditional
+// writes, which the store must support, so that concurrent writers do not
+// overwrite each other.
+package s3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+)
+
+// Credentials are the AWS credentials used to sign requests.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is only needed for temporary credentials.
+	SessionToken string
+}
+
+// Config defines the configuration of an S3 artifact service.
+type Config struct {
+	// Bucket is the name of the bucket storing the artifacts.
+	Bucket string
+	// Region of the bucket. If empty, the AWS_REGION environment variable is
+	// used, and "us-east-1" if it is not set either.
+	Region string
+	// Endpoint is the base URL of an S3-compatible store, e.g.
+	// "http://localhost:9000" for a local MinIO. When set, requests use
+	// path-style addressing. If empty, the AWS S3 endpoint of the region is
+	// used with virtual-hosted-style addressing.
+	Endpoint string
+	// Credentials used to sign requests. If empty, they are read from the
+	// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN
+	// environment variables.
+	Credentials Credentials
+	// HTTPClient is used to send requests. If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+}
+
+// s3Service is an S3 implementation of the Service.
+type s3Service struct {
+	client *s3Client
+}
+
+// NewService creates an artifact service storing artifacts in an S3 bucket.
+func NewService(ctx context.Context, cfg Config) (artifact.Service, error) {
+	client, err := newClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &s3Service{client: client}, nil
+}
+
+func newClient(cfg Config) (*s3Client, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("bucket is required")
+	}
+	region := cfg.Region
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	creds := cfg.Credentials
+	if creds == (Credentials{}) {
+		creds = Credentials{
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil, errors.New("s3 credentials are required")
+	}
+	endpoint, pathStyle := cfg.Endpoint, true
+	if endpoint == "" {
+		endpoint, pathStyle = fmt.Sprintf("https://s3.%s.amazonaws.com", region), false
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &s3Client{
+		httpClient:  httpClient,
+		endpoint:    u,
+		bucket:      cfg.Bucket,
+		pathStyle:   pathStyle,
+		region:      region,
+		credentials: creds,
+		now:         time.Now,
+	}, nil
+}
+
+// fileHasUserNamespace checks if a filename indicates a user-namespaced object.
+func fileHasUserNamespace(filename string) bool {
+	return strings.HasPrefix(filename, "user:")
+}
+
+// buildObjectKey constructs the object key in S3.
+func buildObjectKey(appName, userID, sessionID, fileName string, version int64) string {
+	return buildObjectKeyPrefix(appName, userID, sessionID, fileName) + strconv.FormatInt(version, 10)
+}
+
+func buildObjectKeyPrefix(appName, userID, sessionID, fileName string) string {
+	if fileHasUserNamespace(fileName) {
+		return fmt.Sprintf("%s/%s/user/%s/", appName, userID, fileName)
+	}
+	return fmt.Sprintf("%s/%s/%s/%s/", appName, userID, sessionID, fileName)
+}
+
+func buildSessionPrefix(appName, userID, sessionID string) string {
+	return fmt.Sprintf("%s/%s/%s/", appName, userID, sessionID)
+}
+
+func buildUserPrefix(appName, userID string) string {
+	return fmt.Sprintf("%s/%s/user/", appName, userID)
+}
+
+// Save implements [artifact.Service]
+func (s *s3Service) Save(ctx context.Context, req *artifact.SaveRequest) (*artifact.SaveResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	appName, userID, sessionID, fileName := req.AppName, req.UserID, req.SessionID, req.FileName
+
+	data, contentType := []byte(req.Part.Text), "text/plain"
+	if req.Part.InlineData != nil {
+		data, contentType = req.Part.InlineData.Data, req.Part.InlineData.MIMEType
+	}
+	// The versions are written with conditional writes, a version written
+	// concurrently by another writer is detected and the next one is tried.
+	for range maxSaveAttempts {
+		versions, err := s.versions(ctx, appName, userID, sessionID, fileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list artifact versions: %w", err)
+		}
+		nextVersion := int64(1)
+		if len(versions) > 0 {
+			nextVersion = slices.Max(versions) + 1
+		}
+		key := buildObjectKey(appName, userID, sessionID, fileName, nextVersion)
+		err = s.client.putObject(ctx, key, contentType, data)
+		if errors.Is(err, errObjectExists) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to write artifact to S3: %w", err)
+		}
+		return &artifact.SaveResponse{Version: nextVersion}, nil
+	}
+	return nil, fmt.Errorf("failed to write artifact to S3: %d concurrent writes of the same version", maxSaveAttempts)
+}
+
+// maxSaveAttempts is the number of versions tried by Save when the ones it
+// picks are written concurrently.
+const maxSaveAttempts = 5
+
+// Delete implements [artifact.Service]
+func (s *s3Service) Delete(ctx context.Context, req *artifact.DeleteRequest) error {
+	err := req.Validate()
+	if err != nil {
+		return fmt.Errorf("request validation failed: %w", err)
+	}
+	appName, userID, sessionID, fileName := req.AppName, req.UserID, req.SessionID, req.FileName
+
+	if req.Version != 0 {
+		if err := s.client.deleteObject(ctx, buildObjectKey(appName, userID, sessionID, fileName, req.Version)); err != nil {
+			return fmt.Errorf("failed to delete artifact: %w", err)
+		}
+		return nil
+	}
+
+	versions, err := s.versions(ctx, appName, userID, sessionID, fileName)
+	if err != nil {
+		return fmt.Errorf("failed to fetch versions on delete artifact: %w", err)
+	}
+	g, gctx := errgroup.WithContext(ctx)
+	for _, version := range versions {
+		g.Go(func() error {
+			key := buildObjectKey(appName, userID, sessionID, fileName, version)
+			if err := s.client.deleteObject(gctx, key); err != nil {
+				return fmt.Errorf("failed to delete artifact %s: %w", key, err)
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+// Load implements [artifact.Service]
+func (s *s3Service) Load(ctx context.Context, req *artifact.LoadRequest) (*artifact.LoadResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	appName, userID, sessionID, fileName := req.AppName, req.UserID, req.SessionID, req.FileName
+	version := req.Version
+
+	if version == 0 {
+		versions, err := s.versions(ctx, appName, userID, sessionID, fileName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list artifact versions: %w", err)
+		}
+		if len(versions) == 0 {
+			return nil, fmt.Errorf("artifact not found: %w", fs.ErrNotExist)
+		}
+		version = slices.Max(versions)
+	}
+
+	key := buildObjectKey(appName, userID, sessionID, fileName, version)
+	data, contentType, err := s.client.getObject(ctx, key)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("artifact '%s' not found: %w", key, fs.ErrNotExist)
+		}
+		return nil, fmt.Errorf("could not read artifact '%s': %w", key, err)
+	}
+	return &artifact.LoadResponse{Part: genai.NewPartFromBytes(data, contentType)}, nil
+}
+
+// List implements [artifact.Service]
+func (s *s3Service) List(ctx context.Context, req *artifact.ListRequest) (*artifact.ListResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	appName, userID, sessionID := req.AppName, req.UserID, req.SessionID
+
+	filenamesSet := map[string]bool{}
+	for _, prefix := range []string{buildSessionPrefix(appName, userID, sessionID), buildUserPrefix(appName, userID)} {
+		keys, err := s.client.listObjects(ctx, prefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list artifacts: %w", err)
+		}
+		for _, key := range keys {
+			// The rest of the key is {fileName}/{version}.
+			rest := strings.TrimPrefix(key, prefix)
+			if i := strings.LastIndex(rest, "/"); i > 0 {
+				filenamesSet[rest[:i]] = true
+			}
+		}
+	}
+
+	filenames := slices.Collect(maps.Keys(filenamesSet))
+	sort.Strings(filenames)
+	return &artifact.ListResponse{FileNames: filenames}, nil
+}
+
+// versions returns the versions of an artifact, and no error if there are none.
+func (s *s3Service) versions(ctx context.Context, appName, userID, sessionID, fileName string) ([]int64, error) {
+	prefix := buildObjectKeyPrefix(appName, userID, sessionID, fileName)
+	keys, err := s.client.listObjects(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]int64, 0, len(keys))
+	for _, key := range keys {
+		version, err := strconv.ParseInt(strings.TrimPrefix(key, prefix), 10, 64)
+		// Keys of nested file names share the prefix, skip them.
+		if err != nil {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+// Versions implements [artifact.Service] and returns an error if no versions are found.
+func (s *s3Service) Versions(ctx context.Context, req *artifact.VersionsRequest) (*artifact.VersionsResponse, error) {
+	err := req.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("request validation failed: %w", err)
+	}
+	versions, err := s.versions(ctx, req.AppName, req.UserID, req.SessionID, req.FileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifact versions: %w", err)
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("artifact not found: %w", fs.ErrNotExist)
+	}
+	return &artifact.VersionsResponse{Versions: versions}, nil
+}
+
+var _ artifact.Service = (*s3Service)(nil)
@@ -17,6 +17,12 @@
 // An artifact is a file identified by an application name, a user ID, a session ID,
 // and a filename. The service provides basic storage operations for artifacts,
 // such as Save, Load, Delete, and List. It also supports versioning of artifacts.
+//
+// File names are used in URLs and as file paths, so the save requests reject
+// the names which are unsafe on any OS, see [ValidateFileName]. The other
+// requests accept them, so that the artifacts saved before the validation
+// can still be loaded, listed and deleted. Services receiving arbitrary names
+// can be wrapped with [NewSlugService] to normalize them.
 package artifact
 
 import (
@@ -98,6 +104,9 @@ func (req *SaveRequest) Validate() error {
 		return fmt.Errorf("invalid save request: missing required fields: %s", strings.Join(missingFields, ", "))
 	}
 
+	if err := ValidateFileName(req.FileName); err != nil {
+		return fmt.Errorf("invalid save request: %w", err)
+	}
 	if req.Part.Text == "" && req.Part.InlineData == nil {
 		return fmt.Errorf("invalid save request: Part.InlineData or Part.Text has to be set")
 	}
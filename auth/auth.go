@@ -0,0 +1,196 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package auth defines the types describing how tools authenticate to the
+// services they call.
+//
+// A tool that needs credentials describes them with a [Config] and requests
+// them with tool.Context.RequestCredential. The framework then emits a
+// function call named [RequestCredentialFunctionName] and the invocation
+// pauses. The client obtains the credential, e.g. by running the OAuth2
+// flow, and answers with a function response of the same name whose response
+// is the Config with ExchangedCredential set. The framework stores the
+// credential in the temporary session state of the invocation and calls the
+// tool again, which reads it with tool.Context.Credentials. The credential is
+// not persisted, the next invocations request it again.
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+)
+
+// RequestCredentialFunctionName is the name of the function call asking the
+// client for a credential. Its arguments are the ID of the function call
+// that requested the credential, under "functionCallId", and the [Config],
+// under "authConfig".
+const RequestCredentialFunctionName = "adk_request_credential"
+
+// ErrCredentialNotFound is returned when no credential was provided for a
+// config.
+var ErrCredentialNotFound = errors.New("credential not found")
+
+// Credentials gives access to the credentials provided by the client.
+type Credentials interface {
+	// Get returns the credential provided for cfg, or ErrCredentialNotFound.
+	Get(cfg *Config) (*Credential, error)
+}
+
+// SchemeType is the type of an authentication scheme, following the OpenAPI
+// security scheme types.
+type SchemeType string
+
+const (
+	SchemeTypeAPIKey        SchemeType = "apiKey"
+	SchemeTypeHTTP          SchemeType = "http"
+	SchemeTypeOAuth2        SchemeType = "oauth2"
+	SchemeTypeOpenIDConnect SchemeType = "openIdConnect"
+)
+
+// Scheme describes how a service expects to be authenticated. It follows the
+// OpenAPI security scheme object.
+type Scheme struct {
+	Type        SchemeType `json:"type"`
+	Description string     `json:"description,omitempty"`
+
+	// Name of the header, query or cookie parameter, for SchemeTypeAPIKey.
+	Name string `json:"name,omitempty"`
+	// In is the location of the API key: "query", "header" or "cookie".
+	In string `json:"in,omitempty"`
+
+	// Scheme is the HTTP authorization scheme, e.g. "basic" or "bearer",
+	// for SchemeTypeHTTP.
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+
+	// Flows are the supported OAuth2 flows, for SchemeTypeOAuth2.
+	Flows *OAuthFlows `json:"flows,omitempty"`
+
+	// OpenIDConnectURL is the OpenID Connect discovery URL, for
+	// SchemeTypeOpenIDConnect.
+	OpenIDConnectURL string `json:"openIdConnectUrl,omitempty"`
+}
+
+// OAuthFlows lists the OAuth2 flows supported by a scheme.
+type OAuthFlows struct {
+	Implicit          *OAuthFlow `json:"implicit,omitempty"`
+	Password          *OAuthFlow `json:"password,omitempty"`
+	ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty"`
+	AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty"`
+}
+
+// OAuthFlow describes the endpoints and scopes of an OAuth2 flow.
+type OAuthFlow struct {
+	AuthorizationURL string            `json:"authorizationUrl,omitempty"`
+	TokenURL         string            `json:"tokenUrl,omitempty"`
+	RefreshURL       string            `json:"refreshUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes,omitempty"`
+}
+
+// CredentialType is the type of a credential.
+type CredentialType string
+
+const (
+	CredentialTypeAPIKey         CredentialType = "apiKey"
+	CredentialTypeHTTP           CredentialType = "http"
+	CredentialTypeOAuth2         CredentialType = "oauth2"
+	CredentialTypeOpenIDConnect  CredentialType = "openIdConnect"
+	CredentialTypeServiceAccount CredentialType = "serviceAccount"
+)
+
+// Credential holds the secrets used to authenticate. Only the field matching
+// Type is set.
+type Credential struct {
+	Type CredentialType `json:"authType"`
+	// ResourceRef optionally references a credential stored elsewhere.
+	ResourceRef string `json:"resourceRef,omitempty"`
+
+	APIKey         string          `json:"apiKey,omitempty"`
+	HTTP           *HTTPAuth       `json:"http,omitempty"`
+	OAuth2         *OAuth2Auth     `json:"oauth2,omitempty"`
+	ServiceAccount *ServiceAccount `json:"serviceAccount,omitempty"`
+}
+
+// HTTPAuth is a credential for the HTTP authorization schemes.
+type HTTPAuth struct {
+	// Scheme is the HTTP authorization scheme, e.g. "basic" or "bearer".
+	Scheme   string `json:"scheme"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+// OAuth2Auth is a credential for OAuth2 and OpenID Connect. It holds the
+// client configuration and the state of the authorization code flow.
+type OAuth2Auth struct {
+	ClientID     string `json:"clientId,omitempty"`
+	ClientSecret string `json:"clientSecret,omitempty"`
+	// AuthURI is the URI the user is sent to, to grant the access.
+	AuthURI     string `json:"authUri,omitempty"`
+	State       string `json:"state,omitempty"`
+	RedirectURI string `json:"redirectUri,omitempty"`
+	// AuthResponseURI is the URI the user was redirected to, with the
+	// authorization code in its query.
+	AuthResponseURI string `json:"authResponseUri,omitempty"`
+	AuthCode        string `json:"authCode,omitempty"`
+	AccessToken     string `json:"accessToken,omitempty"`
+	RefreshToken    string `json:"refreshToken,omitempty"`
+	// ExpiresAt is the expiration time of the access token, in seconds since
+	// the Unix epoch.
+	ExpiresAt int64 `json:"expiresAt,omitempty"`
+}
+
+// ServiceAccount is a Google Cloud service account credential.
+type ServiceAccount struct {
+	// CredentialsJSON is the content of the service account key file.
+	CredentialsJSON string   `json:"serviceAccountCredential,omitempty"`
+	Scopes          []string `json:"scopes,omitempty"`
+	// UseDefaultCredential uses the application default credentials instead
+	// of CredentialsJSON.
+	UseDefaultCredential bool `json:"useDefaultCredential,omitempty"`
+}
+
+// Config describes the authentication required by a tool.
+type Config struct {
+	Scheme *Scheme `json:"authScheme"`
+	// RawCredential is the credential configured by the developer, e.g. the
+	// OAuth2 client ID and secret.
+	RawCredential *Credential `json:"rawAuthCredential,omitempty"`
+	// ExchangedCredential is the credential usable to call the service, e.g.
+	// an OAuth2 access token. It is provided by the client.
+	ExchangedCredential *Credential `json:"exchangedAuthCredential,omitempty"`
+	// CredentialKey identifies the credential in the session state.
+	CredentialKey string `json:"credentialKey,omitempty"`
+}
+
+// Key returns the key identifying the credential of the config in the
+// session state. It is CredentialKey if set, otherwise it is derived from the
+// scheme and the raw credential.
+func (c *Config) Key() string {
+	if c.CredentialKey != "" {
+		return c.CredentialKey
+	}
+	data, _ := json.Marshal(struct {
+		Scheme        *Scheme     `json:"s"`
+		RawCredential *Credential `json:"c"`
+	}{c.Scheme, c.RawCredential})
+	sum := sha256.Sum256(data)
+	schemeType := "none"
+	if c.Scheme != nil {
+		schemeType = string(c.Scheme.Type)
+	}
+	return "adk_" + schemeType + "_" + hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/auth"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+func TestConfigKey(t *testing.T) {
+	apiKey := &auth.Config{Scheme: &auth.Scheme{Type: auth.SchemeTypeAPIKey, Name: "X-Key", In: "header"}}
+	otherAPIKey := &auth.Config{Scheme: &auth.Scheme{Type: auth.SchemeTypeAPIKey, Name: "X-Other", In: "header"}}
+	withExchanged := &auth.Config{
+		Scheme:              apiKey.Scheme,
+		ExchangedCredential: &auth.Credential{Type: auth.CredentialTypeAPIKey, APIKey: "secret"},
+	}
+
+	if apiKey.Key() == otherAPIKey.Key() {
+		t.Errorf("Key() = %q for different schemes", apiKey.Key())
+	}
+	if apiKey.Key() != withExchanged.Key() {
+		t.Errorf("Key() changed with the exchanged credential: %q != %q", apiKey.Key(), withExchanged.Key())
+	}
+	if got, want := (&auth.Config{CredentialKey: "my_key"}).Key(), "my_key"; got != want {
+		t.Errorf("Key() = %q, want %q", got, want)
+	}
+}
+
+func TestRequestCredential(t *testing.T) {
+	cfg := &auth.Config{
+		Scheme: &auth.Scheme{Type: auth.SchemeTypeHTTP, Scheme: "bearer"},
+	}
+	type args struct{}
+	var calls int
+	fetch, err := functiontool.New(functiontool.Config{Name: "fetch", Description: "fetches data"},
+		func(ctx tool.Context, _ args) (map[string]any, error) {
+			calls++
+			cred, err := ctx.Credentials().Get(cfg)
+			if errors.Is(err, auth.ErrCredentialNotFound) {
+				ctx.RequestCredential(cfg)
+				return map[string]any{"status": "pending authorization"}, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{"token": cred.HTTP.Token}, nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	llm := &testutil.MockModel{
+		Responses: []*genai.Content{
+			genai.NewContentFromFunctionCall("fetch", map[string]any{}, genai.RoleModel),
+			genai.NewContentFromText("done", genai.RoleModel),
+		},
+	}
+	a, err := llmagent.New(llmagent.Config{Name: "agent", Model: llm, Tools: []tool.Tool{fetch}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner := testutil.NewTestAgentRunner(t, a)
+
+	// The first run pauses on the credential request.
+	events, err := testutil.CollectEvents(runner.Run(t, "session", "fetch the data"))
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	authEvent := events[len(events)-1]
+	if len(authEvent.LongRunningToolIDs) != 1 {
+		t.Fatalf("last event is not a credential request: %+v", authEvent)
+	}
+	authCall := authEvent.Content.Parts[0].FunctionCall
+	if authCall.Name != auth.RequestCredentialFunctionName {
+		t.Fatalf("got function call %q, want %q", authCall.Name, auth.RequestCredentialFunctionName)
+	}
+
+	// The client answers with the exchanged credential.
+	var requested auth.Config
+	data, _ := json.Marshal(authCall.Args["authConfig"])
+	if err := json.Unmarshal(data, &requested); err != nil {
+		t.Fatalf("failed to decode the requested config: %v", err)
+	}
+	requested.ExchangedCredential = &auth.Credential{
+		Type: auth.CredentialTypeHTTP,
+		HTTP: &auth.HTTPAuth{Scheme: "bearer", Token: "token-123"},
+	}
+	data, _ = json.Marshal(requested)
+	var response map[string]any
+	if err := json.Unmarshal(data, &response); err != nil {
+		t.Fatal(err)
+	}
+	content := &genai.Content{Role: genai.RoleUser, Parts: []*genai.Part{{FunctionResponse: &genai.FunctionResponse{
+		ID:       authCall.ID,
+		Name:     auth.RequestCredentialFunctionName,
+		Response: response,
+	}}}}
+	events, err = testutil.CollectEvents(runner.RunContent(t, "session", content))
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	var gotResponses []map[string]any
+	var gotTexts []string
+	for _, ev := range events {
+		for _, p := range ev.Content.Parts {
+			if p.FunctionResponse != nil {
+				gotResponses = append(gotResponses, p.FunctionResponse.Response)
+			}
+			if p.Text != "" {
+				gotTexts = append(gotTexts, p.Text)
+			}
+		}
+	}
+	if diff := cmp.Diff([]map[string]any{{"token": "token-123"}}, gotResponses); diff != "" {
+		t.Errorf("function responses mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"done"}, gotTexts); diff != "" {
+		t.Errorf("texts mismatch (-want +got):\n%s", diff)
+	}
+	if calls != 2 {
+		t.Errorf("tool called %d times, want 2", calls)
+	}
+
+	// The credential is not persisted.
+	stored := runner.Session(t, "session")
+	for k, v := range stored.State().All() {
+		if data, _ := json.Marshal(v); strings.Contains(string(data), "token-123") {
+			t.Errorf("session state %q exposes the credential: %s", k, data)
+		}
+	}
+	for ev := range stored.Events().All() {
+		if data, _ := json.Marshal(ev.Actions.StateDelta); strings.Contains(string(data), "token-123") {
+			t.Errorf("state delta of event %q exposes the credential: %s", ev.ID, data)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bundle builds agent bundles: self-contained binaries embedding a
+// declarative agent definition, the web UI and the service backends chosen
+// at build time, to hand agents to users who don't build Go programs.
+package bundle
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+
+	"google.golang.org/adk/agent/agentconfig"
+	"google.golang.org/adk/cmd/adkgo/internal/root"
+	"google.golang.org/adk/internal/cli/util"
+)
+
+// backendTags are the build tags compiling the optional backends into the
+// bundle, keyed by backend name.
+var backendTags = map[string]string{
+	"firestore": "adk_firestore",
+	"gcs":       "adk_gcs",
+	"s3":        "adk_s3",
+	"vertexai":  "adk_vertexai",
+}
+
+// schemeBackends are the optional backends serving the service URI
+// schemes.
+var schemeBackends = map[string]string{
+	"firestore":   "firestore",
+	"gs":          "gcs",
+	"s3":          "s3",
+	"agentengine": "vertexai",
+}
+
+// filesDir is the directory of the generated package embedding the agent
+// definitions.
+const filesDir = "agents"
+
+type bundleFlags struct {
+	config          string
+	output          string
+	backends        []string
+	sessionService  string
+	artifactService string
+	memoryService   string
+	goos            string
+	goarch          string
+	cgo             bool
+	tempDir         string
+
+	// configDir is the directory of the agent definitions, and rootFile the
+	// file of the root agent within it.
+	configDir string
+	rootFile  string
+}
+
+var flags bundleFlags
+
+// bundleCmd represents the bundle command.
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Builds a self-contained binary serving a declarative agent.",
+	Long: `Bundle builds a single binary embedding a declarative agent, the Web UI and the service backends selected at build time.
+	The binary runs like the full launcher, e.g. "./agent web api webui".
+	The default services can be overridden when the binary runs with the ADK_SESSION_SERVICE, ADK_ARTIFACT_SERVICE and ADK_MEMORY_SERVICE environment variables.
+	The command must run within a Go module requiring google.golang.org/adk.
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return flags.bundle()
+	},
+}
+
+func init() {
+	root.RootCmd.AddCommand(bundleCmd)
+	addFlags(bundleCmd, &flags)
+}
+
+// addFlags defines the flags of a bundle build on cmd.
+func addFlags(cmd *cobra.Command, f *bundleFlags) {
+	cmd.PersistentFlags().StringVarP(&f.config, "config", "c", "", "Directory containing "+agentconfig.RootAgentFile+", or file of the root agent. All the files of its directory are embedded")
+	cmd.PersistentFlags().StringVarP(&f.output, "output", "o", "", "Output binary, defaults to the name of the config directory")
+	cmd.PersistentFlags().StringSliceVar(&f.backends, "backends", nil, "Optional backends compiled into the bundle: firestore, gcs, s3, vertexai. The backends of the default services are always included")
+	cmd.PersistentFlags().StringVar(&f.sessionService, "session_service", "", "Default session service URI, e.g. sqlite:sessions.db or firestore://PROJECT. Defaults to memory:")
+	cmd.PersistentFlags().StringVar(&f.artifactService, "artifact_service", "", "Default artifact service URI, e.g. gs://BUCKET or s3://BUCKET. Defaults to memory:")
+	cmd.PersistentFlags().StringVar(&f.memoryService, "memory_service", "", "Default memory service URI, e.g. agentengine://ENGINE. Defaults to memory:")
+	cmd.PersistentFlags().StringVar(&f.goos, "goos", runtime.GOOS, "Target operating system")
+	cmd.PersistentFlags().StringVar(&f.goarch, "goarch", runtime.GOARCH, "Target architecture")
+	cmd.PersistentFlags().BoolVar(&f.cgo, "cgo", false, "Enable cgo, required by the sqlite session service. Without it the binary is statically linked")
+	cmd.PersistentFlags().StringVarP(&f.tempDir, "temp_dir", "t", ".", "Directory of the generated package, which must be within the Go module")
+}
+
+// validate checks the flags which can't be defaulted.
+func (f *bundleFlags) validate() error {
+	if f.config == "" {
+		return errors.New("config is required")
+	}
+	for _, b := range f.backends {
+		if _, ok := backendTags[b]; !ok {
+			return fmt.Errorf("unknown backend %q, want one of firestore, gcs, s3 or vertexai", b)
+		}
+	}
+	for _, uri := range []string{f.sessionService, f.artifactService, f.memoryService} {
+		if strings.HasPrefix(uri, "sqlite:") && !f.cgo {
+			return fmt.Errorf("service %q requires cgo", uri)
+		}
+	}
+	return nil
+}
+
+// tags returns the build tags of the selected backends and of the ones
+// serving the default services.
+func (f *bundleFlags) tags() []string {
+	var tags []string
+	for _, b := range f.backends {
+		tags = append(tags, backendTags[b])
+	}
+	for _, uri := range []string{f.sessionService, f.artifactService, f.memoryService} {
+		scheme, _, _ := strings.Cut(uri, ":")
+		if b, ok := schemeBackends[scheme]; ok {
+			tags = append(tags, backendTags[b])
+		}
+	}
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
+
+// computeFlags resolves the config and the output paths.
+func (f *bundleFlags) computeFlags() error {
+	return util.LogStartStop("Computing flags",
+		func(p util.Printer) error {
+			config, err := filepath.Abs(f.config)
+			if err != nil {
+				return fmt.Errorf("cannot make an absolute path from '%v': %w", f.config, err)
+			}
+			info, err := os.Stat(config)
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				f.configDir, f.rootFile = config, agentconfig.RootAgentFile
+			} else {
+				f.configDir, f.rootFile = filepath.Split(config)
+				f.configDir = filepath.Clean(f.configDir)
+			}
+			if f.output == "" {
+				f.output = filepath.Base(f.configDir)
+				if f.goos == "windows" {
+					f.output += ".exe"
+				}
+			}
+			f.output, err = filepath.Abs(f.output)
+			if err != nil {
+				return fmt.Errorf("cannot make an absolute path from '%v': %w", f.output, err)
+			}
+			p("Bundling", filepath.Join(f.configDir, f.rootFile), "into", f.output)
+			return nil
+		})
+}
+
+// prepareTemp creates the package of the bundle in a temp dir.
+func (f *bundleFlags) prepareTemp() error {
+	return util.LogStartStop("Preparing temp",
+		func(p util.Printer) error {
+			dir, err := os.MkdirTemp(f.tempDir, "_adkbundle_*")
+			if err != nil {
+				return fmt.Errorf("cannot create a temporary sub directory in '%v': %w", f.tempDir, err)
+			}
+			f.tempDir, err = filepath.Abs(dir)
+			if err != nil {
+				return err
+			}
+			p("Using temp dir:", f.tempDir)
+			if err := copyFiles(f.configDir, filepath.Join(f.tempDir, filesDir), f.tempDir, f.output); err != nil {
+				return fmt.Errorf("failed to copy the agent definitions: %w", err)
+			}
+			src, err := f.mainSource()
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(filepath.Join(f.tempDir, "main.go"), src, 0o644)
+		})
+}
+
+// copyFiles copies the regular files of the tree of src to dst, skipping
+// the hidden ones and the excluded paths.
+func copyFiles(src, dst string, exclude ...string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if slices.Contains(exclude, path) || (path != src && strings.HasPrefix(d.Name(), ".")) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0o644)
+	})
+}
+
+var mainTemplate = template.Must(template.New("main").Parse(`// Code generated by adkgo bundle. DO NOT EDIT.
+
+package main
+
+import (
+	"embed"
+	"io/fs"
+	"log"
+
+	"google.golang.org/adk/cmd/launcher/bundle"
+)
+
+//go:embed all:{{.Dir}}
+var files embed.FS
+
+func main() {
+	agents, err := fs.Sub(files, {{printf "%q" .Dir}})
+	if err != nil {
+		log.Fatal(err)
+	}
+	bundle.Main(bundle.Config{
+		Files:           agents,
+		Root:            {{printf "%q" .Root}},
+		SessionService:  {{printf "%q" .SessionService}},
+		ArtifactService: {{printf "%q" .ArtifactService}},
+		MemoryService:   {{printf "%q" .MemoryService}},
+	})
+}
+`))
+
+// mainSource returns the source of the main package of the bundle.
+func (f *bundleFlags) mainSource() ([]byte, error) {
+	var buf bytes.Buffer
+	err := mainTemplate.Execute(&buf, map[string]string{
+		"Dir":             filesDir,
+		"Root":            filepath.ToSlash(f.rootFile),
+		"SessionService":  f.sessionService,
+		"ArtifactService": f.artifactService,
+		"MemoryService":   f.memoryService,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate main.go: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// build compiles the bundle.
+func (f *bundleFlags) build() error {
+	return util.LogStartStop("Compiling bundle",
+		func(p util.Printer) error {
+			tags := f.tags()
+			p("Using build tags:", tags)
+			cmd := exec.Command("go", "build", "-trimpath", "-ldflags", "-s -w", "-tags", strings.Join(tags, ","), "-o", f.output, ".")
+			cmd.Dir = f.tempDir
+			cgo := "0"
+			if f.cgo {
+				cgo = "1"
+			}
+			cmd.Env = append(os.Environ(), "CGO_ENABLED="+cgo, "GOOS="+f.goos, "GOARCH="+f.goarch)
+			return util.LogCommand(cmd, p)
+		})
+}
+
+func (f *bundleFlags) cleanTemp() error {
+	return util.LogStartStop("Cleaning temp",
+		func(p util.Printer) error {
+			p("Clean temp starting with", f.tempDir)
+			if err := os.RemoveAll(f.tempDir); err != nil {
+				return fmt.Errorf("failed to clean temp directory %v: %w", f.tempDir, err)
+			}
+			return nil
+		})
+}
+
+// bundle executes the sequence of actions building the bundle.
+func (f *bundleFlags) bundle() error {
+	err := f.validate()
+	if err != nil {
+		return err
+	}
+	err = f.computeFlags()
+	if err != nil {
+		return err
+	}
+	err = f.prepareTemp()
+	if err != nil {
+		return err
+	}
+	err = f.build()
+	if cleanErr := f.cleanTemp(); err == nil {
+		err = cleanErr
+	}
+	return err
+}
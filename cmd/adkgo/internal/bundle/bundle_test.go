@@ -0,0 +1,126 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		flags   bundleFlags
+		wantErr string
+	}{
+		{name: "ok", flags: bundleFlags{config: "agents", backends: []string{"gcs"}}},
+		{name: "no config", flags: bundleFlags{}, wantErr: "config is required"},
+		{name: "unknown backend", flags: bundleFlags{config: "agents", backends: []string{"redis"}}, wantErr: `unknown backend "redis"`},
+		{name: "sqlite without cgo", flags: bundleFlags{config: "agents", sessionService: "sqlite:sessions.db"}, wantErr: "requires cgo"},
+		{name: "sqlite with cgo", flags: bundleFlags{config: "agents", sessionService: "sqlite:sessions.db", cgo: true}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.flags.validate()
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("validate() error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestTags(t *testing.T) {
+	f := bundleFlags{
+		backends:        []string{"s3", "gcs"},
+		sessionService:  "firestore://project",
+		artifactService: "gs://bucket",
+		memoryService:   "memory:",
+	}
+	want := []string{"adk_firestore", "adk_gcs", "adk_s3"}
+	if diff := cmp.Diff(want, f.tags()); diff != "" {
+		t.Errorf("tags() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMainSource(t *testing.T) {
+	f := bundleFlags{rootFile: "root.yaml", sessionService: "agentengine://123", artifactService: `s3://b?region="x"`}
+	src, err := f.mainSource()
+	if err != nil {
+		t.Fatalf("mainSource() error = %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "main.go", src, 0); err != nil {
+		t.Fatalf("generated source doesn't parse: %v\n%s", err, src)
+	}
+	for _, want := range []string{
+		"//go:embed all:agents",
+		`Root:            "root.yaml"`,
+		`SessionService:  "agentengine://123"`,
+		`ArtifactService: "s3://b?region=\"x\""`,
+		`MemoryService:   ""`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated source doesn't contain %q:\n%s", want, src)
+		}
+	}
+}
+
+func TestCopyFiles(t *testing.T) {
+	src := t.TempDir()
+	for name, data := range map[string]string{
+		"root_agent.yaml":   "name: root",
+		"sub/helper.yaml":   "name: helper",
+		".git/config":       "hidden",
+		".env":              "hidden",
+		"_adkbundle_1/x.go": "excluded",
+	} {
+		path := filepath.Join(src, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dst := t.TempDir()
+	if err := copyFiles(src, dst, filepath.Join(src, "_adkbundle_1")); err != nil {
+		t.Fatalf("copyFiles() error = %v", err)
+	}
+
+	var got []string
+	err := filepath.WalkDir(dst, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(dst, path)
+			got = append(got, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"root_agent.yaml", "sub/helper.yaml"}, got); diff != "" {
+		t.Errorf("copied files mismatch (-want +got):\n%s", diff)
+	}
+}
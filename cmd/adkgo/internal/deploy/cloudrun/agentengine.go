@@ -0,0 +1,152 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudrun
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/aiplatform/v1beta1"
+	"google.golang.org/api/option"
+
+	"google.golang.org/adk/cmd/adkgo/internal/deploy"
+	"google.golang.org/adk/internal/cli/util"
+)
+
+type deployAgentEngineFlags struct {
+	deployCloudRunFlags
+	agentEngineID string
+	displayName   string
+	description   string
+}
+
+var agentEngineFlags deployAgentEngineFlags
+
+// agentEngineCmd represents the agentengine command
+var agentEngineCmd = &cobra.Command{
+	Use:   "agentengine",
+	Short: "Deploys the application with sessions and memories managed by Vertex AI Agent Engine.",
+	Long: `Deployment creates a Vertex AI Agent Engine, unless an existing one is given, which stores the sessions and the memories of the agents.
+	The server is then deployed to Cloudrun as with the cloudrun command, wired to the Agent Engine, so that the sessions are shared with the agents of other languages using it.
+	The service account of the Cloudrun service needs the Vertex AI User role.
+	Local proxy adding authentication is started.
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return agentEngineFlags.deployOnAgentEngine(cmd.Context())
+	},
+}
+
+// init creates flags and adds subcommand to parent
+func init() {
+	deploy.DeployCmd.AddCommand(agentEngineCmd)
+	addFlags(agentEngineCmd, &agentEngineFlags.deployCloudRunFlags)
+
+	agentEngineCmd.PersistentFlags().StringVar(&agentEngineFlags.agentEngineID, "agent_engine_id", "", "ID or resource name of an existing Agent Engine. If empty, an Agent Engine is created")
+	agentEngineCmd.PersistentFlags().StringVar(&agentEngineFlags.displayName, "display_name", "", "Display name of the created Agent Engine, defaults to the service name")
+	agentEngineCmd.PersistentFlags().StringVar(&agentEngineFlags.description, "description", "", "Description of the created Agent Engine")
+}
+
+// agentEngineName returns the resource name of the existing Agent Engine.
+func (f *deployAgentEngineFlags) agentEngineName() string {
+	if strings.HasPrefix(f.agentEngineID, "projects/") {
+		return f.agentEngineID
+	}
+	return fmt.Sprintf("projects/%s/locations/%s/reasoningEngines/%s", f.gcloud.projectName, f.gcloud.region, f.agentEngineID)
+}
+
+// prepareAgentEngine creates the Agent Engine if needed and wires the server to it
+func (f *deployAgentEngineFlags) prepareAgentEngine(ctx context.Context) error {
+	return util.LogStartStop("Preparing Agent Engine",
+		func(p util.Printer) error {
+			if f.agentEngineID != "" {
+				f.cloudRun.agentEngine = f.agentEngineName()
+				p("Using Agent Engine", f.cloudRun.agentEngine)
+				return nil
+			}
+			svc, err := aiplatform.NewService(ctx, option.WithEndpoint(fmt.Sprintf("https://%s-aiplatform.googleapis.com/", f.gcloud.region)))
+			if err != nil {
+				return fmt.Errorf("failed to create vertex ai client: %w", err)
+			}
+			displayName := f.displayName
+			if displayName == "" {
+				displayName = f.cloudRun.serviceName
+			}
+			parent := fmt.Sprintf("projects/%s/locations/%s", f.gcloud.projectName, f.gcloud.region)
+			p("Creating Agent Engine", displayName, "in", parent)
+			name, err := createAgentEngine(ctx, svc, parent, displayName, f.description)
+			if err != nil {
+				return err
+			}
+			f.cloudRun.agentEngine = name
+			p("Created Agent Engine", name, "- pass it with --agent_engine_id to the next deployments")
+			return nil
+		})
+}
+
+// createAgentEngine creates an Agent Engine without agent code, used for its
+// sessions and its Memory Bank, and returns its resource name.
+func createAgentEngine(ctx context.Context, svc *aiplatform.Service, parent, displayName, description string) (string, error) {
+	engines := aiplatform.NewProjectsLocationsReasoningEnginesService(svc)
+	op, err := engines.Create(parent, &aiplatform.GoogleCloudAiplatformV1beta1ReasoningEngine{
+		DisplayName: displayName,
+		Description: description,
+	}).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to create agent engine: %w", err)
+	}
+	// The operation is named "{engine}/operations/{operation}".
+	name, _, ok := strings.Cut(op.Name, "/operations/")
+	if !ok {
+		return "", fmt.Errorf("unexpected operation name %q", op.Name)
+	}
+	for !op.Done {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(time.Second):
+		}
+		if op, err = engines.Operations.Get(op.Name).Context(ctx).Do(); err != nil {
+			return "", fmt.Errorf("failed to poll agent engine creation: %w", err)
+		}
+	}
+	if op.Error != nil {
+		return "", fmt.Errorf("failed to create agent engine: %s", op.Error.Message)
+	}
+	return name, nil
+}
+
+// deployOnAgentEngine prepares the Agent Engine, then deploys the server wired to it like deployOnCloudRun
+func (f *deployAgentEngineFlags) deployOnAgentEngine(ctx context.Context) error {
+	err := f.validate()
+	if err != nil {
+		return err
+	}
+	// The Vertex AI API must be enabled before creating the Agent Engine.
+	if f.gcloud.enableAPIs {
+		err = f.enableAPIs()
+		if err != nil {
+			return err
+		}
+		f.gcloud.enableAPIs = false
+	}
+	err = f.prepareAgentEngine(ctx)
+	if err != nil {
+		return err
+	}
+	return f.deployOnCloudRun()
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudrun
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/aiplatform/v1beta1"
+	"google.golang.org/api/option"
+)
+
+func TestCreateAgentEngine(t *testing.T) {
+	const engine = "projects/p/locations/us-central1/reasoningEngines/123"
+	var gotPath string
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		data, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(data, &gotBody); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"name": "`+engine+`/operations/op", "done": true}`)
+	}))
+	defer srv.Close()
+	svc, err := aiplatform.NewService(t.Context(), option.WithEndpoint(srv.URL), option.WithoutAuthentication())
+	if err != nil {
+		t.Fatalf("NewService() failed: %v", err)
+	}
+
+	got, err := createAgentEngine(t.Context(), svc, "projects/p/locations/us-central1", "agent", "An agent.")
+	if err != nil {
+		t.Fatalf("createAgentEngine() failed: %v", err)
+	}
+	if got != engine {
+		t.Errorf("createAgentEngine() = %q, want %q", got, engine)
+	}
+	if want := "/v1beta1/projects/p/locations/us-central1/reasoningEngines"; gotPath != want {
+		t.Errorf("request path = %q, want %q", gotPath, want)
+	}
+	if diff := cmp.Diff(map[string]any{"displayName": "agent", "description": "An agent."}, gotBody); diff != "" {
+		t.Errorf("request mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestServerArgs_AgentEngine(t *testing.T) {
+	f := &deployAgentEngineFlags{deployCloudRunFlags: *testFlags(sourceBuilder), agentEngineID: "123"}
+	f.cloudRun.agentEngine = f.agentEngineName()
+	f.cloudRun.api, f.cloudRun.a2a, f.cloudRun.webui = false, false, false
+	want := []string{"web", "-port", "8080", "-agent-engine", "projects/project/locations/us-central1/reasoningEngines/123"}
+	if diff := cmp.Diff(want, f.serverArgs()); diff != "" {
+		t.Errorf("serverArgs() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -12,10 +12,13 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package cloudrun handles command line parameters and execution logic for cloudrun deployment.
+// Package cloudrun handles command line parameters and execution logic for cloudrun deployment,
+// and for Agent Engine deployment, which serves the agent from Cloud Run with the sessions and the memories managed by Agent Engine.
 package cloudrun
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -31,9 +34,34 @@ import (
 	"google.golang.org/adk/internal/cli/util"
 )
 
+// Builders of the container image.
+const (
+	// sourceBuilder uploads the Dockerfile and the executable, the image is
+	// built by Cloud Build.
+	sourceBuilder = "source"
+	// dockerBuilder builds the image with the local docker daemon and pushes
+	// it to the image repository.
+	dockerBuilder = "docker"
+	// koBuilder builds and pushes the image with ko, without a Dockerfile.
+	koBuilder = "ko"
+)
+
+// defaultRepository is the Artifact Registry repository storing the images
+// when no image repository is set.
+const defaultRepository = "adk"
+
+// requiredAPIs are the Google Cloud APIs enabled before the deployment.
+var requiredAPIs = []string{
+	"run.googleapis.com",
+	"cloudbuild.googleapis.com",
+	"artifactregistry.googleapis.com",
+	"aiplatform.googleapis.com",
+}
+
 type gCloudFlags struct {
 	region      string
 	projectName string
+	enableAPIs  bool
 }
 
 type cloudRunServiceFlags struct {
@@ -43,6 +71,11 @@ type cloudRunServiceFlags struct {
 	a2a             bool // enable a2a or not
 	api             bool // enable api or not
 	webui           bool // enable webui or not
+	apiKeySecret    string
+	envVars         []string
+	// agentEngine is the resource name of the Agent Engine storing the
+	// sessions and the memories of the server, if any.
+	agentEngine string
 }
 
 type localProxyFlags struct {
@@ -54,6 +87,10 @@ type buildFlags struct {
 	execPath            string
 	execFile            string
 	dockerfileBuildPath string
+	builder             string
+	imageRepository     string
+	imageTag            string
+	image               string
 }
 
 type sourceFlags struct {
@@ -75,9 +112,11 @@ var flags deployCloudRunFlags
 var cloudrunCmd = &cobra.Command{
 	Use:   "cloudrun",
 	Short: "Deploys the application to cloudrun.",
-	Long: `Deployment prepares a Dockerfile which is fed with locally compiled server executable containing Web UI static files.
-	Service on Cloudrun is created using this information. 
-	Local proxy adding authentication is started. 
+	Long: `Deployment builds a container running the server executable containing Web UI static files.
+	With the "source" builder, a Dockerfile and the locally compiled executable are built by Cloud Build.
+	With the "docker" and "ko" builders, the image is built locally and pushed to Artifact Registry.
+	The required Google Cloud APIs are enabled, and the service on Cloudrun is created with the project and the location set in its environment.
+	Local proxy adding authentication is started.
 	`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return flags.deployOnCloudRun()
@@ -87,34 +126,64 @@ var cloudrunCmd = &cobra.Command{
 // init creates flags and adds subcommand to parent
 func init() {
 	deploy.DeployCmd.AddCommand(cloudrunCmd)
+	addFlags(cloudrunCmd, &flags)
+}
 
-	cloudrunCmd.PersistentFlags().StringVarP(&flags.gcloud.region, "region", "r", "", "GCP Region")
-	cloudrunCmd.PersistentFlags().StringVarP(&flags.gcloud.projectName, "project_name", "p", "", "GCP Project Name")
-	cloudrunCmd.PersistentFlags().StringVarP(&flags.cloudRun.serviceName, "service_name", "s", "", "Cloud Run Service name")
-	cloudrunCmd.PersistentFlags().StringVarP(&flags.build.tempDir, "temp_dir", "t", "", "Temp dir for build, defaults to os.TempDir() if not specified")
-	cloudrunCmd.PersistentFlags().IntVar(&flags.proxy.port, "proxy_port", 8081, "Local proxy port")
-	cloudrunCmd.PersistentFlags().IntVar(&flags.cloudRun.serverPort, "server_port", 8080, "Cloudrun server port")
-	cloudrunCmd.PersistentFlags().StringVarP(&flags.source.entryPointPath, "entry_point_path", "e", "", "Path to an entry point (go 'main')")
-	cloudrunCmd.PersistentFlags().BoolVar(&flags.cloudRun.a2a, "a2a", true, "Enable A2A")
-	cloudrunCmd.PersistentFlags().StringVarP(&flags.cloudRun.a2aAgentCardURL, "a2a_agent_url", "a", "http://127.0.0.1:8081", "A2A agent card URL as advertised in the public agent card")
-	cloudrunCmd.PersistentFlags().BoolVar(&flags.cloudRun.api, "api", true, "Enable API")
-	cloudrunCmd.PersistentFlags().BoolVar(&flags.cloudRun.webui, "webui", true, "Enable Web UI")
+// addFlags defines the flags of a Cloud Run deployment on cmd.
+func addFlags(cmd *cobra.Command, f *deployCloudRunFlags) {
+	cmd.PersistentFlags().StringVarP(&f.gcloud.region, "region", "r", "", "GCP Region")
+	cmd.PersistentFlags().StringVarP(&f.gcloud.projectName, "project_name", "p", "", "GCP Project Name")
+	cmd.PersistentFlags().StringVarP(&f.cloudRun.serviceName, "service_name", "s", "", "Cloud Run Service name")
+	cmd.PersistentFlags().StringVarP(&f.build.tempDir, "temp_dir", "t", "", "Temp dir for build, defaults to os.TempDir() if not specified")
+	cmd.PersistentFlags().IntVar(&f.proxy.port, "proxy_port", 8081, "Local proxy port")
+	cmd.PersistentFlags().IntVar(&f.cloudRun.serverPort, "server_port", 8080, "Cloudrun server port")
+	cmd.PersistentFlags().StringVarP(&f.source.entryPointPath, "entry_point_path", "e", "", "Path to an entry point (go 'main')")
+	cmd.PersistentFlags().BoolVar(&f.cloudRun.a2a, "a2a", true, "Enable A2A")
+	cmd.PersistentFlags().StringVarP(&f.cloudRun.a2aAgentCardURL, "a2a_agent_url", "a", "http://127.0.0.1:8081", "A2A agent card URL as advertised in the public agent card")
+	cmd.PersistentFlags().BoolVar(&f.cloudRun.api, "api", true, "Enable API")
+	cmd.PersistentFlags().BoolVar(&f.cloudRun.webui, "webui", true, "Enable Web UI")
+	cmd.PersistentFlags().StringVar(&f.build.builder, "builder", sourceBuilder, "Container builder: source (Cloud Build), docker or ko")
+	cmd.PersistentFlags().StringVar(&f.build.imageRepository, "image_repository", "", "Repository of the image built by docker or ko, defaults to the 'adk' Artifact Registry repository of the project, which is created if needed")
+	cmd.PersistentFlags().BoolVar(&f.gcloud.enableAPIs, "enable_apis", true, "Enable the Google Cloud APIs used by the deployment and the agent")
+	cmd.PersistentFlags().StringVar(&f.cloudRun.apiKeySecret, "api_key_secret", "GOOGLE_API_KEY", "Secret Manager secret exposed as GOOGLE_API_KEY, empty to not expose any")
+	cmd.PersistentFlags().StringArrayVar(&f.cloudRun.envVars, "env", nil, "Additional environment variable of the service, as KEY=VALUE. Can be repeated")
+}
+
+// validate checks the flags which can't be defaulted.
+func (f *deployCloudRunFlags) validate() error {
+	if f.gcloud.projectName == "" || f.gcloud.region == "" || f.cloudRun.serviceName == "" {
+		return errors.New("project_name, region and service_name are required")
+	}
+	if f.source.entryPointPath == "" {
+		return errors.New("entry_point_path is required")
+	}
+	switch f.build.builder {
+	case sourceBuilder, dockerBuilder, koBuilder:
+	default:
+		return fmt.Errorf("unknown builder %q, want one of %q, %q or %q", f.build.builder, sourceBuilder, dockerBuilder, koBuilder)
+	}
+	for _, env := range f.cloudRun.envVars {
+		if k, _, ok := strings.Cut(env, "="); !ok || k == "" {
+			return fmt.Errorf("invalid environment variable %q, want KEY=VALUE", env)
+		}
+	}
+	return nil
 }
 
 // computeFlags uses command line arguments to create a full config
 func (f *deployCloudRunFlags) computeFlags() error {
 	return util.LogStartStop("Computing flags & preparing temp",
 		func(p util.Printer) error {
-			absp, err := filepath.Abs(flags.source.entryPointPath)
+			absp, err := filepath.Abs(f.source.entryPointPath)
 			if err != nil {
 				return fmt.Errorf("cannot make an absolute path from '%v': %w", f.source.entryPointPath, err)
 			}
 			f.source.entryPointPath = absp
 
-			if flags.build.tempDir == "" {
-				flags.build.tempDir = os.TempDir()
+			if f.build.tempDir == "" {
+				f.build.tempDir = os.TempDir()
 			}
-			absp, err = filepath.Abs(flags.build.tempDir)
+			absp, err = filepath.Abs(f.build.tempDir)
 			if err != nil {
 				return fmt.Errorf("cannot make an absolute path from '%v': %w", f.build.tempDir, err)
 			}
@@ -138,6 +207,15 @@ func (f *deployCloudRunFlags) computeFlags() error {
 			}
 			f.build.dockerfileBuildPath = path.Join(f.build.tempDir, "Dockerfile")
 
+			if f.build.builder != sourceBuilder {
+				if f.build.imageRepository == "" {
+					f.build.imageRepository = f.defaultImageRepository()
+				}
+				f.build.imageTag = time.Now().Format("20060102-150405")
+				f.build.image = fmt.Sprintf("%s/%s:%s", f.build.imageRepository, f.cloudRun.serviceName, f.build.imageTag)
+				p("Using image:", f.build.image)
+			}
+
 			return nil
 		})
 }
@@ -178,44 +256,169 @@ func (f *deployCloudRunFlags) prepareDockerfile() error {
 		func(p util.Printer) error {
 			p("Writing:", f.build.dockerfileBuildPath)
 
-			var b strings.Builder
-			b.WriteString(`
+			cmd, err := json.Marshal(append([]string{"/app/" + f.build.execFile}, f.serverArgs()...))
+			if err != nil {
+				return err
+			}
+			dockerfile := `
 FROM gcr.io/distroless/static-debian11
 
 COPY ` + f.build.execFile + `  /app/` + f.build.execFile + `
-EXPOSE ` + strconv.Itoa(flags.cloudRun.serverPort) + `
+EXPOSE ` + strconv.Itoa(f.cloudRun.serverPort) + `
 # Command to run the executable when the container starts
-CMD ["/app/` + f.build.execFile + `", "web", "-port", "` + strconv.Itoa(flags.cloudRun.serverPort) + `"`)
+CMD ` + string(cmd) + `
+`
+			return os.WriteFile(f.build.dockerfileBuildPath, []byte(dockerfile), 0o600)
+		})
+}
+
+// serverArgs returns the arguments of the server executable in the container.
+func (f *deployCloudRunFlags) serverArgs() []string {
+	args := []string{"web", "-port", strconv.Itoa(f.cloudRun.serverPort)}
+	if f.cloudRun.agentEngine != "" {
+		args = append(args, "-agent-engine", f.cloudRun.agentEngine)
+	}
+	if f.cloudRun.api {
+		args = append(args, "api", "-webui_address", "127.0.0.1:"+strconv.Itoa(f.proxy.port))
+	}
+	if f.cloudRun.a2a {
+		args = append(args, "a2a", "--a2a_agent_url", f.cloudRun.a2aAgentCardURL)
+	}
+	if f.cloudRun.webui {
+		args = append(args, "webui", "--api_server_address", "http://127.0.0.1:"+strconv.Itoa(f.proxy.port)+"/api")
+	}
+	return args
+}
 
-			if flags.cloudRun.api {
-				b.WriteString(`, "api", "-webui_address", "127.0.0.1:` + strconv.Itoa(f.proxy.port) + `"`)
+// envVars returns the environment variables of the service.
+func (f *deployCloudRunFlags) envVars() []string {
+	return append([]string{
+		"GOOGLE_CLOUD_PROJECT=" + f.gcloud.projectName,
+		"GOOGLE_CLOUD_LOCATION=" + f.gcloud.region,
+	}, f.cloudRun.envVars...)
+}
+
+// gcloudList formats values as a gcloud list argument. gcloud splits lists
+// on commas unless another delimiter is set with the ^delimiter^ prefix.
+func gcloudList(values []string) string {
+	for _, v := range values {
+		if strings.Contains(v, ",") {
+			return "^|^" + strings.Join(values, "|")
+		}
+	}
+	return strings.Join(values, ",")
+}
+
+func (f *deployCloudRunFlags) defaultImageRepository() string {
+	return fmt.Sprintf("%s-docker.pkg.dev/%s/%s", f.gcloud.region, f.gcloud.projectName, defaultRepository)
+}
+
+// deployParams returns the gcloud parameters deploying the service.
+func (f *deployCloudRunFlags) deployParams() []string {
+	params := []string{"run", "deploy", f.cloudRun.serviceName}
+	switch f.build.builder {
+	case sourceBuilder:
+		params = append(params, "--source", ".")
+	case dockerBuilder:
+		params = append(params, "--image", f.build.image, "--port", strconv.Itoa(f.cloudRun.serverPort))
+	case koBuilder:
+		// The ko image has no command arguments.
+		params = append(params, "--image", f.build.image, "--port", strconv.Itoa(f.cloudRun.serverPort),
+			"--args", gcloudList(f.serverArgs()))
+	}
+	params = append(params, "--set-env-vars", gcloudList(f.envVars()))
+	if f.cloudRun.apiKeySecret != "" {
+		params = append(params, "--set-secrets=GOOGLE_API_KEY="+f.cloudRun.apiKeySecret+":latest")
+	}
+	return append(params,
+		"--region", f.gcloud.region,
+		"--project", f.gcloud.projectName,
+		"--ingress", "all",
+		"--no-allow-unauthenticated",
+	)
+}
+
+// enableAPIs invokes gcloud to enable the Google Cloud APIs required by the deployment
+func (f *deployCloudRunFlags) enableAPIs() error {
+	return util.LogStartStop("Enabling Google Cloud APIs",
+		func(p util.Printer) error {
+			params := append([]string{"services", "enable"}, requiredAPIs...)
+			cmd := exec.Command("gcloud", append(params, "--project", f.gcloud.projectName)...)
+			return util.LogCommand(cmd, p)
+		})
+}
+
+// prepareRepository creates the default Artifact Registry repository if it
+// doesn't exist and lets docker authenticate to the registry.
+func (f *deployCloudRunFlags) prepareRepository() error {
+	return util.LogStartStop("Preparing image repository",
+		func(p util.Printer) error {
+			if f.build.imageRepository == f.defaultImageRepository() {
+				describe := exec.Command("gcloud", "artifacts", "repositories", "describe", defaultRepository,
+					"--location", f.gcloud.region, "--project", f.gcloud.projectName)
+				if err := util.LogCommand(describe, p); err != nil {
+					p("Creating repository", defaultRepository)
+					create := exec.Command("gcloud", "artifacts", "repositories", "create", defaultRepository,
+						"--repository-format", "docker", "--location", f.gcloud.region, "--project", f.gcloud.projectName)
+					if err := util.LogCommand(create, p); err != nil {
+						return err
+					}
+				}
 			}
-			if flags.cloudRun.a2a {
-				b.WriteString(`, "a2a", "--a2a_agent_url", "` + flags.cloudRun.a2aAgentCardURL + `"`)
+			// The repository is "{host}/{path}", the host is a registry.
+			host, _, _ := strings.Cut(f.build.imageRepository, "/")
+			if !strings.HasSuffix(host, ".pkg.dev") && !strings.HasSuffix(host, "gcr.io") {
+				p("Not a Google Cloud registry, skipping docker authentication to", host)
+				return nil
 			}
-			if flags.cloudRun.webui {
-				b.WriteString(`, "webui", "--api_server_address", "http://127.0.0.1:` + strconv.Itoa(f.proxy.port) + `/api"]
-				`)
+			return util.LogCommand(exec.Command("gcloud", "auth", "configure-docker", host, "--quiet"), p)
+		})
+}
+
+// dockerBuildAndPush builds the image from the Dockerfile with docker and pushes it
+func (f *deployCloudRunFlags) dockerBuildAndPush() error {
+	return util.LogStartStop("Building image with docker",
+		func(p util.Printer) error {
+			build := exec.Command("docker", "build", "--platform", "linux/amd64", "--tag", f.build.image, ".")
+			build.Dir = f.build.tempDir
+			if err := util.LogCommand(build, p); err != nil {
+				return err
 			}
-			return os.WriteFile(f.build.dockerfileBuildPath, []byte(b.String()), 0o600)
+			return util.LogCommand(exec.Command("docker", "push", f.build.image), p)
 		})
 }
 
-// gcloudDeployToCloudRun invokes gcloud to deploy source on CloudRun
-func (f *deployCloudRunFlags) gcloudDeployToCloudRun() error {
-	return util.LogStartStop("Deploying to Cloud Run",
+// koBuildAndPush builds the image of the entry point package with ko and pushes it
+func (f *deployCloudRunFlags) koBuildAndPush() error {
+	return util.LogStartStop("Building image with ko",
 		func(p util.Printer) error {
-			params := []string{
-				"run", "deploy", f.cloudRun.serviceName,
-				"--source", ".",
-				"--set-secrets=GOOGLE_API_KEY=GOOGLE_API_KEY:latest",
-				"--region", f.gcloud.region,
-				"--project", f.gcloud.projectName,
-				"--ingress", "all",
-				"--no-allow-unauthenticated",
+			refs := path.Join(f.build.tempDir, "image_refs")
+			// --bare names the image after KO_DOCKER_REPO, as with docker.
+			cmd := exec.Command("ko", "build", ".", "--bare", "--platform", "linux/amd64", "--tags", f.build.imageTag, "--image-refs", refs)
+			cmd.Dir = f.source.srcBasePath
+			cmd.Env = append(os.Environ(), "KO_DOCKER_REPO="+f.build.imageRepository+"/"+f.cloudRun.serviceName)
+			if err := util.LogCommand(cmd, p); err != nil {
+				return err
 			}
+			// ko reports the image by digest, which is deployed rather than
+			// the mutable tag.
+			data, err := os.ReadFile(refs)
+			if err != nil {
+				return fmt.Errorf("cannot read the image reference written by ko: %w", err)
+			}
+			if image := strings.TrimSpace(string(data)); image != "" {
+				f.build.image = image
+			}
+			p("Built image:", f.build.image)
+			return nil
+		})
+}
 
-			cmd := exec.Command("gcloud", params...)
+// gcloudDeployToCloudRun invokes gcloud to deploy the source or the image on CloudRun
+func (f *deployCloudRunFlags) gcloudDeployToCloudRun() error {
+	return util.LogStartStop("Deploying to Cloud Run",
+		func(p util.Printer) error {
+			cmd := exec.Command("gcloud", f.deployParams()...)
 
 			cmd.Dir = f.build.tempDir
 			return util.LogCommand(cmd, p)
@@ -244,19 +447,47 @@ func (f *deployCloudRunFlags) runGcloudProxy() error {
 
 // deployOnCloudRun executes the sequence of actions preparing and deploying the agent to CloudRun. Then runs authenticating proxy to newly deployed service
 func (f *deployCloudRunFlags) deployOnCloudRun() error {
-	fmt.Println(flags)
-
-	err := f.computeFlags()
+	err := f.validate()
 	if err != nil {
 		return err
 	}
-	err = f.compileEntryPoint()
+	err = f.computeFlags()
 	if err != nil {
 		return err
 	}
-	err = f.prepareDockerfile()
-	if err != nil {
-		return err
+	if f.gcloud.enableAPIs {
+		err = f.enableAPIs()
+		if err != nil {
+			return err
+		}
+	}
+	if f.build.builder != sourceBuilder {
+		err = f.prepareRepository()
+		if err != nil {
+			return err
+		}
+	}
+	switch f.build.builder {
+	case koBuilder:
+		err = f.koBuildAndPush()
+		if err != nil {
+			return err
+		}
+	default:
+		err = f.compileEntryPoint()
+		if err != nil {
+			return err
+		}
+		err = f.prepareDockerfile()
+		if err != nil {
+			return err
+		}
+		if f.build.builder == dockerBuilder {
+			err = f.dockerBuildAndPush()
+			if err != nil {
+				return err
+			}
+		}
 	}
 	err = f.gcloudDeployToCloudRun()
 	if err != nil {
@@ -0,0 +1,133 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudrun
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func testFlags(builder string) *deployCloudRunFlags {
+	return &deployCloudRunFlags{
+		gcloud: gCloudFlags{region: "us-central1", projectName: "project"},
+		cloudRun: cloudRunServiceFlags{
+			serviceName:     "agent",
+			serverPort:      8080,
+			a2aAgentCardURL: "http://127.0.0.1:8081",
+			a2a:             true,
+			api:             true,
+			webui:           true,
+			apiKeySecret:    "GOOGLE_API_KEY",
+		},
+		proxy:  localProxyFlags{port: 8081},
+		build:  buildFlags{builder: builder, image: "us-central1-docker.pkg.dev/project/adk/agent:tag"},
+		source: sourceFlags{entryPointPath: "main.go"},
+	}
+}
+
+func TestDeployParams(t *testing.T) {
+	common := []string{
+		"--region", "us-central1",
+		"--project", "project",
+		"--ingress", "all",
+		"--no-allow-unauthenticated",
+	}
+	for _, tc := range []struct {
+		name   string
+		modify func(f *deployCloudRunFlags)
+		want   []string
+	}{
+		{
+			name:   "source",
+			modify: func(f *deployCloudRunFlags) {},
+			want: append([]string{
+				"run", "deploy", "agent", "--source", ".",
+				"--set-env-vars", "GOOGLE_CLOUD_PROJECT=project,GOOGLE_CLOUD_LOCATION=us-central1",
+				"--set-secrets=GOOGLE_API_KEY=GOOGLE_API_KEY:latest",
+			}, common...),
+		},
+		{
+			name: "docker with env and no secret",
+			modify: func(f *deployCloudRunFlags) {
+				f.build.builder = dockerBuilder
+				f.cloudRun.apiKeySecret = ""
+				f.cloudRun.envVars = []string{"A=1", "B=x,y"}
+			},
+			want: append([]string{
+				"run", "deploy", "agent",
+				"--image", "us-central1-docker.pkg.dev/project/adk/agent:tag", "--port", "8080",
+				"--set-env-vars", "^|^GOOGLE_CLOUD_PROJECT=project|GOOGLE_CLOUD_LOCATION=us-central1|A=1|B=x,y",
+			}, common...),
+		},
+		{
+			name: "ko",
+			modify: func(f *deployCloudRunFlags) {
+				f.build.builder = koBuilder
+				f.cloudRun.a2a = false
+				f.cloudRun.webui = false
+			},
+			want: append([]string{
+				"run", "deploy", "agent",
+				"--image", "us-central1-docker.pkg.dev/project/adk/agent:tag", "--port", "8080",
+				"--args", "web,-port,8080,api,-webui_address,127.0.0.1:8081",
+				"--set-env-vars", "GOOGLE_CLOUD_PROJECT=project,GOOGLE_CLOUD_LOCATION=us-central1",
+				"--set-secrets=GOOGLE_API_KEY=GOOGLE_API_KEY:latest",
+			}, common...),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := testFlags(sourceBuilder)
+			tc.modify(f)
+			if diff := cmp.Diff(tc.want, f.deployParams()); diff != "" {
+				t.Errorf("deployParams() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServerArgs(t *testing.T) {
+	want := []string{
+		"web", "-port", "8080",
+		"api", "-webui_address", "127.0.0.1:8081",
+		"a2a", "--a2a_agent_url", "http://127.0.0.1:8081",
+		"webui", "--api_server_address", "http://127.0.0.1:8081/api",
+	}
+	if diff := cmp.Diff(want, testFlags(sourceBuilder).serverArgs()); diff != "" {
+		t.Errorf("serverArgs() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		modify  func(f *deployCloudRunFlags)
+		wantErr bool
+	}{
+		{name: "valid", modify: func(f *deployCloudRunFlags) {}},
+		{name: "no project", modify: func(f *deployCloudRunFlags) { f.gcloud.projectName = "" }, wantErr: true},
+		{name: "no entry point", modify: func(f *deployCloudRunFlags) { f.source.entryPointPath = "" }, wantErr: true},
+		{name: "unknown builder", modify: func(f *deployCloudRunFlags) { f.build.builder = "bazel" }, wantErr: true},
+		{name: "invalid env", modify: func(f *deployCloudRunFlags) { f.cloudRun.envVars = []string{"A"} }, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := testFlags(sourceBuilder)
+			tc.modify(f)
+			if err := f.validate(); (err != nil) != tc.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/session/sqlite"
+)
+
+// backend creates the services stored in a backend. The constructors of
+// the services it doesn't store are nil.
+type backend struct {
+	session  func(ctx context.Context, u *uri) (session.Service, error)
+	artifact func(ctx context.Context, u *uri) (artifact.Service, error)
+	memory   func(ctx context.Context, u *uri) (memory.Service, error)
+}
+
+// backends are the backends compiled into the bundle, keyed by URI scheme.
+// The optional ones register themselves from files guarded by build tags.
+var backends = map[string]*backend{
+	"memory": {
+		session: func(context.Context, *uri) (session.Service, error) {
+			return session.InMemoryService(), nil
+		},
+		artifact: func(context.Context, *uri) (artifact.Service, error) {
+			return artifact.InMemoryService(), nil
+		},
+		memory: func(context.Context, *uri) (memory.Service, error) {
+			return memory.InMemoryService(), nil
+		},
+	},
+	"sqlite": {
+		session: func(_ context.Context, u *uri) (session.Service, error) {
+			return sqlite.NewSessionService(u.Path)
+		},
+	},
+}
+
+// uri is a parsed service URI.
+type uri struct {
+	Scheme string
+	// Host is the authority of the URI, e.g. the project or the bucket.
+	Host string
+	// Path is the rest of the URI, without the leading slash after the
+	// host. For opaque URIs like "sqlite:sessions.db", it's the part after
+	// the scheme.
+	Path  string
+	Query url.Values
+}
+
+// parseURI parses a service URI. The empty URI selects the memory backend.
+func parseURI(s string) (*uri, error) {
+	if s == "" {
+		s = "memory:"
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" {
+		return nil, fmt.Errorf("missing scheme in %q", s)
+	}
+	res := &uri{Scheme: u.Scheme, Host: u.Host, Query: u.Query()}
+	switch {
+	case u.Opaque != "":
+		res.Path = u.Opaque
+	case u.Host == "":
+		// "sqlite:///tmp/sessions.db" keeps its absolute path.
+		res.Path = u.Path
+	default:
+		res.Path = strings.TrimPrefix(u.Path, "/")
+	}
+	return res, nil
+}
+
+// schemes returns the sorted schemes of the backends matching keep.
+func schemes(keep func(*backend) bool) []string {
+	var res []string
+	for scheme, b := range backends {
+		if keep(b) {
+			res = append(res, scheme)
+		}
+	}
+	slices.Sort(res)
+	return res
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bundle runs an agent bundle: a self-contained binary embedding a
+// declarative agent definition, built with "adkgo bundle".
+//
+// The services of the bundle are selected by URIs, set when the bundle is
+// built and overridden at run time by the ADK_SESSION_SERVICE,
+// ADK_ARTIFACT_SERVICE and ADK_MEMORY_SERVICE environment variables. The
+// backends compiled into the bundle depend on its build tags:
+//
+//	memory:                      in-memory services (default)
+//	sqlite:PATH                  sessions in a SQLite file, requires cgo
+//	firestore://PROJECT[/DB]     sessions in Firestore (adk_firestore)
+//	gs://BUCKET                  artifacts in Cloud Storage (adk_gcs)
+//	s3://BUCKET[?region=R&endpoint=E]
+//	                             artifacts in S3 (adk_s3)
+//	agentengine://ENGINE         sessions and memories in a Vertex AI Agent
+//	                             Engine, given as ID or resource name
+//	                             (adk_vertexai)
+package bundle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/agentconfig"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/cmd/launcher"
+	"google.golang.org/adk/cmd/launcher/full"
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/session"
+)
+
+// Environment variables overriding the services of the bundle.
+const (
+	SessionServiceEnv  = "ADK_SESSION_SERVICE"
+	ArtifactServiceEnv = "ADK_ARTIFACT_SERVICE"
+	MemoryServiceEnv   = "ADK_MEMORY_SERVICE"
+)
+
+// Config defines the content of a bundle.
+type Config struct {
+	// Files contains the agent definitions.
+	Files fs.FS
+	// Root is the file of Files defining the root agent. If empty,
+	// agentconfig.RootAgentFile is used.
+	Root string
+	// SessionService, ArtifactService and MemoryService are the URIs of the
+	// default services. If empty, the services are in memory.
+	SessionService  string
+	ArtifactService string
+	MemoryService   string
+}
+
+// Main runs the bundle with the command line arguments, like the full
+// launcher. It exits the process on failure.
+func Main(cfg Config) {
+	ctx := context.Background()
+	config, err := NewLauncherConfig(ctx, cfg)
+	if err != nil {
+		log.Fatalf("Failed to load the bundle: %v", err)
+	}
+	l := full.NewLauncher()
+	if err := l.Execute(ctx, config, os.Args[1:]); err != nil {
+		log.Fatalf("Run failed: %v\n\n%s", err, l.CommandLineSyntax())
+	}
+}
+
+// NewLauncherConfig returns the launcher config serving the agent of the
+// bundle with its services.
+func NewLauncherConfig(ctx context.Context, cfg Config) (*launcher.Config, error) {
+	if cfg.Files == nil {
+		return nil, errors.New("bundle has no files")
+	}
+	root := cfg.Root
+	if root == "" {
+		root = agentconfig.RootAgentFile
+	}
+	a, err := agentconfig.LoadFS(ctx, cfg.Files, root, agentconfig.NewRegistry())
+	if err != nil {
+		return nil, err
+	}
+	sessionService, err := newService(ctx, "session", env(SessionServiceEnv, cfg.SessionService), func(b *backend) func(context.Context, *uri) (session.Service, error) {
+		return b.session
+	})
+	if err != nil {
+		return nil, err
+	}
+	artifactService, err := newService(ctx, "artifact", env(ArtifactServiceEnv, cfg.ArtifactService), func(b *backend) func(context.Context, *uri) (artifact.Service, error) {
+		return b.artifact
+	})
+	if err != nil {
+		return nil, err
+	}
+	memoryService, err := newService(ctx, "memory", env(MemoryServiceEnv, cfg.MemoryService), func(b *backend) func(context.Context, *uri) (memory.Service, error) {
+		return b.memory
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &launcher.Config{
+		SessionService:  sessionService,
+		ArtifactService: artifactService,
+		MemoryService:   memoryService,
+		AgentLoader:     agent.NewSingleLoader(a),
+	}, nil
+}
+
+// env returns the value of the environment variable key, or def if it is
+// not set.
+func env(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return def
+}
+
+// newService creates the service of the given kind selected by rawURI,
+// using the constructor returned by get for the backend of its scheme.
+func newService[S any](ctx context.Context, kind, rawURI string, get func(*backend) func(context.Context, *uri) (S, error)) (S, error) {
+	var zero S
+	u, err := parseURI(rawURI)
+	if err != nil {
+		return zero, fmt.Errorf("invalid %s service: %w", kind, err)
+	}
+	var create func(context.Context, *uri) (S, error)
+	if b, ok := backends[u.Scheme]; ok {
+		create = get(b)
+	}
+	if create == nil {
+		return zero, fmt.Errorf("unsupported %s service %q, the bundle supports the schemes %v", kind, rawURI, schemes(func(b *backend) bool { return get(b) != nil }))
+	}
+	s, err := create(ctx, u)
+	if err != nil {
+		return zero, fmt.Errorf("failed to create %s service %q: %w", kind, rawURI, err)
+	}
+	return s, nil
+}
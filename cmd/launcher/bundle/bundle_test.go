@@ -0,0 +1,97 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bundle
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseURI(t *testing.T) {
+	for _, tc := range []struct {
+		in      string
+		want    *uri
+		wantErr bool
+	}{
+		{in: "", want: &uri{Scheme: "memory", Query: url.Values{}}},
+		{in: "memory:", want: &uri{Scheme: "memory", Query: url.Values{}}},
+		{in: "sqlite:sessions.db", want: &uri{Scheme: "sqlite", Path: "sessions.db", Query: url.Values{}}},
+		{in: "sqlite:///var/lib/adk/sessions.db", want: &uri{Scheme: "sqlite", Path: "/var/lib/adk/sessions.db", Query: url.Values{}}},
+		{in: "firestore://my-project/my-db?collection=apps", want: &uri{Scheme: "firestore", Host: "my-project", Path: "my-db", Query: url.Values{"collection": {"apps"}}}},
+		{in: "gs://my-bucket", want: &uri{Scheme: "gs", Host: "my-bucket", Query: url.Values{}}},
+		{in: "sessions.db", wantErr: true},
+	} {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := parseURI(tc.in)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseURI(%q) error = %v, wantErr %v", tc.in, err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("parseURI(%q) mismatch (-want +got):\n%s", tc.in, diff)
+			}
+		})
+	}
+}
+
+func TestNewLauncherConfig(t *testing.T) {
+	files := fstest.MapFS{
+		"root_agent.yaml":   {Data: []byte("name: helper\nsub_agents:\n  - config_path: agents/faq.yaml\n")},
+		"agents/faq.yaml":   {Data: []byte("name: faq\n")},
+		"other/assist.yaml": {Data: []byte("name: assist\n")},
+	}
+
+	t.Run("default", func(t *testing.T) {
+		cfg, err := NewLauncherConfig(t.Context(), Config{Files: files})
+		if err != nil {
+			t.Fatalf("NewLauncherConfig() error = %v", err)
+		}
+		root := cfg.AgentLoader.RootAgent()
+		if root.Name() != "helper" || len(root.SubAgents()) != 1 || root.SubAgents()[0].Name() != "faq" {
+			t.Errorf("root agent = %s with %d sub-agents, want helper with faq", root.Name(), len(root.SubAgents()))
+		}
+		if cfg.SessionService == nil || cfg.ArtifactService == nil || cfg.MemoryService == nil {
+			t.Errorf("services = %v, %v, %v, want all set", cfg.SessionService, cfg.ArtifactService, cfg.MemoryService)
+		}
+	})
+
+	t.Run("root file", func(t *testing.T) {
+		cfg, err := NewLauncherConfig(t.Context(), Config{Files: files, Root: "other/assist.yaml"})
+		if err != nil {
+			t.Fatalf("NewLauncherConfig() error = %v", err)
+		}
+		if got := cfg.AgentLoader.RootAgent().Name(); got != "assist" {
+			t.Errorf("root agent = %q, want %q", got, "assist")
+		}
+	})
+
+	t.Run("env override", func(t *testing.T) {
+		t.Setenv(ArtifactServiceEnv, "ftp://host")
+		_, err := NewLauncherConfig(t.Context(), Config{Files: files, ArtifactService: "memory:"})
+		if err == nil || !strings.Contains(err.Error(), `unsupported artifact service "ftp://host"`) {
+			t.Errorf("NewLauncherConfig() error = %v, want unsupported artifact service", err)
+		}
+	})
+
+	t.Run("backend without the service", func(t *testing.T) {
+		_, err := NewLauncherConfig(t.Context(), Config{Files: files, MemoryService: "sqlite:memories.db"})
+		if err == nil || !strings.Contains(err.Error(), "unsupported memory service") {
+			t.Errorf("NewLauncherConfig() error = %v, want unsupported memory service", err)
+		}
+	})
+}
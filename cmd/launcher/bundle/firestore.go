@@ -0,0 +1,36 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build adk_firestore
+
+package bundle
+
+import (
+	"context"
+
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/session/firestore"
+)
+
+func init() {
+	backends["firestore"] = &backend{
+		session: func(ctx context.Context, u *uri) (session.Service, error) {
+			return firestore.NewSessionService(ctx, firestore.Config{
+				ProjectID:  u.Host,
+				Database:   u.Path,
+				Collection: u.Query.Get("collection"),
+			})
+		},
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build adk_s3
+
+package bundle
+
+import (
+	"context"
+
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/artifact/s3"
+)
+
+func init() {
+	backends["s3"] = &backend{
+		artifact: func(ctx context.Context, u *uri) (artifact.Service, error) {
+			return s3.NewService(ctx, s3.Config{
+				Bucket:   u.Host,
+				Region:   u.Query.Get("region"),
+				Endpoint: u.Query.Get("endpoint"),
+			})
+		},
+	}
+}
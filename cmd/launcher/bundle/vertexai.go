@@ -0,0 +1,63 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build adk_vertexai
+
+package bundle
+
+import (
+	"context"
+	"path"
+
+	"google.golang.org/adk/memory"
+	memoryvertexai "google.golang.org/adk/memory/vertexai"
+	"google.golang.org/adk/session"
+	sessionvertexai "google.golang.org/adk/session/vertexai"
+)
+
+func init() {
+	backends["agentengine"] = &backend{
+		session: func(ctx context.Context, u *uri) (session.Service, error) {
+			name, project, location, err := sessionvertexai.ResolveAgentEngine(agentEngine(u))
+			if err != nil {
+				return nil, err
+			}
+			return sessionvertexai.NewSessionService(ctx, sessionvertexai.Config{
+				ProjectID:     project,
+				Location:      location,
+				AgentEngineID: name,
+			})
+		},
+		memory: func(ctx context.Context, u *uri) (memory.Service, error) {
+			name, project, location, err := sessionvertexai.ResolveAgentEngine(agentEngine(u))
+			if err != nil {
+				return nil, err
+			}
+			return memoryvertexai.NewMemoryService(ctx, memoryvertexai.Config{
+				ProjectID:     project,
+				Location:      location,
+				AgentEngineID: name,
+			})
+		},
+	}
+}
+
+// agentEngine returns the Agent Engine of an "agentengine://" URI. Resource
+// names are split by the URI parsing, "projects" becoming the host.
+func agentEngine(u *uri) string {
+	if u.Path == "" {
+		return u.Host
+	}
+	return path.Join(u.Host, u.Path)
+}
@@ -0,0 +1,406 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package console
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/cmd/launcher"
+	"google.golang.org/adk/internal/sessionutils"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+)
+
+// userID and appName are not important at this moment, we can just use any
+const (
+	userID  = "console_user"
+	appName = "console_app"
+)
+
+const helpText = `Commands:
+  /help                 shows this help
+  /agents               lists the agents
+  /agent <name>         sends the next messages to the given agent
+  /state                shows the session state
+  /new                  starts a new session
+  /save <file>          saves the session events to a file
+  /load <file>          continues the conversation saved in a file, in a new session
+  /artifacts [dir]      writes the session artifacts to a directory, the current one by default
+  /exit                 exits the console
+`
+
+// errExit is returned by a command to end the console interaction.
+var errExit = errors.New("exit")
+
+// chat is an interactive conversation with the agents in the console.
+type chat struct {
+	config         *launcher.Config
+	streamingMode  agent.StreamingMode
+	sessionService session.Service
+	out            io.Writer
+
+	// agent handles the user messages. It is the root agent unless another
+	// one is selected with the /agent command.
+	agent     agent.Agent
+	runners   map[string]*runner.Runner
+	sessionID string
+}
+
+func newChat(ctx context.Context, config *launcher.Config, streamingMode agent.StreamingMode, out io.Writer) (*chat, error) {
+	if config.SessionService == nil {
+		config.SessionService = session.InMemoryService()
+	}
+	config.RouteAppServices()
+	if streamingMode == "" {
+		streamingMode = agent.StreamingModeSSE
+	}
+	c := &chat{
+		config:         config,
+		streamingMode:  streamingMode,
+		sessionService: config.SessionService,
+		out:            out,
+		agent:          config.AgentLoader.RootAgent(),
+		runners:        make(map[string]*runner.Runner),
+	}
+	if err := c.newSession(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// loop reads the user messages and commands from in until it is exhausted
+// or the user exits.
+func (c *chat) loop(ctx context.Context, in io.Reader) error {
+	reader := bufio.NewReader(in)
+	for {
+		fmt.Fprint(c.out, "\nUser -> ")
+
+		userInput, err := reader.ReadString('\n')
+		if errors.Is(err, io.EOF) && userInput == "" {
+			return nil
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		if strings.HasPrefix(strings.TrimSpace(userInput), "/") {
+			if err := c.command(ctx, strings.TrimSpace(userInput)); err != nil {
+				if errors.Is(err, errExit) {
+					return nil
+				}
+				fmt.Fprintf(c.out, "\nERROR: %v\n", err)
+			}
+			continue
+		}
+
+		if err := c.send(ctx, genai.NewContentFromText(userInput, genai.RoleUser)); err != nil {
+			return err
+		}
+	}
+}
+
+// command runs a slash command.
+func (c *chat) command(ctx context.Context, line string) error {
+	name, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+	switch name {
+	case "/help":
+		fmt.Fprint(c.out, helpText)
+	case "/exit", "/quit":
+		return errExit
+	case "/agents":
+		c.printAgents(c.config.AgentLoader.RootAgent(), 0)
+	case "/agent":
+		a := findAgent(c.config.AgentLoader.RootAgent(), arg)
+		if a == nil {
+			return fmt.Errorf("unknown agent %q, see /agents", arg)
+		}
+		c.agent = a
+		fmt.Fprintf(c.out, "Messages are sent to %s.\n", a.Name())
+	case "/state":
+		return c.printState(ctx)
+	case "/new":
+		if err := c.newSession(ctx); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "Started session %s.\n", c.sessionID)
+	case "/save":
+		if arg == "" {
+			return fmt.Errorf("usage: /save <file>")
+		}
+		return c.save(ctx, arg)
+	case "/load":
+		if arg == "" {
+			return fmt.Errorf("usage: /load <file>")
+		}
+		return c.load(ctx, arg)
+	case "/artifacts":
+		if arg == "" {
+			arg = "."
+		}
+		return c.dumpArtifacts(ctx, arg)
+	default:
+		return fmt.Errorf("unknown command %s, see /help", name)
+	}
+	return nil
+}
+
+// runner returns the runner of the selected agent.
+func (c *chat) runner() (*runner.Runner, error) {
+	if r, ok := c.runners[c.agent.Name()]; ok {
+		return r, nil
+	}
+	r, err := runner.New(runner.Config{
+		AppName:         appName,
+		Agent:           c.agent,
+		SessionService:  c.sessionService,
+		ArtifactService: c.config.ArtifactService,
+		MemoryService:   c.config.MemoryService,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create runner: %v", err)
+	}
+	c.runners[c.agent.Name()] = r
+	return r, nil
+}
+
+// send runs the selected agent for msg and prints its response.
+func (c *chat) send(ctx context.Context, msg *genai.Content) error {
+	r, err := c.runner()
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(c.out, "\nAgent -> ")
+	prevText := ""
+	for event, err := range r.Run(ctx, userID, c.sessionID, msg, agent.RunConfig{
+		StreamingMode: c.streamingMode,
+	}) {
+		if err != nil {
+			fmt.Fprintf(c.out, "\nAGENT_ERROR: %v\n", err)
+			continue
+		}
+		if event.LLMResponse.Content == nil {
+			continue
+		}
+
+		text := ""
+		for _, p := range event.LLMResponse.Content.Parts {
+			text += p.Text
+		}
+
+		if c.streamingMode != agent.StreamingModeSSE {
+			fmt.Fprint(c.out, text)
+			continue
+		}
+
+		// In SSE mode, always print partial responses and capture them.
+		if !event.IsFinalResponse() {
+			fmt.Fprint(c.out, text)
+			prevText += text
+			continue
+		}
+
+		// Only print final response if it doesn't match previously captured text.
+		if text != prevText {
+			fmt.Fprint(c.out, text)
+		}
+
+		prevText = ""
+	}
+	return nil
+}
+
+func (c *chat) newSession(ctx context.Context) error {
+	resp, err := c.sessionService.Create(ctx, &session.CreateRequest{
+		AppName: appName,
+		UserID:  userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create the session: %v", err)
+	}
+	c.sessionID = resp.Session.ID()
+	return nil
+}
+
+func (c *chat) session(ctx context.Context) (session.Session, error) {
+	resp, err := c.sessionService.Get(ctx, &session.GetRequest{
+		AppName:   appName,
+		UserID:    userID,
+		SessionID: c.sessionID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the session: %v", err)
+	}
+	return resp.Session, nil
+}
+
+func (c *chat) printAgents(a agent.Agent, depth int) {
+	marker := " "
+	if a == c.agent {
+		marker = "*"
+	}
+	fmt.Fprintf(c.out, "%s %s%s", marker, strings.Repeat("  ", depth), a.Name())
+	if a.Description() != "" {
+		fmt.Fprintf(c.out, ": %s", a.Description())
+	}
+	fmt.Fprintln(c.out)
+	for _, sub := range a.SubAgents() {
+		c.printAgents(sub, depth+1)
+	}
+}
+
+func (c *chat) printState(ctx context.Context) error {
+	s, err := c.session(ctx)
+	if err != nil {
+		return err
+	}
+	state := maps.Collect(s.State().All())
+	if len(state) == 0 {
+		fmt.Fprintln(c.out, "The state is empty.")
+		return nil
+	}
+	b, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the state: %v", err)
+	}
+	fmt.Fprintln(c.out, string(b))
+	return nil
+}
+
+// savedSession is the content of the files written by /save.
+type savedSession struct {
+	Events []*session.Event `json:"events"`
+}
+
+func (c *chat) save(ctx context.Context, path string) error {
+	s, err := c.session(ctx)
+	if err != nil {
+		return err
+	}
+	saved := savedSession{Events: slices.Collect(s.Events().All())}
+	b, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the session: %v", err)
+	}
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		return fmt.Errorf("failed to save the session: %v", err)
+	}
+	fmt.Fprintf(c.out, "Saved %d events to %s.\n", len(saved.Events), path)
+	return nil
+}
+
+// load creates a new session with the events saved in the file at path.
+// The app and user state are not changed by the saved events.
+func (c *chat) load(ctx context.Context, path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read the session: %v", err)
+	}
+	var saved savedSession
+	if err := json.Unmarshal(b, &saved); err != nil {
+		return fmt.Errorf("failed to decode the session: %v", err)
+	}
+	if err := c.newSession(ctx); err != nil {
+		return err
+	}
+	s, err := c.session(ctx)
+	if err != nil {
+		return err
+	}
+	for _, event := range saved.Events {
+		if event == nil {
+			continue
+		}
+		_, _, event.Actions.StateDelta = sessionutils.ExtractStateDeltas(event.Actions.StateDelta)
+		if err := c.sessionService.AppendEvent(ctx, s, event); err != nil {
+			return fmt.Errorf("failed to load the session: %v", err)
+		}
+	}
+	fmt.Fprintf(c.out, "Loaded %d events into session %s.\n", len(saved.Events), c.sessionID)
+	return nil
+}
+
+func (c *chat) dumpArtifacts(ctx context.Context, dir string) error {
+	artifacts := c.config.ArtifactService
+	if artifacts == nil {
+		return fmt.Errorf("no artifact service is configured")
+	}
+	list, err := artifacts.List(ctx, &artifact.ListRequest{AppName: appName, UserID: userID, SessionID: c.sessionID})
+	if err != nil {
+		return fmt.Errorf("failed to list the artifacts: %v", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create the directory: %v", err)
+	}
+	written := 0
+	for _, name := range list.FileNames {
+		if !filepath.IsLocal(name) {
+			fmt.Fprintf(c.out, "Skipped %s: invalid file name.\n", name)
+			continue
+		}
+		resp, err := artifacts.Load(ctx, &artifact.LoadRequest{AppName: appName, UserID: userID, SessionID: c.sessionID, FileName: name})
+		if err != nil {
+			return fmt.Errorf("failed to load the artifact %s: %v", name, err)
+		}
+		var data []byte
+		switch p := resp.Part; {
+		case p == nil:
+			continue
+		case p.InlineData != nil:
+			data = p.InlineData.Data
+		case p.Text != "":
+			data = []byte(p.Text)
+		default:
+			fmt.Fprintf(c.out, "Skipped %s: no inline content.\n", name)
+			continue
+		}
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("failed to create the directory: %v", err)
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return fmt.Errorf("failed to write the artifact %s: %v", name, err)
+		}
+		written++
+	}
+	fmt.Fprintf(c.out, "Wrote %d artifacts to %s.\n", written, dir)
+	return nil
+}
+
+func findAgent(a agent.Agent, name string) agent.Agent {
+	if a.Name() == name {
+		return a
+	}
+	for _, sub := range a.SubAgents() {
+		if found := findAgent(sub, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
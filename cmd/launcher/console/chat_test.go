@@ -0,0 +1,147 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package console
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/cmd/launcher"
+	"google.golang.org/adk/internal/testutil"
+)
+
+func newTestConfig(t *testing.T) *launcher.Config {
+	t.Helper()
+	helper, err := llmagent.New(llmagent.Config{
+		Name:        "helper",
+		Description: "helps",
+		Model:       &testutil.MockModel{Responses: []*genai.Content{genai.NewContentFromText("hi from helper", genai.RoleModel)}},
+		OutputKey:   "helper_reply",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := llmagent.New(llmagent.Config{
+		Name:      "root",
+		Model:     &testutil.MockModel{Responses: []*genai.Content{genai.NewContentFromText("hi from root", genai.RoleModel)}},
+		SubAgents: []agent.Agent{helper},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &launcher.Config{
+		AgentLoader:     agent.NewSingleLoader(root),
+		ArtifactService: artifact.InMemoryService(),
+	}
+}
+
+func TestChat(t *testing.T) {
+	ctx := t.Context()
+	dir := t.TempDir()
+	saved := filepath.Join(dir, "session.json")
+	artifactsDir := filepath.Join(dir, "artifacts")
+
+	config := newTestConfig(t)
+	var out strings.Builder
+	c, err := newChat(ctx, config, agent.StreamingModeNone, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := strings.Join([]string{
+		"hello",
+		"/agents",
+		"/agent helper",
+		"hi helper",
+		"/state",
+		"/save " + saved,
+		"/load " + saved,
+		"/state",
+		"/agent nobody",
+		"/artifacts " + artifactsDir,
+		"/exit",
+		"not sent",
+	}, "\n") + "\n"
+	if err := c.loop(ctx, strings.NewReader(input)); err != nil {
+		t.Fatalf("loop() error = %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"Agent -> hi from root",
+		"* root\n    helper: helps\n",
+		"Messages are sent to helper.",
+		"Agent -> hi from helper",
+		`"helper_reply": "hi from helper"`,
+		"Saved 4 events to " + saved,
+		"Loaded 4 events into session",
+		`ERROR: unknown agent "nobody"`,
+		// The loaded session has no artifacts.
+		"Wrote 0 artifacts",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q:\n%s", want, got)
+		}
+	}
+	// The state of the loaded session is rebuilt from the saved events.
+	if n := strings.Count(got, `"helper_reply": "hi from helper"`); n != 2 {
+		t.Errorf("state shown %d times, want 2:\n%s", n, got)
+	}
+}
+
+func TestChat_Artifacts(t *testing.T) {
+	ctx := t.Context()
+	dir := t.TempDir()
+	config := newTestConfig(t)
+	var out strings.Builder
+	c, err := newChat(ctx, config, agent.StreamingModeNone, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, part := range map[string]*genai.Part{
+		"notes.txt":       genai.NewPartFromText("some notes"),
+		"images/logo.png": genai.NewPartFromBytes([]byte{1, 2, 3}, "image/png"),
+	} {
+		if _, err := config.ArtifactService.Save(ctx, &artifact.SaveRequest{
+			AppName: appName, UserID: userID, SessionID: c.sessionID, FileName: name, Part: part,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := c.command(ctx, "/artifacts "+dir); err != nil {
+		t.Fatalf("/artifacts error = %v", err)
+	}
+
+	for name, want := range map[string]string{
+		"notes.txt":       "some notes",
+		"images/logo.png": "\x01\x02\x03",
+	} {
+		got, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Errorf("artifact %s was not written: %v", name, err)
+			continue
+		}
+		if string(got) != want {
+			t.Errorf("artifact %s = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -16,21 +16,15 @@
 package console
 
 import (
-	"bufio"
 	"context"
 	"flag"
 	"fmt"
-	"log"
 	"os"
 
-	"google.golang.org/genai"
-
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/cmd/launcher"
 	"google.golang.org/adk/cmd/launcher/universal"
 	"google.golang.org/adk/internal/cli/util"
-	"google.golang.org/adk/runner"
-	"google.golang.org/adk/session"
 )
 
 // consoleConfig contains command-line params for console launcher
@@ -57,91 +51,14 @@ func NewLauncher() launcher.SubLauncher {
 }
 
 // Run implements launcher.SubLauncher. It starts the console interaction loop.
+// Besides the messages to the agent, the user can enter slash commands, see
+// /help.
 func (l *consoleLauncher) Run(ctx context.Context, config *launcher.Config) error {
-	// userID and appName are not important at this moment, we can just use any
-	userID, appName := "console_user", "console_app"
-
-	sessionService := config.SessionService
-	if sessionService == nil {
-		sessionService = session.InMemoryService()
-	}
-
-	resp, err := sessionService.Create(ctx, &session.CreateRequest{
-		AppName: appName,
-		UserID:  userID,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to create the session service: %v", err)
-	}
-
-	rootAgent := config.AgentLoader.RootAgent()
-
-	session := resp.Session
-
-	r, err := runner.New(runner.Config{
-		AppName:         appName,
-		Agent:           rootAgent,
-		SessionService:  sessionService,
-		ArtifactService: config.ArtifactService,
-	})
+	c, err := newChat(ctx, config, l.config.streamingMode, os.Stdout)
 	if err != nil {
-		return fmt.Errorf("failed to create runner: %v", err)
-	}
-
-	reader := bufio.NewReader(os.Stdin)
-
-	for {
-		fmt.Print("\nUser -> ")
-
-		userInput, err := reader.ReadString('\n')
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		userMsg := genai.NewContentFromText(userInput, genai.RoleUser)
-
-		streamingMode := l.config.streamingMode
-		if streamingMode == "" {
-			streamingMode = agent.StreamingModeSSE
-		}
-		fmt.Print("\nAgent -> ")
-		prevText := ""
-		for event, err := range r.Run(ctx, userID, session.ID(), userMsg, agent.RunConfig{
-			StreamingMode: streamingMode,
-		}) {
-			if err != nil {
-				fmt.Printf("\nAGENT_ERROR: %v\n", err)
-			} else {
-				if event.LLMResponse.Content == nil {
-					continue
-				}
-
-				text := ""
-				for _, p := range event.LLMResponse.Content.Parts {
-					text += p.Text
-				}
-
-				if streamingMode != agent.StreamingModeSSE {
-					fmt.Print(text)
-					continue
-				}
-
-				// In SSE mode, always print partial responses and capture them.
-				if !event.IsFinalResponse() {
-					fmt.Print(text)
-					prevText += text
-					continue
-				}
-
-				// Only print final response if it doesn't match previously captured text.
-				if text != prevText {
-					fmt.Print(text)
-				}
-
-				prevText = ""
-			}
-		}
+		return err
 	}
+	return c.loop(ctx, os.Stdin)
 }
 
 // Parse implements launcher.SubLauncher. After parsing console-specific
@@ -17,12 +17,20 @@ package launcher
 
 import (
 	"context"
+	"crypto/tls"
+	"log/slog"
 
 	"github.com/a2aproject/a2a-go/a2asrv"
+	"go.opentelemetry.io/otel/trace"
 
 	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/agentconfig"
 	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/embedding"
 	"google.golang.org/adk/memory"
+	"google.golang.org/adk/server/adka2a"
+	"google.golang.org/adk/server/httpauth"
+	"google.golang.org/adk/server/ratelimit"
 	"google.golang.org/adk/session"
 )
 
@@ -57,6 +65,87 @@ type Config struct {
 	SessionService  session.Service
 	ArtifactService artifact.Service
 	MemoryService   memory.Service
-	AgentLoader     agent.Loader
-	A2AOptions      []a2asrv.RequestHandlerOption
+	// AppServices overrides the services of some apps, keyed by app name,
+	// e.g. to store their data in other backends or with other credentials.
+	// The services left nil in an override fall back to the ones above.
+	AppServices map[string]AppServices
+	AgentLoader agent.Loader
+	// AgentRegistry resolves the tools and the models of the agents
+	// registered through the REST API, when AgentLoader is an
+	// agent.MutableLoader. If nil, agentconfig.NewRegistry() is used.
+	AgentRegistry *agentconfig.Registry
+	// Embedder, if set, makes the session search of the REST API semantic
+	// instead of full-text.
+	Embedder   embedding.Embedder
+	A2AOptions []a2asrv.RequestHandlerOption
+	// A2AAgentCard customizes the agent card published by the A2A server.
+	// The URL, the interfaces and the capabilities default to the ones
+	// served by the launcher.
+	A2AAgentCard adka2a.AgentCardConfig
+	// TracerProvider receives the spans emitted by the ADK. If nil, the
+	// global tracer provider is used.
+	TracerProvider trace.TracerProvider
+	// Logger receives the structured logs of the ADK. If nil,
+	// slog.Default() is used.
+	Logger *slog.Logger
+	// Authorizer decides whether the authenticated callers of the REST API
+	// can access the data of a user. If nil, the callers can only access
+	// their own data.
+	Authorizer httpauth.Authorizer
+	// AllowedOrigins are the origins of the web pages, other than the ones
+	// of the server, allowed to open the WebSockets of the REST API, e.g.
+	// "https://example.com" or "localhost:8080".
+	AllowedOrigins []string
+	// MaxArtifactSize is the size limit, in bytes, of the artifacts uploaded
+	// through the REST API. If zero, a default of 64 MiB is used.
+	MaxArtifactSize int64
+	// RateLimit, if set, limits the runs started through the REST and gRPC
+	// APIs. Rejected runs get a 429 status with a Retry-After header, or the
+	// ResourceExhausted code.
+	RateLimit *ratelimit.Config
+	// TLSConfig is used by the web server. If it provides the certificates,
+	// the server is served over HTTPS without -tls-cert-file and
+	// -tls-key-file.
+	TLSConfig *tls.Config
+}
+
+// AppServices are the services of an app, overriding the ones of the Config.
+type AppServices struct {
+	SessionService  session.Service
+	ArtifactService artifact.Service
+	MemoryService   memory.Service
+}
+
+// RouteAppServices replaces the services of c by services sending the
+// requests of the apps of AppServices to their overrides. It is called by
+// the launchers once the default services are set.
+func (c *Config) RouteAppServices() {
+	if len(c.AppServices) == 0 {
+		return
+	}
+	sessions := make(map[string]session.Service)
+	artifacts := make(map[string]artifact.Service)
+	memories := make(map[string]memory.Service)
+	for app, s := range c.AppServices {
+		if s.SessionService != nil {
+			sessions[app] = s.SessionService
+		}
+		if s.ArtifactService != nil {
+			artifacts[app] = s.ArtifactService
+		}
+		if s.MemoryService != nil {
+			memories[app] = s.MemoryService
+		}
+	}
+	if len(sessions) > 0 {
+		c.SessionService = session.NewAppRouter(c.SessionService, sessions)
+	}
+	if len(artifacts) > 0 {
+		c.ArtifactService = artifact.NewAppRouter(c.ArtifactService, artifacts)
+	}
+	if len(memories) > 0 {
+		c.MemoryService = memory.NewAppRouter(c.MemoryService, memories)
+	}
+	// The overrides are routed, don't route them again.
+	c.AppServices = nil
 }
@@ -14,18 +14,21 @@
 
 // Package prod provides easy way to play with ADK with all available options without
 // development support (no console, no ADK Web UI) including only production
-// options like the REST API and A2A support.
+// options like the REST API and A2A support, and one-shot runs for batch jobs.
 package prod
 
 import (
 	"google.golang.org/adk/cmd/launcher"
+	"google.golang.org/adk/cmd/launcher/run"
 	"google.golang.org/adk/cmd/launcher/universal"
 	"google.golang.org/adk/cmd/launcher/web"
 	"google.golang.org/adk/cmd/launcher/web/a2a"
 	"google.golang.org/adk/cmd/launcher/web/api"
+	"google.golang.org/adk/cmd/launcher/web/grpc"
 )
 
-// NewLauncher returns a launcher capable of serving ADK REST API and A2A.
+// NewLauncher returns a launcher capable of serving ADK REST API and A2A, and
+// of running the agent once.
 func NewLauncher() launcher.Launcher {
-	return universal.NewLauncher(web.NewLauncher(api.NewLauncher(), a2a.NewLauncher()))
+	return universal.NewLauncher(web.NewLauncher(api.NewLauncher(), a2a.NewLauncher(), grpc.NewLauncher()), run.NewLauncher())
 }
@@ -0,0 +1,194 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package replay provides a launcher that replays recorded sessions through
+// the agent, e.g. for regression checks before a release.
+//
+// The sessions are read from bundles made by the session export of the REST
+// API or [session.Export]. The user messages of each bundle are sent to the
+// agent in a new session, and the events and final responses are compared
+// with the recorded ones. A report of the differences is printed, and an
+// error is returned if a replay differs, so that the program exits with a
+// non-zero code.
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/cmd/launcher"
+	"google.golang.org/adk/cmd/launcher/universal"
+	"google.golang.org/adk/internal/cli/util"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// replayConfig contains command-line params for the replay launcher
+type replayConfig struct {
+	bundles []string // taken from the positional arguments
+	app     string
+	output  string
+	userID  string
+}
+
+// replayLauncher replays recorded sessions
+type replayLauncher struct {
+	flags  *flag.FlagSet // flags are used to parse command-line arguments
+	config *replayConfig // config contains parsed command-line parameters
+
+	out io.Writer
+}
+
+// NewLauncher creates a new replay launcher.
+func NewLauncher() launcher.SubLauncher {
+	config := &replayConfig{}
+
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	fs.StringVar(&config.app, "app", "", "agent to replay the sessions with; the root agent is used if not set")
+	fs.StringVar(&config.output, "output", outputText,
+		fmt.Sprintf("defines the output format (%s|%s): the differences, or the reports as JSON", outputText, outputJSON))
+	fs.StringVar(&config.userID, "user_id", "", "user of the replayed sessions; the recorded user is used if not set")
+
+	return &replayLauncher{config: config, flags: fs, out: os.Stdout}
+}
+
+// Keyword implements launcher.SubLauncher. Returns the command-line keyword for this launcher.
+func (l *replayLauncher) Keyword() string {
+	return "replay"
+}
+
+// Parse implements launcher.SubLauncher. The arguments remaining after the
+// flags are the bundle files, so there are no unparsed arguments.
+func (l *replayLauncher) Parse(args []string) ([]string, error) {
+	err := l.flags.Parse(args)
+	if err != nil || !l.flags.Parsed() {
+		return nil, fmt.Errorf("failed to parse flags: %v", err)
+	}
+	if l.config.output != outputText && l.config.output != outputJSON {
+		return nil, fmt.Errorf("invalid output: %v. Should be (%s|%s)", l.config.output, outputText, outputJSON)
+	}
+	l.config.bundles = l.flags.Args()
+	return nil, nil
+}
+
+// CommandLineSyntax implements launcher.SubLauncher. Returns the command-line syntax for the replay launcher.
+func (l *replayLauncher) CommandLineSyntax() string {
+	return "  [flags] bundle.json...\n" + util.FormatFlagUsage(l.flags)
+}
+
+// SimpleDescription implements launcher.SubLauncher. Returns a simple description of the replay launcher.
+func (l *replayLauncher) SimpleDescription() string {
+	return "replays exported sessions through the agent and reports the differences."
+}
+
+// Execute implements launcher.Launcher. It parses arguments and runs the launcher.
+func (l *replayLauncher) Execute(ctx context.Context, config *launcher.Config, args []string) error {
+	remainingArgs, err := l.Parse(args)
+	if err != nil {
+		return fmt.Errorf("cannot parse args: %w", err)
+	}
+	if err := universal.ErrorOnUnparsedArgs(remainingArgs); err != nil {
+		return fmt.Errorf("cannot parse all the arguments: %w", err)
+	}
+	return l.Run(ctx, config)
+}
+
+// jsonOutput is printed for each bundle with the json output format.
+type jsonOutput struct {
+	Bundle string `json:"bundle"`
+	*runner.ReplayReport
+	Passed bool `json:"passed"`
+}
+
+// Run implements launcher.SubLauncher. It replays the bundles.
+func (l *replayLauncher) Run(ctx context.Context, config *launcher.Config) error {
+	if len(l.config.bundles) == 0 {
+		return errors.New("no bundle to replay")
+	}
+	a := config.AgentLoader.RootAgent()
+	if l.config.app != "" {
+		var err error
+		if a, err = config.AgentLoader.LoadAgent(l.config.app); err != nil {
+			return fmt.Errorf("failed to load agent: %w", err)
+		}
+	}
+	if config.SessionService == nil {
+		config.SessionService = session.InMemoryService()
+	}
+	config.RouteAppServices()
+
+	failed := 0
+	for _, path := range l.config.bundles {
+		report, err := l.replay(ctx, config, a, path)
+		if err != nil {
+			return fmt.Errorf("failed to replay %s: %w", path, err)
+		}
+		if !report.Passed() {
+			failed++
+		}
+		if err := l.print(path, report); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d replayed sessions differ from their recording", failed, len(l.config.bundles))
+	}
+	return nil
+}
+
+func (l *replayLauncher) replay(ctx context.Context, config *launcher.Config, a agent.Agent, path string) (*runner.ReplayReport, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var bundle session.Bundle
+	if err := json.Unmarshal(b, &bundle); err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+	r, err := runner.New(runner.Config{
+		AppName:         bundle.AppName,
+		Agent:           a,
+		SessionService:  config.SessionService,
+		ArtifactService: config.ArtifactService,
+		MemoryService:   config.MemoryService,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create runner: %v", err)
+	}
+	return r.Replay(ctx, &bundle, runner.ReplayConfig{UserID: l.config.userID})
+}
+
+func (l *replayLauncher) print(path string, report *runner.ReplayReport) error {
+	if l.config.output == outputText {
+		_, err := fmt.Fprintf(l.out, "%s:\n%s", path, report)
+		return err
+	}
+	enc := json.NewEncoder(l.out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(jsonOutput{Bundle: path, ReplayReport: report, Passed: report.Passed()}); err != nil {
+		return fmt.Errorf("failed to print the report: %v", err)
+	}
+	return nil
+}
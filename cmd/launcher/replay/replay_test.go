@@ -0,0 +1,108 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replay
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/cmd/launcher"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+func writeBundle(t *testing.T, answer string) string {
+	t.Helper()
+	bundle := session.Bundle{
+		Version:   session.BundleFormatVersion,
+		AppName:   "app",
+		UserID:    "user",
+		SessionID: "recorded",
+		Events: []*session.Event{
+			{ID: "e1", InvocationID: "i1", Author: "user", LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("question", genai.RoleUser)}},
+			{ID: "e2", InvocationID: "i1", Author: "agent", LLMResponse: model.LLMResponse{Content: genai.NewContentFromText(answer, genai.RoleModel)}},
+		},
+	}
+	b, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "bundle.json")
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func newTestLauncher(t *testing.T, response string) (*replayLauncher, *launcher.Config, *strings.Builder) {
+	t.Helper()
+	m := &testutil.MockModel{Responses: []*genai.Content{genai.NewContentFromText(response, genai.RoleModel)}}
+	a, err := llmagent.New(llmagent.Config{Name: "agent", Model: m})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := NewLauncher().(*replayLauncher)
+	out := &strings.Builder{}
+	l.out = out
+	return l, &launcher.Config{AgentLoader: agent.NewSingleLoader(a)}, out
+}
+
+func TestReplay_Passed(t *testing.T) {
+	l, config, out := newTestLauncher(t, "the answer")
+	path := writeBundle(t, "the answer")
+	if err := l.Execute(t.Context(), config, []string{path}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	want := path + ":\nturn 1: ok: \"question\"\n1/1 turns passed\n"
+	if got := out.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestReplay_Differs(t *testing.T) {
+	l, config, out := newTestLauncher(t, "another answer")
+	path := writeBundle(t, "the answer")
+	if err := l.Execute(t.Context(), config, []string{"-output", "json", path}); err == nil {
+		t.Fatal("Execute() succeeded, want error")
+	}
+	var got struct {
+		Passed bool `json:"passed"`
+		Turns  []struct {
+			RecordedText string `json:"recordedText"`
+			ReplayedText string `json:"replayedText"`
+		} `json:"turns"`
+	}
+	if err := json.Unmarshal([]byte(out.String()), &got); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out.String())
+	}
+	if got.Passed || len(got.Turns) != 1 || got.Turns[0].RecordedText != "the answer" || got.Turns[0].ReplayedText != "another answer" {
+		t.Errorf("output = %+v, want a failed turn replayed as %q", got, "another answer")
+	}
+}
+
+func TestReplay_NoBundle(t *testing.T) {
+	l, config, _ := newTestLauncher(t, "the answer")
+	if err := l.Execute(t.Context(), config, nil); err == nil {
+		t.Fatal("Execute() succeeded, want error")
+	}
+}
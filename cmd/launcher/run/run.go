@@ -0,0 +1,239 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package run provides a launcher that runs an agent once, non-interactively,
+// e.g. in CI pipelines and cron jobs.
+//
+// The prompt is taken from the command-line arguments, from a file, or from
+// the standard input, in this order. The final response of the agent is
+// printed to the standard output, and an error is returned if the agent
+// fails, so that the program exits with a non-zero code.
+package run
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/cmd/launcher"
+	"google.golang.org/adk/cmd/launcher/universal"
+	"google.golang.org/adk/internal/cli/util"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+)
+
+const (
+	appName = "run_app"
+
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// runConfig contains command-line params for the run launcher
+type runConfig struct {
+	prompt     string // taken from the positional arguments
+	promptFile string
+	output     string
+	userID     string
+	sessionID  string
+}
+
+// runLauncher runs an agent once
+type runLauncher struct {
+	flags  *flag.FlagSet // flags are used to parse command-line arguments
+	config *runConfig    // config contains parsed command-line parameters
+
+	in  io.Reader
+	out io.Writer
+}
+
+// NewLauncher creates a new run launcher.
+func NewLauncher() launcher.SubLauncher {
+	config := &runConfig{}
+
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.StringVar(&config.promptFile, "prompt_file", "", "file containing the prompt, used if no prompt is given as argument; '-' reads the standard input")
+	fs.StringVar(&config.output, "output", outputText,
+		fmt.Sprintf("defines the output format (%s|%s): the final response, or the events and the final response as JSON", outputText, outputJSON))
+	fs.StringVar(&config.userID, "user_id", "run_user", "user running the agent")
+	fs.StringVar(&config.sessionID, "session_id", "", "session to continue; a new session is created if not set")
+
+	return &runLauncher{config: config, flags: fs, in: os.Stdin, out: os.Stdout}
+}
+
+// Keyword implements launcher.SubLauncher. Returns the command-line keyword for this launcher.
+func (l *runLauncher) Keyword() string {
+	return "run"
+}
+
+// Parse implements launcher.SubLauncher. The arguments remaining after the
+// flags form the prompt, so there are no unparsed arguments.
+func (l *runLauncher) Parse(args []string) ([]string, error) {
+	err := l.flags.Parse(args)
+	if err != nil || !l.flags.Parsed() {
+		return nil, fmt.Errorf("failed to parse flags: %v", err)
+	}
+	if l.config.output != outputText && l.config.output != outputJSON {
+		return nil, fmt.Errorf("invalid output: %v. Should be (%s|%s)", l.config.output, outputText, outputJSON)
+	}
+	l.config.prompt = strings.Join(l.flags.Args(), " ")
+	return nil, nil
+}
+
+// CommandLineSyntax implements launcher.SubLauncher. Returns the command-line syntax for the run launcher.
+func (l *runLauncher) CommandLineSyntax() string {
+	return "  [flags] [prompt]\n" + util.FormatFlagUsage(l.flags)
+}
+
+// SimpleDescription implements launcher.SubLauncher. Returns a simple description of the run launcher.
+func (l *runLauncher) SimpleDescription() string {
+	return "runs an agent once for a prompt and prints its response."
+}
+
+// Execute implements launcher.Launcher. It parses arguments and runs the launcher.
+func (l *runLauncher) Execute(ctx context.Context, config *launcher.Config, args []string) error {
+	remainingArgs, err := l.Parse(args)
+	if err != nil {
+		return fmt.Errorf("cannot parse args: %w", err)
+	}
+	if err := universal.ErrorOnUnparsedArgs(remainingArgs); err != nil {
+		return fmt.Errorf("cannot parse all the arguments: %w", err)
+	}
+	return l.Run(ctx, config)
+}
+
+// Run implements launcher.SubLauncher. It runs the root agent for the prompt.
+func (l *runLauncher) Run(ctx context.Context, config *launcher.Config) error {
+	prompt, err := l.readPrompt()
+	if err != nil {
+		return err
+	}
+
+	if config.SessionService == nil {
+		config.SessionService = session.InMemoryService()
+	}
+	config.RouteAppServices()
+	r, err := runner.New(runner.Config{
+		AppName:         appName,
+		Agent:           config.AgentLoader.RootAgent(),
+		SessionService:  config.SessionService,
+		ArtifactService: config.ArtifactService,
+		MemoryService:   config.MemoryService,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create runner: %v", err)
+	}
+	chat, err := r.NewChat(ctx, runner.ChatConfig{
+		UserID:    l.config.userID,
+		SessionID: l.config.sessionID,
+	})
+	if err != nil {
+		return err
+	}
+
+	result, runErr := chat.SendContent(ctx, genai.NewContentFromText(prompt, genai.RoleUser))
+	if runErr == nil {
+		runErr = eventError(result.Events)
+	}
+	if err := l.print(chat.SessionID(), result, runErr); err != nil {
+		return err
+	}
+	if runErr != nil {
+		return fmt.Errorf("agent run failed: %w", runErr)
+	}
+	return nil
+}
+
+// readPrompt returns the prompt given as argument, or read from the prompt
+// file or the standard input.
+func (l *runLauncher) readPrompt() (string, error) {
+	prompt := l.config.prompt
+	if prompt == "" {
+		var b []byte
+		var err error
+		if l.config.promptFile == "" || l.config.promptFile == "-" {
+			b, err = io.ReadAll(l.in)
+		} else {
+			b, err = os.ReadFile(l.config.promptFile)
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read the prompt: %v", err)
+		}
+		prompt = string(b)
+	}
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" {
+		return "", errors.New("the prompt is empty")
+	}
+	return prompt, nil
+}
+
+// eventError returns the error reported by the last event, if any.
+func eventError(events []*session.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if last := events[len(events)-1]; last.ErrorCode != "" {
+		return fmt.Errorf("%s: %s", last.ErrorCode, last.ErrorMessage)
+	}
+	return nil
+}
+
+// jsonOutput is printed with the json output format.
+type jsonOutput struct {
+	SessionID     string           `json:"sessionId"`
+	FinalText     string           `json:"finalText"`
+	Events        []*session.Event `json:"events"`
+	StateDelta    map[string]any   `json:"stateDelta,omitempty"`
+	ArtifactDelta map[string]int64 `json:"artifactDelta,omitempty"`
+	Error         string           `json:"error,omitempty"`
+}
+
+func (l *runLauncher) print(sessionID string, result *runner.RunResult, runErr error) error {
+	if result == nil {
+		result = &runner.RunResult{}
+	}
+	if l.config.output == outputText {
+		if result.FinalText != "" {
+			fmt.Fprintln(l.out, result.FinalText)
+		}
+		return nil
+	}
+	out := jsonOutput{
+		SessionID:     sessionID,
+		FinalText:     result.FinalText,
+		Events:        result.Events,
+		StateDelta:    result.StateDelta,
+		ArtifactDelta: result.ArtifactDelta,
+	}
+	if out.Events == nil {
+		out.Events = []*session.Event{}
+	}
+	if runErr != nil {
+		out.Error = runErr.Error()
+	}
+	enc := json.NewEncoder(l.out)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return fmt.Errorf("failed to print the result: %v", err)
+	}
+	return nil
+}
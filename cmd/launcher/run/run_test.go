@@ -0,0 +1,136 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package run
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/cmd/launcher"
+	"google.golang.org/adk/internal/testutil"
+)
+
+func newTestLauncher(t *testing.T, stdin string, responses ...string) (*runLauncher, *launcher.Config, *testutil.MockModel, *strings.Builder) {
+	t.Helper()
+	m := &testutil.MockModel{}
+	for _, r := range responses {
+		m.Responses = append(m.Responses, genai.NewContentFromText(r, genai.RoleModel))
+	}
+	a, err := llmagent.New(llmagent.Config{Name: "agent", Model: m, OutputKey: "answer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := NewLauncher().(*runLauncher)
+	out := &strings.Builder{}
+	l.in = strings.NewReader(stdin)
+	l.out = out
+	return l, &launcher.Config{AgentLoader: agent.NewSingleLoader(a)}, m, out
+}
+
+func userText(m *testutil.MockModel) string {
+	req := m.Requests[len(m.Requests)-1]
+	return req.Contents[len(req.Contents)-1].Parts[0].Text
+}
+
+func TestRun_Text(t *testing.T) {
+	promptFile := filepath.Join(t.TempDir(), "prompt.txt")
+	if err := os.WriteFile(promptFile, []byte("prompt from file\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantPrompt string
+	}{
+		{name: "arguments", args: []string{"what", "is", "adk"}, wantPrompt: "what is adk"},
+		{name: "file", args: []string{"-prompt_file", promptFile}, wantPrompt: "prompt from file"},
+		{name: "stdin", stdin: "prompt from stdin\n", wantPrompt: "prompt from stdin"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			l, config, m, out := newTestLauncher(t, tc.stdin, "the answer")
+			if err := l.Execute(t.Context(), config, tc.args); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if got := userText(m); got != tc.wantPrompt {
+				t.Errorf("prompt = %q, want %q", got, tc.wantPrompt)
+			}
+			if diff := cmp.Diff("the answer\n", out.String()); diff != "" {
+				t.Errorf("output mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRun_JSON(t *testing.T) {
+	l, config, _, out := newTestLauncher(t, "", "the answer")
+	if err := l.Execute(t.Context(), config, []string{"-output", "json", "question"}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	var got jsonOutput
+	if err := json.Unmarshal([]byte(out.String()), &got); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out.String())
+	}
+	if got.FinalText != "the answer" {
+		t.Errorf("FinalText = %q, want %q", got.FinalText, "the answer")
+	}
+	if got.SessionID == "" {
+		t.Errorf("SessionID is empty")
+	}
+	if len(got.Events) != 1 {
+		t.Errorf("got %d events, want 1", len(got.Events))
+	}
+	if diff := cmp.Diff(map[string]any{"answer": "the answer"}, got.StateDelta); diff != "" {
+		t.Errorf("StateDelta mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	t.Run("agent failure", func(t *testing.T) {
+		// The model has no response.
+		l, config, _, out := newTestLauncher(t, "")
+		err := l.Execute(t.Context(), config, []string{"-output", "json", "question"})
+		if err == nil {
+			t.Fatal("Execute() succeeded, want error")
+		}
+		var got jsonOutput
+		if err := json.Unmarshal([]byte(out.String()), &got); err != nil {
+			t.Fatalf("output is not JSON: %v\n%s", err, out.String())
+		}
+		if got.Error == "" {
+			t.Errorf("output has no error")
+		}
+	})
+	t.Run("empty prompt", func(t *testing.T) {
+		l, config, _, _ := newTestLauncher(t, "  \n", "the answer")
+		if err := l.Execute(t.Context(), config, nil); err == nil {
+			t.Fatal("Execute() succeeded, want error")
+		}
+	})
+	t.Run("invalid output", func(t *testing.T) {
+		l, config, _, _ := newTestLauncher(t, "", "the answer")
+		if err := l.Execute(t.Context(), config, []string{"-output", "xml", "question"}); err == nil {
+			t.Fatal("Execute() succeeded, want error")
+		}
+	})
+}
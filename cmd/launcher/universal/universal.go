@@ -23,6 +23,8 @@ import (
 	"strings"
 
 	"google.golang.org/adk/cmd/launcher"
+	"google.golang.org/adk/internal/logging"
+	"google.golang.org/adk/telemetry"
 )
 
 // uniLauncher contains information about sublaunchers
@@ -62,6 +64,12 @@ func (l *uniLauncher) ParseAndRun(ctx context.Context, config *launcher.Config,
 
 // run executes the chosen sublauncher.
 func (l *uniLauncher) run(ctx context.Context, config *launcher.Config) error {
+	if config.TracerProvider != nil {
+		telemetry.SetTracerProvider(config.TracerProvider)
+	}
+	if config.Logger != nil {
+		ctx = logging.ToContext(ctx, config.Logger)
+	}
 	return l.chosenLauncher.Run(ctx, config)
 }
 
@@ -16,27 +16,51 @@
 package a2a
 
 import (
+	"bytes"
 	"flag"
 	"fmt"
+	"net/http"
 	"net/url"
+	"os"
+	"slices"
+	"strings"
 
 	a2acore "github.com/a2aproject/a2a-go/a2a"
 	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/a2aproject/a2a-go/a2asrv/push"
 	"github.com/gorilla/mux"
+	gormsqlite "gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 
 	"google.golang.org/adk/cmd/launcher"
 	"google.golang.org/adk/cmd/launcher/web"
 	"google.golang.org/adk/internal/cli/util"
 	"google.golang.org/adk/runner"
 	"google.golang.org/adk/server/adka2a"
+	"google.golang.org/adk/server/adka2a/taskstore"
 )
 
 // apiPath is a suffix used to build an A2A invocation URL
 const apiPath = "/a2a/invoke"
 
+// restPath is the prefix of the A2A HTTP+JSON transport paths.
+const restPath = "/a2a/rest"
+
 // a2aConfig contains parameters for launching ADK A2A server
 type a2aConfig struct {
 	agentURL string // user-provided url which will be used in the agent card to specify url for invoking A2A
+	// pushNotifications enables the delivery of task updates to the webhooks registered by the clients.
+	pushNotifications bool
+	// pushSigningKeyFile is the path of the key used to sign the push notifications.
+	pushSigningKeyFile string
+	// pushAllowedHosts are the webhook hosts reachable whatever their
+	// addresses, e.g. internal services.
+	pushAllowedHosts string
+	// pushAllowHTTP allows webhooks served over plain HTTP.
+	pushAllowHTTP bool
+	// taskDB is the path of the SQLite file persisting the tasks.
+	taskDB string
 }
 
 type a2aLauncher struct {
@@ -51,6 +75,11 @@ func NewLauncher() web.Sublauncher {
 	fs := flag.NewFlagSet("a2a", flag.ContinueOnError)
 
 	fs.StringVar(&config.agentURL, "a2a_agent_url", "http://localhost:8080", "A2A host URL as advertised in the public agent card. It is used by A2A clients as a connection endpoint.")
+	fs.StringVar(&config.taskDB, "a2a_task_db", "", "Path of a SQLite file persisting the A2A tasks, so that they can be fetched and resubscribed to after the executions end or the server restarts. If empty, tasks are kept in memory.")
+	fs.BoolVar(&config.pushNotifications, "a2a_push_notifications", false, "Enables A2A push notifications: the task updates are posted to the webhooks registered by the clients.")
+	fs.StringVar(&config.pushSigningKeyFile, "a2a_push_signing_key_file", "", "Path of a file holding the HMAC key used to sign the push notifications in the "+adka2a.PushSignatureHeader+" header. If empty, the notifications are not signed.")
+	fs.StringVar(&config.pushAllowedHosts, "a2a_push_allowed_hosts", "", "Comma-separated webhook hosts allowed even if they resolve to loopback, private or link-local addresses, which are rejected by default.")
+	fs.BoolVar(&config.pushAllowHTTP, "a2a_push_allow_http", false, "Allows webhooks served over plain HTTP. By default, only HTTPS webhooks are accepted.")
 
 	return &a2aLauncher{
 		config: config,
@@ -83,19 +112,26 @@ func (a *a2aLauncher) SetupSubrouters(router *mux.Router, config *launcher.Confi
 	if err != nil {
 		return err
 	}
+	restURL, err := url.JoinPath(a.config.agentURL, restPath)
+	if err != nil {
+		return err
+	}
 
 	rootAgent := config.AgentLoader.RootAgent()
-	agentCard := &a2acore.AgentCard{
-		Name:                              rootAgent.Name(),
-		Description:                       rootAgent.Description(),
-		DefaultInputModes:                 []string{"text/plain"},
-		DefaultOutputModes:                []string{"text/plain"},
-		URL:                               publicURL,
-		PreferredTransport:                a2acore.TransportProtocolJSONRPC,
-		Skills:                            adka2a.BuildAgentSkills(rootAgent),
-		Capabilities:                      a2acore.AgentCapabilities{Streaming: true},
-		SupportsAuthenticatedExtendedCard: false,
+	cardConfig := config.A2AAgentCard
+	if cardConfig.URL == "" {
+		cardConfig.URL = publicURL
 	}
+	if cardConfig.AdditionalInterfaces == nil {
+		cardConfig.AdditionalInterfaces = []a2acore.AgentInterface{
+			{URL: publicURL, Transport: a2acore.TransportProtocolJSONRPC},
+			{URL: restURL, Transport: a2acore.TransportProtocolHTTPJSON},
+		}
+	}
+	if cardConfig.Capabilities == nil {
+		cardConfig.Capabilities = &a2acore.AgentCapabilities{Streaming: true, PushNotifications: a.config.pushNotifications}
+	}
+	agentCard := adka2a.BuildAgentCard(rootAgent, cardConfig)
 	router.Handle(a2asrv.WellKnownAgentCardPath, a2asrv.NewStaticAgentCardHandler(agentCard))
 
 	agent := config.AgentLoader.RootAgent()
@@ -107,17 +143,66 @@ func (a *a2aLauncher) SetupSubrouters(router *mux.Router, config *launcher.Confi
 			ArtifactService: config.ArtifactService,
 		},
 	})
-	reqHandler := a2asrv.NewHandler(executor, config.A2AOptions...)
+	options := slices.Clone(config.A2AOptions)
+	if a.config.pushNotifications {
+		senderConfig := adka2a.PushSenderConfig{AllowHTTP: a.config.pushAllowHTTP}
+		if a.config.pushAllowedHosts != "" {
+			hosts := strings.Split(a.config.pushAllowedHosts, ",")
+			senderConfig.AllowedHosts = func(host string) bool {
+				return slices.ContainsFunc(hosts, func(h string) bool { return strings.EqualFold(strings.TrimSpace(h), host) })
+			}
+		}
+		if a.config.pushSigningKeyFile != "" {
+			key, err := os.ReadFile(a.config.pushSigningKeyFile)
+			if err != nil {
+				return fmt.Errorf("failed to read the push signing key: %w", err)
+			}
+			senderConfig.SigningKey = bytes.TrimSpace(key)
+		}
+		options = append(options, a2asrv.WithPushNotifications(adka2a.NewPushConfigStore(push.NewInMemoryStore(), senderConfig), adka2a.NewPushSender(senderConfig)))
+	}
+	var store a2asrv.TaskStore
+	if a.config.taskDB != "" {
+		store, err = openTaskStore(a.config.taskDB)
+		if err != nil {
+			return err
+		}
+		options = append(options, a2asrv.WithTaskStore(store))
+	}
+	reqHandler := a2asrv.NewHandler(executor, options...)
+	if store != nil {
+		reqHandler = adka2a.WithStoredTasks(reqHandler, store)
+	}
 	router.Handle(apiPath, a2asrv.NewJSONRPCHandler(reqHandler))
+	router.PathPrefix(restPath + "/").Handler(http.StripPrefix(restPath, adka2a.NewRESTHandler(reqHandler)))
 	return nil
 }
 
+// openTaskStore opens the SQLite task store at path, creating it if needed.
+func openTaskStore(path string) (a2asrv.TaskStore, error) {
+	query := url.Values{}
+	query.Set("_journal_mode", "WAL")
+	query.Set("_busy_timeout", "5000")
+	store, err := taskstore.NewDatabaseStore(
+		gormsqlite.Open("file:"+path+"?"+query.Encode()),
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent)},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open task database: %w", err)
+	}
+	if err := taskstore.AutoMigrate(store); err != nil {
+		return nil, fmt.Errorf("failed to migrate task database: %w", err)
+	}
+	return store, nil
+}
+
 // SimpleDescription implements web.Sublauncher
 func (a *a2aLauncher) SimpleDescription() string {
-	return fmt.Sprintf("starts A2A server which handles jsonrpc requests on %s path", apiPath)
+	return fmt.Sprintf("starts A2A server which handles jsonrpc requests on %s path and HTTP+JSON requests under %s", apiPath, restPath)
 }
 
 // UserMessage implements web.Sublauncher.
 func (a *a2aLauncher) UserMessage(webUrl string, printer func(v ...any)) {
 	printer(fmt.Sprintf("       a2a:  you can access A2A using jsonrpc protocol: %s", webUrl))
+	printer(fmt.Sprintf("       a2a:  you can access A2A using HTTP+JSON protocol: %s%s", webUrl, restPath))
 }
@@ -24,6 +24,7 @@ import (
 	a2acore "github.com/a2aproject/a2a-go/a2a"
 	"github.com/a2aproject/a2a-go/a2aclient"
 	"github.com/a2aproject/a2a-go/a2aclient/agentcard"
+	"github.com/google/go-cmp/cmp"
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
@@ -104,6 +105,14 @@ func TestWebLauncher_ServesA2A(t *testing.T) {
 		}
 	}
 
+	wantInterfaces := []a2acore.AgentInterface{
+		{URL: "http://localhost:" + strconv.Itoa(port) + apiPath, Transport: a2acore.TransportProtocolJSONRPC},
+		{URL: "http://localhost:" + strconv.Itoa(port) + restPath, Transport: a2acore.TransportProtocolHTTPJSON},
+	}
+	if diff := cmp.Diff(wantInterfaces, card.AdditionalInterfaces); diff != "" {
+		t.Errorf("card.AdditionalInterfaces mismatch (-want +got):\n%s", diff)
+	}
+
 	client, err := a2aclient.NewFromCard(ctx, card)
 	if err != nil {
 		t.Fatalf("a2aclient.NewFromCard() error = %v", err)
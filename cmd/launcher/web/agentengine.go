@@ -0,0 +1,50 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"context"
+
+	"google.golang.org/adk/memory"
+	memoryvertexai "google.golang.org/adk/memory/vertexai"
+	"google.golang.org/adk/session"
+	sessionvertexai "google.golang.org/adk/session/vertexai"
+)
+
+// agentEngineServices returns the session and memory services backed by the
+// Agent Engine.
+func agentEngineServices(ctx context.Context, engine string) (session.Service, memory.Service, error) {
+	name, project, location, err := sessionvertexai.ResolveAgentEngine(engine)
+	if err != nil {
+		return nil, nil, err
+	}
+	sessionService, err := sessionvertexai.NewSessionService(ctx, sessionvertexai.Config{
+		ProjectID:     project,
+		Location:      location,
+		AgentEngineID: name,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	memoryService, err := memoryvertexai.NewMemoryService(ctx, memoryvertexai.Config{
+		ProjectID:     project,
+		Location:      location,
+		AgentEngineID: name,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return sessionService, memoryService, nil
+}
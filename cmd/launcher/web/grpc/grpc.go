@@ -0,0 +1,83 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package grpc provides a sublauncher that adds the ADK gRPC API.
+package grpc
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"google.golang.org/adk/cmd/launcher"
+	"google.golang.org/adk/cmd/launcher/web"
+	"google.golang.org/adk/internal/cli/util"
+	"google.golang.org/adk/server/adkgrpc"
+	"google.golang.org/adk/server/adkgrpc/adkpb"
+)
+
+// grpcLauncher can launch the ADK gRPC API
+type grpcLauncher struct {
+	flags *flag.FlagSet
+}
+
+// NewLauncher creates new grpc launcher. It extends Web launcher
+func NewLauncher() web.Sublauncher {
+	return &grpcLauncher{flags: flag.NewFlagSet("grpc", flag.ContinueOnError)}
+}
+
+// CommandLineSyntax implements web.Sublauncher.
+func (g *grpcLauncher) CommandLineSyntax() string {
+	return util.FormatFlagUsage(g.flags)
+}
+
+// Keyword implements web.Sublauncher. Returns the command-line keyword for the gRPC launcher.
+func (g *grpcLauncher) Keyword() string {
+	return "grpc"
+}
+
+// Parse parses the command-line arguments for the gRPC launcher.
+func (g *grpcLauncher) Parse(args []string) ([]string, error) {
+	err := g.flags.Parse(args)
+	if err != nil || !g.flags.Parsed() {
+		return nil, fmt.Errorf("failed to parse grpc flags: %v", err)
+	}
+	return g.flags.Args(), nil
+}
+
+// SimpleDescription implements web.Sublauncher.
+func (g *grpcLauncher) SimpleDescription() string {
+	return "starts ADK gRPC API server on the port of the web server"
+}
+
+// SetupSubrouters implements web.Sublauncher. It routes the gRPC requests,
+// sent over HTTP/2 with or without TLS, to the gRPC server.
+func (g *grpcLauncher) SetupSubrouters(router *mux.Router, config *launcher.Config) error {
+	server := adkgrpc.NewServer(config)
+	router.Methods("POST").
+		PathPrefix("/" + adkpb.AgentService_ServiceDesc.ServiceName + "/").
+		MatcherFunc(func(r *http.Request, _ *mux.RouteMatch) bool {
+			return r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc")
+		}).
+		Handler(server)
+	return nil
+}
+
+// UserMessage implements web.Sublauncher.
+func (g *grpcLauncher) UserMessage(webURL string, printer func(v ...any)) {
+	printer(fmt.Sprintf("      grpc:  you can access the %s gRPC service on %s", adkpb.AgentService_ServiceDesc.ServiceName, strings.TrimPrefix(strings.TrimPrefix(webURL, "http://"), "https://")))
+}
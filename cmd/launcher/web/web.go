@@ -17,19 +17,31 @@ package web
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/a2aproject/a2a-go/a2asrv"
 	"github.com/gorilla/mux"
 
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/remoteagent"
 	"google.golang.org/adk/cmd/launcher"
 	"google.golang.org/adk/cmd/launcher/universal"
 	"google.golang.org/adk/internal/cli/util"
+	"google.golang.org/adk/server/httpauth"
 	"google.golang.org/adk/session"
+	"google.golang.org/adk/session/sqlite"
+	"google.golang.org/adk/telemetry"
 )
 
 // webConfig contains parameters for launching web server
@@ -38,6 +50,34 @@ type webConfig struct {
 	writeTimeout time.Duration
 	readTimeout  time.Duration
 	idleTimeout  time.Duration
+	sessionDB    string
+	// agentEngine is the Vertex AI Agent Engine storing the sessions and
+	// the memories.
+	agentEngine string
+	metrics     bool
+	sessionTTL  time.Duration
+	// cleanupInterval is the period of the session cleanup job.
+	cleanupInterval time.Duration
+	// agentReloadInterval is the period of the checks of the agent
+	// definitions of a reloadable agent loader.
+	agentReloadInterval time.Duration
+	// remoteAgents are the remote A2A agents served as apps.
+	remoteAgents []remoteagent.A2AConfig
+
+	apiKeysFile     string
+	apiKeyHeader    string
+	oidcIssuer      string
+	oidcAudience    string
+	oidcUserClaim   string
+	oidcRolesClaim  string
+	adminRoles      string
+	authExemptPaths string
+
+	// shutdownTimeout bounds the time given to the requests in flight to
+	// complete when the server stops.
+	shutdownTimeout time.Duration
+	tlsCertFile     string
+	tlsKeyFile      string
 }
 
 // webLauncher can launch web server
@@ -145,11 +185,63 @@ func (w *webLauncher) Parse(args []string) ([]string, error) {
 
 // Run implements launcher.SubLauncher.
 func (w *webLauncher) Run(ctx context.Context, config *launcher.Config) error {
+	if (w.config.tlsCertFile == "") != (w.config.tlsKeyFile == "") {
+		return fmt.Errorf("-tls-cert-file and -tls-key-file must be set together")
+	}
+
+	if w.config.agentEngine != "" && (config.SessionService == nil || config.MemoryService == nil) {
+		sessionService, memoryService, err := agentEngineServices(ctx, w.config.agentEngine)
+		if err != nil {
+			return fmt.Errorf("failed to connect to the agent engine: %v", err)
+		}
+		if config.SessionService == nil {
+			config.SessionService = sessionService
+		}
+		if config.MemoryService == nil {
+			config.MemoryService = memoryService
+		}
+	}
+	if config.SessionService == nil && w.config.sessionDB != "" {
+		sessionService, err := sqlite.NewSessionService(w.config.sessionDB)
+		if err != nil {
+			return fmt.Errorf("failed to open session database: %v", err)
+		}
+		defer sqlite.Close(sessionService)
+		config.SessionService = sessionService
+	}
 	if config.SessionService == nil {
 		config.SessionService = session.InMemoryService()
 	}
+	config.RouteAppServices()
+	if w.config.sessionTTL > 0 {
+		if w.config.cleanupInterval <= 0 {
+			return fmt.Errorf("invalid session cleanup interval %v", w.config.cleanupInterval)
+		}
+		cleanupCtx, stopCleanup := context.WithCancel(ctx)
+		defer stopCleanup()
+		go session.RunCleanup(cleanupCtx, config.SessionService, w.config.sessionTTL, w.config.cleanupInterval)
+	}
 
+	if loader, ok := config.AgentLoader.(agent.ReloadableLoader); ok && w.config.agentReloadInterval > 0 {
+		watchCtx, stopWatch := context.WithCancel(ctx)
+		defer stopWatch()
+		go loader.Watch(watchCtx, w.config.agentReloadInterval)
+	}
+	if len(w.config.remoteAgents) > 0 {
+		loader, err := remoteagent.NewA2ALoader(config.AgentLoader, w.config.remoteAgents...)
+		if err != nil {
+			return fmt.Errorf("failed to load remote agents: %w", err)
+		}
+		config.AgentLoader = loader
+	}
+
+	if config.Authorizer == nil && w.config.adminRoles != "" {
+		config.Authorizer = httpauth.UserAuthorizer(strings.Split(w.config.adminRoles, ",")...)
+	}
 	router := BuildBaseRouter()
+	if w.config.metrics {
+		router.Methods("GET").Path("/metrics").Handler(telemetry.MetricsHandler())
+	}
 
 	// check if there are any active sublaunchers
 	if len(w.activeSublaunchers) == 0 {
@@ -167,28 +259,93 @@ func (w *webLauncher) Run(ctx context.Context, config *launcher.Config) error {
 		}
 	}
 
+	var handler http.Handler = router
+	authenticators, err := w.authenticators()
+	if err != nil {
+		return err
+	}
+	if len(authenticators) > 0 {
+		handler = httpauth.Middleware(httpauth.Config{
+			Authenticators: authenticators,
+			ExemptPaths:    strings.Split(w.config.authExemptPaths, ","),
+		})(router)
+	}
+
 	log.Printf("Starting the web server: %+v", w.config)
 	log.Println()
-	webUrl := fmt.Sprintf("http://localhost:%v", fmt.Sprint(w.config.port))
+	scheme := "http"
+	if usesTLS(config.TLSConfig, w.config.tlsCertFile) {
+		scheme = "https"
+	}
+	webUrl := fmt.Sprintf("%s://localhost:%v", scheme, fmt.Sprint(w.config.port))
 	log.Printf("Web servers starts on %s", webUrl)
 	for _, l := range w.activeSublaunchers {
 		l.UserMessage(webUrl, log.Println)
 	}
 	log.Println()
 
-	srv := http.Server{
+	srv := &http.Server{
 		Addr:         fmt.Sprintf(":%v", fmt.Sprint(w.config.port)),
 		WriteTimeout: w.config.writeTimeout,
 		ReadTimeout:  w.config.readTimeout,
 		IdleTimeout:  w.config.idleTimeout,
-		Handler:      router,
+		Handler:      handler,
+		TLSConfig:    config.TLSConfig,
+		// The requests inherit the logger of the launcher context but are
+		// not cancelled with it, so they can complete during the shutdown.
+		BaseContext: func(net.Listener) context.Context { return context.WithoutCancel(ctx) },
 	}
+	// HTTP/2 is also accepted without TLS (h2c), for the gRPC clients.
+	srv.Protocols = new(http.Protocols)
+	srv.Protocols.SetHTTP1(true)
+	srv.Protocols.SetHTTP2(true)
+	srv.Protocols.SetUnencryptedHTTP2(true)
 
-	err := srv.ListenAndServe()
+	l, err := net.Listen("tcp", srv.Addr)
 	if err != nil {
-		return fmt.Errorf("server failed: %v", err)
+		return fmt.Errorf("failed to listen on %s: %w", srv.Addr, err)
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return serve(ctx, srv, l, w.config.tlsCertFile, w.config.tlsKeyFile, w.config.shutdownTimeout)
+}
+
+// serve runs srv on l until ctx is done and then shuts it down gracefully. The
+// requests still in flight after shutdownTimeout, e.g. the SSE streams, are
+// interrupted. The server uses TLS if certFile and keyFile or the TLS config
+// of srv provide a certificate.
+func serve(ctx context.Context, srv *http.Server, l net.Listener, certFile, keyFile string, shutdownTimeout time.Duration) error {
+	errc := make(chan error, 1)
+	go func() {
+		if usesTLS(srv.TLSConfig, certFile) {
+			errc <- srv.ServeTLS(l, certFile, keyFile)
+		} else {
+			errc <- srv.Serve(l)
+		}
+	}()
+
+	select {
+	case err := <-errc:
+		return fmt.Errorf("server failed: %w", err)
+	case <-ctx.Done():
 	}
 
+	log.Printf("Shutting down the web server")
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		// The remaining connections are closed forcibly.
+		if cerr := srv.Close(); cerr != nil {
+			return fmt.Errorf("failed to close the server: %w", cerr)
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("failed to shut down the server: %w", err)
+		}
+		log.Printf("Interrupted the requests still running after %v", shutdownTimeout)
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("server failed: %w", err)
+	}
 	return nil
 }
 
@@ -207,6 +364,31 @@ func NewLauncher(sublaunchers ...Sublauncher) launcher.SubLauncher {
 	fs.DurationVar(&config.writeTimeout, "write-timeout", 15*time.Second, "Server write timeout (i.e. '10s', '2m' - see time.ParseDuration for details) - for writing the response after reading the headers & body")
 	fs.DurationVar(&config.readTimeout, "read-timeout", 15*time.Second, "Server read timeout (i.e. '10s', '2m' - see time.ParseDuration for details) - for reading the whole request including body")
 	fs.DurationVar(&config.idleTimeout, "idle-timeout", 60*time.Second, "Server idle timeout (i.e. '10s', '2m' - see time.ParseDuration for details) - for waiting for the next request (only when keep-alive is enabled)")
+	fs.DurationVar(&config.shutdownTimeout, "shutdown-timeout", 10*time.Second, "Time given to the requests in flight, e.g. the event streams, to complete when the server stops (i.e. '10s', '2m' - see time.ParseDuration for details)")
+	fs.StringVar(&config.tlsCertFile, "tls-cert-file", "", "Path of the PEM certificate of the server. If set with -tls-key-file, the server is served over HTTPS")
+	fs.StringVar(&config.tlsKeyFile, "tls-key-file", "", "Path of the PEM private key matching -tls-cert-file")
+	fs.BoolVar(&config.metrics, "metrics", false, "Serves the agent metrics in the Prometheus text format on /metrics")
+	fs.DurationVar(&config.sessionTTL, "session-ttl", 0, "Sessions not updated for this duration (i.e. '24h' - see time.ParseDuration for details) are deleted by a background job. If zero, sessions are never deleted")
+	fs.DurationVar(&config.cleanupInterval, "session-cleanup-interval", time.Hour, "Interval between two runs of the session cleanup job, used only if -session-ttl is set")
+	fs.DurationVar(&config.agentReloadInterval, "agent-reload-interval", 2*time.Second, "Interval between two checks of the agent definitions, used only if the agents are declarative. The agents are reloaded when their definitions change. If zero, the agents are only reloaded with the /reload-apps endpoint of the API, which is refused unless the request is authenticated as an admin")
+	fs.Func("remote-a2a-agent", "Serves a remote A2A agent as an app, given as '<app_name>=<agent_card_url>'. The sessions and the files of the app are stored by the local services. Can be repeated", func(s string) error {
+		name, card, ok := strings.Cut(s, "=")
+		if !ok || name == "" || card == "" {
+			return fmt.Errorf("invalid remote agent %q, want <app_name>=<agent_card_url>", s)
+		}
+		config.remoteAgents = append(config.remoteAgents, remoteagent.A2AConfig{Name: name, AgentCardSource: card})
+		return nil
+	})
+	fs.StringVar(&config.sessionDB, "session-db", "", "Path of a SQLite file persisting the sessions between restarts. If empty, sessions are kept in memory. Ignored if the session service is set in the launcher config")
+	fs.StringVar(&config.agentEngine, "agent-engine", "", "Vertex AI Agent Engine storing the sessions and the memories, as a resource name or as an ID with GOOGLE_CLOUD_PROJECT and GOOGLE_CLOUD_LOCATION set. Takes precedence over -session-db. Ignored for the services set in the launcher config")
+	fs.StringVar(&config.apiKeysFile, "auth-api-keys-file", "", "Path of a file listing the accepted API keys, one '<user_id> <api_key> [role,...]' entry per line. The requests authenticated with a key act as its user")
+	fs.StringVar(&config.apiKeyHeader, "auth-api-key-header", "X-API-Key", "Header carrying the API key")
+	fs.StringVar(&config.oidcIssuer, "auth-oidc-issuer", "", "Issuer of the OIDC tokens accepted as bearer tokens. The tokens' user acts as the user_id")
+	fs.StringVar(&config.oidcAudience, "auth-oidc-audience", "", "Audience of the OIDC tokens, required with -auth-oidc-issuer")
+	fs.StringVar(&config.oidcUserClaim, "auth-oidc-user-claim", "sub", "Claim of the OIDC tokens used as the user_id")
+	fs.StringVar(&config.oidcRolesClaim, "auth-oidc-roles-claim", "", "Claim of the OIDC tokens listing the roles of the user")
+	fs.StringVar(&config.adminRoles, "auth-admin-roles", "", "Comma-separated roles allowed to access the data of all users and to register or reload apps. Ignored if the authorizer is set in the launcher config")
+	fs.StringVar(&config.authExemptPaths, "auth-exempt-paths", "/,/ui/,"+a2asrv.WellKnownAgentCardPath, "Comma-separated paths served without authentication. A path ending with '/' exempts all the paths it prefixes")
 
 	return &webLauncher{
 		config:       config,
@@ -215,6 +397,66 @@ func NewLauncher(sublaunchers ...Sublauncher) launcher.SubLauncher {
 	}
 }
 
+// authenticators returns the authenticators configured by the flags.
+func (w *webLauncher) authenticators() ([]httpauth.Authenticator, error) {
+	var authenticators []httpauth.Authenticator
+	if w.config.apiKeysFile != "" {
+		keys, err := readAPIKeys(w.config.apiKeysFile)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, httpauth.APIKeys(w.config.apiKeyHeader, keys))
+	}
+	if w.config.oidcIssuer != "" {
+		oidc, err := httpauth.NewOIDC(httpauth.OIDCConfig{
+			Issuer:     w.config.oidcIssuer,
+			Audience:   w.config.oidcAudience,
+			UserClaim:  w.config.oidcUserClaim,
+			RolesClaim: w.config.oidcRolesClaim,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid OIDC configuration: %w", err)
+		}
+		authenticators = append(authenticators, oidc)
+	}
+	return authenticators, nil
+}
+
+// readAPIKeys reads a file of '<user_id> <api_key> [role,...]' lines and
+// returns the principals by API key. Empty lines and lines starting with '#'
+// are ignored.
+func readAPIKeys(path string) (map[string]*httpauth.Principal, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the API keys: %w", err)
+	}
+	keys := make(map[string]*httpauth.Principal)
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 && len(fields) != 3 {
+			return nil, fmt.Errorf("%s:%d: want '<user_id> <api_key> [role,...]'", path, i+1)
+		}
+		p := &httpauth.Principal{Subject: fields[0]}
+		if len(fields) == 3 {
+			p.Roles = strings.Split(fields[2], ",")
+		}
+		keys[fields[1]] = p
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no API key found in %s", path)
+	}
+	return keys, nil
+}
+
+// usesTLS reports whether the server has a certificate to serve HTTPS.
+func usesTLS(cfg *tls.Config, certFile string) bool {
+	return certFile != "" || cfg != nil && (len(cfg.Certificates) > 0 || cfg.GetCertificate != nil || cfg.GetConfigForClient != nil)
+}
+
 // logger is a middleware that logs the HTTP method, request URI, and the time taken to process the request.
 func logger(inner http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
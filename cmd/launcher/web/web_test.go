@@ -0,0 +1,103 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestServe_DrainsRequests(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		shutdownTimeout time.Duration
+		release         bool
+		wantBody        string
+	}{
+		{name: "completed", shutdownTimeout: time.Minute, release: true, wantBody: "done"},
+		{name: "interrupted", shutdownTimeout: 10 * time.Millisecond},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			started := make(chan struct{})
+			release := make(chan struct{})
+			srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				close(started)
+				select {
+				case <-release:
+					io.WriteString(w, "done")
+				case <-r.Context().Done():
+				}
+			})}
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("net.Listen() failed: %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(t.Context())
+			served := make(chan error, 1)
+			go func() { served <- serve(ctx, srv, l, "", "", tc.shutdownTimeout) }()
+
+			type result struct {
+				body string
+				err  error
+			}
+			got := make(chan result, 1)
+			go func() {
+				resp, err := http.Get("http://" + l.Addr().String())
+				if err != nil {
+					got <- result{err: err}
+					return
+				}
+				defer resp.Body.Close()
+				body, err := io.ReadAll(resp.Body)
+				got <- result{body: string(body), err: err}
+			}()
+
+			<-started
+			cancel()
+			if tc.release {
+				// Let the shutdown start before completing the request.
+				time.Sleep(10 * time.Millisecond)
+				close(release)
+			}
+
+			if err := <-served; err != nil {
+				t.Errorf("serve() failed: %v", err)
+			}
+			res := <-got
+			if tc.wantBody != "" && (res.err != nil || res.body != tc.wantBody) {
+				t.Errorf("response = %q, %v, want %q", res.body, res.err, tc.wantBody)
+			}
+			if tc.wantBody == "" && res.err == nil && res.body != "" {
+				t.Errorf("response = %q, want interrupted request", res.body)
+			}
+		})
+	}
+}
+
+func TestServe_ListenError(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() failed: %v", err)
+	}
+	l.Close()
+	if err := serve(t.Context(), &http.Server{}, l, "", "", time.Second); err == nil {
+		t.Errorf("serve() succeeded, want error")
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package embedding defines the interface to compute text embeddings, used by
+// the semantic features of ADK such as memory search and retrieval.
+//
+// Package [google.golang.org/adk/embedding/gemini] provides an implementation
+// backed by the Gemini and Vertex AI embedding models.
+package embedding
+
+import (
+	"context"
+	"errors"
+	"math"
+)
+
+// TaskType tells the embedding model how the embeddings are going to be used,
+// so that it can optimize them.
+type TaskType string
+
+const (
+	// TaskTypeUnspecified lets the model use its default task type.
+	TaskTypeUnspecified TaskType = ""
+	// TaskTypeRetrievalDocument is used for the documents searched by a
+	// retrieval.
+	TaskTypeRetrievalDocument TaskType = "RETRIEVAL_DOCUMENT"
+	// TaskTypeRetrievalQuery is used for the queries of a retrieval.
+	TaskTypeRetrievalQuery TaskType = "RETRIEVAL_QUERY"
+	// TaskTypeSemanticSimilarity is used to compare texts with each other.
+	TaskTypeSemanticSimilarity TaskType = "SEMANTIC_SIMILARITY"
+	// TaskTypeClassification is used to classify texts.
+	TaskTypeClassification TaskType = "CLASSIFICATION"
+	// TaskTypeClustering is used to cluster texts.
+	TaskTypeClustering TaskType = "CLUSTERING"
+)
+
+// Embedder computes the embeddings of texts.
+type Embedder interface {
+	// Embed returns the embeddings of texts, in the same order. Implementations
+	// split the texts into batches as required by the underlying model.
+	Embed(ctx context.Context, texts []string, taskType TaskType) ([][]float32, error)
+}
+
+// ErrDimensionMismatch is returned when comparing embeddings of different
+// dimensions.
+var ErrDimensionMismatch = errors.New("embeddings have different dimensions")
+
+// CosineSimilarity returns the cosine similarity of a and b, between -1 and 1.
+// It returns 0 if one of the embeddings is a zero vector.
+func CosineSimilarity(a, b []float32) (float64, error) {
+	if len(a) != len(b) {
+		return 0, ErrDimensionMismatch
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0, nil
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB)), nil
+}
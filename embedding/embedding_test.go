@@ -0,0 +1,54 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embedding_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"google.golang.org/adk/embedding"
+)
+
+func TestCosineSimilarity(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		a, b []float32
+		want float64
+	}{
+		{name: "same direction", a: []float32{1, 2}, b: []float32{2, 4}, want: 1},
+		{name: "opposite", a: []float32{1, 0}, b: []float32{-3, 0}, want: -1},
+		{name: "orthogonal", a: []float32{1, 0}, b: []float32{0, 1}, want: 0},
+		{name: "zero vector", a: []float32{0, 0}, b: []float32{1, 1}, want: 0},
+		{name: "empty", want: 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := embedding.CosineSimilarity(tc.a, tc.b)
+			if err != nil {
+				t.Fatalf("CosineSimilarity() failed: %v", err)
+			}
+			if math.Abs(got-tc.want) > 1e-9 {
+				t.Errorf("CosineSimilarity() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCosineSimilarity_DimensionMismatch(t *testing.T) {
+	_, err := embedding.CosineSimilarity([]float32{1}, []float32{1, 2})
+	if !errors.Is(err, embedding.ErrDimensionMismatch) {
+		t.Errorf("CosineSimilarity() error = %v, want %v", err, embedding.ErrDimensionMismatch)
+	}
+}
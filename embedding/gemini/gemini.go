@@ -0,0 +1,109 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gemini implements the [embedding.Embedder] interface for the Gemini
+// and Vertex AI embedding models.
+package gemini
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/embedding"
+)
+
+const (
+	// DefaultModel is the embedding model used when none is configured.
+	DefaultModel = "gemini-embedding-001"
+	// DefaultBatchSize is the maximum number of texts embedded by a single
+	// request when none is configured.
+	DefaultBatchSize = 100
+)
+
+// Config defines the configuration of a Gemini embedder.
+type Config struct {
+	// Model is the embedding model, e.g. "gemini-embedding-001" or
+	// "text-embedding-005". If empty, DefaultModel is used.
+	Model string
+	// ClientConfig configures the genai client. If nil, the client is
+	// configured from the environment.
+	ClientConfig *genai.ClientConfig
+	// OutputDimensionality truncates the embeddings to the given number of
+	// dimensions. If zero, the model default is used.
+	OutputDimensionality int32
+	// BatchSize is the maximum number of texts embedded by a single request.
+	// Some Vertex AI models accept a single text per request, in which case
+	// it must be set to 1. If zero, DefaultBatchSize is used.
+	BatchSize int
+}
+
+// NewEmbedder returns an [embedding.Embedder] backed by the Gemini API or
+// Vertex AI, depending on the client configuration.
+func NewEmbedder(ctx context.Context, cfg Config) (embedding.Embedder, error) {
+	if cfg.OutputDimensionality < 0 {
+		return nil, fmt.Errorf("invalid output dimensionality %d", cfg.OutputDimensionality)
+	}
+	if cfg.BatchSize < 0 {
+		return nil, fmt.Errorf("invalid batch size %d", cfg.BatchSize)
+	}
+	client, err := genai.NewClient(ctx, cfg.ClientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create genai client: %w", err)
+	}
+	if cfg.Model == "" {
+		cfg.Model = DefaultModel
+	}
+	if cfg.BatchSize == 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	return &embedder{client: client, cfg: cfg}, nil
+}
+
+type embedder struct {
+	client *genai.Client
+	cfg    Config
+}
+
+// Embed implements embedding.Embedder.
+func (e *embedder) Embed(ctx context.Context, texts []string, taskType embedding.TaskType) ([][]float32, error) {
+	embedCfg := &genai.EmbedContentConfig{TaskType: string(taskType)}
+	if e.cfg.OutputDimensionality > 0 {
+		embedCfg.OutputDimensionality = genai.Ptr(e.cfg.OutputDimensionality)
+	}
+	res := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += e.cfg.BatchSize {
+		batch := texts[start:min(start+e.cfg.BatchSize, len(texts))]
+		contents := make([]*genai.Content, len(batch))
+		for i, text := range batch {
+			contents[i] = genai.NewContentFromText(text, genai.RoleUser)
+		}
+		resp, err := e.client.Models.EmbedContent(ctx, e.cfg.Model, contents, embedCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed texts: %w", err)
+		}
+		if len(resp.Embeddings) != len(batch) {
+			return nil, fmt.Errorf("got %d embeddings for %d texts", len(resp.Embeddings), len(batch))
+		}
+		for _, emb := range resp.Embeddings {
+			if emb == nil {
+				return nil, errors.New("missing embedding in response")
+			}
+			res = append(res, emb.Values)
+		}
+	}
+	return res, nil
+}
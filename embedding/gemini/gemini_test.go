@@ -0,0 +1,103 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gemini_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/embedding"
+	"google.golang.org/adk/embedding/gemini"
+)
+
+func TestEmbedder(t *testing.T) {
+	var gotPaths []string
+	var gotReqs []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPaths = append(gotPaths, r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		var req map[string]any
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		gotReqs = append(gotReqs, req)
+		requests, _ := req["requests"].([]any)
+		var embeddings []map[string]any
+		for i := range requests {
+			embeddings = append(embeddings, map[string]any{"values": []float32{float32(len(gotReqs)), float32(i)}})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"embeddings": embeddings})
+	}))
+	defer srv.Close()
+
+	e, err := gemini.NewEmbedder(t.Context(), gemini.Config{
+		Model: "embedding-test",
+		ClientConfig: &genai.ClientConfig{
+			APIKey:      "fakekey",
+			Backend:     genai.BackendGeminiAPI,
+			HTTPOptions: genai.HTTPOptions{BaseURL: srv.URL},
+		},
+		OutputDimensionality: 2,
+		BatchSize:            2,
+	})
+	if err != nil {
+		t.Fatalf("NewEmbedder() failed: %v", err)
+	}
+
+	got, err := e.Embed(t.Context(), []string{"a", "b", "c"}, embedding.TaskTypeRetrievalDocument)
+	if err != nil {
+		t.Fatalf("Embed() failed: %v", err)
+	}
+
+	want := [][]float32{{1, 0}, {1, 1}, {2, 0}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Embed() mismatch (-want +got):\n%s", diff)
+	}
+	wantPaths := []string{"/v1beta/models/embedding-test:batchEmbedContents", "/v1beta/models/embedding-test:batchEmbedContents"}
+	if diff := cmp.Diff(wantPaths, gotPaths); diff != "" {
+		t.Errorf("request paths mismatch (-want +got):\n%s", diff)
+	}
+	for _, req := range gotReqs {
+		for _, r := range req["requests"].([]any) {
+			r := r.(map[string]any)
+			if r["taskType"] != "RETRIEVAL_DOCUMENT" || r["outputDimensionality"] != float64(2) {
+				t.Errorf("request = %v, want taskType RETRIEVAL_DOCUMENT and outputDimensionality 2", r)
+			}
+		}
+	}
+}
+
+func TestNewEmbedder_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  gemini.Config
+	}{
+		{name: "negative dimensionality", cfg: gemini.Config{OutputDimensionality: -1}},
+		{name: "negative batch size", cfg: gemini.Config{BatchSize: -1}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := gemini.NewEmbedder(t.Context(), tc.cfg); err == nil {
+				t.Errorf("NewEmbedder() succeeded, want error")
+			}
+		})
+	}
+}
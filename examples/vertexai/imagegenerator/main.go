@@ -33,6 +33,7 @@ import (
 	"google.golang.org/adk/model/gemini"
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/adk/tool/imagentool"
 	"google.golang.org/adk/tool/loadartifactstool"
 )
 
@@ -44,12 +45,13 @@ func main() {
 		log.Fatalf("Failed to create model: %v", err)
 	}
 
-	generateImageTool, err := functiontool.New(
-		functiontool.Config{
-			Name:        "generate_image",
-			Description: "Generates image and saves in artifact service.",
+	generateImageTool, err := imagentool.New(ctx, imagentool.Config{
+		ClientConfig: &genai.ClientConfig{
+			Project:  os.Getenv("GOOGLE_CLOUD_PROJECT"),
+			Location: os.Getenv("GOOGLE_CLOUD_LOCATION"),
+			Backend:  genai.BackendVertexAI,
 		},
-		generateImage)
+	})
 	if err != nil {
 		log.Fatalf("Failed to create generate image tool: %v", err)
 	}
@@ -69,7 +71,7 @@ func main() {
 		Model:       model,
 		Description: "Agent to generate pictures, answers questions about it and saves it locally if asked.",
 		Instruction: "You are an agent whose job is to generate an image, describe it and save it locally if asked." +
-			" Also user will provide the filename and you should save it in the artifacts with that filename." +
+			" Also user will provide the filename and you should pass it to generate_image." +
 			" When user ask to save image locally you can call save_image_locally to do it.",
 		Tools: []tool.Tool{
 			loadartifactstool.New(), generateImageTool, saveImageTool,
@@ -90,43 +92,6 @@ func main() {
 	}
 }
 
-// This is a function tool to generate images using Vertex AI's Imagen model.
-func generateImage(ctx tool.Context, input generateImageInput) (generateImageResult, error) {
-	client, err := genai.NewClient(ctx, &genai.ClientConfig{
-		Project:  os.Getenv("GOOGLE_CLOUD_PROJECT"),
-		Location: os.Getenv("GOOGLE_CLOUD_LOCATION"),
-		Backend:  genai.BackendVertexAI,
-	})
-	if err != nil {
-		return generateImageResult{}, err
-	}
-
-	response, err := client.Models.GenerateImages(
-		ctx,
-		"imagen-3.0-generate-002",
-		input.Prompt,
-		&genai.GenerateImagesConfig{NumberOfImages: 1})
-	if err != nil {
-		return generateImageResult{}, err
-	}
-
-	_, err = ctx.Artifacts().Save(ctx, input.Filename, genai.NewPartFromBytes(response.GeneratedImages[0].Image.ImageBytes, "image/png"))
-	if err != nil {
-		return generateImageResult{}, err
-	}
-	return generateImageResult{Filename: input.Filename, Status: "success"}, nil
-}
-
-type generateImageInput struct {
-	Prompt   string `json:"prompt"`
-	Filename string `json:"filename"`
-}
-
-type generateImageResult struct {
-	Filename string `json:"filename"`
-	Status   string `json:"Status"`
-}
-
 // This is function tool that loads image from the artifacts service and
 // saves is to the local filesystem.
 func saveImage(ctx tool.Context, input saveImageInput) (saveImageResult, error) {
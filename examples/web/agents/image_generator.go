@@ -26,63 +26,18 @@ import (
 	"google.golang.org/adk/agent/llmagent"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/tool"
-	"google.golang.org/adk/tool/functiontool"
+	"google.golang.org/adk/tool/imagentool"
 	"google.golang.org/adk/tool/loadartifactstool"
 )
 
-func generateImage(ctx tool.Context, input generateImageInput) (generateImageResult, error) {
-	client, err := genai.NewClient(ctx, &genai.ClientConfig{
-		Project:  os.Getenv("GOOGLE_CLOUD_PROJECT"),
-		Location: os.Getenv("GOOGLE_CLOUD_LOCATION"),
-		Backend:  genai.BackendVertexAI,
-	})
-	if err != nil {
-		return generateImageResult{
-			Status: "fail",
-		}, nil
-	}
-
-	response, err := client.Models.GenerateImages(
-		ctx,
-		"imagen-3.0-generate-002",
-		input.Prompt,
-		&genai.GenerateImagesConfig{NumberOfImages: 1})
-	if err != nil {
-		return generateImageResult{
-			Status: "fail",
-		}, nil
-	}
-
-	_, err = ctx.Artifacts().Save(ctx, input.Filename, genai.NewPartFromBytes(response.GeneratedImages[0].Image.ImageBytes, "image/png"))
-	if err != nil {
-		return generateImageResult{
-			Status: "fail",
-		}, nil
-	}
-
-	return generateImageResult{
-		Status:   "success",
-		Filename: input.Filename,
-	}, nil
-}
-
-type generateImageInput struct {
-	Prompt   string `json:"prompt"`
-	Filename string `json:"filename"`
-}
-
-type generateImageResult struct {
-	Filename string `json:"filename"`
-	Status   string `json:"Status"`
-}
-
 func GetImageGeneratorAgent(ctx context.Context, model model.LLM) agent.Agent {
-	generateImageTool, err := functiontool.New(
-		functiontool.Config{
-			Name:        "generate_image",
-			Description: "Generates image and saves in artifact service.",
+	generateImageTool, err := imagentool.New(ctx, imagentool.Config{
+		ClientConfig: &genai.ClientConfig{
+			Project:  os.Getenv("GOOGLE_CLOUD_PROJECT"),
+			Location: os.Getenv("GOOGLE_CLOUD_LOCATION"),
+			Backend:  genai.BackendVertexAI,
 		},
-		generateImage)
+	})
 	if err != nil {
 		log.Fatalf("Failed to create generate image tool: %v", err)
 	}
@@ -0,0 +1,99 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package guardrail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	dlp "google.golang.org/api/dlp/v2"
+	"google.golang.org/api/option"
+)
+
+// DLPDetectorConfig defines the configuration of a Cloud DLP detector.
+type DLPDetectorConfig struct {
+	// Project is the Google Cloud project running the inspection.
+	Project string
+	// Location of the processing, e.g. "us-central1". If empty, "global" is
+	// used.
+	Location string
+	// InfoTypes are the DLP info types to detect, e.g. "EMAIL_ADDRESS" or
+	// "US_SOCIAL_SECURITY_NUMBER". If empty, the DLP default info types are
+	// detected.
+	InfoTypes []string
+	// MinLikelihood is the minimum likelihood of the findings, e.g.
+	// "POSSIBLE" or "LIKELY". If empty, the DLP default is used.
+	MinLikelihood string
+	// ClientOptions are passed to the underlying DLP client.
+	ClientOptions []option.ClientOption
+}
+
+// NewDLPDetector creates a detector inspecting texts with the Cloud Data
+// Loss Prevention API.
+func NewDLPDetector(ctx context.Context, cfg DLPDetectorConfig) (PIIDetector, error) {
+	if cfg.Project == "" {
+		return nil, errors.New("project is required")
+	}
+	location := cfg.Location
+	if location == "" {
+		location = "global"
+	}
+	svc, err := dlp.NewService(ctx, cfg.ClientOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DLP client: %w", err)
+	}
+	inspectConfig := &dlp.GooglePrivacyDlpV2InspectConfig{MinLikelihood: cfg.MinLikelihood}
+	for _, infoType := range cfg.InfoTypes {
+		inspectConfig.InfoTypes = append(inspectConfig.InfoTypes, &dlp.GooglePrivacyDlpV2InfoType{Name: infoType})
+	}
+	return &dlpDetector{
+		parent:        fmt.Sprintf("projects/%s/locations/%s", cfg.Project, location),
+		content:       dlp.NewProjectsLocationsContentService(svc),
+		inspectConfig: inspectConfig,
+	}, nil
+}
+
+type dlpDetector struct {
+	parent        string
+	content       *dlp.ProjectsLocationsContentService
+	inspectConfig *dlp.GooglePrivacyDlpV2InspectConfig
+}
+
+// Detect implements PIIDetector.
+func (d *dlpDetector) Detect(ctx context.Context, text string) ([]PIIFinding, error) {
+	resp, err := d.content.Inspect(d.parent, &dlp.GooglePrivacyDlpV2InspectContentRequest{
+		Item:          &dlp.GooglePrivacyDlpV2ContentItem{Value: text},
+		InspectConfig: d.inspectConfig,
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("DLP inspection failed: %w", err)
+	}
+	if resp.Result == nil {
+		return nil, nil
+	}
+	var findings []PIIFinding
+	for _, f := range resp.Result.Findings {
+		if f == nil || f.InfoType == nil || f.Location == nil || f.Location.ByteRange == nil {
+			continue
+		}
+		findings = append(findings, PIIFinding{
+			InfoType: f.InfoType.Name,
+			Start:    int(f.Location.ByteRange.Start),
+			End:      int(f.Location.ByteRange.End),
+		})
+	}
+	return findings, nil
+}
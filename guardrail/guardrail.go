@@ -0,0 +1,122 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package guardrail provides filters that check the content exchanged
+// between the user and the agents.
+//
+// Filters are registered on runner.Config. Input filters check every user
+// message before it reaches the agents, output filters check every event
+// produced by the agents before it is stored in the session and returned to
+// the caller. A filter can let the content through, rewrite it or block it.
+// Blocked content is replaced by an event with ErrorCode set to
+// [BlockedErrorCode].
+//
+// [PIIRedactor] redacts personal information, such as email addresses and
+// credit card numbers. Besides being a filter, it provides callbacks to
+// redact the model output and the tool results of specific agents.
+package guardrail
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"google.golang.org/genai"
+)
+
+// BlockedErrorCode is the error code of the events that replace blocked
+// content.
+const BlockedErrorCode = "GUARDRAIL_BLOCKED"
+
+// Result is the outcome of a filter check.
+type Result struct {
+	// Blocked reports whether the content must not be passed on.
+	Blocked bool
+	// Reason explains why the content was blocked. It is used as the error
+	// message of the event that replaces the content.
+	Reason string
+	// Content, if non-nil and the content is not blocked, replaces the
+	// checked content.
+	Content *genai.Content
+	// Metadata, if set and the content is not blocked, is added to the
+	// custom metadata of the events checked by output filters, e.g. to
+	// record how the content was rewritten.
+	Metadata map[string]any
+}
+
+// Filter checks content.
+type Filter interface {
+	// Name returns the name of the filter.
+	Name() string
+	// Check checks content. A nil result lets the content through unchanged.
+	Check(ctx context.Context, content *genai.Content) (*Result, error)
+}
+
+// FilterFunc is the signature of the function wrapped by [Func].
+type FilterFunc func(ctx context.Context, content *genai.Content) (*Result, error)
+
+// Func creates a filter from a function.
+func Func(name string, fn FilterFunc) Filter {
+	return &funcFilter{name: name, fn: fn}
+}
+
+type funcFilter struct {
+	name string
+	fn   FilterFunc
+}
+
+// Name implements Filter.
+func (f *funcFilter) Name() string {
+	return f.name
+}
+
+// Check implements Filter.
+func (f *funcFilter) Check(ctx context.Context, content *genai.Content) (*Result, error) {
+	return f.fn(ctx, content)
+}
+
+// Check runs filters in order. Each filter checks the content as rewritten by
+// the previous ones. It stops at the first filter that blocks the content.
+//
+// The returned result is never nil. If the content is not blocked, its
+// Content field holds the content to pass on.
+func Check(ctx context.Context, filters []Filter, content *genai.Content) (*Result, error) {
+	var metadata map[string]any
+	for _, f := range filters {
+		res, err := f.Check(ctx, content)
+		if err != nil {
+			return nil, fmt.Errorf("guardrail %q failed: %w", f.Name(), err)
+		}
+		if res == nil {
+			continue
+		}
+		if res.Blocked {
+			reason := res.Reason
+			if reason == "" {
+				reason = fmt.Sprintf("content blocked by guardrail %q", f.Name())
+			}
+			return &Result{Blocked: true, Reason: reason}, nil
+		}
+		if res.Content != nil {
+			content = res.Content
+		}
+		if len(res.Metadata) > 0 {
+			if metadata == nil {
+				metadata = map[string]any{}
+			}
+			maps.Copy(metadata, res.Metadata)
+		}
+	}
+	return &Result{Content: content, Metadata: metadata}, nil
+}
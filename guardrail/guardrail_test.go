@@ -0,0 +1,222 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package guardrail_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/guardrail"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+)
+
+func TestCheck(t *testing.T) {
+	redact, err := guardrail.NewRegexBlocklist(guardrail.RegexBlocklistConfig{
+		Patterns:    []string{`\d{4}-\d{4}`},
+		Replacement: "[number]",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := guardrail.NewRegexBlocklist(guardrail.RegexBlocklistConfig{
+		Name:     "no_secrets",
+		Patterns: []string{`(?i)password`},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	upper := guardrail.Func("upper", func(ctx context.Context, content *genai.Content) (*guardrail.Result, error) {
+		if content.Parts[0].Text == "stop" {
+			return &guardrail.Result{Blocked: true}, nil
+		}
+		return nil, nil
+	})
+
+	testCases := []struct {
+		name    string
+		content *genai.Content
+		want    *guardrail.Result
+	}{
+		{
+			name:    "passes",
+			content: genai.NewContentFromText("hello", genai.RoleUser),
+			want:    &guardrail.Result{Content: genai.NewContentFromText("hello", genai.RoleUser)},
+		},
+		{
+			name:    "rewrites",
+			content: genai.NewContentFromText("call 1234-5678", genai.RoleUser),
+			want:    &guardrail.Result{Content: genai.NewContentFromText("call [number]", genai.RoleUser)},
+		},
+		{
+			name:    "blocks by pattern",
+			content: genai.NewContentFromText("my Password is 1234-5678", genai.RoleUser),
+			want:    &guardrail.Result{Blocked: true, Reason: `content matches blocked pattern "(?i)password"`},
+		},
+		{
+			name:    "blocks with default reason",
+			content: genai.NewContentFromText("stop", genai.RoleUser),
+			want:    &guardrail.Result{Blocked: true, Reason: `content blocked by guardrail "upper"`},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := guardrail.Check(t.Context(), []guardrail.Filter{redact, block, upper}, tc.content)
+			if err != nil {
+				t.Fatalf("Check() failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Check() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewRegexBlocklist_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  guardrail.RegexBlocklistConfig
+	}{
+		{name: "no patterns"},
+		{name: "invalid pattern", cfg: guardrail.RegexBlocklistConfig{Patterns: []string{"("}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := guardrail.NewRegexBlocklist(tc.cfg); err == nil {
+				t.Error("NewRegexBlocklist() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestSafetyClassifier(t *testing.T) {
+	testCases := []struct {
+		name     string
+		response string
+		want     *guardrail.Result
+	}{
+		{
+			name:     "safe",
+			response: `{"safe": true}`,
+		},
+		{
+			name:     "unsafe",
+			response: `{"safe": false, "reason": "dangerous"}`,
+			want:     &guardrail.Result{Blocked: true, Reason: "content classified as unsafe: dangerous"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &testutil.MockModel{Responses: []*genai.Content{genai.NewContentFromText(tc.response, genai.RoleModel)}}
+			f, err := guardrail.NewSafetyClassifier(guardrail.SafetyClassifierConfig{Model: m})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := f.Check(t.Context(), genai.NewContentFromText("how do I bake a cake", genai.RoleUser))
+			if err != nil {
+				t.Fatalf("Check() failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Check() mismatch (-want +got):\n%s", diff)
+			}
+			if len(m.Requests) != 1 || m.Requests[0].Config.ResponseMIMEType != "application/json" {
+				t.Errorf("unexpected classifier requests: %v", m.Requests)
+			}
+		})
+	}
+}
+
+func TestRunner_Guardrails(t *testing.T) {
+	block, err := guardrail.NewRegexBlocklist(guardrail.RegexBlocklistConfig{Patterns: []string{"forbidden"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	redact, err := guardrail.NewRegexBlocklist(guardrail.RegexBlocklistConfig{Patterns: []string{"secret"}, Replacement: "***"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name         string
+		message      string
+		wantRequests int
+		want         []*model.LLMResponse
+	}{
+		{
+			name:         "input blocked",
+			message:      "something forbidden",
+			wantRequests: 0,
+			want: []*model.LLMResponse{{
+				ErrorCode:    guardrail.BlockedErrorCode,
+				ErrorMessage: `content matches blocked pattern "forbidden"`,
+			}},
+		},
+		{
+			name:         "output rewritten",
+			message:      "hello",
+			wantRequests: 1,
+			want: []*model.LLMResponse{{
+				Content: genai.NewContentFromText("the *** is out", genai.RoleModel),
+			}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &testutil.MockModel{Responses: []*genai.Content{genai.NewContentFromText("the secret is out", genai.RoleModel)}}
+			a, err := llmagent.New(llmagent.Config{Name: "agent", Model: m})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := t.Context()
+			sessionService := session.InMemoryService()
+			r, err := runner.New(runner.Config{
+				AppName:        "app",
+				Agent:          a,
+				SessionService: sessionService,
+				InputFilters:   []guardrail.Filter{block},
+				OutputFilters:  []guardrail.Filter{redact},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "session"}); err != nil {
+				t.Fatal(err)
+			}
+
+			var got []*model.LLMResponse
+			for ev, err := range r.Run(ctx, "user", "session", genai.NewContentFromText(tc.message, genai.RoleUser), agent.RunConfig{}) {
+				if err != nil {
+					t.Fatalf("Run() failed: %v", err)
+				}
+				if ev.Author != "agent" {
+					t.Errorf("event author = %q, want %q", ev.Author, "agent")
+				}
+				got = append(got, &ev.LLMResponse)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("events mismatch (-want +got):\n%s", diff)
+			}
+			if len(m.Requests) != tc.wantRequests {
+				t.Errorf("got %d model requests, want %d", len(m.Requests), tc.wantRequests)
+			}
+		})
+	}
+}
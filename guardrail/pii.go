@@ -0,0 +1,401 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package guardrail
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+// Info types of the built-in PII detectors. They match the names of the
+// Cloud DLP info types.
+const (
+	InfoTypeEmailAddress     = "EMAIL_ADDRESS"
+	InfoTypePhoneNumber      = "PHONE_NUMBER"
+	InfoTypeCreditCardNumber = "CREDIT_CARD_NUMBER"
+)
+
+// RedactionsMetadataKey is the key of the custom metadata of the events
+// listing the redactions of their content, as a []Redaction.
+const RedactionsMetadataKey = "pii_redactions"
+
+// PIIFinding is a piece of personal information found in a text.
+type PIIFinding struct {
+	// InfoType is the type of the information, e.g. "EMAIL_ADDRESS".
+	InfoType string
+	// Start and End are the byte offsets of the information in the text.
+	Start, End int
+}
+
+// PIIDetector finds personal information in texts.
+type PIIDetector interface {
+	Detect(ctx context.Context, text string) ([]PIIFinding, error)
+}
+
+// NewRegexDetector creates a detector reporting the matches of pattern as
+// findings of the given info type. If valid is not nil, the matches for which
+// it returns false are ignored.
+func NewRegexDetector(infoType, pattern string, valid func(match string) bool) (PIIDetector, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return &regexDetector{infoType: infoType, re: re, valid: valid}, nil
+}
+
+type regexDetector struct {
+	infoType string
+	re       *regexp.Regexp
+	valid    func(string) bool
+}
+
+// Detect implements PIIDetector.
+func (d *regexDetector) Detect(ctx context.Context, text string) ([]PIIFinding, error) {
+	var findings []PIIFinding
+	for _, loc := range d.re.FindAllStringIndex(text, -1) {
+		if d.valid != nil && !d.valid(text[loc[0]:loc[1]]) {
+			continue
+		}
+		findings = append(findings, PIIFinding{InfoType: d.infoType, Start: loc[0], End: loc[1]})
+	}
+	return findings, nil
+}
+
+var (
+	emailDetector = must(NewRegexDetector(InfoTypeEmailAddress,
+		`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`, nil))
+	phoneNumberDetector = must(NewRegexDetector(InfoTypePhoneNumber,
+		`(?:\+\d{1,3}[ .-]?)?(?:\(\d{1,4}\)[ .-]?)?\d{2,4}(?:[ .-]?\d{2,4}){2,3}\b`, digitCountBetween(9, 15)))
+	creditCardDetector = must(NewRegexDetector(InfoTypeCreditCardNumber,
+		`\b\d(?:[ -]?\d){12,18}\b`, luhnValid))
+)
+
+// DefaultPIIDetectors returns the built-in detectors of email addresses,
+// phone numbers and credit card numbers. They are based on regular
+// expressions and only find the common formats; use a DLP detector for a
+// more thorough detection.
+func DefaultPIIDetectors() []PIIDetector {
+	return []PIIDetector{emailDetector, creditCardDetector, phoneNumberDetector}
+}
+
+func must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func digits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+func digitCountBetween(lo, hi int) func(string) bool {
+	return func(s string) bool {
+		n := len(digits(s))
+		return n >= lo && n <= hi
+	}
+}
+
+// luhnValid reports whether the digits of s pass the Luhn checksum used by
+// the credit card numbers.
+func luhnValid(s string) bool {
+	d := digits(s)
+	sum := 0
+	for i := range len(d) {
+		n := int(d[len(d)-1-i] - '0')
+		if i%2 == 1 {
+			n *= 2
+			if n > 9 {
+				n -= 9
+			}
+		}
+		sum += n
+	}
+	return sum%10 == 0
+}
+
+// Redaction counts the redacted pieces of information of a type.
+type Redaction struct {
+	InfoType string `json:"infoType"`
+	Count    int    `json:"count"`
+}
+
+// PIIRedactorConfig defines the configuration of a PII redactor.
+type PIIRedactorConfig struct {
+	// Name of the filter. If empty, "pii_redactor" is used.
+	Name string
+	// Detectors find the personal information to redact. If empty,
+	// DefaultPIIDetectors is used.
+	Detectors []PIIDetector
+	// Replacement returns the text replacing the information of the given
+	// type. If nil, the information is replaced by "[REDACTED_<info type>]".
+	Replacement func(infoType string) string
+	// OnRedaction, if set, is called with the redactions of each redacted
+	// content, e.g. to write an audit log. The redacted information itself
+	// is not reported.
+	OnRedaction func(ctx context.Context, redactions []Redaction)
+}
+
+// PIIRedactor replaces the personal information found in texts.
+//
+// It can be used as an output or input [Filter] of a runner, or set on
+// specific agents with [PIIRedactor.AfterModelCallback] and
+// [PIIRedactor.AfterToolCallback]. The redactions of model output and of
+// filtered contents are recorded in the custom metadata of the events under
+// [RedactionsMetadataKey].
+type PIIRedactor struct {
+	name        string
+	detectors   []PIIDetector
+	replacement func(string) string
+	onRedaction func(context.Context, []Redaction)
+}
+
+// NewPIIRedactor creates a PII redactor.
+func NewPIIRedactor(cfg PIIRedactorConfig) (*PIIRedactor, error) {
+	r := &PIIRedactor{
+		name:        cfg.Name,
+		detectors:   cfg.Detectors,
+		replacement: cfg.Replacement,
+		onRedaction: cfg.OnRedaction,
+	}
+	if r.name == "" {
+		r.name = "pii_redactor"
+	}
+	if len(r.detectors) == 0 {
+		r.detectors = DefaultPIIDetectors()
+	}
+	if slices.Contains(r.detectors, nil) {
+		return nil, fmt.Errorf("nil detector")
+	}
+	if r.replacement == nil {
+		r.replacement = func(infoType string) string { return "[REDACTED_" + infoType + "]" }
+	}
+	return r, nil
+}
+
+// Name implements Filter.
+func (r *PIIRedactor) Name() string {
+	return r.name
+}
+
+// Check implements Filter. It rewrites the content with the personal
+// information of its text parts redacted.
+func (r *PIIRedactor) Check(ctx context.Context, content *genai.Content) (*Result, error) {
+	redacted, redactions, err := r.redactContent(ctx, content)
+	if err != nil || redacted == nil {
+		return nil, err
+	}
+	return &Result{Content: redacted, Metadata: map[string]any{RedactionsMetadataKey: redactions}}, nil
+}
+
+// Redact returns text with its personal information redacted, and the
+// redactions made.
+func (r *PIIRedactor) Redact(ctx context.Context, text string) (string, []Redaction, error) {
+	var findings []PIIFinding
+	for _, d := range r.detectors {
+		f, err := d.Detect(ctx, text)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to detect personal information: %w", err)
+		}
+		findings = append(findings, f...)
+	}
+	if len(findings) == 0 {
+		return text, nil, nil
+	}
+	// The first detector wins when findings overlap.
+	slices.SortStableFunc(findings, func(a, b PIIFinding) int {
+		return cmp.Compare(a.Start, b.Start)
+	})
+	var sb strings.Builder
+	counts := map[string]int{}
+	last := 0
+	for _, f := range findings {
+		if f.Start < last || f.Start >= f.End || f.End > len(text) {
+			continue
+		}
+		sb.WriteString(text[last:f.Start])
+		sb.WriteString(r.replacement(f.InfoType))
+		counts[f.InfoType]++
+		last = f.End
+	}
+	sb.WriteString(text[last:])
+	return sb.String(), toRedactions(counts), nil
+}
+
+func (r *PIIRedactor) redactContent(ctx context.Context, content *genai.Content) (*genai.Content, []Redaction, error) {
+	if content == nil {
+		return nil, nil, nil
+	}
+	var redacted *genai.Content
+	counts := map[string]int{}
+	for i, part := range content.Parts {
+		if part == nil || part.Text == "" {
+			continue
+		}
+		text, redactions, err := r.Redact(ctx, part.Text)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(redactions) == 0 {
+			continue
+		}
+		addRedactions(counts, redactions)
+		if redacted == nil {
+			redacted = &genai.Content{Role: content.Role, Parts: slices.Clone(content.Parts)}
+		}
+		newPart := *part
+		newPart.Text = text
+		redacted.Parts[i] = &newPart
+	}
+	if redacted == nil {
+		return nil, nil, nil
+	}
+	redactions := toRedactions(counts)
+	r.audit(ctx, redactions)
+	return redacted, redactions, nil
+}
+
+// AfterModelCallback returns a callback redacting the personal information
+// of the model responses of an agent. The redactions are recorded in the
+// custom metadata of the responses.
+//
+// With streaming, each partial response is redacted on its own, so that
+// information split across two responses may not be found; the final
+// response is redacted as a whole.
+func (r *PIIRedactor) AfterModelCallback() llmagent.AfterModelCallback {
+	return func(ctx agent.CallbackContext, resp *model.LLMResponse, respErr error) (*model.LLMResponse, error) {
+		if respErr != nil || resp == nil {
+			return nil, nil
+		}
+		redacted, redactions, err := r.redactContent(ctx, resp.Content)
+		if err != nil || redacted == nil {
+			return nil, err
+		}
+		newResp := *resp
+		newResp.Content = redacted
+		newResp.CustomMetadata = maps.Clone(resp.CustomMetadata)
+		if newResp.CustomMetadata == nil {
+			newResp.CustomMetadata = map[string]any{}
+		}
+		newResp.CustomMetadata[RedactionsMetadataKey] = redactions
+		return &newResp, nil
+	}
+}
+
+// AfterToolCallback returns a callback redacting the personal information
+// of the string values of the tool results of an agent. The redactions are
+// only reported to the OnRedaction function, if any.
+func (r *PIIRedactor) AfterToolCallback() llmagent.AfterToolCallback {
+	return func(ctx tool.Context, t tool.Tool, args, result map[string]any, toolErr error) (map[string]any, error) {
+		if toolErr != nil || result == nil {
+			return nil, nil
+		}
+		counts := map[string]int{}
+		redacted, changed, err := r.redactValue(ctx, result, counts)
+		if err != nil || !changed {
+			return nil, err
+		}
+		r.audit(ctx, toRedactions(counts))
+		return redacted.(map[string]any), nil
+	}
+}
+
+// redactValue redacts the strings in v, which is a JSON-like value, and
+// reports whether it changed. The unchanged values are not copied.
+func (r *PIIRedactor) redactValue(ctx context.Context, v any, counts map[string]int) (any, bool, error) {
+	switch v := v.(type) {
+	case string:
+		text, redactions, err := r.Redact(ctx, v)
+		if err != nil || len(redactions) == 0 {
+			return v, false, err
+		}
+		addRedactions(counts, redactions)
+		return text, true, nil
+	case map[string]any:
+		var res map[string]any
+		for k, elem := range v {
+			redacted, changed, err := r.redactValue(ctx, elem, counts)
+			if err != nil {
+				return nil, false, err
+			}
+			if changed {
+				if res == nil {
+					res = maps.Clone(v)
+				}
+				res[k] = redacted
+			}
+		}
+		if res == nil {
+			return v, false, nil
+		}
+		return res, true, nil
+	case []any:
+		var res []any
+		for i, elem := range v {
+			redacted, changed, err := r.redactValue(ctx, elem, counts)
+			if err != nil {
+				return nil, false, err
+			}
+			if changed {
+				if res == nil {
+					res = slices.Clone(v)
+				}
+				res[i] = redacted
+			}
+		}
+		if res == nil {
+			return v, false, nil
+		}
+		return res, true, nil
+	}
+	return v, false, nil
+}
+
+func (r *PIIRedactor) audit(ctx context.Context, redactions []Redaction) {
+	if r.onRedaction != nil && len(redactions) > 0 {
+		r.onRedaction(ctx, redactions)
+	}
+}
+
+func addRedactions(counts map[string]int, redactions []Redaction) {
+	for _, r := range redactions {
+		counts[r.InfoType] += r.Count
+	}
+}
+
+func toRedactions(counts map[string]int) []Redaction {
+	redactions := make([]Redaction, 0, len(counts))
+	for _, infoType := range slices.Sorted(maps.Keys(counts)) {
+		redactions = append(redactions, Redaction{InfoType: infoType, Count: counts[infoType]})
+	}
+	return redactions
+}
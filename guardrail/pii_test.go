@@ -0,0 +1,248 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package guardrail_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/option"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/guardrail"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+func TestPIIRedactor_Redact(t *testing.T) {
+	r, err := guardrail.NewPIIRedactor(guardrail.PIIRedactorConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name           string
+		text           string
+		want           string
+		wantRedactions []guardrail.Redaction
+	}{
+		{
+			name:           "email",
+			text:           "Write to jane.doe+adk@example.co.uk today.",
+			want:           "Write to [REDACTED_EMAIL_ADDRESS] today.",
+			wantRedactions: []guardrail.Redaction{{InfoType: guardrail.InfoTypeEmailAddress, Count: 1}},
+		},
+		{
+			name:           "phone numbers",
+			text:           "Call +1 650-253-0000 or (020) 7946 0958.",
+			want:           "Call [REDACTED_PHONE_NUMBER] or [REDACTED_PHONE_NUMBER].",
+			wantRedactions: []guardrail.Redaction{{InfoType: guardrail.InfoTypePhoneNumber, Count: 2}},
+		},
+		{
+			name:           "credit card",
+			text:           "My card is 4111 1111 1111 1111.",
+			want:           "My card is [REDACTED_CREDIT_CARD_NUMBER].",
+			wantRedactions: []guardrail.Redaction{{InfoType: guardrail.InfoTypeCreditCardNumber, Count: 1}},
+		},
+		{
+			name: "invalid card number",
+			text: "Order 4111 1111 1111 1112 shipped in 2024.",
+			want: "Order 4111 1111 1111 1112 shipped in 2024.",
+		},
+		{
+			name: "no PII",
+			text: "The meeting is at 10:30 on 2025-01-31.",
+			want: "The meeting is at 10:30 on 2025-01-31.",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, redactions, err := r.Redact(t.Context(), tc.text)
+			if err != nil {
+				t.Fatalf("Redact() failed: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("Redact() = %q, want %q", got, tc.want)
+			}
+			if diff := cmp.Diff(tc.wantRedactions, redactions); diff != "" {
+				t.Errorf("Redact() redactions mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPIIRedactor_Callbacks(t *testing.T) {
+	var audited []guardrail.Redaction
+	r, err := guardrail.NewPIIRedactor(guardrail.PIIRedactorConfig{
+		OnRedaction: func(ctx context.Context, redactions []guardrail.Redaction) {
+			audited = append(audited, redactions...)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	type contact struct {
+		Name   string   `json:"name"`
+		Emails []string `json:"emails"`
+	}
+	lookup, err := functiontool.New(functiontool.Config{Name: "lookup", Description: "Looks up a contact."},
+		func(ctx tool.Context, args struct{}) (contact, error) {
+			return contact{Name: "Jane", Emails: []string{"jane@example.com"}}, nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromFunctionCall("lookup", map[string]any{}, genai.RoleModel),
+		genai.NewContentFromText("Call Jane at +1 650-253-0000.", genai.RoleModel),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:                "agent",
+		Model:               m,
+		Tools:               []tool.Tool{lookup},
+		AfterModelCallbacks: []llmagent.AfterModelCallback{r.AfterModelCallback()},
+		AfterToolCallbacks:  []llmagent.AfterToolCallback{r.AfterToolCallback()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := testutil.CollectEvents(testutil.NewTestAgentRunner(t, a).Run(t, "session", "how do I reach Jane?"))
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	var toolResult map[string]any
+	for _, ev := range events {
+		for _, p := range ev.Content.Parts {
+			if p.FunctionResponse != nil {
+				toolResult = p.FunctionResponse.Response
+			}
+		}
+	}
+	wantResult := map[string]any{"name": "Jane", "emails": []any{"[REDACTED_EMAIL_ADDRESS]"}}
+	if diff := cmp.Diff(wantResult, toolResult); diff != "" {
+		t.Errorf("tool result mismatch (-want +got):\n%s", diff)
+	}
+	last := events[len(events)-1]
+	if got, want := last.Content.Parts[0].Text, "Call Jane at [REDACTED_PHONE_NUMBER]."; got != want {
+		t.Errorf("model output = %q, want %q", got, want)
+	}
+	wantMetadata := map[string]any{guardrail.RedactionsMetadataKey: []guardrail.Redaction{{InfoType: guardrail.InfoTypePhoneNumber, Count: 1}}}
+	if diff := cmp.Diff(wantMetadata, last.CustomMetadata); diff != "" {
+		t.Errorf("model output metadata mismatch (-want +got):\n%s", diff)
+	}
+	wantAudited := []guardrail.Redaction{
+		{InfoType: guardrail.InfoTypeEmailAddress, Count: 1},
+		{InfoType: guardrail.InfoTypePhoneNumber, Count: 1},
+	}
+	if diff := cmp.Diff(wantAudited, audited); diff != "" {
+		t.Errorf("audited redactions mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPIIRedactor_OutputFilter(t *testing.T) {
+	r, err := guardrail.NewPIIRedactor(guardrail.PIIRedactorConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &testutil.MockModel{Responses: []*genai.Content{genai.NewContentFromText("Mail me at bob@example.com", genai.RoleModel)}}
+	a, err := llmagent.New(llmagent.Config{Name: "agent", Model: m})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := t.Context()
+	sessionService := session.InMemoryService()
+	rn, err := runner.New(runner.Config{AppName: "app", Agent: a, SessionService: sessionService, OutputFilters: []guardrail.Filter{r}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	var got []*session.Event
+	for ev, err := range rn.Run(ctx, "user", "session", genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+		got = append(got, ev)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d events, want 1", len(got))
+	}
+	if text := got[0].Content.Parts[0].Text; text != "Mail me at [REDACTED_EMAIL_ADDRESS]" {
+		t.Errorf("event text = %q, want the email redacted", text)
+	}
+	wantMetadata := map[string]any{guardrail.RedactionsMetadataKey: []guardrail.Redaction{{InfoType: guardrail.InfoTypeEmailAddress, Count: 1}}}
+	if diff := cmp.Diff(wantMetadata, got[0].CustomMetadata); diff != "" {
+		t.Errorf("event metadata mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDLPDetector(t *testing.T) {
+	var gotPath string
+	var gotReq map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &gotReq); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"result": {"findings": [
+			{"infoType": {"name": "US_SOCIAL_SECURITY_NUMBER"}, "location": {"byteRange": {"start": "7", "end": "18"}}}
+		]}}`)
+	}))
+	defer srv.Close()
+
+	ctx := t.Context()
+	d, err := guardrail.NewDLPDetector(ctx, guardrail.DLPDetectorConfig{
+		Project:       "p",
+		InfoTypes:     []string{"US_SOCIAL_SECURITY_NUMBER"},
+		ClientOptions: []option.ClientOption{option.WithEndpoint(srv.URL), option.WithoutAuthentication()},
+	})
+	if err != nil {
+		t.Fatalf("NewDLPDetector() failed: %v", err)
+	}
+	r, err := guardrail.NewPIIRedactor(guardrail.PIIRedactorConfig{Detectors: []guardrail.PIIDetector{d}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := r.Redact(ctx, "My SSN 123-45-6789.")
+	if err != nil {
+		t.Fatalf("Redact() failed: %v", err)
+	}
+	if want := "My SSN [REDACTED_US_SOCIAL_SECURITY_NUMBER]."; got != want {
+		t.Errorf("Redact() = %q, want %q", got, want)
+	}
+	if want := "/v2/projects/p/locations/global/content:inspect"; gotPath != want {
+		t.Errorf("request path = %q, want %q", gotPath, want)
+	}
+	wantReq := map[string]any{
+		"item":          map[string]any{"value": "My SSN 123-45-6789."},
+		"inspectConfig": map[string]any{"infoTypes": []any{map[string]any{"name": "US_SOCIAL_SECURITY_NUMBER"}}},
+	}
+	if diff := cmp.Diff(wantReq, gotReq); diff != "" {
+		t.Errorf("request mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package guardrail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"google.golang.org/genai"
+)
+
+// RegexBlocklistConfig defines the configuration of a regex blocklist filter.
+type RegexBlocklistConfig struct {
+	// Name of the filter. If empty, "regex_blocklist" is used.
+	Name string
+	// Patterns are the regular expressions matched against the text parts of
+	// the content.
+	Patterns []string
+	// Replacement, if set, makes the filter rewrite the matches with the
+	// given text instead of blocking the content. It may reference
+	// submatches as in regexp.Regexp.ReplaceAllString.
+	Replacement string
+}
+
+// NewRegexBlocklist creates a filter that blocks or rewrites text matching
+// any of the configured patterns.
+func NewRegexBlocklist(cfg RegexBlocklistConfig) (Filter, error) {
+	if len(cfg.Patterns) == 0 {
+		return nil, errors.New("at least one pattern is required")
+	}
+	name := cfg.Name
+	if name == "" {
+		name = "regex_blocklist"
+	}
+	patterns := make([]*regexp.Regexp, len(cfg.Patterns))
+	for i, p := range cfg.Patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+		patterns[i] = re
+	}
+	return &regexBlocklist{name: name, patterns: patterns, replacement: cfg.Replacement}, nil
+}
+
+type regexBlocklist struct {
+	name        string
+	patterns    []*regexp.Regexp
+	replacement string
+}
+
+// Name implements Filter.
+func (f *regexBlocklist) Name() string {
+	return f.name
+}
+
+// Check implements Filter.
+func (f *regexBlocklist) Check(ctx context.Context, content *genai.Content) (*Result, error) {
+	if content == nil {
+		return nil, nil
+	}
+	var rewritten *genai.Content
+	for i, part := range content.Parts {
+		if part == nil || part.Text == "" {
+			continue
+		}
+		text := part.Text
+		for _, re := range f.patterns {
+			if !re.MatchString(text) {
+				continue
+			}
+			if f.replacement == "" {
+				return &Result{Blocked: true, Reason: fmt.Sprintf("content matches blocked pattern %q", re.String())}, nil
+			}
+			text = re.ReplaceAllString(text, f.replacement)
+		}
+		if text == part.Text {
+			continue
+		}
+		if rewritten == nil {
+			rewritten = &genai.Content{Role: content.Role, Parts: make([]*genai.Part, len(content.Parts))}
+			copy(rewritten.Parts, content.Parts)
+		}
+		newPart := *part
+		newPart.Text = text
+		rewritten.Parts[i] = &newPart
+	}
+	if rewritten == nil {
+		return nil, nil
+	}
+	return &Result{Content: rewritten}, nil
+}
@@ -14,7 +14,14 @@
 
 package runconfig
 
-import "context"
+import (
+	"context"
+	"sync/atomic"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+)
 
 type StreamingMode string
 
@@ -26,6 +33,45 @@ const (
 
 type RunConfig struct {
 	StreamingMode StreamingMode
+
+	// MaxLLMCalls and MaxToolCalls limit the number of model and tool calls
+	// of an invocation. Zero means no limit.
+	MaxLLMCalls  int
+	MaxToolCalls int
+
+	// LiveRequestQueue delivers the user input in bidi streaming mode.
+	LiveRequestQueue *agent.LiveRequestQueue
+	// Output and transcription settings of the live connection.
+	ResponseModalities       []genai.Modality
+	SpeechConfig             *genai.SpeechConfig
+	InputAudioTranscription  *genai.AudioTranscriptionConfig
+	OutputAudioTranscription *genai.AudioTranscriptionConfig
+
+	llmCalls  atomic.Int64
+	toolCalls atomic.Int64
+}
+
+// IsLive reports whether the agents run in bidi streaming mode.
+func (c *RunConfig) IsLive() bool {
+	return c != nil && c.StreamingMode == StreamingModeBidi
+}
+
+// AddLLMCall records a model call and reports whether it is within
+// MaxLLMCalls.
+func (c *RunConfig) AddLLMCall() bool {
+	if c == nil || c.MaxLLMCalls <= 0 {
+		return true
+	}
+	return c.llmCalls.Add(1) <= int64(c.MaxLLMCalls)
+}
+
+// AddToolCalls records n tool calls and reports whether they are within
+// MaxToolCalls.
+func (c *RunConfig) AddToolCalls(n int) bool {
+	if c == nil || c.MaxToolCalls <= 0 {
+		return true
+	}
+	return c.toolCalls.Add(int64(n)) <= int64(c.MaxToolCalls)
 }
 
 func ToContext(ctx context.Context, cfg *RunConfig) context.Context {
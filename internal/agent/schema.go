@@ -0,0 +1,103 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/session"
+)
+
+// ErrSchemaViolation is returned when a message or a response doesn't match
+// the schema declared by the agent.
+var ErrSchemaViolation = errors.New("schema violation")
+
+// Schemas returns the input and output schemas declared by a, if any.
+func Schemas(a any) (input, output *jsonschema.Resolved) {
+	ia, ok := a.(Agent)
+	if !ok {
+		return nil, nil
+	}
+	state := Reveal(ia)
+	return state.ServedInputSchema, state.ServedOutputSchema
+}
+
+// ValidateInput checks that the text of content is a JSON value matching the
+// input schema of a. The messages carrying function responses answer the
+// calls of the agent and are not checked.
+func ValidateInput(a any, content *genai.Content) error {
+	schema, _ := Schemas(a)
+	if schema == nil || content == nil {
+		return nil
+	}
+	for _, p := range content.Parts {
+		if p != nil && p.FunctionResponse != nil {
+			return nil
+		}
+	}
+	if err := validateText(schema, Text(content)); err != nil {
+		return fmt.Errorf("%w: message doesn't match the input schema: %w", ErrSchemaViolation, err)
+	}
+	return nil
+}
+
+// ValidateOutput checks that the text of content, the final response of an
+// invocation, is a JSON value matching the output schema of a.
+func ValidateOutput(a any, content *genai.Content) error {
+	_, schema := Schemas(a)
+	if schema == nil {
+		return nil
+	}
+	if err := validateText(schema, Text(content)); err != nil {
+		return fmt.Errorf("%w: response doesn't match the output schema: %w", ErrSchemaViolation, err)
+	}
+	return nil
+}
+
+// IsOutput reports whether event is a final response checked by
+// ValidateOutput: a complete reply with text, not an error nor a pause on
+// long-running function calls.
+func IsOutput(event *session.Event) bool {
+	return event != nil && !event.Partial && event.ErrorCode == "" && len(event.LongRunningToolIDs) == 0 &&
+		event.IsFinalResponse() && Text(event.Content) != ""
+}
+
+// Text returns the concatenated text parts of content, thoughts excluded.
+func Text(content *genai.Content) string {
+	if content == nil {
+		return ""
+	}
+	var sb strings.Builder
+	for _, p := range content.Parts {
+		if p != nil && !p.Thought {
+			sb.WriteString(p.Text)
+		}
+	}
+	return sb.String()
+}
+
+func validateText(schema *jsonschema.Resolved, text string) error {
+	var v any
+	if err := json.Unmarshal([]byte(text), &v); err != nil {
+		return fmt.Errorf("not a JSON value: %w", err)
+	}
+	return schema.Validate(v)
+}
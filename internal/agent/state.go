@@ -14,6 +14,8 @@
 
 package agent
 
+import "github.com/google/jsonschema-go/jsonschema"
+
 // holds Agent internal state
 type Agent interface {
 	internal() *State
@@ -22,6 +24,12 @@ type Agent interface {
 type State struct {
 	AgentType Type
 	Config    any
+
+	// ServedInputSchema and ServedOutputSchema are the schemas of the
+	// messages and of the final response of the agent when it is served, if
+	// declared.
+	ServedInputSchema  *jsonschema.Resolved
+	ServedOutputSchema *jsonschema.Resolved
 }
 
 type Type string
@@ -31,7 +39,9 @@ const (
 	TypeLoopAgent       Type = "LoopAgent"
 	TypeSequentialAgent Type = "SequentialAgent"
 	TypeParallelAgent   Type = "ParallelAgent"
+	TypeGraphAgent      Type = "GraphAgent"
 	TypeCustomAgent     Type = "CustomAgent"
+	TypeRemoteA2AAgent  Type = "RemoteA2AAgent"
 )
 
 func (s *State) internal() *State { return s }
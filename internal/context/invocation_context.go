@@ -16,6 +16,7 @@ package context
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"google.golang.org/genai"
@@ -35,13 +36,40 @@ type InvocationContextParams struct {
 	UserContent   *genai.Content
 	RunConfig     *agent.RunConfig
 	EndInvocation bool
+
+	// InvocationID continues an existing invocation. If empty, a new ID is
+	// generated.
+	InvocationID string
 }
 
 func NewInvocationContext(ctx context.Context, params InvocationContextParams) agent.InvocationContext {
+	invocationID := params.InvocationID
+	if invocationID == "" {
+		invocationID = "e-" + uuid.NewString()
+	}
 	return &InvocationContext{
 		Context:      ctx,
 		params:       params,
-		invocationID: "e-" + uuid.NewString(),
+		invocationID: invocationID,
+	}
+}
+
+// WithUserContent returns a copy of ctx with the given user content. The
+// invocation ID is preserved.
+func WithUserContent(ctx agent.InvocationContext, content *genai.Content) agent.InvocationContext {
+	return &InvocationContext{
+		Context: ctx,
+		params: InvocationContextParams{
+			Artifacts:     ctx.Artifacts(),
+			Memory:        ctx.Memory(),
+			Session:       ctx.Session(),
+			Branch:        ctx.Branch(),
+			Agent:         ctx.Agent(),
+			UserContent:   content,
+			RunConfig:     ctx.RunConfig(),
+			EndInvocation: ctx.Ended(),
+		},
+		invocationID: ctx.InvocationID(),
 	}
 }
 
@@ -91,3 +119,32 @@ func (c *InvocationContext) EndInvocation() {
 func (c *InvocationContext) Ended() bool {
 	return c.params.EndInvocation
 }
+
+// WithContext returns an invocation context that delegates to ictx, except
+// for the context.Context methods that are served by ctx. It is used to
+// attach values, e.g. trace spans, to an invocation context, while keeping
+// its state shared with ictx.
+func WithContext(ictx agent.InvocationContext, ctx context.Context) agent.InvocationContext {
+	return &contextOverride{InvocationContext: ictx, ctx: ctx}
+}
+
+type contextOverride struct {
+	agent.InvocationContext
+	ctx context.Context
+}
+
+func (c *contextOverride) Deadline() (time.Time, bool) {
+	return c.ctx.Deadline()
+}
+
+func (c *contextOverride) Done() <-chan struct{} {
+	return c.ctx.Done()
+}
+
+func (c *contextOverride) Err() error {
+	return c.ctx.Err()
+}
+
+func (c *contextOverride) Value(key any) any {
+	return c.ctx.Value(key)
+}
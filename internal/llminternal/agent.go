@@ -15,10 +15,16 @@
 package llminternal
 
 import (
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/utils"
 	"google.golang.org/adk/model"
+	"google.golang.org/adk/planner"
+	"google.golang.org/adk/session"
 	"google.golang.org/adk/tool"
 )
 
@@ -47,12 +53,48 @@ type State struct {
 
 	InputSchema  *genai.Schema
 	OutputSchema *genai.Schema
+	// OutputJSONSchema is set instead of OutputSchema when the output schema
+	// is a JSON schema, e.g. one inferred from a Go type.
+	OutputJSONSchema *jsonschema.Resolved
 
 	OutputKey string
+
+	ContextProvider   ContextProvider
+	EventsProcessor   EventsProcessor
+	PartConverter     PartConverter
+	ToolResponseSaver ToolResponseSaver
+
+	Planner planner.Planner
+
+	CacheConfig *model.CacheConfig
 }
 
 type InstructionProvider func(ctx agent.ReadonlyContext) (string, error)
 
+// EventsProcessor selects the session events turned into the contents of
+// the model request.
+type EventsProcessor func(ctx agent.ReadonlyContext, events []*session.Event) ([]*session.Event, error)
+
+// ContextProvider returns text relevant to the user query that is added to
+// the model request instructions.
+type ContextProvider func(ctx agent.ReadonlyContext, query string) (string, error)
+
+// ToolResponseSaver returns the response of a tool given to the model in
+// place of response, e.g. a reference to the response saved elsewhere.
+type ToolResponseSaver func(ctx tool.Context, t tool.Tool, response map[string]any) (map[string]any, error)
+
 func (s *State) internal() *State { return s }
 
+// ParseOutput decodes the JSON output of the agent and validates it against
+// the agent's output schema.
+func (s *State) ParseOutput(output string) (any, error) {
+	if s.OutputSchema != nil {
+		return utils.ValidateOutputSchema(output, s.OutputSchema)
+	}
+	if s.OutputJSONSchema != nil {
+		return utils.ValidateOutputJSONSchema(output, s.OutputJSONSchema)
+	}
+	return nil, fmt.Errorf("agent has no output schema")
+}
+
 func Reveal(a Agent) *State { return a.internal() }
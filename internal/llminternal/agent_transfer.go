@@ -56,11 +56,8 @@ import (
 //  - This agent has DisallowTransferToPeers set to false (default).
 //
 // Depending on the target agent type, the transfer may be automatically
-// reversed. See python's Runner._find_agent_to_run method for which
-// agent will remain active to handle the next user message.
-// (src/google/adk/runners.py)
-//
-// TODO: implement it in the runners package and update this doc.
+// reversed. The runner's findAgentToRun method decides which agent will
+// remain active to handle the next user message.
 
 func AgentTransferRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest) error {
 	// TODO: support agent types other than LLMAgent, that have parent/subagents?
@@ -79,6 +76,9 @@ func AgentTransferRequestProcessor(ctx agent.InvocationContext, req *model.LLMRe
 	// TODO(hyangah): why do we set this up in request processor
 	// instead of registering this as a normal function tool of the Agent?
 	transferToAgentTool := &TransferToAgentTool{}
+	for _, target := range targets {
+		transferToAgentTool.AgentNames = append(transferToAgentTool.AgentNames, target.Name())
+	}
 	si, err := instructionsForTransferToAgent(agent, parents[agent.Name()], targets, transferToAgentTool)
 	if err != nil {
 		return err
@@ -87,7 +87,12 @@ func AgentTransferRequestProcessor(ctx agent.InvocationContext, req *model.LLMRe
 	return appendTools(req, transferToAgentTool)
 }
 
-type TransferToAgentTool struct{}
+// TransferToAgentTool hands off the control to another agent.
+type TransferToAgentTool struct {
+	// AgentNames are the agents the model can transfer to.
+	// If empty, any agent name is accepted.
+	AgentNames []string
+}
 
 // Description implements tool.Tool.
 func (t *TransferToAgentTool) Description() string {
@@ -115,6 +120,7 @@ func (t *TransferToAgentTool) Declaration() *genai.FunctionDeclaration {
 				"agent_name": {
 					Type:        "string",
 					Description: "the agent name to transfer to",
+					Enum:        t.AgentNames,
 				},
 			},
 			Required: []string{"agent_name"},
@@ -140,6 +146,9 @@ func (t *TransferToAgentTool) Run(ctx tool.Context, args any) (map[string]any, e
 	if !ok || agent == "" {
 		return nil, fmt.Errorf("empty agent_name: %v", args)
 	}
+	if len(t.AgentNames) > 0 && !slices.Contains(t.AgentNames, agent) {
+		return nil, fmt.Errorf("agent %q is not a valid transfer target, valid targets: %v", agent, t.AgentNames)
+	}
 	ctx.Actions().TransferToAgent = agent
 	return map[string]any{}, nil
 }
@@ -134,6 +134,17 @@ func TestAgentTransferRequestProcessor(t *testing.T) {
 		}) {
 			t.Errorf("AgentTransferRequestProcessor() did not append the function declaration, got: %v", stringify(functions))
 		}
+		gotTransferTool := gotTool.(*llminternal.TransferToAgentTool)
+		for _, want := range append(slices.Clone(wantAgents), wantParent) {
+			if want != "" && !slices.Contains(gotTransferTool.AgentNames, want) {
+				t.Errorf("transfer tool agent names = %v, want it to include %q", gotTransferTool.AgentNames, want)
+			}
+		}
+		for _, unwanted := range unwantAgents {
+			if slices.Contains(gotTransferTool.AgentNames, unwanted) {
+				t.Errorf("transfer tool agent names = %v, want it to exclude %q", gotTransferTool.AgentNames, unwanted)
+			}
+		}
 	}
 
 	t.Run("SoloAgent", func(t *testing.T) {
@@ -457,6 +468,22 @@ func TestTransferToAgentToolRun(t *testing.T) {
 		}
 	})
 
+	t.Run("UnknownAgent", func(t *testing.T) {
+		curTool := &llminternal.TransferToAgentTool{AgentNames: []string{"Sub1", "Sub2"}}
+		ctx := toolinternal.NewToolContext(icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{}), "", &session.EventActions{})
+
+		args := map[string]any{"agent_name": "Other"}
+		if got, err := curTool.Run(ctx, args); err == nil {
+			t.Fatalf("Run(%v) = (%v, %v), want error", args, got, err)
+		}
+		if got := ctx.Actions().TransferToAgent; got != "" {
+			t.Errorf("Run(%v) set TransferToAgent to %q, want empty", args, got)
+		}
+		if got, want := curTool.Declaration().Parameters.Properties["agent_name"].Enum, curTool.AgentNames; !slices.Equal(got, want) {
+			t.Errorf("Declaration() agent_name enum = %v, want %v", got, want)
+		}
+	})
+
 	t.Run("InvalidArguments", func(t *testing.T) {
 		testCases := []struct {
 			name string
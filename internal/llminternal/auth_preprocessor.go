@@ -0,0 +1,169 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/auth"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+// generateAuthEvent returns the event asking the client for the credentials
+// requested by the tools in the function response event, or nil if no
+// credential was requested.
+//
+// adk-python src/google/adk/flows/llm_flows/functions.py generate_auth_event
+func generateAuthEvent(ctx agent.InvocationContext, fnResponseEvent *session.Event) *session.Event {
+	requested := fnResponseEvent.Actions.RequestedAuthConfigs
+	if len(requested) == 0 {
+		return nil
+	}
+	ev := session.NewEvent(ctx.InvocationID())
+	ev.Author = ctx.Agent().Name()
+	ev.Branch = ctx.Branch()
+	ev.LLMResponse = model.LLMResponse{Content: &genai.Content{Role: genai.RoleModel}}
+	for _, functionCallID := range slices.Sorted(maps.Keys(requested)) {
+		call := &genai.FunctionCall{
+			Name: auth.RequestCredentialFunctionName,
+			Args: map[string]any{
+				"functionCallId": functionCallID,
+				"authConfig":     requested[functionCallID],
+			},
+		}
+		ev.LLMResponse.Content.Parts = append(ev.LLMResponse.Content.Parts, &genai.Part{FunctionCall: call})
+	}
+	utils.PopulateClientFunctionCallID(ev.LLMResponse.Content)
+	for _, call := range utils.FunctionCalls(ev.LLMResponse.Content) {
+		ev.LongRunningToolIDs = append(ev.LongRunningToolIDs, call.ID)
+	}
+	return ev
+}
+
+// resumeAuthRequests handles the credentials provided by the client in the
+// last user message: they are stored in the temporary state of the
+// invocation and the tools that requested them are called again.
+//
+// adk-python src/google/adk/auth/auth_preprocessor.py
+func (f *Flow) resumeAuthRequests(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		events := ctx.Session().Events()
+		if events.Len() == 0 {
+			return
+		}
+		last := events.At(events.Len() - 1)
+		if last.Author != "user" {
+			return
+		}
+
+		authCallIDs := map[string]bool{}
+		for _, resp := range utils.FunctionResponses(last.Content) {
+			if resp.Name != auth.RequestCredentialFunctionName {
+				continue
+			}
+			cfg, err := decodeAuthConfig(resp.Response)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if err := toolinternal.StoreCredential(ctx.Session().State(), cfg); err != nil {
+				yield(nil, err)
+				return
+			}
+			authCallIDs[resp.ID] = true
+		}
+		if len(authCallIDs) == 0 {
+			return
+		}
+
+		// Find the function calls that requested the credentials. The auth
+		// events follow the function calls, so they are seen first.
+		toolCallIDs := map[string]bool{}
+		var calls []*genai.Part
+		for i := events.Len() - 2; i >= 0 && (len(calls) == 0 || len(calls) < len(toolCallIDs)); i-- {
+			for _, call := range utils.FunctionCalls(utils.Content(events.At(i))) {
+				switch {
+				case call.Name == auth.RequestCredentialFunctionName && authCallIDs[call.ID]:
+					id, _ := call.Args["functionCallId"].(string)
+					toolCallIDs[id] = true
+				case toolCallIDs[call.ID]:
+					calls = append(calls, &genai.Part{FunctionCall: call})
+				}
+			}
+		}
+		if len(calls) == 0 {
+			// Nothing to resume, the credentials are kept for the tools
+			// called later in the invocation.
+			return
+		}
+
+		llmAgent, ok := ctx.Agent().(Agent)
+		if !ok {
+			yield(nil, fmt.Errorf("agent %v is not an LLMAgent", ctx.Agent().Name()))
+			return
+		}
+		agentTools, err := agentTools(ctx, llmAgent)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		req := &model.LLMRequest{}
+		if err := toolPreprocess(ctx, req, agentTools); err != nil {
+			yield(nil, err)
+			return
+		}
+		tools, err := requestTools(req)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		resp := &model.LLMResponse{Content: &genai.Content{Role: genai.RoleModel, Parts: calls}}
+		ev, err := f.handleFunctionCalls(ctx, tools, resp, nil)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		if !yield(ev, nil) {
+			return
+		}
+		if authEvent := generateAuthEvent(ctx, ev); authEvent != nil {
+			yield(authEvent, nil)
+		}
+	}
+}
+
+// decodeAuthConfig decodes the auth config sent by the client as a function
+// response.
+func decodeAuthConfig(response map[string]any) (*auth.Config, error) {
+	data, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s response: %w", auth.RequestCredentialFunctionName, err)
+	}
+	var cfg auth.Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode %s response: %w", auth.RequestCredentialFunctionName, err)
+	}
+	return &cfg, nil
+}
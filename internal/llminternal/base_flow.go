@@ -15,21 +15,28 @@
 package llminternal
 
 import (
+	"context"
 	"fmt"
 	"iter"
 	"maps"
 	"slices"
+	"time"
 
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
+	"google.golang.org/adk/auth"
 	"google.golang.org/adk/internal/agent/parentmap"
 	"google.golang.org/adk/internal/agent/runconfig"
 	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/logging"
+	"google.golang.org/adk/internal/metrics"
+	"google.golang.org/adk/internal/plugininternal"
 	"google.golang.org/adk/internal/telemetry"
 	"google.golang.org/adk/internal/toolinternal"
 	"google.golang.org/adk/internal/utils"
 	"google.golang.org/adk/model"
+	"google.golang.org/adk/plugin"
 	"google.golang.org/adk/session"
 	"google.golang.org/adk/tool"
 )
@@ -56,10 +63,14 @@ type Flow struct {
 var (
 	DefaultRequestProcessors = []func(ctx agent.InvocationContext, req *model.LLMRequest) error{
 		basicRequestProcessor,
-		authPreprocessor,
 		instructionsRequestProcessor,
+		retrievalRequestProcessor,
 		identityRequestProcessor,
+		cacheRequestProcessor,
 		ContentsRequestProcessor,
+		// Media conversion replaces parts of the contents assembled by
+		// contentsRequestProcessor.
+		mediaRequestProcessor,
 		// Some implementations of NL Planning mark planning contents as thoughts in the post processor.
 		// Since these need to be unmarked, NL Planning should be after contentsRequestProcessor.
 		nlPlanningRequestProcessor,
@@ -76,7 +87,23 @@ var (
 )
 
 func (f *Flow) Run(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+	if runconfig.FromContext(ctx).IsLive() {
+		return f.runLive(ctx)
+	}
 	return func(yield func(*session.Event, error) bool) {
+		for ev, err := range f.resumeAuthRequests(ctx) {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(ev, nil) {
+				return
+			}
+			if len(ev.LongRunningToolIDs) > 0 {
+				// More credentials are needed.
+				return
+			}
+		}
 		for {
 			var lastEvent *session.Event
 			for ev, err := range f.runOneStep(ctx) {
@@ -115,11 +142,16 @@ func (f *Flow) runOneStep(ctx agent.InvocationContext) iter.Seq2[*session.Event,
 		if ctx.Ended() {
 			return
 		}
-		spans := telemetry.StartTrace(ctx, "call_llm")
+		rc := runconfig.FromContext(ctx)
+		if !rc.AddLLMCall() {
+			yield(limitExceededEvent(ctx, agent.MaxLLMCallsExceededErrorCode, fmt.Sprintf("invocation exceeded the limit of %d model calls", rc.MaxLLMCalls)), nil)
+			return
+		}
+		spanCtx, spans := telemetry.StartTrace(ctx, "call_llm")
 		// Create event to pass to callback state delta
 		stateDelta := make(map[string]any)
 		// Calls the LLM.
-		for resp, err := range f.callLLM(ctx, req, stateDelta) {
+		for resp, err := range f.callLLM(icontext.WithContext(ctx, spanCtx), req, stateDelta) {
 			if err != nil {
 				yield(nil, err)
 				return
@@ -128,6 +160,7 @@ func (f *Flow) runOneStep(ctx agent.InvocationContext) iter.Seq2[*session.Event,
 				yield(nil, err)
 				return
 			}
+			recordCacheMetadata(ctx, req, resp, stateDelta)
 			// Skip the model response event if there is no content and no error code.
 			// This is needed for the code executor to trigger another loop according to
 			// adk-python src/google/adk/flows/llm_flows/base_llm_flow.py BaseLlmFlow._postprocess_async.
@@ -135,29 +168,26 @@ func (f *Flow) runOneStep(ctx agent.InvocationContext) iter.Seq2[*session.Event,
 				continue
 			}
 
-			// TODO: temporarily convert
-			tools := make(map[string]tool.Tool)
-			for k, v := range req.Tools {
-				tool, ok := v.(tool.Tool)
-				if !ok {
-					if !yield(nil, fmt.Errorf("unexpected tool type %T for tool %v", v, k)) {
-						return
-					}
-				}
-				tools[k] = tool
+			tools, err := requestTools(req)
+			if err != nil {
+				yield(nil, err)
+				return
 			}
 
 			// Build the event and yield.
 			modelResponseEvent := f.finalizeModelResponseEvent(ctx, resp, tools, stateDelta)
-			telemetry.TraceLLMCall(spans, ctx, req, modelResponseEvent)
+			telemetry.TraceLLMCall(spans, ctx.Session().ID(), req, modelResponseEvent)
 			if !yield(modelResponseEvent, nil) {
 				return
 			}
-			// TODO: generate and yield an auth event if needed.
 
 			// Handle function calls.
+			if n := len(utils.FunctionCalls(resp.Content)); n > 0 && !rc.AddToolCalls(n) {
+				yield(limitExceededEvent(ctx, agent.MaxToolCallsExceededErrorCode, fmt.Sprintf("invocation exceeded the limit of %d tool calls", rc.MaxToolCalls)), nil)
+				return
+			}
 
-			ev, err := f.handleFunctionCalls(ctx, tools, resp)
+			ev, err := f.handleFunctionCalls(ctx, tools, resp, nil)
 			if err != nil {
 				yield(nil, err)
 				return
@@ -169,6 +199,11 @@ func (f *Flow) runOneStep(ctx agent.InvocationContext) iter.Seq2[*session.Event,
 			if !yield(ev, nil) {
 				return
 			}
+			// The invocation pauses until the client provides the requested credentials.
+			if authEvent := generateAuthEvent(ctx, ev); authEvent != nil {
+				yield(authEvent, nil)
+				return
+			}
 
 			// Actually handle "transfer_to_agent" tool. The function call sets the ev.Actions.TransferToAgent field.
 			// We are following python's execution flow which is
@@ -206,17 +241,25 @@ func (f *Flow) preprocess(ctx agent.InvocationContext, req *model.LLMRequest) er
 	}
 
 	// run processors for tools.
-	tools := Reveal(llmAgent).Tools
+	tools, err := agentTools(ctx, llmAgent)
+	if err != nil {
+		return err
+	}
+	return toolPreprocess(ctx, req, tools)
+}
+
+// agentTools returns the tools of the agent, with the tool sets expanded.
+func agentTools(ctx agent.InvocationContext, llmAgent Agent) ([]tool.Tool, error) {
+	tools := slices.Clone(Reveal(llmAgent).Tools)
 	for _, toolSet := range Reveal(llmAgent).Toolsets {
 		tsTools, err := toolSet.Tools(icontext.NewReadonlyContext(ctx))
 		if err != nil {
-			return fmt.Errorf("failed to extract tools from the tool set %q: %w", toolSet.Name(), err)
+			return nil, fmt.Errorf("failed to extract tools from the tool set %q: %w", toolSet.Name(), err)
 		}
 
 		tools = append(tools, tsTools...)
 	}
-
-	return toolPreprocess(ctx, req, tools)
+	return tools, nil
 }
 
 // toolPreprocess runs tool preprocess on the given request
@@ -239,7 +282,7 @@ func toolPreprocess(ctx agent.InvocationContext, req *model.LLMRequest, tools []
 
 func (f *Flow) callLLM(ctx agent.InvocationContext, req *model.LLMRequest, stateDelta map[string]any) iter.Seq2[*model.LLMResponse, error] {
 	return func(yield func(*model.LLMResponse, error) bool) {
-		for _, callback := range f.BeforeModelCallbacks {
+		for _, callback := range withPluginHooks(ctx, f.BeforeModelCallbacks, func(p plugin.Plugin) BeforeModelCallback { return p.OnModelRequest }) {
 			cctx := icontext.NewCallbackContextWithDelta(ctx, stateDelta)
 			callbackResponse, callbackErr := callback(cctx, req)
 
@@ -260,7 +303,19 @@ func (f *Flow) callLLM(ctx agent.InvocationContext, req *model.LLMRequest, state
 		// TODO: RunLive mode when invocation_context.run_config.support_cfc is true.
 		useStream := runconfig.FromContext(ctx).StreamingMode == runconfig.StreamingModeSSE
 
+		start := time.Now()
+		var usage *genai.GenerateContentResponseUsageMetadata
+		var failed bool
+		defer func() {
+			metrics.RecordModelCall(ctx.Agent().Name(), f.Model.Name(), time.Since(start), usage, failed)
+		}()
 		for resp, err := range f.Model.GenerateContent(ctx, req, useStream) {
+			switch {
+			case err != nil || resp.ErrorCode != "":
+				failed = true
+			case resp.UsageMetadata != nil:
+				usage = resp.UsageMetadata
+			}
 			callbackResp, callbackErr := f.runAfterModelCallbacks(ctx, resp, stateDelta, err)
 			// TODO: check if we should stop iterator on the first error from stream or continue yielding next results.
 			if callbackErr != nil {
@@ -289,7 +344,7 @@ func (f *Flow) callLLM(ctx agent.InvocationContext, req *model.LLMRequest, state
 }
 
 func (f *Flow) runAfterModelCallbacks(ctx agent.InvocationContext, llmResp *model.LLMResponse, stateDelta map[string]any, llmErr error) (*model.LLMResponse, error) {
-	for _, callback := range f.AfterModelCallbacks {
+	for _, callback := range withPluginHooks(ctx, f.AfterModelCallbacks, func(p plugin.Plugin) AfterModelCallback { return p.OnModelResponse }) {
 		cctx := icontext.NewCallbackContextWithDelta(ctx, stateDelta)
 		callbackResponse, callbackErr := callback(cctx, llmResp, llmErr)
 
@@ -343,6 +398,34 @@ func (f *Flow) finalizeModelResponseEvent(ctx agent.InvocationContext, resp *mod
 	return ev
 }
 
+// requestTools returns the tools packed into req by the request processors.
+func requestTools(req *model.LLMRequest) (map[string]tool.Tool, error) {
+	// TODO: temporarily convert
+	tools := make(map[string]tool.Tool)
+	for k, v := range req.Tools {
+		t, ok := v.(tool.Tool)
+		if !ok {
+			return nil, fmt.Errorf("unexpected tool type %T for tool %v", v, k)
+		}
+		tools[k] = t
+	}
+	return tools, nil
+}
+
+// limitExceededEvent ends the invocation and returns the event reporting
+// the exceeded limit.
+func limitExceededEvent(ctx agent.InvocationContext, errorCode, errorMessage string) *session.Event {
+	ctx.EndInvocation()
+	ev := session.NewEvent(ctx.InvocationID())
+	ev.Author = ctx.Agent().Name()
+	ev.Branch = ctx.Branch()
+	ev.LLMResponse = model.LLMResponse{
+		ErrorCode:    errorCode,
+		ErrorMessage: errorMessage,
+	}
+	return ev
+}
+
 // findLongRunningFunctionCallIDs iterates over the FunctionCalls and
 // returns the callIDs of the long running functions
 func findLongRunningFunctionCallIDs(c *genai.Content, tools map[string]tool.Tool) []string {
@@ -359,10 +442,11 @@ func findLongRunningFunctionCallIDs(c *genai.Content, tools map[string]tool.Tool
 }
 
 // handleFunctionCalls calls the functions and returns the function response event.
+// The stateDelta, if any, is visible to the tools and included in the event.
 //
 // TODO: accept filters to include/exclude function calls.
 // TODO: check feasibility of running tool.Run concurrently.
-func (f *Flow) handleFunctionCalls(ctx agent.InvocationContext, toolsDict map[string]tool.Tool, resp *model.LLMResponse) (*session.Event, error) {
+func (f *Flow) handleFunctionCalls(ctx agent.InvocationContext, toolsDict map[string]tool.Tool, resp *model.LLMResponse, stateDelta map[string]any) (*session.Event, error) {
 	var fnResponseEvents []*session.Event
 
 	fnCalls := utils.FunctionCalls(resp.Content)
@@ -375,11 +459,28 @@ func (f *Flow) handleFunctionCalls(ctx agent.InvocationContext, toolsDict map[st
 		if !ok {
 			return nil, fmt.Errorf("tool %q is not a function tool", curTool.Name())
 		}
-		toolCtx := toolinternal.NewToolContext(ctx, fnCall.ID, &session.EventActions{StateDelta: make(map[string]any)})
-		// toolCtx := tool.
-		spans := telemetry.StartTrace(ctx, "execute_tool "+fnCall.Name)
+		spanCtx, spans := telemetry.StartTrace(ctx, "execute_tool "+fnCall.Name)
+		toolCtx := toolinternal.NewToolContext(icontext.WithContext(ctx, spanCtx), fnCall.ID, &session.EventActions{StateDelta: maps.Clone(stateDelta)})
 
+		start := time.Now()
 		result := f.callTool(funcTool, fnCall.Args, toolCtx)
+		duration := time.Since(start)
+		toolErr, failed := result["error"]
+		metrics.RecordToolCall(ctx.Agent().Name(), fnCall.Name, duration, failed)
+		logger := logging.FromContext(ctx).With("agent", ctx.Agent().Name(), "tool", fnCall.Name, "function_call_id", fnCall.ID, "duration", duration)
+		if failed {
+			logger.Warn("tool call failed", "error", toolErr)
+		} else {
+			logger.Debug("tool call")
+		}
+		if llmAgent := asLLMAgent(ctx.Agent()); llmAgent != nil && llmAgent.internal().ToolResponseSaver != nil && ctx.Artifacts() != nil && !failed {
+			// The full response is kept if it can't be saved.
+			if saved, err := llmAgent.internal().ToolResponseSaver(toolCtx, curTool, result); err != nil {
+				logger.Warn("failed to save tool response", "error", err)
+			} else if saved != nil {
+				result = saved
+			}
+		}
 
 		// TODO: agent.canonical_after_tool_callbacks
 		// TODO: handle long-running tool.
@@ -409,7 +510,7 @@ func (f *Flow) handleFunctionCalls(ctx agent.InvocationContext, toolsDict map[st
 		return mergedEvent, err
 	}
 	// this is needed for debug traces of parallel calls
-	spans := telemetry.StartTrace(ctx, "execute_tool (merged)")
+	_, spans := telemetry.StartTrace(ctx, "execute_tool (merged)")
 	telemetry.TraceMergedToolCalls(spans, mergedEvent)
 	return mergedEvent, nil
 }
@@ -438,7 +539,7 @@ func (f *Flow) callTool(tool toolinternal.FunctionTool, fArgs map[string]any, to
 }
 
 func (f *Flow) invokeBeforeToolCallbacks(tool toolinternal.FunctionTool, fArgs map[string]any, toolCtx tool.Context) (map[string]any, error) {
-	for _, callback := range f.BeforeToolCallbacks {
+	for _, callback := range withPluginHooks(toolCtx, f.BeforeToolCallbacks, func(p plugin.Plugin) BeforeToolCallback { return p.OnToolCall }) {
 		result, err := callback(toolCtx, tool, fArgs)
 		if err != nil {
 			return nil, fmt.Errorf("failed to execute callback: %w", err)
@@ -453,7 +554,7 @@ func (f *Flow) invokeBeforeToolCallbacks(tool toolinternal.FunctionTool, fArgs m
 }
 
 func (f *Flow) invokeAfterToolCallbacks(tool toolinternal.FunctionTool, fArgs map[string]any, toolCtx tool.Context, fResult map[string]any, fErr error) (map[string]any, error) {
-	for _, callback := range f.AfterToolCallbacks {
+	for _, callback := range withPluginHooks(toolCtx, f.AfterToolCallbacks, func(p plugin.Plugin) AfterToolCallback { return p.OnToolResult }) {
 		result, err := callback(toolCtx, tool, fArgs, fResult, fErr)
 		if err != nil {
 			return nil, fmt.Errorf("failed to execute callback: %w", err)
@@ -467,6 +568,20 @@ func (f *Flow) invokeAfterToolCallbacks(tool toolinternal.FunctionTool, fArgs ma
 	return nil, nil
 }
 
+// withPluginHooks returns the hooks of the plugins registered on the runner
+// followed by the agent's callbacks, so that plugins run first.
+func withPluginHooks[T any](ctx context.Context, callbacks []T, hook func(plugin.Plugin) T) []T {
+	plugins := plugininternal.FromContext(ctx)
+	if len(plugins) == 0 {
+		return callbacks
+	}
+	result := make([]T, 0, len(plugins)+len(callbacks))
+	for _, p := range plugins {
+		result = append(result, hook(p))
+	}
+	return append(result, callbacks...)
+}
+
 func mergeParallelFunctionResponseEvents(events []*session.Event) (*session.Event, error) {
 	switch len(events) {
 	case 0:
@@ -499,8 +614,8 @@ func mergeEventActions(base, other *session.EventActions) *session.EventActions
 	// flows/llm_flows/functions.py merge_parallel_function_response_events
 	//
 	// TODO: merge_parallel_function_response_events creates a "last one wins" scenario
-	// except parts and requested_auth_configs. Check with the ADK team about
-	// the intention.
+	// except parts, artifact deltas and requested_auth_configs. Check with the
+	// ADK team about the intention.
 	if other == nil {
 		return base
 	}
@@ -519,5 +634,17 @@ func mergeEventActions(base, other *session.EventActions) *session.EventActions
 	if other.StateDelta != nil {
 		base.StateDelta = other.StateDelta
 	}
+	if other.ArtifactDelta != nil {
+		if base.ArtifactDelta == nil {
+			base.ArtifactDelta = map[string]int64{}
+		}
+		maps.Copy(base.ArtifactDelta, other.ArtifactDelta)
+	}
+	if other.RequestedAuthConfigs != nil {
+		if base.RequestedAuthConfigs == nil {
+			base.RequestedAuthConfigs = map[string]*auth.Config{}
+		}
+		maps.Copy(base.RequestedAuthConfigs, other.RequestedAuthConfigs)
+	}
 	return base
 }
@@ -0,0 +1,68 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/auth"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+func TestMergeParallelFunctionResponseEvents(t *testing.T) {
+	apiKey := &auth.Config{Scheme: &auth.Scheme{Type: auth.SchemeTypeAPIKey, Name: "key", In: "header"}}
+	oauth := &auth.Config{Scheme: &auth.Scheme{Type: auth.SchemeTypeOAuth2}}
+	responseEvent := func(name string, actions session.EventActions) *session.Event {
+		return &session.Event{
+			LLMResponse: model.LLMResponse{Content: genai.NewContentFromFunctionResponse(name, map[string]any{}, "user")},
+			Actions:     actions,
+		}
+	}
+
+	got, err := mergeParallelFunctionResponseEvents([]*session.Event{
+		responseEvent("a", session.EventActions{
+			ArtifactDelta:        map[string]int64{"a.txt": 1},
+			RequestedAuthConfigs: map[string]*auth.Config{"call_a": apiKey},
+		}),
+		responseEvent("b", session.EventActions{
+			SkipSummarization:    true,
+			Escalate:             true,
+			TransferToAgent:      "other",
+			ArtifactDelta:        map[string]int64{"b.txt": 2},
+			RequestedAuthConfigs: map[string]*auth.Config{"call_b": oauth},
+		}),
+	})
+	if err != nil {
+		t.Fatalf("mergeParallelFunctionResponseEvents() failed: %v", err)
+	}
+
+	want := session.EventActions{
+		SkipSummarization:    true,
+		Escalate:             true,
+		TransferToAgent:      "other",
+		ArtifactDelta:        map[string]int64{"a.txt": 1, "b.txt": 2},
+		RequestedAuthConfigs: map[string]*auth.Config{"call_a": apiKey, "call_b": oauth},
+	}
+	if diff := cmp.Diff(want, got.Actions); diff != "" {
+		t.Errorf("merged actions mismatch (-want +got):\n%s", diff)
+	}
+	if len(got.Content.Parts) != 2 {
+		t.Errorf("got %d parts, want 2", len(got.Content.Parts))
+	}
+}
@@ -41,6 +41,10 @@ func basicRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest) e
 		req.Config.ResponseSchema = llmAgent.internal().OutputSchema
 		req.Config.ResponseMIMEType = "application/json"
 	}
+	if llmAgent.internal().OutputJSONSchema != nil {
+		req.Config.ResponseJsonSchema = llmAgent.internal().OutputJSONSchema.Schema()
+		req.Config.ResponseMIMEType = "application/json"
+	}
 	// TODO: missing features
 	//  populate LLMRequest LiveConnectConfig setting
 	return nil
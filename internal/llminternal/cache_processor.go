@@ -0,0 +1,82 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"encoding/json"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+)
+
+// cacheStatePrefix prefixes the session state key holding the metadata of
+// the cached contents of an agent.
+const cacheStatePrefix = "_adk_context_cache:"
+
+// cacheRequestProcessor asks the model to cache the static part of the
+// request if the agent is configured to, passing the cache used by the
+// previous request of the agent in the session.
+func cacheRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest) error {
+	llmAgent := asLLMAgent(ctx.Agent())
+	if llmAgent == nil || llmAgent.internal().CacheConfig == nil {
+		return nil
+	}
+	req.CacheConfig = llmAgent.internal().CacheConfig
+	if v, err := ctx.Session().State().Get(cacheStatePrefix + ctx.Agent().Name()); err == nil {
+		req.CacheMetadata = decodeCacheMetadata(v)
+	}
+	return nil
+}
+
+// recordCacheMetadata stores the metadata of the cache used for resp in
+// stateDelta if it differs from the one of the request.
+func recordCacheMetadata(ctx agent.InvocationContext, req *model.LLMRequest, resp *model.LLMResponse, stateDelta map[string]any) {
+	meta := resp.CacheMetadata
+	if req.CacheConfig == nil || meta == nil {
+		return
+	}
+	if prev := req.CacheMetadata; prev != nil && prev.Name == meta.Name && prev.Fingerprint == meta.Fingerprint && prev.ExpireTime.Equal(meta.ExpireTime) {
+		return
+	}
+	stateDelta[cacheStatePrefix+ctx.Agent().Name()] = encodeCacheMetadata(meta)
+}
+
+// encodeCacheMetadata converts meta into a JSON value, so that it is
+// stored the same way by all the session services.
+func encodeCacheMetadata(meta *model.CacheMetadata) map[string]any {
+	b, err := json.Marshal(meta)
+	if err != nil {
+		return nil
+	}
+	var v map[string]any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil
+	}
+	return v
+}
+
+// decodeCacheMetadata is the inverse of encodeCacheMetadata. It returns nil
+// if v isn't valid cache metadata.
+func decodeCacheMetadata(v any) *model.CacheMetadata {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var meta model.CacheMetadata
+	if err := json.Unmarshal(b, &meta); err != nil || meta.Name == "" {
+		return nil
+	}
+	return &meta
+}
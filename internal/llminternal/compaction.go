@@ -0,0 +1,92 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"slices"
+
+	"google.golang.org/adk/session"
+)
+
+// applyCompactions replaces the events summarized by compaction events with
+// the summaries. A compaction summarizing a range within the range of another
+// compaction is superseded by it. The compaction events themselves are not
+// returned.
+func applyCompactions(events []*session.Event) []*session.Event {
+	var compactions []*session.Event
+	for _, ev := range events {
+		if c := ev.Actions.Compaction; c != nil && c.Content != nil {
+			compactions = append(compactions, ev)
+		}
+	}
+	if len(compactions) == 0 {
+		return events
+	}
+
+	var active []*session.EventCompaction
+	for _, ev := range compactions {
+		superseded := slices.ContainsFunc(compactions, func(other *session.Event) bool {
+			return supersedes(other, ev)
+		})
+		if !superseded {
+			active = append(active, ev.Actions.Compaction)
+		}
+	}
+
+	var result []*session.Event
+	emitted := make(map[*session.EventCompaction]bool)
+	for _, ev := range events {
+		if ev.Actions.Compaction != nil {
+			continue
+		}
+		c := coveringCompaction(active, ev)
+		if c == nil {
+			result = append(result, ev)
+			continue
+		}
+		if emitted[c] {
+			continue
+		}
+		emitted[c] = true
+		summary := &session.Event{Author: "user", Timestamp: c.StartTime}
+		summary.Content = c.Content
+		result = append(result, summary)
+	}
+	return result
+}
+
+// supersedes reports whether the compaction of event a covers the range of
+// the compaction of event b. Of two compactions of the same range, the later
+// one is used.
+func supersedes(a, b *session.Event) bool {
+	if a == b {
+		return false
+	}
+	ca, cb := a.Actions.Compaction, b.Actions.Compaction
+	if ca.StartTime.After(cb.StartTime) || ca.EndTime.Before(cb.EndTime) {
+		return false
+	}
+	sameRange := ca.StartTime.Equal(cb.StartTime) && ca.EndTime.Equal(cb.EndTime)
+	return !sameRange || a.Timestamp.After(b.Timestamp)
+}
+
+func coveringCompaction(compactions []*session.EventCompaction, ev *session.Event) *session.EventCompaction {
+	for _, c := range compactions {
+		if !ev.Timestamp.Before(c.StartTime) && !ev.Timestamp.After(c.EndTime) {
+			return c
+		}
+	}
+	return nil
+}
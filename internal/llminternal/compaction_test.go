@@ -0,0 +1,88 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+func TestApplyCompactions(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(i int) time.Time { return start.Add(time.Duration(i) * time.Minute) }
+	textEvent := func(i int, text string) *session.Event {
+		return &session.Event{
+			ID: text, Author: "user", Timestamp: at(i),
+			LLMResponse: model.LLMResponse{Content: genai.NewContentFromText(text, genai.RoleUser)},
+		}
+	}
+	compactionEvent := func(i, from, to int, summary string) *session.Event {
+		return &session.Event{
+			ID: "compaction-" + summary, Author: "user", Timestamp: at(i),
+			Actions: session.EventActions{Compaction: &session.EventCompaction{
+				StartTime: at(from), EndTime: at(to), Content: genai.NewContentFromText(summary, genai.RoleModel),
+			}},
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		events []*session.Event
+		want   []string
+	}{
+		{
+			name:   "no compaction",
+			events: []*session.Event{textEvent(0, "a"), textEvent(1, "b")},
+			want:   []string{"a", "b"},
+		},
+		{
+			name:   "compacted prefix",
+			events: []*session.Event{textEvent(0, "a"), textEvent(1, "b"), textEvent(2, "c"), compactionEvent(3, 0, 1, "s1"), textEvent(4, "d")},
+			want:   []string{"s1", "c", "d"},
+		},
+		{
+			name: "superseded compaction",
+			events: []*session.Event{
+				textEvent(0, "a"), textEvent(1, "b"), compactionEvent(2, 0, 1, "s1"),
+				textEvent(3, "c"), compactionEvent(4, 0, 3, "s2"), textEvent(5, "d"),
+			},
+			want: []string{"s2", "d"},
+		},
+		{
+			name: "same range",
+			events: []*session.Event{
+				textEvent(0, "a"), compactionEvent(1, 0, 0, "old"), compactionEvent(2, 0, 0, "new"), textEvent(3, "b"),
+			},
+			want: []string{"new", "b"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, ev := range applyCompactions(tc.events) {
+				got = append(got, ev.Content.Parts[0].Text)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("applyCompactions() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -25,6 +25,8 @@ import (
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
+	"google.golang.org/adk/auth"
+	icontext "google.golang.org/adk/internal/context"
 	"google.golang.org/adk/internal/utils"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/session"
@@ -50,6 +52,14 @@ func ContentsRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest
 			events = append(events, e)
 		}
 	}
+	events = applyCompactions(events)
+	if process := llmAgent.internal().EventsProcessor; process != nil && llmAgent.internal().IncludeContents != "none" {
+		var err error
+		events, err = process(icontext.NewReadonlyContext(ctx), events)
+		if err != nil {
+			return fmt.Errorf("failed to process events: %w", err)
+		}
+	}
 	contents, err := fn(ctx.Agent().Name(), ctx.Branch(), events)
 	if err != nil {
 		return err
@@ -504,7 +514,7 @@ func stringify(v any) string {
 
 // requestEUCFunctionCallName is a special function to handle credential
 // request.
-const requestEUCFunctionCallName = "adk_request_credential"
+const requestEUCFunctionCallName = auth.RequestCredentialFunctionName
 
 func isAuthEvent(ev *session.Event) bool {
 	c := utils.Content(ev)
@@ -15,6 +15,7 @@
 package llminternal
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
 	"slices"
@@ -24,8 +25,10 @@ import (
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/internal/agent/parentmap"
 	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/logging"
 	"google.golang.org/adk/internal/utils"
 	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
 )
 
 // TODO: Remove this once state keywords are implemented and replace with those consts
@@ -146,7 +149,9 @@ func replaceMatch(ctx agent.InvocationContext, match string) (string, error) {
 	value, err := ctx.Session().State().Get(varName)
 	if err != nil {
 		if optional {
-			// TODO: log error when !errors.Is(err, session.ErrStateKeyNotExist)
+			if !errors.Is(err, session.ErrStateKeyNotExist) {
+				logging.FromContext(ctx).Warn("failed to read optional state variable", "key", varName, "error", err)
+			}
 			return "", nil
 		}
 		return "", err
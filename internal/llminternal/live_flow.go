@@ -0,0 +1,164 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"sync"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/agent/runconfig"
+	"google.golang.org/adk/internal/telemetry"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+// runLive runs the agent over a live connection to the model. The user input
+// is read from the invocation's LiveRequestQueue and forwarded to the model
+// while the model responses are turned into events. Function calls are
+// executed and their responses are sent back over the same connection.
+func (f *Flow) runLive(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		rc := runconfig.FromContext(ctx)
+		queue := rc.LiveRequestQueue
+		if queue == nil {
+			yield(nil, errors.New("live mode requires a live request queue"))
+			return
+		}
+		if f.Model == nil {
+			yield(nil, fmt.Errorf("agent %q has no Model configured; ensure Model is set in llmagent.Config", ctx.Agent().Name()))
+			return
+		}
+		liveModel, ok := f.Model.(model.LiveLLM)
+		if !ok {
+			yield(nil, fmt.Errorf("model %q does not support live mode", f.Model.Name()))
+			return
+		}
+
+		req := &model.LLMRequest{}
+		if err := f.preprocess(ctx, req); err != nil {
+			yield(nil, err)
+			return
+		}
+		if ctx.Ended() {
+			return
+		}
+		req.LiveConnectConfig = &genai.LiveConnectConfig{
+			ResponseModalities:       rc.ResponseModalities,
+			SpeechConfig:             rc.SpeechConfig,
+			InputAudioTranscription:  rc.InputAudioTranscription,
+			OutputAudioTranscription: rc.OutputAudioTranscription,
+		}
+		tools, err := requestTools(req)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		connCtx, cancel := context.WithCancel(ctx)
+		conn, err := liveModel.Connect(connCtx, req)
+		if err != nil {
+			cancel()
+			yield(nil, fmt.Errorf("failed to connect to model: %w", err))
+			return
+		}
+
+		// The forwarder has to stop before the connection is closed and
+		// before another agent starts reading from the queue.
+		var wg sync.WaitGroup
+		stop := func() {
+			cancel()
+			wg.Wait()
+			conn.Close()
+		}
+		defer stop()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for liveReq := range queue.Receive(connCtx) {
+				if err := conn.Send(liveReq); err != nil {
+					break
+				}
+			}
+			// The user closed the queue or the send failed, either way the
+			// session is over.
+			conn.Close()
+		}()
+
+		_, spans := telemetry.StartTrace(ctx, "call_llm")
+		for resp, err := range conn.Receive() {
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if err := f.postprocess(ctx, req, resp); err != nil {
+				yield(nil, err)
+				return
+			}
+			if resp.Content == nil && resp.UsageMetadata == nil && resp.ErrorCode == "" && !resp.Interrupted && !resp.TurnComplete &&
+				resp.InputTranscription == nil && resp.OutputTranscription == nil {
+				continue
+			}
+
+			modelResponseEvent := f.finalizeModelResponseEvent(ctx, resp, tools, make(map[string]any))
+			if resp.InputTranscription != nil {
+				// The transcript is what the user said.
+				modelResponseEvent.Author = "user"
+			}
+			telemetry.TraceLLMCall(spans, ctx.Session().ID(), req, modelResponseEvent)
+			if !yield(modelResponseEvent, nil) {
+				return
+			}
+
+			ev, err := f.handleFunctionCalls(ctx, tools, resp, nil)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if ev == nil {
+				continue
+			}
+			if !yield(ev, nil) {
+				return
+			}
+
+			if ev.Actions.TransferToAgent != "" {
+				nextAgent := f.agentToRun(ctx, ev.Actions.TransferToAgent)
+				if nextAgent == nil {
+					yield(nil, fmt.Errorf("failed to find agent: %s", ev.Actions.TransferToAgent))
+					return
+				}
+				// The next agent opens its own connection.
+				stop()
+				for ev, err := range nextAgent.Run(ctx) {
+					if !yield(ev, err) || err != nil {
+						return
+					}
+				}
+				return
+			}
+			if err := conn.Send(&model.LiveRequest{Content: ev.Content}); err != nil {
+				yield(nil, fmt.Errorf("failed to send function responses: %w", err))
+				return
+			}
+		}
+	}
+}
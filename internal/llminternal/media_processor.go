@@ -0,0 +1,69 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"slices"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+)
+
+// PartConverter returns the replacement of a non-text part of a user
+// content, or nil to keep the part.
+type PartConverter func(ctx agent.InvocationContext, part *genai.Part) (*genai.Part, error)
+
+// mediaRequestProcessor replaces the media parts of the user contents of req
+// by the parts returned by the agent's PartConverter. It must run after the
+// contents are assembled.
+func mediaRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest) error {
+	llmAgent := asLLMAgent(ctx.Agent())
+	if llmAgent == nil || llmAgent.internal().PartConverter == nil {
+		return nil
+	}
+	convert := llmAgent.internal().PartConverter
+	for i, content := range req.Contents {
+		if content == nil || content.Role != genai.RoleUser {
+			continue
+		}
+		var parts []*genai.Part
+		for j, part := range content.Parts {
+			if part == nil || (part.InlineData == nil && part.FileData == nil) {
+				continue
+			}
+			converted, err := convert(ctx, part)
+			if err != nil {
+				return err
+			}
+			if converted == nil {
+				continue
+			}
+			// The contents are shared with the session events, they are
+			// copied before being modified.
+			if parts == nil {
+				parts = slices.Clone(content.Parts)
+			}
+			parts[j] = converted
+		}
+		if parts != nil {
+			copied := *content
+			copied.Parts = parts
+			req.Contents[i] = &copied
+		}
+	}
+	return nil
+}
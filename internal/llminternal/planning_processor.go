@@ -0,0 +1,77 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"fmt"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/model"
+)
+
+// nlPlanningRequestProcessor lets the agent's planner adjust the request and
+// append its planning instruction. Thoughts from earlier turns are unmarked,
+// so the model sees its previous plans as regular text.
+func nlPlanningRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest) error {
+	llmAgent := asLLMAgent(ctx.Agent())
+	if llmAgent == nil || llmAgent.internal().Planner == nil {
+		return nil
+	}
+	instruction, err := llmAgent.internal().Planner.BuildPlanningInstruction(icontext.NewReadonlyContext(ctx), req)
+	if err != nil {
+		return fmt.Errorf("failed to build planning instruction: %w", err)
+	}
+	if instruction != "" {
+		utils.AppendInstructions(req, instruction)
+	}
+	removeThoughts(req.Contents)
+	return nil
+}
+
+// nlPlanningResponseProcessor lets the agent's planner rewrite the parts of
+// a complete model response.
+func nlPlanningResponseProcessor(ctx agent.InvocationContext, req *model.LLMRequest, resp *model.LLMResponse) error {
+	llmAgent := asLLMAgent(ctx.Agent())
+	if llmAgent == nil || llmAgent.internal().Planner == nil {
+		return nil
+	}
+	if resp == nil || resp.Partial || resp.Content == nil || len(resp.Content.Parts) == 0 {
+		return nil
+	}
+	if parts := llmAgent.internal().Planner.ProcessPlanningResponse(icontext.NewCallbackContext(ctx), resp.Content.Parts); parts != nil {
+		resp.Content.Parts = parts
+	}
+	return nil
+}
+
+// removeThoughts clears the thought flag of all parts. The contents are
+// cloned from the session events by ContentsRequestProcessor, so they can be
+// changed in place.
+func removeThoughts(contents []*genai.Content) {
+	for _, c := range contents {
+		if c == nil {
+			continue
+		}
+		for _, p := range c.Parts {
+			if p != nil {
+				p.Thought = false
+			}
+		}
+	}
+}
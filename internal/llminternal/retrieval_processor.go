@@ -0,0 +1,46 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llminternal
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/adk/agent"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/utils"
+	"google.golang.org/adk/model"
+)
+
+// retrievalRequestProcessor appends the context returned by the agent's
+// ContextProvider for the current user content to req's instructions.
+func retrievalRequestProcessor(ctx agent.InvocationContext, req *model.LLMRequest) error {
+	llmAgent := asLLMAgent(ctx.Agent())
+	if llmAgent == nil || llmAgent.internal().ContextProvider == nil {
+		return nil
+	}
+	query := strings.TrimSpace(strings.Join(utils.TextParts(ctx.UserContent()), "\n"))
+	if query == "" {
+		return nil
+	}
+	retrieved, err := llmAgent.internal().ContextProvider(icontext.NewReadonlyContext(ctx), query)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve context: %w", err)
+	}
+	if retrieved != "" {
+		utils.AppendInstructions(req, retrieved)
+	}
+	return nil
+}
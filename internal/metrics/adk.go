@@ -0,0 +1,102 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"time"
+
+	"google.golang.org/genai"
+)
+
+// Default is the registry of the ADK metrics.
+var Default = NewRegistry()
+
+// latencyBuckets are the upper bounds, in seconds, of the latency histograms.
+var latencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// waitBuckets are the upper bounds, in seconds, of the wait histograms.
+var waitBuckets = []float64{0.001, 0.01, 0.1, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+var (
+	invocations      = Default.NewCounter("adk_invocations_total", "Number of runner invocations.", "app")
+	invocationErrors = Default.NewCounter("adk_invocation_errors_total", "Number of runner invocations that failed.", "app")
+
+	agentRuns      = Default.NewCounter("adk_agent_runs_total", "Number of agent runs.", "agent")
+	agentRunErrors = Default.NewCounter("adk_agent_run_errors_total", "Number of agent runs that failed.", "agent")
+
+	modelCalls      = Default.NewCounter("adk_model_calls_total", "Number of model calls.", "agent", "model")
+	modelCallErrors = Default.NewCounter("adk_model_call_errors_total", "Number of model calls that failed.", "agent", "model")
+	modelLatency    = Default.NewHistogram("adk_model_call_duration_seconds", "Duration of the model calls.", latencyBuckets, "agent", "model")
+	modelQuotaWait  = Default.NewHistogram("adk_model_quota_wait_seconds", "Time the model calls waited for the quota limits.", waitBuckets, "model")
+	modelTokens     = Default.NewCounter("adk_model_tokens_total", "Number of tokens used by the model calls, by type.", "agent", "model", "type")
+
+	toolCalls      = Default.NewCounter("adk_tool_calls_total", "Number of tool calls.", "agent", "tool")
+	toolCallErrors = Default.NewCounter("adk_tool_call_errors_total", "Number of tool calls that failed.", "agent", "tool")
+	toolLatency    = Default.NewHistogram("adk_tool_call_duration_seconds", "Duration of the tool calls.", latencyBuckets, "agent", "tool")
+)
+
+// RecordInvocation records a runner invocation of the app.
+func RecordInvocation(app string, failed bool) {
+	invocations.Inc(app)
+	if failed {
+		invocationErrors.Inc(app)
+	}
+}
+
+// RecordAgentRun records a run of the agent.
+func RecordAgentRun(agent string, failed bool) {
+	agentRuns.Inc(agent)
+	if failed {
+		agentRunErrors.Inc(agent)
+	}
+}
+
+// RecordModelCall records a call of the model by the agent. usage is the
+// usage metadata of the last response, if any.
+func RecordModelCall(agent, model string, latency time.Duration, usage *genai.GenerateContentResponseUsageMetadata, failed bool) {
+	modelCalls.Inc(agent, model)
+	if failed {
+		modelCallErrors.Inc(agent, model)
+	}
+	modelLatency.Observe(latency.Seconds(), agent, model)
+	if usage == nil {
+		return
+	}
+	for typ, count := range map[string]int32{
+		"prompt":     usage.PromptTokenCount,
+		"candidates": usage.CandidatesTokenCount,
+		"thoughts":   usage.ThoughtsTokenCount,
+		"cached":     usage.CachedContentTokenCount,
+	} {
+		if count > 0 {
+			modelTokens.Add(float64(count), agent, model, typ)
+		}
+	}
+}
+
+// RecordModelQuotaWait records the time a call of the model waited for the
+// quota limits.
+func RecordModelQuotaWait(model string, wait time.Duration) {
+	modelQuotaWait.Observe(wait.Seconds(), model)
+}
+
+// RecordToolCall records a call of the tool by the agent.
+func RecordToolCall(agent, tool string, latency time.Duration, failed bool) {
+	toolCalls.Inc(agent, tool)
+	if failed {
+		toolCallErrors.Inc(agent, tool)
+	}
+	toolLatency.Observe(latency.Seconds(), agent, tool)
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_test
+
+import (
+	"context"
+	"iter"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/internal/metrics"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+func TestRegistry_Write(t *testing.T) {
+	r := metrics.NewRegistry()
+	calls := r.NewCounter("calls_total", "Number of calls.", "tool")
+	latency := r.NewHistogram("latency_seconds", "Latency of the calls.", []float64{0.1, 1}, "tool")
+	calls.Inc("search")
+	calls.Add(2, `say "hi"`)
+	latency.Observe(0.05, "search")
+	latency.Observe(0.5, "search")
+	latency.Observe(5, "search")
+
+	var sb strings.Builder
+	if err := r.Write(&sb); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+	want := `# HELP calls_total Number of calls.
+# TYPE calls_total counter
+calls_total{tool="say \"hi\""} 2
+calls_total{tool="search"} 1
+# HELP latency_seconds Latency of the calls.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{tool="search",le="0.1"} 1
+latency_seconds_bucket{tool="search",le="1"} 2
+latency_seconds_bucket{tool="search",le="+Inf"} 3
+latency_seconds_sum{tool="search"} 5.55
+latency_seconds_count{tool="search"} 3
+`
+	if diff := cmp.Diff(want, sb.String()); diff != "" {
+		t.Errorf("Write() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDefault_RecordsAgentMetrics(t *testing.T) {
+	search, err := functiontool.New(functiontool.Config{
+		Name:        "search",
+		Description: "searches the web",
+	}, func(tool.Context, map[string]any) (map[string]any, error) {
+		return map[string]any{"result": "found"}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	llm := &usageModel{MockModel: testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromFunctionCall("search", map[string]any{}, genai.RoleModel),
+		genai.NewContentFromText("done", genai.RoleModel),
+	}}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:  "metrics_agent",
+		Model: llm,
+		Tools: []tool.Tool{search},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testutil.CollectEvents(testutil.NewTestAgentRunner(t, a).Run(t, "session", "search")); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	metrics.Default.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	got := rec.Body.String()
+	for _, want := range []string{
+		`adk_agent_runs_total{agent="metrics_agent"} 1`,
+		`adk_model_calls_total{agent="metrics_agent",model="mock"} 2`,
+		`adk_model_call_duration_seconds_count{agent="metrics_agent",model="mock"} 2`,
+		`adk_model_tokens_total{agent="metrics_agent",model="mock",type="prompt"} 20`,
+		`adk_model_tokens_total{agent="metrics_agent",model="mock",type="candidates"} 10`,
+		`adk_tool_calls_total{agent="metrics_agent",tool="search"} 1`,
+	} {
+		if !strings.Contains(got, want+"\n") {
+			t.Errorf("metrics do not contain %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, `adk_tool_call_errors_total{agent="metrics_agent"`) {
+		t.Errorf("metrics contain tool errors, got:\n%s", got)
+	}
+}
+
+// usageModel reports a fixed token usage on every response.
+type usageModel struct {
+	testutil.MockModel
+}
+
+func (m *usageModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		for resp, err := range m.MockModel.GenerateContent(ctx, req, stream) {
+			if resp != nil {
+				resp.UsageMetadata = &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 10, CandidatesTokenCount: 5}
+			}
+			if !yield(resp, err) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package plugininternal passes the plugins registered on a runner to the
+// agents it runs.
+package plugininternal
+
+import (
+	"context"
+
+	"google.golang.org/adk/plugin"
+)
+
+func ToContext(ctx context.Context, plugins []plugin.Plugin) context.Context {
+	return context.WithValue(ctx, pluginsCtxKey, plugins)
+}
+
+func FromContext(ctx context.Context) []plugin.Plugin {
+	plugins, ok := ctx.Value(pluginsCtxKey).([]plugin.Plugin)
+	if !ok {
+		return nil
+	}
+	return plugins
+}
+
+type ctxKey int
+
+const pluginsCtxKey ctxKey = 0
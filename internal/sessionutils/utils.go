@@ -15,7 +15,10 @@
 package sessionutils
 
 import (
+	"encoding/base64"
+	"fmt"
 	"maps"
+	"strconv"
 	"strings"
 )
 
@@ -72,3 +75,25 @@ func MergeStates(appState, userState, sessionState map[string]any) map[string]an
 
 	return mergedState
 }
+
+// EncodePageToken returns the page token of the page starting at offset.
+func EncodePageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+// DecodePageToken returns the offset of the page of token. An empty token
+// is the first page.
+func DecodePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, fmt.Errorf("invalid page token %q", token)
+	}
+	offset, err := strconv.Atoi(string(b))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid page token %q", token)
+	}
+	return offset, nil
+}
@@ -22,14 +22,13 @@ import (
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/genai"
 
-	"google.golang.org/adk/agent"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/session"
-	"google.golang.org/adk/tool"
 )
 
 type tracerProviderHolder struct {
@@ -48,6 +47,9 @@ var (
 		spanProcessors: []sdktrace.SpanProcessor{},
 		mu:             &sync.RWMutex{},
 	}
+
+	externalTracerMu       sync.RWMutex
+	externalTracerProvider trace.TracerProvider
 )
 
 const (
@@ -58,6 +60,10 @@ const (
 	genAiToolCallID       = "gen_ai.tool.call.id"
 	genAiSystemName       = "gen_ai.system"
 	genAiRequestModelName = "gen_ai.request.model"
+	genAiAgentName        = "gen_ai.agent.name"
+	genAiAgentDescription = "gen_ai.agent.description"
+	genAiConversationID   = "gen_ai.conversation.id"
+	urlFull               = "url.full"
 
 	gcpVertexAgentLLMRequestName   = "gcp.vertex.agent.llm_request"
 	gcpVertexAgentToolCallArgsName = "gcp.vertex.agent.tool_call_args"
@@ -66,8 +72,11 @@ const (
 	gcpVertexAgentLLMResponseName  = "gcp.vertex.agent.llm_response"
 	gcpVertexAgentInvocationID     = "gcp.vertex.agent.invocation_id"
 	gcpVertexAgentSessionID        = "gcp.vertex.agent.session_id"
+	gcpVertexAgentUserID           = "gcp.vertex.agent.user_id"
+	gcpVertexAgentAppName          = "gcp.vertex.agent.app_name"
 
 	executeToolName = "execute_tool"
+	invokeAgentName = "invoke_agent"
 	mergeToolName   = "(merged tools)"
 )
 
@@ -93,28 +102,105 @@ func RegisterTelemetry() {
 	})
 }
 
-// If the global tracer is not set, the default NoopTracerProvider will be used.
+// SetTracerProvider sets the tracer provider used in addition to the local
+// one. If it is not set, the global tracer provider is used.
+func SetTracerProvider(tp trace.TracerProvider) {
+	externalTracerMu.Lock()
+	defer externalTracerMu.Unlock()
+	externalTracerProvider = tp
+}
+
+// If the external tracer is not set, the global tracer is used. If the global
+// tracer is not set either, the default NoopTracerProvider will be used.
 // That means that the spans are NOT recording/exporting
 // If the local tracer is not set, we'll set up tracer with all registered span processors.
 func getTracers() []trace.Tracer {
 	if localTracer.tp == nil {
 		RegisterTelemetry()
 	}
+	externalTracerMu.RLock()
+	external := externalTracerProvider
+	externalTracerMu.RUnlock()
+	if external == nil {
+		external = otel.GetTracerProvider()
+	}
 	return []trace.Tracer{
 		localTracer.tp.Tracer(systemName),
-		otel.GetTracerProvider().Tracer(systemName),
+		external.Tracer(systemName),
 	}
 }
 
-// StartTrace returns two spans to start emitting events, one from global tracer and second from the local.
-func StartTrace(ctx context.Context, traceName string) []trace.Span {
+// spansKey is the context key of the spans started by StartTrace.
+type spansKey struct{}
+
+// StartTrace returns two spans to start emitting events, one from the local
+// tracer and second from the external one.
+// The returned context carries the spans, so that the spans started from it
+// are their children. The span of the external tracer is also set as the
+// current span of the context, to be propagated by instrumented libraries.
+func StartTrace(ctx context.Context, traceName string) (context.Context, []trace.Span) {
 	tracers := getTracers()
+	parents, _ := ctx.Value(spansKey{}).([]trace.Span)
 	spans := make([]trace.Span, len(tracers))
 	for i, tracer := range tracers {
-		_, span := tracer.Start(ctx, traceName)
+		parentCtx := ctx
+		if i < len(parents) {
+			parentCtx = trace.ContextWithSpan(ctx, parents[i])
+		}
+		_, span := tracer.Start(parentCtx, traceName)
 		spans[i] = span
 	}
-	return spans
+	ctx = context.WithValue(ctx, spansKey{}, spans)
+	return trace.ContextWithSpan(ctx, spans[len(spans)-1]), spans
+}
+
+// EndTrace ends the spans, recording err if it is not nil.
+func EndTrace(spans []trace.Span, err error) {
+	for _, span := range spans {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+// TraceInvocation sets the attributes of a runner invocation.
+func TraceInvocation(spans []trace.Span, appName, userID, sessionID, invocationID string) {
+	for _, span := range spans {
+		span.SetAttributes(
+			attribute.String(gcpVertexAgentAppName, appName),
+			attribute.String(gcpVertexAgentUserID, userID),
+			attribute.String(gcpVertexAgentSessionID, sessionID),
+			attribute.String(gcpVertexAgentInvocationID, invocationID),
+		)
+	}
+}
+
+// TraceAgentRun sets the attributes of an agent run.
+func TraceAgentRun(spans []trace.Span, agentName, agentDescription, sessionID, invocationID string) {
+	for _, span := range spans {
+		span.SetAttributes(
+			attribute.String(genAiOperationName, invokeAgentName),
+			attribute.String(genAiAgentName, agentName),
+			attribute.String(genAiAgentDescription, agentDescription),
+			attribute.String(genAiConversationID, sessionID),
+			attribute.String(gcpVertexAgentInvocationID, invocationID),
+		)
+	}
+}
+
+// TraceA2ACall sets the attributes of a call to a remote A2A agent.
+func TraceA2ACall(spans []trace.Span, agentName, url, sessionID, invocationID string) {
+	for _, span := range spans {
+		span.SetAttributes(
+			attribute.String(genAiOperationName, invokeAgentName),
+			attribute.String(genAiAgentName, agentName),
+			attribute.String(urlFull, url),
+			attribute.String(genAiConversationID, sessionID),
+			attribute.String(gcpVertexAgentInvocationID, invocationID),
+		)
+	}
 }
 
 // TraceMergedToolCalls traces the tool execution events.
@@ -140,8 +226,14 @@ func TraceMergedToolCalls(spans []trace.Span, fnResponseEvent *session.Event) {
 	}
 }
 
+// Tool is the subset of tool.Tool used in traces.
+type Tool interface {
+	Name() string
+	Description() string
+}
+
 // TraceToolCall traces the tool execution events.
-func TraceToolCall(spans []trace.Span, tool tool.Tool, fnArgs map[string]any, fnResponseEvent *session.Event) {
+func TraceToolCall(spans []trace.Span, tool Tool, fnArgs map[string]any, fnResponseEvent *session.Event) {
 	if fnResponseEvent == nil {
 		return
 	}
@@ -188,13 +280,13 @@ func TraceToolCall(spans []trace.Span, tool tool.Tool, fnArgs map[string]any, fn
 }
 
 // TraceLLMCall fills the call_llm event details.
-func TraceLLMCall(spans []trace.Span, agentCtx agent.InvocationContext, llmRequest *model.LLMRequest, event *session.Event) {
+func TraceLLMCall(spans []trace.Span, sessionID string, llmRequest *model.LLMRequest, event *session.Event) {
 	for _, span := range spans {
 		attributes := []attribute.KeyValue{
 			attribute.String(genAiSystemName, systemName),
 			attribute.String(genAiRequestModelName, llmRequest.Model),
 			attribute.String(gcpVertexAgentInvocationID, event.InvocationID),
-			attribute.String(gcpVertexAgentSessionID, agentCtx.Session().ID()),
+			attribute.String(gcpVertexAgentSessionID, sessionID),
 			attribute.String(gcpVertexAgentEventID, event.ID),
 			attribute.String(gcpVertexAgentLLMRequestName, safeSerialize(llmRequestToTrace(llmRequest))),
 			attribute.String(gcpVertexAgentLLMResponseName, safeSerialize(event.LLMResponse)),
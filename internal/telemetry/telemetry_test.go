@@ -0,0 +1,81 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/internal/telemetry"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+func TestSpanHierarchy(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	telemetry.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { telemetry.SetTracerProvider(nil) })
+
+	weather, err := functiontool.New(functiontool.Config{
+		Name:        "weather",
+		Description: "returns the weather",
+	}, func(tool.Context, map[string]any) (map[string]any, error) {
+		return map[string]any{"weather": "sunny"}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := llmagent.New(llmagent.Config{
+		Name: "root",
+		Model: &testutil.MockModel{Responses: []*genai.Content{
+			genai.NewContentFromFunctionCall("weather", map[string]any{}, genai.RoleModel),
+			genai.NewContentFromText("it is sunny", genai.RoleModel),
+		}},
+		Tools: []tool.Tool{weather},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testutil.CollectEvents(testutil.NewTestAgentRunner(t, a).Run(t, "session", "weather?")); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	names := map[string]string{}
+	for _, s := range spans {
+		names[s.SpanContext.SpanID().String()] = s.Name
+	}
+	// Maps the span names to the name of their parent.
+	got := map[string]string{}
+	for _, s := range spans {
+		got[s.Name] = names[s.Parent.SpanID().String()]
+	}
+	want := map[string]string{
+		"invocation":            "",
+		"invoke_agent root":     "invocation",
+		"call_llm":              "invoke_agent root",
+		"execute_tool weather":  "invoke_agent root",
+		"execute_tool (merged)": "invoke_agent root",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("span parents mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testutil
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"sync"
+
+	"google.golang.org/adk/model"
+)
+
+// MockLiveModel is a model.LiveLLM whose connections answer every request
+// sent to them with the responses returned by Respond.
+type MockLiveModel struct {
+	MockModel
+	// Respond returns the responses to a request sent over a connection.
+	Respond func(req *model.LiveRequest) []*model.LLMResponse
+
+	mu              sync.Mutex
+	ConnectRequests []*model.LLMRequest
+}
+
+// Connect implements model.LiveLLM.
+func (m *MockLiveModel) Connect(ctx context.Context, req *model.LLMRequest) (model.LiveConnection, error) {
+	m.mu.Lock()
+	m.ConnectRequests = append(m.ConnectRequests, req)
+	m.mu.Unlock()
+	return &mockLiveConnection{
+		respond: m.Respond,
+		sent:    make(chan *model.LiveRequest, 16),
+		done:    make(chan struct{}),
+	}, nil
+}
+
+var _ model.LiveLLM = (*MockLiveModel)(nil)
+
+type mockLiveConnection struct {
+	respond   func(req *model.LiveRequest) []*model.LLMResponse
+	sent      chan *model.LiveRequest
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (c *mockLiveConnection) Send(req *model.LiveRequest) error {
+	select {
+	case c.sent <- req:
+		return nil
+	case <-c.done:
+		return errors.New("connection is closed")
+	}
+}
+
+func (c *mockLiveConnection) Receive() iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		for {
+			select {
+			case <-c.done:
+				return
+			case req := <-c.sent:
+				for _, resp := range c.respond(req) {
+					if !yield(resp, nil) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+func (c *mockLiveConnection) Close() error {
+	c.closeOnce.Do(func() { close(c.done) })
+	return nil
+}
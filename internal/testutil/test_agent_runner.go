@@ -61,6 +61,20 @@ func (r *TestAgentRunner) session(t *testing.T, appName, userID, sessionID strin
 	return resp.Session, err
 }
 
+// Session returns the stored session with the given ID.
+func (r *TestAgentRunner) Session(t *testing.T, sessionID string) session.Session {
+	t.Helper()
+	resp, err := r.sessionService.Get(t.Context(), &session.GetRequest{
+		AppName:   "test_app",
+		UserID:    "test_user",
+		SessionID: sessionID,
+	})
+	if err != nil {
+		t.Fatalf("failed to get session: %v", err)
+	}
+	return resp.Session
+}
+
 func (r *TestAgentRunner) SetInitSessionState(state map[string]any) {
 	r.initSessionState = state
 }
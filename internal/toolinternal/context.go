@@ -22,6 +22,7 @@ import (
 
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/auth"
 	contextinternal "google.golang.org/adk/internal/context"
 	"google.golang.org/adk/memory"
 	"google.golang.org/adk/session"
@@ -99,3 +100,14 @@ func (c *toolContext) AgentName() string {
 func (c *toolContext) SearchMemory(ctx context.Context, query string) (*memory.SearchResponse, error) {
 	return c.invocationContext.Memory().Search(ctx, query)
 }
+
+func (c *toolContext) RequestCredential(cfg *auth.Config) {
+	if c.eventActions.RequestedAuthConfigs == nil {
+		c.eventActions.RequestedAuthConfigs = make(map[string]*auth.Config)
+	}
+	c.eventActions.RequestedAuthConfigs[c.functionCallID] = cfg
+}
+
+func (c *toolContext) Credentials() auth.Credentials {
+	return &stateCredentials{state: c.State()}
+}
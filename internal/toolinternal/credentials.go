@@ -0,0 +1,71 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolinternal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"google.golang.org/adk/auth"
+	"google.golang.org/adk/session"
+)
+
+// stateCredentials reads the credentials stored in the session state.
+type stateCredentials struct {
+	state session.State
+}
+
+// credentialStateKey returns the state key of the credential of cfg. The
+// credentials are temporary state: they are visible to the invocation that
+// received them but are never persisted, so they do not leak through the
+// session state, the stored events or the exported sessions.
+func credentialStateKey(cfg *auth.Config) string {
+	return session.KeyPrefixTemp + cfg.Key()
+}
+
+// Get implements auth.Credentials.
+func (c *stateCredentials) Get(cfg *auth.Config) (*auth.Credential, error) {
+	key := credentialStateKey(cfg)
+	val, err := c.state.Get(key)
+	if errors.Is(err, session.ErrStateKeyNotExist) || val == nil {
+		return nil, auth.ErrCredentialNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cred, ok := val.(*auth.Credential); ok {
+		return cred, nil
+	}
+	// The credentials set by the application may be JSON objects.
+	data, err := json.Marshal(val)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode credential %q: %w", key, err)
+	}
+	var cred auth.Credential
+	if err := json.Unmarshal(data, &cred); err != nil {
+		return nil, fmt.Errorf("failed to decode credential %q: %w", key, err)
+	}
+	return &cred, nil
+}
+
+// StoreCredential stores the exchanged credential of cfg, as provided by
+// the client, in the temporary state of the invocation.
+func StoreCredential(state session.State, cfg *auth.Config) error {
+	if cfg.ExchangedCredential == nil {
+		return fmt.Errorf("no credential provided for %q", cfg.Key())
+	}
+	return state.Set(credentialStateKey(cfg), cfg.ExchangedCredential)
+}
@@ -31,3 +31,12 @@ type FunctionTool interface {
 type RequestProcessor interface {
 	ProcessRequest(ctx tool.Context, req *model.LLMRequest) error
 }
+
+// BuiltinTool is a tool run by the model itself, e.g. Google Search. Most
+// models don't accept built-in tools and function declarations in the same
+// request.
+type BuiltinTool interface {
+	tool.Tool
+	RequestProcessor
+	IsBuiltin() bool
+}
@@ -20,6 +20,7 @@ import (
 	"math"
 	"reflect"
 
+	"github.com/google/jsonschema-go/jsonschema"
 	"google.golang.org/genai"
 )
 
@@ -134,3 +135,19 @@ func ValidateOutputSchema(output string, schema *genai.Schema) (map[string]any,
 	}
 	return outputMap, nil
 }
+
+// ValidateOutputJSONSchema decodes an output JSON string and validates it
+// against a resolved JSON schema.
+func ValidateOutputJSONSchema(output string, schema *jsonschema.Resolved) (any, error) {
+	if schema == nil {
+		return nil, fmt.Errorf("schema cannot be nil")
+	}
+	var value any
+	if err := json.Unmarshal([]byte(output), &value); err != nil {
+		return nil, fmt.Errorf("failed to parse output JSON: %w", err)
+	}
+	if err := schema.Validate(value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,196 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/embedding"
+	"google.golang.org/adk/session"
+)
+
+// DefaultEmbeddingTopK is the number of memories returned by a search of the
+// embedding service when none is configured.
+const DefaultEmbeddingTopK = 10
+
+// EmbeddingServiceConfig defines the configuration of an in-memory service
+// searching memories by semantic similarity.
+type EmbeddingServiceConfig struct {
+	// Embedder computes the embeddings of the events and of the queries.
+	Embedder embedding.Embedder
+	// TopK is the maximum number of memories returned by a search.
+	// If zero, DefaultEmbeddingTopK is used.
+	TopK int
+	// MinScore is the minimum cosine similarity between a query and a
+	// memory for the memory to be returned.
+	MinScore float64
+}
+
+// InMemoryEmbeddingService returns a new in-memory implementation of the
+// memory service, returning the memories most similar to the query according
+// to their embeddings. Thread-safe.
+func InMemoryEmbeddingService(cfg EmbeddingServiceConfig) (Service, error) {
+	if cfg.Embedder == nil {
+		return nil, errors.New("embedder is required")
+	}
+	if cfg.TopK < 0 {
+		return nil, fmt.Errorf("invalid TopK %d", cfg.TopK)
+	}
+	if cfg.TopK == 0 {
+		cfg.TopK = DefaultEmbeddingTopK
+	}
+	return &embeddingService{
+		cfg:   cfg,
+		store: make(map[key]map[sessionID][]embeddedValue),
+	}, nil
+}
+
+type embeddedValue struct {
+	content   *genai.Content
+	author    string
+	timestamp time.Time
+	embedding []float32
+}
+
+type embeddingService struct {
+	cfg EmbeddingServiceConfig
+
+	mu    sync.RWMutex
+	store map[key]map[sessionID][]embeddedValue
+}
+
+func (s *embeddingService) AddSession(ctx context.Context, curSession session.Session) error {
+	var values []embeddedValue
+	var texts []string
+	for event := range curSession.Events().All() {
+		if event.LLMResponse.Content == nil {
+			continue
+		}
+		var sb strings.Builder
+		for _, part := range event.LLMResponse.Content.Parts {
+			if part.Text == "" || part.Thought {
+				continue
+			}
+			if sb.Len() > 0 {
+				sb.WriteString("\n")
+			}
+			sb.WriteString(part.Text)
+		}
+		if sb.Len() == 0 {
+			continue
+		}
+		values = append(values, embeddedValue{
+			content:   event.LLMResponse.Content,
+			author:    event.Author,
+			timestamp: event.Timestamp,
+		})
+		texts = append(texts, sb.String())
+	}
+
+	if len(texts) > 0 {
+		embeddings, err := s.cfg.Embedder.Embed(ctx, texts, embedding.TaskTypeRetrievalDocument)
+		if err != nil {
+			return fmt.Errorf("failed to embed session %q: %w", curSession.ID(), err)
+		}
+		if len(embeddings) != len(values) {
+			return fmt.Errorf("got %d embeddings for %d events", len(embeddings), len(values))
+		}
+		for i := range values {
+			values[i].embedding = embeddings[i]
+		}
+	}
+
+	k := key{
+		appName: curSession.AppName(),
+		userID:  curSession.UserID(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.store[k]
+	if !ok {
+		v = map[sessionID][]embeddedValue{}
+		s.store[k] = v
+	}
+	v[sessionID(curSession.ID())] = values
+	return nil
+}
+
+func (s *embeddingService) Search(ctx context.Context, req *SearchRequest) (*SearchResponse, error) {
+	k := key{
+		appName: req.AppName,
+		userID:  req.UserID,
+	}
+
+	s.mu.RLock()
+	var candidates []embeddedValue
+	for _, values := range s.store[k] {
+		candidates = append(candidates, values...)
+	}
+	s.mu.RUnlock()
+	if len(candidates) == 0 || req.Query == "" {
+		return &SearchResponse{}, nil
+	}
+
+	embeddings, err := s.cfg.Embedder.Embed(ctx, []string{req.Query}, embedding.TaskTypeRetrievalQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	if len(embeddings) != 1 {
+		return nil, fmt.Errorf("got %d embeddings for the query", len(embeddings))
+	}
+
+	type scored struct {
+		value embeddedValue
+		score float64
+	}
+	var matches []scored
+	for _, c := range candidates {
+		score, err := embedding.CosineSimilarity(embeddings[0], c.embedding)
+		if err != nil {
+			return nil, err
+		}
+		if score < s.cfg.MinScore {
+			continue
+		}
+		matches = append(matches, scored{value: c, score: score})
+	}
+	slices.SortStableFunc(matches, func(a, b scored) int {
+		return cmp.Compare(b.score, a.score)
+	})
+	if len(matches) > s.cfg.TopK {
+		matches = matches[:s.cfg.TopK]
+	}
+
+	res := &SearchResponse{}
+	for _, m := range matches {
+		res.Memories = append(res.Memories, Entry{
+			Content:   m.value.content,
+			Author:    m.value.author,
+			Timestamp: m.value.timestamp,
+		})
+	}
+	return res, nil
+}
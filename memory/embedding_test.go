@@ -0,0 +1,124 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/embedding"
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+// keywordEmbedder embeds texts as the number of occurrences of each of its
+// keywords.
+type keywordEmbedder struct {
+	keywords  []string
+	taskTypes []embedding.TaskType
+}
+
+func (e *keywordEmbedder) Embed(ctx context.Context, texts []string, taskType embedding.TaskType) ([][]float32, error) {
+	e.taskTypes = append(e.taskTypes, taskType)
+	res := make([][]float32, len(texts))
+	for i, text := range texts {
+		res[i] = make([]float32, len(e.keywords))
+		for j, k := range e.keywords {
+			res[i][j] = float32(strings.Count(strings.ToLower(text), k))
+		}
+	}
+	return res, nil
+}
+
+func TestInMemoryEmbeddingService(t *testing.T) {
+	embedder := &keywordEmbedder{keywords: []string{"cat", "dog", "fish"}}
+	s, err := memory.InMemoryEmbeddingService(memory.EmbeddingServiceConfig{
+		Embedder: embedder,
+		TopK:     2,
+		MinScore: 0.1,
+	})
+	if err != nil {
+		t.Fatalf("InMemoryEmbeddingService() failed: %v", err)
+	}
+	ctx := t.Context()
+	for _, sess := range []session.Session{
+		makeSession(t, "app", "user", "s1", []*session.Event{
+			{Author: "user", LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("My cat is called Tom", genai.RoleUser)}},
+			{Author: "bot", LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("Cats and dogs", genai.RoleModel)}},
+		}),
+		makeSession(t, "app", "user", "s2", []*session.Event{
+			{Author: "user", LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("I like fish", genai.RoleUser)}},
+			{Author: "bot"},
+		}),
+		makeSession(t, "app", "other", "s3", []*session.Event{
+			{Author: "user", LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("cat cat cat", genai.RoleUser)}},
+		}),
+	} {
+		if err := s.AddSession(ctx, sess); err != nil {
+			t.Fatalf("AddSession() failed: %v", err)
+		}
+	}
+
+	got, err := s.Search(ctx, &memory.SearchRequest{AppName: "app", UserID: "user", Query: "what is the name of my cat?"})
+	if err != nil {
+		t.Fatalf("Search() failed: %v", err)
+	}
+	want := &memory.SearchResponse{Memories: []memory.Entry{
+		{Author: "user", Content: genai.NewContentFromText("My cat is called Tom", genai.RoleUser)},
+		{Author: "bot", Content: genai.NewContentFromText("Cats and dogs", genai.RoleModel)},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Search() mismatch (-want +got):\n%s", diff)
+	}
+
+	wantTaskTypes := []embedding.TaskType{
+		embedding.TaskTypeRetrievalDocument,
+		embedding.TaskTypeRetrievalDocument,
+		embedding.TaskTypeRetrievalDocument,
+		embedding.TaskTypeRetrievalQuery,
+	}
+	if diff := cmp.Diff(wantTaskTypes, embedder.taskTypes); diff != "" {
+		t.Errorf("task types mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err = s.Search(ctx, &memory.SearchRequest{AppName: "app", UserID: "user", Query: "horses"})
+	if err != nil {
+		t.Fatalf("Search() failed: %v", err)
+	}
+	if diff := cmp.Diff(&memory.SearchResponse{}, got); diff != "" {
+		t.Errorf("Search() without match mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestInMemoryEmbeddingService_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  memory.EmbeddingServiceConfig
+	}{
+		{name: "no embedder", cfg: memory.EmbeddingServiceConfig{}},
+		{name: "negative topK", cfg: memory.EmbeddingServiceConfig{Embedder: &keywordEmbedder{}, TopK: -1}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := memory.InMemoryEmbeddingService(tc.cfg); err == nil {
+				t.Errorf("InMemoryEmbeddingService() succeeded, want error")
+			}
+		})
+	}
+}
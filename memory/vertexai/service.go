@@ -0,0 +1,175 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vertexai provides a [memory.Service] backed by the Memory Bank of
+// Vertex AI Agent Engine.
+//
+// Memory Bank extracts facts from the sessions added to it and consolidates
+// them with the existing memories of the user. The memories are scoped by app
+// name and user ID, as the other ADK implementations do, so they are shared
+// with the agents of any language using the same reasoning engine.
+package vertexai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/api/aiplatform/v1beta1"
+	"google.golang.org/api/option"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/session"
+)
+
+// DefaultTopK is the number of memories returned by a search when none is
+// configured.
+const DefaultTopK = 3
+
+// Config defines the configuration of a Vertex AI Memory Bank service.
+type Config struct {
+	// ProjectID is the Google Cloud project of the reasoning engine.
+	ProjectID string
+	// Location is the region of the reasoning engine, e.g. "us-central1".
+	Location string
+	// AgentEngineID is the ID, or the full resource name, of the reasoning
+	// engine storing the memories.
+	AgentEngineID string
+	// TopK is the maximum number of memories returned by a search.
+	// If zero, DefaultTopK is used.
+	TopK int
+	// ClientOptions are passed to the underlying Vertex AI client.
+	ClientOptions []option.ClientOption
+}
+
+type vertexAIService struct {
+	// engine is the resource name of the reasoning engine.
+	engine   string
+	topK     int
+	memories *aiplatform.ProjectsLocationsReasoningEnginesMemoriesService
+}
+
+// NewMemoryService creates a [memory.Service] storing memories in the Memory
+// Bank of a Vertex AI Agent Engine.
+func NewMemoryService(ctx context.Context, cfg Config) (memory.Service, error) {
+	if cfg.AgentEngineID == "" {
+		return nil, errors.New("agent engine ID is required")
+	}
+	if cfg.TopK < 0 {
+		return nil, fmt.Errorf("invalid TopK %d", cfg.TopK)
+	}
+	engine := cfg.AgentEngineID
+	if !strings.HasPrefix(engine, "projects/") {
+		if cfg.ProjectID == "" || cfg.Location == "" {
+			return nil, errors.New("project ID and location are required")
+		}
+		engine = fmt.Sprintf("projects/%s/locations/%s/reasoningEngines/%s", cfg.ProjectID, cfg.Location, engine)
+	}
+	location := cfg.Location
+	if location == "" {
+		// The resource name is "projects/{project}/locations/{location}/...".
+		if parts := strings.Split(engine, "/"); len(parts) > 3 {
+			location = parts[3]
+		}
+	}
+	topK := cfg.TopK
+	if topK == 0 {
+		topK = DefaultTopK
+	}
+	// The reasoning engines are served by the regional endpoints.
+	opts := append([]option.ClientOption{
+		option.WithEndpoint(fmt.Sprintf("https://%s-aiplatform.googleapis.com/", location)),
+	}, cfg.ClientOptions...)
+	svc, err := aiplatform.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vertex ai client: %w", err)
+	}
+	return &vertexAIService{
+		engine:   engine,
+		topK:     topK,
+		memories: aiplatform.NewProjectsLocationsReasoningEnginesMemoriesService(svc),
+	}, nil
+}
+
+func scope(appName, userID string) map[string]string {
+	return map[string]string{"app_name": appName, "user_id": userID}
+}
+
+// AddSession sends the text events of the session to Memory Bank, which
+// generates the memories asynchronously. Implements memory.Service.
+func (s *vertexAIService) AddSession(ctx context.Context, curSession session.Session) error {
+	var events []*aiplatform.GoogleCloudAiplatformV1beta1GenerateMemoriesRequestDirectContentsSourceEvent
+	for event := range curSession.Events().All() {
+		if event.Content == nil {
+			continue
+		}
+		content := &aiplatform.GoogleCloudAiplatformV1beta1Content{Role: event.Content.Role}
+		for _, part := range event.Content.Parts {
+			if part != nil && part.Text != "" && !part.Thought {
+				content.Parts = append(content.Parts, &aiplatform.GoogleCloudAiplatformV1beta1Part{Text: part.Text})
+			}
+		}
+		if len(content.Parts) > 0 {
+			events = append(events, &aiplatform.GoogleCloudAiplatformV1beta1GenerateMemoriesRequestDirectContentsSourceEvent{Content: content})
+		}
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	req := &aiplatform.GoogleCloudAiplatformV1beta1GenerateMemoriesRequest{
+		DirectContentsSource: &aiplatform.GoogleCloudAiplatformV1beta1GenerateMemoriesRequestDirectContentsSource{Events: events},
+		Scope:                scope(curSession.AppName(), curSession.UserID()),
+	}
+	if _, err := s.memories.Generate(s.engine, req).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("failed to generate memories: %w", err)
+	}
+	return nil
+}
+
+// Search returns the memories of the user most similar to the query.
+// Implements memory.Service.
+func (s *vertexAIService) Search(ctx context.Context, req *memory.SearchRequest) (*memory.SearchResponse, error) {
+	resp, err := s.memories.Retrieve(s.engine, &aiplatform.GoogleCloudAiplatformV1beta1RetrieveMemoriesRequest{
+		Scope: scope(req.AppName, req.UserID),
+		SimilaritySearchParams: &aiplatform.GoogleCloudAiplatformV1beta1RetrieveMemoriesRequestSimilaritySearchParams{
+			SearchQuery: req.Query,
+			TopK:        int64(s.topK),
+		},
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve memories: %w", err)
+	}
+	res := &memory.SearchResponse{Memories: []memory.Entry{}}
+	for _, retrieved := range resp.RetrievedMemories {
+		if retrieved == nil || retrieved.Memory == nil {
+			continue
+		}
+		m := retrieved.Memory
+		updatedAt, err := time.Parse(time.RFC3339Nano, m.UpdateTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid update time of memory %q: %w", m.Name, err)
+		}
+		res.Memories = append(res.Memories, memory.Entry{
+			Content:   genai.NewContentFromText(m.Fact, genai.RoleUser),
+			Author:    genai.RoleUser,
+			Timestamp: updatedAt,
+		})
+	}
+	return res, nil
+}
+
+var _ memory.Service = (*vertexAIService)(nil)
@@ -0,0 +1,156 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vertexai_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/option"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/memory"
+	"google.golang.org/adk/memory/vertexai"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+const testEngine = "projects/project/locations/us-central1/reasoningEngines/engine"
+
+// request is a request received by the fake Memory Bank.
+type request struct {
+	path string
+	body map[string]any
+}
+
+func newTestService(t *testing.T, response string) (memory.Service, *[]request) {
+	t.Helper()
+	var requests []request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var body map[string]any
+		if err := json.Unmarshal(data, &body); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		requests = append(requests, request{path: r.URL.Path, body: body})
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, response)
+	}))
+	t.Cleanup(srv.Close)
+	s, err := vertexai.NewMemoryService(t.Context(), vertexai.Config{
+		ProjectID:     "project",
+		Location:      "us-central1",
+		AgentEngineID: "engine",
+		ClientOptions: []option.ClientOption{option.WithEndpoint(srv.URL), option.WithoutAuthentication()},
+	})
+	if err != nil {
+		t.Fatalf("NewMemoryService() failed: %v", err)
+	}
+	return s, &requests
+}
+
+func TestNewMemoryService_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  vertexai.Config
+	}{
+		{name: "no engine", cfg: vertexai.Config{ProjectID: "project", Location: "us-central1"}},
+		{name: "no project", cfg: vertexai.Config{Location: "us-central1", AgentEngineID: "engine"}},
+		{name: "negative topK", cfg: vertexai.Config{AgentEngineID: testEngine, TopK: -1}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := vertexai.NewMemoryService(t.Context(), tc.cfg); err == nil {
+				t.Errorf("NewMemoryService() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestAddSession(t *testing.T) {
+	ctx := t.Context()
+	s, requests := newTestService(t, `{"name": "`+testEngine+`/operations/op"}`)
+
+	sessions := session.InMemoryService()
+	created, err := sessions.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	for _, content := range []*genai.Content{
+		genai.NewContentFromText("I live in Paris.", genai.RoleUser),
+		genai.NewContentFromFunctionCall("lookup", map[string]any{}, genai.RoleModel),
+		genai.NewContentFromText("Noted.", genai.RoleModel),
+	} {
+		event := session.NewEvent("inv")
+		event.LLMResponse = model.LLMResponse{Content: content}
+		if err := sessions.AppendEvent(ctx, created.Session, event); err != nil {
+			t.Fatalf("AppendEvent() failed: %v", err)
+		}
+	}
+
+	if err := s.AddSession(ctx, created.Session); err != nil {
+		t.Fatalf("AddSession() failed: %v", err)
+	}
+	textEvent := func(text, role string) any {
+		return map[string]any{"content": map[string]any{"role": role, "parts": []any{map[string]any{"text": text}}}}
+	}
+	want := []request{{
+		path: "/v1beta1/" + testEngine + "/memories:generate",
+		body: map[string]any{
+			"directContentsSource": map[string]any{"events": []any{
+				textEvent("I live in Paris.", genai.RoleUser),
+				textEvent("Noted.", genai.RoleModel),
+			}},
+			"scope": map[string]any{"app_name": "app", "user_id": "user"},
+		},
+	}}
+	if diff := cmp.Diff(want, *requests, cmp.AllowUnexported(request{})); diff != "" {
+		t.Errorf("requests mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	s, requests := newTestService(t, `{"retrievedMemories": [
+		{"memory": {"name": "m1", "fact": "The user lives in Paris.", "updateTime": "2025-06-01T10:00:00Z"}, "distance": 0.1},
+		{}
+	]}`)
+
+	got, err := s.Search(t.Context(), &memory.SearchRequest{Query: "where do I live", AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatalf("Search() failed: %v", err)
+	}
+	want := &memory.SearchResponse{Memories: []memory.Entry{{
+		Content:   genai.NewContentFromText("The user lives in Paris.", genai.RoleUser),
+		Author:    genai.RoleUser,
+		Timestamp: time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC),
+	}}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Search() mismatch (-want +got):\n%s", diff)
+	}
+	wantRequests := []request{{
+		path: "/v1beta1/" + testEngine + "/memories:retrieve",
+		body: map[string]any{
+			"scope":                  map[string]any{"app_name": "app", "user_id": "user"},
+			"similaritySearchParams": map[string]any{"searchQuery": "where do I live", "topK": float64(vertexai.DefaultTopK)},
+		},
+	}}
+	if diff := cmp.Diff(wantRequests, *requests, cmp.AllowUnexported(request{})); diff != "" {
+		t.Errorf("requests mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import "time"
+
+const (
+	// DefaultCacheTTL is the lifetime of the cached contents when no TTL is
+	// configured.
+	DefaultCacheTTL = 30 * time.Minute
+	// DefaultCacheMinTokens is the estimated size of the static part of the
+	// request under which no cache is created when no minimum is configured.
+	DefaultCacheMinTokens = 1024
+)
+
+// CacheConfig configures the caching of the static part of the requests, the
+// system instruction and the tool declarations, by models that support it.
+//
+// The cache is keyed by the model and the cached content, so any change of
+// the instructions or the tools, including dynamic instructions, results in
+// a new cache. Models that don't support caching ignore the config.
+type CacheConfig struct {
+	// TTL is the lifetime of the cached contents.
+	// If zero, DefaultCacheTTL is used.
+	TTL time.Duration
+	// MinTokens is the estimated number of tokens of the static part of the
+	// request below which caching is skipped, as small contents can't be
+	// cached. If zero, DefaultCacheMinTokens is used.
+	MinTokens int
+}
+
+// CacheMetadata describes the cached contents used for a request.
+type CacheMetadata struct {
+	// Name is the resource name of the cached contents.
+	Name string `json:"name"`
+	// Fingerprint identifies the cached part of the request.
+	Fingerprint string `json:"fingerprint"`
+	// ExpireTime is the time the cached contents expire at.
+	ExpireTime time.Time `json:"expireTime"`
+}
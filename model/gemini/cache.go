@@ -0,0 +1,116 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gemini
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/logging"
+	"google.golang.org/adk/model"
+)
+
+// cacheRefreshMargin is how long before its expiration a cache stops being
+// reused, so that it doesn't expire while a request is in flight.
+const cacheRefreshMargin = time.Minute
+
+// cacheRequest moves the system instruction and the tools of req into cached
+// contents if req asks for caching. It returns the request to send and the
+// metadata of the cache used, or nil if the request isn't cached.
+//
+// A failure to create the cache is not fatal, the request is sent uncached.
+func (m *geminiModel) cacheRequest(ctx context.Context, req *model.LLMRequest) (*model.LLMRequest, *model.CacheMetadata) {
+	if req.CacheConfig == nil || req.Config == nil {
+		return req, nil
+	}
+	cfg := req.Config
+	if cfg.CachedContent != "" || (cfg.SystemInstruction == nil && len(cfg.Tools) == 0) {
+		return req, nil
+	}
+
+	static, err := json.Marshal(struct {
+		Model             string            `json:"model"`
+		SystemInstruction *genai.Content    `json:"systemInstruction,omitempty"`
+		Tools             []*genai.Tool     `json:"tools,omitempty"`
+		ToolConfig        *genai.ToolConfig `json:"toolConfig,omitempty"`
+	}{m.name, cfg.SystemInstruction, cfg.Tools, cfg.ToolConfig})
+	if err != nil {
+		return req, nil
+	}
+	minTokens := req.CacheConfig.MinTokens
+	if minTokens == 0 {
+		minTokens = model.DefaultCacheMinTokens
+	}
+	// Roughly 4 characters per token.
+	if len(static)/4 < minTokens {
+		return req, nil
+	}
+	sum := sha256.Sum256(static)
+	fingerprint := hex.EncodeToString(sum[:])
+
+	meta := req.CacheMetadata
+	if meta == nil || meta.Name == "" || meta.Fingerprint != fingerprint || time.Until(meta.ExpireTime) < cacheRefreshMargin {
+		meta, err = m.createCache(ctx, cfg, fingerprint, req.CacheConfig.TTL)
+		if err != nil {
+			logging.FromContext(ctx).Warn("failed to create cached contents, sending the request uncached", "model", m.name, "error", err)
+			return req, nil
+		}
+	}
+
+	cached := *cfg
+	cached.CachedContent = meta.Name
+	cached.SystemInstruction = nil
+	cached.Tools = nil
+	cached.ToolConfig = nil
+	r := *req
+	r.Config = &cached
+	return &r, meta
+}
+
+// createCache creates cached contents holding the static part of cfg.
+func (m *geminiModel) createCache(ctx context.Context, cfg *genai.GenerateContentConfig, fingerprint string, ttl time.Duration) (*model.CacheMetadata, error) {
+	if ttl <= 0 {
+		ttl = model.DefaultCacheTTL
+	}
+	cc, err := m.client.Caches.Create(ctx, m.name, &genai.CreateCachedContentConfig{
+		HTTPOptions:       cfg.HTTPOptions,
+		TTL:               ttl,
+		DisplayName:       "adk-" + fingerprint[:16],
+		SystemInstruction: cfg.SystemInstruction,
+		Tools:             cfg.Tools,
+		ToolConfig:        cfg.ToolConfig,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cc.Name == "" {
+		return nil, fmt.Errorf("cached contents have no name")
+	}
+	expireTime := cc.ExpireTime
+	if expireTime.IsZero() {
+		expireTime = time.Now().Add(ttl)
+	}
+	return &model.CacheMetadata{
+		Name:        cc.Name,
+		Fingerprint: fingerprint,
+		ExpireTime:  expireTime,
+	}, nil
+}
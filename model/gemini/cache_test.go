@@ -0,0 +1,145 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gemini
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+// fakeCacheServer serves the cachedContents and generateContent endpoints of
+// the Gemini API and records the requests.
+type fakeCacheServer struct {
+	created  []map[string]any
+	requests []map[string]any
+}
+
+func (s *fakeCacheServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	var req map[string]any
+	_ = json.Unmarshal(body, &req)
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/cachedContents"):
+		s.created = append(s.created, req)
+		fmt.Fprintf(w, `{"name": "cachedContents/c%d", "expireTime": %q}`, len(s.created), time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+	case strings.HasSuffix(r.URL.Path, ":generateContent"):
+		s.requests = append(s.requests, req)
+		io.WriteString(w, `{"candidates": [{"content": {"role": "model", "parts": [{"text": "ok"}]}}]}`)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestModel_ContextCache(t *testing.T) {
+	srv := &fakeCacheServer{}
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	m, err := NewModel(t.Context(), "gemini-2.5-flash", &genai.ClientConfig{
+		APIKey:      "fakekey",
+		Backend:     genai.BackendGeminiAPI,
+		HTTPOptions: genai.HTTPOptions{BaseURL: ts.URL},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	longInstruction := strings.Repeat("Follow the rules. ", 300)
+	generate := func(instruction string, meta *model.CacheMetadata) *model.LLMResponse {
+		t.Helper()
+		req := &model.LLMRequest{
+			Contents: genai.Text("hi"),
+			Config: &genai.GenerateContentConfig{
+				SystemInstruction: genai.NewContentFromText(instruction, genai.RoleUser),
+			},
+			CacheConfig:   &model.CacheConfig{},
+			CacheMetadata: meta,
+		}
+		var got *model.LLMResponse
+		for resp, err := range m.GenerateContent(t.Context(), req, false) {
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+			got = resp
+		}
+		return got
+	}
+
+	resp := generate(longInstruction, nil)
+	if resp.CacheMetadata == nil || resp.CacheMetadata.Name != "cachedContents/c1" {
+		t.Fatalf("CacheMetadata = %+v, want cachedContents/c1", resp.CacheMetadata)
+	}
+	if len(srv.created) != 1 {
+		t.Fatalf("created %d caches, want 1", len(srv.created))
+	}
+	if _, ok := srv.created[0]["systemInstruction"]; !ok {
+		t.Errorf("cache was created without the system instruction: %v", srv.created[0])
+	}
+	lastReq := srv.requests[len(srv.requests)-1]
+	if got := lastReq["cachedContent"]; got != "cachedContents/c1" {
+		t.Errorf("request cachedContent = %v, want cachedContents/c1", got)
+	}
+	if _, ok := lastReq["systemInstruction"]; ok {
+		t.Errorf("cached request has a system instruction")
+	}
+
+	// The cache is reused for the same instruction.
+	resp = generate(longInstruction, resp.CacheMetadata)
+	if diff := cmp.Diff("cachedContents/c1", resp.CacheMetadata.Name); diff != "" {
+		t.Errorf("reused cache mismatch (-want +got):\n%s", diff)
+	}
+	if len(srv.created) != 1 {
+		t.Errorf("created %d caches, want 1", len(srv.created))
+	}
+
+	// A new cache is created when the instruction changes.
+	resp = generate(longInstruction+"Be brief.", resp.CacheMetadata)
+	if diff := cmp.Diff("cachedContents/c2", resp.CacheMetadata.Name); diff != "" {
+		t.Errorf("new cache mismatch (-want +got):\n%s", diff)
+	}
+
+	// An expiring cache is recreated.
+	expiring := *resp.CacheMetadata
+	expiring.ExpireTime = time.Now().Add(time.Second)
+	resp = generate(longInstruction+"Be brief.", &expiring)
+	if diff := cmp.Diff("cachedContents/c3", resp.CacheMetadata.Name); diff != "" {
+		t.Errorf("recreated cache mismatch (-want +got):\n%s", diff)
+	}
+
+	// Small instructions are not cached.
+	resp = generate("Be brief.", nil)
+	if resp.CacheMetadata != nil {
+		t.Errorf("CacheMetadata = %+v, want nil", resp.CacheMetadata)
+	}
+	lastReq = srv.requests[len(srv.requests)-1]
+	if _, ok := lastReq["cachedContent"]; ok {
+		t.Errorf("small request uses a cache")
+	}
+	if len(srv.created) != 3 {
+		t.Errorf("created %d caches, want 3", len(srv.created))
+	}
+}
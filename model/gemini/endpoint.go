@@ -0,0 +1,128 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gemini
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"cloud.google.com/go/auth"
+	"cloud.google.com/go/auth/credentials"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// EndpointConfig identifies a Vertex AI endpoint, e.g. one serving a tuned
+// Gemini model or a model deployed from Model Garden. The model deployed to
+// the endpoint must support the generateContent method.
+type EndpointConfig struct {
+	// Project is the ID of the project of the endpoint.
+	// Optional if EndpointID is a full resource name.
+	Project string
+	// Location is the region of the endpoint, e.g. "us-central1". The
+	// requests are sent to the regional Vertex AI service endpoint.
+	// Optional if EndpointID is a full resource name.
+	Location string
+	// EndpointID is the ID of the endpoint, or its full resource name,
+	// "projects/{project}/locations/{location}/endpoints/{endpoint}".
+	EndpointID string
+	// QuotaProject is the project billed for the requests, if it is not the
+	// quota project of the credentials. Optional.
+	QuotaProject string
+	// Credentials used to call the endpoint. If nil, Application Default
+	// Credentials are used. Optional.
+	Credentials *auth.Credentials
+	// HTTPClient is used to call the endpoint. It must handle the
+	// authentication, Credentials is ignored if it is set. Optional.
+	HTTPClient *http.Client
+}
+
+// EndpointName returns the resource name of a Vertex AI endpoint.
+func EndpointName(project, location, endpointID string) string {
+	return fmt.Sprintf("projects/%s/locations/%s/endpoints/%s", project, location, endpointID)
+}
+
+// NewEndpointModel returns [model.LLM], backed by the model deployed to a
+// Vertex AI endpoint.
+func NewEndpointModel(ctx context.Context, cfg EndpointConfig) (model.LLM, error) {
+	if cfg.EndpointID == "" {
+		return nil, errors.New("endpoint ID is required")
+	}
+	name := cfg.EndpointID
+	if strings.HasPrefix(name, "projects/") {
+		parts := strings.Split(name, "/")
+		if len(parts) != 6 || parts[2] != "locations" || parts[4] != "endpoints" {
+			return nil, fmt.Errorf("invalid endpoint resource name %q", name)
+		}
+		if cfg.Project == "" {
+			cfg.Project = parts[1]
+		}
+		if cfg.Location == "" {
+			cfg.Location = parts[3]
+		}
+	} else {
+		if cfg.Project == "" || cfg.Location == "" {
+			return nil, errors.New("project and location are required")
+		}
+		name = EndpointName(cfg.Project, cfg.Location, cfg.EndpointID)
+	}
+
+	clientCfg := &genai.ClientConfig{
+		Backend:     genai.BackendVertexAI,
+		Project:     cfg.Project,
+		Location:    cfg.Location,
+		Credentials: cfg.Credentials,
+		HTTPClient:  cfg.HTTPClient,
+	}
+	if cfg.QuotaProject != "" {
+		if cfg.HTTPClient != nil {
+			clientCfg.HTTPOptions.Headers = http.Header{"X-Goog-User-Project": []string{cfg.QuotaProject}}
+		} else {
+			creds, err := withQuotaProject(cfg.Credentials, cfg.QuotaProject)
+			if err != nil {
+				return nil, err
+			}
+			clientCfg.Credentials = creds
+		}
+	}
+	return NewModel(ctx, name, clientCfg)
+}
+
+// withQuotaProject returns creds, or the default credentials if creds is nil,
+// billing the requests to quotaProject.
+func withQuotaProject(creds *auth.Credentials, quotaProject string) (*auth.Credentials, error) {
+	if creds == nil {
+		var err error
+		creds, err = credentials.DetectDefault(&credentials.DetectOptions{Scopes: []string{cloudPlatformScope}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to find default credentials: %w", err)
+		}
+	}
+	return auth.NewCredentials(&auth.CredentialsOptions{
+		TokenProvider:     creds.TokenProvider,
+		JSON:              creds.JSON(),
+		ProjectIDProvider: auth.CredentialsPropertyFunc(creds.ProjectID),
+		QuotaProjectIDProvider: auth.CredentialsPropertyFunc(func(context.Context) (string, error) {
+			return quotaProject, nil
+		}),
+		UniverseDomainProvider: auth.CredentialsPropertyFunc(creds.UniverseDomain),
+	}), nil
+}
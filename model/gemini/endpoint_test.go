@@ -0,0 +1,128 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gemini
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"cloud.google.com/go/auth"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestNewEndpointModel(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		cfg              EndpointConfig
+		wantURL          string
+		wantQuotaProject string
+	}{
+		{
+			name:    "endpoint ID",
+			cfg:     EndpointConfig{Project: "p", Location: "us-central1", EndpointID: "123"},
+			wantURL: "https://us-central1-aiplatform.googleapis.com/v1beta1/projects/p/locations/us-central1/endpoints/123:generateContent",
+		},
+		{
+			name:             "resource name and quota project",
+			cfg:              EndpointConfig{EndpointID: "projects/p/locations/europe-west4/endpoints/456", QuotaProject: "billing"},
+			wantURL:          "https://europe-west4-aiplatform.googleapis.com/v1beta1/projects/p/locations/europe-west4/endpoints/456:generateContent",
+			wantQuotaProject: "billing",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotURL, gotQuotaProject string
+			tc.cfg.HTTPClient = &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				gotURL = req.URL.String()
+				gotQuotaProject = req.Header.Get("X-Goog-User-Project")
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"application/json"}},
+					Body:       io.NopCloser(strings.NewReader(`{"candidates": [{"content": {"role": "model", "parts": [{"text": "pong"}]}}]}`)),
+				}, nil
+			})}
+			m, err := NewEndpointModel(t.Context(), tc.cfg)
+			if err != nil {
+				t.Fatalf("NewEndpointModel() error = %v", err)
+			}
+			got, err := readResponse(m.GenerateContent(t.Context(), &model.LLMRequest{Contents: genai.Text("ping")}, false))
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+			if got.FinalText != "pong" {
+				t.Errorf("GenerateContent() = %q, want %q", got.FinalText, "pong")
+			}
+			if gotURL != tc.wantURL {
+				t.Errorf("request URL = %q, want %q", gotURL, tc.wantURL)
+			}
+			if gotQuotaProject != tc.wantQuotaProject {
+				t.Errorf("quota project header = %q, want %q", gotQuotaProject, tc.wantQuotaProject)
+			}
+		})
+	}
+}
+
+func TestNewEndpointModel_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  EndpointConfig
+	}{
+		{name: "no endpoint", cfg: EndpointConfig{Project: "p", Location: "us-central1"}},
+		{name: "no location", cfg: EndpointConfig{Project: "p", EndpointID: "123"}},
+		{name: "invalid resource name", cfg: EndpointConfig{EndpointID: "projects/p/endpoints/123"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewEndpointModel(t.Context(), tc.cfg); err == nil {
+				t.Error("NewEndpointModel() succeeded, want error")
+			}
+		})
+	}
+}
+
+type staticTokenProvider struct{}
+
+func (staticTokenProvider) Token(context.Context) (*auth.Token, error) {
+	return &auth.Token{Value: "token"}, nil
+}
+
+func TestWithQuotaProject(t *testing.T) {
+	ctx := t.Context()
+	creds := auth.NewCredentials(&auth.CredentialsOptions{
+		TokenProvider: staticTokenProvider{},
+		QuotaProjectIDProvider: auth.CredentialsPropertyFunc(func(context.Context) (string, error) {
+			return "default", nil
+		}),
+	})
+	got, err := withQuotaProject(creds, "billing")
+	if err != nil {
+		t.Fatalf("withQuotaProject() error = %v", err)
+	}
+	if qp, err := got.QuotaProjectID(ctx); err != nil || qp != "billing" {
+		t.Errorf("QuotaProjectID() = (%q, %v), want %q", qp, err, "billing")
+	}
+	if tok, err := got.Token(ctx); err != nil || tok.Value != "token" {
+		t.Errorf("Token() = (%v, %v), want the token of the credentials", tok, err)
+	}
+}
@@ -62,6 +62,17 @@ func NewModel(ctx context.Context, modelName string, cfg *genai.ClientConfig) (m
 	}, nil
 }
 
+// NewModelWithRetry is like [NewModel], but the calls of the model that fail
+// because of rate limits or unavailability, with a 429 or 503 status, are
+// retried as configured by retry. See [model.WithRetry].
+func NewModelWithRetry(ctx context.Context, modelName string, cfg *genai.ClientConfig, retry model.RetryConfig) (model.LLM, error) {
+	m, err := NewModel(ctx, modelName, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return model.WithRetry(m, retry), nil
+}
+
 func (m *geminiModel) Name() string {
 	return m.name
 }
@@ -80,13 +91,33 @@ func (m *geminiModel) GenerateContent(ctx context.Context, req *model.LLMRequest
 	}
 	m.addHeaders(req.Config.HTTPOptions.Headers)
 
+	req, cache := m.cacheRequest(ctx, req)
+
 	if stream {
-		return m.generateStream(ctx, req)
+		return withCacheMetadata(m.generateStream(ctx, req), cache)
 	}
 
-	return func(yield func(*model.LLMResponse, error) bool) {
+	return withCacheMetadata(func(yield func(*model.LLMResponse, error) bool) {
 		resp, err := m.generate(ctx, req)
 		yield(resp, err)
+	}, cache)
+}
+
+// withCacheMetadata sets the metadata of the cache used for the request on
+// the responses.
+func withCacheMetadata(seq iter.Seq2[*model.LLMResponse, error], cache *model.CacheMetadata) iter.Seq2[*model.LLMResponse, error] {
+	if cache == nil {
+		return seq
+	}
+	return func(yield func(*model.LLMResponse, error) bool) {
+		for resp, err := range seq {
+			if resp != nil {
+				resp.CacheMetadata = cache
+			}
+			if !yield(resp, err) {
+				return
+			}
+		}
 	}
 }
 
@@ -16,11 +16,14 @@ package gemini
 
 import (
 	"fmt"
+	"io"
 	"iter"
 	"net/http"
+	"net/http/httptest"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -188,6 +191,41 @@ func TestModel_TrackingHeaders(t *testing.T) {
 	})
 }
 
+func TestNewModelWithRetry(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			io.WriteString(w, `{"error": {"code": 429, "message": "quota exceeded", "status": "RESOURCE_EXHAUSTED",
+				"details": [{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "0.01s"}]}}`)
+			return
+		}
+		io.WriteString(w, `{"candidates": [{"content": {"role": "model", "parts": [{"text": "pong"}]}}]}`)
+	}))
+	defer srv.Close()
+
+	m, err := NewModelWithRetry(t.Context(), "gemini-2.0-flash", &genai.ClientConfig{
+		APIKey:      "fakekey",
+		Backend:     genai.BackendGeminiAPI,
+		HTTPOptions: genai.HTTPOptions{BaseURL: srv.URL},
+	}, model.RetryConfig{InitialDelay: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := readResponse(m.GenerateContent(t.Context(), &model.LLMRequest{Contents: genai.Text("ping")}, false))
+	if err != nil {
+		t.Fatalf("GenerateContent() error = %v", err)
+	}
+	if got.FinalText != "pong" {
+		t.Errorf("GenerateContent() = %q, want %q", got.FinalText, "pong")
+	}
+	if calls != 2 {
+		t.Errorf("server called %d times, want 2", calls)
+	}
+}
+
 // newGeminiTestClientConfig returns the genai.ClientConfig configured for record and replay.
 func newGeminiTestClientConfig(t *testing.T, rrfile string) *genai.ClientConfig {
 	t.Helper()
@@ -260,3 +298,35 @@ func (h *headerInterceptor) RoundTrip(req *http.Request) (*http.Response, error)
 	}
 	return h.base.RoundTrip(req)
 }
+
+func TestLiveConnectConfig(t *testing.T) {
+	speech := &genai.SpeechConfig{LanguageCode: "en-US"}
+	req := &model.LLMRequest{
+		Config: &genai.GenerateContentConfig{
+			Temperature:        genai.Ptr[float32](0.5),
+			SystemInstruction:  genai.NewContentFromText("be brief", genai.RoleUser),
+			ResponseModalities: []string{"TEXT"},
+			CandidateCount:     2,
+		},
+		LiveConnectConfig: &genai.LiveConnectConfig{
+			ResponseModalities:       []genai.Modality{genai.ModalityAudio},
+			SpeechConfig:             speech,
+			InputAudioTranscription:  &genai.AudioTranscriptionConfig{},
+			OutputAudioTranscription: &genai.AudioTranscriptionConfig{},
+		},
+	}
+
+	got := liveConnectConfig(req)
+
+	want := &genai.LiveConnectConfig{
+		Temperature:              genai.Ptr[float32](0.5),
+		SystemInstruction:        genai.NewContentFromText("be brief", genai.RoleUser),
+		ResponseModalities:       []genai.Modality{genai.ModalityAudio},
+		SpeechConfig:             speech,
+		InputAudioTranscription:  &genai.AudioTranscriptionConfig{},
+		OutputAudioTranscription: &genai.AudioTranscriptionConfig{},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("liveConnectConfig() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gemini
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"strings"
+	"sync"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+// Connect opens a connection to the model using the Gemini Live API.
+//
+// The generation config, system instruction and tools are taken from
+// req.Config, the speech and transcription settings from
+// req.LiveConnectConfig. req.Contents are sent as the conversation history;
+// the model starts responding right away if the last content is from the
+// user.
+func (m *geminiModel) Connect(ctx context.Context, req *model.LLMRequest) (model.LiveConnection, error) {
+	cfg := liveConnectConfig(req)
+	if cfg.HTTPOptions == nil {
+		cfg.HTTPOptions = &genai.HTTPOptions{}
+	}
+	if cfg.HTTPOptions.Headers == nil {
+		cfg.HTTPOptions.Headers = make(http.Header)
+	}
+	m.addHeaders(cfg.HTTPOptions.Headers)
+
+	session, err := m.client.Live.Connect(ctx, m.name, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to model: %w", err)
+	}
+	conn := &liveConnection{session: session}
+	if len(req.Contents) > 0 {
+		last := req.Contents[len(req.Contents)-1]
+		turnComplete := last != nil && last.Role == genai.RoleUser
+		if err := session.SendClientContent(genai.LiveClientContentInput{
+			Turns:        req.Contents,
+			TurnComplete: &turnComplete,
+		}); err != nil {
+			session.Close()
+			return nil, fmt.Errorf("failed to send history: %w", err)
+		}
+	}
+	return conn, nil
+}
+
+// liveConnectConfig returns the config of a live connection for req. The
+// fields of the generation config that are not supported by the Live API
+// are dropped.
+func liveConnectConfig(req *model.LLMRequest) *genai.LiveConnectConfig {
+	liveCfg := generationLiveConfig(req.Config)
+	if lc := req.LiveConnectConfig; lc != nil {
+		if len(lc.ResponseModalities) > 0 {
+			liveCfg.ResponseModalities = lc.ResponseModalities
+		}
+		if lc.SpeechConfig != nil {
+			liveCfg.SpeechConfig = lc.SpeechConfig
+		}
+		liveCfg.InputAudioTranscription = lc.InputAudioTranscription
+		liveCfg.OutputAudioTranscription = lc.OutputAudioTranscription
+	}
+	return liveCfg
+}
+
+func generationLiveConfig(cfg *genai.GenerateContentConfig) *genai.LiveConnectConfig {
+	if cfg == nil {
+		return &genai.LiveConnectConfig{}
+	}
+	liveCfg := &genai.LiveConnectConfig{
+		ResponseModalities: make([]genai.Modality, 0, len(cfg.ResponseModalities)),
+		Temperature:        cfg.Temperature,
+		TopP:               cfg.TopP,
+		TopK:               cfg.TopK,
+		MaxOutputTokens:    cfg.MaxOutputTokens,
+		MediaResolution:    cfg.MediaResolution,
+		Seed:               cfg.Seed,
+		SpeechConfig:       cfg.SpeechConfig,
+		ThinkingConfig:     cfg.ThinkingConfig,
+		SystemInstruction:  cfg.SystemInstruction,
+		Tools:              cfg.Tools,
+	}
+	if cfg.HTTPOptions != nil {
+		opts := *cfg.HTTPOptions
+		opts.Headers = cfg.HTTPOptions.Headers.Clone()
+		liveCfg.HTTPOptions = &opts
+	}
+	for _, m := range cfg.ResponseModalities {
+		liveCfg.ResponseModalities = append(liveCfg.ResponseModalities, genai.Modality(m))
+	}
+	return liveCfg
+}
+
+// liveConnection implements model.LiveConnection on top of a Live API
+// session.
+type liveConnection struct {
+	session *genai.Session
+
+	mu     sync.Mutex // guards writes to session and closed
+	closed bool
+}
+
+// Send implements model.LiveConnection.
+func (c *liveConnection) Send(req *model.LiveRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return fmt.Errorf("connection is closed")
+	}
+
+	switch {
+	case req.ActivityStart:
+		return c.session.SendRealtimeInput(genai.LiveRealtimeInput{ActivityStart: &genai.ActivityStart{}})
+	case req.ActivityEnd:
+		return c.session.SendRealtimeInput(genai.LiveRealtimeInput{ActivityEnd: &genai.ActivityEnd{}})
+	case req.Blob != nil:
+		return c.session.SendRealtimeInput(genai.LiveRealtimeInput{Media: req.Blob})
+	case req.Content != nil:
+		var responses []*genai.FunctionResponse
+		for _, p := range req.Content.Parts {
+			if p.FunctionResponse != nil {
+				responses = append(responses, p.FunctionResponse)
+			}
+		}
+		if len(responses) > 0 {
+			return c.session.SendToolResponse(genai.LiveToolResponseInput{FunctionResponses: responses})
+		}
+		return c.session.SendClientContent(genai.LiveClientContentInput{Turns: []*genai.Content{req.Content}})
+	}
+	return nil
+}
+
+// Receive implements model.LiveConnection.
+//
+// Text and transcriptions are streamed as partial responses and sent once
+// more as a complete response when they are finished, the turn is over or
+// the model is interrupted. Audio is streamed as partial responses only.
+func (c *liveConnection) Receive() iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		var text, input, output strings.Builder
+		// flush yields the text and transcriptions accumulated since the
+		// last flush.
+		flush := func() bool {
+			return flushInputTranscription(&input, yield) &&
+				flushOutputTranscription(&output, yield) &&
+				flushText(&text, yield)
+		}
+
+		for {
+			msg, err := c.session.Receive()
+			if err != nil {
+				if c.isClosed() {
+					return
+				}
+				yield(nil, fmt.Errorf("failed to receive from model: %w", err))
+				return
+			}
+
+			if msg.ToolCall != nil {
+				if !flush() {
+					return
+				}
+				content := &genai.Content{Role: genai.RoleModel}
+				for _, fc := range msg.ToolCall.FunctionCalls {
+					content.Parts = append(content.Parts, &genai.Part{FunctionCall: fc})
+				}
+				if !yield(&model.LLMResponse{Content: content}, nil) {
+					return
+				}
+			}
+
+			if usage := msg.UsageMetadata; usage != nil {
+				if !yield(&model.LLMResponse{
+					UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+						PromptTokenCount:     usage.PromptTokenCount,
+						CandidatesTokenCount: usage.ResponseTokenCount,
+						TotalTokenCount:      usage.TotalTokenCount,
+					},
+				}, nil) {
+					return
+				}
+			}
+
+			sc := msg.ServerContent
+			if sc == nil {
+				continue
+			}
+			if t := sc.InputTranscription; t != nil {
+				input.WriteString(t.Text)
+				if t.Text != "" && !yield(&model.LLMResponse{InputTranscription: t, Partial: true}, nil) {
+					return
+				}
+				if t.Finished && !flushInputTranscription(&input, yield) {
+					return
+				}
+			}
+			if t := sc.OutputTranscription; t != nil {
+				output.WriteString(t.Text)
+				if t.Text != "" && !yield(&model.LLMResponse{OutputTranscription: t, Partial: true}, nil) {
+					return
+				}
+				if t.Finished && !flushOutputTranscription(&output, yield) {
+					return
+				}
+			}
+			if sc.ModelTurn != nil {
+				for _, p := range sc.ModelTurn.Parts {
+					var resp *model.LLMResponse
+					switch {
+					case p.Text != "":
+						text.WriteString(p.Text)
+						resp = &model.LLMResponse{
+							Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{p}},
+							Partial: true,
+						}
+					case p.InlineData != nil:
+						resp = &model.LLMResponse{
+							Content: &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{p}},
+							Partial: true,
+						}
+					default:
+						continue
+					}
+					resp.GroundingMetadata = sc.GroundingMetadata
+					if !yield(resp, nil) {
+						return
+					}
+				}
+			}
+			if sc.Interrupted {
+				if !flush() || !yield(&model.LLMResponse{Interrupted: true}, nil) {
+					return
+				}
+			}
+			if sc.TurnComplete {
+				if !flush() || !yield(&model.LLMResponse{TurnComplete: true}, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func flushText(text *strings.Builder, yield func(*model.LLMResponse, error) bool) bool {
+	if text.Len() == 0 {
+		return true
+	}
+	resp := &model.LLMResponse{Content: genai.NewContentFromText(text.String(), genai.RoleModel)}
+	text.Reset()
+	return yield(resp, nil)
+}
+
+func flushInputTranscription(input *strings.Builder, yield func(*model.LLMResponse, error) bool) bool {
+	if input.Len() == 0 {
+		return true
+	}
+	resp := &model.LLMResponse{InputTranscription: &genai.Transcription{Text: input.String(), Finished: true}}
+	input.Reset()
+	return yield(resp, nil)
+}
+
+func flushOutputTranscription(output *strings.Builder, yield func(*model.LLMResponse, error) bool) bool {
+	if output.Len() == 0 {
+		return true
+	}
+	resp := &model.LLMResponse{OutputTranscription: &genai.Transcription{Text: output.String(), Finished: true}}
+	output.Reset()
+	return yield(resp, nil)
+}
+
+// Close implements model.LiveConnection.
+func (c *liveConnection) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.session.Close()
+}
+
+func (c *liveConnection) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+var _ model.LiveLLM = (*geminiModel)(nil)
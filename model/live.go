@@ -0,0 +1,60 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"context"
+	"iter"
+
+	"google.golang.org/genai"
+)
+
+// LiveLLM is implemented by models that support bidirectional streaming,
+// where the user input and the model output are exchanged over a long-lived
+// connection, e.g. the Gemini Live API.
+type LiveLLM interface {
+	LLM
+	// Connect opens a live connection to the model. The system instruction,
+	// tools and generation config are taken from req. req.Contents is sent
+	// as the conversation history.
+	Connect(ctx context.Context, req *LLMRequest) (LiveConnection, error)
+}
+
+// LiveConnection is a live connection to a model.
+type LiveConnection interface {
+	// Send sends the request to the model. It is safe to call Send
+	// concurrently.
+	Send(req *LiveRequest) error
+	// Receive returns the responses of the model. The sequence ends when
+	// the connection is closed.
+	Receive() iter.Seq2[*LLMResponse, error]
+	// Close closes the connection.
+	Close() error
+}
+
+// LiveRequest is a message sent to a model over a live connection.
+// Only one of its fields should be set.
+type LiveRequest struct {
+	// Content is sent as a complete turn. Function responses in it are sent
+	// as the responses to the model's tool calls.
+	Content *genai.Content
+	// Blob is realtime input, e.g. an audio chunk or a video frame.
+	Blob *genai.Blob
+	// ActivityStart and ActivityEnd mark the start and the end of the user
+	// activity, e.g. speech. They can only be sent if automatic activity
+	// detection is disabled.
+	ActivityStart bool
+	ActivityEnd   bool
+}
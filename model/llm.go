@@ -35,6 +35,17 @@ type LLMRequest struct {
 	Config   *genai.GenerateContentConfig
 
 	Tools map[string]any `json:"-"`
+
+	// LiveConnectConfig holds the settings that only apply to live
+	// connections, see [LiveLLM]. The generation settings are taken from
+	// Config.
+	LiveConnectConfig *genai.LiveConnectConfig `json:"-"`
+
+	// CacheConfig, if set, asks the model to cache the static part of the
+	// request. CacheMetadata describes the cache used by the previous
+	// request, if any, so that it can be reused.
+	CacheConfig   *CacheConfig   `json:"-"`
+	CacheMetadata *CacheMetadata `json:"-"`
 }
 
 // LLMResponse is the raw LLM response.
@@ -54,9 +65,16 @@ type LLMResponse struct {
 	TurnComplete bool
 	// Flag indicating that LLM was interrupted when generating the content.
 	// Usually it is due to user interruption during a bidi streaming.
-	Interrupted  bool
-	ErrorCode    string
-	ErrorMessage string
-	FinishReason genai.FinishReason
-	AvgLogprobs  float64
+	Interrupted bool
+	// InputTranscription and OutputTranscription are the transcriptions of
+	// the user and the model audio. Only used for bidi streaming.
+	InputTranscription  *genai.Transcription
+	OutputTranscription *genai.Transcription
+	ErrorCode           string
+	ErrorMessage        string
+	FinishReason        genai.FinishReason
+	AvgLogprobs         float64
+	// CacheMetadata describes the cached contents used to generate the
+	// response, if the request was cached.
+	CacheMetadata *CacheMetadata
 }
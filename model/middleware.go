@@ -0,0 +1,220 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"context"
+	"iter"
+	"log/slog"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"google.golang.org/adk/internal/logging"
+)
+
+// Middleware wraps a model to add a behavior to its calls, e.g. logging or
+// retries.
+type Middleware func(LLM) LLM
+
+// Chain returns llm wrapped with the middlewares. The first middleware is the
+// outermost one: it sees a call first and its responses last.
+func Chain(llm LLM, middlewares ...Middleware) LLM {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		llm = middlewares[i](llm)
+	}
+	return llm
+}
+
+// GenerateFunc is the signature of [LLM.GenerateContent].
+type GenerateFunc func(ctx context.Context, req *LLMRequest, stream bool) iter.Seq2[*LLMResponse, error]
+
+// Wrap returns a model with the name of llm whose GenerateContent calls
+// generate, with next calling llm. If llm is a [LiveLLM], so is the returned
+// model; its live connections are not wrapped.
+func Wrap(llm LLM, generate func(ctx context.Context, req *LLMRequest, stream bool, next GenerateFunc) iter.Seq2[*LLMResponse, error]) LLM {
+	w := &wrappedModel{llm: llm, generate: generate}
+	if live, ok := llm.(LiveLLM); ok {
+		return &wrappedLiveModel{wrappedModel: w, live: live}
+	}
+	return w
+}
+
+type wrappedModel struct {
+	llm      LLM
+	generate func(ctx context.Context, req *LLMRequest, stream bool, next GenerateFunc) iter.Seq2[*LLMResponse, error]
+}
+
+func (m *wrappedModel) Name() string {
+	return m.llm.Name()
+}
+
+func (m *wrappedModel) GenerateContent(ctx context.Context, req *LLMRequest, stream bool) iter.Seq2[*LLMResponse, error] {
+	return m.generate(ctx, req, stream, m.llm.GenerateContent)
+}
+
+type wrappedLiveModel struct {
+	*wrappedModel
+	live LiveLLM
+}
+
+func (m *wrappedLiveModel) Connect(ctx context.Context, req *LLMRequest) (LiveConnection, error) {
+	return m.live.Connect(ctx, req)
+}
+
+// Logging returns a middleware logging every model call, with its duration,
+// token usage and error, at the debug level, or the warning level if it
+// failed. If logger is nil, the logger of the context is used.
+func Logging(logger *slog.Logger) Middleware {
+	return func(llm LLM) LLM {
+		return Wrap(llm, func(ctx context.Context, req *LLMRequest, stream bool, next GenerateFunc) iter.Seq2[*LLMResponse, error] {
+			return func(yield func(*LLMResponse, error) bool) {
+				l := logger
+				if l == nil {
+					l = logging.FromContext(ctx)
+				}
+				start := time.Now()
+				var usage TokenUsage
+				var callErr error
+				defer func() {
+					attrs := []any{"model", llm.Name(), "stream", stream, "duration", time.Since(start),
+						"prompt_tokens", usage.PromptTokens, "output_tokens", usage.OutputTokens}
+					if callErr != nil {
+						l.Warn("model call failed", append(attrs, "error", callErr)...)
+						return
+					}
+					l.Debug("model call", attrs...)
+				}()
+				for resp, err := range next(ctx, req, stream) {
+					if err != nil {
+						callErr = err
+					} else if resp != nil && !resp.Partial {
+						usage.add(resp)
+					}
+					if !yield(resp, err) {
+						return
+					}
+				}
+			}
+		})
+	}
+}
+
+// Retry returns a middleware retrying the failed model calls, see
+// [WithRetry].
+func Retry(cfg RetryConfig) Middleware {
+	return func(llm LLM) LLM {
+		return WithRetry(llm, cfg)
+	}
+}
+
+// RateLimit returns a middleware limiting the rate of the model calls to
+// perSecond calls per second, with bursts of up to burst calls. A call waits
+// until it is allowed or its context is done.
+//
+// The limit is shared by all the models wrapped with the returned middleware.
+func RateLimit(perSecond float64, burst int) Middleware {
+	limiter := rate.NewLimiter(rate.Limit(perSecond), max(burst, 1))
+	return func(llm LLM) LLM {
+		return Wrap(llm, func(ctx context.Context, req *LLMRequest, stream bool, next GenerateFunc) iter.Seq2[*LLMResponse, error] {
+			return func(yield func(*LLMResponse, error) bool) {
+				if err := limiter.Wait(ctx); err != nil {
+					yield(nil, err)
+					return
+				}
+				for resp, err := range next(ctx, req, stream) {
+					if !yield(resp, err) {
+						return
+					}
+				}
+			}
+		})
+	}
+}
+
+// TokenUsage is the number of tokens used by model calls.
+type TokenUsage struct {
+	// Calls is the number of model calls.
+	Calls int64
+	// PromptTokens is the number of tokens in the prompts, including cached
+	// content and tool use prompts.
+	PromptTokens int64
+	// OutputTokens is the number of generated tokens, including thoughts.
+	OutputTokens int64
+	// TotalTokens is the total number of tokens billed for the calls.
+	TotalTokens int64
+}
+
+func (u *TokenUsage) add(resp *LLMResponse) {
+	m := resp.UsageMetadata
+	if m == nil {
+		return
+	}
+	prompt := int64(m.PromptTokenCount) + int64(m.ToolUsePromptTokenCount)
+	output := int64(m.CandidatesTokenCount) + int64(m.ThoughtsTokenCount)
+	total := int64(m.TotalTokenCount)
+	if total == 0 {
+		total = prompt + output
+	}
+	u.PromptTokens += prompt
+	u.OutputTokens += output
+	u.TotalTokens += total
+}
+
+// TokenCounter accumulates the token usage of the model calls, see
+// [CountTokens]. It is safe for concurrent use.
+type TokenCounter struct {
+	mu    sync.Mutex
+	usage TokenUsage
+}
+
+// Usage returns the usage accumulated so far.
+func (c *TokenCounter) Usage() TokenUsage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.usage
+}
+
+// Reset sets the accumulated usage to zero.
+func (c *TokenCounter) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.usage = TokenUsage{}
+}
+
+// CountTokens returns a middleware adding the token usage reported by the
+// complete responses of the model calls to counter.
+func CountTokens(counter *TokenCounter) Middleware {
+	return func(llm LLM) LLM {
+		return Wrap(llm, func(ctx context.Context, req *LLMRequest, stream bool, next GenerateFunc) iter.Seq2[*LLMResponse, error] {
+			return func(yield func(*LLMResponse, error) bool) {
+				counter.mu.Lock()
+				counter.usage.Calls++
+				counter.mu.Unlock()
+				for resp, err := range next(ctx, req, stream) {
+					if err == nil && resp != nil && !resp.Partial {
+						counter.mu.Lock()
+						counter.usage.add(resp)
+						counter.mu.Unlock()
+					}
+					if !yield(resp, err) {
+						return
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"iter"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/model"
+)
+
+// usageModel answers every call with a partial and a complete response,
+// both reporting the usage.
+type usageModel struct{}
+
+func (usageModel) Name() string { return "usage" }
+
+func (usageModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		usage := &genai.GenerateContentResponseUsageMetadata{PromptTokenCount: 10, CandidatesTokenCount: 5, TotalTokenCount: 15}
+		if !yield(&model.LLMResponse{Partial: true, UsageMetadata: usage}, nil) {
+			return
+		}
+		yield(&model.LLMResponse{UsageMetadata: usage}, nil)
+	}
+}
+
+func TestChain(t *testing.T) {
+	var calls []string
+	trace := func(name string) model.Middleware {
+		return func(llm model.LLM) model.LLM {
+			return model.Wrap(llm, func(ctx context.Context, req *model.LLMRequest, stream bool, next model.GenerateFunc) iter.Seq2[*model.LLMResponse, error] {
+				calls = append(calls, name)
+				return next(ctx, req, stream)
+			})
+		}
+	}
+	llm := model.Chain(usageModel{}, trace("outer"), trace("inner"))
+	if got := llm.Name(); got != "usage" {
+		t.Errorf("Name() = %q, want %q", got, "usage")
+	}
+	for range llm.GenerateContent(t.Context(), &model.LLMRequest{}, false) {
+	}
+	if diff := cmp.Diff([]string{"outer", "inner"}, calls); diff != "" {
+		t.Errorf("middleware calls mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestWrap_LiveModel(t *testing.T) {
+	if _, ok := model.Chain(usageModel{}, model.Retry(model.RetryConfig{})).(model.LiveLLM); ok {
+		t.Error("wrapped model is a live model, want a regular model")
+	}
+	if _, ok := model.Chain(&testutil.MockLiveModel{}, model.Retry(model.RetryConfig{})).(model.LiveLLM); !ok {
+		t.Error("wrapped live model is not a live model")
+	}
+}
+
+func TestCountTokens(t *testing.T) {
+	var counter model.TokenCounter
+	llm := model.Chain(usageModel{}, model.CountTokens(&counter))
+	for range 2 {
+		for range llm.GenerateContent(t.Context(), &model.LLMRequest{}, true) {
+		}
+	}
+	want := model.TokenUsage{Calls: 2, PromptTokens: 20, OutputTokens: 10, TotalTokens: 30}
+	if diff := cmp.Diff(want, counter.Usage()); diff != "" {
+		t.Errorf("Usage() mismatch (-want +got):\n%s", diff)
+	}
+	counter.Reset()
+	if diff := cmp.Diff(model.TokenUsage{}, counter.Usage()); diff != "" {
+		t.Errorf("Usage() after Reset() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	llm := model.Chain(usageModel{}, model.RateLimit(0.001, 1))
+	for _, err := range llm.GenerateContent(t.Context(), &model.LLMRequest{}, false) {
+		if err != nil {
+			t.Fatalf("first call error = %v, want nil", err)
+		}
+	}
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	var gotErr error
+	for _, err := range llm.GenerateContent(ctx, &model.LLMRequest{}, false) {
+		gotErr = err
+	}
+	if gotErr == nil {
+		t.Error("call over the limit succeeded, want error")
+	}
+}
+
+func TestLogging(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	llm := model.Chain(usageModel{}, model.Logging(logger))
+	for range llm.GenerateContent(t.Context(), &model.LLMRequest{}, true) {
+	}
+	if got := buf.String(); !strings.Contains(got, "msg=\"model call\" model=usage stream=true") || !strings.Contains(got, "prompt_tokens=10 output_tokens=5") {
+		t.Errorf("log = %q, want the model call with its usage", got)
+	}
+
+	buf.Reset()
+	failing := model.Chain(&flakyModel{failures: []error{errors.New("boom")}}, model.Logging(logger))
+	for range failing.GenerateContent(t.Context(), &model.LLMRequest{}, false) {
+	}
+	if got := buf.String(); !strings.Contains(got, "level=WARN msg=\"model call failed\"") || !strings.Contains(got, "error=boom") {
+		t.Errorf("log = %q, want the failed model call", got)
+	}
+}
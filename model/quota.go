@@ -0,0 +1,181 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"context"
+	"iter"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/time/rate"
+
+	"google.golang.org/adk/internal/metrics"
+)
+
+// QuotaLimits are the limits applied to the calls of a model.
+type QuotaLimits struct {
+	// MaxConcurrent is the maximum number of calls in flight. Zero means no
+	// limit.
+	MaxConcurrent int
+	// RequestsPerMinute is the maximum rate of the calls. Zero means no
+	// limit.
+	RequestsPerMinute int
+	// Burst is the number of calls that can start at once within the rate.
+	// If zero, the calls are evenly spaced.
+	Burst int
+}
+
+// QuotaConfig defines the limits of a QuotaLimiter.
+type QuotaConfig struct {
+	// Default are the limits of the models missing from Models.
+	Default QuotaLimits
+	// Models are the limits per model name.
+	Models map[string]QuotaLimits
+}
+
+// QuotaStats are statistics about the calls of a model made through a
+// QuotaLimiter.
+type QuotaStats struct {
+	// InFlight is the number of calls in progress.
+	InFlight int
+	// Waiting is the number of calls waiting for the limits.
+	Waiting int
+	// Calls is the number of calls started so far.
+	Calls int64
+	// TotalWait is the time the calls started so far spent waiting.
+	TotalWait time.Duration
+	// MaxWait is the longest wait of a call.
+	MaxWait time.Duration
+}
+
+// QuotaLimiter caps the concurrency and the rate of model calls per model
+// name. A single limiter is meant to be shared by all the agents of a
+// process, e.g. to keep the fan-out of a parallel agent within the quotas of
+// the model provider. It is safe for concurrent use.
+//
+// The wait times are exported in the adk_model_quota_wait_seconds metric.
+type QuotaLimiter struct {
+	cfg QuotaConfig
+
+	mu     sync.Mutex
+	models map[string]*modelQuota
+}
+
+type modelQuota struct {
+	sem     *semaphore.Weighted
+	limiter *rate.Limiter
+	stats   QuotaStats
+}
+
+// NewQuotaLimiter creates a QuotaLimiter with the given limits.
+func NewQuotaLimiter(cfg QuotaConfig) *QuotaLimiter {
+	return &QuotaLimiter{cfg: cfg, models: make(map[string]*modelQuota)}
+}
+
+func (l *QuotaLimiter) quota(name string) *modelQuota {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if q, ok := l.models[name]; ok {
+		return q
+	}
+	limits, ok := l.cfg.Models[name]
+	if !ok {
+		limits = l.cfg.Default
+	}
+	q := &modelQuota{}
+	if limits.MaxConcurrent > 0 {
+		q.sem = semaphore.NewWeighted(int64(limits.MaxConcurrent))
+	}
+	if limits.RequestsPerMinute > 0 {
+		q.limiter = rate.NewLimiter(rate.Limit(float64(limits.RequestsPerMinute)/60), max(limits.Burst, 1))
+	}
+	l.models[name] = q
+	return q
+}
+
+// Stats returns the statistics of the calls of the named model.
+func (l *QuotaLimiter) Stats(name string) QuotaStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if q, ok := l.models[name]; ok {
+		return q.stats
+	}
+	return QuotaStats{}
+}
+
+// acquire waits until a call of the model is allowed. The returned function
+// must be called when the call is done.
+func (l *QuotaLimiter) acquire(ctx context.Context, name string) (release func(), err error) {
+	q := l.quota(name)
+	l.mu.Lock()
+	q.stats.Waiting++
+	l.mu.Unlock()
+
+	start := time.Now()
+	// The rate is waited for first, so that the calls waiting for it do not
+	// hold a concurrency slot.
+	if q.limiter != nil {
+		err = q.limiter.Wait(ctx)
+	}
+	if err == nil && q.sem != nil {
+		err = q.sem.Acquire(ctx, 1)
+	}
+	wait := time.Since(start)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	q.stats.Waiting--
+	if err != nil {
+		return nil, err
+	}
+	q.stats.InFlight++
+	q.stats.Calls++
+	q.stats.TotalWait += wait
+	q.stats.MaxWait = max(q.stats.MaxWait, wait)
+	metrics.RecordModelQuotaWait(name, wait)
+	return func() {
+		if q.sem != nil {
+			q.sem.Release(1)
+		}
+		l.mu.Lock()
+		q.stats.InFlight--
+		l.mu.Unlock()
+	}, nil
+}
+
+// Quota returns a middleware making the model calls wait for the limits of
+// the limiter for the model name. A call holds its concurrency slot until its
+// responses are consumed, and fails if its context is done while waiting.
+func Quota(l *QuotaLimiter) Middleware {
+	return func(llm LLM) LLM {
+		return Wrap(llm, func(ctx context.Context, req *LLMRequest, stream bool, next GenerateFunc) iter.Seq2[*LLMResponse, error] {
+			return func(yield func(*LLMResponse, error) bool) {
+				release, err := l.acquire(ctx, llm.Name())
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				defer release()
+				for resp, err := range next(ctx, req, stream) {
+					if !yield(resp, err) {
+						return
+					}
+				}
+			}
+		})
+	}
+}
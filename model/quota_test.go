@@ -0,0 +1,133 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model_test
+
+import (
+	"context"
+	"iter"
+	"testing"
+	"time"
+
+	"google.golang.org/adk/model"
+)
+
+// blockingModel answers a call once a value is sent on its release channel.
+type blockingModel struct {
+	name    string
+	started chan struct{}
+	release chan struct{}
+}
+
+func (m *blockingModel) Name() string { return m.name }
+
+func (m *blockingModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		m.started <- struct{}{}
+		<-m.release
+		yield(&model.LLMResponse{}, nil)
+	}
+}
+
+func TestQuota_MaxConcurrent(t *testing.T) {
+	limiter := model.NewQuotaLimiter(model.QuotaConfig{
+		Default: model.QuotaLimits{MaxConcurrent: 2},
+	})
+	m := &blockingModel{name: "gemini", started: make(chan struct{}), release: make(chan struct{})}
+	// Two models of the same name share the limits.
+	models := []model.LLM{
+		model.Chain(m, model.Quota(limiter)),
+		model.Chain(m, model.Quota(limiter)),
+		model.Chain(m, model.Quota(limiter)),
+	}
+	done := make(chan error)
+	for _, llm := range models {
+		go func() {
+			var callErr error
+			for _, err := range llm.GenerateContent(t.Context(), &model.LLMRequest{}, false) {
+				callErr = err
+			}
+			done <- callErr
+		}()
+	}
+
+	<-m.started
+	<-m.started
+	waitFor(t, func() bool { return limiter.Stats("gemini").Waiting == 1 })
+	if got := limiter.Stats("gemini").InFlight; got != 2 {
+		t.Errorf("InFlight = %d, want 2", got)
+	}
+
+	m.release <- struct{}{}
+	<-m.started
+	m.release <- struct{}{}
+	m.release <- struct{}{}
+	for range models {
+		if err := <-done; err != nil {
+			t.Errorf("call failed: %v", err)
+		}
+	}
+	stats := limiter.Stats("gemini")
+	if stats.Calls != 3 || stats.InFlight != 0 || stats.Waiting != 0 || stats.MaxWait <= 0 {
+		t.Errorf("Stats() = %+v, want 3 calls, none in flight or waiting, and a wait", stats)
+	}
+}
+
+func TestQuota_RequestsPerMinute(t *testing.T) {
+	limiter := model.NewQuotaLimiter(model.QuotaConfig{
+		Models: map[string]model.QuotaLimits{"usage": {RequestsPerMinute: 1}},
+	})
+	llm := model.Chain(usageModel{}, model.Quota(limiter))
+	for _, err := range llm.GenerateContent(t.Context(), &model.LLMRequest{}, false) {
+		if err != nil {
+			t.Fatalf("first call error = %v, want nil", err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	var gotErr error
+	for _, err := range llm.GenerateContent(ctx, &model.LLMRequest{}, false) {
+		gotErr = err
+	}
+	if gotErr == nil {
+		t.Error("call over the limit succeeded, want error")
+	}
+
+	// The other models get the default limits, i.e. none.
+	other := &blockingModel{name: "other", started: make(chan struct{}, 2), release: make(chan struct{}, 2)}
+	other.release <- struct{}{}
+	other.release <- struct{}{}
+	otherLLM := model.Chain(other, model.Quota(limiter))
+	for range 2 {
+		for _, err := range otherLLM.GenerateContent(t.Context(), &model.LLMRequest{}, false) {
+			if err != nil {
+				t.Fatalf("call of another model error = %v, want nil", err)
+			}
+		}
+	}
+	if got := limiter.Stats("usage"); got.Calls != 1 || got.Waiting != 0 {
+		t.Errorf("Stats() = %+v, want 1 call and none waiting", got)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met before the deadline")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
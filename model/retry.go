@@ -0,0 +1,207 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/logging"
+)
+
+const (
+	// DefaultRetryAttempts is the maximum number of calls of a model when no
+	// limit is configured.
+	DefaultRetryAttempts = 5
+	// DefaultRetryInitialDelay is the delay before the first retry when none
+	// is configured.
+	DefaultRetryInitialDelay = time.Second
+	// DefaultRetryMaxDelay is the maximum delay between two calls when none is
+	// configured.
+	DefaultRetryMaxDelay = 30 * time.Second
+	// DefaultRetryMultiplier is the factor applied to the delay after each
+	// retry when none is configured.
+	DefaultRetryMultiplier = 2.0
+	// DefaultRetryJitter is the fraction of the delay randomized when none is
+	// configured.
+	DefaultRetryJitter = 0.2
+)
+
+// RetryConfig configures the retries of the failed calls of a model, see
+// [WithRetry].
+type RetryConfig struct {
+	// MaxAttempts is the maximum number of calls, including the first one.
+	// If zero, DefaultRetryAttempts is used.
+	MaxAttempts int
+	// InitialDelay is the delay before the first retry.
+	// If zero, DefaultRetryInitialDelay is used.
+	InitialDelay time.Duration
+	// MaxDelay caps the delay between two calls, including the delays
+	// requested by the server. If zero, DefaultRetryMaxDelay is used.
+	MaxDelay time.Duration
+	// Multiplier is the factor applied to the delay after each retry.
+	// If zero, DefaultRetryMultiplier is used.
+	Multiplier float64
+	// Jitter is the fraction of the delay that is randomized, in [0, 1].
+	// If zero, DefaultRetryJitter is used. A negative value disables the
+	// jitter.
+	Jitter float64
+	// MaxLatency caps the total time spent in a call, retries included. A
+	// retry that would start after it is not made. If zero, there is no cap.
+	MaxLatency time.Duration
+	// Classify decides whether a failed call is retried.
+	// If nil, ClassifyAPIError is used.
+	Classify func(err error) RetryDecision
+}
+
+// RetryDecision is the classification of the error of a model call.
+type RetryDecision struct {
+	// Retry reports whether the call can be retried.
+	Retry bool
+	// After is the delay requested by the server before the next call, e.g.
+	// with a Retry-After header. If zero, the exponential backoff is used.
+	After time.Duration
+	// ErrorCode is the error code of the response returned instead of the
+	// error once the attempts are exhausted. If empty, the error is
+	// returned.
+	ErrorCode string
+}
+
+// ClassifyAPIError retries the calls that failed with a genai.APIError with
+// the 429 (resource exhausted) or 503 (unavailable) status. The retry delay
+// of the google.rpc.RetryInfo error detail is honored.
+func ClassifyAPIError(err error) RetryDecision {
+	var apiErr genai.APIError
+	if pErr := (*genai.APIError)(nil); errors.As(err, &pErr) && pErr != nil {
+		apiErr = *pErr
+	} else if !errors.As(err, &apiErr) {
+		return RetryDecision{}
+	}
+	if apiErr.Code != http.StatusTooManyRequests && apiErr.Code != http.StatusServiceUnavailable {
+		return RetryDecision{}
+	}
+	d := RetryDecision{Retry: true, ErrorCode: apiErr.Status}
+	// The status is the canonical code, e.g. RESOURCE_EXHAUSTED, unless the
+	// body of the response could not be parsed.
+	if d.ErrorCode == "" || strings.Contains(d.ErrorCode, " ") {
+		d.ErrorCode = strconv.Itoa(apiErr.Code)
+	}
+	for _, detail := range apiErr.Details {
+		if t, _ := detail["@type"].(string); t != "type.googleapis.com/google.rpc.RetryInfo" {
+			continue
+		}
+		if s, ok := detail["retryDelay"].(string); ok {
+			if after, err := time.ParseDuration(s); err == nil {
+				d.After = after
+			}
+		}
+	}
+	return d
+}
+
+// WithRetry returns a model retrying the failed calls of llm with an
+// exponential backoff, as configured by cfg.
+//
+// A streaming call is only retried if it fails before producing a response.
+// Once the attempts are exhausted, or the next retry would exceed
+// cfg.MaxLatency, the last error is returned, or a response with its error
+// code if the classification provides one.
+//
+// Live connections, if llm supports them, are not retried.
+func WithRetry(llm LLM, cfg RetryConfig) LLM {
+	if cfg.MaxAttempts == 0 {
+		cfg.MaxAttempts = DefaultRetryAttempts
+	}
+	if cfg.InitialDelay == 0 {
+		cfg.InitialDelay = DefaultRetryInitialDelay
+	}
+	if cfg.MaxDelay == 0 {
+		cfg.MaxDelay = DefaultRetryMaxDelay
+	}
+	if cfg.Multiplier == 0 {
+		cfg.Multiplier = DefaultRetryMultiplier
+	}
+	if cfg.Jitter == 0 {
+		cfg.Jitter = DefaultRetryJitter
+	}
+	cfg.Jitter = min(max(cfg.Jitter, 0), 1)
+	if cfg.Classify == nil {
+		cfg.Classify = ClassifyAPIError
+	}
+	return Wrap(llm, func(ctx context.Context, req *LLMRequest, stream bool, next GenerateFunc) iter.Seq2[*LLMResponse, error] {
+		return func(yield func(*LLMResponse, error) bool) {
+			start := time.Now()
+			for attempt := 1; ; attempt++ {
+				var callErr error
+				produced := false
+				for resp, err := range next(ctx, req, stream) {
+					if err != nil && !produced {
+						callErr = err
+						break
+					}
+					produced = true
+					if !yield(resp, err) {
+						return
+					}
+				}
+				if callErr == nil {
+					return
+				}
+
+				d := cfg.Classify(callErr)
+				if !d.Retry || ctx.Err() != nil {
+					yield(nil, callErr)
+					return
+				}
+				delay := cfg.delay(attempt, d.After)
+				if attempt >= cfg.MaxAttempts || (cfg.MaxLatency > 0 && time.Since(start)+delay > cfg.MaxLatency) {
+					if d.ErrorCode == "" {
+						yield(nil, callErr)
+						return
+					}
+					yield(&LLMResponse{ErrorCode: d.ErrorCode, ErrorMessage: callErr.Error()}, nil)
+					return
+				}
+
+				logging.FromContext(ctx).Warn("model call failed, retrying", "model", llm.Name(), "attempt", attempt, "delay", delay, "error", callErr)
+				timer := time.NewTimer(delay)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					yield(nil, context.Cause(ctx))
+					return
+				case <-timer.C:
+				}
+			}
+		}
+	})
+}
+
+// delay returns the delay before the next call after the given attempt.
+func (cfg *RetryConfig) delay(attempt int, after time.Duration) time.Duration {
+	backoff := float64(cfg.InitialDelay) * math.Pow(cfg.Multiplier, float64(attempt-1))
+	backoff *= 1 + cfg.Jitter*(2*rand.Float64()-1)
+	delay := time.Duration(min(backoff, float64(cfg.MaxDelay)))
+	return min(max(delay, after), cfg.MaxDelay)
+}
@@ -0,0 +1,194 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model_test
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+// flakyModel fails the calls with the errors of failures before succeeding.
+type flakyModel struct {
+	failures []error
+	// partial, if set, is yielded before the error of a failed call.
+	partial *model.LLMResponse
+	calls   int
+}
+
+func (m *flakyModel) Name() string { return "flaky" }
+
+func (m *flakyModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		m.calls++
+		if m.calls <= len(m.failures) {
+			if m.partial != nil && !yield(m.partial, nil) {
+				return
+			}
+			yield(nil, m.failures[m.calls-1])
+			return
+		}
+		yield(&model.LLMResponse{Content: genai.NewContentFromText("ok", genai.RoleModel)}, nil)
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	unavailable := genai.APIError{Code: 503, Status: "UNAVAILABLE"}
+	exhausted := genai.APIError{Code: 429, Status: "RESOURCE_EXHAUSTED"}
+	invalid := genai.APIError{Code: 400, Status: "INVALID_ARGUMENT"}
+	ok := &model.LLMResponse{Content: genai.NewContentFromText("ok", genai.RoleModel)}
+
+	for _, tc := range []struct {
+		name      string
+		llm       *flakyModel
+		cfg       model.RetryConfig
+		wantResps []*model.LLMResponse
+		wantErr   bool
+		wantCalls int
+	}{
+		{
+			name:      "retried until success",
+			llm:       &flakyModel{failures: []error{unavailable, exhausted}},
+			wantResps: []*model.LLMResponse{ok},
+			wantCalls: 3,
+		},
+		{
+			name:      "not retryable",
+			llm:       &flakyModel{failures: []error{invalid}},
+			wantErr:   true,
+			wantCalls: 1,
+		},
+		{
+			name:      "attempts exhausted",
+			llm:       &flakyModel{failures: []error{unavailable, exhausted, exhausted}},
+			cfg:       model.RetryConfig{MaxAttempts: 3},
+			wantResps: []*model.LLMResponse{{ErrorCode: "RESOURCE_EXHAUSTED", ErrorMessage: exhausted.Error()}},
+			wantCalls: 3,
+		},
+		{
+			name:      "latency cap",
+			llm:       &flakyModel{failures: []error{unavailable, unavailable}},
+			cfg:       model.RetryConfig{InitialDelay: time.Hour, MaxDelay: time.Hour, MaxLatency: time.Minute},
+			wantResps: []*model.LLMResponse{{ErrorCode: "UNAVAILABLE", ErrorMessage: unavailable.Error()}},
+			wantCalls: 1,
+		},
+		{
+			name: "no error code",
+			llm:  &flakyModel{failures: []error{errors.New("boom"), errors.New("boom")}},
+			cfg: model.RetryConfig{
+				MaxAttempts: 2,
+				Classify:    func(error) model.RetryDecision { return model.RetryDecision{Retry: true} },
+			},
+			wantErr:   true,
+			wantCalls: 2,
+		},
+		{
+			name:      "failure after a response",
+			llm:       &flakyModel{failures: []error{unavailable}, partial: &model.LLMResponse{Partial: true}},
+			wantResps: []*model.LLMResponse{{Partial: true}},
+			wantErr:   true,
+			wantCalls: 1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.cfg.InitialDelay == 0 {
+				tc.cfg.InitialDelay = time.Millisecond
+			}
+			llm := model.WithRetry(tc.llm, tc.cfg)
+
+			var gotResps []*model.LLMResponse
+			var gotErr error
+			for resp, err := range llm.GenerateContent(t.Context(), &model.LLMRequest{}, true) {
+				if err != nil {
+					gotErr = err
+					continue
+				}
+				gotResps = append(gotResps, resp)
+			}
+			if (gotErr != nil) != tc.wantErr {
+				t.Errorf("GenerateContent() error = %v, wantErr %v", gotErr, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.wantResps, gotResps); diff != "" {
+				t.Errorf("GenerateContent() responses mismatch (-want +got):\n%s", diff)
+			}
+			if tc.llm.calls != tc.wantCalls {
+				t.Errorf("model called %d times, want %d", tc.llm.calls, tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestWithRetry_ContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	llm := &flakyModel{failures: []error{genai.APIError{Code: 503}}}
+	retry := model.WithRetry(llm, model.RetryConfig{InitialDelay: time.Hour})
+	cancel()
+	for _, err := range retry.GenerateContent(ctx, &model.LLMRequest{}, false) {
+		if err == nil {
+			t.Error("GenerateContent() succeeded, want error")
+		}
+	}
+	if llm.calls != 1 {
+		t.Errorf("model called %d times, want 1", llm.calls)
+	}
+}
+
+func TestClassifyAPIError(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		err  error
+		want model.RetryDecision
+	}{
+		{
+			name: "resource exhausted with retry info",
+			err: genai.APIError{Code: 429, Status: "RESOURCE_EXHAUSTED", Details: []map[string]any{
+				{"@type": "type.googleapis.com/google.rpc.QuotaFailure"},
+				{"@type": "type.googleapis.com/google.rpc.RetryInfo", "retryDelay": "12s"},
+			}},
+			want: model.RetryDecision{Retry: true, After: 12 * time.Second, ErrorCode: "RESOURCE_EXHAUSTED"},
+		},
+		{
+			name: "wrapped unavailable",
+			err:  errors.Join(errors.New("call failed"), genai.APIError{Code: 503, Status: "503 Service Unavailable"}),
+			want: model.RetryDecision{Retry: true, ErrorCode: "503"},
+		},
+		{
+			name: "pointer",
+			err:  &genai.APIError{Code: 503, Status: "UNAVAILABLE"},
+			want: model.RetryDecision{Retry: true, ErrorCode: "UNAVAILABLE"},
+		},
+		{
+			name: "invalid argument",
+			err:  genai.APIError{Code: 400, Status: "INVALID_ARGUMENT"},
+		},
+		{
+			name: "other error",
+			err:  errors.New("boom"),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, model.ClassifyAPIError(tc.err)); diff != "" {
+				t.Errorf("ClassifyAPIError() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
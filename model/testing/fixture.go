@@ -0,0 +1,146 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package testing provides fake models that record and replay the
+// interactions with a real model, so that agent tests are deterministic and
+// run without credentials.
+//
+// A test records a fixture once by wrapping the real model with
+// [NewRecordingModel], then replays it with [NewReplayModel]:
+//
+//	import modeltesting "google.golang.org/adk/model/testing"
+//
+//	m, err := modeltesting.NewReplayModel("testdata/weather_agent.json")
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	a, err := llmagent.New(llmagent.Config{Name: "weather_agent", Model: m})
+package testing
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+)
+
+// Fixture is the recording of the interactions with a model.
+type Fixture struct {
+	// Model is the name of the recorded model.
+	Model string `json:"model"`
+	// Interactions are the recorded model calls, in the order they were made.
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded model call.
+type Interaction struct {
+	Request *Request `json:"request"`
+	// Responses are the responses returned by the model, more than one if
+	// the call was streamed.
+	Responses []*model.LLMResponse `json:"responses,omitempty"`
+	// Error is the message of the error returned by the model, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// Request is the recorded part of a [model.LLMRequest].
+type Request struct {
+	Model    string                       `json:"model,omitempty"`
+	Contents []*genai.Content             `json:"contents,omitempty"`
+	Config   *genai.GenerateContentConfig `json:"config,omitempty"`
+}
+
+// LoadFixture reads the fixture stored in the file at path.
+func LoadFixture(path string) (*Fixture, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	var f Fixture
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("failed to decode fixture %q: %w", path, err)
+	}
+	return &f, nil
+}
+
+// Save writes the fixture to the file at path, creating its directory if
+// needed.
+func (f *Fixture) Save(path string) error {
+	b, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(b, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	return nil
+}
+
+// newRequest returns the recorded part of req. The HTTP options, which hold
+// credentials and client headers, are not recorded.
+func newRequest(req *model.LLMRequest) (*Request, error) {
+	r := &Request{Model: req.Model, Contents: req.Contents, Config: req.Config}
+	if r.Config != nil && r.Config.HTTPOptions != nil {
+		cfg := *r.Config
+		cfg.HTTPOptions = nil
+		r.Config = &cfg
+	}
+	// Copy the request, as models may modify it while it is being recorded.
+	b, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	var copied Request
+	if err := json.Unmarshal(b, &copied); err != nil {
+		return nil, err
+	}
+	return &copied, nil
+}
+
+// key returns the value identifying r when looking up a recorded request.
+// The function call IDs are ignored as they are generated anew on each run.
+func (r *Request) key() (string, error) {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	var normalized Request
+	if err := json.Unmarshal(b, &normalized); err != nil {
+		return "", err
+	}
+	for _, c := range normalized.Contents {
+		if c == nil {
+			continue
+		}
+		for _, p := range c.Parts {
+			if p.FunctionCall != nil {
+				p.FunctionCall.ID = ""
+			}
+			if p.FunctionResponse != nil {
+				p.FunctionResponse.ID = ""
+			}
+		}
+	}
+	b, err = json.Marshal(normalized)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
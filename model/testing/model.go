@@ -0,0 +1,208 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"sync"
+
+	"google.golang.org/adk/model"
+)
+
+// RecordingModel is a [model.LLM] that forwards the calls to another model
+// and records them into a fixture file.
+type RecordingModel struct {
+	llm  model.LLM
+	path string
+
+	mu      sync.Mutex
+	fixture *Fixture
+}
+
+// NewRecordingModel returns a model that forwards the calls to llm and
+// writes the recorded interactions to the file at path after each call,
+// overwriting the existing file.
+func NewRecordingModel(llm model.LLM, path string) *RecordingModel {
+	return &RecordingModel{
+		llm:     llm,
+		path:    path,
+		fixture: &Fixture{Model: llm.Name()},
+	}
+}
+
+// Name implements model.LLM.
+func (m *RecordingModel) Name() string {
+	return m.llm.Name()
+}
+
+// GenerateContent implements model.LLM.
+func (m *RecordingModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		recorded, err := newRequest(req)
+		if err != nil {
+			yield(nil, fmt.Errorf("failed to record request: %w", err))
+			return
+		}
+		interaction := &Interaction{Request: recorded}
+		stopped := false
+		for resp, err := range m.llm.GenerateContent(ctx, req, stream) {
+			if err != nil {
+				interaction.Error = err.Error()
+			} else if resp != nil {
+				// Copy the response, as callers may modify it.
+				copied, err := copyResponse(resp)
+				if err != nil {
+					yield(nil, fmt.Errorf("failed to record response: %w", err))
+					return
+				}
+				interaction.Responses = append(interaction.Responses, copied)
+			}
+			if !yield(resp, err) {
+				stopped = true
+				break
+			}
+		}
+		if err := m.record(interaction); err != nil && !stopped {
+			yield(nil, err)
+		}
+	}
+}
+
+func copyResponse(resp *model.LLMResponse) (*model.LLMResponse, error) {
+	b, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+	var copied model.LLMResponse
+	if err := json.Unmarshal(b, &copied); err != nil {
+		return nil, err
+	}
+	return &copied, nil
+}
+
+// Fixture returns the interactions recorded so far.
+func (m *RecordingModel) Fixture() *Fixture {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return &Fixture{Model: m.fixture.Model, Interactions: append([]*Interaction(nil), m.fixture.Interactions...)}
+}
+
+func (m *RecordingModel) record(interaction *Interaction) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fixture.Interactions = append(m.fixture.Interactions, interaction)
+	return m.fixture.Save(m.path)
+}
+
+// ReplayModel is a [model.LLM] that serves the responses recorded in a
+// fixture.
+//
+// A request is answered with the responses of a recorded interaction with
+// an identical request, ignoring the function call IDs. Identical requests
+// are answered with the interactions in the order they were recorded.
+type ReplayModel struct {
+	name string
+
+	mu           sync.Mutex
+	interactions map[string][]*Interaction
+}
+
+// NewReplayModel returns a model that replays the fixture stored in the file
+// at path.
+func NewReplayModel(path string) (*ReplayModel, error) {
+	f, err := LoadFixture(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayModelFromFixture(f)
+}
+
+// NewReplayModelFromFixture returns a model that replays f.
+func NewReplayModelFromFixture(f *Fixture) (*ReplayModel, error) {
+	m := &ReplayModel{name: f.Model, interactions: make(map[string][]*Interaction)}
+	for i, interaction := range f.Interactions {
+		if interaction == nil || interaction.Request == nil {
+			return nil, fmt.Errorf("interaction %d has no request", i)
+		}
+		key, err := interaction.Request.key()
+		if err != nil {
+			return nil, fmt.Errorf("invalid request in interaction %d: %w", i, err)
+		}
+		m.interactions[key] = append(m.interactions[key], interaction)
+	}
+	return m, nil
+}
+
+// Name implements model.LLM.
+func (m *ReplayModel) Name() string {
+	return m.name
+}
+
+// GenerateContent implements model.LLM. The responses are replayed as
+// recorded, whatever the value of stream.
+func (m *ReplayModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		interaction, err := m.next(req)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		for _, resp := range interaction.Responses {
+			// Return a copy, as callers may modify the response.
+			r := *resp
+			if !yield(&r, nil) {
+				return
+			}
+		}
+		if interaction.Error != "" {
+			yield(nil, errors.New(interaction.Error))
+		}
+	}
+}
+
+// Remaining returns the number of recorded interactions that haven't been
+// replayed yet.
+func (m *ReplayModel) Remaining() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, interactions := range m.interactions {
+		n += len(interactions)
+	}
+	return n
+}
+
+func (m *ReplayModel) next(req *model.LLMRequest) (*Interaction, error) {
+	r, err := newRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	key, err := r.key()
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	interactions := m.interactions[key]
+	if len(interactions) == 0 {
+		return nil, fmt.Errorf("no recorded interaction matches the request: %s", key)
+	}
+	m.interactions[key] = interactions[1:]
+	return interactions[0], nil
+}
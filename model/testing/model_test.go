@@ -0,0 +1,158 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testing_test
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/model"
+	modeltesting "google.golang.org/adk/model/testing"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+func newAgent(t *testing.T, m model.LLM) agent.Agent {
+	t.Helper()
+	type Args struct{}
+	ping, err := functiontool.New(functiontool.Config{Name: "ping"}, func(tool.Context, Args) (map[string]any, error) {
+		return map[string]any{"result": "pong"}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := llmagent.New(llmagent.Config{
+		Name:        "agent",
+		Model:       m,
+		Instruction: "Ping when asked.",
+		Tools:       []tool.Tool{ping},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func runAgent(t *testing.T, a agent.Agent) []string {
+	t.Helper()
+	events, err := testutil.CollectEvents(testutil.NewTestAgentRunner(t, a).Run(t, "session", "ping please"))
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	return eventSummaries(events)
+}
+
+func eventSummaries(events []*session.Event) []string {
+	var got []string
+	for _, ev := range events {
+		if ev.Content == nil {
+			continue
+		}
+		for _, p := range ev.Content.Parts {
+			switch {
+			case p.Text != "":
+				got = append(got, "text:"+p.Text)
+			case p.FunctionCall != nil:
+				got = append(got, "call:"+p.FunctionCall.Name)
+			case p.FunctionResponse != nil:
+				got = append(got, "response:"+p.FunctionResponse.Name)
+			}
+		}
+	}
+	return got
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	mock := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromFunctionCall("ping", map[string]any{}, genai.RoleModel),
+		genai.NewContentFromText("pong received", genai.RoleModel),
+	}}
+	recorder := modeltesting.NewRecordingModel(mock, path)
+	recorded := runAgent(t, newAgent(t, recorder))
+
+	want := []string{"call:ping", "response:ping", "text:pong received"}
+	if diff := cmp.Diff(want, recorded); diff != "" {
+		t.Fatalf("recorded run mismatch (-want +got):\n%s", diff)
+	}
+	if got := len(recorder.Fixture().Interactions); got != 2 {
+		t.Fatalf("recorded %d interactions, want 2", got)
+	}
+
+	replay, err := modeltesting.NewReplayModel(path)
+	if err != nil {
+		t.Fatalf("NewReplayModel() error = %v", err)
+	}
+	// The replayed run calls the tool with new function call IDs.
+	replayed := runAgent(t, newAgent(t, replay))
+	if diff := cmp.Diff(want, replayed); diff != "" {
+		t.Errorf("replayed run mismatch (-want +got):\n%s", diff)
+	}
+	if got := replay.Remaining(); got != 0 {
+		t.Errorf("Remaining() = %d, want 0", got)
+	}
+}
+
+type failingModel struct{}
+
+func (failingModel) Name() string { return "failing" }
+
+func (failingModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		yield(nil, errors.New("quota exceeded"))
+	}
+}
+
+func TestReplayModel_Errors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	recorder := modeltesting.NewRecordingModel(failingModel{}, path)
+	req := &model.LLMRequest{Contents: genai.Text("hi")}
+	for _, err := range recorder.GenerateContent(t.Context(), req, false) {
+		if err == nil {
+			t.Fatal("GenerateContent() succeeded, want error")
+		}
+	}
+
+	replay, err := modeltesting.NewReplayModel(path)
+	if err != nil {
+		t.Fatalf("NewReplayModel() error = %v", err)
+	}
+	if got, want := replay.Name(), "failing"; got != want {
+		t.Errorf("Name() = %q, want %q", got, want)
+	}
+	for _, err := range replay.GenerateContent(t.Context(), req, false) {
+		if err == nil || err.Error() != "quota exceeded" {
+			t.Errorf("GenerateContent() error = %v, want the recorded error", err)
+		}
+	}
+
+	// The interaction has been consumed.
+	for _, err := range replay.GenerateContent(t.Context(), req, false) {
+		if err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+			t.Errorf("GenerateContent() error = %v, want no recorded interaction", err)
+		}
+	}
+}
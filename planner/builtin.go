@@ -0,0 +1,51 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package planner
+
+import (
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+)
+
+// BuiltInPlanner uses the model's built-in thinking features.
+//
+// The thinking config is applied to every model request. The model must
+// support thinking, otherwise the request fails.
+type BuiltInPlanner struct {
+	// ThinkingConfig is set on the model request config.
+	ThinkingConfig *genai.ThinkingConfig
+}
+
+var _ Planner = (*BuiltInPlanner)(nil)
+
+// BuildPlanningInstruction implements Planner. It sets the thinking config on
+// the request and adds no instruction.
+func (p *BuiltInPlanner) BuildPlanningInstruction(ctx agent.ReadonlyContext, req *model.LLMRequest) (string, error) {
+	if p.ThinkingConfig == nil {
+		return "", nil
+	}
+	if req.Config == nil {
+		req.Config = &genai.GenerateContentConfig{}
+	}
+	req.Config.ThinkingConfig = p.ThinkingConfig
+	return "", nil
+}
+
+// ProcessPlanningResponse implements Planner. The response is left as is.
+func (p *BuiltInPlanner) ProcessPlanningResponse(ctx agent.CallbackContext, parts []*genai.Part) []*genai.Part {
+	return nil
+}
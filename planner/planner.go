@@ -0,0 +1,41 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package planner provides planners that let an LLM agent plan before it
+// acts.
+//
+// A [Planner] is set on llmagent.Config. Before every model call the planner
+// may add instructions or adjust the request config, and after every model
+// response it may rewrite the response parts, e.g. to mark the planning text
+// as thoughts so it is not shown as the final answer.
+package planner
+
+import (
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+)
+
+// Planner guides the agent to plan and to execute the plan step by step.
+type Planner interface {
+	// BuildPlanningInstruction is called before each model call. It may
+	// modify req and returns an instruction that is appended to the system
+	// instruction. An empty string means no instruction is added.
+	BuildPlanningInstruction(ctx agent.ReadonlyContext, req *model.LLMRequest) (string, error)
+	// ProcessPlanningResponse is called with the parts of each model
+	// response. If it returns a non-nil slice, it replaces the response
+	// parts.
+	ProcessPlanningResponse(ctx agent.CallbackContext, parts []*genai.Part) []*genai.Part
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package planner_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/planner"
+)
+
+func TestPlanReActPlanner_ProcessPlanningResponse(t *testing.T) {
+	call := func(name string) *genai.Part {
+		return &genai.Part{FunctionCall: &genai.FunctionCall{Name: name}}
+	}
+
+	testCases := []struct {
+		name  string
+		parts []*genai.Part
+		want  []*genai.Part
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "final answer split",
+			parts: []*genai.Part{
+				{Text: planner.PlanningTag + " 1. answer"},
+				{Text: planner.ReasoningTag + " easy " + planner.FinalAnswerTag + " 42"},
+			},
+			want: []*genai.Part{
+				{Text: planner.PlanningTag + " 1. answer", Thought: true},
+				{Text: planner.ReasoningTag + " easy " + planner.FinalAnswerTag, Thought: true},
+				{Text: " 42"},
+			},
+		},
+		{
+			name: "untagged text is kept",
+			parts: []*genai.Part{
+				{Text: "hello"},
+			},
+			want: []*genai.Part{
+				{Text: "hello"},
+			},
+		},
+		{
+			name: "stops after first function call group",
+			parts: []*genai.Part{
+				{Text: planner.ActionTag + " search"},
+				call(""),
+				call("search"),
+				call("lookup"),
+				{Text: "ignored"},
+				call("ignored"),
+			},
+			want: []*genai.Part{
+				{Text: planner.ActionTag + " search", Thought: true},
+				call("search"),
+				call("lookup"),
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &planner.PlanReActPlanner{}
+			got := p.ProcessPlanningResponse(nil, tc.parts)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("ProcessPlanningResponse() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBuiltInPlanner_BuildPlanningInstruction(t *testing.T) {
+	budget := int32(1024)
+	thinking := &genai.ThinkingConfig{IncludeThoughts: true, ThinkingBudget: &budget}
+	p := &planner.BuiltInPlanner{ThinkingConfig: thinking}
+
+	req := &model.LLMRequest{}
+	got, err := p.BuildPlanningInstruction(nil, req)
+	if err != nil {
+		t.Fatalf("BuildPlanningInstruction() failed: %v", err)
+	}
+	if got != "" {
+		t.Errorf("BuildPlanningInstruction() = %q, want empty", got)
+	}
+	if req.Config == nil || req.Config.ThinkingConfig != thinking {
+		t.Errorf("request thinking config = %v, want %v", req.Config, thinking)
+	}
+}
+
+func TestLLMAgent_PlanReActPlanner(t *testing.T) {
+	model := &testutil.MockModel{
+		Responses: []*genai.Content{
+			genai.NewContentFromText(planner.PlanningTag+" answer directly "+planner.FinalAnswerTag+"done", "model"),
+		},
+	}
+	a, err := llmagent.New(llmagent.Config{
+		Name:    "agent",
+		Model:   model,
+		Planner: &planner.PlanReActPlanner{},
+	})
+	if err != nil {
+		t.Fatalf("llmagent.New() failed: %v", err)
+	}
+
+	events, err := testutil.CollectEvents(testutil.NewTestAgentRunner(t, a).Run(t, "session", "question"))
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	if len(model.Requests) != 1 {
+		t.Fatalf("got %d model requests, want 1", len(model.Requests))
+	}
+	si := model.Requests[0].Config.SystemInstruction
+	if si == nil || len(si.Parts) == 0 || !strings.Contains(si.Parts[0].Text, planner.PlanningTag) {
+		t.Errorf("system instruction = %v, want planning instruction", si)
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	want := []*genai.Part{
+		{Text: planner.PlanningTag + " answer directly " + planner.FinalAnswerTag, Thought: true},
+		{Text: "done"},
+	}
+	if diff := cmp.Diff(want, events[0].Content.Parts); diff != "" {
+		t.Errorf("event parts mismatch (-want +got):\n%s", diff)
+	}
+}
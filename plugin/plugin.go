@@ -0,0 +1,101 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package plugin provides hooks that apply to every agent run by a runner.
+//
+// Plugins are registered on runner.Config and are called for all agents in
+// the agent tree, so cross-cutting concerns such as logging, redaction or
+// metrics do not have to be configured on each agent.
+//
+// Plugin hooks run in registration order before the callbacks configured on
+// the agent. The first hook that returns a non-nil value or an error
+// short-circuits the remaining plugins and the agent callbacks of the same
+// kind.
+package plugin
+
+import (
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+)
+
+// Plugin is a set of hooks called by the runner and the agents it runs.
+//
+// Implementations can embed [Base] and override only the hooks they need.
+type Plugin interface {
+	// Name returns the name of the plugin. Names must be unique within a
+	// runner.
+	Name() string
+
+	// OnUserMessage is called with the user message before it is added to
+	// the session. If it returns non-nil content, that content replaces the
+	// message.
+	OnUserMessage(ctx agent.InvocationContext, msg *genai.Content) (*genai.Content, error)
+	// OnEvent is called for every event yielded by the agents before it is
+	// added to the session. If it returns a non-nil event, that event
+	// replaces the original one.
+	OnEvent(ctx agent.InvocationContext, event *session.Event) (*session.Event, error)
+
+	// OnModelRequest is called before an LLM agent calls its model. If it
+	// returns a non-nil response, the model call is skipped and the response
+	// is used instead.
+	OnModelRequest(ctx agent.CallbackContext, req *model.LLMRequest) (*model.LLMResponse, error)
+	// OnModelResponse is called with every model response or error. If it
+	// returns a non-nil response, that response replaces the model's.
+	OnModelResponse(ctx agent.CallbackContext, resp *model.LLMResponse, respErr error) (*model.LLMResponse, error)
+
+	// OnToolCall is called before a tool runs. If it returns a non-nil
+	// result, the tool is not run and the result is used instead.
+	OnToolCall(ctx tool.Context, t tool.Tool, args map[string]any) (map[string]any, error)
+	// OnToolResult is called after a tool has run. If it returns a non-nil
+	// result, that result replaces the tool's.
+	OnToolResult(ctx tool.Context, t tool.Tool, args, result map[string]any, toolErr error) (map[string]any, error)
+}
+
+// Base implements all Plugin hooks as no-ops. It is meant to be embedded in
+// Plugin implementations.
+type Base struct{}
+
+// OnUserMessage implements Plugin.
+func (Base) OnUserMessage(agent.InvocationContext, *genai.Content) (*genai.Content, error) {
+	return nil, nil
+}
+
+// OnEvent implements Plugin.
+func (Base) OnEvent(agent.InvocationContext, *session.Event) (*session.Event, error) {
+	return nil, nil
+}
+
+// OnModelRequest implements Plugin.
+func (Base) OnModelRequest(agent.CallbackContext, *model.LLMRequest) (*model.LLMResponse, error) {
+	return nil, nil
+}
+
+// OnModelResponse implements Plugin.
+func (Base) OnModelResponse(agent.CallbackContext, *model.LLMResponse, error) (*model.LLMResponse, error) {
+	return nil, nil
+}
+
+// OnToolCall implements Plugin.
+func (Base) OnToolCall(tool.Context, tool.Tool, map[string]any) (map[string]any, error) {
+	return nil, nil
+}
+
+// OnToolResult implements Plugin.
+func (Base) OnToolResult(tool.Context, tool.Tool, map[string]any, map[string]any, error) (map[string]any, error) {
+	return nil, nil
+}
@@ -0,0 +1,178 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/plugin"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+type recorder struct {
+	plugin.Base
+	name  string
+	calls []string
+}
+
+func (r *recorder) Name() string { return r.name }
+
+func (r *recorder) OnUserMessage(ctx agent.InvocationContext, msg *genai.Content) (*genai.Content, error) {
+	r.calls = append(r.calls, "user_message")
+	return nil, nil
+}
+
+func (r *recorder) OnEvent(ctx agent.InvocationContext, event *session.Event) (*session.Event, error) {
+	r.calls = append(r.calls, "event")
+	return nil, nil
+}
+
+func (r *recorder) OnModelRequest(ctx agent.CallbackContext, req *model.LLMRequest) (*model.LLMResponse, error) {
+	r.calls = append(r.calls, "model_request")
+	return nil, nil
+}
+
+func (r *recorder) OnModelResponse(ctx agent.CallbackContext, resp *model.LLMResponse, respErr error) (*model.LLMResponse, error) {
+	r.calls = append(r.calls, "model_response")
+	return nil, nil
+}
+
+func (r *recorder) OnToolCall(ctx tool.Context, t tool.Tool, args map[string]any) (map[string]any, error) {
+	r.calls = append(r.calls, "tool_call")
+	return nil, nil
+}
+
+func (r *recorder) OnToolResult(ctx tool.Context, t tool.Tool, args, result map[string]any, toolErr error) (map[string]any, error) {
+	r.calls = append(r.calls, "tool_result")
+	return nil, nil
+}
+
+// redactor replaces the user message and answers all tool calls itself.
+type redactor struct {
+	plugin.Base
+}
+
+func (redactor) Name() string { return "redactor" }
+
+func (redactor) OnUserMessage(ctx agent.InvocationContext, msg *genai.Content) (*genai.Content, error) {
+	return genai.NewContentFromText("[redacted]", genai.RoleUser), nil
+}
+
+func (redactor) OnToolCall(ctx tool.Context, t tool.Tool, args map[string]any) (map[string]any, error) {
+	return map[string]any{"result": "from plugin"}, nil
+}
+
+func TestPlugins(t *testing.T) {
+	type Args struct{}
+	toolCalled := false
+	echo, err := functiontool.New(functiontool.Config{Name: "echo"}, func(tool.Context, Args) (map[string]any, error) {
+		toolCalled = true
+		return map[string]any{"result": "from tool"}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mockModel := &testutil.MockModel{
+		Responses: []*genai.Content{
+			genai.NewContentFromFunctionCall("echo", map[string]any{}, genai.RoleModel),
+			genai.NewContentFromText("done", genai.RoleModel),
+		},
+	}
+	a, err := llmagent.New(llmagent.Config{
+		Name:  "agent",
+		Model: mockModel,
+		Tools: []tool.Tool{echo},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := &recorder{name: "recorder"}
+	ctx := t.Context()
+	sessionService := session.InMemoryService()
+	r, err := runner.New(runner.Config{
+		AppName:        "app",
+		Agent:          a,
+		SessionService: sessionService,
+		Plugins:        []plugin.Plugin{rec, redactor{}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var responses []map[string]any
+	for ev, err := range r.Run(ctx, "user", "session", genai.NewContentFromText("my password is hunter2", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+		for _, p := range ev.Content.Parts {
+			if p.FunctionResponse != nil {
+				responses = append(responses, p.FunctionResponse.Response)
+			}
+		}
+	}
+
+	wantCalls := []string{
+		"user_message",
+		"model_request", "model_response", "event",
+		"tool_call", "tool_result", "event",
+		"model_request", "model_response", "event",
+	}
+	if diff := cmp.Diff(wantCalls, rec.calls); diff != "" {
+		t.Errorf("plugin calls mismatch (-want +got):\n%s", diff)
+	}
+	if toolCalled {
+		t.Errorf("tool was called, want the plugin result to be used instead")
+	}
+	if diff := cmp.Diff([]map[string]any{{"result": "from plugin"}}, responses); diff != "" {
+		t.Errorf("function responses mismatch (-want +got):\n%s", diff)
+	}
+	if len(mockModel.Requests) == 0 {
+		t.Fatal("model was not called")
+	}
+	if diff := cmp.Diff(genai.NewContentFromText("[redacted]", genai.RoleUser), mockModel.Requests[0].Contents[0]); diff != "" {
+		t.Errorf("user content sent to the model mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunner_DuplicatePluginName(t *testing.T) {
+	a, err := llmagent.New(llmagent.Config{Name: "agent"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = runner.New(runner.Config{
+		AppName:        "app",
+		Agent:          a,
+		SessionService: session.InMemoryService(),
+		Plugins:        []plugin.Plugin{redactor{}, redactor{}},
+	})
+	if err == nil {
+		t.Error("runner.New() succeeded, want error for duplicate plugin names")
+	}
+}
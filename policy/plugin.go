@@ -0,0 +1,178 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/logging"
+	"google.golang.org/adk/plugin"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+)
+
+// MetadataKey is the key of the custom metadata of the function response
+// events holding the audit records of the policy decisions. Its value is a
+// list of records with the function_call_id, tool, decision, rule, reason
+// and confirmed fields.
+const MetadataKey = "tool_policy"
+
+// PluginName is the name of the plugin enforcing the policy.
+const PluginName = "tool_policy"
+
+// ConfirmFunc asks the user to confirm a tool call the policy decided to
+// confirm. It returns whether the user confirmed the call.
+type ConfirmFunc func(ctx tool.Context, req *Request, res *Result) (bool, error)
+
+// Config defines how the runner enforces a policy.
+type Config struct {
+	// Policy evaluates the tool calls.
+	Policy Policy
+	// Confirm asks the user to confirm the calls requiring confirmation.
+	// Optional. If nil, these calls are denied.
+	Confirm ConfirmFunc
+}
+
+// NewPlugin returns the plugin enforcing the policy of cfg. It is added by
+// the runner when runner.Config.ToolPolicy is set.
+func NewPlugin(cfg Config) (plugin.Plugin, error) {
+	if cfg.Policy == nil {
+		return nil, errors.New("policy is required")
+	}
+	return &policyPlugin{cfg: cfg, pending: map[string][]map[string]any{}}, nil
+}
+
+type policyPlugin struct {
+	plugin.Base
+	cfg Config
+
+	mu sync.Mutex
+	// pending holds the audit records of the invocations, until the events
+	// with the tool responses are yielded.
+	pending map[string][]map[string]any
+}
+
+// Name implements plugin.Plugin.
+func (p *policyPlugin) Name() string {
+	return PluginName
+}
+
+// OnToolCall implements plugin.Plugin. It evaluates the policy and returns
+// an error response if the call is not allowed.
+func (p *policyPlugin) OnToolCall(ctx tool.Context, t tool.Tool, args map[string]any) (map[string]any, error) {
+	req := &Request{Agent: ctx.AgentName(), Tool: t.Name(), Args: args, UserID: ctx.UserID()}
+	res, err := p.cfg.Policy.Evaluate(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate tool policy: %w", err)
+	}
+	if res == nil {
+		res = &Result{Decision: Allow}
+	}
+
+	allowed, confirmed := res.Decision == Allow, false
+	reason := res.Reason
+	switch res.Decision {
+	case Allow:
+	case Confirm:
+		if p.cfg.Confirm == nil {
+			reason = "the tool call requires the confirmation of the user"
+			break
+		}
+		confirmed, err = p.cfg.Confirm(ctx, req, res)
+		if err != nil {
+			return nil, fmt.Errorf("failed to confirm tool call: %w", err)
+		}
+		allowed = confirmed
+		if !confirmed {
+			reason = "the user did not confirm the tool call"
+		}
+	default:
+		if reason == "" {
+			reason = "the tool call is not allowed"
+		}
+	}
+	p.audit(ctx, req, res, confirmed)
+
+	if allowed {
+		return nil, nil
+	}
+	return map[string]any{"error": "tool call denied by policy: " + reason}, nil
+}
+
+// audit logs the decision and keeps its record for the event with the tool
+// response.
+func (p *policyPlugin) audit(ctx tool.Context, req *Request, res *Result, confirmed bool) {
+	logging.FromContext(ctx).Info("tool policy decision",
+		"agent", req.Agent, "tool", req.Tool, "user", req.UserID, "function_call_id", ctx.FunctionCallID(),
+		"decision", res.Decision, "rule", res.Rule, "reason", res.Reason, "confirmed", confirmed)
+
+	record := map[string]any{
+		"function_call_id": ctx.FunctionCallID(),
+		"tool":             req.Tool,
+		"decision":         string(res.Decision),
+		"rule":             res.Rule,
+		"reason":           res.Reason,
+		"confirmed":        confirmed,
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pending[ctx.InvocationID()] = append(p.pending[ctx.InvocationID()], record)
+}
+
+// OnEvent implements plugin.Plugin. It adds the audit records of the tool
+// calls to the event with their responses. The event is updated in place so
+// that the other plugins still see it.
+func (p *policyPlugin) OnEvent(ctx agent.InvocationContext, event *session.Event) (*session.Event, error) {
+	if event.Content == nil {
+		return nil, nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pending := p.pending[event.InvocationID]
+	if len(pending) == 0 {
+		return nil, nil
+	}
+	var records []any
+	for _, part := range event.Content.Parts {
+		resp := part.FunctionResponse
+		if resp == nil {
+			continue
+		}
+		for i, r := range pending {
+			if r["function_call_id"] == resp.ID && r["tool"] == resp.Name {
+				records = append(records, r)
+				pending = append(pending[:i], pending[i+1:]...)
+				break
+			}
+		}
+	}
+	if len(pending) == 0 {
+		delete(p.pending, event.InvocationID)
+	} else {
+		p.pending[event.InvocationID] = pending
+	}
+	if len(records) > 0 {
+		event.CustomMetadata = maps.Clone(event.CustomMetadata)
+		if event.CustomMetadata == nil {
+			event.CustomMetadata = map[string]any{}
+		}
+		event.CustomMetadata[MetadataKey] = records
+	}
+	return nil, nil
+}
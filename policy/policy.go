@@ -0,0 +1,216 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package policy decides whether the agents run by a runner may call their
+// tools.
+//
+// A [Policy] is evaluated before each tool call with the agent, the tool, its
+// arguments and the user of the invocation. It allows the call, denies it or
+// requires the confirmation of the user. [Rules] implements a Policy from a
+// list of rules, which can be parsed from YAML or JSON with [ParseRules]:
+//
+//	default: allow
+//	rules:
+//	- name: no-deletes
+//	  tool: "delete_*"
+//	  decision: deny
+//	  reason: deleting data is not allowed
+//	- name: large-refunds
+//	  agent: billing_agent
+//	  tool: refund
+//	  args:
+//	    amount: "[0-9][0-9][0-9]*"
+//	  decision: confirm
+//	- user: "admin-*"
+//	  decision: allow
+//
+// Policies are configured on runner.Config.ToolPolicy. Each decision is
+// logged and recorded in the custom metadata of the event holding the tool
+// response, under the [MetadataKey] key.
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Decision is the outcome of the evaluation of a tool call.
+type Decision string
+
+const (
+	// Allow lets the tool run.
+	Allow Decision = "allow"
+	// Deny prevents the tool from running. The model receives an error
+	// response instead.
+	Deny Decision = "deny"
+	// Confirm lets the tool run only once the user has confirmed the call.
+	Confirm Decision = "confirm"
+)
+
+func (d Decision) validate() error {
+	switch d {
+	case Allow, Deny, Confirm:
+		return nil
+	}
+	return fmt.Errorf("invalid decision %q", d)
+}
+
+// Request describes a tool call to evaluate.
+type Request struct {
+	// Agent is the name of the agent calling the tool.
+	Agent string
+	// Tool is the name of the tool.
+	Tool string
+	// Args are the arguments of the call.
+	Args map[string]any
+	// UserID is the user of the invocation.
+	UserID string
+}
+
+// Result is the decision of a Policy for a tool call.
+type Result struct {
+	Decision Decision
+	// Rule is the name of the rule which made the decision, if any.
+	Rule string
+	// Reason explains the decision. For denied calls, it is passed to the
+	// model.
+	Reason string
+}
+
+// Policy evaluates the tool calls of the agents.
+type Policy interface {
+	// Evaluate returns the decision for req.
+	Evaluate(ctx context.Context, req *Request) (*Result, error)
+}
+
+// Func adapts a function to the Policy interface.
+type Func func(ctx context.Context, req *Request) (*Result, error)
+
+// Evaluate implements Policy.
+func (f Func) Evaluate(ctx context.Context, req *Request) (*Result, error) {
+	return f(ctx, req)
+}
+
+// Rule matches tool calls and decides on them. The Agent, Tool and User
+// fields are patterns with the syntax of path.Match. Empty fields match any
+// value.
+type Rule struct {
+	// Name identifies the rule in the audit records.
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+	// Agent matches the name of the agent calling the tool.
+	Agent string `yaml:"agent,omitempty" json:"agent,omitempty"`
+	// Tool matches the name of the tool.
+	Tool string `yaml:"tool,omitempty" json:"tool,omitempty"`
+	// User matches the user of the invocation.
+	User string `yaml:"user,omitempty" json:"user,omitempty"`
+	// Args maps argument names to patterns matching their values, formatted
+	// with fmt.Sprint. Calls without one of the arguments don't match.
+	Args map[string]string `yaml:"args,omitempty" json:"args,omitempty"`
+	// Decision applied to the matching calls.
+	Decision Decision `yaml:"decision" json:"decision"`
+	// Reason explains the decision.
+	Reason string `yaml:"reason,omitempty" json:"reason,omitempty"`
+}
+
+func (r *Rule) validate() error {
+	if err := r.Decision.validate(); err != nil {
+		return err
+	}
+	patterns := []string{r.Agent, r.Tool, r.User}
+	for _, p := range r.Args {
+		patterns = append(patterns, p)
+	}
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+func (r *Rule) matches(req *Request) bool {
+	if !match(r.Agent, req.Agent) || !match(r.Tool, req.Tool) || !match(r.User, req.UserID) {
+		return false
+	}
+	for name, pattern := range r.Args {
+		v, ok := req.Args[name]
+		if !ok || !match(pattern, fmt.Sprint(v)) {
+			return false
+		}
+	}
+	return true
+}
+
+func match(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(pattern, value)
+	return ok
+}
+
+// Rules is a Policy applying the first of its rules which matches a call.
+type Rules struct {
+	// Rules are evaluated in order.
+	Rules []Rule `yaml:"rules" json:"rules"`
+	// Default is the decision for the calls matching no rule. If empty,
+	// calls are allowed.
+	Default Decision `yaml:"default,omitempty" json:"default,omitempty"`
+}
+
+// ParseRules parses rules in YAML or JSON and validates them.
+func ParseRules(data []byte) (*Rules, error) {
+	var rules Rules
+	if err := yaml.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse rules: %w", err)
+	}
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
+
+// Validate checks the decisions and patterns of the rules.
+func (r *Rules) Validate() error {
+	if r.Default != "" {
+		if err := r.Default.validate(); err != nil {
+			return fmt.Errorf("invalid default: %w", err)
+		}
+	}
+	var errs []error
+	for i := range r.Rules {
+		if err := r.Rules[i].validate(); err != nil {
+			errs = append(errs, fmt.Errorf("invalid rule %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Evaluate implements Policy.
+func (r *Rules) Evaluate(ctx context.Context, req *Request) (*Result, error) {
+	for i := range r.Rules {
+		rule := &r.Rules[i]
+		if rule.matches(req) {
+			return &Result{Decision: rule.Decision, Rule: rule.Name, Reason: rule.Reason}, nil
+		}
+	}
+	if r.Default == "" {
+		return &Result{Decision: Allow}, nil
+	}
+	return &Result{Decision: r.Default}, nil
+}
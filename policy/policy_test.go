@@ -0,0 +1,266 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/policy"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const testRules = `
+default: deny
+rules:
+- name: no-deletes
+  tool: "delete_*"
+  decision: deny
+  reason: deleting data is not allowed
+- name: large-refunds
+  agent: billing
+  tool: refund
+  args:
+    amount: "[0-9][0-9][0-9]*"
+  decision: confirm
+- tool: refund
+  decision: allow
+- user: "admin-*"
+  decision: allow
+`
+
+func TestRules_Evaluate(t *testing.T) {
+	rules, err := policy.ParseRules([]byte(testRules))
+	if err != nil {
+		t.Fatalf("ParseRules() failed: %v", err)
+	}
+	for _, tc := range []struct {
+		name string
+		req  *policy.Request
+		want *policy.Result
+	}{
+		{
+			name: "deny by tool",
+			req:  &policy.Request{Agent: "billing", Tool: "delete_user", UserID: "admin-1"},
+			want: &policy.Result{Decision: policy.Deny, Rule: "no-deletes", Reason: "deleting data is not allowed"},
+		},
+		{
+			name: "confirm by args",
+			req:  &policy.Request{Agent: "billing", Tool: "refund", Args: map[string]any{"amount": 250}},
+			want: &policy.Result{Decision: policy.Confirm, Rule: "large-refunds"},
+		},
+		{
+			name: "small refund",
+			req:  &policy.Request{Agent: "billing", Tool: "refund", Args: map[string]any{"amount": 20}},
+			want: &policy.Result{Decision: policy.Allow},
+		},
+		{
+			name: "missing arg",
+			req:  &policy.Request{Agent: "billing", Tool: "refund"},
+			want: &policy.Result{Decision: policy.Allow},
+		},
+		{
+			name: "other agent",
+			req:  &policy.Request{Agent: "support", Tool: "refund", Args: map[string]any{"amount": 250}},
+			want: &policy.Result{Decision: policy.Allow},
+		},
+		{
+			name: "by user",
+			req:  &policy.Request{Agent: "billing", Tool: "lookup", UserID: "admin-1"},
+			want: &policy.Result{Decision: policy.Allow},
+		},
+		{
+			name: "default",
+			req:  &policy.Request{Agent: "billing", Tool: "lookup", UserID: "alice"},
+			want: &policy.Result{Decision: policy.Deny},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := rules.Evaluate(t.Context(), tc.req)
+			if err != nil {
+				t.Fatalf("Evaluate() failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Evaluate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	empty := &policy.Rules{}
+	got, err := empty.Evaluate(t.Context(), &policy.Request{Tool: "lookup"})
+	if err != nil {
+		t.Fatalf("Evaluate() failed: %v", err)
+	}
+	if got.Decision != policy.Allow {
+		t.Errorf("Evaluate() decision = %q, want %q", got.Decision, policy.Allow)
+	}
+}
+
+func TestParseRules_Invalid(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		rules string
+	}{
+		{name: "syntax", rules: "rules: ["},
+		{name: "decision", rules: "rules:\n- tool: lookup\n  decision: maybe"},
+		{name: "missing decision", rules: "rules:\n- tool: lookup"},
+		{name: "default", rules: "default: never"},
+		{name: "pattern", rules: "rules:\n- tool: \"[\"\n  decision: deny"},
+		{name: "arg pattern", rules: "rules:\n- args: {id: \"[\"}\n  decision: deny"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := policy.ParseRules([]byte(tc.rules)); err == nil {
+				t.Errorf("ParseRules() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestRunner_ToolPolicy(t *testing.T) {
+	type Args struct {
+		Amount int `json:"amount"`
+	}
+	var called []string
+	newTool := func(name string) tool.Tool {
+		ft, err := functiontool.New(functiontool.Config{Name: name}, func(ctx tool.Context, args Args) (map[string]any, error) {
+			called = append(called, name)
+			return map[string]any{"result": "ok"}, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ft
+	}
+	rules, err := policy.ParseRules([]byte(testRules))
+	if err != nil {
+		t.Fatalf("ParseRules() failed: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name          string
+		confirm       policy.ConfirmFunc
+		wantCalled    []string
+		wantResponses map[string]any
+		wantAudit     []any
+	}{
+		{
+			name:       "confirmed",
+			confirm:    func(tool.Context, *policy.Request, *policy.Result) (bool, error) { return true, nil },
+			wantCalled: []string{"refund"},
+			wantResponses: map[string]any{
+				"1": map[string]any{"result": "ok"},
+				"2": map[string]any{"error": "tool call denied by policy: deleting data is not allowed"},
+			},
+			wantAudit: []any{
+				map[string]any{"function_call_id": "1", "tool": "refund", "decision": "confirm", "rule": "large-refunds", "reason": "", "confirmed": true},
+				map[string]any{"function_call_id": "2", "tool": "delete_user", "decision": "deny", "rule": "no-deletes", "reason": "deleting data is not allowed", "confirmed": false},
+			},
+		},
+		{
+			name: "no confirmation",
+			wantResponses: map[string]any{
+				"1": map[string]any{"error": "tool call denied by policy: the tool call requires the confirmation of the user"},
+				"2": map[string]any{"error": "tool call denied by policy: deleting data is not allowed"},
+			},
+			wantAudit: []any{
+				map[string]any{"function_call_id": "1", "tool": "refund", "decision": "confirm", "rule": "large-refunds", "reason": "", "confirmed": false},
+				map[string]any{"function_call_id": "2", "tool": "delete_user", "decision": "deny", "rule": "no-deletes", "reason": "deleting data is not allowed", "confirmed": false},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			called = nil
+			mockModel := &testutil.MockModel{
+				Responses: []*genai.Content{
+					{
+						Role: genai.RoleModel,
+						Parts: []*genai.Part{
+							{FunctionCall: &genai.FunctionCall{ID: "1", Name: "refund", Args: map[string]any{"amount": 500}}},
+							{FunctionCall: &genai.FunctionCall{ID: "2", Name: "delete_user", Args: map[string]any{}}},
+						},
+					},
+					genai.NewContentFromText("done", genai.RoleModel),
+				},
+			}
+			a, err := llmagent.New(llmagent.Config{
+				Name:  "billing",
+				Model: mockModel,
+				Tools: []tool.Tool{newTool("refund"), newTool("delete_user")},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx := t.Context()
+			sessionService := session.InMemoryService()
+			r, err := runner.New(runner.Config{
+				AppName:        "app",
+				Agent:          a,
+				SessionService: sessionService,
+				ToolPolicy:     &policy.Config{Policy: rules, Confirm: tc.confirm},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "session"}); err != nil {
+				t.Fatal(err)
+			}
+
+			responses := map[string]any{}
+			var audit []any
+			for ev, err := range r.Run(ctx, "user", "session", genai.NewContentFromText("refund and delete", genai.RoleUser), agent.RunConfig{}) {
+				if err != nil {
+					t.Fatalf("Run() failed: %v", err)
+				}
+				for _, p := range ev.Content.Parts {
+					if p.FunctionResponse != nil {
+						responses[p.FunctionResponse.ID] = p.FunctionResponse.Response
+					}
+				}
+				if records, ok := ev.CustomMetadata[policy.MetadataKey]; ok {
+					audit = append(audit, records.([]any)...)
+				}
+			}
+
+			if diff := cmp.Diff(tc.wantCalled, called); diff != "" {
+				t.Errorf("called tools mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantResponses, responses); diff != "" {
+				t.Errorf("responses mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantAudit, audit); diff != "" {
+				t.Errorf("audit records mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewPlugin_NoPolicy(t *testing.T) {
+	if _, err := policy.NewPlugin(policy.Config{}); err == nil {
+		t.Errorf("NewPlugin() succeeded, want error")
+	}
+	var p policy.Policy = policy.Func(func(context.Context, *policy.Request) (*policy.Result, error) { return nil, nil })
+	if _, err := policy.NewPlugin(policy.Config{Policy: p}); err != nil {
+		t.Errorf("NewPlugin() failed: %v", err)
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/adk/internal/sessioninternal"
+	"google.golang.org/adk/session"
+)
+
+// EventBatchingConfig defines how the events of the invocations are written
+// to the session service.
+//
+// Without batching, every complete event is appended to the session service
+// before it is yielded, which costs a round trip per event with remote
+// session services. With batching, the events are yielded right away and
+// appended in batches, when MaxEvents events are pending, when an event is
+// appended after FlushInterval elapsed since the oldest pending one, and at
+// the end of the invocation, including when the caller stops consuming the
+// events early.
+//
+// The agents of the invocation see the pending events and their state
+// changes as if they were stored. However, the events yielded but not yet
+// written are lost if the process crashes, and a failed write is reported
+// after the events it contains have been yielded. Readers of the session
+// outside of the invocation only see the written events.
+type EventBatchingConfig struct {
+	// MaxEvents is the number of pending events that triggers a write.
+	// If zero, the events are written only when FlushInterval elapses or the
+	// invocation ends.
+	MaxEvents int
+	// FlushInterval is the longest time an event stays pending while the
+	// invocation produces new events. If zero, the events are written only
+	// when MaxEvents events are pending or the invocation ends.
+	FlushInterval time.Duration
+}
+
+func (c *EventBatchingConfig) validate() error {
+	if c.MaxEvents < 0 {
+		return fmt.Errorf("invalid event batching MaxEvents %d", c.MaxEvents)
+	}
+	if c.FlushInterval < 0 {
+		return fmt.Errorf("invalid event batching FlushInterval %v", c.FlushInterval)
+	}
+	return nil
+}
+
+// eventWriter appends the events of an invocation to the session service,
+// directly or in batches depending on the runner configuration.
+type eventWriter struct {
+	service session.Service
+	stored  session.Session
+	cfg     *EventBatchingConfig
+	now     func() time.Time
+
+	mu      sync.Mutex
+	pending []*session.Event
+	oldest  time.Time
+	// delta holds the session state written since the last flush, from the
+	// pending events and from the agents.
+	delta map[string]any
+}
+
+func (r *Runner) newEventWriter(stored session.Session) *eventWriter {
+	return &eventWriter{
+		service: r.sessionService,
+		stored:  stored,
+		cfg:     r.eventBatching,
+		now:     time.Now,
+		delta:   make(map[string]any),
+	}
+}
+
+// session returns the session seen by the agents of the invocation.
+func (w *eventWriter) session() session.Session {
+	if w.cfg == nil {
+		return w.stored
+	}
+	return &bufferedSession{w: w}
+}
+
+// append stores event, or queues it until the next flush when batching is
+// enabled.
+func (w *eventWriter) append(ctx context.Context, event *session.Event) error {
+	if w.cfg == nil {
+		return w.service.AppendEvent(ctx, w.stored, event)
+	}
+	if event.Partial {
+		return nil
+	}
+
+	w.mu.Lock()
+	now := w.now()
+	if len(w.pending) == 0 {
+		w.oldest = now
+	}
+	w.pending = append(w.pending, event)
+	for k, v := range event.Actions.StateDelta {
+		if !strings.HasPrefix(k, session.KeyPrefixTemp) {
+			w.delta[k] = v
+		}
+	}
+	full := w.cfg.MaxEvents > 0 && len(w.pending) >= w.cfg.MaxEvents
+	stale := w.cfg.FlushInterval > 0 && now.Sub(w.oldest) >= w.cfg.FlushInterval
+	w.mu.Unlock()
+
+	if full || stale {
+		return w.flush(ctx)
+	}
+	return nil
+}
+
+// flush appends the pending events to the session service, in order. The
+// events that could not be appended stay pending.
+func (w *eventWriter) flush(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for len(w.pending) > 0 {
+		if err := w.service.AppendEvent(ctx, w.stored, w.pending[0]); err != nil {
+			return err
+		}
+		w.pending[0] = nil
+		w.pending = w.pending[1:]
+	}
+	w.pending = nil
+	clear(w.delta)
+	return nil
+}
+
+// bufferedSession is the stored session of the invocation with the pending
+// events of the writer appended.
+type bufferedSession struct {
+	w *eventWriter
+}
+
+func (s *bufferedSession) ID() string                { return s.w.stored.ID() }
+func (s *bufferedSession) AppName() string           { return s.w.stored.AppName() }
+func (s *bufferedSession) UserID() string            { return s.w.stored.UserID() }
+func (s *bufferedSession) LastUpdateTime() time.Time { return s.w.stored.LastUpdateTime() }
+func (s *bufferedSession) State() session.State      { return &bufferedState{w: s.w} }
+
+func (s *bufferedSession) Events() session.Events {
+	s.w.mu.Lock()
+	defer s.w.mu.Unlock()
+
+	stored := s.w.stored.Events()
+	events := make(bufferedEvents, 0, stored.Len()+len(s.w.pending))
+	for e := range stored.All() {
+		events = append(events, e)
+	}
+	return append(events, s.w.pending...)
+}
+
+type bufferedEvents []*session.Event
+
+func (e bufferedEvents) All() iter.Seq[*session.Event] {
+	return func(yield func(*session.Event) bool) {
+		for _, event := range e {
+			if !yield(event) {
+				return
+			}
+		}
+	}
+}
+
+func (e bufferedEvents) Len() int {
+	return len(e)
+}
+
+func (e bufferedEvents) At(i int) *session.Event {
+	if i >= 0 && i < len(e) {
+		return e[i]
+	}
+	return nil
+}
+
+// bufferedState is the state of the stored session with the state changes
+// since the last flush applied.
+type bufferedState struct {
+	w *eventWriter
+}
+
+func (s *bufferedState) Get(key string) (any, error) {
+	s.w.mu.Lock()
+	v, ok := s.w.delta[key]
+	s.w.mu.Unlock()
+	if ok {
+		return v, nil
+	}
+	return s.w.stored.State().Get(key)
+}
+
+func (s *bufferedState) Set(key string, value any) error {
+	mutableState, ok := s.w.stored.State().(sessioninternal.MutableState)
+	if !ok {
+		return errors.New("this session state is not mutable")
+	}
+	if err := mutableState.Set(key, value); err != nil {
+		return err
+	}
+	s.w.mu.Lock()
+	s.w.delta[key] = value
+	s.w.mu.Unlock()
+	return nil
+}
+
+func (s *bufferedState) All() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		state := maps.Collect(s.w.stored.State().All())
+		s.w.mu.Lock()
+		maps.Copy(state, s.w.delta)
+		s.w.mu.Unlock()
+		for k, v := range state {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
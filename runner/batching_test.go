@@ -0,0 +1,240 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+// storedEvents returns the number of events of the session in service.
+func storedEvents(t *testing.T, service session.Service, sessionID string) int {
+	t.Helper()
+	resp, err := service.Get(t.Context(), &session.GetRequest{AppName: "testApp", UserID: "testUser", SessionID: sessionID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.Session.Events().Len()
+}
+
+// countingAgent yields n events, each setting the "count" state key, and
+// records the events and state it sees in its session before each of them.
+func countingAgent(n int, seenEvents *[]int, seenCounts *[]any) agent.Agent {
+	return must(agent.New(agent.Config{
+		Name: "test_agent",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				for i := range n {
+					*seenEvents = append(*seenEvents, ctx.Session().Events().Len())
+					count, _ := ctx.Session().State().Get("count")
+					*seenCounts = append(*seenCounts, count)
+
+					event := session.NewEvent(ctx.InvocationID())
+					event.Author = "test_agent"
+					event.LLMResponse = model.LLMResponse{
+						Content: genai.NewContentFromText(fmt.Sprint(i), genai.RoleModel),
+					}
+					event.Actions.StateDelta = map[string]any{"count": i}
+					if !yield(event, nil) {
+						return
+					}
+				}
+			}
+		},
+	}))
+}
+
+func TestRunner_EventBatching(t *testing.T) {
+	ctx := t.Context()
+	sessionService := session.InMemoryService()
+	var seenEvents []int
+	var seenCounts []any
+	r, err := New(Config{
+		AppName:        "testApp",
+		Agent:          countingAgent(5, &seenEvents, &seenCounts),
+		SessionService: sessionService,
+		EventBatching:  &EventBatchingConfig{MaxEvents: 3},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "testApp", UserID: "testUser", SessionID: "s"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var stored []int
+	for _, err := range r.Run(ctx, "testUser", "s", genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("r.Run() error = %v", err)
+		}
+		stored = append(stored, storedEvents(t, sessionService, "s"))
+	}
+
+	// The user message and the events are written by 3, the rest at the end
+	// of the invocation.
+	if diff := cmp.Diff([]int{0, 3, 3, 3, 6}, stored); diff != "" {
+		t.Errorf("stored events mismatch (-want +got):\n%s", diff)
+	}
+	if got := storedEvents(t, sessionService, "s"); got != 6 {
+		t.Errorf("got %d stored events after the invocation, want 6", got)
+	}
+	// The agent sees the pending events and their state changes.
+	if diff := cmp.Diff([]int{1, 2, 3, 4, 5}, seenEvents); diff != "" {
+		t.Errorf("events seen by the agent mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]any{nil, 0, 1, 2, 3}, seenCounts); diff != "" {
+		t.Errorf("state seen by the agent mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunner_EventBatching_EarlyStop(t *testing.T) {
+	ctx := t.Context()
+	sessionService := session.InMemoryService()
+	var seenEvents []int
+	var seenCounts []any
+	r, err := New(Config{
+		AppName:        "testApp",
+		Agent:          countingAgent(5, &seenEvents, &seenCounts),
+		SessionService: sessionService,
+		EventBatching:  &EventBatchingConfig{MaxEvents: 10},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "testApp", UserID: "testUser", SessionID: "s"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for range r.Run(ctx, "testUser", "s", genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{}) {
+		break
+	}
+
+	if got := storedEvents(t, sessionService, "s"); got != 2 {
+		t.Errorf("got %d stored events, want 2", got)
+	}
+}
+
+type failingSessionService struct {
+	session.Service
+}
+
+func (s *failingSessionService) AppendEvent(ctx context.Context, sess session.Session, event *session.Event) error {
+	return errors.New("store unavailable")
+}
+
+func TestRunner_EventBatching_FlushError(t *testing.T) {
+	ctx := t.Context()
+	sessionService := &failingSessionService{Service: session.InMemoryService()}
+	var seenEvents []int
+	var seenCounts []any
+	r, err := New(Config{
+		AppName:        "testApp",
+		Agent:          countingAgent(2, &seenEvents, &seenCounts),
+		SessionService: sessionService,
+		EventBatching:  &EventBatchingConfig{MaxEvents: 10},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "testApp", UserID: "testUser", SessionID: "s"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var events int
+	var gotErr error
+	for event, err := range r.Run(ctx, "testUser", "s", genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			gotErr = err
+			continue
+		}
+		if event != nil {
+			events++
+		}
+	}
+	if events != 2 {
+		t.Errorf("got %d events, want 2", events)
+	}
+	if gotErr == nil {
+		t.Errorf("r.Run() succeeded, want the write error")
+	}
+}
+
+func TestEventWriter_FlushInterval(t *testing.T) {
+	ctx := t.Context()
+	sessionService := session.InMemoryService()
+	resp, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "testApp", UserID: "testUser", SessionID: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &Runner{sessionService: sessionService, eventBatching: &EventBatchingConfig{FlushInterval: time.Second}}
+	w := r.newEventWriter(resp.Session)
+	now := time.Now()
+	w.now = func() time.Time { return now }
+
+	var stored []int
+	for _, elapsed := range []time.Duration{0, 500 * time.Millisecond, time.Second, 1500 * time.Millisecond} {
+		now = now.Add(elapsed)
+		event := session.NewEvent("inv")
+		event.Actions.StateDelta = map[string]any{"key": elapsed.String(), "temp:key": "v"}
+		if err := w.append(ctx, event); err != nil {
+			t.Fatalf("append() error = %v", err)
+		}
+		stored = append(stored, storedEvents(t, sessionService, "s"))
+	}
+
+	// The third event comes 1.5s after the first one, the fourth starts a
+	// new batch.
+	if diff := cmp.Diff([]int{0, 0, 3, 3}, stored); diff != "" {
+		t.Errorf("stored events mismatch (-want +got):\n%s", diff)
+	}
+	if got, err := w.session().State().Get("key"); err != nil || got != "1.5s" {
+		t.Errorf("State().Get(key) = %v, %v, want 1.5s", got, err)
+	}
+	if err := w.flush(ctx); err != nil {
+		t.Fatalf("flush() error = %v", err)
+	}
+	if got := storedEvents(t, sessionService, "s"); got != 4 {
+		t.Errorf("got %d stored events after flush, want 4", got)
+	}
+	if _, err := w.session().State().Get("temp:key"); !errors.Is(err, session.ErrStateKeyNotExist) {
+		t.Errorf("State().Get(temp:key) error = %v, want %v", err, session.ErrStateKeyNotExist)
+	}
+}
+
+func TestNew_EventBatchingValidation(t *testing.T) {
+	for _, cfg := range []*EventBatchingConfig{
+		{MaxEvents: -1},
+		{FlushInterval: -time.Second},
+	} {
+		if _, err := New(Config{
+			Agent:          must(agent.New(agent.Config{Name: "test_agent"})),
+			SessionService: session.InMemoryService(),
+			EventBatching:  cfg,
+		}); err == nil {
+			t.Errorf("New(%+v) succeeded, want error", cfg)
+		}
+	}
+}
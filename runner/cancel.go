@@ -0,0 +1,133 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+// CancelledErrorCode is the error code of the event that ends an invocation
+// cancelled with [Runner.Cancel].
+const CancelledErrorCode = "CANCELLED"
+
+// ErrInvocationNotFound is returned by [Runner.Cancel] when the session has no
+// matching invocation in progress.
+var ErrInvocationNotFound = errors.New("invocation not found")
+
+// errInvocationCancelled is the cause of the context of a cancelled invocation.
+var errInvocationCancelled = errors.New("invocation cancelled")
+
+// invocationRegistry holds the invocations in progress of each session. There
+// is at most one since invocations of a session are serialized.
+type invocationRegistry struct {
+	mu          sync.Mutex
+	invocations map[sessionKey]*inflightInvocation
+}
+
+type inflightInvocation struct {
+	id     string
+	cancel context.CancelCauseFunc
+}
+
+var activeInvocations = &invocationRegistry{invocations: map[sessionKey]*inflightInvocation{}}
+
+// add registers an invocation in progress. It returns the function removing
+// it from the registry.
+func (r *invocationRegistry) add(key sessionKey, invocationID string, cancel context.CancelCauseFunc) func() {
+	inv := &inflightInvocation{id: invocationID, cancel: cancel}
+	r.mu.Lock()
+	r.invocations[key] = inv
+	r.mu.Unlock()
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.invocations[key] == inv {
+			delete(r.invocations, key)
+		}
+	}
+}
+
+// active returns the ID of the invocation in progress in the session, or an
+// empty string if there is none.
+func (r *invocationRegistry) active(key sessionKey) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if inv, ok := r.invocations[key]; ok {
+		return inv.id
+	}
+	return ""
+}
+
+func (r *invocationRegistry) cancel(key sessionKey, invocationID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	inv, ok := r.invocations[key]
+	if !ok || (invocationID != "" && inv.id != invocationID) {
+		if invocationID == "" {
+			return fmt.Errorf("session %q has no invocation in progress: %w", key.sessionID, ErrInvocationNotFound)
+		}
+		return fmt.Errorf("invocation %q of session %q is not in progress: %w", invocationID, key.sessionID, ErrInvocationNotFound)
+	}
+	inv.cancel(errInvocationCancelled)
+	return nil
+}
+
+// Cancel stops an invocation in progress of the session, started by any
+// runner of the app within the process. If invocationID is empty, the
+// invocation in progress is cancelled whatever its ID.
+//
+// The context of the invocation is canceled and the invocation ends with an
+// event with the [CancelledErrorCode] error code. If the session has no
+// matching invocation in progress, Cancel returns an error wrapping
+// [ErrInvocationNotFound].
+func (r *Runner) Cancel(userID, sessionID, invocationID string) error {
+	return activeInvocations.cancel(sessionKey{appName: r.appName, userID: userID, sessionID: sessionID}, invocationID)
+}
+
+// registerInvocation makes the invocation cancellable with [Runner.Cancel].
+// It returns the function to call once the invocation ends.
+func (r *Runner) registerInvocation(ctx agent.InvocationContext, cancel context.CancelCauseFunc) func() {
+	key := sessionKey{appName: r.appName, userID: ctx.Session().UserID(), sessionID: ctx.Session().ID()}
+	unregister := activeInvocations.add(key, ctx.InvocationID(), cancel)
+	return func() {
+		unregister()
+		cancel(nil)
+	}
+}
+
+// isCancelled reports whether the invocation was cancelled with
+// [Runner.Cancel].
+func isCancelled(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), errInvocationCancelled)
+}
+
+// cancelledEvent returns the event ending a cancelled invocation.
+func cancelledEvent(ctx agent.InvocationContext, author string) *session.Event {
+	event := session.NewEvent(ctx.InvocationID())
+	event.Author = author
+	event.Branch = ctx.Branch()
+	event.LLMResponse = model.LLMResponse{
+		ErrorCode:    CancelledErrorCode,
+		ErrorMessage: errInvocationCancelled.Error(),
+	}
+	return event
+}
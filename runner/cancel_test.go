@@ -0,0 +1,89 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"errors"
+	"iter"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+func TestRunner_Cancel(t *testing.T) {
+	ctx := t.Context()
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "testApp", UserID: "testUser", SessionID: "s1"}); err != nil {
+		t.Fatal(err)
+	}
+	testAgent := must(agent.New(agent.Config{
+		Name: "test_agent",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				event := session.NewEvent(ctx.InvocationID())
+				event.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText("working", genai.RoleModel)}
+				if !yield(event, nil) {
+					return
+				}
+				<-ctx.Done()
+				yield(nil, ctx.Err())
+			}
+		},
+	}))
+	r, err := New(Config{AppName: "testApp", Agent: testAgent, SessionService: sessionService})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if err := r.Cancel("testUser", "s1", ""); !errors.Is(err, ErrInvocationNotFound) {
+		t.Errorf("Cancel() without invocation in progress error = %v, want %v", err, ErrInvocationNotFound)
+	}
+
+	var codes []string
+	for event, err := range r.Run(ctx, "testUser", "s1", genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		codes = append(codes, event.ErrorCode)
+		if event.ErrorCode != "" {
+			continue
+		}
+		if err := r.Cancel("testUser", "s1", "other"); !errors.Is(err, ErrInvocationNotFound) {
+			t.Errorf("Cancel() of another invocation error = %v, want %v", err, ErrInvocationNotFound)
+		}
+		if err := r.Cancel("testUser", "s1", event.InvocationID); err != nil {
+			t.Errorf("Cancel() error = %v", err)
+		}
+	}
+	if diff := cmp.Diff([]string{"", CancelledErrorCode}, codes); diff != "" {
+		t.Errorf("event error codes mismatch (-want +got):\n%s", diff)
+	}
+
+	resp, err := sessionService.Get(ctx, &session.GetRequest{AppName: "testApp", UserID: "testUser", SessionID: "s1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Session.Events().Len(); got != 3 {
+		t.Errorf("session has %d events, want 3", got)
+	}
+	if err := r.Cancel("testUser", "s1", ""); !errors.Is(err, ErrInvocationNotFound) {
+		t.Errorf("Cancel() after the invocation ended error = %v, want %v", err, ErrInvocationNotFound)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/session"
+)
+
+// RunResult is the outcome of an invocation run to completion.
+type RunResult struct {
+	// Events are the events of the invocation. Partial events are not
+	// included.
+	Events []*session.Event
+	// FinalText is the text of the final response of the invocation, empty
+	// if it has no text.
+	FinalText string
+	// StateDelta is the state changed by the invocation, merged from the
+	// state deltas of the events.
+	StateDelta map[string]any
+	// ArtifactDelta maps the names of the artifacts saved by the invocation
+	// to their latest version.
+	ArtifactDelta map[string]int64
+}
+
+// RunAndCollect runs the agent for the given user input like [Runner.Run]
+// and waits for the invocation to complete.
+//
+// If the invocation fails, the error is returned along with the result of
+// the events produced before the failure.
+func (r *Runner) RunAndCollect(ctx context.Context, userID, sessionID string, msg *genai.Content, cfg agent.RunConfig) (*RunResult, error) {
+	result := &RunResult{
+		StateDelta:    map[string]any{},
+		ArtifactDelta: map[string]int64{},
+	}
+	for event, err := range r.Run(ctx, userID, sessionID, msg, cfg) {
+		if err != nil {
+			return result, err
+		}
+		result.add(event)
+	}
+	return result, nil
+}
+
+func (res *RunResult) add(event *session.Event) {
+	if event == nil || event.Partial {
+		return
+	}
+	res.Events = append(res.Events, event)
+	maps.Copy(res.StateDelta, event.Actions.StateDelta)
+	for name, version := range event.Actions.ArtifactDelta {
+		res.ArtifactDelta[name] = max(res.ArtifactDelta[name], version)
+	}
+	if event.IsFinalResponse() {
+		if text := responseText(event.Content); text != "" {
+			res.FinalText = text
+		}
+	}
+}
+
+// responseText returns the text of c, thoughts excluded.
+func responseText(c *genai.Content) string {
+	if c == nil {
+		return ""
+	}
+	var sb strings.Builder
+	for _, p := range c.Parts {
+		if p.Text != "" && !p.Thought {
+			sb.WriteString(p.Text)
+		}
+	}
+	return sb.String()
+}
+
+// ChatConfig is used to create a [Chat].
+type ChatConfig struct {
+	// UserID is the user chatting with the agent.
+	UserID string
+	// SessionID is the session to continue. If empty, a new session is
+	// created.
+	SessionID string
+	// RunConfig is used for all the messages of the chat.
+	RunConfig agent.RunConfig
+}
+
+// Chat is a multi-turn conversation with the agent of a runner, in a single
+// session. It is meant for programs embedding an agent, command line tools
+// and tests.
+//
+// A Chat must not be used concurrently.
+type Chat struct {
+	runner    *Runner
+	userID    string
+	sessionID string
+	runConfig agent.RunConfig
+}
+
+// NewChat starts a conversation with the agent of the runner.
+func (r *Runner) NewChat(ctx context.Context, cfg ChatConfig) (*Chat, error) {
+	if cfg.UserID == "" {
+		return nil, fmt.Errorf("user ID is required")
+	}
+	sessionID := cfg.SessionID
+	if sessionID == "" {
+		resp, err := r.sessionService.Create(ctx, &session.CreateRequest{
+			AppName: r.appName,
+			UserID:  cfg.UserID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create a session: %w", err)
+		}
+		sessionID = resp.Session.ID()
+	} else if _, err := r.getSession(ctx, cfg.UserID, sessionID); err != nil {
+		return nil, err
+	}
+	return &Chat{
+		runner:    r,
+		userID:    cfg.UserID,
+		sessionID: sessionID,
+		runConfig: cfg.RunConfig,
+	}, nil
+}
+
+// SessionID returns the ID of the session of the chat.
+func (c *Chat) SessionID() string {
+	return c.sessionID
+}
+
+// Send sends a text message to the agent and waits for its response.
+func (c *Chat) Send(ctx context.Context, text string) (*RunResult, error) {
+	return c.SendContent(ctx, genai.NewContentFromText(text, genai.RoleUser))
+}
+
+// SendContent sends a message to the agent and waits for its response.
+func (c *Chat) SendContent(ctx context.Context, msg *genai.Content) (*RunResult, error) {
+	return c.runner.RunAndCollect(ctx, c.userID, c.sessionID, msg, c.runConfig)
+}
+
+// Session returns the current state of the session of the chat.
+func (c *Chat) Session(ctx context.Context) (session.Session, error) {
+	return c.runner.getSession(ctx, c.userID, c.sessionID)
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+func TestRunner_RunAndCollect(t *testing.T) {
+	ctx := t.Context()
+	type Args struct{}
+	saveReport, err := functiontool.New(functiontool.Config{Name: "save_report"}, func(tctx tool.Context, _ Args) (map[string]any, error) {
+		if _, err := tctx.Artifacts().Save(tctx, "report.txt", genai.NewPartFromText("report")); err != nil {
+			return nil, err
+		}
+		return map[string]any{"status": "saved"}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	llm := &scriptedModel{responses: []*genai.Content{
+		genai.NewContentFromFunctionCall("save_report", map[string]any{}, genai.RoleModel),
+		{Role: genai.RoleModel, Parts: []*genai.Part{{Text: "thinking", Thought: true}, {Text: "The report is saved."}}},
+	}}
+	a := must(llmagent.New(llmagent.Config{
+		Name:      "root",
+		Model:     llm,
+		Tools:     []tool.Tool{saveReport},
+		OutputKey: "answer",
+	}))
+	sessionService := session.InMemoryService()
+	r, err := New(Config{AppName: "app", Agent: a, SessionService: sessionService, ArtifactService: artifact.InMemoryService()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := r.RunAndCollect(ctx, "user", "session", genai.NewContentFromText("save the report", genai.RoleUser), agent.RunConfig{})
+	if err != nil {
+		t.Fatalf("RunAndCollect() error = %v", err)
+	}
+
+	if got.FinalText != "The report is saved." {
+		t.Errorf("FinalText = %q, want %q", got.FinalText, "The report is saved.")
+	}
+	if len(got.Events) != 3 {
+		t.Errorf("got %d events, want 3", len(got.Events))
+	}
+	if diff := cmp.Diff(map[string]any{"answer": "The report is saved."}, got.StateDelta); diff != "" {
+		t.Errorf("StateDelta mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]int64{"report.txt": 1}, got.ArtifactDelta); diff != "" {
+		t.Errorf("ArtifactDelta mismatch (-want +got):\n%s", diff)
+	}
+
+	// The run fails as the model has no more responses.
+	got, err = r.RunAndCollect(ctx, "user", "session", genai.NewContentFromText("again", genai.RoleUser), agent.RunConfig{})
+	if err == nil {
+		t.Errorf("RunAndCollect() succeeded, want error")
+	}
+	if got == nil {
+		t.Errorf("RunAndCollect() returned no result with the error")
+	}
+}
+
+func TestChat(t *testing.T) {
+	ctx := t.Context()
+	llm := &scriptedModel{responses: []*genai.Content{
+		genai.NewContentFromText("Hello Ann.", genai.RoleModel),
+		genai.NewContentFromText("Your name is Ann.", genai.RoleModel),
+		genai.NewContentFromText("Still Ann.", genai.RoleModel),
+	}}
+	a := must(llmagent.New(llmagent.Config{Name: "root", Model: llm}))
+	r, err := New(Config{AppName: "app", Agent: a, SessionService: session.InMemoryService()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.NewChat(ctx, ChatConfig{}); err == nil {
+		t.Errorf("NewChat() without user succeeded, want error")
+	}
+	if _, err := r.NewChat(ctx, ChatConfig{UserID: "user", SessionID: "missing"}); err == nil {
+		t.Errorf("NewChat() with unknown session succeeded, want error")
+	}
+
+	chat, err := r.NewChat(ctx, ChatConfig{UserID: "user"})
+	if err != nil {
+		t.Fatalf("NewChat() error = %v", err)
+	}
+	var answers []string
+	for _, msg := range []string{"I'm Ann.", "What's my name?"} {
+		res, err := chat.Send(ctx, msg)
+		if err != nil {
+			t.Fatalf("Send(%q) error = %v", msg, err)
+		}
+		answers = append(answers, res.FinalText)
+	}
+	if diff := cmp.Diff([]string{"Hello Ann.", "Your name is Ann."}, answers); diff != "" {
+		t.Errorf("answers mismatch (-want +got):\n%s", diff)
+	}
+	// The second request has the whole conversation.
+	if got := len(llm.requests[1].Contents); got != 3 {
+		t.Errorf("second request has %d contents, want 3", got)
+	}
+
+	s, err := chat.Session(ctx)
+	if err != nil {
+		t.Fatalf("Session() error = %v", err)
+	}
+	if got := s.Events().Len(); got != 4 {
+		t.Errorf("session has %d events, want 4", got)
+	}
+
+	// The conversation can be continued in another chat.
+	resumed, err := r.NewChat(ctx, ChatConfig{UserID: "user", SessionID: chat.SessionID()})
+	if err != nil {
+		t.Fatalf("NewChat() error = %v", err)
+	}
+	if _, err := resumed.Send(ctx, "And now?"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if got := len(llm.requests[2].Contents); got != 5 {
+		t.Errorf("resumed request has %d contents, want 5", got)
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+// DefaultCompactionInstruction is used to summarize the events when
+// CompactionConfig.Instruction is empty.
+const DefaultCompactionInstruction = "Summarize the conversation below between a user and AI agents. " +
+	"Keep the facts, decisions, open questions and tool results needed to continue the conversation. " +
+	"Answer with the summary only."
+
+// CompactionConfig configures the summarization of the older events of the
+// sessions. When a session exceeds one of the thresholds at the end of an
+// invocation, the events not yet summarized, except the most recent ones,
+// are summarized by the model into a compaction event, see
+// [session.EventCompaction]. The events stay in the session but the summary
+// replaces them in the requests to the agents' models.
+type CompactionConfig struct {
+	// Model summarizes the events. Required.
+	Model model.LLM
+	// MaxEvents triggers a compaction when the session has more events not
+	// summarized yet. Zero disables the threshold.
+	MaxEvents int
+	// MaxTokens triggers a compaction when the last model call of the
+	// session used more tokens. Zero disables the threshold.
+	MaxTokens int32
+	// KeepRecentEvents is the number of the most recent events which are
+	// not summarized.
+	KeepRecentEvents int
+	// Instruction tells the model how to summarize the events. Optional,
+	// defaults to DefaultCompactionInstruction.
+	Instruction string
+}
+
+func (c *CompactionConfig) validate() error {
+	if c.Model == nil {
+		return fmt.Errorf("compaction model is required")
+	}
+	if c.MaxEvents <= 0 && c.MaxTokens <= 0 {
+		return fmt.Errorf("compaction requires MaxEvents or MaxTokens")
+	}
+	if c.KeepRecentEvents < 0 {
+		return fmt.Errorf("invalid KeepRecentEvents %d", c.KeepRecentEvents)
+	}
+	return nil
+}
+
+// compact summarizes the older events of the session if it exceeds the
+// compaction thresholds.
+func (r *Runner) compact(ctx agent.InvocationContext, userID, sessionID string) error {
+	storedSession, err := r.getSession(ctx, userID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	var last *session.EventCompaction
+	for event := range storedSession.Events().All() {
+		if c := event.Actions.Compaction; c != nil && (last == nil || c.EndTime.After(last.EndTime)) {
+			last = c
+		}
+	}
+	var pending []*session.Event
+	for event := range storedSession.Events().All() {
+		if event.Actions.Compaction != nil || (last != nil && !event.Timestamp.After(last.EndTime)) {
+			continue
+		}
+		pending = append(pending, event)
+	}
+	if !r.compaction.exceeded(pending) {
+		return nil
+	}
+
+	n := len(pending) - r.compaction.KeepRecentEvents
+	// The function responses are kept with their calls.
+	for n > 0 && n < len(pending) && hasFunctionResponse(pending[n]) {
+		n--
+	}
+	if n <= 0 {
+		return nil
+	}
+	events := pending[:n]
+
+	summary, err := r.summarize(ctx, last, events)
+	if err != nil {
+		return err
+	}
+	compaction := &session.EventCompaction{
+		StartTime: events[0].Timestamp,
+		EndTime:   events[n-1].Timestamp,
+		Content:   genai.NewContentFromText(summary, genai.RoleModel),
+	}
+	if last != nil {
+		// The summary includes the previous one.
+		compaction.StartTime = last.StartTime
+	}
+	event := session.NewEvent(ctx.InvocationID())
+	event.Author = "user"
+	event.Actions.Compaction = compaction
+	if err := r.sessionService.AppendEvent(ctx, storedSession, event); err != nil {
+		return fmt.Errorf("failed to add compaction event to session: %w", err)
+	}
+	return nil
+}
+
+func hasFunctionResponse(event *session.Event) bool {
+	if event.Content == nil {
+		return false
+	}
+	return slices.ContainsFunc(event.Content.Parts, func(p *genai.Part) bool { return p.FunctionResponse != nil })
+}
+
+// exceeded reports whether the events not summarized yet exceed the
+// thresholds.
+func (c *CompactionConfig) exceeded(pending []*session.Event) bool {
+	if c.MaxEvents > 0 && len(pending) > c.MaxEvents {
+		return true
+	}
+	if c.MaxTokens <= 0 {
+		return false
+	}
+	for i := len(pending) - 1; i >= 0; i-- {
+		if usage := pending[i].UsageMetadata; usage != nil {
+			return usage.TotalTokenCount > c.MaxTokens
+		}
+	}
+	return false
+}
+
+// summarize asks the compaction model for a summary of the events, which
+// extends the previous summary, if any.
+func (r *Runner) summarize(ctx agent.InvocationContext, previous *session.EventCompaction, events []*session.Event) (string, error) {
+	var sb strings.Builder
+	sb.WriteString(r.compaction.Instruction)
+	if r.compaction.Instruction == "" {
+		sb.WriteString(DefaultCompactionInstruction)
+	}
+	sb.WriteString("\n\n")
+	if previous != nil {
+		sb.WriteString("Summary of the earlier conversation:\n")
+		writeContentText(&sb, previous.Content)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("Conversation:\n")
+	for _, event := range events {
+		if event.Content == nil {
+			continue
+		}
+		fmt.Fprintf(&sb, "[%s]: ", event.Author)
+		writeContentText(&sb, event.Content)
+		sb.WriteString("\n")
+	}
+
+	req := &model.LLMRequest{
+		Model:    r.compaction.Model.Name(),
+		Contents: []*genai.Content{genai.NewContentFromText(sb.String(), genai.RoleUser)},
+	}
+	var summary string
+	for resp, err := range r.compaction.Model.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return "", fmt.Errorf("failed to summarize events: %w", err)
+		}
+		if resp.Partial || resp.Content == nil {
+			continue
+		}
+		var text strings.Builder
+		writeContentText(&text, resp.Content)
+		summary = strings.TrimSpace(text.String())
+	}
+	if summary == "" {
+		return "", fmt.Errorf("failed to summarize events: empty response")
+	}
+	return summary, nil
+}
+
+// writeContentText writes the text of the content, including the function
+// calls and responses, for a summarization prompt.
+func writeContentText(sb *strings.Builder, content *genai.Content) {
+	for _, part := range content.Parts {
+		switch {
+		case part.Thought:
+		case part.Text != "":
+			sb.WriteString(part.Text)
+		case part.FunctionCall != nil:
+			args, _ := json.Marshal(part.FunctionCall.Args)
+			fmt.Fprintf(sb, "called %s(%s)", part.FunctionCall.Name, args)
+		case part.FunctionResponse != nil:
+			resp, _ := json.Marshal(part.FunctionResponse.Response)
+			fmt.Fprintf(sb, "%s returned %s", part.FunctionResponse.Name, resp)
+		case part.InlineData != nil || part.FileData != nil:
+			sb.WriteString("(file)")
+		default:
+			continue
+		}
+		sb.WriteString(" ")
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/session"
+)
+
+func TestRunner_Compaction(t *testing.T) {
+	ctx := t.Context()
+	llm := &scriptedModel{responses: []*genai.Content{
+		genai.NewContentFromText("answer 1", genai.RoleModel),
+		genai.NewContentFromText("answer 2", genai.RoleModel),
+		genai.NewContentFromText("answer 3", genai.RoleModel),
+	}}
+	summarizer := &scriptedModel{responses: []*genai.Content{
+		genai.NewContentFromText("the user asked two questions", genai.RoleModel),
+	}}
+	a, err := llmagent.New(llmagent.Config{Name: "test_agent", Model: llm})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService := session.InMemoryService()
+	r, err := New(Config{
+		AppName:        "testApp",
+		Agent:          a,
+		SessionService: sessionService,
+		Compaction:     &CompactionConfig{Model: summarizer, MaxEvents: 3, KeepRecentEvents: 1},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "testApp", UserID: "testUser", SessionID: "s"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, question := range []string{"question 1", "question 2", "question 3"} {
+		for _, err := range r.Run(ctx, "testUser", "s", genai.NewContentFromText(question, genai.RoleUser), agent.RunConfig{}) {
+			if err != nil {
+				t.Fatalf("r.Run() error = %v", err)
+			}
+		}
+	}
+
+	// The compaction happens after the second invocation, with 4 events.
+	if len(summarizer.requests) != 1 {
+		t.Fatalf("summarizer got %d requests, want 1", len(summarizer.requests))
+	}
+	prompt := summarizer.requests[0].Contents[0].Parts[0].Text
+	for _, want := range []string{"[user]: question 1", "[test_agent]: answer 1", "[user]: question 2"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("summarization prompt %q does not contain %q", prompt, want)
+		}
+	}
+	if strings.Contains(prompt, "answer 2") {
+		t.Errorf("summarization prompt %q contains the most recent event", prompt)
+	}
+
+	var got []string
+	for _, content := range llm.requests[2].Contents {
+		got = append(got, content.Parts[0].Text)
+	}
+	want := []string{"the user asked two questions", "answer 2", "question 3"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("model request contents mismatch (-want +got):\n%s", diff)
+	}
+
+	resp, err := sessionService.Get(ctx, &session.GetRequest{AppName: "testApp", UserID: "testUser", SessionID: "s"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The summarized events are kept in the session.
+	if got := resp.Session.Events().Len(); got != 7 {
+		t.Errorf("session has %d events, want 7", got)
+	}
+}
+
+func TestNew_InvalidCompaction(t *testing.T) {
+	a := must(agent.New(agent.Config{Name: "test_agent"}))
+	for _, cfg := range []*CompactionConfig{
+		{MaxEvents: 10},
+		{Model: &scriptedModel{}},
+		{Model: &scriptedModel{}, MaxEvents: 10, KeepRecentEvents: -1},
+	} {
+		if _, err := New(Config{AppName: "testApp", Agent: a, SessionService: session.InMemoryService(), Compaction: cfg}); err == nil {
+			t.Errorf("New() with compaction %+v succeeded, want error", cfg)
+		}
+	}
+}
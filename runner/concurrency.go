@@ -0,0 +1,110 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrInvocationInProgress is returned when a session is invoked while another
+// invocation of the same session is in progress and the runner is configured
+// with [RejectConcurrentInvocations].
+var ErrInvocationInProgress = errors.New("invocation already in progress")
+
+// SessionConcurrency defines how a runner handles an invocation of a session
+// which already has an invocation in progress.
+//
+// Invocations are serialized within the process, across all runners sharing
+// the app name, so that the events of two invocations never interleave.
+// Runners in different processes are not coordinated.
+type SessionConcurrency int
+
+const (
+	// QueueConcurrentInvocations makes the invocation wait until the
+	// invocation in progress ends or the context is canceled. This is the
+	// default.
+	QueueConcurrentInvocations SessionConcurrency = iota
+	// RejectConcurrentInvocations fails the invocation with
+	// [ErrInvocationInProgress].
+	RejectConcurrentInvocations
+)
+
+type sessionKey struct {
+	appName, userID, sessionID string
+}
+
+// sessionLocks holds a lock for each session with an invocation in progress.
+type sessionLocks struct {
+	mu    sync.Mutex
+	locks map[sessionKey]*sessionLock
+}
+
+type sessionLock struct {
+	// sem has a capacity of one, it is full while an invocation is in progress.
+	sem chan struct{}
+	// refs is the number of the invocations holding or waiting for the lock.
+	refs int
+}
+
+var activeSessions = &sessionLocks{locks: map[sessionKey]*sessionLock{}}
+
+// acquire locks the session for an invocation. It returns the function
+// releasing the lock.
+func (l *sessionLocks) acquire(ctx context.Context, key sessionKey, mode SessionConcurrency) (func(), error) {
+	l.mu.Lock()
+	lock, ok := l.locks[key]
+	if !ok {
+		lock = &sessionLock{sem: make(chan struct{}, 1)}
+		l.locks[key] = lock
+	}
+	lock.refs++
+	l.mu.Unlock()
+
+	if mode == RejectConcurrentInvocations {
+		select {
+		case lock.sem <- struct{}{}:
+		default:
+			l.unref(key, lock)
+			return nil, fmt.Errorf("session %q: %w", key.sessionID, ErrInvocationInProgress)
+		}
+	} else {
+		select {
+		case lock.sem <- struct{}{}:
+		case <-ctx.Done():
+			l.unref(key, lock)
+			return nil, fmt.Errorf("waiting for the invocation in progress in session %q: %w", key.sessionID, context.Cause(ctx))
+		}
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			<-lock.sem
+			l.unref(key, lock)
+		})
+	}, nil
+}
+
+func (l *sessionLocks) unref(key sessionKey, lock *sessionLock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lock.refs--
+	if lock.refs == 0 {
+		delete(l.locks, key)
+	}
+}
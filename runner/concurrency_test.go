@@ -0,0 +1,126 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+// newBlockingRunner returns a runner whose agent signals started and waits
+// for release before producing its event.
+func newBlockingRunner(t *testing.T, sessionService session.Service, mode SessionConcurrency, started chan<- struct{}, release <-chan struct{}) *Runner {
+	t.Helper()
+	testAgent := must(agent.New(agent.Config{
+		Name: "test_agent",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				started <- struct{}{}
+				<-release
+				event := session.NewEvent(ctx.InvocationID())
+				event.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText("done", genai.RoleModel)}
+				yield(event, nil)
+			}
+		},
+	}))
+	r, err := New(Config{AppName: "testApp", Agent: testAgent, SessionService: sessionService, SessionConcurrency: mode})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return r
+}
+
+func runCollectingError(ctx context.Context, r *Runner, sessionID string) error {
+	for _, err := range r.Run(ctx, "testUser", sessionID, genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestRunner_SessionConcurrency(t *testing.T) {
+	ctx := t.Context()
+	sessionService := session.InMemoryService()
+	for _, id := range []string{"s1", "s2"} {
+		if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "testApp", UserID: "testUser", SessionID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	started, release := make(chan struct{}, 10), make(chan struct{})
+	queueRunner := newBlockingRunner(t, sessionService, QueueConcurrentInvocations, started, release)
+	rejectRunner := newBlockingRunner(t, sessionService, RejectConcurrentInvocations, started, release)
+
+	first := make(chan error)
+	go func() { first <- runCollectingError(ctx, queueRunner, "s1") }()
+	<-started
+
+	if err := runCollectingError(ctx, rejectRunner, "s1"); !errors.Is(err, ErrInvocationInProgress) {
+		t.Errorf("concurrent run with reject mode error = %v, want %v", err, ErrInvocationInProgress)
+	}
+
+	canceledCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := runCollectingError(canceledCtx, queueRunner, "s1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("queued run with expired context error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	// Other sessions are not affected.
+	other := make(chan error)
+	go func() { other <- runCollectingError(ctx, rejectRunner, "s2") }()
+	<-started
+
+	queued := make(chan error)
+	go func() { queued <- runCollectingError(ctx, queueRunner, "s1") }()
+	select {
+	case <-started:
+		t.Fatal("queued invocation started while another invocation is in progress")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	for name, ch := range map[string]chan error{"first": first, "other": other, "queued": queued} {
+		if err := <-ch; err != nil {
+			t.Errorf("%s run error = %v", name, err)
+		}
+	}
+
+	resp, err := sessionService.Get(ctx, &session.GetRequest{AppName: "testApp", UserID: "testUser", SessionID: "s1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The user message and the agent response of the two invocations, not interleaved.
+	var authors []string
+	for event := range resp.Session.Events().All() {
+		authors = append(authors, event.Author)
+	}
+	want := []string{"user", "test_agent", "user", "test_agent"}
+	if diff := cmp.Diff(want, authors); diff != "" {
+		t.Errorf("session events authors mismatch (-want +got):\n%s", diff)
+	}
+	if len(activeSessions.locks) != 0 {
+		t.Errorf("activeSessions has %d locks after all invocations ended, want 0", len(activeSessions.locks))
+	}
+}
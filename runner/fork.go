@@ -0,0 +1,127 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/internal/sessionutils"
+	"google.golang.org/adk/session"
+)
+
+// ErrEventNotFound is returned by [Runner.Fork] when the session has no event
+// with the given ID.
+var ErrEventNotFound = errors.New("event not found")
+
+// Fork creates a new session of the user with a copy of the events of the
+// given session, up to and including the event with the given ID. It allows
+// to re-run the conversation from an earlier point, e.g. with an edited user
+// message, while the original session is kept.
+//
+// The session state of the new session is rebuilt from the copied events.
+// The app and user state are shared, so they are not changed by the fork.
+// If the runner has an artifact service, the versions of the session
+// artifacts saved by the copied events are copied as well.
+func (r *Runner) Fork(ctx context.Context, userID, sessionID, eventID string) (session.Session, error) {
+	source, err := r.getSession(ctx, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*session.Event
+	found := false
+	for event := range source.Events().All() {
+		events = append(events, event)
+		if event.ID == eventID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("session %q: %w: %q", sessionID, ErrEventNotFound, eventID)
+	}
+
+	resp, err := r.sessionService.Create(ctx, &session.CreateRequest{
+		AppName: r.appName,
+		UserID:  userID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a session: %w", err)
+	}
+	fork := resp.Session
+
+	artifactVersions := map[string]int64{}
+	for _, event := range events {
+		forked := *event
+		_, _, sessionDelta := sessionutils.ExtractStateDeltas(event.Actions.StateDelta)
+		forked.Actions.StateDelta = sessionDelta
+		forked.Actions.ArtifactDelta = maps.Clone(event.Actions.ArtifactDelta)
+		if err := r.sessionService.AppendEvent(ctx, fork, &forked); err != nil {
+			return nil, fmt.Errorf("failed to add event to session: %w", err)
+		}
+		for name, version := range event.Actions.ArtifactDelta {
+			artifactVersions[name] = max(artifactVersions[name], version)
+		}
+	}
+
+	if r.artifactService != nil {
+		for name, version := range artifactVersions {
+			if err := r.copyArtifact(ctx, source, fork, name, version); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return fork, nil
+}
+
+// copyArtifact copies the versions of a session artifact, up to maxVersion,
+// from the source session to the fork.
+func (r *Runner) copyArtifact(ctx context.Context, source, fork session.Session, name string, maxVersion int64) error {
+	if strings.HasPrefix(name, session.KeyPrefixUser) {
+		// User artifacts are shared by the sessions of the user.
+		return nil
+	}
+	resp, err := r.artifactService.Versions(ctx, &artifact.VersionsRequest{
+		AppName: r.appName, UserID: source.UserID(), SessionID: source.ID(), FileName: name,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list the versions of artifact %q: %w", name, err)
+	}
+	versions := slices.Sorted(slices.Values(resp.Versions))
+	for _, version := range versions {
+		if version > maxVersion {
+			break
+		}
+		loaded, err := r.artifactService.Load(ctx, &artifact.LoadRequest{
+			AppName: r.appName, UserID: source.UserID(), SessionID: source.ID(), FileName: name, Version: version,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to load artifact %q version %d: %w", name, version, err)
+		}
+		_, err = r.artifactService.Save(ctx, &artifact.SaveRequest{
+			AppName: r.appName, UserID: fork.UserID(), SessionID: fork.ID(), FileName: name, Part: loaded.Part, Version: version,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to save artifact %q: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+func TestRunner_Fork(t *testing.T) {
+	ctx := t.Context()
+	sessionService := session.InMemoryService()
+	artifactService := artifact.InMemoryService()
+	testAgent := must(agent.New(agent.Config{Name: "test_agent"}))
+	r, err := New(Config{AppName: "testApp", Agent: testAgent, SessionService: sessionService, ArtifactService: artifactService})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	resp, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "testApp", UserID: "testUser", SessionID: "source"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := resp.Session
+	for i, text := range []string{"v1", "v2", "v3"} {
+		if _, err := artifactService.Save(ctx, &artifact.SaveRequest{
+			AppName: "testApp", UserID: "testUser", SessionID: "source", FileName: "report.txt", Part: genai.NewPartFromText(text),
+		}); err != nil {
+			t.Fatal(err)
+		}
+		event := session.NewEvent("invocation")
+		event.ID = text
+		event.Author = "test_agent"
+		event.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText(text, genai.RoleModel)}
+		event.Actions.StateDelta = map[string]any{"step": i + 1, "user:last": text}
+		event.Actions.ArtifactDelta = map[string]int64{"report.txt": int64(i + 1)}
+		if err := sessionService.AppendEvent(ctx, source, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fork, err := r.Fork(ctx, "testUser", "source", "v2")
+	if err != nil {
+		t.Fatalf("Fork() error = %v", err)
+	}
+	if fork.ID() == "source" {
+		t.Fatalf("Fork() returned the source session")
+	}
+
+	got, err := sessionService.Get(ctx, &session.GetRequest{AppName: "testApp", UserID: "testUser", SessionID: fork.ID()})
+	if err != nil {
+		t.Fatalf("sessionService.Get() error = %v", err)
+	}
+	var gotEvents []string
+	for event := range got.Session.Events().All() {
+		gotEvents = append(gotEvents, event.ID)
+	}
+	if diff := cmp.Diff([]string{"v1", "v2"}, gotEvents); diff != "" {
+		t.Errorf("forked session events mismatch (-want +got):\n%s", diff)
+	}
+	gotState := map[string]any{}
+	for k, v := range got.Session.State().All() {
+		gotState[k] = v
+	}
+	// The user state is shared, so it keeps the value set by the source session.
+	wantState := map[string]any{"step": 2, "user:last": "v3"}
+	if diff := cmp.Diff(wantState, gotState); diff != "" {
+		t.Errorf("forked session state mismatch (-want +got):\n%s", diff)
+	}
+
+	versions, err := artifactService.Versions(ctx, &artifact.VersionsRequest{AppName: "testApp", UserID: "testUser", SessionID: fork.ID(), FileName: "report.txt"})
+	if err != nil {
+		t.Fatalf("artifactService.Versions() error = %v", err)
+	}
+	if len(versions.Versions) != 2 {
+		t.Errorf("forked session has %d artifact versions, want 2", len(versions.Versions))
+	}
+	latest, err := artifactService.Load(ctx, &artifact.LoadRequest{AppName: "testApp", UserID: "testUser", SessionID: fork.ID(), FileName: "report.txt"})
+	if err != nil {
+		t.Fatalf("artifactService.Load() error = %v", err)
+	}
+	if diff := cmp.Diff(genai.NewPartFromText("v2"), latest.Part); diff != "" {
+		t.Errorf("forked artifact mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := r.Fork(ctx, "testUser", "source", "unknown"); !errors.Is(err, ErrEventNotFound) {
+		t.Errorf("Fork() with unknown event error = %v, want %v", err, ErrEventNotFound)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/adk/session"
+)
+
+// InvocationStatus is the state of an invocation.
+type InvocationStatus string
+
+const (
+	// InvocationRunning is the status of an invocation in progress.
+	InvocationRunning InvocationStatus = "running"
+	// InvocationCompleted is the status of an invocation that ended normally.
+	InvocationCompleted InvocationStatus = "completed"
+	// InvocationError is the status of an invocation whose last event has an
+	// error code.
+	InvocationError InvocationStatus = "error"
+	// InvocationCancelled is the status of an invocation cancelled with
+	// [Runner.Cancel].
+	InvocationCancelled InvocationStatus = "cancelled"
+)
+
+// Invocation summarizes an invocation of a session.
+type Invocation struct {
+	ID string
+	// RootAgent is the name of the agent that handled the user message of
+	// the invocation. It is empty if no agent produced an event.
+	RootAgent string
+	// StartTime is the time of the first event of the invocation.
+	StartTime time.Time
+	// EndTime is the time of the last event of the invocation. It is zero
+	// while the invocation is running.
+	EndTime time.Time
+	Status  InvocationStatus
+}
+
+// Invocations returns the invocations of the session, in the order they
+// started. They are aggregated from the events stored in the session.
+//
+// An invocation is reported as running while it is in progress in a runner
+// of the app within the process. Invocations in progress in other processes
+// are reported with the status of their last stored event.
+func (r *Runner) Invocations(ctx context.Context, userID, sessionID string) ([]*Invocation, error) {
+	storedSession, err := r.getSession(ctx, userID, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	active := activeInvocations.active(sessionKey{appName: r.appName, userID: userID, sessionID: sessionID})
+	return summarizeInvocations(storedSession.Events(), active), nil
+}
+
+// summarizeInvocations aggregates events by invocation. activeID is the ID of
+// the invocation in progress, if any.
+func summarizeInvocations(events session.Events, activeID string) []*Invocation {
+	var invocations []*Invocation
+	byID := map[string]*Invocation{}
+	lastErrorCode := map[string]string{}
+	for event := range events.All() {
+		if event.InvocationID == "" {
+			continue
+		}
+		inv, ok := byID[event.InvocationID]
+		if !ok {
+			inv = &Invocation{ID: event.InvocationID, StartTime: event.Timestamp}
+			byID[event.InvocationID] = inv
+			invocations = append(invocations, inv)
+		}
+		if inv.RootAgent == "" && event.Author != "user" {
+			inv.RootAgent = event.Author
+		}
+		inv.EndTime = event.Timestamp
+		lastErrorCode[inv.ID] = event.ErrorCode
+	}
+
+	for _, inv := range invocations {
+		switch code := lastErrorCode[inv.ID]; {
+		case inv.ID == activeID:
+			inv.Status = InvocationRunning
+			inv.EndTime = time.Time{}
+		case code == CancelledErrorCode:
+			inv.Status = InvocationCancelled
+		case code != "":
+			inv.Status = InvocationError
+		default:
+			inv.Status = InvocationCompleted
+		}
+	}
+	return invocations
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+func TestRunner_Invocations(t *testing.T) {
+	ctx := t.Context()
+	sessionService := session.InMemoryService()
+	resp, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "testApp", UserID: "testUser", SessionID: "s1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, e := range []struct {
+		invocationID, author, errorCode string
+	}{
+		{"i1", "user", ""},
+		{"i1", "root", ""},
+		{"i1", "sub", ""},
+		{"i2", "user", ""},
+		{"i2", "root", "MAX_TOKENS"},
+		{"i3", "user", ""},
+		{"i3", "sub", CancelledErrorCode},
+		{"i4", "user", ""},
+		{"i4", "root", ""},
+	} {
+		event := session.NewEvent(e.invocationID)
+		event.Author = e.author
+		event.Timestamp = start.Add(time.Duration(i) * time.Second)
+		event.LLMResponse = model.LLMResponse{ErrorCode: e.errorCode}
+		if err := sessionService.AppendEvent(ctx, resp.Session, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r, err := New(Config{AppName: "testApp", Agent: must(agent.New(agent.Config{Name: "root"})), SessionService: sessionService})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	unregister := activeInvocations.add(sessionKey{appName: "testApp", userID: "testUser", sessionID: "s1"}, "i4", func(error) {})
+	defer unregister()
+
+	got, err := r.Invocations(ctx, "testUser", "s1")
+	if err != nil {
+		t.Fatalf("Invocations() error = %v", err)
+	}
+	want := []*Invocation{
+		{ID: "i1", RootAgent: "root", StartTime: start, EndTime: start.Add(2 * time.Second), Status: InvocationCompleted},
+		{ID: "i2", RootAgent: "root", StartTime: start.Add(3 * time.Second), EndTime: start.Add(4 * time.Second), Status: InvocationError},
+		{ID: "i3", RootAgent: "sub", StartTime: start.Add(5 * time.Second), EndTime: start.Add(6 * time.Second), Status: InvocationCancelled},
+		{ID: "i4", RootAgent: "root", StartTime: start.Add(7 * time.Second), Status: InvocationRunning},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Invocations() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := r.Invocations(ctx, "testUser", "unknown"); err == nil {
+		t.Error("Invocations() of unknown session succeeded, want error")
+	}
+}
@@ -0,0 +1,278 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/agent/runconfig"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/logging"
+	"google.golang.org/adk/internal/metrics"
+	"google.golang.org/adk/internal/telemetry"
+	"google.golang.org/adk/session"
+)
+
+// RunLive runs the agent in bidirectional streaming mode. The user input,
+// e.g. text, audio chunks and activity signals, is read from queue and the
+// agent responds with events as the model produces them. The run ends when
+// queue is closed or the agent finishes.
+//
+// The content sent through queue goes through the plugins and the input
+// filters of the runner before it reaches the agent, and the events of the
+// agent through the output filters, as with [Runner.Run]. Blocked content
+// is not sent to the model, the rest is recorded in the session as user
+// events. The invocation can be cancelled with [Runner.Cancel].
+//
+// The agent's model must implement [model.LiveLLM].
+func (r *Runner) RunLive(ctx context.Context, userID, sessionID string, queue *agent.LiveRequestQueue, cfg agent.RunConfig) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		if queue == nil {
+			yield(nil, fmt.Errorf("live request queue is required"))
+			return
+		}
+
+		spanCtx, spans := telemetry.StartTrace(ctx, "invocation")
+		var traceErr error
+		defer func() {
+			telemetry.EndTrace(spans, traceErr)
+			metrics.RecordInvocation(r.appName, traceErr != nil)
+		}()
+
+		release, err := r.lockSession(spanCtx, userID, sessionID)
+		if err != nil {
+			traceErr = err
+			yield(nil, err)
+			return
+		}
+		defer release()
+
+		storedSession, err := r.getSession(spanCtx, userID, sessionID)
+		if err != nil {
+			traceErr = err
+			yield(nil, err)
+			return
+		}
+
+		agentToRun, err := r.findAgentToRun(ctx, storedSession)
+		if err != nil {
+			traceErr = err
+			yield(nil, err)
+			return
+		}
+
+		// The agent reads the requests that passed the input checks.
+		input := agent.NewLiveRequestQueue()
+		events := r.newEventWriter(storedSession)
+		cancelCtx, cancel := context.WithCancelCause(spanCtx)
+		cfg.StreamingMode = agent.StreamingModeBidi
+		ctx := r.newInvocationContext(cancelCtx, events.session(), &runconfig.RunConfig{
+			StreamingMode:    runconfig.StreamingMode(cfg.StreamingMode),
+			MaxLLMCalls:      cfg.MaxLLMCalls,
+			MaxToolCalls:     cfg.MaxToolCalls,
+			LiveRequestQueue: input,
+
+			ResponseModalities:       cfg.ResponseModalities,
+			SpeechConfig:             cfg.SpeechConfig,
+			InputAudioTranscription:  cfg.InputAudioTranscription,
+			OutputAudioTranscription: cfg.OutputAudioTranscription,
+		}, icontext.InvocationContextParams{
+			Agent:     agentToRun,
+			RunConfig: &cfg,
+		})
+		telemetry.TraceInvocation(spans, r.appName, userID, sessionID, ctx.InvocationID())
+		defer r.registerInvocation(ctx, cancel)()
+
+		for event, err := range writingEvents(ctx, events, r.runLive(ctx, events, queue, input, cancel, cfg)) {
+			if err != nil {
+				traceErr = err
+			}
+			if !yield(event, err) {
+				return
+			}
+		}
+	}
+}
+
+// liveResult is an event or an error of a live invocation, or the user
+// content to record in the session.
+type liveResult struct {
+	event *session.Event
+	err   error
+	user  *genai.Content
+}
+
+// runLive forwards the requests of queue that pass the input checks to
+// input, the queue of the agent, and yields the events of the agent. The
+// user content is written to the session before it is forwarded, and the
+// events replacing blocked requests are yielded as they are blocked.
+//
+// The agent runs in its own goroutine but is paused while its events are
+// processed, as with [Runner.Run]. cancel stops the agent if the caller
+// stops consuming the events.
+func (r *Runner) runLive(ctx agent.InvocationContext, events *eventWriter, queue, input *agent.LiveRequestQueue, cancel context.CancelCauseFunc, cfg agent.RunConfig) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		agentToRun := ctx.Agent()
+		logger := logging.FromContext(ctx)
+
+		done := make(chan struct{})
+		inputCtx, stopInput := context.WithCancel(ctx)
+		var wg sync.WaitGroup
+		stop := sync.OnceFunc(func() {
+			close(done)
+			stopInput()
+			cancel(nil)
+			wg.Wait()
+		})
+		defer stop()
+
+		inputs := make(chan liveResult)
+		send := func(res liveResult) bool {
+			select {
+			case inputs <- res:
+				return true
+			case <-done:
+				return false
+			}
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(inputs)
+			defer input.Close()
+			for req := range queue.Receive(inputCtx) {
+				if req.Content != nil {
+					msg, blocked, err := r.checkInput(ctx, req.Content)
+					if err != nil || blocked != nil {
+						if !send(liveResult{event: blocked, err: err}) {
+							return
+						}
+						continue
+					}
+					// The session gets its own copy of the parts, the
+					// blobs in it may be replaced with artifacts.
+					user := &genai.Content{Role: msg.Role, Parts: slices.Clone(msg.Parts)}
+					if !send(liveResult{user: user}) {
+						return
+					}
+					checked := *req
+					checked.Content = msg
+					req = &checked
+				}
+				if err := input.Send(req); err != nil {
+					return
+				}
+			}
+		}()
+
+		agentEvents := make(chan liveResult)
+		next := make(chan struct{})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(agentEvents)
+			for event, err := range agentToRun.Run(ctx) {
+				select {
+				case agentEvents <- liveResult{event: event, err: err}:
+				case <-done:
+					return
+				}
+				select {
+				case <-next:
+				case <-done:
+					return
+				}
+			}
+		}()
+
+		processed := &invocationEvents{r: r, ctx: ctx, events: events, tokenBudget: cfg.TokenBudget}
+		for agentEvents != nil || inputs != nil {
+			select {
+			case res, ok := <-inputs:
+				if !ok {
+					inputs = nil
+					continue
+				}
+				if res.err != nil {
+					if !yield(nil, res.err) {
+						return
+					}
+					continue
+				}
+				if res.user != nil {
+					if err := r.appendMessageToSession(ctx, events, res.user, cfg.SaveInputBlobsAsArtifacts); err != nil {
+						yield(nil, err)
+						return
+					}
+					continue
+				}
+				if err := events.append(ctx, res.event); err != nil {
+					yield(nil, fmt.Errorf("failed to add event to session: %w", err))
+					return
+				}
+				if !yield(res.event, nil) {
+					return
+				}
+
+			case res, ok := <-agentEvents:
+				if !ok {
+					agentEvents = nil
+					// The agent is done, there is no one left to read the
+					// user input.
+					stopInput()
+					continue
+				}
+				if isCancelled(ctx) {
+					stop()
+					r.endCancelled(ctx, events, yield)
+					return
+				}
+				if res.err != nil {
+					logger.Warn("agent run failed", "agent", agentToRun.Name(), "error", res.err)
+					if !yield(res.event, res.err) {
+						return
+					}
+					next <- struct{}{}
+					continue
+				}
+				out, end, err := processed.process(res.event)
+				if err != nil {
+					yield(nil, err)
+					return
+				}
+				for _, event := range out {
+					if !yield(event, nil) {
+						return
+					}
+				}
+				if end {
+					return
+				}
+				next <- struct{}{}
+			}
+		}
+
+		if isCancelled(ctx) {
+			r.endCancelled(ctx, events, yield)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/guardrail"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+)
+
+func TestRunner_RunLiveFilters(t *testing.T) {
+	ctx := t.Context()
+	var received []string
+	liveModel := &testutil.MockLiveModel{
+		Respond: func(req *model.LiveRequest) []*model.LLMResponse {
+			if req.Content == nil {
+				return nil
+			}
+			received = append(received, req.Content.Parts[0].Text)
+			return []*model.LLMResponse{
+				{Content: genai.NewContentFromText("the secret is 42", genai.RoleModel)},
+				{TurnComplete: true},
+			}
+		},
+	}
+	a, err := llmagent.New(llmagent.Config{Name: "live_agent", Model: liveModel})
+	if err != nil {
+		t.Fatal(err)
+	}
+	blockInput, err := guardrail.NewRegexBlocklist(guardrail.RegexBlocklistConfig{Patterns: []string{"forbidden"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	blockOutput, err := guardrail.NewRegexBlocklist(guardrail.RegexBlocklistConfig{Patterns: []string{"secret"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	r, err := runner.New(runner.Config{
+		AppName:        "app",
+		Agent:          a,
+		SessionService: sessionService,
+		InputFilters:   []guardrail.Filter{blockInput},
+		OutputFilters:  []guardrail.Filter{blockOutput},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queue := agent.NewLiveRequestQueue()
+	for _, text := range []string{"a forbidden question", "tell me"} {
+		if err := queue.SendContent(genai.NewContentFromText(text, genai.RoleUser)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var got []string
+	for ev, err := range r.RunLive(ctx, "user", "session", queue, agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("RunLive() failed: %v", err)
+		}
+		switch {
+		case ev.TurnComplete:
+			got = append(got, "turn_complete")
+			queue.Close()
+		case ev.ErrorCode != "":
+			got = append(got, ev.Author+":"+ev.ErrorCode)
+		case ev.Content != nil:
+			got = append(got, ev.Author+":"+ev.Content.Parts[0].Text)
+		}
+	}
+
+	want := []string{"live_agent:" + guardrail.BlockedErrorCode, "live_agent:" + guardrail.BlockedErrorCode, "turn_complete"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("events mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"tell me"}, received); diff != "" {
+		t.Errorf("model input mismatch (-want +got):\n%s", diff)
+	}
+
+	resp, err := sessionService.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for ev := range resp.Session.Events().All() {
+		if ev.Content != nil && strings.Contains(ev.Content.Parts[0].Text, "secret") {
+			t.Errorf("session has the blocked output %q", ev.Content.Parts[0].Text)
+		}
+	}
+}
+
+func TestRunner_RunLiveRecordsUserContent(t *testing.T) {
+	ctx := t.Context()
+	liveModel := &testutil.MockLiveModel{
+		Respond: func(req *model.LiveRequest) []*model.LLMResponse {
+			if req.Content == nil {
+				return nil
+			}
+			return []*model.LLMResponse{
+				{Content: genai.NewContentFromText("hi", genai.RoleModel)},
+				{TurnComplete: true},
+			}
+		},
+	}
+	a, err := llmagent.New(llmagent.Config{Name: "live_agent", Model: liveModel})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	r, err := runner.New(runner.Config{AppName: "app", Agent: a, SessionService: sessionService})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	queue := agent.NewLiveRequestQueue()
+	if err := queue.SendContent(genai.NewContentFromText("hello", genai.RoleUser)); err != nil {
+		t.Fatal(err)
+	}
+	for ev, err := range r.RunLive(ctx, "user", "session", queue, agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("RunLive() failed: %v", err)
+		}
+		if ev.TurnComplete {
+			queue.Close()
+		}
+	}
+
+	resp, err := sessionService.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for ev := range resp.Session.Events().All() {
+		if ev.Content != nil {
+			got = append(got, ev.Author+":"+ev.Content.Parts[0].Text)
+		}
+	}
+	want := []string{"user:hello", "live_agent:hi"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("session events mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/session"
+)
+
+// ReplayConfig is used by [Runner.Replay].
+type ReplayConfig struct {
+	// UserID is the user of the replayed session. If empty, the user of the
+	// bundle is used.
+	UserID string
+	// RunConfig is used for all the replayed messages.
+	RunConfig agent.RunConfig
+	// MatchText reports whether the replayed final response of a turn
+	// matches the recorded one. If nil, the texts must be equal, ignoring
+	// leading and trailing spaces.
+	MatchText func(recorded, replayed string) bool
+}
+
+// ReplayTurn compares a recorded invocation with its replay.
+type ReplayTurn struct {
+	// InvocationID is the ID of the recorded invocation.
+	InvocationID string `json:"invocationId"`
+	// UserMessage is the text of the replayed user message.
+	UserMessage string `json:"userMessage"`
+	// RecordedEvents and ReplayedEvents outline the events of the
+	// invocations: their author, and the tool calls, tool responses, text
+	// or agent transfer they carry.
+	RecordedEvents []string `json:"recordedEvents"`
+	ReplayedEvents []string `json:"replayedEvents"`
+	// RecordedText and ReplayedText are the final responses.
+	RecordedText string `json:"recordedText"`
+	ReplayedText string `json:"replayedText"`
+	// EventsMatch and TextMatches report whether the replay matches the
+	// recording.
+	EventsMatch bool `json:"eventsMatch"`
+	TextMatches bool `json:"textMatches"`
+	// Error is the error of the replayed invocation, if it failed.
+	Error string `json:"error,omitempty"`
+}
+
+// Passed reports whether the replay of the turn matches the recording.
+func (t *ReplayTurn) Passed() bool {
+	return t.Error == "" && t.EventsMatch && t.TextMatches
+}
+
+// ReplayReport is the result of [Runner.Replay].
+type ReplayReport struct {
+	// SessionID is the session the conversation was replayed in.
+	SessionID string        `json:"sessionId"`
+	Turns     []*ReplayTurn `json:"turns"`
+}
+
+// Passed reports whether the replay of every turn matches the recording.
+func (r *ReplayReport) Passed() bool {
+	for _, t := range r.Turns {
+		if !t.Passed() {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the report in a human-readable form, with the differences
+// between the recorded and the replayed turns.
+func (r *ReplayReport) String() string {
+	var sb strings.Builder
+	passed := 0
+	for i, t := range r.Turns {
+		status := "ok"
+		if t.Passed() {
+			passed++
+		} else {
+			status = "FAILED"
+		}
+		fmt.Fprintf(&sb, "turn %d: %s: %q\n", i+1, status, t.UserMessage)
+		if t.Error != "" {
+			fmt.Fprintf(&sb, "  error: %s\n", t.Error)
+		}
+		if !t.EventsMatch {
+			sb.WriteString("  events differ (-recorded +replayed):\n")
+			for _, line := range diffLines(t.RecordedEvents, t.ReplayedEvents) {
+				fmt.Fprintf(&sb, "    %s\n", line)
+			}
+		}
+		if !t.TextMatches {
+			fmt.Fprintf(&sb, "  final response differs:\n    recorded: %q\n    replayed: %q\n", t.RecordedText, t.ReplayedText)
+		}
+	}
+	fmt.Fprintf(&sb, "%d/%d turns passed\n", passed, len(r.Turns))
+	return sb.String()
+}
+
+// Replay sends the user messages recorded in the bundle, made by
+// [session.Export], to the agent of the runner, in a new session, and
+// compares the events and the final response of each invocation with the
+// recorded ones. It is meant for regression tests of an agent against real
+// conversations.
+//
+// The replayed session starts with an empty state. The recorded invocations
+// without a user message, e.g. resumed ones, are not replayed.
+func (r *Runner) Replay(ctx context.Context, bundle *session.Bundle, cfg ReplayConfig) (*ReplayReport, error) {
+	if bundle == nil {
+		return nil, errors.New("bundle is required")
+	}
+	userID := cfg.UserID
+	if userID == "" {
+		userID = bundle.UserID
+	}
+	matchText := cfg.MatchText
+	if matchText == nil {
+		matchText = func(recorded, replayed string) bool {
+			return strings.TrimSpace(recorded) == strings.TrimSpace(replayed)
+		}
+	}
+	chat, err := r.NewChat(ctx, ChatConfig{UserID: userID, RunConfig: cfg.RunConfig})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ReplayReport{SessionID: chat.SessionID()}
+	for _, turn := range recordedTurns(bundle.Events) {
+		recorded := &RunResult{StateDelta: map[string]any{}, ArtifactDelta: map[string]int64{}}
+		for _, event := range turn.events {
+			recorded.add(event)
+		}
+		result, err := chat.SendContent(ctx, turn.message)
+		if err != nil && ctx.Err() != nil {
+			return nil, err
+		}
+		t := &ReplayTurn{
+			InvocationID:   turn.invocationID,
+			UserMessage:    responseText(turn.message),
+			RecordedEvents: outlineEvents(recorded.Events),
+			ReplayedEvents: outlineEvents(result.Events),
+			RecordedText:   recorded.FinalText,
+			ReplayedText:   result.FinalText,
+		}
+		if err != nil {
+			t.Error = err.Error()
+		}
+		t.EventsMatch = slices.Equal(t.RecordedEvents, t.ReplayedEvents)
+		t.TextMatches = matchText(t.RecordedText, t.ReplayedText)
+		report.Turns = append(report.Turns, t)
+	}
+	return report, nil
+}
+
+type recordedTurn struct {
+	invocationID string
+	message      *genai.Content
+	// events are the events of the invocation following the user message.
+	events []*session.Event
+}
+
+// recordedTurns groups the events by invocation, in the order the
+// invocations started, and keeps the ones started by a user message.
+func recordedTurns(events []*session.Event) []*recordedTurn {
+	var turns []*recordedTurn
+	byID := map[string]*recordedTurn{}
+	for _, event := range events {
+		if event == nil {
+			continue
+		}
+		turn, ok := byID[event.InvocationID]
+		if !ok {
+			turn = &recordedTurn{invocationID: event.InvocationID}
+			byID[event.InvocationID] = turn
+			turns = append(turns, turn)
+		}
+		if turn.message == nil && event.Author == "user" && event.Content != nil && !hasFunctionResponse(event) {
+			turn.message = event.Content
+			continue
+		}
+		turn.events = append(turn.events, event)
+	}
+	res := turns[:0]
+	for _, turn := range turns {
+		if turn.message != nil {
+			res = append(res, turn)
+		}
+	}
+	return res
+}
+
+// outlineEvents describes each event by its author and what it carries,
+// leaving out the texts and arguments that vary between runs.
+func outlineEvents(events []*session.Event) []string {
+	var res []string
+	for _, event := range events {
+		var items []string
+		if event.Content != nil {
+			for _, p := range event.Content.Parts {
+				switch {
+				case p.FunctionCall != nil:
+					items = append(items, "call "+p.FunctionCall.Name)
+				case p.FunctionResponse != nil:
+					items = append(items, "response "+p.FunctionResponse.Name)
+				case p.Text != "" && !p.Thought:
+					if len(items) == 0 || items[len(items)-1] != "text" {
+						items = append(items, "text")
+					}
+				}
+			}
+		}
+		if event.Actions.TransferToAgent != "" {
+			items = append(items, "transfer to "+event.Actions.TransferToAgent)
+		}
+		if event.ErrorCode != "" {
+			items = append(items, "error "+event.ErrorCode)
+		}
+		if len(items) == 0 {
+			items = append(items, "empty")
+		}
+		res = append(res, event.Author+": "+strings.Join(items, ", "))
+	}
+	return res
+}
+
+// diffLines returns the lines of a and b, prefixed with "-" for the lines
+// only in a, "+" for the lines only in b and " " for the common lines.
+func diffLines(a, b []string) []string {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var res []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			res = append(res, "  "+a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			res = append(res, "- "+a[i])
+			i++
+		default:
+			res = append(res, "+ "+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		res = append(res, "- "+a[i])
+	}
+	for ; j < len(b); j++ {
+		res = append(res, "+ "+b[j])
+	}
+	return res
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"iter"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+// scriptedAgent answers each user message with the contents scripted for
+// its text.
+func scriptedAgent(script map[string][]*genai.Content) agent.Agent {
+	return must(agent.New(agent.Config{
+		Name: "test_agent",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				for _, content := range script[ctx.UserContent().Parts[0].Text] {
+					event := session.NewEvent(ctx.InvocationID())
+					event.LLMResponse = model.LLMResponse{Content: content}
+					if !yield(event, nil) {
+						return
+					}
+				}
+			}
+		},
+	}))
+}
+
+func TestRunner_Replay(t *testing.T) {
+	ctx := t.Context()
+	weatherCall := &genai.Content{Role: genai.RoleModel, Parts: []*genai.Part{genai.NewPartFromFunctionCall("get_weather", nil)}}
+	recordedAgent := scriptedAgent(map[string][]*genai.Content{
+		"hello":   {genai.NewContentFromText("hi there", genai.RoleModel)},
+		"weather": {weatherCall, genai.NewContentFromText("sunny", genai.RoleModel)},
+	})
+	sessionService := session.InMemoryService()
+	recorder, err := New(Config{AppName: "testApp", Agent: recordedAgent, SessionService: sessionService})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	chat, err := recorder.NewChat(ctx, ChatConfig{UserID: "testUser"})
+	if err != nil {
+		t.Fatalf("NewChat() error = %v", err)
+	}
+	for _, msg := range []string{"hello", "weather"} {
+		if _, err := chat.Send(ctx, msg); err != nil {
+			t.Fatalf("Send(%q) error = %v", msg, err)
+		}
+	}
+	bundle, err := session.Export(ctx, sessionService, &session.ExportRequest{AppName: "testApp", UserID: "testUser", SessionID: chat.SessionID()})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	replayedAgent := scriptedAgent(map[string][]*genai.Content{
+		"hello":   {genai.NewContentFromText(" hi there\n", genai.RoleModel)},
+		"weather": {genai.NewContentFromText("rainy", genai.RoleModel)},
+	})
+	replayer, err := New(Config{AppName: "testApp", Agent: replayedAgent, SessionService: sessionService})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	report, err := replayer.Replay(ctx, bundle, ReplayConfig{})
+	if err != nil {
+		t.Fatalf("Replay() error = %v", err)
+	}
+
+	if report.SessionID == chat.SessionID() {
+		t.Error("Replay() used the recorded session")
+	}
+	for _, turn := range report.Turns {
+		turn.InvocationID = ""
+	}
+	want := []*ReplayTurn{
+		{
+			UserMessage:    "hello",
+			RecordedEvents: []string{"test_agent: text"},
+			ReplayedEvents: []string{"test_agent: text"},
+			RecordedText:   "hi there",
+			ReplayedText:   " hi there\n",
+			EventsMatch:    true,
+			TextMatches:    true,
+		},
+		{
+			UserMessage:    "weather",
+			RecordedEvents: []string{"test_agent: call get_weather", "test_agent: text"},
+			ReplayedEvents: []string{"test_agent: text"},
+			RecordedText:   "sunny",
+			ReplayedText:   "rainy",
+		},
+	}
+	if diff := cmp.Diff(want, report.Turns); diff != "" {
+		t.Errorf("Replay() turns mismatch (-want +got):\n%s", diff)
+	}
+	if report.Passed() {
+		t.Error("Passed() = true, want false")
+	}
+	wantText := `turn 1: ok: "hello"
+turn 2: FAILED: "weather"
+  events differ (-recorded +replayed):
+    - test_agent: call get_weather
+      test_agent: text
+  final response differs:
+    recorded: "sunny"
+    replayed: "rainy"
+1/2 turns passed
+`
+	if diff := cmp.Diff(wantText, report.String()); diff != "" {
+		t.Errorf("String() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	got := diffLines(strings.Fields("a b c d"), strings.Fields("a x c d e"))
+	want := []string{"  a", "- b", "+ x", "  c", "  d", "+ e"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("diffLines() mismatch (-want +got):\n%s", diff)
+	}
+}
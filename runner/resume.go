@@ -0,0 +1,186 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/agent/runconfig"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/session"
+)
+
+// ErrInvocationNotPaused is returned by [Runner.Resume] when the invocation
+// is not waiting for the given function response.
+var ErrInvocationNotPaused = errors.New("invocation is not paused")
+
+// Checkpoint is the point where an invocation paused, waiting for the
+// responses to long-running function calls.
+//
+// Checkpoints are derived from the events stored in the session, so an
+// invocation can be resumed by any runner that uses the same session
+// service.
+type Checkpoint struct {
+	InvocationID string
+	// Author is the name of the agent that made the function calls.
+	Author string
+	// Branch of the event with the function calls.
+	Branch string
+	// PendingCalls are the long-running function calls that the user has
+	// not responded to yet.
+	PendingCalls []*genai.FunctionCall
+}
+
+// FindCheckpoint returns the checkpoint of the invocation with the given ID,
+// or nil if the invocation is not paused.
+//
+// An invocation is paused as long as some of its long-running function calls
+// have no function response from the user.
+func FindCheckpoint(events session.Events, invocationID string) *Checkpoint {
+	var cp *Checkpoint
+	for event := range events.All() {
+		if event.Content == nil {
+			continue
+		}
+		if event.InvocationID == invocationID && len(event.LongRunningToolIDs) > 0 {
+			for _, p := range event.Content.Parts {
+				if p.FunctionCall == nil || !slices.Contains(event.LongRunningToolIDs, p.FunctionCall.ID) {
+					continue
+				}
+				if cp == nil {
+					cp = &Checkpoint{InvocationID: invocationID}
+				}
+				cp.Author = event.Author
+				cp.Branch = event.Branch
+				cp.PendingCalls = append(cp.PendingCalls, p.FunctionCall)
+			}
+		}
+		if cp == nil || event.Author != "user" {
+			continue
+		}
+		for _, p := range event.Content.Parts {
+			if p.FunctionResponse == nil {
+				continue
+			}
+			cp.PendingCalls = slices.DeleteFunc(cp.PendingCalls, func(fc *genai.FunctionCall) bool {
+				return fc.ID == p.FunctionResponse.ID
+			})
+		}
+	}
+	if cp == nil || len(cp.PendingCalls) == 0 {
+		return nil
+	}
+	return cp
+}
+
+// Resume continues an invocation that paused on long-running function calls,
+// see [FindCheckpoint]. resp is the result of one of the pending calls; it is
+// matched by ID, or by name if the ID is empty. The agent that made the call
+// continues the invocation, with the same invocation ID.
+//
+// If the invocation is not waiting for resp, the sequence yields an error
+// wrapping [ErrInvocationNotPaused].
+func (r *Runner) Resume(ctx context.Context, userID, sessionID, invocationID string, resp *genai.FunctionResponse, cfg agent.RunConfig) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		if resp == nil {
+			yield(nil, fmt.Errorf("function response is required"))
+			return
+		}
+		release, err := r.lockSession(ctx, userID, sessionID)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer release()
+
+		storedSession, err := r.getSession(ctx, userID, sessionID)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+
+		cp := FindCheckpoint(storedSession.Events(), invocationID)
+		if cp == nil {
+			yield(nil, fmt.Errorf("%w: invocation %q has no pending function calls", ErrInvocationNotPaused, invocationID))
+			return
+		}
+		call := pendingCall(cp, resp)
+		if call == nil {
+			yield(nil, fmt.Errorf("%w: invocation %q is not waiting for function %q with ID %q", ErrInvocationNotPaused, invocationID, resp.Name, resp.ID))
+			return
+		}
+		agentToRun := findAgent(r.rootAgent, cp.Author)
+		if agentToRun == nil {
+			yield(nil, fmt.Errorf("failed to find agent %q", cp.Author))
+			return
+		}
+
+		resp = &genai.FunctionResponse{
+			ID:       call.ID,
+			Name:     call.Name,
+			Response: resp.Response,
+		}
+		events := r.newEventWriter(storedSession)
+		cancelCtx, cancel := context.WithCancelCause(ctx)
+		ctx := r.newInvocationContext(cancelCtx, events.session(), &runconfig.RunConfig{
+			StreamingMode: runconfig.StreamingMode(cfg.StreamingMode),
+			MaxLLMCalls:   cfg.MaxLLMCalls,
+			MaxToolCalls:  cfg.MaxToolCalls,
+		}, icontext.InvocationContextParams{
+			Agent:        agentToRun,
+			Branch:       cp.Branch,
+			UserContent:  genai.NewContentFromParts([]*genai.Part{{FunctionResponse: resp}}, genai.RoleUser),
+			RunConfig:    &cfg,
+			InvocationID: cp.InvocationID,
+		})
+		defer r.registerInvocation(ctx, cancel)()
+
+		for event, err := range r.run(ctx, events, cfg) {
+			if !yield(event, err) {
+				return
+			}
+		}
+	}
+}
+
+// pendingCall returns the pending call of cp that resp responds to.
+func pendingCall(cp *Checkpoint, resp *genai.FunctionResponse) *genai.FunctionCall {
+	if resp.ID != "" {
+		i := slices.IndexFunc(cp.PendingCalls, func(fc *genai.FunctionCall) bool { return fc.ID == resp.ID })
+		if i < 0 {
+			return nil
+		}
+		return cp.PendingCalls[i]
+	}
+	var match *genai.FunctionCall
+	for _, fc := range cp.PendingCalls {
+		if fc.Name != resp.Name {
+			continue
+		}
+		if match != nil {
+			// Ambiguous, the ID is required.
+			return nil
+		}
+		match = fc
+	}
+	return match
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+// scriptedModel returns the given responses in order.
+type scriptedModel struct {
+	responses []*genai.Content
+	requests  []*model.LLMRequest
+}
+
+func (m *scriptedModel) Name() string { return "scripted" }
+
+func (m *scriptedModel) GenerateContent(ctx context.Context, req *model.LLMRequest, stream bool) iter.Seq2[*model.LLMResponse, error] {
+	return func(yield func(*model.LLMResponse, error) bool) {
+		m.requests = append(m.requests, req)
+		if len(m.responses) == 0 {
+			yield(nil, errors.New("no more responses"))
+			return
+		}
+		resp := m.responses[0]
+		m.responses = m.responses[1:]
+		yield(&model.LLMResponse{Content: resp}, nil)
+	}
+}
+
+func TestRunner_Resume(t *testing.T) {
+	ctx := t.Context()
+	approve, err := functiontool.New(functiontool.Config{
+		Name:          "approve",
+		Description:   "asks a human for approval",
+		IsLongRunning: true,
+	}, func(tool.Context, map[string]any) (map[string]any, error) {
+		return map[string]any{"status": "pending"}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	call := &genai.FunctionCall{ID: "call1", Name: "approve", Args: map[string]any{}}
+	llm := &scriptedModel{responses: []*genai.Content{
+		genai.NewContentFromParts([]*genai.Part{{FunctionCall: call}}, genai.RoleModel),
+		genai.NewContentFromText("waiting for approval", genai.RoleModel),
+		genai.NewContentFromText("approved", genai.RoleModel),
+	}}
+	a := must(llmagent.New(llmagent.Config{
+		Name:  "root",
+		Model: llm,
+		Tools: []tool.Tool{approve},
+	}))
+	sessionService := session.InMemoryService()
+	r, err := New(Config{AppName: "app", Agent: a, SessionService: sessionService})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var invocationID string
+	for event, err := range r.Run(ctx, "user", "session", genai.NewContentFromText("deploy", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+		invocationID = event.InvocationID
+	}
+
+	resp, err := sessionService.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantCheckpoint := &Checkpoint{
+		InvocationID: invocationID,
+		Author:       "root",
+		PendingCalls: []*genai.FunctionCall{call},
+	}
+	if diff := cmp.Diff(wantCheckpoint, FindCheckpoint(resp.Session.Events(), invocationID)); diff != "" {
+		t.Errorf("FindCheckpoint() mismatch (-want +got):\n%s", diff)
+	}
+
+	var got []string
+	for event, err := range r.Resume(ctx, "user", "session", invocationID, &genai.FunctionResponse{Name: "approve", Response: map[string]any{"status": "approved"}}, agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("Resume() failed: %v", err)
+		}
+		if event.InvocationID != invocationID {
+			t.Errorf("event invocation ID = %q, want %q", event.InvocationID, invocationID)
+		}
+		got = append(got, event.Content.Parts[0].Text)
+	}
+	if diff := cmp.Diff([]string{"approved"}, got); diff != "" {
+		t.Errorf("resumed events mismatch (-want +got):\n%s", diff)
+	}
+
+	contents := llm.requests[len(llm.requests)-1].Contents
+	wantResp := &genai.FunctionResponse{ID: "call1", Name: "approve", Response: map[string]any{"status": "approved"}}
+	if diff := cmp.Diff(wantResp, contents[len(contents)-1].Parts[0].FunctionResponse); diff != "" {
+		t.Errorf("last model request content mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, err := range r.Resume(ctx, "user", "session", invocationID, wantResp, agent.RunConfig{}) {
+		if !errors.Is(err, ErrInvocationNotPaused) {
+			t.Errorf("second Resume() error = %v, want %v", err, ErrInvocationNotPaused)
+		}
+	}
+}
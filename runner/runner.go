@@ -19,24 +19,36 @@ import (
 	"context"
 	"fmt"
 	"iter"
-	"log"
+	"log/slog"
+	"maps"
 
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/guardrail"
 	"google.golang.org/adk/internal/agent/parentmap"
 	"google.golang.org/adk/internal/agent/runconfig"
 	artifactinternal "google.golang.org/adk/internal/artifact"
 	icontext "google.golang.org/adk/internal/context"
 	"google.golang.org/adk/internal/llminternal"
+	"google.golang.org/adk/internal/logging"
 	imemory "google.golang.org/adk/internal/memory"
+	"google.golang.org/adk/internal/metrics"
+	"google.golang.org/adk/internal/plugininternal"
 	"google.golang.org/adk/internal/sessioninternal"
+	"google.golang.org/adk/internal/telemetry"
 	"google.golang.org/adk/memory"
 	"google.golang.org/adk/model"
+	"google.golang.org/adk/plugin"
+	"google.golang.org/adk/policy"
 	"google.golang.org/adk/session"
 )
 
+// TokenBudgetExceededErrorCode is the error code of the event that ends an
+// invocation that exceeded agent.RunConfig.TokenBudget.
+const TokenBudgetExceededErrorCode = "TOKEN_BUDGET_EXCEEDED"
+
 // Config is used to create a [Runner].
 type Config struct {
 	AppName string
@@ -48,6 +60,34 @@ type Config struct {
 	ArtifactService artifact.Service
 	// optional
 	MemoryService memory.Service
+	// Plugins are called for all agents in the agent tree, in the given
+	// order. Optional.
+	Plugins []plugin.Plugin
+	// InputFilters check every user message before it is passed to the
+	// agents. Optional.
+	InputFilters []guardrail.Filter
+	// OutputFilters check every event produced by the agents before it is
+	// stored and returned. When set, partial events are not returned.
+	// Optional.
+	OutputFilters []guardrail.Filter
+	// ToolPolicy, if set, is evaluated before each tool call of the agents
+	// to allow it, deny it or require the confirmation of the user. It is
+	// enforced before the plugins are called. Optional.
+	ToolPolicy *policy.Config
+	// Logger receives the structured logs of the invocations, with the
+	// session and invocation IDs as attributes. Optional, defaults to
+	// slog.Default().
+	Logger *slog.Logger
+	// SessionConcurrency defines how an invocation of a session with another
+	// invocation in progress is handled. Optional, defaults to
+	// QueueConcurrentInvocations.
+	SessionConcurrency SessionConcurrency
+	// Compaction, if set, summarizes the older events of the sessions at the
+	// end of the invocations. Optional.
+	Compaction *CompactionConfig
+	// EventBatching, if set, writes the events of the invocations to the
+	// session service in batches instead of one by one. Optional.
+	EventBatching *EventBatchingConfig
 }
 
 // New creates a new [Runner].
@@ -60,6 +100,38 @@ func New(cfg Config) (*Runner, error) {
 		return nil, fmt.Errorf("session service is required")
 	}
 
+	plugins := cfg.Plugins
+	if cfg.ToolPolicy != nil {
+		p, err := policy.NewPlugin(*cfg.ToolPolicy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tool policy: %w", err)
+		}
+		plugins = append([]plugin.Plugin{p}, plugins...)
+	}
+
+	pluginNames := make(map[string]bool)
+	for _, p := range plugins {
+		if p == nil {
+			return nil, fmt.Errorf("plugin must not be nil")
+		}
+		if pluginNames[p.Name()] {
+			return nil, fmt.Errorf("duplicate plugin name %q", p.Name())
+		}
+		pluginNames[p.Name()] = true
+	}
+
+	if cfg.Compaction != nil {
+		if err := cfg.Compaction.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.EventBatching != nil {
+		if err := cfg.EventBatching.validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	parents, err := parentmap.New(cfg.Agent)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create agent tree: %w", err)
@@ -71,6 +143,13 @@ func New(cfg Config) (*Runner, error) {
 		sessionService:  cfg.SessionService,
 		artifactService: cfg.ArtifactService,
 		memoryService:   cfg.MemoryService,
+		plugins:         plugins,
+		inputFilters:    cfg.InputFilters,
+		outputFilters:   cfg.OutputFilters,
+		logger:          cfg.Logger,
+		concurrency:     cfg.SessionConcurrency,
+		compaction:      cfg.Compaction,
+		eventBatching:   cfg.EventBatching,
 		parents:         parents,
 	}, nil
 }
@@ -84,6 +163,13 @@ type Runner struct {
 	sessionService  session.Service
 	artifactService artifact.Service
 	memoryService   memory.Service
+	plugins         []plugin.Plugin
+	inputFilters    []guardrail.Filter
+	outputFilters   []guardrail.Filter
+	logger          *slog.Logger
+	concurrency     SessionConcurrency
+	compaction      *CompactionConfig
+	eventBatching   *EventBatchingConfig
 
 	parents parentmap.Map
 }
@@ -94,89 +180,390 @@ type Runner struct {
 func (r *Runner) Run(ctx context.Context, userID, sessionID string, msg *genai.Content, cfg agent.RunConfig) iter.Seq2[*session.Event, error] {
 	// TODO(hakim): we need to validate whether cfg is compatible with the Agent.
 	//   see adk-python/src/google/adk/runners.py Runner._new_invocation_context.
-	// TODO: setup tracer.
 	return func(yield func(*session.Event, error) bool) {
-		resp, err := r.sessionService.Get(ctx, &session.GetRequest{
-			AppName:   r.appName,
-			UserID:    userID,
-			SessionID: sessionID,
-		})
+		spanCtx, spans := telemetry.StartTrace(ctx, "invocation")
+		var traceErr error
+		defer func() {
+			telemetry.EndTrace(spans, traceErr)
+			metrics.RecordInvocation(r.appName, traceErr != nil)
+		}()
+
+		release, err := r.lockSession(spanCtx, userID, sessionID)
 		if err != nil {
+			traceErr = err
 			yield(nil, err)
 			return
 		}
+		defer release()
 
-		session := resp.Session
+		storedSession, err := r.getSession(spanCtx, userID, sessionID)
+		if err != nil {
+			traceErr = err
+			yield(nil, err)
+			return
+		}
 
-		agentToRun, err := r.findAgentToRun(session)
+		agentToRun, err := r.findAgentToRun(ctx, storedSession)
 		if err != nil {
+			traceErr = err
 			yield(nil, err)
 			return
 		}
 
-		ctx = parentmap.ToContext(ctx, r.parents)
-		ctx = runconfig.ToContext(ctx, &runconfig.RunConfig{
+		events := r.newEventWriter(storedSession)
+		cancelCtx, cancel := context.WithCancelCause(spanCtx)
+		ctx := r.newInvocationContext(cancelCtx, events.session(), &runconfig.RunConfig{
 			StreamingMode: runconfig.StreamingMode(cfg.StreamingMode),
+			MaxLLMCalls:   cfg.MaxLLMCalls,
+			MaxToolCalls:  cfg.MaxToolCalls,
+		}, icontext.InvocationContextParams{
+			Agent:       agentToRun,
+			UserContent: msg,
+			RunConfig:   &cfg,
 		})
+		telemetry.TraceInvocation(spans, r.appName, userID, sessionID, ctx.InvocationID())
+		defer r.registerInvocation(ctx, cancel)()
 
-		var artifacts agent.Artifacts
-		if r.artifactService != nil {
-			artifacts = &artifactinternal.Artifacts{
-				Service:   r.artifactService,
-				SessionID: session.ID(),
-				AppName:   session.AppName(),
-				UserID:    session.UserID(),
+		for event, err := range r.run(ctx, events, cfg) {
+			if err != nil {
+				traceErr = err
+			}
+			if !yield(event, err) {
+				return
 			}
 		}
 
-		var memoryImpl agent.Memory = nil
-		if r.memoryService != nil {
-			memoryImpl = &imemory.Memory{
-				Service:   r.memoryService,
-				SessionID: session.ID(),
-				UserID:    session.UserID(),
-				AppName:   session.AppName(),
+		if r.compaction != nil {
+			// A failed compaction does not fail the invocation, it is retried
+			// at the end of the next one.
+			if err := r.compact(ctx, userID, sessionID); err != nil {
+				logging.FromContext(ctx).Warn("session compaction failed", "error", err)
 			}
 		}
+	}
+}
 
-		ctx := icontext.NewInvocationContext(ctx, icontext.InvocationContextParams{
-			Artifacts:   artifacts,
-			Memory:      memoryImpl,
-			Session:     sessioninternal.NewMutableSession(r.sessionService, session),
-			Agent:       agentToRun,
-			UserContent: msg,
-			RunConfig:   &cfg,
-		})
+// run passes the user message of the invocation to the agent and yields
+// the events of the agent. The events are written to the session with
+// events.
+func (r *Runner) run(ctx agent.InvocationContext, events *eventWriter, cfg agent.RunConfig) iter.Seq2[*session.Event, error] {
+	return writingEvents(ctx, events, func(yield func(*session.Event, error) bool) {
+		agentToRun := ctx.Agent()
+
+		msg, blocked, err := r.checkInput(ctx, ctx.UserContent())
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		if blocked != nil {
+			if err := events.append(ctx, blocked); err != nil {
+				yield(nil, fmt.Errorf("failed to add event to session: %w", err))
+				return
+			}
+			yield(blocked, nil)
+			return
+		}
+		if msg != ctx.UserContent() {
+			ctx = icontext.WithUserContent(ctx, msg)
+		}
 
-		if err := r.appendMessageToSession(ctx, session, msg, cfg.SaveInputBlobsAsArtifacts); err != nil {
+		if err := r.appendMessageToSession(ctx, events, ctx.UserContent(), cfg.SaveInputBlobsAsArtifacts); err != nil {
 			yield(nil, err)
 			return
 		}
 
+		logger := logging.FromContext(ctx)
+		agentEvents := &invocationEvents{r: r, ctx: ctx, events: events, tokenBudget: cfg.TokenBudget}
 		for event, err := range agentToRun.Run(ctx) {
+			if isCancelled(ctx) {
+				break
+			}
 			if err != nil {
+				logger.Warn("agent run failed", "agent", agentToRun.Name(), "error", err)
 				if !yield(event, err) {
 					return
 				}
 				continue
 			}
-
-			// only commit non-partial event to a session service
-			if !event.LLMResponse.Partial {
-				if err := r.sessionService.AppendEvent(ctx, session, event); err != nil {
-					yield(nil, fmt.Errorf("failed to add event to session: %w", err))
+			out, end, err := agentEvents.process(event)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			for _, event := range out {
+				if !yield(event, nil) {
 					return
 				}
 			}
+			if end {
+				return
+			}
+		}
 
-			if !yield(event, nil) {
+		if isCancelled(ctx) {
+			r.endCancelled(ctx, events, yield)
+		}
+	})
+}
+
+// writingEvents yields the events of seq and writes the pending events of
+// the invocation when it ends, however it ends, and before the next
+// invocation of the session starts.
+func writingEvents(ctx context.Context, events *eventWriter, seq iter.Seq2[*session.Event, error]) iter.Seq2[*session.Event, error] {
+	return func(yield func(*session.Event, error) bool) {
+		stopped := false
+		defer func() {
+			if err := events.flush(context.WithoutCancel(ctx)); err != nil {
+				err = fmt.Errorf("failed to add event to session: %w", err)
+				if stopped {
+					logging.FromContext(ctx).Error("failed to write the pending events", "error", err)
+					return
+				}
+				yield(nil, err)
+			}
+		}()
+		for event, err := range seq {
+			if !yield(event, err) {
+				stopped = true
 				return
 			}
 		}
 	}
 }
 
-func (r *Runner) appendMessageToSession(ctx agent.InvocationContext, storedSession session.Session, msg *genai.Content, saveInputBlobsAsArtifacts bool) error {
+// checkInput passes a user message through the plugins and the input
+// filters. It returns the message to give to the agent, or the event
+// replacing the message if it is blocked.
+func (r *Runner) checkInput(ctx agent.InvocationContext, msg *genai.Content) (*genai.Content, *session.Event, error) {
+	newMsg, err := r.runOnUserMessage(ctx, msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if newMsg != nil {
+		msg = newMsg
+	}
+	if len(r.inputFilters) == 0 || msg == nil {
+		return msg, nil, nil
+	}
+	res, err := guardrail.Check(ctx, r.inputFilters, msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if res.Blocked {
+		return nil, blockedEvent(ctx, ctx.Agent().Name(), res.Reason), nil
+	}
+	return res.Content, nil, nil
+}
+
+// invocationEvents passes the events of the agents of an invocation through
+// the plugins and the output filters, writes them to the session and
+// enforces the token budget.
+type invocationEvents struct {
+	r           *Runner
+	ctx         agent.InvocationContext
+	events      *eventWriter
+	tokenBudget int64
+	usage       session.Usage
+}
+
+// process returns the events to yield for an event of the agents, and
+// whether the invocation must end after them.
+func (p *invocationEvents) process(event *session.Event) ([]*session.Event, bool, error) {
+	ctx := p.ctx
+	event, err := p.r.runOnEvent(ctx, event)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(p.r.outputFilters) > 0 {
+		// Partial events cannot be checked reliably, so they are dropped
+		// and only the complete events are returned.
+		if event.LLMResponse.Partial {
+			return nil, false, nil
+		}
+		event, err = p.r.checkOutput(ctx, event)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	// only commit non-partial event to a session service
+	if !event.LLMResponse.Partial {
+		if err := p.events.append(ctx, event); err != nil {
+			return nil, false, fmt.Errorf("failed to add event to session: %w", err)
+		}
+	}
+	logging.FromContext(ctx).Debug("event", "event_id", event.ID, "author", event.Author, "partial", event.LLMResponse.Partial, "final", event.IsFinalResponse())
+
+	if p.tokenBudget <= 0 || event.LLMResponse.Partial {
+		return []*session.Event{event}, false, nil
+	}
+	p.usage.Add(event.UsageMetadata)
+	if p.usage.TotalTokens <= p.tokenBudget {
+		return []*session.Event{event}, false, nil
+	}
+	exceeded := session.NewEvent(ctx.InvocationID())
+	exceeded.Author = ctx.Agent().Name()
+	exceeded.Branch = ctx.Branch()
+	exceeded.LLMResponse = model.LLMResponse{
+		ErrorCode:    TokenBudgetExceededErrorCode,
+		ErrorMessage: fmt.Sprintf("invocation used %d tokens, exceeding the budget of %d", p.usage.TotalTokens, p.tokenBudget),
+	}
+	if err := p.events.append(ctx, exceeded); err != nil {
+		return []*session.Event{event}, true, fmt.Errorf("failed to add event to session: %w", err)
+	}
+	return []*session.Event{event, exceeded}, true, nil
+}
+
+// endCancelled writes and yields the event ending a cancelled invocation.
+func (r *Runner) endCancelled(ctx agent.InvocationContext, events *eventWriter, yield func(*session.Event, error) bool) {
+	logging.FromContext(ctx).Info("invocation cancelled", "agent", ctx.Agent().Name())
+	event := cancelledEvent(ctx, ctx.Agent().Name())
+	// The invocation context is canceled, the event is stored regardless.
+	if err := events.append(context.WithoutCancel(ctx), event); err != nil {
+		yield(nil, fmt.Errorf("failed to add event to session: %w", err))
+		return
+	}
+	yield(event, nil)
+}
+
+// lockSession waits for or rejects, depending on the runner configuration,
+// the concurrent invocations of the session. The returned function ends the
+// invocation.
+func (r *Runner) lockSession(ctx context.Context, userID, sessionID string) (func(), error) {
+	return activeSessions.acquire(ctx, sessionKey{appName: r.appName, userID: userID, sessionID: sessionID}, r.concurrency)
+}
+
+func (r *Runner) getSession(ctx context.Context, userID, sessionID string) (session.Session, error) {
+	resp, err := r.sessionService.Get(ctx, &session.GetRequest{
+		AppName:   r.appName,
+		UserID:    userID,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Session, nil
+}
+
+// newInvocationContext creates the context of an invocation within
+// storedSession. The services and the session of params are set by the
+// runner.
+func (r *Runner) newInvocationContext(ctx context.Context, storedSession session.Session, rc *runconfig.RunConfig, params icontext.InvocationContextParams) agent.InvocationContext {
+	ctx = parentmap.ToContext(ctx, r.parents)
+	ctx = runconfig.ToContext(ctx, rc)
+	ctx = plugininternal.ToContext(ctx, r.plugins)
+
+	if r.artifactService != nil {
+		params.Artifacts = &artifactinternal.Artifacts{
+			Service:   r.artifactService,
+			SessionID: storedSession.ID(),
+			AppName:   storedSession.AppName(),
+			UserID:    storedSession.UserID(),
+		}
+	}
+
+	if r.memoryService != nil {
+		params.Memory = &imemory.Memory{
+			Service:   r.memoryService,
+			SessionID: storedSession.ID(),
+			UserID:    storedSession.UserID(),
+			AppName:   storedSession.AppName(),
+		}
+	}
+
+	params.Session = sessioninternal.NewMutableSession(r.sessionService, storedSession)
+	ictx := icontext.NewInvocationContext(ctx, params)
+
+	logger := r.loggerFor(ctx).With(
+		"app_name", storedSession.AppName(),
+		"user_id", storedSession.UserID(),
+		"session_id", storedSession.ID(),
+		"invocation_id", ictx.InvocationID(),
+	)
+	return icontext.WithContext(ictx, logging.ToContext(ictx, logger))
+}
+
+// loggerFor returns the configured logger, or the logger of ctx.
+func (r *Runner) loggerFor(ctx context.Context) *slog.Logger {
+	if r.logger != nil {
+		return r.logger
+	}
+	return logging.FromContext(ctx)
+}
+
+// runOnUserMessage returns the content of the first plugin that replaces the
+// user message, or nil if no plugin does.
+func (r *Runner) runOnUserMessage(ctx agent.InvocationContext, msg *genai.Content) (*genai.Content, error) {
+	for _, p := range r.plugins {
+		newMsg, err := p.OnUserMessage(ctx, msg)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %q failed to process user message: %w", p.Name(), err)
+		}
+		if newMsg != nil {
+			return newMsg, nil
+		}
+	}
+	return nil, nil
+}
+
+// runOnEvent returns the event as replaced by the first plugin that returns
+// a non-nil event.
+func (r *Runner) runOnEvent(ctx agent.InvocationContext, event *session.Event) (*session.Event, error) {
+	for _, p := range r.plugins {
+		newEvent, err := p.OnEvent(ctx, event)
+		if err != nil {
+			return nil, fmt.Errorf("plugin %q failed to process event: %w", p.Name(), err)
+		}
+		if newEvent != nil {
+			return newEvent, nil
+		}
+	}
+	return event, nil
+}
+
+// checkOutput runs the output filters on the content of event. It returns
+// the event with the rewritten content, or an error event if the content is
+// blocked.
+func (r *Runner) checkOutput(ctx agent.InvocationContext, event *session.Event) (*session.Event, error) {
+	if event.Author == "user" || event.LLMResponse.Content == nil {
+		return event, nil
+	}
+	res, err := guardrail.Check(ctx, r.outputFilters, event.LLMResponse.Content)
+	if err != nil {
+		return nil, err
+	}
+	if !res.Blocked {
+		event.LLMResponse.Content = res.Content
+		if len(res.Metadata) > 0 {
+			if event.CustomMetadata == nil {
+				event.CustomMetadata = map[string]any{}
+			}
+			maps.Copy(event.CustomMetadata, res.Metadata)
+		}
+		return event, nil
+	}
+	blocked := blockedEvent(ctx, event.Author, res.Reason)
+	blocked.ID = event.ID
+	blocked.Branch = event.Branch
+	blocked.Actions = event.Actions
+	return blocked, nil
+}
+
+// blockedEvent creates the event that replaces content blocked by a
+// guardrail.
+func blockedEvent(ctx agent.InvocationContext, author, reason string) *session.Event {
+	event := session.NewEvent(ctx.InvocationID())
+	event.Author = author
+	event.Branch = ctx.Branch()
+	event.LLMResponse = model.LLMResponse{
+		ErrorCode:    guardrail.BlockedErrorCode,
+		ErrorMessage: reason,
+	}
+	return event
+}
+
+func (r *Runner) appendMessageToSession(ctx agent.InvocationContext, events *eventWriter, msg *genai.Content, saveInputBlobsAsArtifacts bool) error {
 	if msg == nil {
 		return nil
 	}
@@ -205,7 +592,7 @@ func (r *Runner) appendMessageToSession(ctx agent.InvocationContext, storedSessi
 		Content: msg,
 	}
 
-	if err := r.sessionService.AppendEvent(ctx, storedSession, event); err != nil {
+	if err := events.append(ctx, event); err != nil {
 		return fmt.Errorf("failed to append event to sessionService: %w", err)
 	}
 	return nil
@@ -213,11 +600,8 @@ func (r *Runner) appendMessageToSession(ctx agent.InvocationContext, storedSessi
 
 // findAgentToRun returns the agent that should handle the next request based on
 // session history.
-func (r *Runner) findAgentToRun(session session.Session) (agent.Agent, error) {
-	events := session.Events()
-	for i := events.Len() - 1; i >= 0; i-- {
-		event := events.At(i)
-
+func (r *Runner) findAgentToRun(ctx context.Context, sess session.Session) (agent.Agent, error) {
+	for event := range session.ReverseAll(sess.Events()) {
 		// TODO: findMatchingFunctionCall.
 
 		if event.Author == "user" {
@@ -227,7 +611,7 @@ func (r *Runner) findAgentToRun(session session.Session) (agent.Agent, error) {
 		subAgent := findAgent(r.rootAgent, event.Author)
 		// Agent not found, continue looking for the other event.
 		if subAgent == nil {
-			log.Printf("Event from an unknown agent: %s, event id: %s", event.Author, event.ID)
+			r.loggerFor(ctx).Warn("event from an unknown agent", "author", event.Author, "event_id", event.ID, "session_id", sess.ID())
 			continue
 		}
 
@@ -17,16 +17,20 @@ package runner
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"iter"
+	"log/slog"
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/agent/llmagent"
 	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/model"
 	"google.golang.org/adk/session"
 )
 
@@ -87,7 +91,7 @@ func TestRunner_findAgentToRun(t *testing.T) {
 			r := &Runner{
 				rootAgent: tt.rootAgent,
 			}
-			gotAgent, err := r.findAgentToRun(tt.session)
+			gotAgent, err := r.findAgentToRun(t.Context(), tt.session)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Runner.findAgentToRun() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -314,6 +318,124 @@ func TestRunner_SaveInputBlobsAsArtifacts(t *testing.T) {
 	}
 }
 
+func TestRunner_TokenBudget(t *testing.T) {
+	ctx := t.Context()
+	sessionService := session.InMemoryService()
+
+	testAgent := must(agent.New(agent.Config{
+		Name: "test_agent",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				for i := range 5 {
+					event := session.NewEvent(ctx.InvocationID())
+					event.LLMResponse = model.LLMResponse{
+						Content: genai.NewContentFromText(fmt.Sprint(i), genai.RoleModel),
+						UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+							PromptTokenCount:     30,
+							CandidatesTokenCount: 10,
+							TotalTokenCount:      40,
+						},
+					}
+					if !yield(event, nil) {
+						return
+					}
+				}
+			}
+		},
+	}))
+
+	r, err := New(Config{
+		AppName:        "testApp",
+		Agent:          testAgent,
+		SessionService: sessionService,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "testApp", UserID: "testUser", SessionID: "testSession"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for event, err := range r.Run(ctx, "testUser", "testSession", genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{TokenBudget: 100}) {
+		if err != nil {
+			t.Fatalf("r.Run() returned an error: %v", err)
+		}
+		if event.ErrorCode != "" {
+			got = append(got, event.ErrorCode)
+			continue
+		}
+		got = append(got, event.Content.Parts[0].Text)
+	}
+
+	want := []string{"0", "1", "2", TokenBudgetExceededErrorCode}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("events mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunner_Logger(t *testing.T) {
+	ctx := t.Context()
+	sessionService := session.InMemoryService()
+	testAgent := must(agent.New(agent.Config{
+		Name: "test_agent",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				event := session.NewEvent(ctx.InvocationID())
+				event.ID = "event1"
+				event.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText("hello", genai.RoleModel)}
+				yield(event, nil)
+			}
+		},
+	}))
+
+	var buf bytes.Buffer
+	r, err := New(Config{
+		AppName:        "testApp",
+		Agent:          testAgent,
+		SessionService: sessionService,
+		Logger:         slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "testApp", UserID: "testUser", SessionID: "testSession"}); err != nil {
+		t.Fatal(err)
+	}
+	var invocationID string
+	for event, err := range r.Run(ctx, "testUser", "testSession", genai.NewContentFromText("hi", genai.RoleUser), agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("r.Run() returned an error: %v", err)
+		}
+		invocationID = event.InvocationID
+	}
+
+	var got []map[string]any
+	for line := range strings.Lines(buf.String()) {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("failed to decode log record %q: %v", line, err)
+		}
+		delete(record, "time")
+		got = append(got, record)
+	}
+	want := []map[string]any{{
+		"level":         "DEBUG",
+		"msg":           "event",
+		"app_name":      "testApp",
+		"user_id":       "testUser",
+		"session_id":    "testSession",
+		"invocation_id": invocationID,
+		"event_id":      "event1",
+		"author":        "test_agent",
+		"partial":       false,
+		"final":         true,
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("log records mismatch (-want +got):\n%s", diff)
+	}
+}
+
 // creates agentTree for tests and returns references to the agents
 func agentTree(t *testing.T) agentTreeStruct {
 	t.Helper()
@@ -15,7 +15,9 @@
 package adka2a
 
 import (
+	"cmp"
 	"fmt"
+	"maps"
 	"regexp"
 	"slices"
 	"strings"
@@ -28,6 +30,107 @@ import (
 	"google.golang.org/adk/internal/llminternal"
 )
 
+// SchemaExtensionURI identifies the agent card extension advertising the
+// input and output JSON schemas declared by the agent, in the input_schema and
+// output_schema params.
+const SchemaExtensionURI = "https://google.golang.org/adk/a2a/extensions/schemas/v1"
+
+// AgentCardConfig customizes the [a2a.AgentCard] built by [BuildAgentCard].
+// The zero fields are derived from the agent or left empty.
+type AgentCardConfig struct {
+	// URL is the address of the preferred transport of the agent.
+	URL string
+	// PreferredTransport is the transport served at URL. If empty,
+	// a2a.TransportProtocolJSONRPC is used.
+	PreferredTransport a2a.TransportProtocol
+	// AdditionalInterfaces lists all the transports served by the agent.
+	AdditionalInterfaces []a2a.AgentInterface
+	// Name of the agent. If empty, the name of the agent is used.
+	Name string
+	// Description of the agent. If empty, the description of the agent is used.
+	Description string
+	// Version of the agent.
+	Version string
+	// Provider is the organization publishing the agent.
+	Provider *a2a.AgentProvider
+	// DocumentationURL links to the documentation of the agent.
+	DocumentationURL string
+	// IconURL links to the icon of the agent.
+	IconURL string
+	// SecuritySchemes declares the schemes which can authenticate the
+	// requests to the agent.
+	SecuritySchemes a2a.NamedSecuritySchemes
+	// Security lists the scheme requirements of the requests to the agent.
+	Security []a2a.SecurityRequirements
+	// Skills of the agent. If nil, the skills are built by [BuildAgentSkills].
+	Skills []a2a.AgentSkill
+	// Capabilities of the agent. If nil, only streaming is advertised.
+	Capabilities *a2a.AgentCapabilities
+	// SupportsAuthenticatedExtendedCard is set if an extended card is served
+	// to the authenticated clients.
+	SupportsAuthenticatedExtendedCard bool
+	// Customize, if set, is called with the built card before it is
+	// returned, to set the fields not covered by the config.
+	Customize func(card *a2a.AgentCard)
+}
+
+// BuildAgentCard builds the [a2a.AgentCard] of the agent, overriding the
+// derived values with the ones set in cfg.
+func BuildAgentCard(agent agent.Agent, cfg AgentCardConfig) *a2a.AgentCard {
+	card := &a2a.AgentCard{
+		Name:                              cmp.Or(cfg.Name, agent.Name()),
+		Description:                       cmp.Or(cfg.Description, agent.Description()),
+		Version:                           cfg.Version,
+		URL:                               cfg.URL,
+		PreferredTransport:                cmp.Or(cfg.PreferredTransport, a2a.TransportProtocolJSONRPC),
+		AdditionalInterfaces:              slices.Clone(cfg.AdditionalInterfaces),
+		Provider:                          cfg.Provider,
+		DocumentationURL:                  cfg.DocumentationURL,
+		IconURL:                           cfg.IconURL,
+		DefaultInputModes:                 []string{"text/plain"},
+		DefaultOutputModes:                []string{"text/plain"},
+		SecuritySchemes:                   maps.Clone(cfg.SecuritySchemes),
+		Security:                          slices.Clone(cfg.Security),
+		Skills:                            slices.Clone(cfg.Skills),
+		Capabilities:                      a2a.AgentCapabilities{Streaming: true},
+		SupportsAuthenticatedExtendedCard: cfg.SupportsAuthenticatedExtendedCard,
+	}
+	if cfg.Skills == nil {
+		card.Skills = BuildAgentSkills(agent)
+	}
+	if cfg.Capabilities != nil {
+		card.Capabilities = *cfg.Capabilities
+	}
+	addSchemas(card, agent)
+	if cfg.Customize != nil {
+		cfg.Customize(card)
+	}
+	return card
+}
+
+// addSchemas advertises the schemas declared by the agent: the messages and
+// responses are JSON values, described by the schema extension.
+func addSchemas(card *a2a.AgentCard, agent agent.Agent) {
+	input, output := iagent.Schemas(agent)
+	if input == nil && output == nil {
+		return
+	}
+	params := map[string]any{}
+	if input != nil {
+		card.DefaultInputModes = []string{"application/json"}
+		params["input_schema"] = input.Schema()
+	}
+	if output != nil {
+		card.DefaultOutputModes = []string{"application/json"}
+		params["output_schema"] = output.Schema()
+	}
+	card.Capabilities.Extensions = append(slices.Clone(card.Capabilities.Extensions), a2a.AgentExtension{
+		URI:         SchemaExtensionURI,
+		Description: "JSON schemas of the messages and final responses of the agent.",
+		Params:      params,
+	})
+}
+
 // BuildAgentSkills attempts to create a list of [a2a.AgentSkill]s based on agent descriptions and types.
 // This information can be used in [a2a.AgentCard] to help clients understand agent capabilities.
 func BuildAgentSkills(agent agent.Agent) []a2a.AgentSkill {
@@ -85,7 +188,16 @@ func buildLLMAgentSkills(agent agent.Agent, llmState *llminternal.State) []a2a.A
 		}
 	}
 
-	// TODO(yarolegovich): mention planning and code-execution skills once supported (and if configured)
+	if llmState.Planner != nil {
+		skills = append(skills, a2a.AgentSkill{
+			ID:          fmt.Sprintf("%s-planning", agent.Name()),
+			Name:        "planning",
+			Description: "Can think about the tasks to do and make plans",
+			Tags:        []string{"llm", "planning"},
+		})
+	}
+
+	// TODO(yarolegovich): mention code-execution skills once supported (and if configured)
 
 	return skills
 }
@@ -266,6 +378,8 @@ func getDefaultAgentDescription(state *iagent.State) string {
 		return "A sequential workflow agent"
 	case iagent.TypeParallelAgent:
 		return "A parallel workflow agent"
+	case iagent.TypeGraphAgent:
+		return "A graph workflow agent"
 	case iagent.TypeLLMAgent:
 		return "An LLM-based agent"
 	default:
@@ -281,6 +395,8 @@ func getAgentTypeTag(state *iagent.State) string {
 		return "sequential_workflow"
 	case iagent.TypeParallelAgent:
 		return "parallel_workflow"
+	case iagent.TypeGraphAgent:
+		return "graph_workflow"
 	case iagent.TypeLLMAgent:
 		return "llm_agent"
 	default:
@@ -307,6 +423,6 @@ func getInternalState(agent agent.Agent) *iagent.State {
 }
 
 func isWorkflowAgent(state *iagent.State) bool {
-	workflowAgents := []iagent.Type{iagent.TypeLoopAgent, iagent.TypeSequentialAgent, iagent.TypeParallelAgent}
+	workflowAgents := []iagent.Type{iagent.TypeLoopAgent, iagent.TypeSequentialAgent, iagent.TypeParallelAgent, iagent.TypeGraphAgent}
 	return slices.Contains(workflowAgents, state.AgentType)
 }
@@ -19,12 +19,14 @@ import (
 
 	"github.com/a2aproject/a2a-go/a2a"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/jsonschema-go/jsonschema"
 
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/agent/llmagent"
 	"google.golang.org/adk/agent/workflowagents/loopagent"
 	"google.golang.org/adk/agent/workflowagents/parallelagent"
 	"google.golang.org/adk/agent/workflowagents/sequentialagent"
+	"google.golang.org/adk/planner"
 	"google.golang.org/adk/tool"
 	"google.golang.org/adk/tool/geminitool"
 	"google.golang.org/adk/tool/loadartifactstool"
@@ -97,6 +99,28 @@ func TestGetAgentSkills_LLMAgent(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "llm with planner",
+			agent: must(llmagent.New(llmagent.Config{
+				Name:        "Test LLM",
+				Description: "Test llm.",
+				Planner:     &planner.PlanReActPlanner{},
+			})),
+			want: []a2a.AgentSkill{
+				{
+					ID:          "Test LLM",
+					Description: "Test llm.",
+					Name:        "model",
+					Tags:        []string{"llm"},
+				},
+				{
+					ID:          "Test LLM-planning",
+					Name:        "planning",
+					Description: "Can think about the tasks to do and make plans",
+					Tags:        []string{"llm", "planning"},
+				},
+			},
+		},
 		{
 			name: "empty loop agent",
 			agent: must(loopagent.New(loopagent.Config{
@@ -395,3 +419,92 @@ func TestReplacePronouns(t *testing.T) {
 		}
 	}
 }
+
+func TestBuildAgentCard(t *testing.T) {
+	a := must(agent.New(agent.Config{Name: "weather", Description: "Reports the weather."}))
+	schemes := a2a.NamedSecuritySchemes{"bearer": a2a.HTTPAuthSecurityScheme{Scheme: "Bearer"}}
+
+	testCases := []struct {
+		name string
+		cfg  AgentCardConfig
+		want *a2a.AgentCard
+	}{
+		{
+			name: "defaults",
+			cfg:  AgentCardConfig{URL: "http://localhost/a2a"},
+			want: &a2a.AgentCard{
+				Name:               "weather",
+				Description:        "Reports the weather.",
+				URL:                "http://localhost/a2a",
+				PreferredTransport: a2a.TransportProtocolJSONRPC,
+				DefaultInputModes:  []string{"text/plain"},
+				DefaultOutputModes: []string{"text/plain"},
+				Skills:             BuildAgentSkills(a),
+				Capabilities:       a2a.AgentCapabilities{Streaming: true},
+			},
+		},
+		{
+			name: "overrides",
+			cfg: AgentCardConfig{
+				URL:                "http://localhost/rest",
+				PreferredTransport: a2a.TransportProtocolHTTPJSON,
+				Name:               "Weather",
+				Description:        "Weather forecasts.",
+				Version:            "1.2.0",
+				Provider:           &a2a.AgentProvider{Org: "Example", URL: "https://example.com"},
+				SecuritySchemes:    schemes,
+				Security:           []a2a.SecurityRequirements{{"bearer": {}}},
+				Skills:             []a2a.AgentSkill{{ID: "forecast", Name: "forecast"}},
+				Capabilities:       &a2a.AgentCapabilities{PushNotifications: true},
+				Customize: func(card *a2a.AgentCard) {
+					card.DefaultOutputModes = append(card.DefaultOutputModes, "application/json")
+				},
+			},
+			want: &a2a.AgentCard{
+				Name:               "Weather",
+				Description:        "Weather forecasts.",
+				Version:            "1.2.0",
+				URL:                "http://localhost/rest",
+				PreferredTransport: a2a.TransportProtocolHTTPJSON,
+				Provider:           &a2a.AgentProvider{Org: "Example", URL: "https://example.com"},
+				DefaultInputModes:  []string{"text/plain"},
+				DefaultOutputModes: []string{"text/plain", "application/json"},
+				SecuritySchemes:    schemes,
+				Security:           []a2a.SecurityRequirements{{"bearer": {}}},
+				Skills:             []a2a.AgentSkill{{ID: "forecast", Name: "forecast"}},
+				Capabilities:       a2a.AgentCapabilities{PushNotifications: true},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := BuildAgentCard(a, tc.cfg)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("BuildAgentCard() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBuildAgentCard_Schemas(t *testing.T) {
+	input := &jsonschema.Schema{Type: "object", Required: []string{"city"}}
+	output := &jsonschema.Schema{Type: "string"}
+	a := must(agent.New(agent.Config{Name: "weather", InputSchema: input, OutputSchema: output}))
+
+	card := BuildAgentCard(a, AgentCardConfig{})
+
+	if diff := cmp.Diff([]string{"application/json"}, card.DefaultInputModes); diff != "" {
+		t.Errorf("DefaultInputModes mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"application/json"}, card.DefaultOutputModes); diff != "" {
+		t.Errorf("DefaultOutputModes mismatch (-want +got):\n%s", diff)
+	}
+	want := []a2a.AgentExtension{{
+		URI:         SchemaExtensionURI,
+		Description: "JSON schemas of the messages and final responses of the agent.",
+		Params:      map[string]any{"input_schema": input, "output_schema": output},
+	}}
+	if diff := cmp.Diff(want, card.Capabilities.Extensions); diff != "" {
+		t.Errorf("Capabilities.Extensions mismatch (-want +got):\n%s", diff)
+	}
+}
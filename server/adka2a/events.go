@@ -15,12 +15,14 @@
 package adka2a
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/a2aproject/a2a-go/a2a"
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
+	"google.golang.org/adk/internal/logging"
 	"google.golang.org/adk/session"
 )
 
@@ -80,7 +82,7 @@ func ToSessionEvent(ctx agent.InvocationContext, event a2a.Event) (*session.Even
 		if err != nil {
 			return nil, fmt.Errorf("artifact update event conversion failed: %w", err)
 		}
-		event.LongRunningToolIDs = getLongRunningToolIDs(v.Artifact.Parts, event.Content.Parts)
+		event.LongRunningToolIDs = getLongRunningToolIDs(ctx, v.Artifact.Parts, event.Content.Parts)
 		event.CustomMetadata = ToCustomMetadata(v.TaskID, v.ContextID)
 		event.Partial = true
 		return event, nil
@@ -191,7 +193,7 @@ func taskToEvent(ctx agent.InvocationContext, task *a2a.Task) (*session.Event, e
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert artifact parts: %w", err)
 		}
-		lrtIDs := getLongRunningToolIDs(artifact.Parts, artifactParts)
+		lrtIDs := getLongRunningToolIDs(ctx, artifact.Parts, artifactParts)
 
 		parts = append(parts, artifactParts...)
 		longRunningToolIDs = append(longRunningToolIDs, lrtIDs...)
@@ -202,7 +204,7 @@ func taskToEvent(ctx agent.InvocationContext, task *a2a.Task) (*session.Event, e
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert status message parts: %w", err)
 		}
-		lrtIDs := getLongRunningToolIDs(task.Status.Message.Parts, msgParts)
+		lrtIDs := getLongRunningToolIDs(ctx, task.Status.Message.Parts, msgParts)
 
 		parts = append(parts, msgParts...)
 		longRunningToolIDs = append(longRunningToolIDs, lrtIDs...)
@@ -246,7 +248,7 @@ func finalTaskStatusUpdateToEvent(ctx agent.InvocationContext, update *a2a.TaskS
 	return event, nil
 }
 
-func getLongRunningToolIDs(parts []a2a.Part, converted []*genai.Part) []string {
+func getLongRunningToolIDs(ctx context.Context, parts []a2a.Part, converted []*genai.Part) []string {
 	var ids []string
 	for i, part := range parts {
 		dp, ok := part.(a2a.DataPart)
@@ -256,7 +258,7 @@ func getLongRunningToolIDs(parts []a2a.Part, converted []*genai.Part) []string {
 		if longRunning, ok := dp.Metadata[a2aDataPartMetaLongRunningKey].(bool); ok && longRunning {
 			fnCall := converted[i]
 			if fnCall.FunctionCall == nil {
-				// TODO(yarolegovich): log a warning
+				logging.FromContext(ctx).Warn("long-running tool part is not a function call", "index", i)
 				continue
 			}
 			ids = append(ids, fnCall.FunctionCall.ID)
@@ -16,6 +16,7 @@ package adka2a
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/a2aproject/a2a-go/a2a"
@@ -24,6 +25,8 @@ import (
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
+	iagent "google.golang.org/adk/internal/agent"
+	"google.golang.org/adk/internal/logging"
 	"google.golang.org/adk/runner"
 	"google.golang.org/adk/session"
 )
@@ -34,6 +37,8 @@ type ExecutorConfig struct {
 	RunnerConfig runner.Config
 	// RunConfig is the configuration which will be passed to [runner.Runner.Run] during A2A Execute invocation.
 	RunConfig agent.RunConfig
+	// SessionResolver maps A2A requests onto ADK sessions. If not set, [DefaultSessionResolver] is used.
+	SessionResolver SessionResolver
 }
 
 var _ a2asrv.AgentExecutor = (*Executor)(nil)
@@ -47,6 +52,9 @@ var _ a2asrv.AgentExecutor = (*Executor)(nil)
 //   - If there was an LLMResponse with non-zero error code, produce a TaskStatusUpdateEvent with TaskStateFailed.
 //     Else if there was an LLMResponse with long-running tool invocation, produce a TaskStatusUpdateEvent with TaskStateInputRequired.
 //     Else produce a TaskStatusUpdateEvent with TaskStateCompleted.
+//
+// If the agent declares an input or output schema, see [agent.Config], a message or a final
+// response that doesn't match it produces a TaskStatusUpdateEvent with TaskStateFailed.
 type Executor struct {
 	config ExecutorConfig
 }
@@ -61,6 +69,14 @@ func (e *Executor) Execute(ctx context.Context, reqCtx *a2asrv.RequestContext, q
 	if msg == nil {
 		return fmt.Errorf("message not provided")
 	}
+	logger := e.config.RunnerConfig.Logger
+	if logger == nil {
+		logger = logging.FromContext(ctx)
+	}
+	logger = logger.With("task_id", reqCtx.TaskID, "context_id", reqCtx.ContextID)
+	ctx = logging.ToContext(ctx, logger)
+	logger.Debug("received A2A message", "message_id", msg.ID)
+
 	content, err := toGenAIContent(msg)
 	if err != nil {
 		return fmt.Errorf("a2a message conversion failed: %w", err)
@@ -77,7 +93,14 @@ func (e *Executor) Execute(ctx context.Context, reqCtx *a2asrv.RequestContext, q
 		}
 	}
 
-	invocationMeta := toInvocationMeta(ctx, e.config, reqCtx)
+	invocationMeta, err := toInvocationMeta(ctx, e.config, reqCtx)
+	if err != nil {
+		return queue.Write(ctx, toTaskFailedUpdateEvent(reqCtx, err, nil))
+	}
+
+	if err := iagent.ValidateInput(e.config.RunnerConfig.Agent, content); err != nil {
+		return queue.Write(ctx, toTaskFailedUpdateEvent(reqCtx, err, invocationMeta.eventMeta))
+	}
 
 	if err := e.prepareSession(ctx, invocationMeta); err != nil {
 		event := toTaskFailedUpdateEvent(reqCtx, err, invocationMeta.eventMeta)
@@ -101,7 +124,12 @@ func (e *Executor) Execute(ctx context.Context, reqCtx *a2asrv.RequestContext, q
 	return nil
 }
 
+// Cancel stops the invocation in progress in the session of the task, if any,
+// and marks the task as canceled.
 func (e *Executor) Cancel(ctx context.Context, reqCtx *a2asrv.RequestContext, queue eventqueue.Queue) error {
+	if err := e.cancelInvocation(ctx, reqCtx); err != nil {
+		logging.FromContext(ctx).Warn("failed to cancel the A2A task invocation", "task_id", reqCtx.TaskID, "error", err)
+	}
 	event := a2a.NewStatusUpdateEvent(reqCtx, a2a.TaskStateCanceled, nil)
 	if err := queue.Write(ctx, event); err != nil {
 		return err
@@ -109,21 +137,49 @@ func (e *Executor) Cancel(ctx context.Context, reqCtx *a2asrv.RequestContext, qu
 	return nil
 }
 
+// cancelInvocation cancels the invocation in progress in the session of the
+// task. The run of the invocation ends with a cancelled event which stops
+// the processing in Execute.
+func (e *Executor) cancelInvocation(ctx context.Context, reqCtx *a2asrv.RequestContext) error {
+	meta, err := toInvocationMeta(ctx, e.config, reqCtx)
+	if err != nil {
+		return err
+	}
+	r, err := runner.New(e.config.RunnerConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create a runner: %w", err)
+	}
+	if err := r.Cancel(meta.userID, meta.sessionID, ""); err != nil && !errors.Is(err, runner.ErrInvocationNotFound) {
+		return err
+	}
+	return nil
+}
+
 // Processing failures should be delivered as Task failed events. An error is returned from this method if an event write fails.
 func (e *Executor) process(ctx context.Context, r *runner.Runner, processor *eventProcessor, content *genai.Content, q eventqueue.Queue) error {
 	meta := processor.meta
+	var output *session.Event
 	for event, err := range r.Run(ctx, meta.userID, meta.sessionID, content, e.config.RunConfig) {
 		if err != nil {
-			event := processor.makeTaskFailedEvent(fmt.Errorf("agent run failed: %w", err), nil)
+			event := processor.makeTaskFailedEvent(ctx, fmt.Errorf("agent run failed: %w", err), nil)
 			if eventSendErr := q.Write(ctx, event); eventSendErr != nil {
 				return fmt.Errorf("error event write failed: %w, %w", err, eventSendErr)
 			}
 			return nil
 		}
 
+		if event.ErrorCode == runner.CancelledErrorCode {
+			// The task is marked as canceled by Cancel.
+			return nil
+		}
+
+		if iagent.IsOutput(event) {
+			output = event
+		}
+
 		a2aEvent, err := processor.process(ctx, event)
 		if err != nil {
-			event := processor.makeTaskFailedEvent(fmt.Errorf("processor failed: %w", err), event)
+			event := processor.makeTaskFailedEvent(ctx, fmt.Errorf("processor failed: %w", err), event)
 			if eventSendErr := q.Write(ctx, event); eventSendErr != nil {
 				return fmt.Errorf("processor error event write failed: %w, %w", err, eventSendErr)
 			}
@@ -137,6 +193,15 @@ func (e *Executor) process(ctx context.Context, r *runner.Runner, processor *eve
 		}
 	}
 
+	if output != nil {
+		if err := iagent.ValidateOutput(e.config.RunnerConfig.Agent, output.Content); err != nil {
+			if err := q.Write(ctx, processor.makeTaskFailedEvent(ctx, err, output)); err != nil {
+				return fmt.Errorf("error event write failed: %w", err)
+			}
+			return nil
+		}
+	}
+
 	for _, ev := range processor.makeTerminalEvents() {
 		if err := q.Write(ctx, ev); err != nil {
 			return fmt.Errorf("terminal event send failed: %w", err)
@@ -25,6 +25,7 @@ import (
 	"github.com/a2aproject/a2a-go/a2asrv/eventqueue"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/jsonschema-go/jsonschema"
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
@@ -264,6 +265,59 @@ func TestExecutor_Cancel(t *testing.T) {
 	}
 }
 
+func TestExecutor_CancelInvocation(t *testing.T) {
+	ctx := t.Context()
+	started := make(chan struct{})
+	agent, err := agent.New(agent.Config{
+		Name: "test",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				close(started)
+				<-ctx.Done()
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("agent.New() error = %v, want nil", err)
+	}
+	task := &a2a.Task{ID: a2a.NewTaskID(), ContextID: a2a.NewContextID()}
+	msg := a2a.NewMessageForTask(a2a.MessageRoleUser, task, a2a.TextPart{Text: "hi"})
+	reqCtx := &a2asrv.RequestContext{TaskID: task.ID, ContextID: task.ContextID, Message: msg}
+	executor := NewExecutor(ExecutorConfig{
+		RunnerConfig: runner.Config{AppName: agent.Name(), Agent: agent, SessionService: session.InMemoryService()},
+	})
+
+	executeQueue := &testQueue{Queue: eventqueue.NewInMemoryQueue(10)}
+	done := make(chan error)
+	go func() { done <- executor.Execute(ctx, reqCtx, executeQueue) }()
+	<-started
+
+	cancelQueue := &testQueue{Queue: eventqueue.NewInMemoryQueue(10)}
+	if err := executor.Cancel(ctx, &a2asrv.RequestContext{TaskID: task.ID, ContextID: task.ContextID, StoredTask: task}, cancelQueue); err != nil {
+		t.Fatalf("executor.Cancel() error = %v, want nil", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("executor.Execute() error = %v, want nil", err)
+	}
+
+	var gotStates []a2a.TaskState
+	for _, event := range executeQueue.events {
+		if ev, ok := event.(*a2a.TaskStatusUpdateEvent); ok {
+			gotStates = append(gotStates, ev.Status.State)
+		}
+	}
+	wantStates := []a2a.TaskState{a2a.TaskStateSubmitted, a2a.TaskStateWorking}
+	if diff := cmp.Diff(wantStates, gotStates); diff != "" {
+		t.Errorf("executor.Execute() states mismatch (-want +got):\n%s", diff)
+	}
+	if len(cancelQueue.events) != 1 {
+		t.Fatalf("executor.Cancel() produced %d events, want 1", len(cancelQueue.events))
+	}
+	if event := cancelQueue.events[0].(*a2a.TaskStatusUpdateEvent); event.Status.State != a2a.TaskStateCanceled {
+		t.Errorf("executor.Cancel() = %v, want a TaskStateCanceled update", event)
+	}
+}
+
 func TestExecutor_SessionReuse(t *testing.T) {
 	ctx := t.Context()
 	agent, err := newEventReplayAgent([]*session.Event{}, nil)
@@ -289,7 +343,10 @@ func TestExecutor_SessionReuse(t *testing.T) {
 		t.Fatalf("executor.Execute() error = %v, want nil", err)
 	}
 
-	meta := toInvocationMeta(ctx, config, reqCtx)
+	meta, err := toInvocationMeta(ctx, config, reqCtx)
+	if err != nil {
+		t.Fatalf("toInvocationMeta() error = %v, want nil", err)
+	}
 	sessions, err := sessionService.List(ctx, &session.ListRequest{AppName: runnerConfig.AppName, UserID: meta.userID})
 	if err != nil {
 		t.Fatalf("sessionService.List() error = %v, want nil", err)
@@ -299,8 +356,118 @@ func TestExecutor_SessionReuse(t *testing.T) {
 	}
 
 	reqCtx.ContextID = a2a.NewContextID()
-	otherContextMeta := toInvocationMeta(ctx, config, reqCtx)
+	otherContextMeta, err := toInvocationMeta(ctx, config, reqCtx)
+	if err != nil {
+		t.Fatalf("toInvocationMeta() error = %v, want nil", err)
+	}
 	if meta.sessionID == otherContextMeta.sessionID {
 		t.Fatal("want sessionID to be different for different contextIDs")
 	}
 }
+
+func TestExecutor_SessionResolver(t *testing.T) {
+	ctx := t.Context()
+	agent, err := newEventReplayAgent([]*session.Event{}, nil)
+	if err != nil {
+		t.Fatalf("newEventReplayAgent() error = %v, want nil", err)
+	}
+	task := &a2a.Task{ID: a2a.NewTaskID(), ContextID: a2a.NewContextID()}
+
+	testCases := []struct {
+		name          string
+		resolver      SessionResolver
+		wantSessionID string
+		wantState     a2a.TaskState
+	}{
+		{
+			name: "custom identity",
+			resolver: func(ctx context.Context, reqCtx *a2asrv.RequestContext) (string, string, error) {
+				return "alice", "support-" + reqCtx.ContextID, nil
+			},
+			wantSessionID: "support-" + task.ContextID,
+			wantState:     a2a.TaskStateCompleted,
+		},
+		{
+			name: "resolver error",
+			resolver: func(ctx context.Context, reqCtx *a2asrv.RequestContext) (string, string, error) {
+				return "", "", fmt.Errorf("unknown principal")
+			},
+			wantState: a2a.TaskStateFailed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sessionService := session.InMemoryService()
+			reqCtx := &a2asrv.RequestContext{TaskID: task.ID, ContextID: task.ContextID, Message: a2a.NewMessageForTask(a2a.MessageRoleUser, task)}
+			runnerConfig := runner.Config{AppName: agent.Name(), Agent: agent, SessionService: sessionService}
+			executor := NewExecutor(ExecutorConfig{RunnerConfig: runnerConfig, SessionResolver: tc.resolver})
+			queue := &testQueue{Queue: eventqueue.NewInMemoryQueue(100)}
+
+			if err := executor.Execute(ctx, reqCtx, queue); err != nil {
+				t.Fatalf("executor.Execute() error = %v, want nil", err)
+			}
+
+			last := queue.events[len(queue.events)-1].(*a2a.TaskStatusUpdateEvent)
+			if last.Status.State != tc.wantState {
+				t.Fatalf("executor.Execute() final state = %v, want %v", last.Status.State, tc.wantState)
+			}
+			if tc.wantSessionID == "" {
+				return
+			}
+			_, err := sessionService.Get(ctx, &session.GetRequest{AppName: runnerConfig.AppName, UserID: "alice", SessionID: tc.wantSessionID})
+			if err != nil {
+				t.Fatalf("sessionService.Get() error = %v, want the resolved session to exist", err)
+			}
+		})
+	}
+}
+
+func TestExecutor_Schemas(t *testing.T) {
+	ctx := t.Context()
+	schema := &jsonschema.Schema{Type: "object", Required: []string{"city"}}
+	task := &a2a.Task{ID: a2a.NewTaskID(), ContextID: a2a.NewContextID()}
+
+	testCases := []struct {
+		name      string
+		message   string
+		reply     string
+		wantState a2a.TaskState
+	}{
+		{name: "valid", message: `{"city": "Paris"}`, reply: `{"city": "Paris"}`, wantState: a2a.TaskStateCompleted},
+		{name: "invalid message", message: "weather in Paris", reply: `{"city": "Paris"}`, wantState: a2a.TaskStateFailed},
+		{name: "invalid response", message: `{"city": "Paris"}`, reply: "sunny", wantState: a2a.TaskStateFailed},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			agent, err := agent.New(agent.Config{
+				Name:         "test",
+				InputSchema:  schema,
+				OutputSchema: schema,
+				Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+					return func(yield func(*session.Event, error) bool) {
+						event := session.NewEvent(ctx.InvocationID())
+						event.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText(tc.reply, genai.RoleModel)}
+						yield(event, nil)
+					}
+				},
+			})
+			if err != nil {
+				t.Fatalf("agent.New() error = %v", err)
+			}
+			msg := a2a.NewMessageForTask(a2a.MessageRoleUser, task, a2a.TextPart{Text: tc.message})
+			reqCtx := &a2asrv.RequestContext{TaskID: task.ID, ContextID: task.ContextID, Message: msg}
+			runnerConfig := runner.Config{AppName: agent.Name(), Agent: agent, SessionService: session.InMemoryService()}
+			executor := NewExecutor(ExecutorConfig{RunnerConfig: runnerConfig})
+			queue := &testQueue{Queue: eventqueue.NewInMemoryQueue(100)}
+
+			if err := executor.Execute(ctx, reqCtx, queue); err != nil {
+				t.Fatalf("executor.Execute() error = %v, want nil", err)
+			}
+
+			last := queue.events[len(queue.events)-1].(*a2a.TaskStatusUpdateEvent)
+			if last.Status.State != tc.wantState {
+				t.Errorf("executor.Execute() final state = %v, want %v", last.Status.State, tc.wantState)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ package adka2a
 
 import (
 	"context"
+	"fmt"
 	"maps"
 
 	"github.com/a2aproject/a2a-go/a2asrv"
@@ -35,7 +36,13 @@ type invocationMeta struct {
 	eventMeta map[string]any
 }
 
-func toInvocationMeta(ctx context.Context, config ExecutorConfig, reqCtx *a2asrv.RequestContext) invocationMeta {
+// SessionResolver maps an A2A request onto the ADK user and session used to handle it.
+// The authenticated caller, if any, is available through [a2asrv.CallContextFrom].
+type SessionResolver func(ctx context.Context, reqCtx *a2asrv.RequestContext) (userID, sessionID string, err error)
+
+// DefaultSessionResolver uses the A2A context ID as the session ID. The user ID is the name of
+// the authenticated caller or is derived from the context ID for anonymous calls.
+func DefaultSessionResolver(ctx context.Context, reqCtx *a2asrv.RequestContext) (string, string, error) {
 	// TODO(yarolegovich): update once A2A provides auth data extraction from Context
 	userID, sessionID := "A2A_USER_"+reqCtx.ContextID, reqCtx.ContextID
 
@@ -45,6 +52,21 @@ func toInvocationMeta(ctx context.Context, config ExecutorConfig, reqCtx *a2asrv
 			userID = callCtx.User.Name()
 		}
 	}
+	return userID, sessionID, nil
+}
+
+func toInvocationMeta(ctx context.Context, config ExecutorConfig, reqCtx *a2asrv.RequestContext) (invocationMeta, error) {
+	resolve := config.SessionResolver
+	if resolve == nil {
+		resolve = DefaultSessionResolver
+	}
+	userID, sessionID, err := resolve(ctx, reqCtx)
+	if err != nil {
+		return invocationMeta{}, fmt.Errorf("failed to resolve a session: %w", err)
+	}
+	if userID == "" || sessionID == "" {
+		return invocationMeta{}, fmt.Errorf("failed to resolve a session: empty user or session ID")
+	}
 
 	m := map[string]any{
 		ToA2AMetaKey("app_name"):   config.RunnerConfig.AppName,
@@ -52,7 +74,7 @@ func toInvocationMeta(ctx context.Context, config ExecutorConfig, reqCtx *a2asrv
 		ToA2AMetaKey("session_id"): sessionID,
 	}
 
-	return invocationMeta{userID: userID, sessionID: sessionID, eventMeta: m}
+	return invocationMeta{userID: userID, sessionID: sessionID, eventMeta: m}, nil
 }
 
 func toEventMeta(meta invocationMeta, event *session.Event) (map[string]any, error) {
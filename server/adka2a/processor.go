@@ -24,6 +24,7 @@ import (
 	"github.com/a2aproject/a2a-go/a2asrv"
 	"google.golang.org/genai"
 
+	"google.golang.org/adk/internal/logging"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/session"
 )
@@ -134,11 +135,12 @@ func (p *eventProcessor) makeTerminalEvents() []a2a.Event {
 	return result
 }
 
-func (p *eventProcessor) makeTaskFailedEvent(cause error, event *session.Event) *a2a.TaskStatusUpdateEvent {
+func (p *eventProcessor) makeTaskFailedEvent(ctx context.Context, cause error, event *session.Event) *a2a.TaskStatusUpdateEvent {
+	logging.FromContext(ctx).Warn("A2A task failed", "error", cause)
 	meta := p.meta.eventMeta
 	if event != nil {
 		if eventMeta, err := toEventMeta(p.meta, event); err != nil {
-			// TODO(yarolegovich): log ignored error
+			logging.FromContext(ctx).Warn("failed to convert event metadata", "event_id", event.ID, "error", err)
 		} else {
 			meta = eventMeta
 		}
@@ -0,0 +1,336 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adka2a
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+
+	"google.golang.org/adk/internal/logging"
+)
+
+const (
+	// PushSignatureHeader carries the signature of the push notifications
+	// sent by the sender returned by [NewPushSender], in the
+	// "t=<unix seconds>,v1=<hex HMAC-SHA256>" format. The HMAC is computed
+	// over "<unix seconds>.<body>".
+	PushSignatureHeader = "X-ADK-Signature"
+	// PushTokenHeader carries the token of the push notification config
+	// registered by the client.
+	PushTokenHeader = "X-A2A-Notification-Token"
+)
+
+// PushSenderConfig defines the configuration of the push notification sender.
+type PushSenderConfig struct {
+	// Client sends the notifications. If nil, a client with a 30 seconds
+	// timeout is used. The transport of a custom client must be nil or an
+	// [*http.Transport], its connections are checked like the ones of the
+	// default client.
+	Client *http.Client
+	// SigningKey is the HMAC key used to sign the notifications. If empty,
+	// the notifications are not signed.
+	SigningKey []byte
+	// FailOnError makes a failed delivery fail the task. By default, the
+	// failures are only logged.
+	FailOnError bool
+	// AllowHTTP allows webhooks served over plain HTTP. By default, only
+	// HTTPS webhooks are accepted.
+	AllowHTTP bool
+	// AllowedHosts, if set, reports whether the webhooks of a host can be
+	// reached whatever its addresses. By default, the webhooks resolving to
+	// loopback, private, link-local or unspecified addresses are rejected,
+	// so that the clients can't make the server call its internal network.
+	AllowedHosts func(host string) bool
+}
+
+// ErrInvalidPushURL is returned for the webhook URLs rejected by the
+// [PushSenderConfig].
+var ErrInvalidPushURL = errors.New("invalid push notification URL")
+
+// NewPushSender returns an [a2asrv.PushSender] delivering the task updates
+// to the webhooks registered by the clients. Pass it to the request handler
+// with [a2asrv.WithPushNotifications] to let the clients follow the tasks
+// which outlive their connection.
+func NewPushSender(cfg PushSenderConfig) a2asrv.PushSender {
+	client := http.Client{Timeout: 30 * time.Second}
+	if cfg.Client != nil {
+		client = *cfg.Client
+	}
+	transport, err := cfg.guardTransport(client.Transport)
+	client.Transport = transport
+	// Redirects would let the webhooks send the notifications, with their
+	// credentials, to unchecked URLs.
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	cfg.Client = &client
+	return &pushSender{config: cfg, err: err}
+}
+
+// NewPushConfigStore returns a store rejecting the registration of the
+// webhooks which the sender created with cfg would refuse to call, so that
+// the clients get the error. The valid configurations are saved in store.
+func NewPushConfigStore(store a2asrv.PushConfigStore, cfg PushSenderConfig) a2asrv.PushConfigStore {
+	return &pushConfigStore{PushConfigStore: store, config: cfg}
+}
+
+type pushConfigStore struct {
+	a2asrv.PushConfigStore
+	config PushSenderConfig
+}
+
+// Save implements a2asrv.PushConfigStore.
+func (s *pushConfigStore) Save(ctx context.Context, taskID a2a.TaskID, config *a2a.PushConfig) (*a2a.PushConfig, error) {
+	if config != nil {
+		if err := s.config.validateURL(ctx, config.URL); err != nil {
+			return nil, err
+		}
+	}
+	return s.PushConfigStore.Save(ctx, taskID, config)
+}
+
+// validateURL checks that the webhook rawURL can be called.
+func (cfg *PushSenderConfig) validateURL(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPushURL, err)
+	}
+	switch {
+	case u.Scheme == "https":
+	case u.Scheme == "http" && cfg.AllowHTTP:
+	default:
+		return fmt.Errorf("%w %q: unsupported scheme", ErrInvalidPushURL, rawURL)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return fmt.Errorf("%w %q: missing host", ErrInvalidPushURL, rawURL)
+	}
+	if cfg.AllowedHosts != nil && cfg.AllowedHosts(host) {
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("%w %q: %v", ErrInvalidPushURL, rawURL, err)
+	}
+	for _, addr := range addrs {
+		if internalIP(addr.IP) {
+			return fmt.Errorf("%w %q: internal address %v", ErrInvalidPushURL, rawURL, addr.IP)
+		}
+	}
+	return nil
+}
+
+// dialContext connects to the webhooks. The addresses of the hosts which
+// aren't allowed are checked again when connecting, in case they resolve
+// differently than when the URL was validated.
+func (cfg *PushSenderConfig) dialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	if host, _, err := net.SplitHostPort(address); err != nil || cfg.AllowedHosts == nil || !cfg.AllowedHosts(host) {
+		dialer.Control = checkDial
+	}
+	return dialer.DialContext(ctx, network, address)
+}
+
+// guardTransport returns a copy of rt whose connections to the internal
+// addresses are rejected. The transports whose connections can't be checked
+// are refused.
+func (cfg *PushSenderConfig) guardTransport(rt http.RoundTripper) (http.RoundTripper, error) {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	transport, ok := rt.(*http.Transport)
+	if !ok || transport.Dial != nil || transport.DialTLS != nil {
+		return nil, fmt.Errorf("push notification transport %T can't be restricted to external addresses", rt)
+	}
+	transport = transport.Clone()
+	if transport.DialContext == nil {
+		transport.DialContext = cfg.dialContext
+	} else {
+		transport.DialContext = cfg.checkConn(transport.DialContext)
+	}
+	if transport.DialTLSContext != nil {
+		transport.DialTLSContext = cfg.checkConn(transport.DialTLSContext)
+	}
+	return transport, nil
+}
+
+// checkConn wraps the custom dial function of a transport, the connections
+// to the internal addresses of the hosts which aren't allowed are closed.
+func (cfg *PushSenderConfig) checkConn(dial func(ctx context.Context, network, address string) (net.Conn, error)) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, err := dial(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+		if host, _, err := net.SplitHostPort(address); err == nil && cfg.AllowedHosts != nil && cfg.AllowedHosts(host) {
+			return conn, nil
+		}
+		if err := checkDial(network, conn.RemoteAddr().String(), nil); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return conn, nil
+	}
+}
+
+// checkDial is the net.Dialer control function rejecting the connections
+// to internal addresses.
+func checkDial(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || internalIP(ip) {
+		return fmt.Errorf("%w: connection to internal address %s", ErrInvalidPushURL, host)
+	}
+	return nil
+}
+
+// sharedAddressSpace is the range shared by the carrier-grade NATs with their
+// customers (RFC 6598), not reported by [net.IP.IsPrivate].
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0).To4(), Mask: net.CIDRMask(10, 32)}
+
+// internalIP reports whether ip belongs to the host or to a private network.
+func internalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified() || sharedAddressSpace.Contains(ip)
+}
+
+type pushSender struct {
+	config PushSenderConfig
+	// err is the error of the client configuration, returned for every
+	// notification.
+	err error
+}
+
+// SendPush implements a2asrv.PushSender.
+func (s *pushSender) SendPush(ctx context.Context, config *a2a.PushConfig, task *a2a.Task) error {
+	if err := s.send(ctx, config, task); err != nil {
+		if s.config.FailOnError {
+			return err
+		}
+		logging.FromContext(ctx).Warn("failed to send A2A push notification", "task_id", task.ID, "url", config.URL, "error", err)
+	}
+	return nil
+}
+
+func (s *pushSender) send(ctx context.Context, config *a2a.PushConfig, task *a2a.Task) error {
+	if s.err != nil {
+		return s.err
+	}
+	if err := s.config.validateURL(ctx, config.URL); err != nil {
+		return err
+	}
+	body, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create push request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if config.Token != "" {
+		req.Header.Set(PushTokenHeader, config.Token)
+	}
+	if config.Auth != nil && config.Auth.Credentials != "" {
+		// The first supported scheme is used.
+	schemes:
+		for _, scheme := range config.Auth.Schemes {
+			switch strings.ToLower(scheme) {
+			case "bearer":
+				req.Header.Set("Authorization", "Bearer "+config.Auth.Credentials)
+				break schemes
+			case "basic":
+				req.Header.Set("Authorization", "Basic "+config.Auth.Credentials)
+				break schemes
+			}
+		}
+	}
+	if len(s.config.SigningKey) > 0 {
+		req.Header.Set(PushSignatureHeader, signPush(s.config.SigningKey, time.Now(), body))
+	}
+
+	resp, err := s.config.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send push notification: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("push notification endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+func signPush(key []byte, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(pushMAC(key, ts, body))
+}
+
+func pushMAC(key []byte, ts string, body []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// ErrInvalidPushSignature is returned by [VerifyPushSignature] when the
+// signature does not match the notification.
+var ErrInvalidPushSignature = errors.New("invalid push notification signature")
+
+// VerifyPushSignature checks the [PushSignatureHeader] value of a push
+// notification against its body. Signatures older than maxAge are rejected
+// to prevent replays, a zero maxAge disables the check.
+func VerifyPushSignature(key []byte, signature string, body []byte, maxAge time.Duration) error {
+	var ts, sig string
+	for _, field := range strings.Split(signature, ",") {
+		k, v, _ := strings.Cut(field, "=")
+		switch k {
+		case "t":
+			ts = v
+		case "v1":
+			sig = v
+		}
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: missing timestamp", ErrInvalidPushSignature)
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, pushMAC(key, ts, body)) {
+		return ErrInvalidPushSignature
+	}
+	if maxAge > 0 && time.Since(time.Unix(unix, 0)) > maxAge {
+		return fmt.Errorf("%w: expired", ErrInvalidPushSignature)
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adka2a
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/a2aproject/a2a-go/a2asrv/push"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+)
+
+func TestVerifyPushSignature(t *testing.T) {
+	key := []byte("secret")
+	body := []byte(`{"id":"task"}`)
+	now := time.Now()
+
+	testCases := []struct {
+		name      string
+		signature string
+		body      []byte
+		maxAge    time.Duration
+		wantErr   bool
+	}{
+		{name: "valid", signature: signPush(key, now, body), body: body, maxAge: time.Minute},
+		{name: "no max age", signature: signPush(key, now.Add(-time.Hour), body), body: body},
+		{name: "wrong key", signature: signPush([]byte("other"), now, body), body: body, wantErr: true},
+		{name: "tampered body", signature: signPush(key, now, body), body: []byte(`{"id":"other"}`), wantErr: true},
+		{name: "expired", signature: signPush(key, now.Add(-time.Hour), body), body: body, maxAge: time.Minute, wantErr: true},
+		{name: "malformed", signature: "v1=abc", body: body, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := VerifyPushSignature(key, tc.signature, tc.body, tc.maxAge)
+			if tc.wantErr != (err != nil) {
+				t.Fatalf("VerifyPushSignature() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidPushSignature) {
+				t.Errorf("VerifyPushSignature() error = %v, want %v", err, ErrInvalidPushSignature)
+			}
+		})
+	}
+}
+
+func TestPushSender(t *testing.T) {
+	key := []byte("secret")
+	type notification struct {
+		token, authorization string
+		signatureErr         error
+		state                a2a.TaskState
+	}
+	var mu sync.Mutex
+	var got []notification
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("failed to read the notification: %v", err)
+		}
+		var task a2a.Task
+		if err := json.Unmarshal(body, &task); err != nil {
+			t.Errorf("failed to decode the notification: %v", err)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, notification{
+			token:         r.Header.Get(PushTokenHeader),
+			authorization: r.Header.Get("Authorization"),
+			signatureErr:  VerifyPushSignature(key, r.Header.Get(PushSignatureHeader), body, time.Minute),
+			state:         task.Status.State,
+		})
+	}))
+	defer webhook.Close()
+
+	event := session.NewEvent("invocation")
+	event.Content = genai.NewContentFromText("done", genai.RoleModel)
+	agent, err := newEventReplayAgent([]*session.Event{event}, nil)
+	if err != nil {
+		t.Fatalf("newEventReplayAgent() error = %v", err)
+	}
+	executor := NewExecutor(ExecutorConfig{
+		RunnerConfig: runner.Config{AppName: agent.Name(), Agent: agent, SessionService: session.InMemoryService()},
+	})
+	handler := a2asrv.NewHandler(executor, a2asrv.WithPushNotifications(push.NewInMemoryStore(), NewPushSender(PushSenderConfig{SigningKey: key, AllowHTTP: true, AllowedHosts: allowAll})))
+
+	_, err = handler.OnSendMessage(t.Context(), &a2a.MessageSendParams{
+		Message: a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "hi"}),
+		Config: &a2a.MessageSendConfig{PushConfig: &a2a.PushConfig{
+			URL:   webhook.URL,
+			Token: "token",
+			Auth:  &a2a.PushAuthInfo{Schemes: []string{"Bearer"}, Credentials: "credentials"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("OnSendMessage() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) == 0 {
+		t.Fatalf("no notification received")
+	}
+	for _, n := range got {
+		if n.signatureErr != nil {
+			t.Errorf("VerifyPushSignature() error = %v", n.signatureErr)
+		}
+		if diff := cmp.Diff([]string{"token", "Bearer credentials"}, []string{n.token, n.authorization}); diff != "" {
+			t.Errorf("notification headers mismatch (-want +got):\n%s", diff)
+		}
+	}
+	if last := got[len(got)-1].state; last != a2a.TaskStateCompleted {
+		t.Errorf("last notification state = %v, want %v", last, a2a.TaskStateCompleted)
+	}
+}
+
+func TestPushSender_FailOnError(t *testing.T) {
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer webhook.Close()
+
+	task := &a2a.Task{ID: a2a.NewTaskID(), ContextID: a2a.NewContextID()}
+	for _, failOnError := range []bool{false, true} {
+		sender := NewPushSender(PushSenderConfig{FailOnError: failOnError, AllowHTTP: true, AllowedHosts: allowAll})
+		err := sender.SendPush(t.Context(), &a2a.PushConfig{URL: webhook.URL}, task)
+		if failOnError != (err != nil) {
+			t.Errorf("SendPush() with FailOnError=%v error = %v", failOnError, err)
+		}
+	}
+}
+
+func allowAll(string) bool { return true }
+
+func TestPushSender_RejectedURLs(t *testing.T) {
+	task := &a2a.Task{ID: a2a.NewTaskID(), ContextID: a2a.NewContextID()}
+	for _, tc := range []struct {
+		name string
+		url  string
+		cfg  PushSenderConfig
+	}{
+		{name: "http", url: "http://example.com/hook"},
+		{name: "other scheme", url: "file:///etc/passwd", cfg: PushSenderConfig{AllowHTTP: true}},
+		{name: "no host", url: "https:///hook"},
+		{name: "loopback", url: "https://127.0.0.1/hook"},
+		{name: "localhost", url: "https://localhost:8080/hook"},
+		{name: "ipv6 loopback", url: "https://[::1]/hook"},
+		{name: "metadata server", url: "https://169.254.169.254/computeMetadata/v1/"},
+		{name: "private", url: "https://10.0.0.1/hook"},
+		{name: "private 192.168", url: "http://192.168.1.10/hook", cfg: PushSenderConfig{AllowHTTP: true}},
+		{name: "unspecified", url: "https://0.0.0.0/hook"},
+		{name: "shared address space", url: "https://100.64.0.1/hook"},
+		{name: "other host allowed", url: "https://127.0.0.1/hook", cfg: PushSenderConfig{AllowedHosts: func(host string) bool { return host == "hooks.internal" }}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.cfg.FailOnError = true
+			err := NewPushSender(tc.cfg).SendPush(t.Context(), &a2a.PushConfig{URL: tc.url}, task)
+			if !errors.Is(err, ErrInvalidPushURL) {
+				t.Errorf("SendPush(%q) error = %v, want %v", tc.url, err, ErrInvalidPushURL)
+			}
+		})
+	}
+}
+
+func TestPushSender_CustomClient(t *testing.T) {
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer webhook.Close()
+	// The public address of the webhook is routed to the local server.
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, webhook.Listener.Addr().String())
+		},
+	}
+	task := &a2a.Task{ID: a2a.NewTaskID(), ContextID: a2a.NewContextID()}
+	for _, tc := range []struct {
+		name    string
+		client  *http.Client
+		allowed func(string) bool
+		wantErr bool
+	}{
+		{name: "internal address", client: &http.Client{Transport: transport}, wantErr: true},
+		{name: "allowed host", client: &http.Client{Transport: transport}, allowed: allowAll},
+		{name: "unknown transport", client: &http.Client{Transport: roundTripperFunc(http.DefaultTransport.RoundTrip)}, allowed: allowAll, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sender := NewPushSender(PushSenderConfig{Client: tc.client, FailOnError: true, AllowHTTP: true, AllowedHosts: tc.allowed})
+			err := sender.SendPush(t.Context(), &a2a.PushConfig{URL: "http://203.0.113.1/hook"}, task)
+			if tc.wantErr != (err != nil) {
+				t.Errorf("SendPush() error = %v, want error %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestPushSender_NoRedirect(t *testing.T) {
+	var redirected bool
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+	}))
+	defer target.Close()
+	webhook := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+	defer webhook.Close()
+
+	sender := NewPushSender(PushSenderConfig{FailOnError: true, AllowHTTP: true, AllowedHosts: allowAll})
+	task := &a2a.Task{ID: a2a.NewTaskID(), ContextID: a2a.NewContextID()}
+	if err := sender.SendPush(t.Context(), &a2a.PushConfig{URL: webhook.URL}, task); err == nil {
+		t.Errorf("SendPush() succeeded, want an error for the redirect")
+	}
+	if redirected {
+		t.Errorf("the redirect was followed")
+	}
+}
+
+func TestPushConfigStore(t *testing.T) {
+	store := NewPushConfigStore(push.NewInMemoryStore(), PushSenderConfig{})
+	taskID := a2a.NewTaskID()
+	if _, err := store.Save(t.Context(), taskID, &a2a.PushConfig{URL: "https://169.254.169.254/"}); !errors.Is(err, ErrInvalidPushURL) {
+		t.Errorf("Save() error = %v, want %v", err, ErrInvalidPushURL)
+	}
+	configs, err := store.List(t.Context(), taskID)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(configs) != 0 {
+		t.Errorf("List() = %v, want no config", configs)
+	}
+
+	store = NewPushConfigStore(push.NewInMemoryStore(), PushSenderConfig{AllowedHosts: allowAll})
+	if _, err := store.Save(t.Context(), taskID, &a2a.PushConfig{URL: "https://127.0.0.1/hook"}); err != nil {
+		t.Errorf("Save() of an allowed host error = %v", err)
+	}
+}
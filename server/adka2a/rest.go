@@ -0,0 +1,291 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adka2a
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2apb"
+	"github.com/a2aproject/a2a-go/a2apb/pbconv"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"google.golang.org/adk/internal/logging"
+)
+
+// NewRESTHandler returns an [http.Handler] serving handler over the HTTP+JSON
+// transport of the A2A protocol, e.g. "POST /v1/message:send" or
+// "GET /v1/tasks/{id}". The streaming methods respond with server-sent
+// events. The paths are matched from the root, so the handler must be
+// mounted with [http.StripPrefix] when it is served under a prefix.
+func NewRESTHandler(handler a2asrv.RequestHandler) http.Handler {
+	return &restHandler{handler: handler}
+}
+
+type restHandler struct {
+	handler a2asrv.RequestHandler
+}
+
+// pushConfigsCollection is the collection of the push notification configs
+// of a task.
+const pushConfigsCollection = "pushNotificationConfigs"
+
+// ServeHTTP implements http.Handler.
+func (h *restHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path, ok := strings.CutPrefix(r.URL.Path, "/v1/")
+	if !ok {
+		writeRESTError(w, r, a2a.ErrMethodNotFound)
+		return
+	}
+	switch {
+	case path == "message:send" && r.Method == http.MethodPost:
+		h.sendMessage(w, r)
+	case path == "message:stream" && r.Method == http.MethodPost:
+		h.sendMessageStream(w, r)
+	case path == "card" && r.Method == http.MethodGet:
+		h.getCard(w, r)
+	case strings.HasPrefix(path, "tasks/"):
+		h.serveTask(w, r, strings.Split(strings.TrimPrefix(path, "tasks/"), "/"))
+	default:
+		writeRESTError(w, r, a2a.ErrMethodNotFound)
+	}
+}
+
+// serveTask serves the methods of the "tasks/{id}" resources, segments are
+// the path segments following "tasks/".
+func (h *restHandler) serveTask(w http.ResponseWriter, r *http.Request, segments []string) {
+	id, method, _ := strings.Cut(segments[0], ":")
+	if id == "" {
+		writeRESTError(w, r, a2a.ErrInvalidParams)
+		return
+	}
+	taskID := a2a.TaskID(id)
+	switch {
+	case len(segments) == 1 && method == "" && r.Method == http.MethodGet:
+		h.getTask(w, r, taskID)
+	case len(segments) == 1 && method == "cancel" && r.Method == http.MethodPost:
+		task, err := h.handler.OnCancelTask(r.Context(), &a2a.TaskIDParams{ID: taskID})
+		writeRESTResult(w, r, task, err, pbconv.ToProtoTask)
+	case len(segments) == 1 && method == "subscribe" && (r.Method == http.MethodGet || r.Method == http.MethodPost):
+		writeRESTStream(w, r, h.handler.OnResubscribeToTask(r.Context(), &a2a.TaskIDParams{ID: taskID}))
+	case len(segments) == 2 && method == "" && segments[1] == pushConfigsCollection && r.Method == http.MethodPost:
+		h.setPushConfig(w, r, taskID)
+	case len(segments) == 2 && method == "" && segments[1] == pushConfigsCollection && r.Method == http.MethodGet:
+		configs, err := h.handler.OnListTaskPushConfig(r.Context(), &a2a.ListTaskPushConfigParams{TaskID: taskID})
+		writeRESTResult(w, r, configs, err, pbconv.ToProtoListTaskPushConfig)
+	case len(segments) == 3 && method == "" && segments[1] == pushConfigsCollection && r.Method == http.MethodGet:
+		config, err := h.handler.OnGetTaskPushConfig(r.Context(), &a2a.GetTaskPushConfigParams{TaskID: taskID, ConfigID: segments[2]})
+		writeRESTResult(w, r, config, err, pbconv.ToProtoTaskPushConfig)
+	case len(segments) == 3 && method == "" && segments[1] == pushConfigsCollection && r.Method == http.MethodDelete:
+		err := h.handler.OnDeleteTaskPushConfig(r.Context(), &a2a.DeleteTaskPushConfigParams{TaskID: taskID, ConfigID: segments[2]})
+		if err != nil {
+			writeRESTError(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeRESTError(w, r, a2a.ErrMethodNotFound)
+	}
+}
+
+func (h *restHandler) sendMessage(w http.ResponseWriter, r *http.Request) {
+	params, err := decodeSendMessageRequest(r)
+	if err != nil {
+		writeRESTError(w, r, err)
+		return
+	}
+	result, err := h.handler.OnSendMessage(r.Context(), params)
+	writeRESTResult(w, r, result, err, pbconv.ToProtoSendMessageResponse)
+}
+
+func (h *restHandler) sendMessageStream(w http.ResponseWriter, r *http.Request) {
+	params, err := decodeSendMessageRequest(r)
+	if err != nil {
+		writeRESTError(w, r, err)
+		return
+	}
+	writeRESTStream(w, r, h.handler.OnSendMessageStream(r.Context(), params))
+}
+
+func (h *restHandler) getCard(w http.ResponseWriter, r *http.Request) {
+	card, err := h.handler.OnGetExtendedAgentCard(r.Context())
+	writeRESTResult(w, r, card, err, pbconv.ToProtoAgentCard)
+}
+
+func (h *restHandler) getTask(w http.ResponseWriter, r *http.Request, taskID a2a.TaskID) {
+	query := &a2a.TaskQueryParams{ID: taskID}
+	if v := r.URL.Query().Get("historyLength"); v != "" {
+		historyLength, err := strconv.Atoi(v)
+		if err != nil {
+			writeRESTError(w, r, fmt.Errorf("%w: invalid historyLength %q", a2a.ErrInvalidParams, v))
+			return
+		}
+		query.HistoryLength = &historyLength
+	}
+	task, err := h.handler.OnGetTask(r.Context(), query)
+	writeRESTResult(w, r, task, err, pbconv.ToProtoTask)
+}
+
+func (h *restHandler) setPushConfig(w http.ResponseWriter, r *http.Request, taskID a2a.TaskID) {
+	// The body is the config of the CreateTaskPushNotificationConfigRequest.
+	req := &a2apb.CreateTaskPushNotificationConfigRequest{
+		Parent: pbconv.MakeTaskName(taskID),
+		Config: &a2apb.TaskPushNotificationConfig{},
+	}
+	if err := decodeRESTBody(r, req.Config); err != nil {
+		writeRESTError(w, r, err)
+		return
+	}
+	params, err := pbconv.FromProtoCreateTaskPushConfigRequest(req)
+	if err != nil {
+		writeRESTError(w, r, fmt.Errorf("%w: %v", a2a.ErrInvalidParams, err))
+		return
+	}
+	config, err := h.handler.OnSetTaskPushConfig(r.Context(), params)
+	writeRESTResult(w, r, config, err, pbconv.ToProtoTaskPushConfig)
+}
+
+func decodeSendMessageRequest(r *http.Request) (*a2a.MessageSendParams, error) {
+	req := &a2apb.SendMessageRequest{}
+	if err := decodeRESTBody(r, req); err != nil {
+		return nil, err
+	}
+	params, err := pbconv.FromProtoSendMessageRequest(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", a2a.ErrInvalidParams, err)
+	}
+	if params == nil || params.Message == nil {
+		return nil, fmt.Errorf("%w: message is required", a2a.ErrInvalidParams)
+	}
+	return params, nil
+}
+
+func decodeRESTBody(r *http.Request, m proto.Message) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return fmt.Errorf("%w: %v", a2a.ErrInvalidRequest, err)
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(body, m); err != nil {
+		return fmt.Errorf("%w: %v", a2a.ErrParseError, err)
+	}
+	return nil
+}
+
+// writeRESTResult writes the result of a method converted by toProto, or err
+// if the method failed.
+func writeRESTResult[T any, P proto.Message](w http.ResponseWriter, r *http.Request, result T, err error, toProto func(T) (P, error)) {
+	if err != nil {
+		writeRESTError(w, r, err)
+		return
+	}
+	msg, err := toProto(result)
+	if err != nil {
+		writeRESTError(w, r, fmt.Errorf("%w: %v", a2a.ErrInternalError, err))
+		return
+	}
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		writeRESTError(w, r, fmt.Errorf("%w: %v", a2a.ErrInternalError, err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(data); err != nil {
+		logging.FromContext(r.Context()).Warn("failed to write A2A response", "error", err)
+	}
+}
+
+// writeRESTStream writes events as server-sent events, each carrying a
+// StreamResponse. An error ends the stream with an event carrying the error.
+func writeRESTStream(w http.ResponseWriter, r *http.Request, events iter.Seq2[a2a.Event, error]) {
+	rc := http.NewResponseController(w)
+	started := false
+	for event, err := range events {
+		var data []byte
+		if err == nil {
+			var resp *a2apb.StreamResponse
+			if resp, err = pbconv.ToProtoStreamResponse(event); err == nil {
+				data, err = protojson.Marshal(resp)
+			}
+		}
+		if err != nil {
+			if !started {
+				writeRESTError(w, r, err)
+				return
+			}
+			_, data = restError(err)
+		}
+		if !started {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("Connection", "keep-alive")
+			started = true
+		}
+		if _, werr := fmt.Fprintf(w, "data: %s\n\n", data); werr != nil {
+			logging.FromContext(r.Context()).Warn("failed to write A2A event", "error", werr)
+			return
+		}
+		if ferr := rc.Flush(); ferr != nil {
+			logging.FromContext(r.Context()).Warn("failed to flush A2A event", "error", ferr)
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func writeRESTError(w http.ResponseWriter, r *http.Request, err error) {
+	code, data := restError(err)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if _, werr := w.Write(data); werr != nil {
+		logging.FromContext(r.Context()).Warn("failed to write A2A error", "error", werr)
+	}
+}
+
+// restError returns the HTTP status code of err and its JSON encoding.
+func restError(err error) (int, []byte) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, a2a.ErrTaskNotFound), errors.Is(err, a2a.ErrMethodNotFound),
+		errors.Is(err, a2a.ErrAuthenticatedExtendedCardNotConfigured):
+		code = http.StatusNotFound
+	case errors.Is(err, a2a.ErrParseError), errors.Is(err, a2a.ErrInvalidRequest), errors.Is(err, a2a.ErrInvalidParams):
+		code = http.StatusBadRequest
+	case errors.Is(err, a2a.ErrTaskNotCancelable):
+		code = http.StatusConflict
+	case errors.Is(err, a2a.ErrPushNotificationNotSupported), errors.Is(err, a2a.ErrUnsupportedOperation):
+		code = http.StatusNotImplemented
+	case errors.Is(err, a2a.ErrUnsupportedContentType):
+		code = http.StatusUnsupportedMediaType
+	}
+	data, _ := json.Marshal(restErrorBody{Code: code, Message: err.Error()})
+	return code, data
+}
+
+// restErrorBody is the JSON body of the errors.
+type restErrorBody struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,188 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adka2a
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2apb"
+	"github.com/a2aproject/a2a-go/a2apb/pbconv"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+)
+
+func newRESTTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	event := session.NewEvent("invocation")
+	event.Content = genai.NewContentFromText("hello", genai.RoleModel)
+	agent, err := newEventReplayAgent([]*session.Event{event}, nil)
+	if err != nil {
+		t.Fatalf("newEventReplayAgent() error = %v", err)
+	}
+	executor := NewExecutor(ExecutorConfig{
+		RunnerConfig: runner.Config{AppName: agent.Name(), Agent: agent, SessionService: session.InMemoryService()},
+	})
+	srv := httptest.NewServer(NewRESTHandler(a2asrv.NewHandler(executor)))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func readRESTResponse(t *testing.T, resp *http.Response, wantCode int, m proto.Message) {
+	t.Helper()
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("io.ReadAll() error = %v", err)
+	}
+	if resp.StatusCode != wantCode {
+		t.Fatalf("status code = %d, want %d, body: %s", resp.StatusCode, wantCode, body)
+	}
+	if err := protojson.Unmarshal(body, m); err != nil {
+		t.Fatalf("protojson.Unmarshal(%s) error = %v", body, err)
+	}
+}
+
+func readRESTJSON(t *testing.T, resp *http.Response, wantCode int, v any) {
+	t.Helper()
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("io.ReadAll() error = %v", err)
+	}
+	if resp.StatusCode != wantCode {
+		t.Fatalf("status code = %d, want %d, body: %s", resp.StatusCode, wantCode, body)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		t.Fatalf("json.Unmarshal(%s) error = %v", body, err)
+	}
+}
+
+const restSendMessageBody = `{"request": {"messageId": "m1", "role": "ROLE_USER", "content": [{"text": "hi"}]}}`
+
+func TestRESTHandler_SendMessageAndGetTask(t *testing.T) {
+	srv := newRESTTestServer(t)
+
+	resp, err := http.Post(srv.URL+"/v1/message:send", "application/json", strings.NewReader(restSendMessageBody))
+	if err != nil {
+		t.Fatalf("POST message:send error = %v", err)
+	}
+	sent := &a2apb.SendMessageResponse{}
+	readRESTResponse(t, resp, http.StatusOK, sent)
+	task := sent.GetTask()
+	if task == nil {
+		t.Fatalf("message:send response = %v, want a task", sent)
+	}
+	if task.GetStatus().GetState() != a2apb.TaskState_TASK_STATE_COMPLETED {
+		t.Errorf("task state = %v, want completed", task.GetStatus().GetState())
+	}
+
+	resp, err = http.Get(srv.URL + "/v1/tasks/" + task.GetId() + "?historyLength=0")
+	if err != nil {
+		t.Fatalf("GET task error = %v", err)
+	}
+	got := &a2apb.Task{}
+	readRESTResponse(t, resp, http.StatusOK, got)
+	if got.GetId() != task.GetId() || len(got.GetHistory()) != 0 {
+		t.Errorf("GET task = %v, want task %s without history", got, task.GetId())
+	}
+}
+
+func TestRESTHandler_Stream(t *testing.T) {
+	srv := newRESTTestServer(t)
+
+	resp, err := http.Post(srv.URL+"/v1/message:stream", "application/json", strings.NewReader(restSendMessageBody))
+	if err != nil {
+		t.Fatalf("POST message:stream error = %v", err)
+	}
+	defer resp.Body.Close()
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", got)
+	}
+
+	var events []a2a.Event
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		sr := &a2apb.StreamResponse{}
+		if err := protojson.Unmarshal([]byte(data), sr); err != nil {
+			t.Fatalf("protojson.Unmarshal(%s) error = %v", data, err)
+		}
+		event, err := pbconv.FromProtoStreamResponse(sr)
+		if err != nil {
+			t.Fatalf("FromProtoStreamResponse() error = %v", err)
+		}
+		events = append(events, event)
+	}
+	if len(events) == 0 {
+		t.Fatalf("no event received")
+	}
+	last, ok := events[len(events)-1].(*a2a.TaskStatusUpdateEvent)
+	if !ok || !last.Final || last.Status.State != a2a.TaskStateCompleted {
+		t.Errorf("last event = %v, want final completed status update", events[len(events)-1])
+	}
+}
+
+func TestRESTHandler_Errors(t *testing.T) {
+	srv := newRESTTestServer(t)
+
+	testCases := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		wantCode int
+	}{
+		{name: "unknown task", method: http.MethodGet, path: "/v1/tasks/unknown", wantCode: http.StatusNotFound},
+		{name: "unknown method", method: http.MethodPost, path: "/v1/tasks/unknown:pause", wantCode: http.StatusNotFound},
+		{name: "no version", method: http.MethodGet, path: "/tasks/unknown", wantCode: http.StatusNotFound},
+		{name: "malformed body", method: http.MethodPost, path: "/v1/message:send", body: "{", wantCode: http.StatusBadRequest},
+		{name: "no message", method: http.MethodPost, path: "/v1/message:send", body: "{}", wantCode: http.StatusBadRequest},
+		{name: "invalid history length", method: http.MethodGet, path: "/v1/tasks/t1?historyLength=x", wantCode: http.StatusBadRequest},
+		{name: "push not supported", method: http.MethodGet, path: "/v1/tasks/t1/pushNotificationConfigs", wantCode: http.StatusNotImplemented},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequestWithContext(t.Context(), tc.method, srv.URL+tc.path, strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatalf("http.NewRequest() error = %v", err)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request error = %v", err)
+			}
+			var body restErrorBody
+			readRESTJSON(t, resp, tc.wantCode, &body)
+			if diff := cmp.Diff(tc.wantCode, body.Code); diff != "" {
+				t.Errorf("error code mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adka2a
+
+import (
+	"context"
+	"errors"
+	"iter"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+)
+
+// WithStoredTasks wraps handler so that the clients resubscribing to a task
+// which is no longer executing, e.g. after a reconnect or a server restart,
+// receive the task as last saved in store instead of an
+// [a2a.ErrTaskNotFound] error. The store should be the one passed to the
+// handler with [a2asrv.WithTaskStore].
+func WithStoredTasks(handler a2asrv.RequestHandler, store a2asrv.TaskStore) a2asrv.RequestHandler {
+	return &storedTasksHandler{RequestHandler: handler, store: store}
+}
+
+type storedTasksHandler struct {
+	a2asrv.RequestHandler
+	store a2asrv.TaskStore
+}
+
+// OnResubscribeToTask implements a2asrv.RequestHandler.
+func (h *storedTasksHandler) OnResubscribeToTask(ctx context.Context, params *a2a.TaskIDParams) iter.Seq2[a2a.Event, error] {
+	return func(yield func(a2a.Event, error) bool) {
+		running := false
+		for event, err := range h.RequestHandler.OnResubscribeToTask(ctx, params) {
+			if !running && errors.Is(err, a2a.ErrTaskNotFound) {
+				break
+			}
+			running = true
+			if !yield(event, err) {
+				return
+			}
+		}
+		if running {
+			return
+		}
+		task, err := h.store.Get(ctx, params.ID)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		yield(task, nil)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adka2a
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+)
+
+type testTaskStore struct {
+	tasks map[a2a.TaskID]*a2a.Task
+}
+
+func (s *testTaskStore) Save(_ context.Context, task *a2a.Task) error {
+	s.tasks[task.ID] = task
+	return nil
+}
+
+func (s *testTaskStore) Get(_ context.Context, taskID a2a.TaskID) (*a2a.Task, error) {
+	task, ok := s.tasks[taskID]
+	if !ok {
+		return nil, a2a.ErrTaskNotFound
+	}
+	return task, nil
+}
+
+func TestWithStoredTasks(t *testing.T) {
+	event := session.NewEvent("invocation")
+	event.Content = genai.NewContentFromText("done", genai.RoleModel)
+	agent, err := newEventReplayAgent([]*session.Event{event}, nil)
+	if err != nil {
+		t.Fatalf("newEventReplayAgent() error = %v", err)
+	}
+	executor := NewExecutor(ExecutorConfig{
+		RunnerConfig: runner.Config{AppName: agent.Name(), Agent: agent, SessionService: session.InMemoryService()},
+	})
+	store := &testTaskStore{tasks: make(map[a2a.TaskID]*a2a.Task)}
+	handler := WithStoredTasks(a2asrv.NewHandler(executor, a2asrv.WithTaskStore(store)), store)
+	ctx := t.Context()
+
+	result, err := handler.OnSendMessage(ctx, &a2a.MessageSendParams{
+		Message: a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "hi"}),
+	})
+	if err != nil {
+		t.Fatalf("OnSendMessage() error = %v", err)
+	}
+	task, ok := result.(*a2a.Task)
+	if !ok {
+		t.Fatalf("OnSendMessage() result type = %T, want *a2a.Task", result)
+	}
+
+	var got []a2a.Event
+	for event, err := range handler.OnResubscribeToTask(ctx, &a2a.TaskIDParams{ID: task.ID}) {
+		if err != nil {
+			t.Fatalf("OnResubscribeToTask() error = %v", err)
+		}
+		got = append(got, event)
+	}
+	if diff := cmp.Diff([]a2a.Event{store.tasks[task.ID]}, got); diff != "" {
+		t.Errorf("OnResubscribeToTask() mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, err := range handler.OnResubscribeToTask(ctx, &a2a.TaskIDParams{ID: a2a.NewTaskID()}) {
+		if !errors.Is(err, a2a.ErrTaskNotFound) {
+			t.Errorf("OnResubscribeToTask() of unknown task error = %v, want %v", err, a2a.ErrTaskNotFound)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package taskstore provides an [a2asrv.TaskStore] persisting the A2A tasks
+// in a relational database, so that the tasks outlive the executions and the
+// server restarts.
+package taskstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"gorm.io/gorm"
+)
+
+// databaseStore is a database implementation of a2asrv.TaskStore.
+type databaseStore struct {
+	db *gorm.DB
+}
+
+// NewDatabaseStore creates a new [a2asrv.TaskStore] that uses a relational
+// database (e.g., PostgreSQL, SQLite) via the GORM library.
+//
+// It requires a [gorm.Dialector] to specify the database connection and
+// accepts optional [gorm.Option] values for further GORM configuration.
+func NewDatabaseStore(dialector gorm.Dialector, opts ...gorm.Option) (a2asrv.TaskStore, error) {
+	db, err := gorm.Open(dialector, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating database task store: %w", err)
+	}
+	return &databaseStore{db: db}, nil
+}
+
+// AutoMigrate runs the GORM auto-migration tool to ensure the database schema
+// matches the storage model of a store created by [NewDatabaseStore].
+func AutoMigrate(store a2asrv.TaskStore) error {
+	s, ok := store.(*databaseStore)
+	if !ok {
+		return fmt.Errorf("invalid task store type")
+	}
+	if err := s.db.AutoMigrate(&storageTask{}); err != nil {
+		return fmt.Errorf("auto migrate failed: %w", err)
+	}
+	return nil
+}
+
+// Save implements a2asrv.TaskStore.
+func (s *databaseStore) Save(ctx context.Context, task *a2a.Task) error {
+	if task == nil || task.ID == "" {
+		return fmt.Errorf("task ID is required")
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task %s: %w", task.ID, err)
+	}
+	st := &storageTask{
+		ID:         string(task.ID),
+		ContextID:  task.ContextID,
+		State:      string(task.Status.State),
+		Task:       string(data),
+		UpdateTime: time.Now(),
+	}
+	if err := s.db.WithContext(ctx).Save(st).Error; err != nil {
+		return fmt.Errorf("failed to save task %s: %w", task.ID, err)
+	}
+	return nil
+}
+
+// Get implements a2asrv.TaskStore.
+func (s *databaseStore) Get(ctx context.Context, taskID a2a.TaskID) (*a2a.Task, error) {
+	var st storageTask
+	err := s.db.WithContext(ctx).Where(&storageTask{ID: string(taskID)}).First(&st).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, a2a.ErrTaskNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task %s: %w", taskID, err)
+	}
+	var task a2a.Task
+	if err := json.Unmarshal([]byte(st.Task), &task); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task %s: %w", taskID, err)
+	}
+	return &task, nil
+}
+
+// storageTask corresponds to the 'a2a_tasks' table.
+type storageTask struct {
+	ID        string `gorm:"primaryKey;"`
+	ContextID string `gorm:"index;"`
+	State     string
+	// Task is the JSON encoding of the a2a.Task.
+	Task       string
+	UpdateTime time.Time
+}
+
+// TableName explicitly sets the table name for the storageTask struct.
+func (storageTask) TableName() string {
+	return "a2a_tasks"
+}
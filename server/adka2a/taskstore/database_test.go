@@ -0,0 +1,77 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package taskstore
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/google/go-cmp/cmp"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newStore(t *testing.T, path string) a2asrv.TaskStore {
+	t.Helper()
+	store, err := NewDatabaseStore(sqlite.Open(path), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("NewDatabaseStore() error = %v", err)
+	}
+	if err := AutoMigrate(store); err != nil {
+		t.Fatalf("AutoMigrate() error = %v", err)
+	}
+	return store
+}
+
+func TestDatabaseStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.db")
+	store := newStore(t, path)
+	ctx := t.Context()
+
+	task := &a2a.Task{
+		ID:        a2a.NewTaskID(),
+		ContextID: a2a.NewContextID(),
+		Status:    a2a.TaskStatus{State: a2a.TaskStateWorking},
+		History:   []*a2a.Message{a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "hi"})},
+	}
+	if err := store.Save(ctx, task); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	task.Status.State = a2a.TaskStateCompleted
+	task.Artifacts = []*a2a.Artifact{{ID: a2a.NewArtifactID(), Parts: a2a.ContentParts{a2a.TextPart{Text: "hello"}}}}
+	if err := store.Save(ctx, task); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	// The tasks survive reopening the database.
+	got, err := newStore(t, path).Get(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if diff := cmp.Diff(task, got); diff != "" {
+		t.Errorf("Get() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := store.Get(ctx, a2a.NewTaskID()); !errors.Is(err, a2a.ErrTaskNotFound) {
+		t.Errorf("Get() of unknown task error = %v, want %v", err, a2a.ErrTaskNotFound)
+	}
+	if err := store.Save(ctx, &a2a.Task{}); err == nil {
+		t.Errorf("Save() of task without ID succeeded, want error")
+	}
+}
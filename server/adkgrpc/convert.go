@@ -0,0 +1,174 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adkgrpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+
+	"google.golang.org/genai"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"google.golang.org/adk/server/adkgrpc/adkpb"
+	"google.golang.org/adk/session"
+)
+
+// toStruct converts m to a Struct. The values are converted through their
+// JSON encoding, so that any JSON serializable value is supported.
+func toStruct(m map[string]any) (*structpb.Struct, error) {
+	if m == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	s := &structpb.Struct{}
+	if err := s.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func toSession(s session.Session, withEvents bool) (*adkpb.Session, error) {
+	state, err := toStruct(maps.Collect(s.State().All()))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "convert session state: %v", err)
+	}
+	pb := &adkpb.Session{
+		AppName:        s.AppName(),
+		UserId:         s.UserID(),
+		Id:             s.ID(),
+		State:          state,
+		LastUpdateTime: timestamppb.New(s.LastUpdateTime()),
+	}
+	if !withEvents {
+		return pb, nil
+	}
+	for event := range s.Events().All() {
+		pbEvent, err := toEvent(event)
+		if err != nil {
+			return nil, err
+		}
+		pb.Events = append(pb.Events, pbEvent)
+	}
+	return pb, nil
+}
+
+func toEvent(e *session.Event) (*adkpb.Event, error) {
+	content, err := toContent(e.Content)
+	if err != nil {
+		return nil, err
+	}
+	stateDelta, err := toStruct(e.Actions.StateDelta)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "convert state delta: %v", err)
+	}
+	return &adkpb.Event{
+		Id:                 e.ID,
+		InvocationId:       e.InvocationID,
+		Author:             e.Author,
+		Branch:             e.Branch,
+		Timestamp:          timestamppb.New(e.Timestamp),
+		Content:            content,
+		Partial:            e.Partial,
+		TurnComplete:       e.TurnComplete,
+		Interrupted:        e.Interrupted,
+		ErrorCode:          e.ErrorCode,
+		ErrorMessage:       e.ErrorMessage,
+		LongRunningToolIds: e.LongRunningToolIDs,
+		Actions: &adkpb.EventActions{
+			StateDelta:        stateDelta,
+			ArtifactDelta:     e.Actions.ArtifactDelta,
+			SkipSummarization: e.Actions.SkipSummarization,
+			TransferToAgent:   e.Actions.TransferToAgent,
+			Escalate:          e.Actions.Escalate,
+		},
+	}, nil
+}
+
+func toContent(c *genai.Content) (*adkpb.Content, error) {
+	if c == nil {
+		return nil, nil
+	}
+	pb := &adkpb.Content{Role: c.Role}
+	for _, p := range c.Parts {
+		if p == nil {
+			continue
+		}
+		pbPart, err := toPart(p)
+		if err != nil {
+			return nil, err
+		}
+		pb.Parts = append(pb.Parts, pbPart)
+	}
+	return pb, nil
+}
+
+func toPart(p *genai.Part) (*adkpb.Part, error) {
+	pb := &adkpb.Part{Text: p.Text, Thought: p.Thought}
+	if p.InlineData != nil {
+		pb.InlineData = &adkpb.Blob{MimeType: p.InlineData.MIMEType, Data: p.InlineData.Data}
+	}
+	if p.FileData != nil {
+		pb.FileData = &adkpb.FileData{MimeType: p.FileData.MIMEType, FileUri: p.FileData.FileURI}
+	}
+	if fc := p.FunctionCall; fc != nil {
+		args, err := toStruct(fc.Args)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "convert arguments of function call %s: %v", fc.Name, err)
+		}
+		pb.FunctionCall = &adkpb.FunctionCall{Id: fc.ID, Name: fc.Name, Args: args}
+	}
+	if fr := p.FunctionResponse; fr != nil {
+		response, err := toStruct(fr.Response)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "convert response of function %s: %v", fr.Name, err)
+		}
+		pb.FunctionResponse = &adkpb.FunctionResponse{Id: fr.ID, Name: fr.Name, Response: response}
+	}
+	return pb, nil
+}
+
+func fromContent(pb *adkpb.Content) (*genai.Content, error) {
+	c := &genai.Content{Role: pb.Role}
+	if c.Role == "" {
+		c.Role = genai.RoleUser
+	}
+	for _, p := range pb.Parts {
+		part := &genai.Part{Text: p.Text, Thought: p.Thought}
+		if p.InlineData != nil {
+			part.InlineData = &genai.Blob{MIMEType: p.InlineData.MimeType, Data: p.InlineData.Data}
+		}
+		if p.FileData != nil {
+			part.FileData = &genai.FileData{MIMEType: p.FileData.MimeType, FileURI: p.FileData.FileUri}
+		}
+		if p.FunctionCall != nil {
+			part.FunctionCall = &genai.FunctionCall{ID: p.FunctionCall.Id, Name: p.FunctionCall.Name, Args: p.FunctionCall.Args.AsMap()}
+		}
+		if p.FunctionResponse != nil {
+			part.FunctionResponse = &genai.FunctionResponse{ID: p.FunctionResponse.Id, Name: p.FunctionResponse.Name, Response: p.FunctionResponse.Response.AsMap()}
+		}
+		c.Parts = append(c.Parts, part)
+	}
+	if len(c.Parts) == 0 {
+		return nil, fmt.Errorf("the content has no parts")
+	}
+	return c, nil
+}
@@ -0,0 +1,311 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package adkgrpc provides a gRPC server for the ADK API. It mirrors the ADK
+// REST API of package adkrest with typed messages and a server-streaming Run
+// method, defined by the AgentService of package adkpb.
+//
+// The server can be served on its own listener, or on the port of the REST
+// API by sending it the HTTP/2 requests with a gRPC content type:
+//
+//	grpcServer := adkgrpc.NewServer(config)
+//	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//		if r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+//			grpcServer.ServeHTTP(w, r)
+//			return
+//		}
+//		restHandler.ServeHTTP(w, r)
+//	})
+package adkgrpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/cmd/launcher"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/server/adkgrpc/adkpb"
+	"google.golang.org/adk/server/httpauth"
+	"google.golang.org/adk/server/ratelimit"
+	"google.golang.org/adk/session"
+)
+
+// NewServer creates a gRPC server serving the AgentService for the agents
+// and the services of config. The runs are limited by config.RateLimit, if
+// set.
+func NewServer(config *launcher.Config, opts ...grpc.ServerOption) *grpc.Server {
+	if config.RateLimit != nil {
+		opts = append([]grpc.ServerOption{grpc.ChainStreamInterceptor(ratelimit.StreamServerInterceptor(*config.RateLimit))}, opts...)
+	}
+	s := grpc.NewServer(opts...)
+	Register(s, config)
+	return s
+}
+
+// Register registers the AgentService for the agents and the services of
+// config on s. The runs are not limited by config.RateLimit unless s uses
+// the interceptor of ratelimit.StreamServerInterceptor.
+func Register(s grpc.ServiceRegistrar, config *launcher.Config) {
+	adkpb.RegisterAgentServiceServer(s, &service{config: config})
+}
+
+type service struct {
+	adkpb.UnimplementedAgentServiceServer
+	config *launcher.Config
+}
+
+// authorize checks that the authenticated principal, if any, can access
+// the data of userID. method is the HTTP method of the equivalent REST
+// request, so that the authorizers apply the same rules to both APIs.
+func (s *service) authorize(ctx context.Context, appName, userID, method string) error {
+	p := httpauth.FromContext(ctx)
+	if p == nil {
+		return nil
+	}
+	authorizer := s.config.Authorizer
+	if authorizer == nil {
+		authorizer = httpauth.UserAuthorizer()
+	}
+	if err := authorizer.Authorize(ctx, p, httpauth.AccessRequest{AppName: appName, UserID: userID, Method: method}); err != nil {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return nil
+}
+
+func (s *service) ListApps(ctx context.Context, req *adkpb.ListAppsRequest) (*adkpb.ListAppsResponse, error) {
+	return &adkpb.ListAppsResponse{Apps: s.config.AgentLoader.ListAgents()}, nil
+}
+
+func (s *service) CreateSession(ctx context.Context, req *adkpb.CreateSessionRequest) (*adkpb.Session, error) {
+	if err := s.authorize(ctx, req.AppName, req.UserId, http.MethodPost); err != nil {
+		return nil, err
+	}
+	resp, err := s.config.SessionService.Create(ctx, &session.CreateRequest{
+		AppName:   req.AppName,
+		UserID:    req.UserId,
+		SessionID: req.SessionId,
+		State:     req.State.AsMap(),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "create session: %v", err)
+	}
+	return toSession(resp.Session, true)
+}
+
+func (s *service) GetSession(ctx context.Context, req *adkpb.GetSessionRequest) (*adkpb.Session, error) {
+	if err := s.authorize(ctx, req.AppName, req.UserId, http.MethodGet); err != nil {
+		return nil, err
+	}
+	stored, err := s.getSession(ctx, req.AppName, req.UserId, req.SessionId)
+	if err != nil {
+		return nil, err
+	}
+	return toSession(stored, true)
+}
+
+func (s *service) getSession(ctx context.Context, appName, userID, sessionID string) (session.Session, error) {
+	resp, err := s.config.SessionService.Get(ctx, &session.GetRequest{
+		AppName:   appName,
+		UserID:    userID,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "get session: %v", err)
+	}
+	return resp.Session, nil
+}
+
+func (s *service) ListSessions(ctx context.Context, req *adkpb.ListSessionsRequest) (*adkpb.ListSessionsResponse, error) {
+	if err := s.authorize(ctx, req.AppName, req.UserId, http.MethodGet); err != nil {
+		return nil, err
+	}
+	resp, err := s.config.SessionService.List(ctx, &session.ListRequest{AppName: req.AppName, UserID: req.UserId})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "list sessions: %v", err)
+	}
+	sessions := make([]*adkpb.Session, 0, len(resp.Sessions))
+	for _, stored := range resp.Sessions {
+		sess, err := toSession(stored, false)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, sess)
+	}
+	return &adkpb.ListSessionsResponse{Sessions: sessions}, nil
+}
+
+func (s *service) DeleteSession(ctx context.Context, req *adkpb.DeleteSessionRequest) (*emptypb.Empty, error) {
+	if err := s.authorize(ctx, req.AppName, req.UserId, http.MethodDelete); err != nil {
+		return nil, err
+	}
+	err := s.config.SessionService.Delete(ctx, &session.DeleteRequest{
+		AppName:   req.AppName,
+		UserID:    req.UserId,
+		SessionID: req.SessionId,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "delete session: %v", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *service) artifactService() (artifact.Service, error) {
+	if s.config.ArtifactService == nil {
+		return nil, status.Error(codes.Unimplemented, "the artifact service is not configured")
+	}
+	return s.config.ArtifactService, nil
+}
+
+// artifactCode returns the status code of an error of the artifact service,
+// def for the errors which aren't caused by the request.
+func artifactCode(err error, def codes.Code) codes.Code {
+	if errors.Is(err, artifact.ErrInvalidFileName) {
+		return codes.InvalidArgument
+	}
+	return def
+}
+
+func (s *service) ListArtifacts(ctx context.Context, req *adkpb.ListArtifactsRequest) (*adkpb.ListArtifactsResponse, error) {
+	if err := s.authorize(ctx, req.AppName, req.UserId, http.MethodGet); err != nil {
+		return nil, err
+	}
+	artifacts, err := s.artifactService()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := artifacts.List(ctx, &artifact.ListRequest{
+		AppName:   req.AppName,
+		UserID:    req.UserId,
+		SessionID: req.SessionId,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "list artifacts: %v", err)
+	}
+	return &adkpb.ListArtifactsResponse{FileNames: resp.FileNames}, nil
+}
+
+func (s *service) LoadArtifact(ctx context.Context, req *adkpb.LoadArtifactRequest) (*adkpb.Part, error) {
+	if err := s.authorize(ctx, req.AppName, req.UserId, http.MethodGet); err != nil {
+		return nil, err
+	}
+	artifacts, err := s.artifactService()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := artifacts.Load(ctx, &artifact.LoadRequest{
+		AppName:   req.AppName,
+		UserID:    req.UserId,
+		SessionID: req.SessionId,
+		FileName:  req.FileName,
+		Version:   req.Version,
+	})
+	if err != nil {
+		return nil, status.Errorf(artifactCode(err, codes.NotFound), "load artifact: %v", err)
+	}
+	return toPart(resp.Part)
+}
+
+func (s *service) DeleteArtifact(ctx context.Context, req *adkpb.DeleteArtifactRequest) (*emptypb.Empty, error) {
+	if err := s.authorize(ctx, req.AppName, req.UserId, http.MethodDelete); err != nil {
+		return nil, err
+	}
+	artifacts, err := s.artifactService()
+	if err != nil {
+		return nil, err
+	}
+	err = artifacts.Delete(ctx, &artifact.DeleteRequest{
+		AppName:   req.AppName,
+		UserID:    req.UserId,
+		SessionID: req.SessionId,
+		FileName:  req.FileName,
+	})
+	if err != nil {
+		return nil, status.Errorf(artifactCode(err, codes.Internal), "delete artifact: %v", err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *service) Run(req *adkpb.RunRequest, stream grpc.ServerStreamingServer[adkpb.Event]) error {
+	ctx := stream.Context()
+	if err := s.authorize(ctx, req.AppName, req.UserId, http.MethodPost); err != nil {
+		return err
+	}
+	if req.NewMessage == nil {
+		return status.Error(codes.InvalidArgument, "new_message is required")
+	}
+	stored, err := s.getSession(ctx, req.AppName, req.UserId, req.SessionId)
+	if err != nil {
+		return err
+	}
+	msg, err := fromContent(req.NewMessage)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "new_message: %v", err)
+	}
+	a, err := s.config.AgentLoader.LoadAgent(req.AppName)
+	if err != nil {
+		return status.Errorf(codes.NotFound, "load agent: %v", err)
+	}
+	r, err := runner.New(runner.Config{
+		AppName:         req.AppName,
+		Agent:           a,
+		SessionService:  s.config.SessionService,
+		ArtifactService: s.config.ArtifactService,
+		MemoryService:   s.config.MemoryService,
+		// Concurrent requests for a session are rejected instead of
+		// blocking the client, as in the REST API.
+		SessionConcurrency: runner.RejectConcurrentInvocations,
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "create runner: %v", err)
+	}
+
+	if stateDelta := req.StateDelta.AsMap(); len(stateDelta) > 0 {
+		event := session.NewEvent("p-" + uuid.NewString())
+		event.Author = "user"
+		event.Actions.StateDelta = stateDelta
+		if err := s.config.SessionService.AppendEvent(ctx, stored, event); err != nil {
+			return status.Errorf(codes.Internal, "update state: %v", err)
+		}
+	}
+
+	streamingMode := agent.StreamingModeNone
+	if req.Streaming {
+		streamingMode = agent.StreamingModeSSE
+	}
+	for event, err := range r.Run(ctx, req.UserId, req.SessionId, msg, agent.RunConfig{StreamingMode: streamingMode}) {
+		if errors.Is(err, runner.ErrInvocationInProgress) {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "run agent: %v", err)
+		}
+		pbEvent, err := toEvent(event)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(pbEvent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
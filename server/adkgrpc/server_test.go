@@ -0,0 +1,233 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adkgrpc_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/testing/protocmp"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/cmd/launcher"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/server/adkgrpc"
+	"google.golang.org/adk/server/adkgrpc/adkpb"
+	"google.golang.org/adk/server/httpauth"
+	"google.golang.org/adk/session"
+)
+
+// newClient serves the gRPC server of config over HTTP/2 without TLS, as
+// the web launcher does, and returns a client of the server. The requests
+// are authenticated as the user named by the "user" metadata, if any.
+func newClient(t *testing.T, config *launcher.Config) adkpb.AgentServiceClient {
+	t.Helper()
+	grpcServer := adkgrpc.NewServer(config)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user := r.Header.Get("user"); user != "" {
+			r = r.WithContext(httpauth.ToContext(r.Context(), &httpauth.Principal{Subject: user}))
+		}
+		grpcServer.ServeHTTP(w, r)
+	})
+	srv := httptest.NewUnstartedServer(handler)
+	srv.Config.Protocols = new(http.Protocols)
+	srv.Config.Protocols.SetUnencryptedHTTP2(true)
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	conn, err := grpc.NewClient(strings.TrimPrefix(srv.URL, "http://"), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return adkpb.NewAgentServiceClient(conn)
+}
+
+func newConfig(t *testing.T, m *testutil.MockModel) *launcher.Config {
+	t.Helper()
+	a, err := llmagent.New(llmagent.Config{Name: "app", Model: m})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &launcher.Config{
+		SessionService:  session.InMemoryService(),
+		ArtifactService: artifact.InMemoryService(),
+		AgentLoader:     agent.NewSingleLoader(a),
+	}
+}
+
+func TestRun(t *testing.T) {
+	ctx := t.Context()
+	m := &testutil.MockModel{Responses: []*genai.Content{genai.NewContentFromText("hello", genai.RoleModel)}}
+	client := newClient(t, newConfig(t, m))
+
+	apps, err := client.ListApps(ctx, &adkpb.ListAppsRequest{})
+	if err != nil {
+		t.Fatalf("ListApps() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"app"}, apps.Apps); diff != "" {
+		t.Errorf("ListApps() mismatch (-want +got):\n%s", diff)
+	}
+
+	created, err := client.CreateSession(ctx, &adkpb.CreateSessionRequest{AppName: "app", UserId: "user", SessionId: "s"})
+	if err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	stream, err := client.Run(ctx, &adkpb.RunRequest{
+		AppName:    "app",
+		UserId:     "user",
+		SessionId:  created.Id,
+		NewMessage: &adkpb.Content{Parts: []*adkpb.Part{{Text: "hi"}}},
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	var texts []string
+	for {
+		event, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		for _, p := range event.GetContent().GetParts() {
+			texts = append(texts, event.Author+": "+p.Text)
+		}
+	}
+	if diff := cmp.Diff([]string{"app: hello"}, texts); diff != "" {
+		t.Errorf("streamed events mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err := client.GetSession(ctx, &adkpb.GetSessionRequest{AppName: "app", UserId: "user", SessionId: "s"})
+	if err != nil {
+		t.Fatalf("GetSession() error = %v", err)
+	}
+	var contents []*adkpb.Content
+	for _, e := range got.Events {
+		contents = append(contents, e.Content)
+	}
+	want := []*adkpb.Content{
+		{Role: "user", Parts: []*adkpb.Part{{Text: "hi"}}},
+		{Role: "model", Parts: []*adkpb.Part{{Text: "hello"}}},
+	}
+	if diff := cmp.Diff(want, contents, protocmp.Transform()); diff != "" {
+		t.Errorf("session events mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	ctx := t.Context()
+	client := newClient(t, newConfig(t, &testutil.MockModel{}))
+	if _, err := client.CreateSession(ctx, &adkpb.CreateSessionRequest{AppName: "app", UserId: "user", SessionId: "s"}); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+	msg := &adkpb.Content{Parts: []*adkpb.Part{{Text: "hi"}}}
+
+	for _, tc := range []struct {
+		name     string
+		req      *adkpb.RunRequest
+		wantCode codes.Code
+	}{
+		{name: "no message", req: &adkpb.RunRequest{AppName: "app", UserId: "user", SessionId: "s"}, wantCode: codes.InvalidArgument},
+		{name: "unknown session", req: &adkpb.RunRequest{AppName: "app", UserId: "user", SessionId: "other", NewMessage: msg}, wantCode: codes.NotFound},
+		{name: "unknown app", req: &adkpb.RunRequest{AppName: "other", UserId: "user", SessionId: "s", NewMessage: msg}, wantCode: codes.NotFound},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			stream, err := client.Run(ctx, tc.req)
+			if err == nil {
+				_, err = stream.Recv()
+			}
+			if got := status.Code(err); got != tc.wantCode {
+				t.Errorf("Run() error = %v, want code %v", err, tc.wantCode)
+			}
+		})
+	}
+}
+
+func TestAuthorization(t *testing.T) {
+	ctx := t.Context()
+	config := newConfig(t, &testutil.MockModel{})
+	client := newClient(t, config)
+	if _, err := client.CreateSession(ctx, &adkpb.CreateSessionRequest{AppName: "app", UserId: "alice", SessionId: "s"}); err != nil {
+		t.Fatalf("CreateSession() error = %v", err)
+	}
+
+	for _, tc := range []struct {
+		name, user string
+		wantCode   codes.Code
+	}{
+		{name: "same user", user: "alice", wantCode: codes.OK},
+		{name: "other user", user: "bob", wantCode: codes.PermissionDenied},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := metadata.AppendToOutgoingContext(ctx, "user", tc.user)
+			_, err := client.GetSession(ctx, &adkpb.GetSessionRequest{AppName: "app", UserId: "alice", SessionId: "s"})
+			if got := status.Code(err); got != tc.wantCode {
+				t.Errorf("GetSession() error = %v, want code %v", err, tc.wantCode)
+			}
+		})
+	}
+}
+
+func TestArtifacts(t *testing.T) {
+	ctx := t.Context()
+	config := newConfig(t, &testutil.MockModel{})
+	client := newClient(t, config)
+	if _, err := config.ArtifactService.Save(ctx, &artifact.SaveRequest{AppName: "app", UserID: "user", SessionID: "s", FileName: "f.txt", Part: genai.NewPartFromText("v1")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.ArtifactService.Save(ctx, &artifact.SaveRequest{AppName: "app", UserID: "user", SessionID: "s", FileName: "f.txt", Part: genai.NewPartFromText("v2")}); err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := client.ListArtifacts(ctx, &adkpb.ListArtifactsRequest{AppName: "app", UserId: "user", SessionId: "s"})
+	if err != nil {
+		t.Fatalf("ListArtifacts() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"f.txt"}, list.FileNames); diff != "" {
+		t.Errorf("ListArtifacts() mismatch (-want +got):\n%s", diff)
+	}
+	for version, want := range map[int64]string{0: "v2", 1: "v1"} {
+		part, err := client.LoadArtifact(ctx, &adkpb.LoadArtifactRequest{AppName: "app", UserId: "user", SessionId: "s", FileName: "f.txt", Version: version})
+		if err != nil {
+			t.Fatalf("LoadArtifact(%d) error = %v", version, err)
+		}
+		if part.Text != want {
+			t.Errorf("LoadArtifact(%d) = %q, want %q", version, part.Text, want)
+		}
+	}
+
+	if _, err := client.DeleteArtifact(ctx, &adkpb.DeleteArtifactRequest{AppName: "app", UserId: "user", SessionId: "s", FileName: "f.txt"}); err != nil {
+		t.Fatalf("DeleteArtifact() error = %v", err)
+	}
+	_, err = client.LoadArtifact(ctx, &adkpb.LoadArtifactRequest{AppName: "app", UserId: "user", SessionId: "s", FileName: "f.txt"})
+	if got := status.Code(err); got != codes.NotFound {
+		t.Errorf("LoadArtifact() after delete error = %v, want code %v", err, codes.NotFound)
+	}
+}
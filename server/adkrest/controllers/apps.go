@@ -15,19 +15,36 @@
 package controllers
 
 import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 
+	"github.com/gorilla/mux"
+
 	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/agentconfig"
 )
 
+// maxAppDefinitionSize bounds the size of the agent definitions registered
+// through the API.
+const maxAppDefinitionSize = 1 << 20
+
 // AppsAPIController is the controller for the Apps API.
 type AppsAPIController struct {
 	agentLoader agent.Loader
+	registry    *agentconfig.Registry
 }
 
-// NewAppsAPIController creates a controller for Apps API.
-func NewAppsAPIController(agentLoader agent.Loader) *AppsAPIController {
-	return &AppsAPIController{agentLoader: agentLoader}
+// NewAppsAPIController creates a controller for Apps API. The agents
+// registered through the API are built with registry, which resolves their
+// tools and models. If registry is nil, agentconfig.NewRegistry() is used.
+func NewAppsAPIController(agentLoader agent.Loader, registry *agentconfig.Registry) *AppsAPIController {
+	if registry == nil {
+		registry = agentconfig.NewRegistry()
+	}
+	return &AppsAPIController{agentLoader: agentLoader, registry: registry}
 }
 
 // ListAppsHandler handles listing all loaded agents.
@@ -35,3 +52,86 @@ func (c *AppsAPIController) ListAppsHandler(rw http.ResponseWriter, req *http.Re
 	apps := c.agentLoader.ListAgents()
 	EncodeJSONResponse(apps, http.StatusOK, rw)
 }
+
+// ReloadAppsHandler rebuilds the agents of a reloadable agent loader, so that
+// the changes of their definitions are used by the next invocations. It
+// returns the apps after the reload. It is authorized like
+// RegisterAppHandler.
+func (c *AppsAPIController) ReloadAppsHandler(rw http.ResponseWriter, req *http.Request) error {
+	loader, ok := c.agentLoader.(agent.ReloadableLoader)
+	if !ok {
+		return newStatusError(errors.New("the agents can't be reloaded"), http.StatusNotImplemented)
+	}
+	if err := checkAdmin(req, ""); err != nil {
+		return err
+	}
+	if err := loader.Reload(req.Context()); err != nil {
+		return newStatusError(fmt.Errorf("reload agents: %w", err), http.StatusInternalServerError)
+	}
+	EncodeJSONResponse(loader.ListAgents(), http.StatusOK, rw)
+	return nil
+}
+
+// RegisterAppHandler serves a new app, built from the declarative agent
+// definition in YAML or JSON of the request body, see agentconfig. The
+// sub-agents must be defined inline, and remote A2A agents are referenced by
+// the URL of their agent card. The app is named after the name query
+// parameter, or after the root agent. It returns the apps after the
+// registration.
+//
+// The registration is an admin request: it is refused unless the request is
+// authenticated and authorized as such, e.g. by the admin roles of
+// httpauth.UserAuthorizer.
+func (c *AppsAPIController) RegisterAppHandler(rw http.ResponseWriter, req *http.Request) error {
+	loader, ok := c.agentLoader.(agent.MutableLoader)
+	if !ok {
+		return newStatusError(errors.New("the apps can't be registered"), http.StatusNotImplemented)
+	}
+	// The caller is checked before the agent is built, and again with the
+	// name of the app once it is known.
+	if err := checkAdmin(req, req.URL.Query().Get("name")); err != nil {
+		return err
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(rw, req.Body, maxAppDefinitionSize))
+	if err != nil {
+		return newStatusError(fmt.Errorf("read request: %w", err), http.StatusBadRequest)
+	}
+	a, err := agentconfig.BuildInline(req.Context(), data, c.registry)
+	if err != nil {
+		return newStatusError(fmt.Errorf("build agent: %w", err), http.StatusBadRequest)
+	}
+	name := cmp.Or(req.URL.Query().Get("name"), a.Name())
+	if err := checkAdmin(req, name); err != nil {
+		return err
+	}
+	if err := loader.Register(name, a); err != nil {
+		if errors.Is(err, agent.ErrAgentExists) {
+			return newStatusError(err, http.StatusConflict)
+		}
+		return newStatusError(fmt.Errorf("register app: %w", err), http.StatusBadRequest)
+	}
+	EncodeJSONResponse(loader.ListAgents(), http.StatusCreated, rw)
+	return nil
+}
+
+// UnregisterAppHandler stops serving an app registered with
+// RegisterAppHandler. The sessions of the app are kept. It is authorized
+// like RegisterAppHandler.
+func (c *AppsAPIController) UnregisterAppHandler(rw http.ResponseWriter, req *http.Request) error {
+	loader, ok := c.agentLoader.(agent.MutableLoader)
+	if !ok {
+		return newStatusError(errors.New("the apps can't be unregistered"), http.StatusNotImplemented)
+	}
+	name := mux.Vars(req)["app_name"]
+	if err := checkAdmin(req, name); err != nil {
+		return err
+	}
+	if err := loader.Unregister(name); err != nil {
+		if errors.Is(err, agent.ErrAgentNotFound) {
+			return newStatusError(err, http.StatusNotFound)
+		}
+		return newStatusError(fmt.Errorf("unregister app: %w", err), http.StatusInternalServerError)
+	}
+	EncodeJSONResponse(nil, http.StatusOK, rw)
+	return nil
+}
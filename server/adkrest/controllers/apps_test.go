@@ -0,0 +1,252 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/server/adkrest/controllers"
+	"google.golang.org/adk/server/httpauth"
+)
+
+type reloadableLoader struct {
+	agent.Loader
+	err     error
+	reloads int
+}
+
+func (l *reloadableLoader) Reload(ctx context.Context) error {
+	l.reloads++
+	return l.err
+}
+
+func (l *reloadableLoader) Watch(ctx context.Context, interval time.Duration) {}
+
+// admin is a principal allowed the admin requests by adminHandler.
+var admin = &httpauth.Principal{Subject: "root", Roles: []string{"admin"}}
+
+// adminHandler serves the requests with the admin role of
+// httpauth.UserAuthorizer, as the principal p if not nil.
+func adminHandler(handler http.HandlerFunc, p *httpauth.Principal) http.Handler {
+	authorized := controllers.Authorize(httpauth.UserAuthorizer("admin"))(handler)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if p != nil {
+			r = r.WithContext(httpauth.ToContext(r.Context(), p))
+		}
+		authorized.ServeHTTP(w, r)
+	})
+}
+
+func TestReloadAppsHandler(t *testing.T) {
+	a, err := agent.New(agent.Config{Name: "app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name        string
+		loader      agent.Loader
+		principal   *httpauth.Principal
+		wantStatus  int
+		wantApps    []string
+		wantReloads int
+	}{
+		{
+			name:       "not reloadable",
+			loader:     agent.NewSingleLoader(a),
+			principal:  admin,
+			wantStatus: http.StatusNotImplemented,
+		},
+		{
+			name:        "reloaded",
+			loader:      &reloadableLoader{Loader: agent.NewSingleLoader(a)},
+			principal:   admin,
+			wantStatus:  http.StatusOK,
+			wantApps:    []string{"app"},
+			wantReloads: 1,
+		},
+		{
+			name:        "reload error",
+			loader:      &reloadableLoader{Loader: agent.NewSingleLoader(a), err: errors.New("invalid definition")},
+			principal:   admin,
+			wantStatus:  http.StatusInternalServerError,
+			wantReloads: 1,
+		},
+		{
+			name:       "unauthenticated",
+			loader:     &reloadableLoader{Loader: agent.NewSingleLoader(a)},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "not admin",
+			loader:     &reloadableLoader{Loader: agent.NewSingleLoader(a)},
+			principal:  &httpauth.Principal{Subject: "alice"},
+			wantStatus: http.StatusForbidden,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := adminHandler(controllers.NewErrorHandler(controllers.NewAppsAPIController(tc.loader, nil).ReloadAppsHandler), tc.principal)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/reload-apps", nil))
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tc.wantStatus)
+			}
+			if l, ok := tc.loader.(*reloadableLoader); ok && l.reloads != tc.wantReloads {
+				t.Errorf("reloads = %d, want %d", l.reloads, tc.wantReloads)
+			}
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+			var got []string
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantApps, got); diff != "" {
+				t.Errorf("apps mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRegisterAppHandler(t *testing.T) {
+	a, err := agent.New(agent.Config{Name: "app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name       string
+		loader     agent.Loader
+		query      string
+		body       string
+		principal  *httpauth.Principal
+		wantStatus int
+		wantApps   []string
+	}{
+		{
+			name:       "registered",
+			loader:     agent.NewMutableLoader(agent.NewSingleLoader(a)),
+			body:       "name: billing\nagent_class: LoopAgent\n",
+			principal:  admin,
+			wantStatus: http.StatusCreated,
+			wantApps:   []string{"app", "billing"},
+		},
+		{
+			name:       "named by query",
+			loader:     agent.NewMutableLoader(nil),
+			query:      "?name=payments",
+			body:       "name: billing\nagent_class: RemoteA2aAgent\nagent_card: https://billing.example.com/card.json\n",
+			principal:  admin,
+			wantStatus: http.StatusCreated,
+			wantApps:   []string{"payments"},
+		},
+		{
+			name:       "conflict",
+			loader:     agent.NewMutableLoader(agent.NewSingleLoader(a)),
+			body:       "name: app\nagent_class: LoopAgent\n",
+			principal:  admin,
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "invalid definition",
+			loader:     agent.NewMutableLoader(nil),
+			body:       "name: billing\nagent_class: MagicAgent\n",
+			principal:  admin,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unauthenticated",
+			loader:     agent.NewMutableLoader(nil),
+			body:       "name: billing\nagent_class: LoopAgent\n",
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "not admin",
+			loader:     agent.NewMutableLoader(nil),
+			body:       "name: billing\nagent_class: LoopAgent\n",
+			principal:  &httpauth.Principal{Subject: "alice"},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "not mutable",
+			loader:     agent.NewSingleLoader(a),
+			body:       "name: billing\nagent_class: LoopAgent\n",
+			wantStatus: http.StatusNotImplemented,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := adminHandler(controllers.NewErrorHandler(controllers.NewAppsAPIController(tc.loader, nil).RegisterAppHandler), tc.principal)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/apps"+tc.query, strings.NewReader(tc.body)))
+
+			if rec.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tc.wantStatus, rec.Body)
+			}
+			if tc.wantStatus != http.StatusCreated {
+				return
+			}
+			var got []string
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if diff := cmp.Diff(tc.wantApps, got); diff != "" {
+				t.Errorf("apps mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUnregisterAppHandler(t *testing.T) {
+	a, err := agent.New(agent.Config{Name: "app"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	loader := agent.NewMutableLoader(agent.NewSingleLoader(a))
+	if err := loader.Register("billing", a); err != nil {
+		t.Fatal(err)
+	}
+	unregister := controllers.NewErrorHandler(controllers.NewAppsAPIController(loader, nil).UnregisterAppHandler)
+	for _, tc := range []struct {
+		app        string
+		principal  *httpauth.Principal
+		wantStatus int
+	}{
+		{app: "billing", wantStatus: http.StatusForbidden},
+		{app: "billing", principal: &httpauth.Principal{Subject: "alice"}, wantStatus: http.StatusForbidden},
+		{app: "billing", principal: admin, wantStatus: http.StatusOK},
+		{app: "billing", principal: admin, wantStatus: http.StatusNotFound},
+		{app: "app", principal: admin, wantStatus: http.StatusNotFound},
+	} {
+		req := mux.SetURLVars(httptest.NewRequest(http.MethodDelete, "/apps/"+tc.app, nil), map[string]string{"app_name": tc.app})
+		rec := httptest.NewRecorder()
+		adminHandler(unregister, tc.principal).ServeHTTP(rec, req)
+		if rec.Code != tc.wantStatus {
+			t.Errorf("DELETE /apps/%s status = %d, want %d", tc.app, rec.Code, tc.wantStatus)
+		}
+	}
+	if diff := cmp.Diff([]string{"app"}, loader.ListAgents()); diff != "" {
+		t.Errorf("ListAgents() mismatch (-want +got):\n%s", diff)
+	}
+}
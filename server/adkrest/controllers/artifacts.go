@@ -15,22 +15,42 @@
 package controllers
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
 	"net/http"
+	"path"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
+	"google.golang.org/genai"
 
 	"google.golang.org/adk/artifact"
 	"google.golang.org/adk/server/adkrest/internal/models"
 )
 
+// DefaultMaxArtifactSize is the size limit of the artifact uploads when none
+// is configured.
+const DefaultMaxArtifactSize = 64 << 20
+
 // ArtifactsAPIController is the controller for the Artifacts API.
 type ArtifactsAPIController struct {
 	artifactService artifact.Service
+	maxArtifactSize int64
 }
 
-func NewArtifactsAPIController(artifactService artifact.Service) *ArtifactsAPIController {
-	return &ArtifactsAPIController{artifactService: artifactService}
+// NewArtifactsAPIController creates the controller for the Artifacts API.
+// The uploads larger than maxArtifactSize bytes are rejected. If
+// maxArtifactSize is zero, DefaultMaxArtifactSize is used.
+func NewArtifactsAPIController(artifactService artifact.Service, maxArtifactSize int64) *ArtifactsAPIController {
+	if maxArtifactSize <= 0 {
+		maxArtifactSize = DefaultMaxArtifactSize
+	}
+	return &ArtifactsAPIController{artifactService: artifactService, maxArtifactSize: maxArtifactSize}
 }
 
 // ListArtifactsHandler lists all the artifact filenames within a session.
@@ -98,10 +118,10 @@ func (c *ArtifactsAPIController) LoadArtifactHandler(rw http.ResponseWriter, req
 
 	resp, err := c.artifactService.Load(req.Context(), loadReq)
 	if err != nil {
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		http.Error(rw, err.Error(), artifactErrorStatus(err))
 		return
 	}
-	EncodeJSONResponse(resp.Part, http.StatusOK, rw)
+	writeArtifact(rw, req, artifactName, resp.Part)
 }
 
 // LoadArtifactVersionHandler gets an artifact from the artifact service storage with specified version.
@@ -144,10 +164,156 @@ func (c *ArtifactsAPIController) LoadArtifactVersionHandler(rw http.ResponseWrit
 
 	resp, err := c.artifactService.Load(req.Context(), loadReq)
 	if err != nil {
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		http.Error(rw, err.Error(), artifactErrorStatus(err))
+		return
+	}
+	writeArtifact(rw, req, artifactName, resp.Part)
+}
+
+// writeArtifact writes the loaded artifact to the response. By default the
+// part is encoded as JSON. With the "raw=true" query parameter the content is
+// served as is with its MIME type, and Range requests are supported.
+//
+// The artifacts may come from users or models, so the raw content is never
+// run by the browser: it is not sniffed, it is sandboxed, and only the
+// passive media types are displayed inline, the others are downloaded.
+func writeArtifact(rw http.ResponseWriter, req *http.Request, name string, part *genai.Part) {
+	raw, _ := strconv.ParseBool(req.URL.Query().Get("raw"))
+	if !raw {
+		EncodeJSONResponse(part, http.StatusOK, rw)
+		return
+	}
+	var data []byte
+	var mimeType string
+	switch {
+	case part == nil:
+		http.Error(rw, "artifact not found", http.StatusNotFound)
+		return
+	case part.InlineData != nil:
+		data, mimeType = part.InlineData.Data, part.InlineData.MIMEType
+	case part.Text != "":
+		data, mimeType = []byte(part.Text), "text/plain; charset=utf-8"
+	default:
+		http.Error(rw, "artifact has no raw content", http.StatusUnprocessableEntity)
+		return
+	}
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	disposition := "attachment"
+	if inlineMediaType(mimeType) {
+		disposition = "inline"
+	}
+	rw.Header().Set("Content-Type", mimeType)
+	rw.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": path.Base(name)}))
+	rw.Header().Set("X-Content-Type-Options", "nosniff")
+	rw.Header().Set("Content-Security-Policy", "sandbox")
+	http.ServeContent(rw, req, name, time.Time{}, bytes.NewReader(data))
+}
+
+// inlineMediaType reports whether the content of the MIME type can be
+// displayed by the browser without running scripts.
+func inlineMediaType(mimeType string) bool {
+	mediaType, _, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case mediaType == "text/plain":
+		return true
+	case mediaType == "image/svg+xml":
+		return false
+	}
+	kind, _, _ := strings.Cut(mediaType, "/")
+	return kind == "image" || kind == "audio" || kind == "video"
+}
+
+// maxArtifactMemory is the part of a multipart upload kept in memory, the
+// rest is stored in temporary files.
+const maxArtifactMemory = 32 << 20
+
+// SaveArtifactHandler uploads a new version of an artifact. The body is
+// either a JSON [models.SaveArtifactRequest] or a multipart form with the
+// content in the "file" field.
+func (c *ArtifactsAPIController) SaveArtifactHandler(rw http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	sessionID, err := models.SessionIDFromHTTPParameters(vars)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if sessionID.ID == "" {
+		http.Error(rw, "session_id parameter is required", http.StatusBadRequest)
+		return
+	}
+	artifactName := vars["artifact_name"]
+	if artifactName == "" {
+		http.Error(rw, "artifact_name parameter is required", http.StatusBadRequest)
 		return
 	}
-	EncodeJSONResponse(resp.Part, http.StatusOK, rw)
+	req.Body = http.MaxBytesReader(rw, req.Body, c.maxArtifactSize)
+	part, err := artifactPartFromRequest(req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if maxBytesErr := (*http.MaxBytesError)(nil); errors.As(err, &maxBytesErr) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		http.Error(rw, err.Error(), status)
+		return
+	}
+	resp, err := c.artifactService.Save(req.Context(), &artifact.SaveRequest{
+		AppName:   sessionID.AppName,
+		UserID:    sessionID.UserID,
+		SessionID: sessionID.ID,
+		FileName:  artifactName,
+		Part:      part,
+	})
+	if err != nil {
+		http.Error(rw, err.Error(), artifactErrorStatus(err))
+		return
+	}
+	EncodeJSONResponse(models.SaveArtifactResponse{Version: resp.Version}, http.StatusCreated, rw)
+}
+
+// artifactPartFromRequest reads the artifact content of an upload request.
+func artifactPartFromRequest(req *http.Request) (*genai.Part, error) {
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		if err := req.ParseMultipartForm(maxArtifactMemory); err != nil {
+			return nil, err
+		}
+		file, header, err := req.FormFile("file")
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		data, err := io.ReadAll(file)
+		if err != nil {
+			return nil, err
+		}
+		mimeType := header.Header.Get("Content-Type")
+		if mimeType == "" {
+			mimeType = http.DetectContentType(data)
+		}
+		return genai.NewPartFromBytes(data, mimeType), nil
+	}
+
+	var saveRequest models.SaveArtifactRequest
+	if err := json.NewDecoder(req.Body).Decode(&saveRequest); err != nil {
+		return nil, err
+	}
+	switch {
+	case saveRequest.InlineData != nil && saveRequest.Text != "":
+		return nil, errors.New("only one of inlineData and text can be set")
+	case saveRequest.InlineData != nil:
+		if saveRequest.InlineData.MIMEType == "" {
+			return nil, errors.New("inlineData.mimeType is required")
+		}
+		return &genai.Part{InlineData: saveRequest.InlineData}, nil
+	case saveRequest.Text != "":
+		return genai.NewPartFromText(saveRequest.Text), nil
+	}
+	return nil, errors.New("inlineData or text is required")
 }
 
 // DeleteArtifactHandler handles deleting an artifact.
@@ -174,8 +340,17 @@ func (c *ArtifactsAPIController) DeleteArtifactHandler(rw http.ResponseWriter, r
 		FileName:  artifactName,
 	})
 	if err != nil {
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		http.Error(rw, err.Error(), artifactErrorStatus(err))
 		return
 	}
 	EncodeJSONResponse(nil, http.StatusOK, rw)
 }
+
+// artifactErrorStatus returns the HTTP status of an error of the artifact
+// service.
+func artifactErrorStatus(err error) int {
+	if errors.Is(err, artifact.ErrInvalidFileName) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
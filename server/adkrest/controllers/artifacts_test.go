@@ -0,0 +1,363 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/server/adkrest/controllers"
+	"google.golang.org/adk/server/adkrest/internal/models"
+)
+
+func TestSaveArtifact(t *testing.T) {
+	multipartBody := func(contentType string, data []byte) (string, *bytes.Buffer) {
+		t.Helper()
+		var body bytes.Buffer
+		w := multipart.NewWriter(&body)
+		h := textproto.MIMEHeader{}
+		h.Set("Content-Disposition", `form-data; name="file"; filename="f"`)
+		if contentType != "" {
+			h.Set("Content-Type", contentType)
+		}
+		fw, err := w.CreatePart(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write(data)
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return w.FormDataContentType(), &body
+	}
+
+	tests := []struct {
+		name       string
+		body       func() (string, *bytes.Buffer)
+		wantStatus int
+		wantPart   *genai.Part
+	}{
+		{
+			name: "json inline data",
+			body: func() (string, *bytes.Buffer) {
+				return "application/json", bytes.NewBufferString(`{"inlineData": {"mimeType": "image/png", "data": "AQID"}}`)
+			},
+			wantStatus: http.StatusCreated,
+			wantPart:   genai.NewPartFromBytes([]byte{1, 2, 3}, "image/png"),
+		},
+		{
+			name: "json text",
+			body: func() (string, *bytes.Buffer) {
+				return "application/json", bytes.NewBufferString(`{"text": "hello"}`)
+			},
+			wantStatus: http.StatusCreated,
+			wantPart:   genai.NewPartFromText("hello"),
+		},
+		{
+			name:       "multipart",
+			body:       func() (string, *bytes.Buffer) { return multipartBody("application/pdf", []byte("%PDF")) },
+			wantStatus: http.StatusCreated,
+			wantPart:   genai.NewPartFromBytes([]byte("%PDF"), "application/pdf"),
+		},
+		{
+			name:       "multipart detects content type",
+			body:       func() (string, *bytes.Buffer) { return multipartBody("", []byte("plain text")) },
+			wantStatus: http.StatusCreated,
+			wantPart:   genai.NewPartFromBytes([]byte("plain text"), "text/plain; charset=utf-8"),
+		},
+		{
+			name: "json without content",
+			body: func() (string, *bytes.Buffer) {
+				return "application/json", bytes.NewBufferString(`{}`)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "json without mime type",
+			body: func() (string, *bytes.Buffer) {
+				return "application/json", bytes.NewBufferString(`{"inlineData": {"data": "AQID"}}`)
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			artifactService := artifact.InMemoryService()
+			apiController := controllers.NewArtifactsAPIController(artifactService, 0)
+			vars := map[string]string{
+				"app_name":      "testApp",
+				"user_id":       "testUser",
+				"session_id":    "testSession",
+				"artifact_name": "file.bin",
+			}
+
+			for wantVersion := int64(1); wantVersion <= 2; wantVersion++ {
+				contentType, body := tt.body()
+				req, err := http.NewRequest(http.MethodPost, "/apps/testApp/users/testUser/sessions/testSession/artifacts/file.bin", body)
+				if err != nil {
+					t.Fatalf("new request: %v", err)
+				}
+				req.Header.Set("Content-Type", contentType)
+				req = mux.SetURLVars(req, vars)
+				rr := httptest.NewRecorder()
+
+				apiController.SaveArtifactHandler(rr, req)
+
+				if rr.Code != tt.wantStatus {
+					t.Fatalf("handler returned wrong status code: got %v want %v: %s", rr.Code, tt.wantStatus, strings.TrimSpace(rr.Body.String()))
+				}
+				if tt.wantStatus != http.StatusCreated {
+					return
+				}
+				var got models.SaveArtifactResponse
+				if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+					t.Fatalf("decode response: %v", err)
+				}
+				if got.Version != wantVersion {
+					t.Errorf("version = %d, want %d", got.Version, wantVersion)
+				}
+			}
+
+			resp, err := artifactService.Load(t.Context(), &artifact.LoadRequest{
+				AppName:   "testApp",
+				UserID:    "testUser",
+				SessionID: "testSession",
+				FileName:  "file.bin",
+			})
+			if err != nil {
+				t.Fatalf("Load() failed: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantPart, resp.Part); diff != "" {
+				t.Errorf("stored artifact mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSaveArtifact_InvalidName(t *testing.T) {
+	apiController := controllers.NewArtifactsAPIController(artifact.InMemoryService(), 0)
+	req, err := http.NewRequest(http.MethodPost, "/apps/testApp/users/testUser/sessions/testSession/artifacts/..%2Fsecret", bytes.NewBufferString(`{"text": "hello"}`))
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req = mux.SetURLVars(req, map[string]string{
+		"app_name":      "testApp",
+		"user_id":       "testUser",
+		"session_id":    "testSession",
+		"artifact_name": "../secret",
+	})
+	rr := httptest.NewRecorder()
+
+	apiController.SaveArtifactHandler(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("handler returned wrong status code: got %v want %v: %s", rr.Code, http.StatusBadRequest, strings.TrimSpace(rr.Body.String()))
+	}
+}
+
+func TestSaveArtifact_TooLarge(t *testing.T) {
+	var multipartBody bytes.Buffer
+	w := multipart.NewWriter(&multipartBody)
+	fw, err := w.CreateFormFile("file", "f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write(bytes.Repeat([]byte("x"), 2048))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{name: "json", contentType: "application/json", body: `{"text": "` + strings.Repeat("x", 2048) + `"}`},
+		{name: "multipart", contentType: w.FormDataContentType(), body: multipartBody.String()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			artifactService := artifact.InMemoryService()
+			apiController := controllers.NewArtifactsAPIController(artifactService, 1024)
+			req, err := http.NewRequest(http.MethodPost, "/apps/testApp/users/testUser/sessions/testSession/artifacts/file.bin", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("new request: %v", err)
+			}
+			req.Header.Set("Content-Type", tt.contentType)
+			req = mux.SetURLVars(req, map[string]string{
+				"app_name":      "testApp",
+				"user_id":       "testUser",
+				"session_id":    "testSession",
+				"artifact_name": "file.bin",
+			})
+			rr := httptest.NewRecorder()
+
+			apiController.SaveArtifactHandler(rr, req)
+
+			if rr.Code != http.StatusRequestEntityTooLarge {
+				t.Errorf("handler returned wrong status code: got %v want %v: %s", rr.Code, http.StatusRequestEntityTooLarge, strings.TrimSpace(rr.Body.String()))
+			}
+		})
+	}
+}
+
+func TestLoadArtifact_Raw(t *testing.T) {
+	artifactService := artifact.InMemoryService()
+	for name, part := range map[string]*genai.Part{
+		"image.png": genai.NewPartFromBytes([]byte("0123456789"), "image/png"),
+		"notes.txt": genai.NewPartFromText("hello"),
+		"page.html": genai.NewPartFromBytes([]byte("<script>alert(1)</script>"), "text/html"),
+		"logo.svg":  genai.NewPartFromBytes([]byte("<svg/>"), "image/svg+xml"),
+		"blob":      {InlineData: &genai.Blob{Data: []byte("<html>")}},
+	} {
+		if _, err := artifactService.Save(t.Context(), &artifact.SaveRequest{
+			AppName:   "testApp",
+			UserID:    "testUser",
+			SessionID: "testSession",
+			FileName:  name,
+			Part:      part,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	apiController := controllers.NewArtifactsAPIController(artifactService, 0)
+
+	tests := []struct {
+		name            string
+		artifactName    string
+		query           string
+		rangeHeader     string
+		wantStatus      int
+		wantContentType string
+		wantDisposition string
+		wantBody        string
+	}{
+		{
+			name:            "json",
+			artifactName:    "notes.txt",
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/json; charset=UTF-8",
+			wantBody:        `{"text":"hello"}`,
+		},
+		{
+			name:            "raw binary",
+			artifactName:    "image.png",
+			query:           "?raw=true",
+			wantStatus:      http.StatusOK,
+			wantContentType: "image/png",
+			wantDisposition: `inline; filename=image.png`,
+			wantBody:        "0123456789",
+		},
+		{
+			name:            "raw text",
+			artifactName:    "notes.txt",
+			query:           "?raw=true",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/plain; charset=utf-8",
+			wantDisposition: `inline; filename=notes.txt`,
+			wantBody:        "hello",
+		},
+		{
+			name:            "raw range",
+			artifactName:    "image.png",
+			query:           "?raw=true",
+			rangeHeader:     "bytes=2-5",
+			wantStatus:      http.StatusPartialContent,
+			wantContentType: "image/png",
+			wantDisposition: `inline; filename=image.png`,
+			wantBody:        "2345",
+		},
+		{
+			name:            "raw html",
+			artifactName:    "page.html",
+			query:           "?raw=true",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/html",
+			wantDisposition: `attachment; filename=page.html`,
+			wantBody:        "<script>alert(1)</script>",
+		},
+		{
+			name:            "raw svg",
+			artifactName:    "logo.svg",
+			query:           "?raw=true",
+			wantStatus:      http.StatusOK,
+			wantContentType: "image/svg+xml",
+			wantDisposition: `attachment; filename=logo.svg`,
+			wantBody:        "<svg/>",
+		},
+		{
+			name:            "raw without mime type",
+			artifactName:    "blob",
+			query:           "?raw=true",
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/octet-stream",
+			wantDisposition: `attachment; filename=blob`,
+			wantBody:        "<html>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/apps/testApp/users/testUser/sessions/testSession/artifacts/"+tt.artifactName+tt.query, nil)
+			if err != nil {
+				t.Fatalf("new request: %v", err)
+			}
+			if tt.rangeHeader != "" {
+				req.Header.Set("Range", tt.rangeHeader)
+			}
+			req = mux.SetURLVars(req, map[string]string{
+				"app_name":      "testApp",
+				"user_id":       "testUser",
+				"session_id":    "testSession",
+				"artifact_name": tt.artifactName,
+			})
+			rr := httptest.NewRecorder()
+
+			apiController.LoadArtifactHandler(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, tt.wantStatus)
+			}
+			if got := rr.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantContentType)
+			}
+			if got := rr.Header().Get("Content-Disposition"); got != tt.wantDisposition {
+				t.Errorf("Content-Disposition = %q, want %q", got, tt.wantDisposition)
+			}
+			if tt.query != "" {
+				if got := rr.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+					t.Errorf("X-Content-Type-Options = %q, want nosniff", got)
+				}
+				if got := rr.Header().Get("Content-Security-Policy"); got != "sandbox" {
+					t.Errorf("Content-Security-Policy = %q, want sandbox", got)
+				}
+			}
+			if got := strings.TrimSpace(rr.Body.String()); got != tt.wantBody {
+				t.Errorf("body = %q, want %q", got, tt.wantBody)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ package controllers
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/gorilla/mux"
@@ -51,8 +52,7 @@ func (c *DebugAPIController) TraceDictHandler(rw http.ResponseWriter, req *http.
 		http.Error(rw, "event_id parameter is required", http.StatusBadRequest)
 		return
 	}
-	traceDict := c.spansExporter.GetTraceDict()
-	eventDict, ok := traceDict[eventID]
+	eventDict, ok := c.spansExporter.GetEventTrace(eventID, traceAllowed(req))
 	if !ok {
 		http.Error(rw, fmt.Sprintf("event not found: %s", eventID), http.StatusNotFound)
 		return
@@ -60,6 +60,26 @@ func (c *DebugAPIController) TraceDictHandler(rw http.ResponseWriter, req *http.
 	EncodeJSONResponse(eventDict, http.StatusOK, rw)
 }
 
+// SessionTraceHandler returns the spans of the traces of a session, ordered
+// by start time. The parent span IDs describe the span tree.
+func (c *DebugAPIController) SessionTraceHandler(rw http.ResponseWriter, req *http.Request) {
+	params := mux.Vars(req)
+	sessionID := params["session_id"]
+	if sessionID == "" {
+		http.Error(rw, "session_id parameter is required", http.StatusBadRequest)
+		return
+	}
+	EncodeJSONResponse(c.spansExporter.GetSessionSpans(sessionID, traceAllowed(req)), http.StatusOK, rw)
+}
+
+// traceAllowed returns the function reporting whether the caller of req can
+// see the traces of the user of an app.
+func traceAllowed(req *http.Request) func(appName, userID string) bool {
+	return func(appName, userID string) bool {
+		return checkUser(req, appName, userID) == nil
+	}
+}
+
 // EventGraphHandler returns the debug information for the session and session events in form of graph.
 func (c *DebugAPIController) EventGraphHandler(rw http.ResponseWriter, req *http.Request) {
 	vars := mux.Vars(req)
@@ -121,12 +141,23 @@ func (c *DebugAPIController) EventGraphHandler(rw http.ResponseWriter, req *http
 		http.Error(rw, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	graph, err := services.GetAgentGraph(req.Context(), agent, highlightedPairs)
-	if err != nil {
-		http.Error(rw, err.Error(), http.StatusInternalServerError)
-		return
+	switch format := req.URL.Query().Get("format"); format {
+	case "", "dot":
+		graph, err := services.GetAgentGraph(req.Context(), agent, highlightedPairs)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		EncodeJSONResponse(map[string]string{"dotSrc": graph}, http.StatusOK, rw)
+	case "json":
+		EncodeJSONResponse(services.BuildAgentGraph(agent, highlightedPairs), http.StatusOK, rw)
+	case "svg":
+		rw.Header().Set("Content-Type", "image/svg+xml")
+		rw.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(rw, services.RenderAgentGraphSVG(services.BuildAgentGraph(agent, highlightedPairs)))
+	default:
+		http.Error(rw, fmt.Sprintf("unsupported graph format %q", format), http.StatusBadRequest)
 	}
-	EncodeJSONResponse(map[string]string{"dotSrc": graph}, http.StatusOK, rw)
 }
 
 func functionalCalls(event *session.Event) []*genai.FunctionCall {
@@ -16,8 +16,14 @@
 package controllers
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"google.golang.org/adk/server/httpauth"
 )
 
 // TODO: Move to an internal package, controllers doesn't have to be public API.
@@ -57,3 +63,71 @@ func NewErrorHandler(fn errorHandler) http.HandlerFunc {
 func Unimplemented(rw http.ResponseWriter, req *http.Request) {
 	rw.WriteHeader(http.StatusNotImplemented)
 }
+
+type authorizerKey struct{}
+
+// checkUser returns a 403 status error if the request is authenticated and
+// the principal cannot access the data of userID.
+func checkUser(req *http.Request, appName, userID string) error {
+	ctx := req.Context()
+	p := httpauth.FromContext(ctx)
+	if p == nil {
+		return nil
+	}
+	authorizer, ok := ctx.Value(authorizerKey{}).(httpauth.Authorizer)
+	if !ok {
+		authorizer = httpauth.UserAuthorizer()
+	}
+	if err := authorizer.Authorize(ctx, p, httpauth.AccessRequest{AppName: appName, UserID: userID, Method: req.Method}); err != nil {
+		return newStatusError(err, http.StatusForbidden)
+	}
+	return nil
+}
+
+// checkAdmin returns a 403 status error unless the request is authenticated
+// and the principal is allowed the admin requests on appName. The admin
+// requests are refused when the server does not authenticate its requests.
+func checkAdmin(req *http.Request, appName string) error {
+	ctx := req.Context()
+	p := httpauth.FromContext(ctx)
+	if p == nil {
+		return newStatusError(fmt.Errorf("%w: the request must be authenticated as an admin", httpauth.ErrForbidden), http.StatusForbidden)
+	}
+	authorizer, ok := ctx.Value(authorizerKey{}).(httpauth.Authorizer)
+	if !ok {
+		authorizer = httpauth.UserAuthorizer()
+	}
+	if err := authorizer.Authorize(ctx, p, httpauth.AccessRequest{AppName: appName, Method: req.Method, Admin: true}); err != nil {
+		return newStatusError(err, http.StatusForbidden)
+	}
+	return nil
+}
+
+// Authorize returns a middleware checking with the authorizer that the
+// authenticated principal can access the data of the user named by the
+// user_id path variable or query parameter. The handlers taking the user
+// from the request body check it with the same authorizer.
+// If authorizer is nil, httpauth.UserAuthorizer() is used.
+func Authorize(authorizer httpauth.Authorizer) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if authorizer != nil {
+				r = r.WithContext(context.WithValue(r.Context(), authorizerKey{}, authorizer))
+			}
+			vars := mux.Vars(r)
+			userID, ok := vars["user_id"]
+			appName := vars["app_name"]
+			if !ok && r.URL.Query().Has("user_id") {
+				userID, ok = r.URL.Query().Get("user_id"), true
+				appName = r.URL.Query().Get("app_name")
+			}
+			if ok {
+				if err := checkUser(r, appName, userID); err != nil {
+					http.Error(w, err.Error(), http.StatusForbidden)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/server/adkrest/controllers"
+	"google.golang.org/adk/server/adkrest/internal/models"
+	"google.golang.org/adk/server/httpauth"
+	"google.golang.org/adk/session"
+)
+
+func TestAuthorize(t *testing.T) {
+	readOnly := httpauth.AuthorizerFunc(func(ctx context.Context, p *httpauth.Principal, req httpauth.AccessRequest) error {
+		if req.Method != http.MethodGet {
+			return httpauth.ErrForbidden
+		}
+		return nil
+	})
+	alice := &httpauth.Principal{Subject: "alice"}
+	admin := &httpauth.Principal{Subject: "root", Roles: []string{"viewer", "admin"}}
+
+	for _, tc := range []struct {
+		name       string
+		authorizer httpauth.Authorizer
+		method     string
+		path       string
+		principal  *httpauth.Principal
+		wantStatus int
+	}{
+		{name: "same user", path: "/apps/app/users/alice/sessions", principal: alice, wantStatus: http.StatusOK},
+		{name: "other user", path: "/apps/app/users/bob/sessions", principal: alice, wantStatus: http.StatusForbidden},
+		{name: "other user in query", path: "/run_live?app_name=app&user_id=bob", principal: alice, wantStatus: http.StatusForbidden},
+		{name: "unauthenticated", path: "/apps/app/users/bob/sessions", wantStatus: http.StatusOK},
+		{name: "no user", path: "/list-apps", principal: alice, wantStatus: http.StatusOK},
+		{name: "admin", authorizer: httpauth.UserAuthorizer("admin"), path: "/apps/app/users/bob/sessions", principal: admin, wantStatus: http.StatusOK},
+		{name: "not admin", authorizer: httpauth.UserAuthorizer("admin"), path: "/apps/app/users/bob/sessions", principal: alice, wantStatus: http.StatusForbidden},
+		{name: "custom allows", authorizer: readOnly, method: http.MethodGet, path: "/apps/app/users/bob/sessions", principal: alice, wantStatus: http.StatusOK},
+		{name: "custom denies", authorizer: readOnly, method: http.MethodPost, path: "/apps/app/users/alice/sessions", principal: alice, wantStatus: http.StatusForbidden},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			router := mux.NewRouter()
+			router.Use(controllers.Authorize(tc.authorizer))
+			router.HandleFunc("/apps/{app_name}/users/{user_id}/sessions", func(w http.ResponseWriter, r *http.Request) {})
+			router.HandleFunc("/run_live", func(w http.ResponseWriter, r *http.Request) {})
+			router.HandleFunc("/list-apps", func(w http.ResponseWriter, r *http.Request) {})
+
+			method := tc.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, tc.path, nil)
+			if tc.principal != nil {
+				req = req.WithContext(httpauth.ToContext(req.Context(), tc.principal))
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code != tc.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tc.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRunHandler_OtherUser(t *testing.T) {
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(t.Context(), &session.CreateRequest{AppName: "test_app", UserID: "bob", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	apiController := controllers.NewRuntimeAPIController(sessionService, agent.NewSingleLoader(nil), nil, nil)
+	body, err := json.Marshal(models.RunAgentRequest{
+		AppName:    "test_app",
+		UserId:     "bob",
+		SessionId:  "session",
+		NewMessage: *genai.NewContentFromText("hi", genai.RoleUser),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/run", strings.NewReader(string(body)))
+	req = req.WithContext(httpauth.ToContext(req.Context(), &httpauth.Principal{Subject: "alice"}))
+
+	err = apiController.RunHandler(httptest.NewRecorder(), req)
+	var statusErr interface{ Status() int }
+	if !errors.As(err, &statusErr) || statusErr.Status() != http.StatusForbidden {
+		t.Errorf("RunHandler() = %v, want a 403 error", err)
+	}
+}
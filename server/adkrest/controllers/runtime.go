@@ -17,13 +17,24 @@ package controllers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/artifact"
+	iagent "google.golang.org/adk/internal/agent"
+	"google.golang.org/adk/model"
 	"google.golang.org/adk/runner"
 	"google.golang.org/adk/server/adkrest/internal/models"
+	"google.golang.org/adk/server/ratelimit"
 	"google.golang.org/adk/session"
 )
 
@@ -32,11 +43,25 @@ type RuntimeAPIController struct {
 	sessionService  session.Service
 	artifactService artifact.Service
 	agentLoader     agent.Loader
+	upgrader        websocket.Upgrader
 }
 
 // NewRuntimeAPIController creates the controller for the Runtime API.
-func NewRuntimeAPIController(sessionService session.Service, agentLoader agent.Loader, artifactService artifact.Service) *RuntimeAPIController {
-	return &RuntimeAPIController{sessionService: sessionService, agentLoader: agentLoader, artifactService: artifactService}
+//
+// The WebSocket endpoints accept the connections without an Origin header,
+// e.g. from other servers, the same-origin ones and the ones from
+// allowedOrigins. The allowed origins are given as URLs, e.g.
+// "https://example.com", or as hosts with an optional port, e.g.
+// "localhost:8080". "*" allows every origin.
+func NewRuntimeAPIController(sessionService session.Service, agentLoader agent.Loader, artifactService artifact.Service, allowedOrigins []string) *RuntimeAPIController {
+	return &RuntimeAPIController{
+		sessionService:  sessionService,
+		agentLoader:     agentLoader,
+		artifactService: artifactService,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: checkOrigin(allowedOrigins),
+		},
+	}
 }
 
 // RunAgent executes a non-streaming agent run for a given session and message.
@@ -45,6 +70,9 @@ func (c *RuntimeAPIController) RunHandler(rw http.ResponseWriter, req *http.Requ
 	if err != nil {
 		return err
 	}
+	if err := checkUser(req, runAgentRequest.AppName, runAgentRequest.UserId); err != nil {
+		return err
+	}
 	sessionEvents, err := c.runAgent(req.Context(), runAgentRequest)
 	if err != nil {
 		return err
@@ -63,6 +91,9 @@ func (c *RuntimeAPIController) runAgent(ctx context.Context, runAgentRequest mod
 	if err != nil {
 		return nil, err
 	}
+	if err := c.checkMessage(runAgentRequest); err != nil {
+		return nil, err
+	}
 
 	r, rCfg, err := c.getRunner(runAgentRequest)
 	if err != nil {
@@ -73,15 +104,168 @@ func (c *RuntimeAPIController) runAgent(ctx context.Context, runAgentRequest mod
 
 	var events []*session.Event
 	for event, err := range resp {
+		if errors.Is(err, runner.ErrInvocationInProgress) {
+			return nil, newStatusError(err, http.StatusConflict)
+		}
 		if err != nil {
 			return nil, newStatusError(fmt.Errorf("run agent: %w", err), http.StatusInternalServerError)
 		}
 		events = append(events, event)
 	}
+	if err := c.checkResponse(runAgentRequest.AppName, lastFinalResponse(events)); err != nil {
+		return nil, err
+	}
 	return events, nil
 }
 
+// ResumeHandler continues an invocation that paused on a long-running
+// function call with the function response from the request. It returns the
+// events of the resumed run.
+func (c *RuntimeAPIController) ResumeHandler(rw http.ResponseWriter, req *http.Request) error {
+	var resumeRequest models.ResumeRequest
+	defer req.Body.Close()
+	d := json.NewDecoder(req.Body)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&resumeRequest); err != nil {
+		return newStatusError(fmt.Errorf("decode request: %w", err), http.StatusBadRequest)
+	}
+	if resumeRequest.InvocationId == "" {
+		return newStatusError(fmt.Errorf("invocationId is required"), http.StatusBadRequest)
+	}
+	if err := checkUser(req, resumeRequest.AppName, resumeRequest.UserId); err != nil {
+		return err
+	}
+	if err := c.validateSessionExists(req.Context(), resumeRequest.AppName, resumeRequest.UserId, resumeRequest.SessionId); err != nil {
+		return err
+	}
+	r, rCfg, err := c.getRunner(models.RunAgentRequest{AppName: resumeRequest.AppName, Streaming: resumeRequest.Streaming})
+	if err != nil {
+		return err
+	}
+
+	var events []models.Event
+	for event, err := range r.Resume(req.Context(), resumeRequest.UserId, resumeRequest.SessionId, resumeRequest.InvocationId, &resumeRequest.FunctionResponse, *rCfg) {
+		if errors.Is(err, runner.ErrInvocationNotPaused) || errors.Is(err, runner.ErrInvocationInProgress) {
+			return newStatusError(err, http.StatusConflict)
+		}
+		if err != nil {
+			return newStatusError(fmt.Errorf("resume agent: %w", err), http.StatusInternalServerError)
+		}
+		events = append(events, models.FromSessionEvent(*event))
+	}
+	EncodeJSONResponse(events, http.StatusOK, rw)
+	return nil
+}
+
+// ForkHandler creates a new session with the events of the session from the
+// path up to, and including, the event from the request. It returns the new
+// session.
+func (c *RuntimeAPIController) ForkHandler(rw http.ResponseWriter, req *http.Request) error {
+	sessionID, err := models.SessionIDFromHTTPParameters(mux.Vars(req))
+	if err != nil {
+		return newStatusError(err, http.StatusBadRequest)
+	}
+	if sessionID.ID == "" {
+		return newStatusError(fmt.Errorf("session_id parameter is required"), http.StatusBadRequest)
+	}
+	var forkRequest models.ForkSessionRequest
+	defer req.Body.Close()
+	d := json.NewDecoder(req.Body)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&forkRequest); err != nil {
+		return newStatusError(fmt.Errorf("decode request: %w", err), http.StatusBadRequest)
+	}
+	if forkRequest.EventId == "" {
+		return newStatusError(fmt.Errorf("eventId is required"), http.StatusBadRequest)
+	}
+	if err := c.validateSessionExists(req.Context(), sessionID.AppName, sessionID.UserID, sessionID.ID); err != nil {
+		return err
+	}
+	r, _, err := c.getRunner(models.RunAgentRequest{AppName: sessionID.AppName})
+	if err != nil {
+		return err
+	}
+
+	fork, err := r.Fork(req.Context(), sessionID.UserID, sessionID.ID, forkRequest.EventId)
+	if errors.Is(err, runner.ErrEventNotFound) {
+		return newStatusError(err, http.StatusNotFound)
+	}
+	if err != nil {
+		return newStatusError(fmt.Errorf("fork session: %w", err), http.StatusInternalServerError)
+	}
+	respSession, err := models.FromSession(fork)
+	if err != nil {
+		return newStatusError(err, http.StatusInternalServerError)
+	}
+	EncodeJSONResponse(respSession, http.StatusOK, rw)
+	return nil
+}
+
+// ListInvocationsHandler returns the invocations of the session from the
+// path, with their status, aggregated from the session events.
+func (c *RuntimeAPIController) ListInvocationsHandler(rw http.ResponseWriter, req *http.Request) error {
+	sessionID, err := models.SessionIDFromHTTPParameters(mux.Vars(req))
+	if err != nil {
+		return newStatusError(err, http.StatusBadRequest)
+	}
+	if sessionID.ID == "" {
+		return newStatusError(fmt.Errorf("session_id parameter is required"), http.StatusBadRequest)
+	}
+	if err := c.validateSessionExists(req.Context(), sessionID.AppName, sessionID.UserID, sessionID.ID); err != nil {
+		return err
+	}
+	r, _, err := c.getRunner(models.RunAgentRequest{AppName: sessionID.AppName})
+	if err != nil {
+		return err
+	}
+
+	invocations, err := r.Invocations(req.Context(), sessionID.UserID, sessionID.ID)
+	if err != nil {
+		return newStatusError(fmt.Errorf("list invocations: %w", err), http.StatusInternalServerError)
+	}
+	result := []models.Invocation{}
+	for _, inv := range invocations {
+		result = append(result, models.FromInvocation(inv))
+	}
+	EncodeJSONResponse(result, http.StatusOK, rw)
+	return nil
+}
+
+// CancelInvocationHandler cancels the invocation from the path if it is in
+// progress in the session. The run of the invocation ends with a cancelled
+// event.
+func (c *RuntimeAPIController) CancelInvocationHandler(rw http.ResponseWriter, req *http.Request) error {
+	vars := mux.Vars(req)
+	sessionID, err := models.SessionIDFromHTTPParameters(vars)
+	if err != nil {
+		return newStatusError(err, http.StatusBadRequest)
+	}
+	if sessionID.ID == "" {
+		return newStatusError(fmt.Errorf("session_id parameter is required"), http.StatusBadRequest)
+	}
+	invocationID := vars["invocation_id"]
+	if invocationID == "" {
+		return newStatusError(fmt.Errorf("invocation_id parameter is required"), http.StatusBadRequest)
+	}
+	r, _, err := c.getRunner(models.RunAgentRequest{AppName: sessionID.AppName})
+	if err != nil {
+		return err
+	}
+
+	err = r.Cancel(sessionID.UserID, sessionID.ID, invocationID)
+	if errors.Is(err, runner.ErrInvocationNotFound) {
+		return newStatusError(err, http.StatusNotFound)
+	}
+	if err != nil {
+		return newStatusError(fmt.Errorf("cancel invocation: %w", err), http.StatusInternalServerError)
+	}
+	EncodeJSONResponse(nil, http.StatusOK, rw)
+	return nil
+}
+
 // RunSSEHandler executes an agent run and streams the resulting events using Server-Sent Events (SSE).
+// If the request enables streaming, partial model responses are sent as soon as
+// they are generated. Partial events are not stored in the session.
 func (c *RuntimeAPIController) RunSSEHandler(rw http.ResponseWriter, req *http.Request) error {
 	flusher, ok := rw.(http.Flusher)
 	if !ok {
@@ -96,11 +280,17 @@ func (c *RuntimeAPIController) RunSSEHandler(rw http.ResponseWriter, req *http.R
 	if err != nil {
 		return err
 	}
+	if err := checkUser(req, runAgentRequest.AppName, runAgentRequest.UserId); err != nil {
+		return err
+	}
 
 	err = c.validateSessionExists(req.Context(), runAgentRequest.AppName, runAgentRequest.UserId, runAgentRequest.SessionId)
 	if err != nil {
 		return err
 	}
+	if err := c.checkMessage(runAgentRequest); err != nil {
+		return err
+	}
 
 	r, rCfg, err := c.getRunner(runAgentRequest)
 	if err != nil {
@@ -110,23 +300,262 @@ func (c *RuntimeAPIController) RunSSEHandler(rw http.ResponseWriter, req *http.R
 	resp := r.Run(req.Context(), runAgentRequest.UserId, runAgentRequest.SessionId, &runAgentRequest.NewMessage, *rCfg)
 
 	rw.WriteHeader(http.StatusOK)
+	var final *session.Event
 	for event, err := range resp {
 		if err != nil {
-			_, err := fmt.Fprintf(rw, "Error while running agent: %v\n", err)
-			if err != nil {
-				return newStatusError(fmt.Errorf("write response: %w", err), http.StatusInternalServerError)
-			}
-			flusher.Flush()
-			continue
+			// The status was already sent, so the error is reported as the
+			// last message of the stream.
+			return flashError(flusher, rw, err)
+		}
+		if iagent.IsOutput(event) {
+			final = event
 		}
 		err := flashEvent(flusher, rw, *event)
 		if err != nil {
 			return err
 		}
 	}
+	if err := c.checkResponse(runAgentRequest.AppName, final); err != nil {
+		return flashError(flusher, rw, err)
+	}
+	return nil
+}
+
+// maxWebSocketMessageSize is the largest message the clients of the
+// WebSocket endpoints can send.
+const maxWebSocketMessageSize = 8 << 20
+
+// checkOrigin returns the function rejecting the cross-site WebSocket
+// connections whose origin is not in allowed. Browsers do not apply CORS to
+// WebSockets, so without it any web page could act as the user.
+func checkOrigin(allowed []string) func(*http.Request) bool {
+	hosts := make(map[string]bool, len(allowed))
+	for _, origin := range allowed {
+		if origin == "*" {
+			return func(*http.Request) bool { return true }
+		}
+		if u, err := url.Parse(origin); err == nil && u.Host != "" {
+			origin = u.Host
+		}
+		hosts[strings.ToLower(origin)] = true
+	}
+	return func(req *http.Request) bool {
+		origin := req.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		u, err := url.Parse(origin)
+		if err != nil {
+			return false
+		}
+		host := strings.ToLower(u.Host)
+		return host == strings.ToLower(req.Host) || hosts[host]
+	}
+}
+
+// upgrade upgrades the connection of req to a WebSocket.
+func (c *RuntimeAPIController) upgrade(rw http.ResponseWriter, req *http.Request) (*websocket.Conn, error) {
+	conn, err := c.upgrader.Upgrade(rw, req, nil)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetReadLimit(maxWebSocketMessageSize)
+	return conn, nil
+}
+
+// RunLiveHandler runs the agent in bidirectional streaming mode over a
+// WebSocket. The app, user and session are given by the app_name, user_id
+// and session_id query parameters. The optional modalities parameter, e.g.
+// modalities=AUDIO, selects the modalities of the model output. With audio
+// output, the audio of the user and the model is transcribed as well.
+//
+// The client sends models.LiveRequest messages, e.g. audio chunks and
+// activity signals, and receives the events of the agent as they are
+// generated. The run ends when the client sends a close request or
+// disconnects.
+func (c *RuntimeAPIController) RunLiveHandler(rw http.ResponseWriter, req *http.Request) error {
+	query := req.URL.Query()
+	appName, userID, sessionID := query.Get("app_name"), query.Get("user_id"), query.Get("session_id")
+	if appName == "" || userID == "" || sessionID == "" {
+		return newStatusError(fmt.Errorf("app_name, user_id and session_id are required"), http.StatusBadRequest)
+	}
+	if err := c.validateSessionExists(req.Context(), appName, userID, sessionID); err != nil {
+		return err
+	}
+	r, rCfg, err := c.getRunner(models.RunAgentRequest{AppName: appName})
+	if err != nil {
+		return err
+	}
+	for _, m := range query["modalities"] {
+		rCfg.ResponseModalities = append(rCfg.ResponseModalities, genai.Modality(strings.ToUpper(m)))
+	}
+	if slices.Contains(rCfg.ResponseModalities, genai.ModalityAudio) {
+		// Transcripts let the client render the conversation as text.
+		rCfg.InputAudioTranscription = &genai.AudioTranscriptionConfig{}
+		rCfg.OutputAudioTranscription = &genai.AudioTranscriptionConfig{}
+	}
+
+	conn, err := c.upgrade(rw, req)
+	if err != nil {
+		// Upgrade has already replied to the client.
+		return nil
+	}
+	defer conn.Close()
+
+	queue := agent.NewLiveRequestQueue()
+	go func() {
+		defer queue.Close()
+		for {
+			var liveReq models.LiveRequest
+			if err := conn.ReadJSON(&liveReq); err != nil || liveReq.Close {
+				return
+			}
+			if err := queue.Send(&model.LiveRequest{
+				Content:       liveReq.Content,
+				Blob:          liveReq.Blob,
+				ActivityStart: liveReq.ActivityStart,
+				ActivityEnd:   liveReq.ActivityEnd,
+			}); err != nil {
+				return
+			}
+		}
+	}()
+
+	for event, err := range r.RunLive(req.Context(), userID, sessionID, queue, *rCfg) {
+		if err != nil {
+			msg := websocket.FormatCloseMessage(websocket.CloseInternalServerErr, err.Error())
+			conn.WriteMessage(websocket.CloseMessage, msg)
+			return nil
+		}
+		if err := conn.WriteJSON(models.FromSessionEvent(*event)); err != nil {
+			return nil
+		}
+	}
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
 	return nil
 }
 
+// RunWSHandler runs the agent over a WebSocket. The client sends a
+// models.RunAgentRequest as the first message, and receives the events of the
+// run as JSON messages, including the partial events if the request enables
+// streaming. The client can stop the run by sending a models.RunWSControl
+// message with cancel set.
+//
+// The server closes the connection when the run ends, with the normal
+// closure code if the run completed or was cancelled, the policy violation
+// code if the request was rejected, the try again later code if the session
+// is already running an invocation, and the internal error code if the run
+// failed. The reason of the close message describes the error.
+func (c *RuntimeAPIController) RunWSHandler(rw http.ResponseWriter, req *http.Request) error {
+	conn, err := c.upgrade(rw, req)
+	if err != nil {
+		// Upgrade has already replied to the client.
+		return nil
+	}
+	defer conn.Close()
+
+	var runAgentRequest models.RunAgentRequest
+	_, msg, err := conn.NextReader()
+	if err == nil {
+		d := json.NewDecoder(msg)
+		d.DisallowUnknownFields()
+		err = d.Decode(&runAgentRequest)
+	}
+	if err != nil {
+		closeWebSocket(conn, newStatusError(fmt.Errorf("decode request: %w", err), http.StatusBadRequest))
+		return nil
+	}
+	// The run is authorized as the POST requests of /run and /run_sse.
+	runReq := req.Clone(req.Context())
+	runReq.Method = http.MethodPost
+	if err := checkUser(runReq, runAgentRequest.AppName, runAgentRequest.UserId); err != nil {
+		closeWebSocket(conn, err)
+		return nil
+	}
+	if err := ratelimit.Check(req.Context(), runAgentRequest.AppName, runAgentRequest.UserId); err != nil {
+		closeWebSocket(conn, newStatusError(err, http.StatusTooManyRequests))
+		return nil
+	}
+	if err := c.validateSessionExists(req.Context(), runAgentRequest.AppName, runAgentRequest.UserId, runAgentRequest.SessionId); err != nil {
+		closeWebSocket(conn, err)
+		return nil
+	}
+	if err := c.checkMessage(runAgentRequest); err != nil {
+		closeWebSocket(conn, err)
+		return nil
+	}
+	r, rCfg, err := c.getRunner(runAgentRequest)
+	if err != nil {
+		closeWebSocket(conn, err)
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+	go func() {
+		// The run is cancelled when the client asks for it or disconnects.
+		defer cancel()
+		for {
+			var control models.RunWSControl
+			if err := conn.ReadJSON(&control); err != nil || control.Cancel {
+				return
+			}
+		}
+	}()
+
+	var final *session.Event
+	for event, err := range r.Run(ctx, runAgentRequest.UserId, runAgentRequest.SessionId, &runAgentRequest.NewMessage, *rCfg) {
+		if ctx.Err() != nil {
+			break
+		}
+		if errors.Is(err, runner.ErrInvocationInProgress) {
+			closeWebSocket(conn, newStatusError(err, http.StatusConflict))
+			return nil
+		}
+		if err != nil {
+			closeWebSocket(conn, newStatusError(fmt.Errorf("run agent: %w", err), http.StatusInternalServerError))
+			return nil
+		}
+		if iagent.IsOutput(event) {
+			final = event
+		}
+		if err := conn.WriteJSON(models.FromSessionEvent(*event)); err != nil {
+			return nil
+		}
+	}
+	reason := ""
+	if ctx.Err() != nil {
+		reason = "run cancelled"
+	} else if err := c.checkResponse(runAgentRequest.AppName, final); err != nil {
+		closeWebSocket(conn, err)
+		return nil
+	}
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, reason))
+	return nil
+}
+
+// closeWebSocket closes conn with the close code matching the status of err.
+func closeWebSocket(conn *websocket.Conn, err error) {
+	code := websocket.CloseInternalServerErr
+	var statusErr statusError
+	if errors.As(err, &statusErr) {
+		switch {
+		case statusErr.Status() == http.StatusConflict, statusErr.Status() == http.StatusTooManyRequests:
+			code = websocket.CloseTryAgainLater
+		case statusErr.Status() < http.StatusInternalServerError:
+			code = websocket.ClosePolicyViolation
+		}
+	}
+	reason := err.Error()
+	// The reason must fit in a control frame with the close code.
+	if len(reason) > maxCloseReasonLen {
+		reason = reason[:maxCloseReasonLen]
+	}
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason))
+}
+
+const maxCloseReasonLen = 123
+
 func flashEvent(flusher http.Flusher, rw http.ResponseWriter, event session.Event) error {
 	_, err := fmt.Fprintf(rw, "data: ")
 	if err != nil {
@@ -144,6 +573,18 @@ func flashEvent(flusher http.Flusher, rw http.ResponseWriter, event session.Even
 	return nil
 }
 
+func flashError(flusher http.Flusher, rw http.ResponseWriter, runErr error) error {
+	data, err := json.Marshal(map[string]string{"error": runErr.Error()})
+	if err != nil {
+		return newStatusError(fmt.Errorf("encode response: %w", err), http.StatusInternalServerError)
+	}
+	if _, err := fmt.Fprintf(rw, "data: %s\n\n", data); err != nil {
+		return newStatusError(fmt.Errorf("write response: %w", err), http.StatusInternalServerError)
+	}
+	flusher.Flush()
+	return nil
+}
+
 func (c *RuntimeAPIController) validateSessionExists(ctx context.Context, appName, userID, sessionID string) error {
 	_, err := c.sessionService.Get(ctx, &session.GetRequest{
 		AppName:   appName,
@@ -156,6 +597,45 @@ func (c *RuntimeAPIController) validateSessionExists(ctx context.Context, appNam
 	return nil
 }
 
+// checkMessage rejects the message of req if it doesn't match the input
+// schema of the agent.
+func (c *RuntimeAPIController) checkMessage(req models.RunAgentRequest) error {
+	curAgent, err := c.agentLoader.LoadAgent(req.AppName)
+	if err != nil {
+		return newStatusError(fmt.Errorf("load agent: %w", err), http.StatusInternalServerError)
+	}
+	if err := iagent.ValidateInput(curAgent, &req.NewMessage); err != nil {
+		return newStatusError(err, http.StatusBadRequest)
+	}
+	return nil
+}
+
+// checkResponse fails the run if final, the last final response of the
+// invocation, doesn't match the output schema of the agent. Runs without a
+// final response, e.g. that failed, are not checked.
+func (c *RuntimeAPIController) checkResponse(appName string, final *session.Event) error {
+	if final == nil {
+		return nil
+	}
+	curAgent, err := c.agentLoader.LoadAgent(appName)
+	if err != nil {
+		return newStatusError(fmt.Errorf("load agent: %w", err), http.StatusInternalServerError)
+	}
+	if err := iagent.ValidateOutput(curAgent, final.Content); err != nil {
+		return newStatusError(err, http.StatusInternalServerError)
+	}
+	return nil
+}
+
+func lastFinalResponse(events []*session.Event) *session.Event {
+	for i := len(events) - 1; i >= 0; i-- {
+		if iagent.IsOutput(events[i]) {
+			return events[i]
+		}
+	}
+	return nil
+}
+
 func (c *RuntimeAPIController) getRunner(req models.RunAgentRequest) (*runner.Runner, *agent.RunConfig, error) {
 	curAgent, err := c.agentLoader.LoadAgent(req.AppName)
 	if err != nil {
@@ -167,6 +647,9 @@ func (c *RuntimeAPIController) getRunner(req models.RunAgentRequest) (*runner.Ru
 		Agent:           curAgent,
 		SessionService:  c.sessionService,
 		ArtifactService: c.artifactService,
+		// Concurrent requests for a session are answered with a conflict
+		// instead of blocking the client.
+		SessionConcurrency: runner.RejectConcurrentInvocations,
 	},
 	)
 	if err != nil {
@@ -0,0 +1,780 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/server/adkrest/controllers"
+	"google.golang.org/adk/server/adkrest/internal/models"
+	"google.golang.org/adk/session"
+)
+
+func TestRunSSEHandler_StreamsPartialEvents(t *testing.T) {
+	ctx := t.Context()
+	mockModel := &testutil.MockModel{
+		Responses: []*genai.Content{
+			genai.NewContentFromText("Hello", genai.RoleModel),
+			genai.NewContentFromText(", world", genai.RoleModel),
+		},
+		StreamResponsesCount: 2,
+	}
+	a, err := llmagent.New(llmagent.Config{Name: "test_app", Model: mockModel})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "test_app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	apiController := controllers.NewRuntimeAPIController(sessionService, agent.NewSingleLoader(a), nil, nil)
+
+	body, err := json.Marshal(models.RunAgentRequest{
+		AppName:    "test_app",
+		UserId:     "user",
+		SessionId:  "session",
+		NewMessage: *genai.NewContentFromText("hi", genai.RoleUser),
+		Streaming:  true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/run_sse", strings.NewReader(string(body)))
+	rr := httptest.NewRecorder()
+
+	if err := apiController.RunSSEHandler(rr, req); err != nil {
+		t.Fatalf("RunSSEHandler() failed: %v", err)
+	}
+
+	type chunk struct {
+		Text    string
+		Partial bool
+	}
+	var got []chunk
+	scanner := bufio.NewScanner(rr.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var event models.Event
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			t.Fatalf("failed to decode event %q: %v", data, err)
+		}
+		got = append(got, chunk{Text: event.Content.Parts[0].Text, Partial: event.Partial})
+	}
+	want := []chunk{
+		{Text: "Hello", Partial: true},
+		{Text: ", world", Partial: true},
+		{Text: "Hello, world"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("streamed events mismatch (-want +got):\n%s", diff)
+	}
+
+	resp, err := sessionService.Get(ctx, &session.GetRequest{AppName: "test_app", UserID: "user", SessionID: "session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The user message and the final model response.
+	if got := resp.Session.Events().Len(); got != 2 {
+		t.Errorf("session has %d events, want 2", got)
+	}
+	for ev := range resp.Session.Events().All() {
+		if ev.Partial {
+			t.Errorf("partial event %v stored in session", ev)
+		}
+	}
+}
+
+func TestRunLiveHandler(t *testing.T) {
+	ctx := t.Context()
+	liveModel := &testutil.MockLiveModel{
+		Respond: func(req *model.LiveRequest) []*model.LLMResponse {
+			switch {
+			case req.Blob != nil:
+				return []*model.LLMResponse{{Content: genai.NewContentFromText("heard "+string(req.Blob.Data), genai.RoleModel)}}
+			case req.ActivityEnd:
+				return []*model.LLMResponse{{TurnComplete: true}}
+			}
+			return nil
+		},
+	}
+	a, err := llmagent.New(llmagent.Config{Name: "test_app", Model: liveModel})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "test_app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	apiController := controllers.NewRuntimeAPIController(sessionService, agent.NewSingleLoader(a), nil, nil)
+	srv := httptest.NewServer(controllers.NewErrorHandler(apiController.RunLiveHandler))
+	defer srv.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/run_live?app_name=test_app&user_id=user&session_id=session"
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, wsURL, nil)
+	if err != nil {
+		t.Fatalf("Dial() failed: %v", err)
+	}
+	defer conn.Close()
+
+	for _, req := range []models.LiveRequest{
+		{Blob: &genai.Blob{MIMEType: "audio/pcm", Data: []byte("audio")}},
+		{ActivityEnd: true},
+	} {
+		if err := conn.WriteJSON(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []models.Event
+	for len(got) < 2 {
+		var event models.Event
+		if err := conn.ReadJSON(&event); err != nil {
+			t.Fatalf("ReadJSON() failed: %v", err)
+		}
+		got = append(got, event)
+	}
+	if err := conn.WriteJSON(models.LiveRequest{Close: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+		t.Errorf("ReadMessage() error = %v, want normal closure", err)
+	}
+
+	if got[0].Content == nil || got[0].Content.Parts[0].Text != "heard audio" {
+		t.Errorf("first event content = %v, want %q", got[0].Content, "heard audio")
+	}
+	if !got[1].TurnComplete {
+		t.Errorf("second event = %+v, want turn complete", got[1])
+	}
+}
+
+func TestRunLiveHandler_Origin(t *testing.T) {
+	ctx := t.Context()
+	a, err := llmagent.New(llmagent.Config{Name: "test_app", Model: &testutil.MockLiveModel{
+		Respond: func(*model.LiveRequest) []*model.LLMResponse { return nil },
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "test_app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	apiController := controllers.NewRuntimeAPIController(sessionService, agent.NewSingleLoader(a), nil, []string{"https://app.example.com", "localhost:3000"})
+	srv := httptest.NewServer(controllers.NewErrorHandler(apiController.RunLiveHandler))
+	defer srv.Close()
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/run_live?app_name=test_app&user_id=user&session_id=session"
+
+	tests := []struct {
+		name   string
+		origin string
+		wantOK bool
+	}{
+		{name: "no origin", wantOK: true},
+		{name: "same origin", origin: srv.URL, wantOK: true},
+		{name: "allowed url", origin: "https://app.example.com", wantOK: true},
+		{name: "allowed host", origin: "http://localhost:3000", wantOK: true},
+		{name: "other site", origin: "https://evil.example.com"},
+		{name: "other port", origin: "http://localhost:4000"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			header := http.Header{}
+			if tc.origin != "" {
+				header.Set("Origin", tc.origin)
+			}
+			conn, resp, err := websocket.DefaultDialer.DialContext(ctx, wsURL, header)
+			if !tc.wantOK {
+				if err == nil {
+					conn.Close()
+					t.Fatalf("Dial() succeeded, want origin %q rejected", tc.origin)
+				}
+				if resp == nil || resp.StatusCode != http.StatusForbidden {
+					t.Errorf("Dial() response = %v, want status %d", resp, http.StatusForbidden)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Dial() failed: %v", err)
+			}
+			defer conn.Close()
+			if err := conn.WriteJSON(models.LiveRequest{Close: true}); err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				t.Errorf("ReadMessage() error = %v, want normal closure", err)
+			}
+		})
+	}
+}
+
+func TestRunLiveHandler_MissingParams(t *testing.T) {
+	apiController := controllers.NewRuntimeAPIController(session.InMemoryService(), agent.NewSingleLoader(nil), nil, nil)
+	rr := httptest.NewRecorder()
+	controllers.NewErrorHandler(apiController.RunLiveHandler)(rr, httptest.NewRequest(http.MethodGet, "/run_live?app_name=app", nil))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestResumeHandler_NotPaused(t *testing.T) {
+	ctx := t.Context()
+	a, err := llmagent.New(llmagent.Config{Name: "test_app", Model: &testutil.MockModel{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "test_app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	apiController := controllers.NewRuntimeAPIController(sessionService, agent.NewSingleLoader(a), nil, nil)
+
+	body, err := json.Marshal(models.ResumeRequest{
+		AppName:          "test_app",
+		UserId:           "user",
+		SessionId:        "session",
+		InvocationId:     "e-unknown",
+		FunctionResponse: genai.FunctionResponse{ID: "call1", Name: "approve"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	controllers.NewErrorHandler(apiController.ResumeHandler)(rr, httptest.NewRequest(http.MethodPost, "/run/resume", strings.NewReader(string(body))))
+	if rr.Code != http.StatusConflict {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusConflict)
+	}
+}
+
+func TestRunHandler_InvocationInProgress(t *testing.T) {
+	ctx := t.Context()
+	started, release := make(chan struct{}), make(chan struct{})
+	a, err := agent.New(agent.Config{
+		Name: "test_app",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				close(started)
+				<-release
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "test_app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	apiController := controllers.NewRuntimeAPIController(sessionService, agent.NewSingleLoader(a), nil, nil)
+	handler := controllers.NewErrorHandler(apiController.RunHandler)
+
+	body, err := json.Marshal(models.RunAgentRequest{
+		AppName:    "test_app",
+		UserId:     "user",
+		SessionId:  "session",
+		NewMessage: *genai.NewContentFromText("hi", genai.RoleUser),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler(first, httptest.NewRequest(http.MethodPost, "/run", strings.NewReader(string(body))))
+	}()
+	<-started
+
+	rr := httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodPost, "/run", strings.NewReader(string(body))))
+	if rr.Code != http.StatusConflict {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusConflict)
+	}
+
+	close(release)
+	<-done
+	if first.Code != http.StatusOK {
+		t.Errorf("first run status = %d, want %d", first.Code, http.StatusOK)
+	}
+}
+
+func TestListInvocationsHandler(t *testing.T) {
+	ctx := t.Context()
+	a, err := llmagent.New(llmagent.Config{
+		Name:  "test_app",
+		Model: &testutil.MockModel{Responses: []*genai.Content{genai.NewContentFromText("hello", genai.RoleModel)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "test_app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	apiController := controllers.NewRuntimeAPIController(sessionService, agent.NewSingleLoader(a), nil, nil)
+
+	body, err := json.Marshal(models.RunAgentRequest{
+		AppName:    "test_app",
+		UserId:     "user",
+		SessionId:  "session",
+		NewMessage: *genai.NewContentFromText("hi", genai.RoleUser),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := httptest.NewRecorder()
+	controllers.NewErrorHandler(apiController.RunHandler)(run, httptest.NewRequest(http.MethodPost, "/run", strings.NewReader(string(body))))
+	if run.Code != http.StatusOK {
+		t.Fatalf("run status = %d, want %d: %s", run.Code, http.StatusOK, run.Body)
+	}
+	var events []models.Event
+	if err := json.Unmarshal(run.Body.Bytes(), &events); err != nil {
+		t.Fatalf("failed to decode events: %v", err)
+	}
+
+	handler := controllers.NewErrorHandler(apiController.ListInvocationsHandler)
+	list := func(sessionID string) *httptest.ResponseRecorder {
+		vars := map[string]string{"app_name": "test_app", "user_id": "user", "session_id": sessionID}
+		req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/apps/test_app/users/user/sessions/"+sessionID+"/invocations", nil), vars)
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+		return rr
+	}
+	rr := list("session")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body)
+	}
+	var got []models.Invocation
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode invocations: %v", err)
+	}
+	want := []models.Invocation{{ID: events[0].InvocationID, RootAgent: "test_app", Status: "completed"}}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(models.Invocation{}, "StartTime", "EndTime")); diff != "" {
+		t.Errorf("invocations mismatch (-want +got):\n%s", diff)
+	}
+	if got[0].StartTime == 0 || got[0].EndTime < got[0].StartTime {
+		t.Errorf("invocation times = [%d, %d], want a valid range", got[0].StartTime, got[0].EndTime)
+	}
+
+	if rr := list("unknown"); rr.Code != http.StatusNotFound {
+		t.Errorf("unknown session status = %d, want %d", rr.Code, http.StatusNotFound)
+	}
+}
+
+func TestCancelInvocationHandler(t *testing.T) {
+	ctx := t.Context()
+	started := make(chan string)
+	a, err := agent.New(agent.Config{
+		Name: "test_app",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				started <- ctx.InvocationID()
+				<-ctx.Done()
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "test_app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	apiController := controllers.NewRuntimeAPIController(sessionService, agent.NewSingleLoader(a), nil, nil)
+	runHandler := controllers.NewErrorHandler(apiController.RunHandler)
+	cancelHandler := controllers.NewErrorHandler(apiController.CancelInvocationHandler)
+
+	body, err := json.Marshal(models.RunAgentRequest{
+		AppName:    "test_app",
+		UserId:     "user",
+		SessionId:  "session",
+		NewMessage: *genai.NewContentFromText("hi", genai.RoleUser),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runHandler(run, httptest.NewRequest(http.MethodPost, "/run", strings.NewReader(string(body))))
+	}()
+	invocationID := <-started
+
+	cancel := func(invocationID string) int {
+		vars := map[string]string{"app_name": "test_app", "user_id": "user", "session_id": "session", "invocation_id": invocationID}
+		req := mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/apps/test_app/users/user/sessions/session/invocations/"+invocationID+"/cancel", nil), vars)
+		rr := httptest.NewRecorder()
+		cancelHandler(rr, req)
+		return rr.Code
+	}
+	if got := cancel("unknown"); got != http.StatusNotFound {
+		t.Errorf("cancel of unknown invocation status = %d, want %d", got, http.StatusNotFound)
+	}
+	if got := cancel(invocationID); got != http.StatusOK {
+		t.Errorf("cancel status = %d, want %d", got, http.StatusOK)
+	}
+
+	<-done
+	if run.Code != http.StatusOK {
+		t.Fatalf("run status = %d, want %d: %s", run.Code, http.StatusOK, run.Body)
+	}
+	var events []models.Event
+	if err := json.Unmarshal(run.Body.Bytes(), &events); err != nil {
+		t.Fatalf("failed to decode events: %v", err)
+	}
+	if len(events) == 0 || events[len(events)-1].ErrorCode != runner.CancelledErrorCode {
+		t.Errorf("run events = %+v, want a final %s event", events, runner.CancelledErrorCode)
+	}
+	if got := cancel(invocationID); got != http.StatusNotFound {
+		t.Errorf("cancel of ended invocation status = %d, want %d", got, http.StatusNotFound)
+	}
+}
+
+func TestForkHandler(t *testing.T) {
+	ctx := t.Context()
+	a, err := llmagent.New(llmagent.Config{Name: "test_app", Model: &testutil.MockModel{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService := session.InMemoryService()
+	resp, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "test_app", UserID: "user", SessionID: "session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"e1", "e2"} {
+		event := session.NewEvent("invocation")
+		event.ID = id
+		event.Author = "user"
+		if err := sessionService.AppendEvent(ctx, resp.Session, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	apiController := controllers.NewRuntimeAPIController(sessionService, agent.NewSingleLoader(a), nil, nil)
+	handler := controllers.NewErrorHandler(apiController.ForkHandler)
+	vars := map[string]string{"app_name": "test_app", "user_id": "user", "session_id": "session"}
+
+	testCases := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantEvents []string
+	}{
+		{name: "fork", body: `{"eventId": "e1"}`, wantStatus: http.StatusOK, wantEvents: []string{"e1"}},
+		{name: "unknown event", body: `{"eventId": "e3"}`, wantStatus: http.StatusNotFound},
+		{name: "missing event", body: `{}`, wantStatus: http.StatusBadRequest},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/apps/test_app/users/user/sessions/session/fork", strings.NewReader(tc.body)), vars)
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+			if rr.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, tc.wantStatus, rr.Body)
+			}
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+			var got models.Session
+			if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+				t.Fatalf("failed to decode session: %v", err)
+			}
+			if got.ID == "session" {
+				t.Errorf("fork returned the source session")
+			}
+			var gotEvents []string
+			for _, event := range got.Events {
+				gotEvents = append(gotEvents, event.ID)
+			}
+			if diff := cmp.Diff(tc.wantEvents, gotEvents); diff != "" {
+				t.Errorf("forked events mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExportImportSessionHandlers(t *testing.T) {
+	ctx := t.Context()
+	a, err := llmagent.New(llmagent.Config{Name: "test_app", Model: &testutil.MockModel{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService, artifactService := session.InMemoryService(), artifact.InMemoryService()
+	resp, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "test_app", UserID: "user", SessionID: "session", State: map[string]any{"k": "v"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := session.NewEvent("invocation")
+	event.ID = "e1"
+	event.Author = "user"
+	event.Actions.ArtifactDelta = map[string]int64{"a.txt": 1}
+	if err := sessionService.AppendEvent(ctx, resp.Session, event); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := artifactService.Save(ctx, &artifact.SaveRequest{AppName: "test_app", UserID: "user", SessionID: "session", FileName: "a.txt", Part: genai.NewPartFromText("data")}); err != nil {
+		t.Fatal(err)
+	}
+	apiController := controllers.NewRuntimeAPIController(sessionService, agent.NewSingleLoader(a), artifactService, nil)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/apps/test_app/users/user/sessions/session/export", nil),
+		map[string]string{"app_name": "test_app", "user_id": "user", "session_id": "session"})
+	rr := httptest.NewRecorder()
+	controllers.NewErrorHandler(apiController.ExportSessionHandler)(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("export status = %d, want %d: %s", rr.Code, http.StatusOK, rr.Body)
+	}
+	bundle := rr.Body.String()
+
+	importVars := map[string]string{"app_name": "test_app", "user_id": "user"}
+	testCases := []struct {
+		name       string
+		target     string
+		body       string
+		wantStatus int
+	}{
+		{name: "import", target: "?session_id=copy", body: bundle, wantStatus: http.StatusOK},
+		{name: "existing session", body: bundle, wantStatus: http.StatusConflict},
+		{name: "unknown version", target: "?session_id=other", body: `{"version": 99}`, wantStatus: http.StatusBadRequest},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := mux.SetURLVars(httptest.NewRequest(http.MethodPost, "/apps/test_app/users/user/sessions:import"+tc.target, strings.NewReader(tc.body)), importVars)
+			rr := httptest.NewRecorder()
+			controllers.NewErrorHandler(apiController.ImportSessionHandler)(rr, req)
+			if rr.Code != tc.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, tc.wantStatus, rr.Body)
+			}
+			if tc.wantStatus != http.StatusOK {
+				return
+			}
+			var got models.Session
+			if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+				t.Fatalf("failed to decode session: %v", err)
+			}
+			if got.ID != "copy" || len(got.Events) != 1 || got.Events[0].ID != "e1" || got.State["k"] != "v" {
+				t.Errorf("imported session = %+v, want session copy with event e1 and state k=v", got)
+			}
+			loaded, err := artifactService.Load(ctx, &artifact.LoadRequest{AppName: "test_app", UserID: "user", SessionID: "copy", FileName: "a.txt"})
+			if err != nil {
+				t.Fatalf("failed to load imported artifact: %v", err)
+			}
+			if loaded.Part.Text != "data" {
+				t.Errorf("imported artifact = %q, want %q", loaded.Part.Text, "data")
+			}
+		})
+	}
+}
+
+func dialRunWS(t *testing.T, sessionService session.Service, a agent.Agent) *websocket.Conn {
+	t.Helper()
+	apiController := controllers.NewRuntimeAPIController(sessionService, agent.NewSingleLoader(a), nil, nil)
+	srv := httptest.NewServer(controllers.NewErrorHandler(apiController.RunWSHandler))
+	t.Cleanup(srv.Close)
+	conn, _, err := websocket.DefaultDialer.DialContext(t.Context(), "ws"+strings.TrimPrefix(srv.URL, "http")+"/run_ws", nil)
+	if err != nil {
+		t.Fatalf("Dial() failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestRunWSHandler(t *testing.T) {
+	ctx := t.Context()
+	a, err := llmagent.New(llmagent.Config{Name: "test_app", Model: &testutil.MockModel{
+		Responses: []*genai.Content{genai.NewContentFromText("hello", genai.RoleModel)},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "test_app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	conn := dialRunWS(t, sessionService, a)
+
+	if err := conn.WriteJSON(models.RunAgentRequest{
+		AppName:    "test_app",
+		UserId:     "user",
+		SessionId:  "session",
+		NewMessage: *genai.NewContentFromText("hi", genai.RoleUser),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var event models.Event
+	if err := conn.ReadJSON(&event); err != nil {
+		t.Fatalf("ReadJSON() failed: %v", err)
+	}
+	if event.Content == nil || event.Content.Parts[0].Text != "hello" {
+		t.Errorf("event content = %v, want %q", event.Content, "hello")
+	}
+	if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+		t.Errorf("ReadMessage() error = %v, want normal closure", err)
+	}
+}
+
+func TestRunWSHandler_Cancel(t *testing.T) {
+	ctx := t.Context()
+	a, err := agent.New(agent.Config{
+		Name: "test_app",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				event := session.NewEvent(ctx.InvocationID())
+				event.Author = "test_app"
+				event.Content = genai.NewContentFromText("working", genai.RoleModel)
+				if !yield(event, nil) {
+					return
+				}
+				<-ctx.Done()
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "test_app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	conn := dialRunWS(t, sessionService, a)
+
+	if err := conn.WriteJSON(models.RunAgentRequest{
+		AppName:    "test_app",
+		UserId:     "user",
+		SessionId:  "session",
+		NewMessage: *genai.NewContentFromText("hi", genai.RoleUser),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var event models.Event
+	if err := conn.ReadJSON(&event); err != nil {
+		t.Fatalf("ReadJSON() failed: %v", err)
+	}
+	if err := conn.WriteJSON(models.RunWSControl{Cancel: true}); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = conn.ReadMessage()
+	var closeErr *websocket.CloseError
+	if !errors.As(err, &closeErr) || closeErr.Code != websocket.CloseNormalClosure || closeErr.Text != "run cancelled" {
+		t.Errorf("ReadMessage() error = %v, want normal closure of the cancelled run", err)
+	}
+}
+
+func TestRunWSHandler_Rejected(t *testing.T) {
+	sessionService := session.InMemoryService()
+	for _, tc := range []struct {
+		name string
+		req  any
+	}{
+		{name: "invalid request", req: map[string]any{"unknown": true}},
+		{name: "unknown session", req: models.RunAgentRequest{AppName: "test_app", UserId: "user", SessionId: "missing"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			conn := dialRunWS(t, sessionService, nil)
+			if err := conn.WriteJSON(tc.req); err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.ClosePolicyViolation) {
+				t.Errorf("ReadMessage() error = %v, want policy violation", err)
+			}
+		})
+	}
+}
+
+func TestRunHandler_Schemas(t *testing.T) {
+	ctx := t.Context()
+	citySchema := &jsonschema.Schema{
+		Type:       "object",
+		Properties: map[string]*jsonschema.Schema{"city": {Type: "string"}},
+		Required:   []string{"city"},
+	}
+	// The agent replies with the message, unless it asks for plain text.
+	a, err := agent.New(agent.Config{
+		Name:         "test_app",
+		InputSchema:  citySchema,
+		OutputSchema: citySchema,
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*session.Event, error] {
+			return func(yield func(*session.Event, error) bool) {
+				reply := ctx.UserContent().Parts[0].Text
+				if strings.Contains(reply, "plain") {
+					reply = "sunny"
+				}
+				event := session.NewEvent(ctx.InvocationID())
+				event.Author = "test_app"
+				event.LLMResponse = model.LLMResponse{Content: genai.NewContentFromText(reply, genai.RoleModel)}
+				yield(event, nil)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService := session.InMemoryService()
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "test_app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	apiController := controllers.NewRuntimeAPIController(sessionService, agent.NewSingleLoader(a), nil, nil)
+	handler := controllers.NewErrorHandler(apiController.RunHandler)
+
+	for _, tc := range []struct {
+		name    string
+		message string
+		want    int
+	}{
+		{name: "valid", message: `{"city": "Paris"}`, want: http.StatusOK},
+		{name: "not JSON", message: "weather in Paris", want: http.StatusBadRequest},
+		{name: "missing property", message: `{"town": "Paris"}`, want: http.StatusBadRequest},
+		{name: "invalid response", message: `{"city": "Paris", "format": "plain"}`, want: http.StatusInternalServerError},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			body, err := json.Marshal(models.RunAgentRequest{
+				AppName:    "test_app",
+				UserId:     "user",
+				SessionId:  "session",
+				NewMessage: *genai.NewContentFromText(tc.message, genai.RoleUser),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+			handler(rr, httptest.NewRequest(http.MethodPost, "/run", strings.NewReader(string(body))))
+			if rr.Code != tc.want {
+				t.Errorf("status = %d, want %d, body: %s", rr.Code, tc.want, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"google.golang.org/adk/server/adkrest/internal/models"
+	"google.golang.org/adk/session"
+)
+
+// maxSessionBundleSize bounds the size of the bundles imported through the
+// API. The bundles carry the artifacts of their session, encoded in base64.
+const maxSessionBundleSize = 128 << 20
+
+// ExportSessionHandler returns the session from the path as a
+// [session.Bundle], with its events, state and artifacts.
+func (c *RuntimeAPIController) ExportSessionHandler(rw http.ResponseWriter, req *http.Request) error {
+	sessionID, err := models.SessionIDFromHTTPParameters(mux.Vars(req))
+	if err != nil {
+		return newStatusError(err, http.StatusBadRequest)
+	}
+	if sessionID.ID == "" {
+		return newStatusError(fmt.Errorf("session_id parameter is required"), http.StatusBadRequest)
+	}
+	if err := c.validateSessionExists(req.Context(), sessionID.AppName, sessionID.UserID, sessionID.ID); err != nil {
+		return err
+	}
+	bundle, err := session.Export(req.Context(), c.sessionService, &session.ExportRequest{
+		AppName:   sessionID.AppName,
+		UserID:    sessionID.UserID,
+		SessionID: sessionID.ID,
+		Artifacts: c.artifactService,
+	})
+	if err != nil {
+		return newStatusError(fmt.Errorf("export session: %w", err), http.StatusInternalServerError)
+	}
+	rw.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", sessionID.ID+".json"))
+	EncodeJSONResponse(bundle, http.StatusOK, rw)
+	return nil
+}
+
+// ImportSessionHandler creates a session of the user from the path with the
+// [session.Bundle] of the request body. The session keeps the ID of the
+// bundle, unless the session_id query parameter is set. It returns the new
+// session. Bundles larger than 128 MiB are rejected.
+func (c *RuntimeAPIController) ImportSessionHandler(rw http.ResponseWriter, req *http.Request) error {
+	sessionID, err := models.SessionIDFromHTTPParameters(mux.Vars(req))
+	if err != nil {
+		return newStatusError(err, http.StatusBadRequest)
+	}
+	var bundle session.Bundle
+	defer req.Body.Close()
+	if err := json.NewDecoder(http.MaxBytesReader(rw, req.Body, maxSessionBundleSize)).Decode(&bundle); err != nil {
+		if maxBytesErr := (*http.MaxBytesError)(nil); errors.As(err, &maxBytesErr) {
+			return newStatusError(fmt.Errorf("bundle is larger than %d bytes", maxBytesErr.Limit), http.StatusRequestEntityTooLarge)
+		}
+		return newStatusError(fmt.Errorf("decode request: %w", err), http.StatusBadRequest)
+	}
+	if bundle.Version != session.BundleFormatVersion {
+		return newStatusError(fmt.Errorf("unsupported bundle version %d", bundle.Version), http.StatusBadRequest)
+	}
+	id := req.URL.Query().Get("session_id")
+	if id == "" {
+		id = bundle.SessionID
+	}
+	if id != "" {
+		_, err := c.sessionService.Get(req.Context(), &session.GetRequest{AppName: sessionID.AppName, UserID: sessionID.UserID, SessionID: id})
+		if err == nil {
+			return newStatusError(fmt.Errorf("session %q already exists", id), http.StatusConflict)
+		}
+	}
+	imported, err := session.Import(req.Context(), c.sessionService, &session.ImportRequest{
+		Bundle:    &bundle,
+		AppName:   sessionID.AppName,
+		UserID:    sessionID.UserID,
+		SessionID: id,
+		Artifacts: c.artifactService,
+	})
+	if err != nil {
+		return newStatusError(fmt.Errorf("import session: %w", err), http.StatusInternalServerError)
+	}
+	respSession, err := models.FromSession(imported)
+	if err != nil {
+		return newStatusError(err, http.StatusInternalServerError)
+	}
+	EncodeJSONResponse(respSession, http.StatusOK, rw)
+	return nil
+}
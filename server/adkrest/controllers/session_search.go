@@ -0,0 +1,284 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gorilla/mux"
+
+	"google.golang.org/adk/embedding"
+	"google.golang.org/adk/server/adkrest/internal/models"
+	"google.golang.org/adk/session"
+)
+
+const (
+	searchModeFullText = "fulltext"
+	searchModeSemantic = "semantic"
+
+	// defaultSearchLimit is the number of results of SearchSessionsHandler
+	// when the limit parameter is not set.
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+
+	// snippetLength is the maximum number of characters of a snippet,
+	// without the ellipses.
+	snippetLength = 120
+	// snippetContext is the number of characters kept before a match.
+	snippetContext = 40
+)
+
+// SearchSessionsHandler searches the text of the events of the sessions of a
+// user and returns the best matching events with snippets. The query is set
+// by the q parameter and the number of results by the limit parameter.
+//
+// The search is full-text by default: the events must contain all the words
+// of the query, ignoring case. If the controller has an embedder, the search
+// is semantic and the events are ranked by their similarity to the query.
+// The mode parameter selects the mode explicitly, "fulltext" or "semantic".
+func (c *SessionsAPIController) SearchSessionsHandler(rw http.ResponseWriter, req *http.Request) {
+	sessionID, err := models.SessionIDFromHTTPParameters(mux.Vars(req))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	query := req.URL.Query()
+	q := strings.TrimSpace(query.Get("q"))
+	if q == "" {
+		http.Error(rw, "q parameter is required", http.StatusBadRequest)
+		return
+	}
+	limit := defaultSearchLimit
+	if v := query.Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit <= 0 || limit > maxSearchLimit {
+			http.Error(rw, fmt.Sprintf("invalid limit %q", v), http.StatusBadRequest)
+			return
+		}
+	}
+	mode := query.Get("mode")
+	switch mode {
+	case "":
+		mode = searchModeFullText
+		if c.embedder != nil {
+			mode = searchModeSemantic
+		}
+	case searchModeFullText:
+	case searchModeSemantic:
+		if c.embedder == nil {
+			http.Error(rw, "semantic search is not configured", http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(rw, fmt.Sprintf("invalid mode %q", mode), http.StatusBadRequest)
+		return
+	}
+
+	docs, err := c.searchableEvents(req.Context(), sessionID)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var results []models.SessionSearchResult
+	if mode == searchModeSemantic {
+		results, err = c.semanticSearch(req.Context(), q, docs)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	} else {
+		results = fullTextSearch(q, docs)
+	}
+	slices.SortStableFunc(results, func(a, b models.SessionSearchResult) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Time, a.Time)
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	EncodeJSONResponse(models.SearchSessionsResponse{Mode: mode, Results: results}, http.StatusOK, rw)
+}
+
+// searchableEvent is the text of an event of a session.
+type searchableEvent struct {
+	sessionID string
+	event     *session.Event
+	text      string
+}
+
+func (c *SessionsAPIController) searchableEvents(ctx context.Context, sessionID models.SessionID) ([]searchableEvent, error) {
+	resp, err := c.service.List(ctx, &session.ListRequest{
+		AppName: sessionID.AppName,
+		UserID:  sessionID.UserID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var docs []searchableEvent
+	for _, s := range resp.Sessions {
+		stored, err := c.service.Get(ctx, &session.GetRequest{
+			AppName:   s.AppName(),
+			UserID:    s.UserID(),
+			SessionID: s.ID(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		for event := range stored.Session.Events().All() {
+			if text := eventText(event); text != "" {
+				docs = append(docs, searchableEvent{sessionID: s.ID(), event: event, text: text})
+			}
+		}
+	}
+	return docs, nil
+}
+
+// eventText returns the text of the event, without the thoughts, with its
+// whitespace collapsed.
+func eventText(event *session.Event) string {
+	if event.Content == nil {
+		return ""
+	}
+	var words []string
+	for _, part := range event.Content.Parts {
+		if part.Text != "" && !part.Thought {
+			words = append(words, strings.Fields(part.Text)...)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func fullTextSearch(q string, docs []searchableEvent) []models.SessionSearchResult {
+	terms := strings.Fields(strings.ToLower(q))
+	var results []models.SessionSearchResult
+	for _, doc := range docs {
+		text := []rune(doc.text)
+		lower := toLowerRunes(text)
+		score, first := 0, -1
+		for _, term := range terms {
+			n, at := countRunes(lower, []rune(term))
+			if n == 0 {
+				score = 0
+				break
+			}
+			score += n
+			if first < 0 || at < first {
+				first = at
+			}
+		}
+		if score == 0 {
+			continue
+		}
+		results = append(results, searchResult(doc, snippet(text, first), float64(score)))
+	}
+	return results
+}
+
+func (c *SessionsAPIController) semanticSearch(ctx context.Context, q string, docs []searchableEvent) ([]models.SessionSearchResult, error) {
+	if len(docs) == 0 {
+		return nil, nil
+	}
+	texts := make([]string, len(docs))
+	for i, doc := range docs {
+		texts[i] = doc.text
+	}
+	docEmbeddings, err := c.embedder.Embed(ctx, texts, embedding.TaskTypeRetrievalDocument)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed events: %w", err)
+	}
+	queryEmbeddings, err := c.embedder.Embed(ctx, []string{q}, embedding.TaskTypeRetrievalQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	if len(docEmbeddings) != len(docs) || len(queryEmbeddings) != 1 {
+		return nil, fmt.Errorf("got %d embeddings for %d texts", len(docEmbeddings)+len(queryEmbeddings), len(docs)+1)
+	}
+	results := make([]models.SessionSearchResult, 0, len(docs))
+	for i, doc := range docs {
+		score, err := embedding.CosineSimilarity(queryEmbeddings[0], docEmbeddings[i])
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, searchResult(doc, snippet([]rune(doc.text), 0), score))
+	}
+	return results, nil
+}
+
+func searchResult(doc searchableEvent, snippet string, score float64) models.SessionSearchResult {
+	return models.SessionSearchResult{
+		SessionID:    doc.sessionID,
+		EventID:      doc.event.ID,
+		InvocationID: doc.event.InvocationID,
+		Author:       doc.event.Author,
+		Time:         doc.event.Timestamp.Unix(),
+		Snippet:      snippet,
+		Score:        score,
+	}
+}
+
+// snippet returns at most snippetLength characters of text starting a bit
+// before the match at index at, with ellipses where the text is cut.
+func snippet(text []rune, at int) string {
+	start := max(0, at-snippetContext)
+	end := min(len(text), start+snippetLength)
+	if end-start < snippetLength {
+		start = max(0, end-snippetLength)
+	}
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+	sb.WriteString(string(text[start:end]))
+	if end < len(text) {
+		sb.WriteString("…")
+	}
+	return sb.String()
+}
+
+// toLowerRunes lowers the case of each rune, keeping the indexes unchanged.
+func toLowerRunes(text []rune) []rune {
+	lower := make([]rune, len(text))
+	for i, r := range text {
+		lower[i] = unicode.ToLower(r)
+	}
+	return lower
+}
+
+// countRunes returns the number of non-overlapping occurrences of term in
+// text and the index of the first one, or -1.
+func countRunes(text, term []rune) (int, int) {
+	n, first := 0, -1
+	for i := 0; i+len(term) <= len(text); {
+		if slices.Equal(text[i:i+len(term)], term) {
+			if first < 0 {
+				first = i
+			}
+			n++
+			i += len(term)
+			continue
+		}
+		i++
+	}
+	return n, first
+}
@@ -17,10 +17,16 @@ package controllers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"maps"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 
+	"google.golang.org/adk/embedding"
 	"google.golang.org/adk/server/adkrest/internal/models"
 	"google.golang.org/adk/session"
 )
@@ -29,12 +35,14 @@ import (
 
 // SessionsAPIController is the controller for the Sessions API.
 type SessionsAPIController struct {
-	service session.Service
+	service  session.Service
+	embedder embedding.Embedder
 }
 
-// NewSessionsAPIController creates a new SessionsAPIController.
-func NewSessionsAPIController(service session.Service) *SessionsAPIController {
-	return &SessionsAPIController{service: service}
+// NewSessionsAPIController creates a new SessionsAPIController. The embedder
+// makes the session search semantic, it can be nil.
+func NewSessionsAPIController(service session.Service, embedder embedding.Embedder) *SessionsAPIController {
+	return &SessionsAPIController{service: service, embedder: embedder}
 }
 
 // CreateSesssionHTTP is a HTTP handler for the create session API.
@@ -135,6 +143,168 @@ func (c *SessionsAPIController) GetSessionHandler(rw http.ResponseWriter, req *h
 	EncodeJSONResponse(session, http.StatusOK, rw)
 }
 
+// GetSessionUsageHandler returns the number of tokens used in a specific
+// session, in total and per invocation.
+func (c *SessionsAPIController) GetSessionUsageHandler(rw http.ResponseWriter, req *http.Request) {
+	params := mux.Vars(req)
+	sessionID, err := models.SessionIDFromHTTPParameters(params)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if sessionID.ID == "" {
+		http.Error(rw, "session_id parameter is required", http.StatusBadRequest)
+		return
+	}
+	storedSession, err := c.service.Get(req.Context(), &session.GetRequest{
+		AppName:   sessionID.AppName,
+		UserID:    sessionID.UserID,
+		SessionID: sessionID.ID,
+	})
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	EncodeJSONResponse(models.FromSessionUsage(storedSession.Session), http.StatusOK, rw)
+}
+
+// GetSessionStateHandler returns the state of a specific session, including
+// the app and user state with their "app:" and "user:" prefixes.
+func (c *SessionsAPIController) GetSessionStateHandler(rw http.ResponseWriter, req *http.Request) {
+	params := mux.Vars(req)
+	sessionID, err := models.SessionIDFromHTTPParameters(params)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if sessionID.ID == "" {
+		http.Error(rw, "session_id parameter is required", http.StatusBadRequest)
+		return
+	}
+	state, err := c.sessionState(req.Context(), sessionID)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	EncodeJSONResponse(state, http.StatusOK, rw)
+}
+
+// PatchSessionStateHandler applies the state delta from the request to a
+// specific session and returns the updated state. The delta is recorded in
+// the session as an event authored by the user.
+func (c *SessionsAPIController) PatchSessionStateHandler(rw http.ResponseWriter, req *http.Request) {
+	params := mux.Vars(req)
+	sessionID, err := models.SessionIDFromHTTPParameters(params)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if sessionID.ID == "" {
+		http.Error(rw, "session_id parameter is required", http.StatusBadRequest)
+		return
+	}
+	var patchRequest models.PatchSessionStateRequest
+	if err := json.NewDecoder(req.Body).Decode(&patchRequest); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(patchRequest.StateDelta) == 0 {
+		http.Error(rw, "stateDelta is required", http.StatusBadRequest)
+		return
+	}
+	storedSession, err := c.service.Get(req.Context(), &session.GetRequest{
+		AppName:   sessionID.AppName,
+		UserID:    sessionID.UserID,
+		SessionID: sessionID.ID,
+	})
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusNotFound)
+		return
+	}
+	event := session.NewEvent("p-" + uuid.NewString())
+	event.Author = "user"
+	event.Actions.StateDelta = patchRequest.StateDelta
+	if err := c.service.AppendEvent(req.Context(), storedSession.Session, event); err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	state, err := c.sessionState(req.Context(), sessionID)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	EncodeJSONResponse(state, http.StatusOK, rw)
+}
+
+func (c *SessionsAPIController) sessionState(ctx context.Context, sessionID models.SessionID) (map[string]any, error) {
+	resp, err := c.service.Get(ctx, &session.GetRequest{
+		AppName:   sessionID.AppName,
+		UserID:    sessionID.UserID,
+		SessionID: sessionID.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	state := map[string]any{}
+	maps.Insert(state, resp.Session.State().All())
+	return state, nil
+}
+
+// defaultEventsPageSize is the page size of ListEventsHandler when the
+// page_size parameter is not set.
+const defaultEventsPageSize = 100
+
+// ListEventsHandler returns a page of the events of a specific session. The
+// page is selected by the page_size and page_token query parameters. The
+// events can be filtered by the after_timestamp (in seconds since the Unix
+// epoch) and author parameters; author can be repeated.
+func (c *SessionsAPIController) ListEventsHandler(rw http.ResponseWriter, req *http.Request) {
+	params := mux.Vars(req)
+	sessionID, err := models.SessionIDFromHTTPParameters(params)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if sessionID.ID == "" {
+		http.Error(rw, "session_id parameter is required", http.StatusBadRequest)
+		return
+	}
+	query := req.URL.Query()
+	listReq := &session.ListEventsRequest{
+		AppName:   sessionID.AppName,
+		UserID:    sessionID.UserID,
+		SessionID: sessionID.ID,
+		PageSize:  defaultEventsPageSize,
+		PageToken: query.Get("page_token"),
+		Authors:   query["author"],
+	}
+	if v := query.Get("page_size"); v != "" {
+		listReq.PageSize, err = strconv.Atoi(v)
+		if err != nil || listReq.PageSize <= 0 {
+			http.Error(rw, fmt.Sprintf("invalid page_size %q", v), http.StatusBadRequest)
+			return
+		}
+	}
+	if v := query.Get("after_timestamp"); v != "" {
+		seconds, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			http.Error(rw, fmt.Sprintf("invalid after_timestamp %q", v), http.StatusBadRequest)
+			return
+		}
+		listReq.After = time.UnixMilli(int64(seconds * 1000))
+	}
+	resp, err := c.service.ListEvents(req.Context(), listReq)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	events := make([]models.Event, 0, len(resp.Events))
+	for _, event := range resp.Events {
+		events = append(events, models.FromSessionEvent(*event))
+	}
+	EncodeJSONResponse(models.ListEventsResponse{Events: events, NextPageToken: resp.NextPageToken}, http.StatusOK, rw)
+}
+
 // ListSessions handles listing all sessions for a given app and user.
 func (c *SessionsAPIController) ListSessionsHandler(rw http.ResponseWriter, req *http.Request) {
 	params := mux.Vars(req)
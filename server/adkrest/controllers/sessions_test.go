@@ -16,6 +16,7 @@ package controllers_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -27,10 +28,14 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/gorilla/mux"
+	"google.golang.org/genai"
 
+	"google.golang.org/adk/embedding"
+	"google.golang.org/adk/model"
 	"google.golang.org/adk/server/adkrest/controllers"
 	"google.golang.org/adk/server/adkrest/internal/fakes"
 	"google.golang.org/adk/server/adkrest/internal/models"
+	"google.golang.org/adk/session"
 )
 
 func TestGetSession(t *testing.T) {
@@ -117,7 +122,7 @@ func TestGetSession(t *testing.T) {
 	for _, tt := range tc {
 		t.Run(tt.name, func(t *testing.T) {
 			sessionService := fakes.FakeSessionService{Sessions: tt.storedSessions}
-			apiController := controllers.NewSessionsAPIController(&sessionService)
+			apiController := controllers.NewSessionsAPIController(&sessionService, nil)
 			req, err := http.NewRequest(http.MethodGet, "/apps/testApp/users/testUser/sessions/testSession", nil)
 			if err != nil {
 				t.Fatalf("new request: %v", err)
@@ -230,7 +235,7 @@ func TestCreateSession(t *testing.T) {
 	for _, tt := range tc {
 		t.Run(tt.name, func(t *testing.T) {
 			sessionService := fakes.FakeSessionService{Sessions: tt.storedSessions}
-			apiController := controllers.NewSessionsAPIController(&sessionService)
+			apiController := controllers.NewSessionsAPIController(&sessionService, nil)
 			reqBytes, err := json.Marshal(tt.createRequestObj)
 			if err != nil {
 				t.Fatalf("marshal request: %v", err)
@@ -304,7 +309,7 @@ func TestDeleteSession(t *testing.T) {
 	for _, tt := range tc {
 		t.Run(tt.name, func(t *testing.T) {
 			sessionService := fakes.FakeSessionService{Sessions: tt.storedSessions}
-			apiController := controllers.NewSessionsAPIController(&sessionService)
+			apiController := controllers.NewSessionsAPIController(&sessionService, nil)
 			req, err := http.NewRequest(http.MethodDelete, "/apps/testApp/users/testUser/sessions/testSession", nil)
 			if err != nil {
 				t.Fatalf("new request: %v", err)
@@ -406,7 +411,7 @@ func TestListSessions(t *testing.T) {
 	for _, tt := range tc {
 		t.Run(tt.name, func(t *testing.T) {
 			sessionService := fakes.FakeSessionService{Sessions: tt.storedSessions}
-			apiController := controllers.NewSessionsAPIController(&sessionService)
+			apiController := controllers.NewSessionsAPIController(&sessionService, nil)
 			req, err := http.NewRequest(http.MethodDelete, "/apps/testApp/users/testUser/sessions/testSession", nil)
 			if err != nil {
 				t.Fatalf("new request: %v", err)
@@ -456,3 +461,310 @@ func EquateApproxInt(margin int64) cmp.Option {
 		return diff <= margin
 	})
 }
+
+func TestGetSessionUsage(t *testing.T) {
+	id := fakes.SessionKey{
+		AppName:   "testApp",
+		UserID:    "testUser",
+		SessionID: "testSession",
+	}
+	usageEvent := func(invocationID string, prompt, output int32, partial bool) *session.Event {
+		return &session.Event{
+			InvocationID: invocationID,
+			LLMResponse: model.LLMResponse{
+				Partial: partial,
+				UsageMetadata: &genai.GenerateContentResponseUsageMetadata{
+					PromptTokenCount:     prompt,
+					CandidatesTokenCount: output,
+					TotalTokenCount:      prompt + output,
+				},
+			},
+		}
+	}
+	sessionService := fakes.FakeSessionService{Sessions: map[fakes.SessionKey]fakes.TestSession{
+		id: {
+			Id:           id,
+			SessionState: fakes.TestState{},
+			SessionEvents: fakes.TestEvents{
+				{InvocationID: "inv1", Author: "user"},
+				usageEvent("inv1", 10, 5, false),
+				usageEvent("inv1", 20, 5, false),
+				usageEvent("inv2", 100, 1, true),
+				usageEvent("inv2", 100, 10, false),
+			},
+			UpdatedAt: time.Now(),
+		},
+	}}
+	apiController := controllers.NewSessionsAPIController(&sessionService, nil)
+	req, err := http.NewRequest(http.MethodGet, "/apps/testApp/users/testUser/sessions/testSession/usage", nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req = mux.SetURLVars(req, sessionVars(id))
+	rr := httptest.NewRecorder()
+
+	apiController.GetSessionUsageHandler(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+	var got models.SessionUsage
+	if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	want := models.SessionUsage{
+		Usage: models.Usage{PromptTokens: 130, OutputTokens: 20, TotalTokens: 150},
+		Invocations: map[string]models.Usage{
+			"inv1": {PromptTokens: 30, OutputTokens: 10, TotalTokens: 40},
+			"inv2": {PromptTokens: 100, OutputTokens: 10, TotalTokens: 110},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GetSessionUsage() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestListEvents(t *testing.T) {
+	id := fakes.SessionKey{
+		AppName:   "testApp",
+		UserID:    "testUser",
+		SessionID: "testSession",
+	}
+	start := time.Unix(1700000000, 0)
+	sessionService := fakes.FakeSessionService{Sessions: map[fakes.SessionKey]fakes.TestSession{
+		id: {
+			Id:           id,
+			SessionState: fakes.TestState{},
+			SessionEvents: fakes.TestEvents{
+				{ID: "e0", Author: "user", Timestamp: start},
+				{ID: "e1", Author: "agent", Timestamp: start.Add(time.Second)},
+				{ID: "e2", Author: "user", Timestamp: start.Add(2 * time.Second)},
+				{ID: "e3", Author: "user", Timestamp: start.Add(3 * time.Second)},
+			},
+			UpdatedAt: time.Now(),
+		},
+	}}
+	apiController := controllers.NewSessionsAPIController(&sessionService, nil)
+
+	list := func(query string) (models.ListEventsResponse, int) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, "/apps/testApp/users/testUser/sessions/testSession/events?"+query, nil)
+		if err != nil {
+			t.Fatalf("new request: %v", err)
+		}
+		req = mux.SetURLVars(req, sessionVars(id))
+		rr := httptest.NewRecorder()
+		apiController.ListEventsHandler(rr, req)
+		var resp models.ListEventsResponse
+		if rr.Code == http.StatusOK {
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+		}
+		return resp, rr.Code
+	}
+	ids := func(resp models.ListEventsResponse) []string {
+		var ids []string
+		for _, event := range resp.Events {
+			ids = append(ids, event.ID)
+		}
+		return ids
+	}
+
+	first, code := list("page_size=1&author=user&after_timestamp=1700000001")
+	if code != http.StatusOK {
+		t.Fatalf("handler returned wrong status code: got %v want %v", code, http.StatusOK)
+	}
+	if diff := cmp.Diff([]string{"e2"}, ids(first)); diff != "" {
+		t.Errorf("first page mismatch (-want +got):\n%s", diff)
+	}
+	if first.NextPageToken == "" {
+		t.Fatalf("first page has no next page token")
+	}
+	second, _ := list("page_size=1&author=user&after_timestamp=1700000001&page_token=" + first.NextPageToken)
+	if diff := cmp.Diff([]string{"e3"}, ids(second)); diff != "" {
+		t.Errorf("second page mismatch (-want +got):\n%s", diff)
+	}
+	if second.NextPageToken != "" {
+		t.Errorf("second page token = %q, want empty", second.NextPageToken)
+	}
+
+	if _, code := list("page_size=-1"); code != http.StatusBadRequest {
+		t.Errorf("invalid page_size: got status %v want %v", code, http.StatusBadRequest)
+	}
+}
+
+func TestPatchSessionState(t *testing.T) {
+	ctx := t.Context()
+	sessionService := session.InMemoryService()
+	for _, sessionID := range []string{"s1", "s2"} {
+		if _, err := sessionService.Create(ctx, &session.CreateRequest{
+			AppName:   "testApp",
+			UserID:    "testUser",
+			SessionID: sessionID,
+			State:     map[string]any{"k": "v"},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	apiController := controllers.NewSessionsAPIController(sessionService, nil)
+	state := func(method, sessionID, body string) (map[string]any, int) {
+		t.Helper()
+		req, err := http.NewRequest(method, "/apps/testApp/users/testUser/sessions/"+sessionID+"/state", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("new request: %v", err)
+		}
+		req = mux.SetURLVars(req, sessionVars(fakes.SessionKey{AppName: "testApp", UserID: "testUser", SessionID: sessionID}))
+		rr := httptest.NewRecorder()
+		if method == http.MethodPatch {
+			apiController.PatchSessionStateHandler(rr, req)
+		} else {
+			apiController.GetSessionStateHandler(rr, req)
+		}
+		var got map[string]any
+		if rr.Code == http.StatusOK {
+			if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+		}
+		return got, rr.Code
+	}
+
+	got, code := state(http.MethodPatch, "s1", `{"stateDelta": {"k": "v2", "app:a": 1, "user:u": 2, "temp:t": 3}}`)
+	if code != http.StatusOK {
+		t.Fatalf("PATCH returned wrong status code: got %v want %v", code, http.StatusOK)
+	}
+	want := map[string]any{"k": "v2", "app:a": float64(1), "user:u": float64(2)}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("PATCH state mismatch (-want +got):\n%s", diff)
+	}
+
+	// The app and user state is shared with the other sessions.
+	got, code = state(http.MethodGet, "s2", "")
+	if code != http.StatusOK {
+		t.Fatalf("GET returned wrong status code: got %v want %v", code, http.StatusOK)
+	}
+	want = map[string]any{"k": "v", "app:a": float64(1), "user:u": float64(2)}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GET state mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, code := state(http.MethodPatch, "s1", `{}`); code != http.StatusBadRequest {
+		t.Errorf("empty PATCH: got status %v want %v", code, http.StatusBadRequest)
+	}
+	if _, code := state(http.MethodPatch, "missing", `{"stateDelta": {"k": 1}}`); code != http.StatusNotFound {
+		t.Errorf("PATCH missing session: got status %v want %v", code, http.StatusNotFound)
+	}
+}
+
+// keywordEmbedder embeds texts as the number of occurrences of each of its
+// keywords.
+type keywordEmbedder struct {
+	keywords []string
+}
+
+func (e *keywordEmbedder) Embed(ctx context.Context, texts []string, taskType embedding.TaskType) ([][]float32, error) {
+	res := make([][]float32, len(texts))
+	for i, text := range texts {
+		res[i] = make([]float32, len(e.keywords))
+		for j, k := range e.keywords {
+			res[i][j] = float32(strings.Count(strings.ToLower(text), k))
+		}
+	}
+	return res, nil
+}
+
+func TestSearchSessions(t *testing.T) {
+	ctx := t.Context()
+	sessionService := session.InMemoryService()
+	texts := map[string][]string{
+		"s1": {"My cat is called Tom", "Tom the cat is " + strings.Repeat("very ", 40) + "nice"},
+		"s2": {"I have a dog", "Dogs and cats"},
+	}
+	start := time.Unix(1700000000, 0)
+	for i, sessionID := range []string{"s1", "s2"} {
+		created, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "testApp", UserID: "testUser", SessionID: sessionID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j, text := range texts[sessionID] {
+			event := session.NewEvent(fmt.Sprintf("inv%d", i))
+			event.ID = fmt.Sprintf("%s-e%d", sessionID, j)
+			event.Author = "user"
+			event.Timestamp = start.Add(time.Duration(2*i+j) * time.Minute)
+			event.Content = genai.NewContentFromText(text, genai.RoleUser)
+			if err := sessionService.AppendEvent(ctx, created.Session, event); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	search := func(embedder embedding.Embedder, query string) (models.SearchSessionsResponse, int) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, "/apps/testApp/users/testUser/sessions:search?"+query, nil)
+		if err != nil {
+			t.Fatalf("new request: %v", err)
+		}
+		req = mux.SetURLVars(req, map[string]string{"app_name": "testApp", "user_id": "testUser"})
+		rr := httptest.NewRecorder()
+		controllers.NewSessionsAPIController(sessionService, embedder).SearchSessionsHandler(rr, req)
+		var got models.SearchSessionsResponse
+		if rr.Code == http.StatusOK {
+			if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+		}
+		return got, rr.Code
+	}
+
+	got, code := search(nil, "q=Tom+CAT")
+	if code != http.StatusOK {
+		t.Fatalf("full-text search returned wrong status code: got %v want %v", code, http.StatusOK)
+	}
+	want := models.SearchSessionsResponse{
+		Mode: "fulltext",
+		Results: []models.SessionSearchResult{
+			{
+				SessionID:    "s1",
+				EventID:      "s1-e1",
+				InvocationID: "inv0",
+				Author:       "user",
+				Time:         start.Add(time.Minute).Unix(),
+				Snippet:      "Tom the cat is " + strings.Repeat("very ", 21) + "…",
+				Score:        2,
+			},
+			{
+				SessionID:    "s1",
+				EventID:      "s1-e0",
+				InvocationID: "inv0",
+				Author:       "user",
+				Time:         start.Unix(),
+				Snippet:      "My cat is called Tom",
+				Score:        2,
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("full-text search mismatch (-want +got):\n%s", diff)
+	}
+
+	got, code = search(&keywordEmbedder{keywords: []string{"cat", "dog"}}, "q=dog&limit=2")
+	if code != http.StatusOK {
+		t.Fatalf("semantic search returned wrong status code: got %v want %v", code, http.StatusOK)
+	}
+	gotIDs := []string{}
+	for _, r := range got.Results {
+		gotIDs = append(gotIDs, r.EventID)
+	}
+	if got.Mode != "semantic" {
+		t.Errorf("semantic search mode = %q, want %q", got.Mode, "semantic")
+	}
+	if diff := cmp.Diff([]string{"s2-e0", "s2-e1"}, gotIDs); diff != "" {
+		t.Errorf("semantic search results mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, query := range []string{"", "q=cat&limit=0", "q=cat&mode=semantic", "q=cat&mode=other"} {
+		if _, code := search(nil, query); code != http.StatusBadRequest {
+			t.Errorf("search(%q): got status %v want %v", query, code, http.StatusBadRequest)
+		}
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"google.golang.org/adk/server/adkrest/controllers"
 	"google.golang.org/adk/server/adkrest/internal/routers"
 	"google.golang.org/adk/server/adkrest/internal/services"
+	"google.golang.org/adk/server/ratelimit"
 )
 
 // NewHandler creates and returns an http.Handler for the ADK REST API.
@@ -33,14 +34,18 @@ func NewHandler(config *launcher.Config) http.Handler {
 	telemetry.AddSpanProcessor(sdktrace.NewSimpleSpanProcessor(adkExporter))
 
 	router := mux.NewRouter().StrictSlash(true)
+	router.Use(controllers.Authorize(config.Authorizer))
+	if config.RateLimit != nil {
+		router.Use(ratelimit.Middleware(*config.RateLimit))
+	}
 	// TODO: Allow taking a prefix to allow customizing the path
 	// where the ADK REST API will be served.
 	setupRouter(router,
-		routers.NewSessionsAPIRouter(controllers.NewSessionsAPIController(config.SessionService)),
-		routers.NewRuntimeAPIRouter(controllers.NewRuntimeAPIController(config.SessionService, config.AgentLoader, config.ArtifactService)),
-		routers.NewAppsAPIRouter(controllers.NewAppsAPIController(config.AgentLoader)),
+		routers.NewSessionsAPIRouter(controllers.NewSessionsAPIController(config.SessionService, config.Embedder)),
+		routers.NewRuntimeAPIRouter(controllers.NewRuntimeAPIController(config.SessionService, config.AgentLoader, config.ArtifactService, config.AllowedOrigins)),
+		routers.NewAppsAPIRouter(controllers.NewAppsAPIController(config.AgentLoader, config.AgentRegistry)),
 		routers.NewDebugAPIRouter(controllers.NewDebugAPIController(config.SessionService, config.AgentLoader, adkExporter)),
-		routers.NewArtifactsAPIRouter(controllers.NewArtifactsAPIController(config.ArtifactService)),
+		routers.NewArtifactsAPIRouter(controllers.NewArtifactsAPIController(config.ArtifactService, config.MaxArtifactSize)),
 		&routers.EvalAPIRouter{},
 	)
 	return router
@@ -20,8 +20,10 @@ import (
 	"context"
 	"fmt"
 	"iter"
+	"slices"
 	"time"
 
+	"google.golang.org/adk/internal/sessionutils"
 	"google.golang.org/adk/session"
 )
 
@@ -174,6 +176,17 @@ func (s *FakeSessionService) Delete(ctx context.Context, req *session.DeleteRequ
 	return nil
 }
 
+func (s *FakeSessionService) Cleanup(ctx context.Context, olderThan time.Time) (int, error) {
+	deleted := 0
+	for id, sess := range s.Sessions {
+		if sess.UpdatedAt.Before(olderThan) {
+			delete(s.Sessions, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
 func (s *FakeSessionService) AppendEvent(ctx context.Context, curSession session.Session, event *session.Event) error {
 	testSession, ok := curSession.(*TestSession)
 	if !ok {
@@ -185,4 +198,35 @@ func (s *FakeSessionService) AppendEvent(ctx context.Context, curSession session
 	return nil
 }
 
+func (s *FakeSessionService) ListEvents(ctx context.Context, req *session.ListEventsRequest) (*session.ListEventsResponse, error) {
+	sess, ok := s.Sessions[SessionKey{
+		AppName:   req.AppName,
+		UserID:    req.UserID,
+		SessionID: req.SessionID,
+	}]
+	if !ok {
+		return nil, fmt.Errorf("not found")
+	}
+	offset, err := sessionutils.DecodePageToken(req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	events := []*session.Event{}
+	for _, event := range sess.SessionEvents {
+		if !req.After.IsZero() && event.Timestamp.Before(req.After) {
+			continue
+		}
+		if len(req.Authors) > 0 && !slices.Contains(req.Authors, event.Author) {
+			continue
+		}
+		events = append(events, event)
+	}
+	resp := &session.ListEventsResponse{Events: events[min(offset, len(events)):]}
+	if req.PageSize > 0 && len(resp.Events) > req.PageSize {
+		resp.Events = resp.Events[:req.PageSize]
+		resp.NextPageToken = sessionutils.EncodePageToken(offset + req.PageSize)
+	}
+	return resp, nil
+}
+
 var _ session.Service = (*FakeSessionService)(nil)
@@ -0,0 +1,32 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "google.golang.org/genai"
+
+// SaveArtifactRequest is the JSON body of a request to upload an artifact.
+type SaveArtifactRequest struct {
+	// InlineData holds the artifact bytes and their MIME type. The data is
+	// base64 encoded in JSON.
+	InlineData *genai.Blob `json:"inlineData,omitempty"`
+	// Text is used for text artifacts instead of InlineData.
+	Text string `json:"text,omitempty"`
+}
+
+// SaveArtifactResponse is the response to an artifact upload.
+type SaveArtifactResponse struct {
+	// Version is the version assigned to the uploaded artifact.
+	Version int64 `json:"version"`
+}
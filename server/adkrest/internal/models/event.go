@@ -19,32 +19,70 @@ import (
 
 	"google.golang.org/genai"
 
+	"google.golang.org/adk/auth"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/session"
 )
 
 // EventActions represent a data model for session.EventActions
 type EventActions struct {
-	StateDelta    map[string]any   `json:"stateDelta"`
-	ArtifactDelta map[string]int64 `json:"artifactDelta"`
+	StateDelta           map[string]any          `json:"stateDelta"`
+	ArtifactDelta        map[string]int64        `json:"artifactDelta"`
+	SkipSummarization    bool                    `json:"skipSummarization,omitempty"`
+	TransferToAgent      string                  `json:"transferToAgent,omitempty"`
+	Escalate             bool                    `json:"escalate,omitempty"`
+	RequestedAuthConfigs map[string]*auth.Config `json:"requestedAuthConfigs,omitempty"`
+	Compaction           *EventCompaction        `json:"compaction,omitempty"`
+}
+
+// EventCompaction represent a data model for session.EventCompaction
+type EventCompaction struct {
+	StartTime        float64        `json:"startTimestamp"`
+	EndTime          float64        `json:"endTimestamp"`
+	CompactedContent *genai.Content `json:"compactedContent"`
+}
+
+func toEventCompaction(c *session.EventCompaction) *EventCompaction {
+	if c == nil {
+		return nil
+	}
+	return &EventCompaction{
+		StartTime:        float64(c.StartTime.UnixNano()) / float64(time.Second),
+		EndTime:          float64(c.EndTime.UnixNano()) / float64(time.Second),
+		CompactedContent: c.Content,
+	}
+}
+
+func (c *EventCompaction) toSessionCompaction() *session.EventCompaction {
+	if c == nil {
+		return nil
+	}
+	return &session.EventCompaction{
+		StartTime: time.Unix(0, int64(c.StartTime*float64(time.Second))),
+		EndTime:   time.Unix(0, int64(c.EndTime*float64(time.Second))),
+		Content:   c.CompactedContent,
+	}
 }
 
 // Event represents a single event in a session.
 type Event struct {
-	ID                 string                   `json:"id"`
-	Time               int64                    `json:"time"`
-	InvocationID       string                   `json:"invocationId"`
-	Branch             string                   `json:"branch"`
-	Author             string                   `json:"author"`
-	Partial            bool                     `json:"partial"`
-	LongRunningToolIDs []string                 `json:"longRunningToolIds"`
-	Content            *genai.Content           `json:"content"`
-	GroundingMetadata  *genai.GroundingMetadata `json:"groundingMetadata"`
-	TurnComplete       bool                     `json:"turnComplete"`
-	Interrupted        bool                     `json:"interrupted"`
-	ErrorCode          string                   `json:"errorCode"`
-	ErrorMessage       string                   `json:"errorMessage"`
-	Actions            EventActions             `json:"actions"`
+	ID                  string                                      `json:"id"`
+	Time                int64                                       `json:"time"`
+	InvocationID        string                                      `json:"invocationId"`
+	Branch              string                                      `json:"branch"`
+	Author              string                                      `json:"author"`
+	Partial             bool                                        `json:"partial"`
+	LongRunningToolIDs  []string                                    `json:"longRunningToolIds"`
+	Content             *genai.Content                              `json:"content"`
+	GroundingMetadata   *genai.GroundingMetadata                    `json:"groundingMetadata"`
+	UsageMetadata       *genai.GenerateContentResponseUsageMetadata `json:"usageMetadata,omitempty"`
+	TurnComplete        bool                                        `json:"turnComplete"`
+	Interrupted         bool                                        `json:"interrupted"`
+	InputTranscription  *genai.Transcription                        `json:"inputTranscription,omitempty"`
+	OutputTranscription *genai.Transcription                        `json:"outputTranscription,omitempty"`
+	ErrorCode           string                                      `json:"errorCode"`
+	ErrorMessage        string                                      `json:"errorMessage"`
+	Actions             EventActions                                `json:"actions"`
 }
 
 // ToSessionEvent maps Event data struct to session.Event
@@ -57,17 +95,25 @@ func ToSessionEvent(event Event) *session.Event {
 		Author:             event.Author,
 		LongRunningToolIDs: event.LongRunningToolIDs,
 		LLMResponse: model.LLMResponse{
-			Content:           event.Content,
-			GroundingMetadata: event.GroundingMetadata,
-			Partial:           event.Partial,
-			TurnComplete:      event.TurnComplete,
-			Interrupted:       event.Interrupted,
-			ErrorCode:         event.ErrorCode,
-			ErrorMessage:      event.ErrorMessage,
+			Content:             event.Content,
+			GroundingMetadata:   event.GroundingMetadata,
+			UsageMetadata:       event.UsageMetadata,
+			Partial:             event.Partial,
+			TurnComplete:        event.TurnComplete,
+			Interrupted:         event.Interrupted,
+			InputTranscription:  event.InputTranscription,
+			OutputTranscription: event.OutputTranscription,
+			ErrorCode:           event.ErrorCode,
+			ErrorMessage:        event.ErrorMessage,
 		},
 		Actions: session.EventActions{
-			StateDelta:    event.Actions.StateDelta,
-			ArtifactDelta: event.Actions.ArtifactDelta,
+			StateDelta:           event.Actions.StateDelta,
+			ArtifactDelta:        event.Actions.ArtifactDelta,
+			SkipSummarization:    event.Actions.SkipSummarization,
+			TransferToAgent:      event.Actions.TransferToAgent,
+			Escalate:             event.Actions.Escalate,
+			RequestedAuthConfigs: event.Actions.RequestedAuthConfigs,
+			Compaction:           event.Actions.Compaction.toSessionCompaction(),
 		},
 	}
 }
@@ -75,22 +121,36 @@ func ToSessionEvent(event Event) *session.Event {
 // FromSessionEvent maps session.Event to Event data struct
 func FromSessionEvent(event session.Event) Event {
 	return Event{
-		ID:                 event.ID,
-		Time:               event.Timestamp.Unix(),
-		InvocationID:       event.InvocationID,
-		Branch:             event.Branch,
-		Author:             event.Author,
-		Partial:            event.Partial,
-		LongRunningToolIDs: event.LongRunningToolIDs,
-		Content:            event.LLMResponse.Content,
-		GroundingMetadata:  event.LLMResponse.GroundingMetadata,
-		TurnComplete:       event.LLMResponse.TurnComplete,
-		Interrupted:        event.LLMResponse.Interrupted,
-		ErrorCode:          event.LLMResponse.ErrorCode,
-		ErrorMessage:       event.LLMResponse.ErrorMessage,
+		ID:                  event.ID,
+		Time:                event.Timestamp.Unix(),
+		InvocationID:        event.InvocationID,
+		Branch:              event.Branch,
+		Author:              event.Author,
+		Partial:             event.Partial,
+		LongRunningToolIDs:  event.LongRunningToolIDs,
+		Content:             event.LLMResponse.Content,
+		GroundingMetadata:   event.LLMResponse.GroundingMetadata,
+		UsageMetadata:       event.LLMResponse.UsageMetadata,
+		TurnComplete:        event.LLMResponse.TurnComplete,
+		Interrupted:         event.LLMResponse.Interrupted,
+		InputTranscription:  event.LLMResponse.InputTranscription,
+		OutputTranscription: event.LLMResponse.OutputTranscription,
+		ErrorCode:           event.LLMResponse.ErrorCode,
+		ErrorMessage:        event.LLMResponse.ErrorMessage,
 		Actions: EventActions{
-			StateDelta:    event.Actions.StateDelta,
-			ArtifactDelta: event.Actions.ArtifactDelta,
+			StateDelta:           event.Actions.StateDelta,
+			ArtifactDelta:        event.Actions.ArtifactDelta,
+			SkipSummarization:    event.Actions.SkipSummarization,
+			TransferToAgent:      event.Actions.TransferToAgent,
+			Escalate:             event.Actions.Escalate,
+			RequestedAuthConfigs: event.Actions.RequestedAuthConfigs,
+			Compaction:           toEventCompaction(event.Actions.Compaction),
 		},
 	}
 }
+
+// ListEventsResponse is a page of the events of a session.
+type ListEventsResponse struct {
+	Events        []Event `json:"events"`
+	NextPageToken string  `json:"nextPageToken,omitempty"`
+}
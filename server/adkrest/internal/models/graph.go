@@ -0,0 +1,53 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+// AgentGraph is the structure of an agent tree, for the frontends that do not
+// render DOT.
+type AgentGraph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode is an agent or a tool of an AgentGraph.
+type GraphNode struct {
+	// ID is the name of the agent or the tool.
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	// Type is either "agent" or "tool".
+	Type string `json:"type"`
+	// AgentType is the kind of agent, e.g. "LLMAgent" or "SequentialAgent".
+	AgentType string `json:"agentType,omitempty"`
+	// Group is the ID of the workflow agent containing the node, if any.
+	Group       string `json:"group,omitempty"`
+	Highlighted bool   `json:"highlighted,omitempty"`
+	// URL is the agent card URL of a remote agent, served by another
+	// process.
+	URL string `json:"url,omitempty"`
+}
+
+// GraphEdge connects two nodes of an AgentGraph.
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Type is one of "tool", "sub_agent", "sequence", "loop" or "graph".
+	Type        string `json:"type"`
+	Highlighted bool   `json:"highlighted,omitempty"`
+	// Reversed is set when the highlighted interaction goes from To to From.
+	Reversed bool `json:"reversed,omitempty"`
+	// CrossProcess is set when one of the nodes is a remote agent, so the
+	// interaction goes through the A2A protocol.
+	CrossProcess bool `json:"crossProcess,omitempty"`
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"google.golang.org/adk/runner"
+)
+
+// Invocation summarizes an invocation of a session.
+type Invocation struct {
+	ID        string `json:"id"`
+	RootAgent string `json:"rootAgent"`
+	// StartTime and EndTime are Unix timestamps in seconds. EndTime is
+	// omitted while the invocation is running.
+	StartTime int64 `json:"startTime"`
+	EndTime   int64 `json:"endTime,omitempty"`
+	// Status is one of "running", "completed", "error" and "cancelled".
+	Status string `json:"status"`
+}
+
+// FromInvocation converts a runner invocation to its API representation.
+func FromInvocation(inv *runner.Invocation) Invocation {
+	result := Invocation{
+		ID:        inv.ID,
+		RootAgent: inv.RootAgent,
+		StartTime: inv.StartTime.Unix(),
+		Status:    string(inv.Status),
+	}
+	if !inv.EndTime.IsZero() {
+		result.EndTime = inv.EndTime.Unix()
+	}
+	return result
+}
@@ -50,3 +50,48 @@ func (req RunAgentRequest) AssertRunAgentRequestRequired() error {
 
 	return nil
 }
+
+// ResumeRequest continues an invocation that paused on a long-running
+// function call.
+type ResumeRequest struct {
+	AppName string `json:"appName"`
+
+	UserId string `json:"userId"`
+
+	SessionId string `json:"sessionId"`
+
+	InvocationId string `json:"invocationId"`
+
+	// FunctionResponse is the result of the pending function call.
+	FunctionResponse genai.FunctionResponse `json:"functionResponse"`
+
+	Streaming bool `json:"streaming,omitempty"`
+}
+
+// ForkSessionRequest creates a new session with the events of a session up
+// to, and including, the given event.
+type ForkSessionRequest struct {
+	EventId string `json:"eventId"`
+}
+
+// LiveRequest is a message sent by the client over the /run_live WebSocket.
+// Only one of its fields should be set.
+type LiveRequest struct {
+	// Content is sent to the agent as a complete turn.
+	Content *genai.Content `json:"content,omitempty"`
+	// Blob is realtime input, e.g. an audio chunk.
+	Blob *genai.Blob `json:"blob,omitempty"`
+	// ActivityStart and ActivityEnd mark the start and the end of the user
+	// activity, e.g. speech.
+	ActivityStart bool `json:"activityStart,omitempty"`
+	ActivityEnd   bool `json:"activityEnd,omitempty"`
+	// Close ends the live session.
+	Close bool `json:"close,omitempty"`
+}
+
+// RunWSControl is a message sent by the client over the /run_ws WebSocket
+// after the run request.
+type RunWSControl struct {
+	// Cancel stops the run.
+	Cancel bool `json:"cancel,omitempty"`
+}
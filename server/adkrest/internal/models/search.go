@@ -0,0 +1,38 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+// SessionSearchResult is an event matching a session search.
+type SessionSearchResult struct {
+	SessionID    string `json:"sessionId"`
+	EventID      string `json:"eventId"`
+	InvocationID string `json:"invocationId"`
+	Author       string `json:"author"`
+	// Time is the Unix timestamp of the event in seconds.
+	Time int64 `json:"time"`
+	// Snippet is the part of the text of the event around the match.
+	Snippet string `json:"snippet"`
+	// Score ranks the results, higher is better. It is the number of
+	// occurrences of the query terms for full-text searches and the cosine
+	// similarity with the query for semantic searches.
+	Score float64 `json:"score"`
+}
+
+// SearchSessionsResponse is the response of a session search.
+type SearchSessionsResponse struct {
+	// Mode is "fulltext" or "semantic".
+	Mode    string                `json:"mode"`
+	Results []SessionSearchResult `json:"results"`
+}
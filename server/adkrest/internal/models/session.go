@@ -38,6 +38,15 @@ type CreateSessionRequest struct {
 	Events []Event        `json:"events"`
 }
 
+// PatchSessionStateRequest is the body of a request to update the state of
+// a session.
+type PatchSessionStateRequest struct {
+	// StateDelta is applied to the state. Keys with the "app:" and "user:"
+	// prefixes update the state shared by all sessions of the app and of the
+	// user. Keys with the "temp:" prefix are ignored.
+	StateDelta map[string]any `json:"stateDelta"`
+}
+
 type SessionID struct {
 	ID      string `mapstructure:"session_id,optional"`
 	AppName string `mapstructure:"app_name,required"`
@@ -0,0 +1,28 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+// Span is a finished trace span, as shown by the trace view of the web UI.
+type Span struct {
+	Name    string `json:"name"`
+	SpanID  string `json:"span_id"`
+	TraceID string `json:"trace_id"`
+	// ParentSpanID is empty for the root spans.
+	ParentSpanID string `json:"parent_span_id,omitempty"`
+	// StartTime and EndTime are in nanoseconds since the Unix epoch.
+	StartTime  int64          `json:"start_time"`
+	EndTime    int64          `json:"end_time"`
+	Attributes map[string]any `json:"attributes"`
+}
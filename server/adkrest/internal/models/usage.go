@@ -0,0 +1,53 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"google.golang.org/adk/session"
+)
+
+// Usage represents the number of tokens used by model calls.
+type Usage struct {
+	PromptTokens int64 `json:"promptTokens"`
+	OutputTokens int64 `json:"outputTokens"`
+	TotalTokens  int64 `json:"totalTokens"`
+}
+
+// SessionUsage represents the tokens used in a session, in total and per
+// invocation.
+type SessionUsage struct {
+	Usage
+	Invocations map[string]Usage `json:"invocations"`
+}
+
+// FromSessionUsage computes the token usage of the session's events.
+func FromSessionUsage(s session.Session) SessionUsage {
+	invocations := map[string]Usage{}
+	for id, u := range session.InvocationUsage(s.Events()) {
+		invocations[id] = fromUsage(u)
+	}
+	return SessionUsage{
+		Usage:       fromUsage(session.TotalUsage(s.Events())),
+		Invocations: invocations,
+	}
+}
+
+func fromUsage(u session.Usage) Usage {
+	return Usage{
+		PromptTokens: u.PromptTokens,
+		OutputTokens: u.OutputTokens,
+		TotalTokens:  u.TotalTokens,
+	}
+}
@@ -39,5 +39,23 @@ func (r *AppsAPIRouter) Routes() Routes {
 			Pattern:     "/list-apps",
 			HandlerFunc: r.appsController.ListAppsHandler,
 		},
+		Route{
+			Name:        "ReloadApps",
+			Methods:     []string{http.MethodPost},
+			Pattern:     "/reload-apps",
+			HandlerFunc: controllers.NewErrorHandler(r.appsController.ReloadAppsHandler),
+		},
+		Route{
+			Name:        "RegisterApp",
+			Methods:     []string{http.MethodPost},
+			Pattern:     "/apps",
+			HandlerFunc: controllers.NewErrorHandler(r.appsController.RegisterAppHandler),
+		},
+		Route{
+			Name:        "UnregisterApp",
+			Methods:     []string{http.MethodDelete},
+			Pattern:     "/apps/{app_name}",
+			HandlerFunc: controllers.NewErrorHandler(r.appsController.UnregisterAppHandler),
+		},
 	}
 }
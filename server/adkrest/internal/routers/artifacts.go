@@ -51,6 +51,12 @@ func (r *ArtifactsAPIRouter) Routes() Routes {
 			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/artifacts/{artifact_name}/versions/{version}",
 			HandlerFunc: r.artifactsController.LoadArtifactVersionHandler,
 		},
+		Route{
+			Name:        "SaveArtifact",
+			Methods:     []string{http.MethodPost, http.MethodOptions},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/artifacts/{artifact_name}",
+			HandlerFunc: r.artifactsController.SaveArtifactHandler,
+		},
 		Route{
 			Name:        "DeleteArtifact",
 			Methods:     []string{http.MethodDelete, http.MethodOptions},
@@ -39,11 +39,59 @@ func (r *RuntimeAPIRouter) Routes() Routes {
 			Pattern:     "/run",
 			HandlerFunc: controllers.NewErrorHandler(r.runtimeController.RunHandler),
 		},
+		Route{
+			Name:        "ResumeAgent",
+			Methods:     []string{http.MethodPost, http.MethodOptions},
+			Pattern:     "/run/resume",
+			HandlerFunc: controllers.NewErrorHandler(r.runtimeController.ResumeHandler),
+		},
+		Route{
+			Name:        "ForkSession",
+			Methods:     []string{http.MethodPost, http.MethodOptions},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/fork",
+			HandlerFunc: controllers.NewErrorHandler(r.runtimeController.ForkHandler),
+		},
+		Route{
+			Name:        "ExportSession",
+			Methods:     []string{http.MethodGet},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/export",
+			HandlerFunc: controllers.NewErrorHandler(r.runtimeController.ExportSessionHandler),
+		},
+		Route{
+			Name:        "ImportSession",
+			Methods:     []string{http.MethodPost, http.MethodOptions},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions:import",
+			HandlerFunc: controllers.NewErrorHandler(r.runtimeController.ImportSessionHandler),
+		},
+		Route{
+			Name:        "ListInvocations",
+			Methods:     []string{http.MethodGet},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/invocations",
+			HandlerFunc: controllers.NewErrorHandler(r.runtimeController.ListInvocationsHandler),
+		},
+		Route{
+			Name:        "CancelInvocation",
+			Methods:     []string{http.MethodPost, http.MethodOptions},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/invocations/{invocation_id}/cancel",
+			HandlerFunc: controllers.NewErrorHandler(r.runtimeController.CancelInvocationHandler),
+		},
 		Route{
 			Name:        "RunAgentSse",
 			Methods:     []string{http.MethodPost, http.MethodOptions},
 			Pattern:     "/run_sse",
 			HandlerFunc: controllers.NewErrorHandler(r.runtimeController.RunSSEHandler),
 		},
+		Route{
+			Name:        "RunAgentLive",
+			Methods:     []string{http.MethodGet},
+			Pattern:     "/run_live",
+			HandlerFunc: controllers.NewErrorHandler(r.runtimeController.RunLiveHandler),
+		},
+		Route{
+			Name:        "RunAgentWS",
+			Methods:     []string{http.MethodGet},
+			Pattern:     "/run_ws",
+			HandlerFunc: controllers.NewErrorHandler(r.runtimeController.RunWSHandler),
+		},
 	}
 }
@@ -63,5 +63,35 @@ func (r *SessionsAPIRouter) Routes() Routes {
 			Pattern:     "/apps/{app_name}/users/{user_id}/sessions",
 			HandlerFunc: r.sessionController.ListSessionsHandler,
 		},
+		Route{
+			Name:        "SearchSessions",
+			Methods:     []string{http.MethodGet},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions:search",
+			HandlerFunc: r.sessionController.SearchSessionsHandler,
+		},
+		Route{
+			Name:        "GetSessionState",
+			Methods:     []string{http.MethodGet},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/state",
+			HandlerFunc: r.sessionController.GetSessionStateHandler,
+		},
+		Route{
+			Name:        "PatchSessionState",
+			Methods:     []string{http.MethodPatch},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/state",
+			HandlerFunc: r.sessionController.PatchSessionStateHandler,
+		},
+		Route{
+			Name:        "ListEvents",
+			Methods:     []string{http.MethodGet},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/events",
+			HandlerFunc: r.sessionController.ListEventsHandler,
+		},
+		Route{
+			Name:        "GetSessionUsage",
+			Methods:     []string{http.MethodGet},
+			Pattern:     "/apps/{app_name}/users/{user_id}/sessions/{session_id}/usage",
+			HandlerFunc: r.sessionController.GetSessionUsageHandler,
+		},
 	}
 }
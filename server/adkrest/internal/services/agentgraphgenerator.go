@@ -18,10 +18,13 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"strconv"
 
 	"github.com/awalterschulze/gographviz"
 
 	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/remoteagent"
+	"google.golang.org/adk/agent/workflowagents/graphagent"
 	agentinternal "google.golang.org/adk/internal/agent"
 	llmagentinternal "google.golang.org/adk/internal/llminternal"
 	"google.golang.org/adk/tool"
@@ -31,6 +34,7 @@ const (
 	DarkGreen  = "\"#0F5223\""
 	LightGreen = "\"#69CB87\""
 	LightGray  = "\"#cccccc\""
+	LightBlue  = "\"#8ab4f8\""
 	White      = "\"#ffffff\""
 	Background = "\"#333537\""
 )
@@ -39,6 +43,7 @@ var supportedClusterAgents = []agentinternal.Type{
 	agentinternal.TypeLoopAgent,
 	agentinternal.TypeSequentialAgent,
 	agentinternal.TypeParallelAgent,
+	agentinternal.TypeGraphAgent,
 }
 
 type namedInstance interface {
@@ -56,11 +61,37 @@ func nodeName(instance any) string {
 	}
 }
 
+// remoteAgentURL returns the agent card URL of a remote A2A agent, and
+// whether instance is a remote agent.
+func remoteAgentURL(instance any) (string, bool) {
+	a, ok := instance.(agentinternal.Agent)
+	if !ok {
+		return "", false
+	}
+	state := agentinternal.Reveal(a)
+	if state.AgentType != agentinternal.TypeRemoteA2AAgent {
+		return "", false
+	}
+	cfg, _ := state.Config.(remoteagent.A2AConfig)
+	if cfg.AgentCardSource == "" && cfg.AgentCard != nil {
+		return cfg.AgentCard.URL, true
+	}
+	return cfg.AgentCardSource, true
+}
+
+func isRemoteAgent(instance any) bool {
+	_, ok := remoteAgentURL(instance)
+	return ok
+}
+
 func nodeCaption(instance any) string {
 	caption := ""
 	switch i := instance.(type) {
 	case agent.Agent:
 		caption = "🤖 " + i.Name()
+		if isRemoteAgent(i) {
+			caption = "🌐 " + i.Name()
+		}
 		typedAgent, ok := i.(agentinternal.Agent)
 		if ok {
 			if slices.Contains(supportedClusterAgents, agentinternal.Reveal(typedAgent).AgentType) {
@@ -78,6 +109,9 @@ func nodeCaption(instance any) string {
 func nodeShape(instance any) string {
 	switch instance.(type) {
 	case agent.Agent:
+		if isRemoteAgent(instance) {
+			return "box3d"
+		}
 		return "ellipse"
 	case tool.Tool:
 		return "box"
@@ -143,16 +177,22 @@ func drawCluster(parentGraph, cluster *gographviz.Graph, agent agent.Agent, high
 	if !ok {
 		return nil
 	}
+	state := agentinternal.Reveal(agentInternal)
+	remote := map[string]bool{}
+	for _, subAgent := range agent.SubAgents() {
+		remote[subAgent.Name()] = isRemoteAgent(subAgent)
+	}
 	for i, subAgent := range agent.SubAgents() {
 		err := buildGraph(cluster, parentGraph, subAgent, highlightedPairs, visitedNodes)
 		if err != nil {
 			return fmt.Errorf("draw cluster: build graph: %w", err)
 		}
-		switch agentinternal.Reveal(agentInternal).AgentType {
+		switch state.AgentType {
 		// Sequential sub-agents should be connected one after another with edges.
 		case agentinternal.TypeSequentialAgent:
 			if i < len(agent.SubAgents())-1 {
-				err = drawEdge(parentGraph, nodeName(subAgent), nodeName(agent.SubAgents()[i+1]), highlightedPairs)
+				next := agent.SubAgents()[i+1]
+				err = drawEdge(parentGraph, nodeName(subAgent), nodeName(next), remote[subAgent.Name()] || remote[next.Name()], highlightedPairs)
 				if err != nil {
 					return fmt.Errorf("draw cluster: draw edge: %w", err)
 				}
@@ -163,13 +203,25 @@ func drawCluster(parentGraph, cluster *gographviz.Graph, agent agent.Agent, high
 			if nextAgentIdx >= len(agent.SubAgents()) {
 				nextAgentIdx = 0
 			}
-			err = drawEdge(parentGraph, nodeName(subAgent), nodeName(agent.SubAgents()[nextAgentIdx]), highlightedPairs)
+			next := agent.SubAgents()[nextAgentIdx]
+			err = drawEdge(parentGraph, nodeName(subAgent), nodeName(next), remote[subAgent.Name()] || remote[next.Name()], highlightedPairs)
 			if err != nil {
 				return fmt.Errorf("draw cluster: draw edge: %w", err)
 			}
 		}
 		// Parallel sub-agents shouldn't be connected, they will be a part of the sub graph.
 	}
+	// Graph sub-agents are connected according to the configured edges.
+	if cfg, ok := state.Config.(graphagent.Config); ok {
+		for _, e := range cfg.Edges {
+			if e.To == graphagent.End {
+				continue
+			}
+			if err := drawEdge(parentGraph, e.From, e.To, remote[e.From] || remote[e.To], highlightedPairs); err != nil {
+				return fmt.Errorf("draw cluster: draw edge: %w", err)
+			}
+		}
+	}
 	return nil
 }
 
@@ -215,11 +267,23 @@ func drawNode(graph, parentGraph *gographviz.Graph, instance any, highlightedPai
 			nodeAttributes["color"] = LightGray
 			nodeAttributes["style"] = "rounded"
 		}
+		// Remote agents run in other processes, their card URL is shown
+		// as tooltip.
+		if url, ok := remoteAgentURL(instance); ok {
+			if !highlighted {
+				nodeAttributes["color"] = LightBlue
+			}
+			if url != "" {
+				nodeAttributes["tooltip"] = strconv.Quote(url)
+			}
+		}
 		return parentGraph.AddNode(graph.Name, name, nodeAttributes)
 	}
 }
 
-func drawEdge(graph *gographviz.Graph, from, to string, highlightedPairs [][]string) error {
+// drawEdge connects two nodes. The cross-process edges, between an agent and
+// a remote agent, are dashed.
+func drawEdge(graph *gographviz.Graph, from, to string, crossProcess bool, highlightedPairs [][]string) error {
 	edgeHighlighted := edgeHighlighted(from, to, highlightedPairs)
 	edgeAttributes := map[string]string{}
 	if edgeHighlighted != nil {
@@ -234,6 +298,9 @@ func drawEdge(graph *gographviz.Graph, from, to string, highlightedPairs [][]str
 		edgeAttributes["color"] = LightGray
 		edgeAttributes["arrowhead"] = "none"
 	}
+	if crossProcess {
+		edgeAttributes["style"] = "dashed"
+	}
 	return graph.AddEdge(from, to, true, edgeAttributes)
 }
 
@@ -262,7 +329,7 @@ func buildGraph(graph, parentGraph *gographviz.Graph, instance any, highlightedP
 			if err != nil {
 				return fmt.Errorf("draw tool node: %w", err)
 			}
-			err = drawEdge(graph, nodeName(agent), nodeName(tool), highlightedPairs)
+			err = drawEdge(graph, nodeName(agent), nodeName(tool), false, highlightedPairs)
 			if err != nil {
 				return fmt.Errorf("draw tool edge: %w", err)
 			}
@@ -273,6 +340,14 @@ func buildGraph(graph, parentGraph *gographviz.Graph, instance any, highlightedP
 		if err != nil {
 			return fmt.Errorf("build sub agent graph: %w", err)
 		}
+		// The workflow agents link their remote sub-agents in their
+		// cluster, the other agents delegate to them through A2A.
+		if isRemoteAgent(subAgent) && !shouldBuildAgentCluster(agent) {
+			err = drawEdge(graph, nodeName(agent), nodeName(subAgent), true, highlightedPairs)
+			if err != nil {
+				return fmt.Errorf("draw remote agent edge: %w", err)
+			}
+		}
 	}
 	return nil
 }
@@ -25,6 +25,7 @@ import (
 
 	"google.golang.org/adk/agent"
 	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/agent/remoteagent"
 	"google.golang.org/adk/agent/workflowagents/loopagent"
 	"google.golang.org/adk/agent/workflowagents/parallelagent"
 	"google.golang.org/adk/agent/workflowagents/sequentialagent"
@@ -505,6 +506,7 @@ func TestDrawEdge(t *testing.T) {
 		name             string
 		from             string
 		to               string
+		crossProcess     bool
 		highlightedPairs [][]string
 		expected         gographviz.Attrs
 	}{
@@ -539,6 +541,18 @@ func TestDrawEdge(t *testing.T) {
 				"dir":       "back",
 			},
 		},
+		{
+			name:             "draw cross-process edge",
+			from:             "NodeG",
+			to:               "NodeH",
+			crossProcess:     true,
+			highlightedPairs: [][]string{},
+			expected: gographviz.Attrs{
+				"color":     LightGray,
+				"arrowhead": "none",
+				"style":     "dashed",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -556,7 +570,7 @@ func TestDrawEdge(t *testing.T) {
 				}
 			}
 
-			err = drawEdge(graph, tt.from, tt.to, tt.highlightedPairs)
+			err = drawEdge(graph, tt.from, tt.to, tt.crossProcess, tt.highlightedPairs)
 			if err != nil {
 				t.Fatalf("drawEdge failed: %v", err)
 			}
@@ -690,3 +704,43 @@ func TestBuildGraph(t *testing.T) {
 		t.Error("Edge from SubAgent1 to Tool1 not found")
 	}
 }
+
+func TestBuildGraph_RemoteAgent(t *testing.T) {
+	graph := gographviz.NewGraph()
+	if err := graph.SetName("G"); err != nil {
+		t.Fatalf("failed to set graph name: %v", err)
+	}
+	remote, err := remoteagent.NewA2A(remoteagent.A2AConfig{Name: "Remote", AgentCardSource: "https://remote.example.com/card.json"})
+	if err != nil {
+		t.Fatalf("remoteagent.NewA2A() error = %v", err)
+	}
+	mainAgent := newTestAgent(t, "MainAgent", "", agentinternal.TypeLLMAgent, []agent.Agent{remote}, nil)
+
+	if err := buildGraph(graph, graph, mainAgent, [][]string{}, map[string]bool{}); err != nil {
+		t.Fatalf("buildGraph failed: %v", err)
+	}
+
+	node := graph.Nodes.Lookup["Remote"]
+	if node == nil {
+		t.Fatalf("Node Remote not found in graph")
+	}
+	wantNode := gographviz.Attrs{
+		"label":     `"🌐 Remote"`,
+		"shape":     "box3d",
+		"fontcolor": LightGray,
+		"color":     LightBlue,
+		"style":     "rounded",
+		"tooltip":   `"https://remote.example.com/card.json"`,
+	}
+	if diff := cmp.Diff(wantNode, node.Attrs); diff != "" {
+		t.Errorf("remote node attributes mismatch (-want +got):\n%s", diff)
+	}
+	edge := lookupEdge(t, graph, "MainAgent", "Remote")
+	if edge == nil {
+		t.Fatalf("Edge from MainAgent to Remote not found")
+		return
+	}
+	if got := edge.Attrs["style"]; got != "dashed" {
+		t.Errorf("edge style = %q, want dashed", got)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/workflowagents/graphagent"
+	agentinternal "google.golang.org/adk/internal/agent"
+	llmagentinternal "google.golang.org/adk/internal/llminternal"
+	"google.golang.org/adk/server/adkrest/internal/models"
+	"google.golang.org/adk/tool"
+)
+
+// BuildAgentGraph returns the structure of the agent tree, with the nodes
+// and edges of [GetAgentGraph]. The edges from the LLM agents to their sub
+// agents are included as well. The edges to remote agents are marked as
+// cross-process.
+func BuildAgentGraph(root agent.Agent, highlightedPairs [][]string) *models.AgentGraph {
+	b := &graphBuilder{
+		graph:            &models.AgentGraph{Nodes: []models.GraphNode{}, Edges: []models.GraphEdge{}},
+		highlightedPairs: highlightedPairs,
+		visited:          map[string]bool{},
+		remote:           map[string]bool{},
+	}
+	b.build(root, "")
+	for i, e := range b.graph.Edges {
+		b.graph.Edges[i].CrossProcess = b.remote[e.From] || b.remote[e.To]
+	}
+	return b.graph
+}
+
+type graphBuilder struct {
+	graph            *models.AgentGraph
+	highlightedPairs [][]string
+	visited          map[string]bool
+	// remote are the names of the remote agents.
+	remote map[string]bool
+}
+
+func (b *graphBuilder) build(instance any, group string) {
+	if _, ok := instance.(namedInstance); !ok {
+		return
+	}
+	name := nodeName(instance)
+	if b.visited[name] {
+		return
+	}
+	b.addNode(instance, group)
+
+	a, ok := instance.(agent.Agent)
+	if !ok {
+		return
+	}
+	if shouldBuildAgentCluster(a) {
+		b.buildCluster(a)
+		return
+	}
+	if llmAgent, ok := a.(llmagentinternal.Agent); ok {
+		for _, t := range llmagentinternal.Reveal(llmAgent).Tools {
+			b.build(t, group)
+			b.addEdge(name, t.Name(), "tool")
+		}
+	}
+	for _, subAgent := range a.SubAgents() {
+		b.build(subAgent, group)
+		b.addEdge(name, subAgent.Name(), "sub_agent")
+	}
+}
+
+func (b *graphBuilder) buildCluster(a agent.Agent) {
+	state := agentinternal.Reveal(a.(agentinternal.Agent))
+	subAgents := a.SubAgents()
+	for i, subAgent := range subAgents {
+		b.build(subAgent, a.Name())
+		switch state.AgentType {
+		case agentinternal.TypeSequentialAgent:
+			if i < len(subAgents)-1 {
+				b.addEdge(subAgent.Name(), subAgents[i+1].Name(), "sequence")
+			}
+		case agentinternal.TypeLoopAgent:
+			b.addEdge(subAgent.Name(), subAgents[(i+1)%len(subAgents)].Name(), "loop")
+		}
+	}
+	if cfg, ok := state.Config.(graphagent.Config); ok {
+		for _, e := range cfg.Edges {
+			if e.To == graphagent.End {
+				continue
+			}
+			b.addEdge(e.From, e.To, "graph")
+		}
+	}
+}
+
+func (b *graphBuilder) addNode(instance any, group string) {
+	name := nodeName(instance)
+	b.visited[name] = true
+	node := models.GraphNode{
+		ID:          name,
+		Label:       name,
+		Group:       group,
+		Highlighted: highlighted(name, b.highlightedPairs),
+	}
+	switch i := instance.(type) {
+	case agent.Agent:
+		node.Type = "agent"
+		if typed, ok := i.(agentinternal.Agent); ok {
+			node.AgentType = string(agentinternal.Reveal(typed).AgentType)
+		}
+		if url, ok := remoteAgentURL(i); ok {
+			node.URL = url
+			b.remote[name] = true
+		}
+	case tool.Tool:
+		node.Type = "tool"
+	}
+	b.graph.Nodes = append(b.graph.Nodes, node)
+}
+
+func (b *graphBuilder) addEdge(from, to, typ string) {
+	edge := models.GraphEdge{From: from, To: to, Type: typ}
+	if h := edgeHighlighted(from, to, b.highlightedPairs); h != nil {
+		edge.Highlighted = true
+		edge.Reversed = !*h
+	}
+	b.graph.Edges = append(b.graph.Edges, edge)
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/remoteagent"
+	agentinternal "google.golang.org/adk/internal/agent"
+	"google.golang.org/adk/server/adkrest/internal/models"
+	"google.golang.org/adk/tool"
+)
+
+func TestBuildAgentGraph(t *testing.T) {
+	search := &mockTool{name: "search"}
+	writer := newTestAgent(t, "writer", "", agentinternal.TypeLLMAgent, nil, []tool.Tool{search})
+	reviewer := newTestAgent(t, "reviewer", "", agentinternal.TypeLLMAgent, nil, nil)
+	loop := newTestAgent(t, "refine", "", agentinternal.TypeLoopAgent, []agent.Agent{writer, reviewer}, nil)
+	root := newTestAgent(t, "root", "", agentinternal.TypeLLMAgent, []agent.Agent{loop}, nil)
+
+	got := BuildAgentGraph(root, [][]string{{"search", "writer"}})
+
+	want := &models.AgentGraph{
+		Nodes: []models.GraphNode{
+			{ID: "root", Label: "root", Type: "agent", AgentType: "LLMAgent"},
+			{ID: "refine", Label: "refine", Type: "agent", AgentType: "LoopAgent"},
+			{ID: "writer", Label: "writer", Type: "agent", AgentType: "LLMAgent", Group: "refine", Highlighted: true},
+			{ID: "search", Label: "search", Type: "tool", Group: "refine", Highlighted: true},
+			{ID: "reviewer", Label: "reviewer", Type: "agent", AgentType: "LLMAgent", Group: "refine"},
+		},
+		Edges: []models.GraphEdge{
+			{From: "writer", To: "search", Type: "tool", Highlighted: true, Reversed: true},
+			{From: "writer", To: "reviewer", Type: "loop"},
+			{From: "reviewer", To: "writer", Type: "loop"},
+			{From: "root", To: "refine", Type: "sub_agent"},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("BuildAgentGraph() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func newRemoteAgent(t *testing.T, name, cardURL string) agent.Agent {
+	t.Helper()
+	a, err := remoteagent.NewA2A(remoteagent.A2AConfig{Name: name, AgentCardSource: cardURL})
+	if err != nil {
+		t.Fatalf("remoteagent.NewA2A() error = %v", err)
+	}
+	return a
+}
+
+func TestBuildAgentGraph_RemoteAgents(t *testing.T) {
+	billing := newRemoteAgent(t, "billing", "https://billing.example.com/card.json")
+	writer := newTestAgent(t, "writer", "", agentinternal.TypeLLMAgent, nil, nil)
+	seq := newTestAgent(t, "pipeline", "", agentinternal.TypeSequentialAgent, []agent.Agent{writer, billing}, nil)
+	root := newTestAgent(t, "root", "", agentinternal.TypeLLMAgent, []agent.Agent{seq, newRemoteAgent(t, "support", "https://support.example.com/card.json")}, nil)
+
+	got := BuildAgentGraph(root, nil)
+
+	want := &models.AgentGraph{
+		Nodes: []models.GraphNode{
+			{ID: "root", Label: "root", Type: "agent", AgentType: "LLMAgent"},
+			{ID: "pipeline", Label: "pipeline", Type: "agent", AgentType: "SequentialAgent"},
+			{ID: "writer", Label: "writer", Type: "agent", AgentType: "LLMAgent", Group: "pipeline"},
+			{ID: "billing", Label: "billing", Type: "agent", AgentType: "RemoteA2AAgent", Group: "pipeline", URL: "https://billing.example.com/card.json"},
+			{ID: "support", Label: "support", Type: "agent", AgentType: "RemoteA2AAgent", URL: "https://support.example.com/card.json"},
+		},
+		Edges: []models.GraphEdge{
+			{From: "writer", To: "billing", Type: "sequence", CrossProcess: true},
+			{From: "root", To: "pipeline", Type: "sub_agent"},
+			{From: "root", To: "support", Type: "sub_agent", CrossProcess: true},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("BuildAgentGraph() mismatch (-want +got):\n%s", diff)
+	}
+
+	svg := RenderAgentGraphSVG(got)
+	if err := xml.Unmarshal([]byte(svg), new(struct{})); err != nil {
+		t.Fatalf("SVG is not well formed: %v\n%s", err, svg)
+	}
+	for _, want := range []string{"🌐 billing", "<title>https://support.example.com/card.json</title>", `stroke-dasharray="6 4"`} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG does not contain %q:\n%s", want, svg)
+		}
+	}
+}
+
+func TestRenderAgentGraphSVG(t *testing.T) {
+	writer := newTestAgent(t, "writer", "", agentinternal.TypeLLMAgent, nil, []tool.Tool{&mockTool{name: "search<&>"}})
+	reviewer := newTestAgent(t, "reviewer", "", agentinternal.TypeLLMAgent, nil, nil)
+	seq := newTestAgent(t, "pipeline", "", agentinternal.TypeSequentialAgent, []agent.Agent{writer, reviewer}, nil)
+
+	svg := RenderAgentGraphSVG(BuildAgentGraph(seq, [][]string{{"writer", "writer"}}))
+
+	dec := xml.NewDecoder(strings.NewReader(svg))
+	var texts []string
+	shapes := map[string]int{}
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("SVG is not well formed: %v\n%s", err, svg)
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			shapes[tok.Name.Local]++
+		case xml.CharData:
+			if s := strings.TrimSpace(string(tok)); s != "" {
+				texts = append(texts, s)
+			}
+		}
+	}
+
+	wantTexts := []string{"pipeline (SequentialAgent)", "🤖 writer", "🔧 search<&>", "🤖 reviewer"}
+	if diff := cmp.Diff(wantTexts, texts); diff != "" {
+		t.Errorf("SVG texts mismatch (-want +got):\n%s", diff)
+	}
+	// One ellipse per agent, one rect per tool plus the background and the cluster.
+	if shapes["ellipse"] != 2 || shapes["rect"] != 3 || shapes["line"] != 2 {
+		t.Errorf("SVG shapes = %v, want 2 ellipses, 3 rects and 2 lines", shapes)
+	}
+	if !strings.Contains(svg, `fill="#0F5223"`) {
+		t.Errorf("SVG does not highlight the writer node:\n%s", svg)
+	}
+}
+
+func TestLayerRanks(t *testing.T) {
+	got := layerRanks([]string{"a", "b", "c", "d"}, map[string][]string{
+		"a": {"b", "c"},
+		"b": {"c"},
+		"c": {"a"},
+	})
+	want := map[string]int{"b": 1, "c": 2}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("layerRanks() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,327 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	agentinternal "google.golang.org/adk/internal/agent"
+	"google.golang.org/adk/server/adkrest/internal/models"
+)
+
+// Layout constants of the SVG rendering, in pixels.
+const (
+	svgMargin       = 24
+	svgNodeHeight   = 36
+	svgCharWidth    = 8
+	svgNodePadding  = 32
+	svgColumnGap    = 64
+	svgRowGap       = 28
+	svgClusterPad   = 12
+	svgClusterLabel = 22
+)
+
+// box is a rectangle in the SVG canvas.
+type box struct {
+	x, y, w, h float64
+}
+
+func (b box) center() (float64, float64) {
+	return b.x + b.w/2, b.y + b.h/2
+}
+
+// clip returns the point where the segment from the center of b to (px, py)
+// crosses the border of b.
+func (b box) clip(px, py float64) (float64, float64) {
+	cx, cy := b.center()
+	dx, dy := px-cx, py-cy
+	if dx == 0 && dy == 0 {
+		return cx, cy
+	}
+	scale := math.Inf(1)
+	if dx != 0 {
+		scale = math.Min(scale, b.w/2/math.Abs(dx))
+	}
+	if dy != 0 {
+		scale = math.Min(scale, b.h/2/math.Abs(dy))
+	}
+	return cx + dx*scale, cy + dy*scale
+}
+
+// RenderAgentGraphSVG renders the graph as SVG, with a left to right layered
+// layout and the colors of [GetAgentGraph]. Workflow agents are drawn as
+// boxes around their sub agents.
+func RenderAgentGraphSVG(graph *models.AgentGraph) string {
+	nodes := map[string]models.GraphNode{}
+	var leaves []string
+	for _, n := range graph.Nodes {
+		nodes[n.ID] = n
+		if !isClusterNode(n) {
+			leaves = append(leaves, n.ID)
+		}
+	}
+
+	// Edges to a workflow agent are laid out as edges to its first leaf.
+	var firstLeaf func(id string) string
+	firstLeaf = func(id string) string {
+		if n, ok := nodes[id]; ok && !isClusterNode(n) {
+			return id
+		}
+		for _, n := range graph.Nodes {
+			if n.Group == id {
+				if leaf := firstLeaf(n.ID); leaf != "" {
+					return leaf
+				}
+			}
+		}
+		return ""
+	}
+	adjacency := map[string][]string{}
+	for _, e := range graph.Edges {
+		from, to := firstLeaf(e.From), firstLeaf(e.To)
+		if from != "" && to != "" && from != to {
+			adjacency[from] = append(adjacency[from], to)
+		}
+	}
+	ranks := layerRanks(leaves, adjacency)
+
+	// Place the leaves in columns by rank, keeping the order of the graph.
+	var columns [][]string
+	for _, id := range leaves {
+		for len(columns) <= ranks[id] {
+			columns = append(columns, nil)
+		}
+		columns[ranks[id]] = append(columns[ranks[id]], id)
+	}
+	boxes := map[string]box{}
+	x := float64(svgMargin + svgClusterPad)
+	for _, column := range columns {
+		width := 0.0
+		for _, id := range column {
+			width = math.Max(width, nodeWidth(nodes[id]))
+		}
+		y := float64(svgMargin + svgClusterPad + svgClusterLabel)
+		for _, id := range column {
+			boxes[id] = box{x: x, y: y, w: width, h: svgNodeHeight}
+			y += svgNodeHeight + svgRowGap + svgClusterLabel
+		}
+		x += width + svgColumnGap
+	}
+
+	// Workflow agents surround their members, inner ones first.
+	var clusters []string
+	var clusterBox func(id string) (box, bool)
+	clusterBox = func(id string) (box, bool) {
+		if b, ok := boxes[id]; ok {
+			return b, true
+		}
+		minX, minY := math.Inf(1), math.Inf(1)
+		maxX, maxY := math.Inf(-1), math.Inf(-1)
+		for _, n := range graph.Nodes {
+			if n.Group != id {
+				continue
+			}
+			b, ok := clusterBox(n.ID)
+			if !ok {
+				continue
+			}
+			minX, minY = math.Min(minX, b.x), math.Min(minY, b.y)
+			maxX, maxY = math.Max(maxX, b.x+b.w), math.Max(maxY, b.y+b.h)
+		}
+		if math.IsInf(minX, 1) {
+			return box{}, false
+		}
+		b := box{
+			x: minX - svgClusterPad,
+			y: minY - svgClusterPad - svgClusterLabel,
+			w: maxX - minX + 2*svgClusterPad,
+			h: maxY - minY + 2*svgClusterPad + svgClusterLabel,
+		}
+		boxes[id] = b
+		clusters = append(clusters, id)
+		return b, true
+	}
+	for _, n := range graph.Nodes {
+		if isClusterNode(n) {
+			clusterBox(n.ID)
+		}
+	}
+
+	width, height := 0.0, 0.0
+	for _, b := range boxes {
+		width, height = math.Max(width, b.x+b.w), math.Max(height, b.y+b.h)
+	}
+	width, height = width+svgMargin, height+svgMargin
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="sans-serif" font-size="14">`+"\n", width, height, width, height)
+	sb.WriteString("<defs>\n")
+	for _, m := range []struct{ id, color string }{{"arrow", svgColor(LightGray)}, {"arrow-highlighted", svgColor(LightGreen)}} {
+		fmt.Fprintf(&sb, `<marker id="%s" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="8" markerHeight="8" orient="auto-start-reverse"><path d="M0,0 L10,5 L0,10 z" fill="%s"/></marker>`+"\n", m.id, m.color)
+	}
+	sb.WriteString("</defs>\n")
+	fmt.Fprintf(&sb, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", svgColor(Background))
+
+	// Outer clusters are drawn first, so that inner ones stay visible.
+	for _, id := range slices.Backward(clusters) {
+		b := boxes[id]
+		n := nodes[id]
+		fmt.Fprintf(&sb, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="8" fill="none" stroke="%s"/>`+"\n", b.x, b.y, b.w, b.h, svgColor(White))
+		fmt.Fprintf(&sb, `<text x="%.1f" y="%.1f" fill="%s">%s</text>`+"\n", b.x+svgClusterPad, b.y+svgClusterLabel-6, svgColor(LightGray), html.EscapeString(n.Label+" ("+n.AgentType+")"))
+	}
+
+	for _, e := range graph.Edges {
+		from, okFrom := boxes[e.From]
+		to, okTo := boxes[e.To]
+		if !okFrom || !okTo {
+			continue
+		}
+		fx, fy := from.center()
+		tx, ty := to.center()
+		x1, y1 := from.clip(tx, ty)
+		x2, y2 := to.clip(fx, fy)
+		color, marker, dash := svgColor(LightGray), "", ""
+		if e.Highlighted {
+			color = svgColor(LightGreen)
+			if e.Reversed {
+				marker = ` marker-start="url(#arrow-highlighted)"`
+			} else {
+				marker = ` marker-end="url(#arrow-highlighted)"`
+			}
+		}
+		if e.CrossProcess {
+			dash = ` stroke-dasharray="6 4"`
+		}
+		fmt.Fprintf(&sb, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s"%s%s/>`+"\n", x1, y1, x2, y2, color, dash, marker)
+	}
+
+	for _, id := range leaves {
+		b := boxes[id]
+		n := nodes[id]
+		fill, stroke := "none", svgColor(LightGray)
+		if n.AgentType == string(agentinternal.TypeRemoteA2AAgent) {
+			stroke = svgColor(LightBlue)
+		}
+		if n.Highlighted {
+			fill, stroke = svgColor(DarkGreen), svgColor(DarkGreen)
+		}
+		cx, cy := b.center()
+		// The card URL of remote agents is shown as tooltip.
+		if n.URL != "" {
+			fmt.Fprintf(&sb, "<g><title>%s</title>\n", html.EscapeString(n.URL))
+		}
+		switch {
+		case n.AgentType == string(agentinternal.TypeRemoteA2AAgent):
+			fmt.Fprintf(&sb, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" stroke="%s" stroke-dasharray="6 4"/>`+"\n", b.x, b.y, b.w, b.h, fill, stroke)
+		case n.Type == "tool":
+			fmt.Fprintf(&sb, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="4" fill="%s" stroke="%s"/>`+"\n", b.x, b.y, b.w, b.h, fill, stroke)
+		default:
+			fmt.Fprintf(&sb, `<ellipse cx="%.1f" cy="%.1f" rx="%.1f" ry="%.1f" fill="%s" stroke="%s"/>`+"\n", cx, cy, b.w/2, b.h/2, fill, stroke)
+		}
+		fmt.Fprintf(&sb, `<text x="%.1f" y="%.1f" text-anchor="middle" dominant-baseline="middle" fill="%s">%s</text>`+"\n", cx, cy, svgColor(LightGray), html.EscapeString(nodeLabel(n)))
+		if n.URL != "" {
+			sb.WriteString("</g>\n")
+		}
+	}
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
+
+func isClusterNode(n models.GraphNode) bool {
+	return slices.Contains(supportedClusterAgents, agentinternal.Type(n.AgentType))
+}
+
+func nodeLabel(n models.GraphNode) string {
+	if n.Type == "tool" {
+		return "🔧 " + n.Label
+	}
+	if n.AgentType == string(agentinternal.TypeRemoteA2AAgent) {
+		return "🌐 " + n.Label
+	}
+	return "🤖 " + n.Label
+}
+
+func nodeWidth(n models.GraphNode) float64 {
+	return float64(utf8.RuneCountInString(nodeLabel(n))*svgCharWidth + svgNodePadding)
+}
+
+// svgColor strips the quotes of the DOT color constants.
+func svgColor(dotColor string) string {
+	return strings.Trim(dotColor, `"`)
+}
+
+// layerRanks assigns each node the length of the longest path reaching it,
+// ignoring the edges that close a cycle.
+func layerRanks(nodes []string, adjacency map[string][]string) map[string]int {
+	// Depth first search in the node order drops the back edges.
+	const (
+		unvisited = iota
+		onStack
+		done
+	)
+	state := map[string]int{}
+	forward := map[string][]string{}
+	var visit func(id string)
+	visit = func(id string) {
+		state[id] = onStack
+		for _, next := range adjacency[id] {
+			switch state[next] {
+			case unvisited:
+				forward[id] = append(forward[id], next)
+				visit(next)
+			case done:
+				forward[id] = append(forward[id], next)
+			}
+		}
+		state[id] = done
+	}
+	for _, id := range nodes {
+		if state[id] == unvisited {
+			visit(id)
+		}
+	}
+
+	// The forward edges form a DAG, ranks are computed in topological order.
+	inDegree := map[string]int{}
+	for _, id := range nodes {
+		for _, next := range forward[id] {
+			inDegree[next]++
+		}
+	}
+	ranks := map[string]int{}
+	var queue []string
+	for _, id := range nodes {
+		if inDegree[id] == 0 {
+			queue = append(queue, id)
+		}
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, next := range forward[id] {
+			ranks[next] = max(ranks[next], ranks[id]+1)
+			inDegree[next]--
+			if inDegree[next] == 0 {
+				queue = append(queue, next)
+			}
+		}
+	}
+	return ranks
+}
@@ -15,35 +15,114 @@
 package services
 
 import (
+	"cmp"
 	"context"
+	"maps"
+	"slices"
 	"strings"
+	"sync"
 
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"google.golang.org/adk/server/adkrest/internal/models"
+)
+
+const (
+	sessionIDAttribute = "gcp.vertex.agent.session_id"
+	userIDAttribute    = "gcp.vertex.agent.user_id"
+	appNameAttribute   = "gcp.vertex.agent.app_name"
+)
+
+const (
+	// maxTraces is the number of traces kept by the exporter, the oldest
+	// ones are dropped first.
+	maxTraces = 1000
+	// maxTraceSpans is the number of spans kept per trace.
+	maxTraceSpans = 1000
 )
 
 // APIServerSpanExporter is a custom SpanExporter that stores relevant span data.
 // Stores attributes of specific spans (call_llm, send_data, execute_tool) keyed by `gcp.vertex.agent.event_id`.
 // This is used for debugging individual events.
+// It also stores all the spans, indexed by the sessions of their traces, to
+// show the traces of a session.
+// Only the last maxTraces traces are kept.
 // APIServerSpanExporter implements sdktrace.SpanExporter interface.
 type APIServerSpanExporter struct {
+	mu        sync.Mutex
 	traceDict map[string]map[string]string
+	// spans are the finished spans by trace ID.
+	spans map[string][]models.Span
+	// sessionTraces are the IDs of the traces of each session.
+	sessionTraces map[string][]string
+	// owners are the app and the user of each trace, from the attributes of
+	// their invocation span.
+	owners map[string]traceOwner
+	// traceEvents are the IDs of the events of each trace in traceDict.
+	traceEvents map[string][]string
+	// traces are the IDs of the stored traces, oldest first.
+	traces []string
+}
+
+// traceOwner is the app and the user of the invocation of a trace.
+type traceOwner struct {
+	appName, userID string
 }
 
 // NewAPIServerSpanExporter returns a APIServerSpanExporter instance
 func NewAPIServerSpanExporter() *APIServerSpanExporter {
 	return &APIServerSpanExporter{
-		traceDict: make(map[string]map[string]string),
+		traceDict:     make(map[string]map[string]string),
+		spans:         make(map[string][]models.Span),
+		sessionTraces: make(map[string][]string),
+		owners:        make(map[string]traceOwner),
+		traceEvents:   make(map[string][]string),
+	}
+}
+
+// GetEventTrace returns a copy of the stored attributes of the span of an
+// event. allowed reports whether the caller can see the traces of the user of
+// an app; the app and the user are empty while the invocation of the event
+// is in progress.
+func (s *APIServerSpanExporter) GetEventTrace(eventID string, allowed func(appName, userID string) bool) (map[string]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	attributes, ok := s.traceDict[eventID]
+	if !ok {
+		return nil, false
 	}
+	owner := s.owners[attributes["trace_id"]]
+	if !allowed(owner.appName, owner.userID) {
+		return nil, false
+	}
+	return maps.Clone(attributes), true
 }
 
-// GetTraceDict returns stored trace informations
-func (s *APIServerSpanExporter) GetTraceDict() map[string]map[string]string {
-	return s.traceDict
+// GetSessionSpans returns the spans of the traces of a session, ordered by
+// start time. Only the traces whose app and user are allowed are returned.
+func (s *APIServerSpanExporter) GetSessionSpans(sessionID string, allowed func(appName, userID string) bool) []models.Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result := []models.Span{}
+	for _, traceID := range s.sessionTraces[sessionID] {
+		owner := s.owners[traceID]
+		if !allowed(owner.appName, owner.userID) {
+			continue
+		}
+		result = append(result, s.spans[traceID]...)
+	}
+	slices.SortStableFunc(result, func(a, b models.Span) int {
+		return cmp.Compare(a.StartTime, b.StartTime)
+	})
+	return result
 }
 
 // ExportSpans implements custom export function for sdktrace.SpanExporter.
 func (s *APIServerSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	for _, span := range spans {
+		s.storeSpan(span)
 		if span.Name() == "call_llm" || span.Name() == "send_data" || strings.HasPrefix(span.Name(), "execute_tool") {
 			spanAttributes := span.Attributes()
 			attributes := make(map[string]string)
@@ -51,9 +130,13 @@ func (s *APIServerSpanExporter) ExportSpans(ctx context.Context, spans []sdktrac
 				key := string(attribute.Key)
 				attributes[key] = attribute.Value.AsString()
 			}
-			attributes["trace_id"] = span.SpanContext().TraceID().String()
+			traceID := span.SpanContext().TraceID().String()
+			attributes["trace_id"] = traceID
 			attributes["span_id"] = span.SpanContext().SpanID().String()
 			if eventID, ok := attributes["gcp.vertex.agent.event_id"]; ok {
+				if _, ok := s.traceDict[eventID]; !ok {
+					s.traceEvents[traceID] = append(s.traceEvents[traceID], eventID)
+				}
 				s.traceDict[eventID] = attributes
 			}
 		}
@@ -61,6 +144,75 @@ func (s *APIServerSpanExporter) ExportSpans(ctx context.Context, spans []sdktrac
 	return nil
 }
 
+// storeSpan stores the span in its trace, and the trace in the session of the
+// span, if any.
+func (s *APIServerSpanExporter) storeSpan(span sdktrace.ReadOnlySpan) {
+	traceID := span.SpanContext().TraceID().String()
+	stored := models.Span{
+		Name:       span.Name(),
+		SpanID:     span.SpanContext().SpanID().String(),
+		TraceID:    traceID,
+		StartTime:  span.StartTime().UnixNano(),
+		EndTime:    span.EndTime().UnixNano(),
+		Attributes: make(map[string]any),
+	}
+	if span.Parent().IsValid() {
+		stored.ParentSpanID = span.Parent().SpanID().String()
+	}
+	for _, attribute := range span.Attributes() {
+		stored.Attributes[string(attribute.Key)] = attribute.Value.AsInterface()
+	}
+	if _, ok := s.spans[traceID]; !ok {
+		s.addTrace(traceID)
+	}
+	if len(s.spans[traceID]) < maxTraceSpans {
+		s.spans[traceID] = append(s.spans[traceID], stored)
+	}
+
+	if sessionID, ok := stored.Attributes[sessionIDAttribute].(string); ok && sessionID != "" {
+		if !slices.Contains(s.sessionTraces[sessionID], traceID) {
+			s.sessionTraces[sessionID] = append(s.sessionTraces[sessionID], traceID)
+		}
+	}
+	userID, _ := stored.Attributes[userIDAttribute].(string)
+	appName, _ := stored.Attributes[appNameAttribute].(string)
+	if userID != "" || appName != "" {
+		s.owners[traceID] = traceOwner{appName: appName, userID: userID}
+	}
+}
+
+// addTrace records a new trace, dropping the oldest one if maxTraces traces
+// are stored.
+func (s *APIServerSpanExporter) addTrace(traceID string) {
+	s.spans[traceID] = nil
+	s.traces = append(s.traces, traceID)
+	if len(s.traces) <= maxTraces {
+		return
+	}
+	oldest := s.traces[0]
+	s.traces[0] = ""
+	s.traces = s.traces[1:]
+
+	for _, span := range s.spans[oldest] {
+		sessionID, ok := span.Attributes[sessionIDAttribute].(string)
+		if !ok {
+			continue
+		}
+		traces := slices.DeleteFunc(s.sessionTraces[sessionID], func(id string) bool { return id == oldest })
+		if len(traces) == 0 {
+			delete(s.sessionTraces, sessionID)
+		} else {
+			s.sessionTraces[sessionID] = traces
+		}
+	}
+	for _, eventID := range s.traceEvents[oldest] {
+		delete(s.traceDict, eventID)
+	}
+	delete(s.traceEvents, oldest)
+	delete(s.spans, oldest)
+	delete(s.owners, oldest)
+}
+
 // Shutdown is a function that sdktrace.SpanExporter has, should close the span exporter connections.
 // Since APIServerSpanExporter holds only in-memory dictionary, no additional logic required.
 func (s *APIServerSpanExporter) Shutdown(ctx context.Context) error {
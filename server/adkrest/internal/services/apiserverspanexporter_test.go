@@ -16,6 +16,7 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"go.opentelemetry.io/otel/attribute"
@@ -42,11 +43,13 @@ func TestNewAPIServerSpanExporter(t *testing.T) {
 	if exporter == nil {
 		t.Fatal("NewAPIServerSpanExporter returned nil")
 	}
-	if exporter.GetTraceDict() == nil {
-		t.Error("traceDict should be non-nil")
+	if _, ok := exporter.GetEventTrace("event-id", allowAll); ok {
+		t.Error("GetEventTrace() found an event in a new exporter")
 	}
 }
 
+func allowAll(appName, userID string) bool { return true }
+
 func TestAPIServerSpanExporterExportSpans(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -113,20 +116,13 @@ func TestAPIServerSpanExporterExportSpans(t *testing.T) {
 				t.Fatalf("ExportSpans() error = %v", err)
 			}
 
-			traceDict := apiServerExporter.GetTraceDict()
-
+			eventDict, ok := apiServerExporter.GetEventTrace("event-id", allowAll)
 			if !tc.expectedEvent {
-				if len(traceDict) != 0 {
-					t.Errorf("traceDict should be empty, but has %d items", len(traceDict))
+				if ok {
+					t.Errorf("GetEventTrace() = %v, want no event", eventDict)
 				}
 				return
 			}
-
-			if len(traceDict) != 1 {
-				t.Fatalf("traceDict should have 1 item, but has %d", len(traceDict))
-			}
-
-			eventDict, ok := traceDict["event-id"]
 			if !ok {
 				t.Fatalf("traceDict should contain event ID event-id")
 			}
@@ -142,6 +138,110 @@ func TestAPIServerSpanExporterExportSpans(t *testing.T) {
 	}
 }
 
+func TestAPIServerSpanExporterGetSessionSpans(t *testing.T) {
+	ctx := t.Context()
+	exporter := NewAPIServerSpanExporter()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)).Tracer("test-tracer")
+
+	// The session ID is only set on the root span, which ends last.
+	rootCtx, root := tracer.Start(ctx, "invocation", trace.WithAttributes(attribute.String("gcp.vertex.agent.session_id", "s1")))
+	_, child := tracer.Start(rootCtx, "call_llm")
+	child.End()
+	root.End()
+	_, other := tracer.Start(ctx, "invocation", trace.WithAttributes(attribute.String("gcp.vertex.agent.session_id", "s2")))
+	other.End()
+
+	got := exporter.GetSessionSpans("s1", allowAll)
+	if len(got) != 2 {
+		t.Fatalf("GetSessionSpans() returned %d spans, want 2", len(got))
+	}
+	if got[0].Name != "invocation" || got[1].Name != "call_llm" {
+		t.Errorf("GetSessionSpans() names = %q, %q, want invocation, call_llm", got[0].Name, got[1].Name)
+	}
+	if got[0].ParentSpanID != "" {
+		t.Errorf("root span parent = %q, want empty", got[0].ParentSpanID)
+	}
+	if got[1].ParentSpanID != got[0].SpanID {
+		t.Errorf("child span parent = %q, want %q", got[1].ParentSpanID, got[0].SpanID)
+	}
+	if got[0].Attributes["gcp.vertex.agent.session_id"] != "s1" {
+		t.Errorf("root span attributes = %v, want the session ID", got[0].Attributes)
+	}
+	if spans := exporter.GetSessionSpans("unknown", allowAll); len(spans) != 0 {
+		t.Errorf("GetSessionSpans(unknown) = %v, want empty", spans)
+	}
+}
+
+func TestAPIServerSpanExporterOwners(t *testing.T) {
+	ctx := t.Context()
+	exporter := NewAPIServerSpanExporter()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)).Tracer("test-tracer")
+
+	for _, user := range []string{"alice", "bob"} {
+		rootCtx, root := tracer.Start(ctx, "invocation", trace.WithAttributes(
+			attribute.String("gcp.vertex.agent.app_name", "app"),
+			attribute.String("gcp.vertex.agent.user_id", user),
+			attribute.String("gcp.vertex.agent.session_id", "s1"),
+		))
+		_, child := tracer.Start(rootCtx, "call_llm", trace.WithAttributes(attribute.String("gcp.vertex.agent.event_id", "event-"+user)))
+		child.End()
+		root.End()
+	}
+	onlyAlice := func(appName, userID string) bool { return appName == "app" && userID == "alice" }
+
+	got := exporter.GetSessionSpans("s1", onlyAlice)
+	if len(got) != 2 {
+		t.Fatalf("GetSessionSpans() returned %d spans, want 2", len(got))
+	}
+	for _, span := range got {
+		if span.TraceID != got[0].TraceID {
+			t.Errorf("GetSessionSpans() returned spans of traces %q and %q, want the trace of alice", got[0].TraceID, span.TraceID)
+		}
+	}
+	if _, ok := exporter.GetEventTrace("event-bob", onlyAlice); ok {
+		t.Error("GetEventTrace(event-bob) returned the event of another user")
+	}
+	attributes, ok := exporter.GetEventTrace("event-alice", onlyAlice)
+	if !ok {
+		t.Fatal("GetEventTrace(event-alice) found no event")
+	}
+	// The attributes are a copy.
+	attributes["span_id"] = "changed"
+	if attributes, _ := exporter.GetEventTrace("event-alice", onlyAlice); attributes["span_id"] == "changed" {
+		t.Error("GetEventTrace() returned the stored attributes")
+	}
+}
+
+func TestAPIServerSpanExporterLimit(t *testing.T) {
+	ctx := t.Context()
+	exporter := NewAPIServerSpanExporter()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)).Tracer("test-tracer")
+
+	for i := range maxTraces + 10 {
+		_, span := tracer.Start(ctx, "call_llm", trace.WithAttributes(
+			attribute.String("gcp.vertex.agent.session_id", fmt.Sprintf("s%d", i)),
+			attribute.String("gcp.vertex.agent.event_id", fmt.Sprintf("e%d", i)),
+		))
+		span.End()
+	}
+
+	if got := len(exporter.spans); got != maxTraces {
+		t.Errorf("exporter stores %d traces, want %d", got, maxTraces)
+	}
+	if got := len(exporter.sessionTraces); got != maxTraces {
+		t.Errorf("exporter stores the traces of %d sessions, want %d", got, maxTraces)
+	}
+	if spans := exporter.GetSessionSpans("s0", allowAll); len(spans) != 0 {
+		t.Errorf("GetSessionSpans(s0) = %v, want the oldest trace dropped", spans)
+	}
+	if _, ok := exporter.GetEventTrace("e0", allowAll); ok {
+		t.Error("GetEventTrace(e0) found the event of the oldest trace")
+	}
+	if _, ok := exporter.GetEventTrace(fmt.Sprintf("e%d", maxTraces+9), allowAll); !ok {
+		t.Error("GetEventTrace() did not find the event of the last trace")
+	}
+}
+
 func TestAPIServerSpanExporterShutdown(t *testing.T) {
 	exporter := NewAPIServerSpanExporter()
 	if err := exporter.Shutdown(context.Background()); err != nil {
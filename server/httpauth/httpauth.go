@@ -0,0 +1,188 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package httpauth provides an HTTP middleware authenticating the requests
+// served by the launchers, with static API keys or OIDC tokens.
+//
+// The authenticated [Principal] is stored in the request context. The ADK
+// REST API uses its subject as the user ID, and asks an [Authorizer] whether
+// it can access the data of the user named by a request.
+package httpauth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// ErrNoCredentials is returned by an Authenticator when the request does not
+// carry the credentials it handles.
+var ErrNoCredentials = errors.New("no credentials")
+
+// Principal is an authenticated caller.
+type Principal struct {
+	// Subject identifies the caller. It is used as the user ID.
+	Subject string
+	// Roles granted to the caller.
+	Roles []string
+	// Claims are the claims of the token, for OIDC principals.
+	Claims map[string]any
+}
+
+type principalKey struct{}
+
+// ToContext returns a context carrying the principal.
+func ToContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal of the context, or nil if the request
+// was not authenticated.
+func FromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
+
+// Authenticator authenticates HTTP requests.
+type Authenticator interface {
+	// Authenticate returns the principal making the request. It returns
+	// ErrNoCredentials if the request does not carry credentials of its kind.
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// Config defines the configuration of the middleware.
+type Config struct {
+	// Authenticators are tried in order, the first one finding credentials
+	// in the request decides.
+	Authenticators []Authenticator
+	// ExemptPaths are served without authentication. A path ending with "/"
+	// exempts all the paths it prefixes, other paths must match exactly.
+	ExemptPaths []string
+}
+
+// Middleware returns a middleware rejecting the requests that are not
+// authenticated with a 401 status. CORS preflight requests are not
+// authenticated.
+func Middleware(cfg Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodOptions || exempt(cfg.ExemptPaths, r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			for _, a := range cfg.Authenticators {
+				p, err := a.Authenticate(r)
+				if errors.Is(err, ErrNoCredentials) {
+					continue
+				}
+				if err != nil {
+					http.Error(w, "unauthenticated: "+err.Error(), http.StatusUnauthorized)
+					return
+				}
+				next.ServeHTTP(w, r.WithContext(ToContext(r.Context(), p)))
+				return
+			}
+			http.Error(w, "unauthenticated: missing credentials", http.StatusUnauthorized)
+		})
+	}
+}
+
+func exempt(paths []string, path string) bool {
+	for _, p := range paths {
+		if p == path || (strings.HasSuffix(p, "/") && strings.HasPrefix(path, p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeys returns an Authenticator accepting the static API keys sent in the
+// given header. keys maps each API key to the principal it authenticates.
+func APIKeys(header string, keys map[string]*Principal) Authenticator {
+	return &apiKeys{header: header, keys: keys}
+}
+
+type apiKeys struct {
+	header string
+	keys   map[string]*Principal
+}
+
+// Authenticate implements Authenticator.
+func (a *apiKeys) Authenticate(r *http.Request) (*Principal, error) {
+	key := r.Header.Get(a.header)
+	if key == "" {
+		return nil, ErrNoCredentials
+	}
+	for k, p := range a.keys {
+		if subtle.ConstantTimeCompare([]byte(k), []byte(key)) == 1 {
+			return p, nil
+		}
+	}
+	return nil, errors.New("invalid API key")
+}
+
+// ErrForbidden is returned by an Authorizer denying an access.
+var ErrForbidden = errors.New("forbidden")
+
+// AccessRequest describes an access to the data of a user.
+type AccessRequest struct {
+	// AppName is the application accessed, if known.
+	AppName string
+	// UserID is the user owning the data.
+	UserID string
+	// Method is the HTTP method of the request.
+	Method string
+	// Admin reports whether the request administers the server, e.g. the
+	// registration of an app, rather than accessing the data of UserID,
+	// which is then empty. The authorizers should only allow it to the
+	// admins.
+	Admin bool
+}
+
+// Authorizer decides whether a principal can access the data of a user.
+type Authorizer interface {
+	// Authorize returns nil if the access is allowed, or an error wrapping
+	// ErrForbidden.
+	Authorize(ctx context.Context, p *Principal, req AccessRequest) error
+}
+
+// AuthorizerFunc is an Authorizer implemented by a function.
+type AuthorizerFunc func(ctx context.Context, p *Principal, req AccessRequest) error
+
+// Authorize implements Authorizer.
+func (f AuthorizerFunc) Authorize(ctx context.Context, p *Principal, req AccessRequest) error {
+	return f(ctx, p, req)
+}
+
+// UserAuthorizer returns an Authorizer allowing the principals to access
+// their own data. The principals having one of adminRoles can access the
+// data of all users, and are the only ones allowed the admin requests.
+func UserAuthorizer(adminRoles ...string) Authorizer {
+	return AuthorizerFunc(func(ctx context.Context, p *Principal, req AccessRequest) error {
+		if slices.ContainsFunc(p.Roles, func(role string) bool { return slices.Contains(adminRoles, role) }) {
+			return nil
+		}
+		if req.Admin {
+			return fmt.Errorf("%w: user %q is not an admin", ErrForbidden, p.Subject)
+		}
+		if p.Subject == req.UserID {
+			return nil
+		}
+		return fmt.Errorf("%w: user %q cannot access the data of user %q", ErrForbidden, p.Subject, req.UserID)
+	})
+}
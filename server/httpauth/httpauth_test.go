@@ -0,0 +1,197 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpauth_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+
+	"google.golang.org/adk/server/httpauth"
+)
+
+// echoSubject answers with the subject of the authenticated principal.
+var echoSubject = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	if p := httpauth.FromContext(r.Context()); p != nil {
+		io.WriteString(w, p.Subject)
+	}
+})
+
+func serve(t *testing.T, h http.Handler, req *http.Request) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code, rec.Body.String()
+}
+
+func TestMiddleware_APIKeys(t *testing.T) {
+	h := httpauth.Middleware(httpauth.Config{
+		Authenticators: []httpauth.Authenticator{httpauth.APIKeys("X-API-Key", map[string]*httpauth.Principal{"key-1": {Subject: "alice"}})},
+		ExemptPaths:    []string{"/.well-known/agent-card.json", "/ui/"},
+	})(echoSubject)
+
+	for _, tc := range []struct {
+		name        string
+		method      string
+		path        string
+		key         string
+		wantStatus  int
+		wantSubject string
+	}{
+		{name: "valid key", method: "GET", path: "/api/list-apps", key: "key-1", wantStatus: http.StatusOK, wantSubject: "alice"},
+		{name: "invalid key", method: "GET", path: "/api/list-apps", key: "key-2", wantStatus: http.StatusUnauthorized},
+		{name: "no key", method: "GET", path: "/api/list-apps", wantStatus: http.StatusUnauthorized},
+		{name: "exempt path", method: "GET", path: "/.well-known/agent-card.json", wantStatus: http.StatusOK},
+		{name: "exempt prefix", method: "GET", path: "/ui/index.html", wantStatus: http.StatusOK},
+		{name: "not an exempt prefix", method: "GET", path: "/.well-known/agent-card.json/x", wantStatus: http.StatusUnauthorized},
+		{name: "preflight", method: "OPTIONS", path: "/api/list-apps", wantStatus: http.StatusOK},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			if tc.key != "" {
+				req.Header.Set("X-API-Key", tc.key)
+			}
+			status, body := serve(t, h, req)
+			if status != tc.wantStatus {
+				t.Fatalf("status = %d, want %d (body %q)", status, tc.wantStatus, body)
+			}
+			if status == http.StatusOK && body != tc.wantSubject {
+				t.Errorf("subject = %q, want %q", body, tc.wantSubject)
+			}
+		})
+	}
+}
+
+func TestMiddleware_OIDC(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const kid = "key-1"
+	var fetches int
+	var issuer string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"jwks_uri": issuer + "/jwks"})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{Key: key.Public(), KeyID: kid, Algorithm: string(jose.ES256), Use: "sig"}}})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	issuer = srv.URL
+
+	oidc, err := httpauth.NewOIDC(httpauth.OIDCConfig{Issuer: issuer, Audience: "adk", UserClaim: "email"})
+	if err != nil {
+		t.Fatalf("NewOIDC() failed: %v", err)
+	}
+	h := httpauth.Middleware(httpauth.Config{Authenticators: []httpauth.Authenticator{oidc}})(echoSubject)
+
+	sign := func(signingKey any, claims jwt.Claims, email string) string {
+		signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.ES256, Key: signingKey}, (&jose.SignerOptions{}).WithHeader("kid", kid))
+		if err != nil {
+			t.Fatal(err)
+		}
+		token, err := jwt.Signed(signer).Claims(claims).Claims(map[string]any{"email": email}).Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	now := time.Now()
+	valid := jwt.Claims{Issuer: issuer, Audience: jwt.Audience{"adk"}, Subject: "123", Expiry: jwt.NewNumericDate(now.Add(time.Hour)), IssuedAt: jwt.NewNumericDate(now)}
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	for _, tc := range []struct {
+		name        string
+		token       string
+		wantStatus  int
+		wantSubject string
+	}{
+		{name: "valid", token: sign(key, valid, "alice@example.com"), wantStatus: http.StatusOK, wantSubject: "alice@example.com"},
+		{name: "wrong audience", token: sign(key, jwt.Claims{Issuer: issuer, Audience: jwt.Audience{"other"}, Expiry: valid.Expiry}, "a@example.com"), wantStatus: http.StatusUnauthorized},
+		{name: "wrong issuer", token: sign(key, jwt.Claims{Issuer: "https://other", Audience: valid.Audience, Expiry: valid.Expiry}, "a@example.com"), wantStatus: http.StatusUnauthorized},
+		{name: "expired", token: sign(key, jwt.Claims{Issuer: issuer, Audience: valid.Audience, Expiry: jwt.NewNumericDate(now.Add(-time.Hour))}, "a@example.com"), wantStatus: http.StatusUnauthorized},
+		{name: "wrong signature", token: sign(otherKey, valid, "a@example.com"), wantStatus: http.StatusUnauthorized},
+		{name: "missing user claim", token: sign(key, valid, ""), wantStatus: http.StatusUnauthorized},
+		{name: "malformed", token: "not-a-jwt", wantStatus: http.StatusUnauthorized},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/list-apps", nil)
+			req.Header.Set("Authorization", "Bearer "+tc.token)
+			status, body := serve(t, h, req)
+			if status != tc.wantStatus {
+				t.Fatalf("status = %d, want %d (body %q)", status, tc.wantStatus, body)
+			}
+			if status == http.StatusOK && body != tc.wantSubject {
+				t.Errorf("subject = %q, want %q", body, tc.wantSubject)
+			}
+		})
+	}
+	if fetches != 1 {
+		t.Errorf("keys fetched %d times, want 1", fetches)
+	}
+}
+
+func TestNewOIDC_Errors(t *testing.T) {
+	for _, cfg := range []httpauth.OIDCConfig{
+		{Audience: "adk"},
+		{Issuer: "https://issuer"},
+	} {
+		if _, err := httpauth.NewOIDC(cfg); err == nil {
+			t.Errorf("NewOIDC(%+v) succeeded, want error", cfg)
+		}
+	}
+}
+
+func TestUserAuthorizer(t *testing.T) {
+	a := httpauth.UserAuthorizer("admin")
+	for _, tc := range []struct {
+		name      string
+		principal *httpauth.Principal
+		userID    string
+		admin     bool
+		wantErr   bool
+	}{
+		{name: "own data", principal: &httpauth.Principal{Subject: "alice"}, userID: "alice"},
+		{name: "admin request", principal: &httpauth.Principal{Subject: "root", Roles: []string{"admin"}}, admin: true},
+		{name: "admin request of a user", principal: &httpauth.Principal{Subject: "alice"}, admin: true, wantErr: true},
+		{name: "admin request without subject", principal: &httpauth.Principal{}, admin: true, wantErr: true},
+		{name: "other user", principal: &httpauth.Principal{Subject: "alice"}, userID: "bob", wantErr: true},
+		{name: "admin", principal: &httpauth.Principal{Subject: "root", Roles: []string{"admin"}}, userID: "bob"},
+		{name: "other role", principal: &httpauth.Principal{Subject: "carol", Roles: []string{"viewer"}}, userID: "bob", wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := a.Authorize(t.Context(), tc.principal, httpauth.AccessRequest{AppName: "app", UserID: tc.userID, Method: http.MethodGet, Admin: tc.admin})
+			if gotErr := err != nil; gotErr != tc.wantErr {
+				t.Fatalf("Authorize() = %v, want error %v", err, tc.wantErr)
+			}
+			if err != nil && !errors.Is(err, httpauth.ErrForbidden) {
+				t.Errorf("Authorize() = %v, want it to wrap ErrForbidden", err)
+			}
+		})
+	}
+}
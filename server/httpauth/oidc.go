@@ -0,0 +1,187 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpauth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+)
+
+// minKeysRefreshInterval limits how often the keys are fetched again when a
+// token is signed with an unknown key.
+const minKeysRefreshInterval = time.Minute
+
+var signatureAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+// OIDCConfig defines the configuration of an OIDC Authenticator.
+type OIDCConfig struct {
+	// Issuer must match the "iss" claim of the tokens. The keys are
+	// discovered from its /.well-known/openid-configuration document.
+	Issuer string
+	// Audience must be one of the "aud" claims of the tokens.
+	Audience string
+	// JWKSURL overrides the URL of the keys found by discovery.
+	JWKSURL string
+	// UserClaim is the claim used as the subject of the principal.
+	// If empty, "sub" is used.
+	UserClaim string
+	// RolesClaim is the claim listing the roles of the principal, as an
+	// array or a space separated string. If empty, no role is granted.
+	RolesClaim string
+	// HTTPClient is used to fetch the keys. If nil, http.DefaultClient is
+	// used.
+	HTTPClient *http.Client
+}
+
+// NewOIDC returns an Authenticator accepting the JWTs sent as bearer tokens
+// in the Authorization header, signed by the issuer. The keys are fetched on
+// the first request.
+func NewOIDC(cfg OIDCConfig) (Authenticator, error) {
+	if cfg.Issuer == "" {
+		return nil, errors.New("issuer is required")
+	}
+	if cfg.Audience == "" {
+		return nil, errors.New("audience is required")
+	}
+	if cfg.UserClaim == "" {
+		cfg.UserClaim = "sub"
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = http.DefaultClient
+	}
+	return &oidc{cfg: cfg}, nil
+}
+
+type oidc struct {
+	cfg OIDCConfig
+
+	mu        sync.Mutex
+	keys      *jose.JSONWebKeySet
+	fetchedAt time.Time
+}
+
+// Authenticate implements Authenticator.
+func (o *oidc) Authenticate(r *http.Request) (*Principal, error) {
+	scheme, raw, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return nil, ErrNoCredentials
+	}
+	token, err := jwt.ParseSigned(raw, signatureAlgorithms)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+	keys, err := o.keySet(r, false)
+	if err != nil {
+		return nil, err
+	}
+	var claims jwt.Claims
+	var allClaims map[string]any
+	err = token.Claims(keys, &claims, &allClaims)
+	if errors.Is(err, jose.ErrJWKSKidNotFound) {
+		// The keys may have been rotated.
+		if keys, err = o.keySet(r, true); err != nil {
+			return nil, err
+		}
+		err = token.Claims(keys, &claims, &allClaims)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+	if err := claims.Validate(jwt.Expected{
+		Issuer:      o.cfg.Issuer,
+		AnyAudience: jwt.Audience{o.cfg.Audience},
+		Time:        time.Now(),
+	}); err != nil {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+	subject, _ := allClaims[o.cfg.UserClaim].(string)
+	if subject == "" {
+		return nil, fmt.Errorf("invalid token: missing %q claim", o.cfg.UserClaim)
+	}
+	return &Principal{Subject: subject, Roles: o.roles(allClaims), Claims: allClaims}, nil
+}
+
+// keySet returns the keys of the issuer, fetching them if needed.
+func (o *oidc) keySet(r *http.Request, refresh bool) (*jose.JSONWebKeySet, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.keys != nil && (!refresh || time.Since(o.fetchedAt) < minKeysRefreshInterval) {
+		return o.keys, nil
+	}
+	jwksURL := o.cfg.JWKSURL
+	if jwksURL == "" {
+		var discovery struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := o.get(r, strings.TrimSuffix(o.cfg.Issuer, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+			return nil, fmt.Errorf("failed to discover the issuer keys: %w", err)
+		}
+		jwksURL = discovery.JWKSURI
+	}
+	var keys jose.JSONWebKeySet
+	if err := o.get(r, jwksURL, &keys); err != nil {
+		return nil, fmt.Errorf("failed to fetch the issuer keys: %w", err)
+	}
+	o.keys, o.fetchedAt = &keys, time.Now()
+	return o.keys, nil
+}
+
+func (o *oidc) get(r *http.Request, url string, v any) error {
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := o.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func (o *oidc) roles(claims map[string]any) []string {
+	if o.cfg.RolesClaim == "" {
+		return nil
+	}
+	switch v := claims[o.cfg.RolesClaim].(type) {
+	case string:
+		return strings.Fields(v)
+	case []any:
+		var roles []string
+		for _, r := range v {
+			if role, ok := r.(string); ok {
+				roles = append(roles, role)
+			}
+		}
+		return roles
+	}
+	return nil
+}
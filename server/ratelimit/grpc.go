@@ -0,0 +1,68 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"google.golang.org/adk/server/adkgrpc/adkpb"
+)
+
+// StreamServerInterceptor returns a gRPC interceptor rejecting the Run calls
+// of the AgentService exceeding the limits with the ResourceExhausted code
+// and a retry-after header, in seconds. The other calls are not limited.
+//
+// The application and the user are read from the request. The subject of
+// the authenticated principal, if any, takes precedence over the user of the
+// request. Config.Paths and Config.MaxBodySize are not used.
+func StreamServerInterceptor(cfg Config) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if info.FullMethod != adkpb.AgentService_Run_FullMethodName || (cfg.PerUser == nil && cfg.PerApp == nil) {
+			return handler(srv, ss)
+		}
+		return handler(srv, &limitedStream{ServerStream: ss, cfg: &cfg})
+	}
+}
+
+// limitedStream checks the limits when the request is received.
+type limitedStream struct {
+	grpc.ServerStream
+	cfg     *Config
+	checked bool
+}
+
+func (s *limitedStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil || s.checked {
+		return err
+	}
+	s.checked = true
+	req, ok := m.(interface {
+		GetAppName() string
+		GetUserId() string
+	})
+	if !ok {
+		return nil
+	}
+	if err := s.cfg.check(s.Context(), req.GetAppName(), req.GetUserId()); err != nil {
+		s.SetHeader(metadata.Pairs("retry-after", strconv.Itoa(retryAfterSeconds(err.RetryAfter))))
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"google.golang.org/adk/server/adkgrpc/adkpb"
+	"google.golang.org/adk/server/httpauth"
+)
+
+// fakeStream receives a copy of its request.
+type fakeStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	req    *adkpb.RunRequest
+	header metadata.MD
+}
+
+func (s *fakeStream) Context() context.Context { return s.ctx }
+
+func (s *fakeStream) RecvMsg(m any) error {
+	m.(*adkpb.RunRequest).AppName = s.req.AppName
+	m.(*adkpb.RunRequest).UserId = s.req.UserId
+	return nil
+}
+
+func (s *fakeStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	perUser := &fakeLimiter{n: 1}
+	interceptor := StreamServerInterceptor(Config{PerUser: perUser})
+	handler := func(srv any, ss grpc.ServerStream) error {
+		return ss.RecvMsg(&adkpb.RunRequest{})
+	}
+
+	run := func(method string, ctx context.Context, req *adkpb.RunRequest) (*fakeStream, error) {
+		ss := &fakeStream{ctx: ctx, req: req}
+		return ss, interceptor(nil, ss, &grpc.StreamServerInfo{FullMethod: method, IsServerStream: true}, handler)
+	}
+
+	req := &adkpb.RunRequest{AppName: "app", UserId: "u1"}
+	if _, err := run(adkpb.AgentService_Run_FullMethodName, t.Context(), req); err != nil {
+		t.Fatalf("first run failed: %v", err)
+	}
+	ss, err := run(adkpb.AgentService_Run_FullMethodName, t.Context(), req)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("second run = %v, want ResourceExhausted", err)
+	}
+	if got := ss.header.Get("retry-after"); len(got) != 1 || got[0] != "2" {
+		t.Errorf("retry-after header = %v, want [2]", got)
+	}
+	// The principal takes precedence over the user of the request.
+	ctx := httpauth.ToContext(t.Context(), &httpauth.Principal{Subject: "u2"})
+	if _, err := run(adkpb.AgentService_Run_FullMethodName, ctx, req); err != nil {
+		t.Errorf("run of another principal failed: %v", err)
+	}
+	// The other methods are not limited.
+	if _, err := run("/google.adk.v1.AgentService/Other", t.Context(), req); err != nil {
+		t.Errorf("other method failed: %v", err)
+	}
+
+	wantKeys := []string{"user:app:u1", "user:app:u1", "user:app:u2"}
+	if diff := cmp.Diff(wantKeys, perUser.keys); diff != "" {
+		t.Errorf("keys mismatch (-want +got):\n%s", diff)
+	}
+}
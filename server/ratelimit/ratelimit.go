@@ -0,0 +1,298 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ratelimit provides an HTTP middleware applying token-bucket rate
+// limits to the runs of the ADK REST API, per user and per application, and
+// a gRPC interceptor applying them to the runs of the ADK gRPC API.
+//
+// The buckets are kept by a [Limiter]. [NewInMemory] keeps them in the
+// memory of the process, which suits a single replica. [NewRedis] keeps them
+// in Redis, so that replicas sharing a deployment share the limits.
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"golang.org/x/time/rate"
+
+	"google.golang.org/adk/server/httpauth"
+)
+
+// DefaultPaths are the path templates of the REST API routes limited when
+// Config.Paths is empty.
+var DefaultPaths = []string{"/run", "/run_sse", "/run/resume", "/run_live", "/run_ws"}
+
+// DefaultMaxBodySize is the size limit, in bytes, of the request bodies read
+// by the middleware when Config.MaxBodySize is zero.
+const DefaultMaxBodySize = 32 << 20
+
+// Rate defines a token bucket.
+type Rate struct {
+	// PerSecond is the number of tokens added to the bucket each second.
+	PerSecond float64
+	// Burst is the capacity of the bucket, i.e. the number of requests that
+	// can be made at once.
+	Burst int
+}
+
+// PerMinute returns a Rate allowing n requests per minute, in bursts of at
+// most burst requests.
+func PerMinute(n, burst int) Rate {
+	return Rate{PerSecond: float64(n) / 60, Burst: burst}
+}
+
+func (r Rate) validate() error {
+	if r.PerSecond <= 0 || r.Burst <= 0 {
+		return fmt.Errorf("invalid rate %v per second with burst %d", r.PerSecond, r.Burst)
+	}
+	return nil
+}
+
+// Limiter keeps a token bucket per key.
+type Limiter interface {
+	// Allow takes a token from the bucket of key. If the bucket is empty, it
+	// returns false and the time after which a token will be available.
+	Allow(ctx context.Context, key string) (ok bool, retryAfter time.Duration, err error)
+}
+
+// NewInMemory returns a Limiter keeping its buckets in memory.
+func NewInMemory(r Rate) (Limiter, error) {
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	return &inMemory{
+		rate:    r,
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}, nil
+}
+
+// pruneInterval is the number of calls between two removals of the full
+// buckets, which behave as the ones not yet created.
+const pruneInterval = 1000
+
+type inMemory struct {
+	rate Rate
+	now  func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	calls   int
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+// Allow implements Limiter.
+func (l *inMemory) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.calls++
+	if l.calls%pruneInterval == 0 {
+		l.prune(now)
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(l.rate.PerSecond), l.rate.Burst)}
+		l.buckets[key] = b
+	}
+	b.lastUsed = now
+	res := b.limiter.ReserveN(now, 1)
+	if delay := res.DelayFrom(now); delay > 0 {
+		res.CancelAt(now)
+		return false, delay, nil
+	}
+	return true, 0, nil
+}
+
+func (l *inMemory) prune(now time.Time) {
+	refill := time.Duration(float64(l.rate.Burst) / l.rate.PerSecond * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.lastUsed) > refill {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Config defines the configuration of the middleware.
+type Config struct {
+	// PerUser limits the runs of each user of each application. Its keys are
+	// "user:<app>:<user>".
+	PerUser Limiter
+	// PerApp limits the runs of each application, all users together. Its
+	// keys are "app:<app>".
+	PerApp Limiter
+	// Paths are the path templates of the limited routes. If empty,
+	// DefaultPaths are limited.
+	Paths []string
+	// MaxBodySize is the size limit, in bytes, of the bodies of the limited
+	// requests. Larger requests are rejected with a 413 status. Defaults to
+	// DefaultMaxBodySize.
+	MaxBodySize int64
+	// Logger receives the errors of the limiters, which let the requests
+	// through. If nil, slog.Default() is used.
+	Logger *slog.Logger
+}
+
+// Middleware returns a gorilla/mux middleware rejecting the runs exceeding
+// the limits with a 429 status and a Retry-After header.
+//
+// The application and the user are read from the body of the run requests,
+// or from the app_name and user_id query parameters of the WebSocket runs.
+// The WebSocket runs not naming their application in the query, as the ones
+// of /run_ws which name it in their first message, are checked by the
+// handler with [Check]. The subject of the authenticated principal, if any,
+// takes precedence over the user of the request.
+func Middleware(cfg Config) mux.MiddlewareFunc {
+	paths := cfg.Paths
+	if len(paths) == 0 {
+		paths = DefaultPaths
+	}
+	maxBodySize := cfg.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultMaxBodySize
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !limited(r, paths) || (cfg.PerUser == nil && cfg.PerApp == nil) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			var run struct {
+				AppName string `json:"appName"`
+				UserID  string `json:"userId"`
+			}
+			if r.Method == http.MethodGet {
+				query := r.URL.Query()
+				run.AppName, run.UserID = query.Get("app_name"), query.Get("user_id")
+				if run.AppName == "" {
+					next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), configKey{}, &cfg)))
+					return
+				}
+			} else {
+				body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+				if err != nil {
+					status := http.StatusBadRequest
+					if maxBytesErr := (*http.MaxBytesError)(nil); errors.As(err, &maxBytesErr) {
+						status = http.StatusRequestEntityTooLarge
+					}
+					http.Error(w, err.Error(), status)
+					return
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+				// Malformed bodies are rejected by the handler.
+				_ = json.Unmarshal(body, &run)
+			}
+
+			if err := cfg.check(r.Context(), run.AppName, run.UserID); err != nil {
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(err.RetryAfter)))
+				http.Error(w, err.Error(), http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// LimitError is returned for the runs exceeding the limits.
+type LimitError struct {
+	// RetryAfter is the time after which the run may be retried.
+	RetryAfter time.Duration
+}
+
+func (e *LimitError) Error() string {
+	return "rate limit exceeded"
+}
+
+type configKey struct{}
+
+// Check takes a token from the buckets of the user and of the application of
+// a run whose request was let through by the middleware unchecked, i.e. a
+// WebSocket run naming its application in a message. It returns a
+// *LimitError if one of the buckets is empty, and nil if the request was not
+// limited by the middleware.
+func Check(ctx context.Context, appName, userID string) error {
+	cfg, ok := ctx.Value(configKey{}).(*Config)
+	if !ok {
+		return nil
+	}
+	if err := cfg.check(ctx, appName, userID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// check takes a token from the buckets of the user and of the application.
+// The limiters failing let the run through.
+func (cfg *Config) check(ctx context.Context, appName, userID string) *LimitError {
+	if p := httpauth.FromContext(ctx); p != nil {
+		userID = p.Subject
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	checks := []struct {
+		limiter Limiter
+		key     string
+	}{
+		{cfg.PerUser, "user:" + appName + ":" + userID},
+		{cfg.PerApp, "app:" + appName},
+	}
+	for _, c := range checks {
+		if c.limiter == nil {
+			continue
+		}
+		ok, retryAfter, err := c.limiter.Allow(ctx, c.key)
+		if err != nil {
+			logger.ErrorContext(ctx, "rate limiter failed", "key", c.key, "error", err)
+			continue
+		}
+		if !ok {
+			return &LimitError{RetryAfter: retryAfter}
+		}
+	}
+	return nil
+}
+
+func limited(r *http.Request, paths []string) bool {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return false
+	}
+	tmpl, err := route.GetPathTemplate()
+	return err == nil && slices.Contains(paths, tmpl)
+}
+
+// retryAfterSeconds rounds d up to a whole number of seconds, at least one.
+func retryAfterSeconds(d time.Duration) int {
+	return max(1, int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,263 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+
+	"google.golang.org/adk/server/httpauth"
+)
+
+func TestInMemory(t *testing.T) {
+	l, err := NewInMemory(Rate{PerSecond: 1, Burst: 2})
+	if err != nil {
+		t.Fatalf("NewInMemory() failed: %v", err)
+	}
+	now := time.Unix(1000, 0)
+	l.(*inMemory).now = func() time.Time { return now }
+
+	type result struct {
+		OK         bool
+		RetryAfter time.Duration
+	}
+	allow := func(key string) result {
+		ok, retryAfter, err := l.Allow(t.Context(), key)
+		if err != nil {
+			t.Fatalf("Allow() failed: %v", err)
+		}
+		return result{ok, retryAfter}
+	}
+
+	got := []result{allow("a"), allow("a"), allow("a"), allow("b")}
+	now = now.Add(500 * time.Millisecond)
+	got = append(got, allow("a"))
+	now = now.Add(500 * time.Millisecond)
+	got = append(got, allow("a"), allow("a"))
+
+	want := []result{
+		{OK: true},
+		{OK: true},
+		{OK: false, RetryAfter: time.Second},
+		{OK: true},
+		{OK: false, RetryAfter: 500 * time.Millisecond},
+		{OK: true},
+		{OK: false, RetryAfter: time.Second},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Allow() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNew_InvalidRate(t *testing.T) {
+	for _, r := range []Rate{{PerSecond: 0, Burst: 1}, {PerSecond: 1, Burst: 0}} {
+		if _, err := NewInMemory(r); err == nil {
+			t.Errorf("NewInMemory(%v) succeeded, want error", r)
+		}
+		if _, err := NewRedis(func(context.Context, string, []string, ...any) (any, error) { return nil, nil }, "", r); err == nil {
+			t.Errorf("NewRedis(%v) succeeded, want error", r)
+		}
+	}
+}
+
+func TestRedis(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		result         any
+		err            error
+		wantOK         bool
+		wantRetryAfter time.Duration
+		wantErr        bool
+	}{
+		{name: "allowed", result: []any{int64(1), int64(0)}, wantOK: true},
+		{name: "rejected", result: []any{int64(0), int64(1500)}, wantRetryAfter: 1500 * time.Millisecond},
+		{name: "eval error", err: errors.New("connection refused"), wantErr: true},
+		{name: "unexpected result", result: "OK", wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotKeys []string
+			var gotArgs []any
+			eval := func(ctx context.Context, script string, keys []string, args ...any) (any, error) {
+				gotKeys, gotArgs = keys, args
+				return tc.result, tc.err
+			}
+			l, err := NewRedis(eval, "adk:", Rate{PerSecond: 2, Burst: 5})
+			if err != nil {
+				t.Fatalf("NewRedis() failed: %v", err)
+			}
+			l.(*redisLimiter).now = func() time.Time { return time.UnixMilli(1234) }
+
+			ok, retryAfter, err := l.Allow(t.Context(), "user:app:u1")
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Allow() error = %v, want error %v", err, tc.wantErr)
+			}
+			if ok != tc.wantOK || retryAfter != tc.wantRetryAfter {
+				t.Errorf("Allow() = %v, %v, want %v, %v", ok, retryAfter, tc.wantOK, tc.wantRetryAfter)
+			}
+			if diff := cmp.Diff([]string{"adk:user:app:u1"}, gotKeys); diff != "" {
+				t.Errorf("keys mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff([]any{2.0, 5, int64(1234)}, gotArgs); diff != "" {
+				t.Errorf("args mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// fakeLimiter allows the first n requests of each key and records the keys.
+type fakeLimiter struct {
+	n    int
+	err  error
+	keys []string
+}
+
+func (l *fakeLimiter) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	l.keys = append(l.keys, key)
+	if l.err != nil {
+		return false, 0, l.err
+	}
+	count := 0
+	for _, k := range l.keys {
+		if k == key {
+			count++
+		}
+	}
+	return count <= l.n, 1200 * time.Millisecond, nil
+}
+
+func TestMiddleware(t *testing.T) {
+	perUser := &fakeLimiter{n: 1}
+	perApp := &fakeLimiter{n: 2}
+	router := mux.NewRouter()
+	router.Use(Middleware(Config{PerUser: perUser, PerApp: perApp}))
+	echo := func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(w, r.Body)
+	}
+	router.HandleFunc("/run", echo)
+	router.HandleFunc("/list-apps", echo)
+
+	type response struct {
+		Status     int
+		RetryAfter string
+		Body       string
+	}
+	do := func(path, body string, p *httpauth.Principal) response {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if p != nil {
+			req = req.WithContext(httpauth.ToContext(req.Context(), p))
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return response{rr.Code, rr.Header().Get("Retry-After"), strings.TrimSpace(rr.Body.String())}
+	}
+
+	const u1 = `{"appName":"app","userId":"u1"}`
+	got := []response{
+		do("/run", u1, nil),
+		do("/run", u1, nil),
+		do("/run", `{"appName":"app","userId":"u2"}`, nil),
+		do("/run", `{"appName":"app","userId":"u3"}`, &httpauth.Principal{Subject: "u4"}),
+		do("/list-apps", "apps", nil),
+	}
+	want := []response{
+		{Status: http.StatusOK, Body: u1},
+		{Status: http.StatusTooManyRequests, RetryAfter: "2", Body: "rate limit exceeded"},
+		{Status: http.StatusOK, Body: `{"appName":"app","userId":"u2"}`},
+		{Status: http.StatusTooManyRequests, RetryAfter: "2", Body: "rate limit exceeded"},
+		{Status: http.StatusOK, Body: "apps"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("responses mismatch (-want +got):\n%s", diff)
+	}
+	wantUserKeys := []string{"user:app:u1", "user:app:u1", "user:app:u2", "user:app:u4"}
+	if diff := cmp.Diff(wantUserKeys, perUser.keys); diff != "" {
+		t.Errorf("per-user keys mismatch (-want +got):\n%s", diff)
+	}
+	wantAppKeys := []string{"app:app", "app:app", "app:app"}
+	if diff := cmp.Diff(wantAppKeys, perApp.keys); diff != "" {
+		t.Errorf("per-app keys mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMiddleware_LimiterErrorLetsRequestsThrough(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(Middleware(Config{PerUser: &fakeLimiter{err: errors.New("redis is down")}}))
+	router.HandleFunc("/run_sse", func(w http.ResponseWriter, r *http.Request) {})
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/run_sse", strings.NewReader(`{"appName":"app","userId":"u1"}`)))
+	if rr.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusOK)
+	}
+}
+
+func TestMiddleware_WebSocketRuns(t *testing.T) {
+	perUser := &fakeLimiter{n: 1}
+	router := mux.NewRouter()
+	router.Use(Middleware(Config{PerUser: perUser}))
+	router.HandleFunc("/run_live", func(w http.ResponseWriter, r *http.Request) {})
+	router.HandleFunc("/run_ws", func(w http.ResponseWriter, r *http.Request) {
+		// The handler reads the application and the user from the first
+		// message.
+		if err := Check(r.Context(), "app", "u2"); err != nil {
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+		}
+	})
+
+	do := func(target string) int {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, target, nil))
+		return rr.Code
+	}
+	got := []int{
+		do("/run_live?app_name=app&user_id=u1&session_id=s"),
+		do("/run_live?app_name=app&user_id=u1&session_id=s"),
+		do("/run_ws"),
+		do("/run_ws"),
+	}
+	want := []int{http.StatusOK, http.StatusTooManyRequests, http.StatusOK, http.StatusTooManyRequests}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("statuses mismatch (-want +got):\n%s", diff)
+	}
+	wantKeys := []string{"user:app:u1", "user:app:u1", "user:app:u2", "user:app:u2"}
+	if diff := cmp.Diff(wantKeys, perUser.keys); diff != "" {
+		t.Errorf("keys mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := Check(t.Context(), "app", "u3"); err != nil {
+		t.Errorf("Check() outside of the middleware = %v, want nil", err)
+	}
+}
+
+func TestMiddleware_BodyTooLarge(t *testing.T) {
+	router := mux.NewRouter()
+	router.Use(Middleware(Config{PerUser: &fakeLimiter{n: 1}, MaxBodySize: 16}))
+	router.HandleFunc("/run/resume", func(w http.ResponseWriter, r *http.Request) {})
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/run/resume", strings.NewReader(`{"appName":"app","userId":"u1"}`)))
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want %d", rr.Code, http.StatusRequestEntityTooLarge)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RedisEvalFunc runs a Lua script on Redis and returns its result, decoded
+// as the Redis client does: integers as int64 and arrays as []any.
+//
+// It lets the Redis limiter work with any client. For example, with
+// github.com/redis/go-redis:
+//
+//	eval := func(ctx context.Context, script string, keys []string, args ...any) (any, error) {
+//		return client.Eval(ctx, script, keys, args...).Result()
+//	}
+type RedisEvalFunc func(ctx context.Context, script string, keys []string, args ...any) (any, error)
+
+// tokenBucketScript refills the bucket of KEYS[1] for the time elapsed since
+// its last use and takes a token from it. It returns {1, 0} if a token was
+// taken, {0, wait} otherwise, wait being the milliseconds until a token is
+// available. Buckets expire once full again.
+const tokenBucketScript = `
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+  tokens = burst
+  ts = now
+end
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  wait = math.ceil((1 - tokens) * 1000 / rate)
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst * 1000 / rate) + 1000)
+return {allowed, wait}
+`
+
+// NewRedis returns a Limiter keeping its buckets in Redis, in hashes whose
+// keys are the limited keys prefixed with prefix.
+func NewRedis(eval RedisEvalFunc, prefix string, r Rate) (Limiter, error) {
+	if eval == nil {
+		return nil, errors.New("redis eval function is required")
+	}
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	return &redisLimiter{eval: eval, prefix: prefix, rate: r, now: time.Now}, nil
+}
+
+type redisLimiter struct {
+	eval   RedisEvalFunc
+	prefix string
+	rate   Rate
+	now    func() time.Time
+}
+
+// Allow implements Limiter.
+func (l *redisLimiter) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	res, err := l.eval(ctx, tokenBucketScript, []string{l.prefix + key}, l.rate.PerSecond, l.rate.Burst, l.now().UnixMilli())
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to run the token bucket script: %w", err)
+	}
+	vals, ok := res.([]any)
+	if !ok || len(vals) != 2 {
+		return false, 0, fmt.Errorf("unexpected token bucket script result %v", res)
+	}
+	allowed, ok1 := vals[0].(int64)
+	wait, ok2 := vals[1].(int64)
+	if !ok1 || !ok2 {
+		return false, 0, fmt.Errorf("unexpected token bucket script result %v", res)
+	}
+	return allowed == 1, time.Duration(wait) * time.Millisecond, nil
+}
@@ -0,0 +1,213 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+)
+
+// BundleFormatVersion is the version of the bundle format written by
+// [Export].
+const BundleFormatVersion = 1
+
+// Bundle is a self-contained copy of a session, with its events, its state
+// and the artifacts it saved. It can be serialized to JSON, e.g. to move the
+// session to another backend with [Import], attach it to a bug report or
+// turn it into an evaluation case.
+type Bundle struct {
+	// Version is the format version of the bundle.
+	Version int `json:"version"`
+	// ExportTime is the time the bundle was created.
+	ExportTime time.Time `json:"exportTime"`
+	AppName    string    `json:"appName"`
+	UserID     string    `json:"userId"`
+	SessionID  string    `json:"sessionId"`
+	// State is the session state. App, user and temporary state keys are
+	// not included.
+	State map[string]any `json:"state,omitempty"`
+	// Events are the events of the session in chronological order.
+	Events []*Event `json:"events"`
+	// Artifacts are all the versions of the session artifacts. User
+	// artifacts, shared by the sessions of the user, are not included.
+	Artifacts []*BundleArtifact `json:"artifacts,omitempty"`
+}
+
+// BundleArtifact is a version of an artifact in a Bundle.
+type BundleArtifact struct {
+	Name    string      `json:"name"`
+	Version int64       `json:"version"`
+	Part    *genai.Part `json:"part"`
+}
+
+// ExportRequest represents a request to export a session.
+type ExportRequest struct {
+	AppName   string
+	UserID    string
+	SessionID string
+	// Artifacts is the artifact service the session artifacts are read from.
+	// Optional: if nil, the bundle has no artifacts.
+	Artifacts artifact.Service
+}
+
+// Export returns a bundle of the session stored in service.
+func Export(ctx context.Context, service Service, req *ExportRequest) (*Bundle, error) {
+	resp, err := service.Get(ctx, &GetRequest{AppName: req.AppName, UserID: req.UserID, SessionID: req.SessionID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	s := resp.Session
+	bundle := &Bundle{
+		Version:    BundleFormatVersion,
+		ExportTime: time.Now(),
+		AppName:    s.AppName(),
+		UserID:     s.UserID(),
+		SessionID:  s.ID(),
+		State:      sessionScoped(s.State().All()),
+		Events:     slices.Collect(s.Events().All()),
+	}
+	if req.Artifacts != nil {
+		if bundle.Artifacts, err = exportArtifacts(ctx, req.Artifacts, s); err != nil {
+			return nil, err
+		}
+	}
+	return bundle, nil
+}
+
+func exportArtifacts(ctx context.Context, service artifact.Service, s Session) ([]*BundleArtifact, error) {
+	list, err := service.List(ctx, &artifact.ListRequest{AppName: s.AppName(), UserID: s.UserID(), SessionID: s.ID()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list artifacts: %w", err)
+	}
+	var artifacts []*BundleArtifact
+	for _, name := range slices.Sorted(slices.Values(list.FileNames)) {
+		if strings.HasPrefix(name, KeyPrefixUser) {
+			continue
+		}
+		versions, err := service.Versions(ctx, &artifact.VersionsRequest{AppName: s.AppName(), UserID: s.UserID(), SessionID: s.ID(), FileName: name})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the versions of artifact %q: %w", name, err)
+		}
+		for _, version := range slices.Sorted(slices.Values(versions.Versions)) {
+			loaded, err := service.Load(ctx, &artifact.LoadRequest{AppName: s.AppName(), UserID: s.UserID(), SessionID: s.ID(), FileName: name, Version: version})
+			if err != nil {
+				return nil, fmt.Errorf("failed to load artifact %q version %d: %w", name, version, err)
+			}
+			artifacts = append(artifacts, &BundleArtifact{Name: name, Version: version, Part: loaded.Part})
+		}
+	}
+	return artifacts, nil
+}
+
+// ImportRequest represents a request to import a bundle as a new session.
+type ImportRequest struct {
+	Bundle *Bundle
+	// AppName, UserID and SessionID identify the created session.
+	// Optional: the values of the bundle are used for the empty ones.
+	AppName   string
+	UserID    string
+	SessionID string
+	// Artifacts is the artifact service the bundle artifacts are saved to.
+	// Optional: if nil, the artifacts are not imported.
+	Artifacts artifact.Service
+}
+
+// Import creates a session in service from a bundle made by [Export], and
+// saves its artifacts. The events keep their IDs and timestamps. If the
+// import fails, the created session and the saved artifacts are deleted, so
+// that the import can be retried.
+func Import(ctx context.Context, service Service, req *ImportRequest) (Session, error) {
+	b := req.Bundle
+	if b == nil {
+		return nil, errors.New("bundle is required")
+	}
+	if b.Version != BundleFormatVersion {
+		return nil, fmt.Errorf("unsupported bundle version %d", b.Version)
+	}
+	appName, userID, sessionID := cmp.Or(req.AppName, b.AppName), cmp.Or(req.UserID, b.UserID), cmp.Or(req.SessionID, b.SessionID)
+
+	resp, err := service.Create(ctx, &CreateRequest{AppName: appName, UserID: userID, SessionID: sessionID, State: sessionScoped(maps.All(b.State))})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	s := resp.Session
+	var saved []*BundleArtifact
+	rollback := func(err error) error {
+		// The cleanup also runs when ctx is cancelled.
+		ctx := context.WithoutCancel(ctx)
+		for _, a := range saved {
+			if delErr := req.Artifacts.Delete(ctx, &artifact.DeleteRequest{
+				AppName: appName, UserID: userID, SessionID: s.ID(), FileName: a.Name, Version: a.Version,
+			}); delErr != nil {
+				err = errors.Join(err, fmt.Errorf("failed to delete imported artifact %q version %d: %w", a.Name, a.Version, delErr))
+			}
+		}
+		if delErr := service.Delete(ctx, &DeleteRequest{AppName: appName, UserID: userID, SessionID: s.ID()}); delErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to delete imported session: %w", delErr))
+		}
+		return err
+	}
+	for _, event := range b.Events {
+		if event == nil {
+			continue
+		}
+		imported := *event
+		// The app and user state are not part of the bundle, their deltas
+		// would overwrite the state of the target.
+		imported.Actions.StateDelta = sessionScoped(maps.All(event.Actions.StateDelta))
+		imported.Actions.ArtifactDelta = maps.Clone(event.Actions.ArtifactDelta)
+		if err := service.AppendEvent(ctx, s, &imported); err != nil {
+			return nil, rollback(fmt.Errorf("failed to add event to session: %w", err))
+		}
+	}
+	if req.Artifacts != nil {
+		for _, a := range b.Artifacts {
+			if a == nil || strings.HasPrefix(a.Name, KeyPrefixUser) {
+				continue
+			}
+			resp, err := req.Artifacts.Save(ctx, &artifact.SaveRequest{
+				AppName: appName, UserID: userID, SessionID: s.ID(), FileName: a.Name, Part: a.Part, Version: a.Version,
+			})
+			if err != nil {
+				return nil, rollback(fmt.Errorf("failed to save artifact %q version %d: %w", a.Name, a.Version, err))
+			}
+			saved = append(saved, &BundleArtifact{Name: a.Name, Version: resp.Version})
+		}
+	}
+	return s, nil
+}
+
+// sessionScoped returns the entries of state whose keys are not app, user or
+// temporary state keys.
+func sessionScoped(state iter.Seq2[string, any]) map[string]any {
+	res := map[string]any{}
+	for k, v := range state {
+		if !strings.HasPrefix(k, KeyPrefixApp) && !strings.HasPrefix(k, KeyPrefixUser) && !strings.HasPrefix(k, KeyPrefixTemp) {
+			res[k] = v
+		}
+	}
+	return res
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/model"
+)
+
+func TestExportImport(t *testing.T) {
+	ctx := t.Context()
+	sessions, artifacts := InMemoryService(), artifact.InMemoryService()
+	created, err := sessions.Create(ctx, &CreateRequest{
+		AppName: "app", UserID: "user", SessionID: "s1",
+		State: map[string]any{"topic": "go", "user:lang": "en"},
+	})
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	events := []*Event{
+		{
+			ID: "e1", InvocationID: "i1", Author: "user", Timestamp: ts,
+			LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("hi", genai.RoleUser)},
+			Actions:     EventActions{StateDelta: map[string]any{}},
+		},
+		{
+			ID: "e2", InvocationID: "i1", Author: "agent", Timestamp: ts.Add(time.Second),
+			LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("hello", genai.RoleModel)},
+			Actions: EventActions{
+				StateDelta:    map[string]any{"greeted": true, "app:count": 1.0},
+				ArtifactDelta: map[string]int64{"notes.txt": 2},
+			},
+		},
+	}
+	for _, e := range events {
+		if err := sessions.AppendEvent(ctx, created.Session, e); err != nil {
+			t.Fatalf("AppendEvent() failed: %v", err)
+		}
+	}
+	for _, req := range []*artifact.SaveRequest{
+		{FileName: "notes.txt", Part: genai.NewPartFromText("v1")},
+		{FileName: "notes.txt", Part: genai.NewPartFromText("v2")},
+		{FileName: "image.png", Part: genai.NewPartFromBytes([]byte{1, 2, 3}, "image/png")},
+		{FileName: "user:profile.txt", Part: genai.NewPartFromText("shared")},
+	} {
+		req.AppName, req.UserID, req.SessionID = "app", "user", "s1"
+		if _, err := artifacts.Save(ctx, req); err != nil {
+			t.Fatalf("Save() failed: %v", err)
+		}
+	}
+
+	bundle, err := Export(ctx, sessions, &ExportRequest{AppName: "app", UserID: "user", SessionID: "s1", Artifacts: artifacts})
+	if err != nil {
+		t.Fatalf("Export() failed: %v", err)
+	}
+	wantState := map[string]any{"topic": "go", "greeted": true}
+	if diff := cmp.Diff(wantState, bundle.State); diff != "" {
+		t.Errorf("bundle state mismatch (-want +got):\n%s", diff)
+	}
+	wantArtifacts := []*BundleArtifact{
+		{Name: "image.png", Version: 1, Part: genai.NewPartFromBytes([]byte{1, 2, 3}, "image/png")},
+		{Name: "notes.txt", Version: 1, Part: genai.NewPartFromText("v1")},
+		{Name: "notes.txt", Version: 2, Part: genai.NewPartFromText("v2")},
+	}
+	if diff := cmp.Diff(wantArtifacts, bundle.Artifacts); diff != "" {
+		t.Errorf("bundle artifacts mismatch (-want +got):\n%s", diff)
+	}
+
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		t.Fatalf("json.Marshal() failed: %v", err)
+	}
+	var decoded Bundle
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() failed: %v", err)
+	}
+
+	targetSessions, targetArtifacts := InMemoryService(), artifact.InMemoryService()
+	imported, err := Import(ctx, targetSessions, &ImportRequest{Bundle: &decoded, UserID: "other", Artifacts: targetArtifacts})
+	if err != nil {
+		t.Fatalf("Import() failed: %v", err)
+	}
+	if imported.AppName() != "app" || imported.UserID() != "other" || imported.ID() != "s1" {
+		t.Errorf("Import() session = %s/%s/%s, want app/other/s1", imported.AppName(), imported.UserID(), imported.ID())
+	}
+	got, err := targetSessions.Get(ctx, &GetRequest{AppName: "app", UserID: "other", SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	gotState := map[string]any{}
+	for k, v := range got.Session.State().All() {
+		gotState[k] = v
+	}
+	if diff := cmp.Diff(wantState, gotState); diff != "" {
+		t.Errorf("imported state mismatch (-want +got):\n%s", diff)
+	}
+	var gotIDs []string
+	for e := range got.Session.Events().All() {
+		gotIDs = append(gotIDs, e.ID)
+	}
+	if diff := cmp.Diff([]string{"e1", "e2"}, gotIDs); diff != "" {
+		t.Errorf("imported event IDs mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(events[1].Content, got.Session.Events().At(1).Content); diff != "" {
+		t.Errorf("imported event content mismatch (-want +got):\n%s", diff)
+	}
+	loaded, err := targetArtifacts.Load(ctx, &artifact.LoadRequest{AppName: "app", UserID: "other", SessionID: "s1", FileName: "notes.txt", Version: 1})
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if diff := cmp.Diff(genai.NewPartFromText("v1"), loaded.Part); diff != "" {
+		t.Errorf("imported artifact mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := targetArtifacts.Load(ctx, &artifact.LoadRequest{AppName: "app", UserID: "other", SessionID: "s1", FileName: "user:profile.txt"}); err == nil {
+		t.Error("user artifact was imported, want it skipped")
+	}
+}
+
+func TestImport_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		req  *ImportRequest
+	}{
+		{name: "no bundle", req: &ImportRequest{}},
+		{name: "unknown version", req: &ImportRequest{Bundle: &Bundle{Version: 99, AppName: "app", UserID: "user"}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := Import(t.Context(), InMemoryService(), tc.req); err == nil {
+				t.Error("Import() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestImport_Rollback(t *testing.T) {
+	ctx := t.Context()
+	service := InMemoryService()
+	artifacts := artifact.InMemoryService()
+	event := NewEvent("inv")
+	event.Author = "user"
+	bundle := &Bundle{
+		Version: BundleFormatVersion,
+		AppName: "app",
+		UserID:  "user",
+		Events:  []*Event{event},
+		Artifacts: []*BundleArtifact{
+			{Name: "report.txt", Version: 1, Part: genai.NewPartFromText("report")},
+			{Name: "../secret.txt", Version: 1, Part: genai.NewPartFromText("secret")},
+		},
+	}
+
+	if _, err := Import(ctx, service, &ImportRequest{Bundle: bundle, SessionID: "imported", Artifacts: artifacts}); err == nil {
+		t.Fatal("Import() succeeded, want error")
+	}
+	if _, err := service.Get(ctx, &GetRequest{AppName: "app", UserID: "user", SessionID: "imported"}); err == nil {
+		t.Error("Get() of the failed import succeeded, want the session deleted")
+	}
+	list, err := artifacts.List(ctx, &artifact.ListRequest{AppName: "app", UserID: "user", SessionID: "imported"})
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	if len(list.FileNames) != 0 {
+		t.Errorf("artifacts after the failed import = %v, want none", list.FileNames)
+	}
+}
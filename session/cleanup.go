@@ -0,0 +1,49 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/adk/internal/logging"
+)
+
+// RunCleanup deletes the sessions of service that were not updated for ttl,
+// see [Service.Cleanup]. It runs a first cleanup immediately and then one
+// every interval, until ctx is done. Errors are logged and the next cleanup
+// is attempted at the following interval.
+//
+// It is meant to be started in its own goroutine by long-running servers.
+func RunCleanup(ctx context.Context, service Service, ttl, interval time.Duration) {
+	if ttl <= 0 || interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		deleted, err := service.Cleanup(ctx, time.Now().Add(-ttl))
+		if err != nil {
+			logging.FromContext(ctx).Warn("session cleanup failed", "error", err)
+		} else if deleted > 0 {
+			logging.FromContext(ctx).Info("session cleanup deleted expired sessions", "count", deleted, "ttl", ttl)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRunCleanup(t *testing.T) {
+	ctx := t.Context()
+	service := InMemoryService()
+	created, err := service.Create(ctx, &CreateRequest{AppName: "app", UserID: "user", SessionID: "old"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := &Event{ID: "e1", Author: "user", Timestamp: time.Now().Add(-2 * time.Hour)}
+	if err := service.AppendEvent(ctx, created.Session, event); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first cleanup runs before waiting for ctx.
+	cleanupCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	RunCleanup(cleanupCtx, service, time.Hour, time.Hour)
+
+	if _, err := service.Get(ctx, &GetRequest{AppName: "app", UserID: "user", SessionID: "old"}); err == nil {
+		t.Errorf("Get() succeeded after RunCleanup(), want the session to be deleted")
+	}
+}
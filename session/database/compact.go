@@ -0,0 +1,97 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"google.golang.org/adk/session"
+)
+
+// CompactRequest selects the events removed by [CompactEvents].
+type CompactRequest struct {
+	// Before removes the events with a timestamp strictly before it.
+	Before time.Time
+	// KeepRecent is the number of most recent events of each session that
+	// are kept regardless of their timestamp.
+	KeepRecent int
+}
+
+// CompactEvents deletes old events of all the sessions to bound the size of
+// the database. The state of the sessions is not modified, since state
+// deltas are already applied when events are appended.
+//
+// It returns the number of deleted events.
+//
+// NOTE: This function relies on a type assertion to the concrete *databaseService
+// implementation. It will return an error if the provided session.Service is
+// a different implementation.
+func CompactEvents(ctx context.Context, service session.Service, req CompactRequest) (int64, error) {
+	dbservice, ok := service.(*databaseService)
+	if !ok {
+		return 0, fmt.Errorf("invalid session service type")
+	}
+	if req.Before.IsZero() {
+		return 0, fmt.Errorf("before is required")
+	}
+	if req.KeepRecent < 0 {
+		return 0, fmt.Errorf("invalid KeepRecent %d", req.KeepRecent)
+	}
+
+	var deleted int64
+	err := dbservice.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var sessions []storageSession
+		if err := tx.Select("app_name", "user_id", "id").Find(&sessions).Error; err != nil {
+			return fmt.Errorf("failed to list sessions: %w", err)
+		}
+		for _, s := range sessions {
+			before := req.Before
+			if req.KeepRecent > 0 {
+				// Events at or after the oldest kept event are not deleted.
+				var kept []storageEvent
+				err := tx.Select("timestamp").
+					Where("app_name = ? AND user_id = ? AND session_id = ?", s.AppName, s.UserID, s.ID).
+					Order("timestamp DESC").
+					Limit(req.KeepRecent).
+					Find(&kept).Error
+				if err != nil {
+					return fmt.Errorf("failed to fetch recent events: %w", err)
+				}
+				if len(kept) == req.KeepRecent && kept[len(kept)-1].Timestamp.Before(before) {
+					before = kept[len(kept)-1].Timestamp
+				} else if len(kept) < req.KeepRecent {
+					continue
+				}
+			}
+			result := tx.
+				Where("app_name = ? AND user_id = ? AND session_id = ?", s.AppName, s.UserID, s.ID).
+				Where("timestamp < ?", before).
+				Delete(&storageEvent{})
+			if result.Error != nil {
+				return fmt.Errorf("failed to delete events: %w", result.Error)
+			}
+			deleted += result.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	"google.golang.org/adk/internal/sessionutils"
 	"google.golang.org/adk/session"
 )
 
@@ -218,6 +219,66 @@ func (s *databaseService) Get(ctx context.Context, req *session.GetRequest) (*se
 	}, nil
 }
 
+// ListEvents returns a page of the events of a session, implements session.Service
+func (s *databaseService) ListEvents(ctx context.Context, req *session.ListEventsRequest) (*session.ListEventsResponse, error) {
+	appName, userID, sessionID := req.AppName, req.UserID, req.SessionID
+	if appName == "" || userID == "" || sessionID == "" {
+		return nil, fmt.Errorf("app_name, user_id, session_id are required, got app_name: %q, user_id: %q, session_id: %q", appName, userID, sessionID)
+	}
+	offset, err := sessionutils.DecodePageToken(req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int64
+	err = s.db.WithContext(ctx).
+		Model(&storageSession{}).
+		Where(&storageSession{AppName: appName, UserID: userID, ID: sessionID}).
+		Count(&count).Error
+	if err != nil {
+		return nil, fmt.Errorf("database error while fetching session: %w", err)
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("session %q not found", sessionID)
+	}
+
+	eventQuery := s.db.WithContext(ctx).
+		Model(&storageEvent{}).
+		Where("app_name = ?", appName).
+		Where("user_id = ?", userID).
+		Where("session_id = ?", sessionID)
+	if !req.After.IsZero() {
+		eventQuery = eventQuery.Where("timestamp >= ?", req.After)
+	}
+	if len(req.Authors) > 0 {
+		eventQuery = eventQuery.Where("author IN ?", req.Authors)
+	}
+	eventQuery = eventQuery.Order("timestamp ASC").Order("id ASC").Offset(offset)
+	if req.PageSize > 0 {
+		// Fetch one more event to know whether there is a next page.
+		eventQuery = eventQuery.Limit(req.PageSize + 1)
+	}
+
+	var storageEvents []storageEvent
+	if err := eventQuery.Find(&storageEvents).Error; err != nil {
+		return nil, fmt.Errorf("database error while fetching events: %w", err)
+	}
+
+	resp := &session.ListEventsResponse{Events: make([]*session.Event, 0, len(storageEvents))}
+	if req.PageSize > 0 && len(storageEvents) > req.PageSize {
+		storageEvents = storageEvents[:req.PageSize]
+		resp.NextPageToken = sessionutils.EncodePageToken(offset + req.PageSize)
+	}
+	for i := range storageEvents {
+		evt, err := createEventFromStorageEvent(&storageEvents[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to map storage event: %w", err)
+		}
+		resp.Events = append(resp.Events, evt)
+	}
+	return resp, nil
+}
+
 // List retrieves sessions from the database using its appName and optional UserID
 func (s *databaseService) List(ctx context.Context, req *session.ListRequest) (*session.ListResponse, error) {
 	appName, userID := req.AppName, req.UserID
@@ -300,6 +361,14 @@ func (s *databaseService) Delete(ctx context.Context, req *session.DeleteRequest
 	}
 
 	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Events are deleted first, so that the foreign key is satisfied
+		// on databases that enforce it.
+		err := tx.Where("app_name = ? AND user_id = ? AND session_id = ?", appName, userID, sessionID).
+			Delete(&storageEvent{}).Error
+		if err != nil {
+			return fmt.Errorf("database error during events deletion: %w", err)
+		}
+
 		target := &storageSession{}
 
 		result := tx.Where(&storageSession{
@@ -316,6 +385,37 @@ func (s *databaseService) Delete(ctx context.Context, req *session.DeleteRequest
 	})
 }
 
+func (s *databaseService) Cleanup(ctx context.Context, olderThan time.Time) (int, error) {
+	var deleted int
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var expired []storageSession
+		err := tx.Select("app_name", "user_id", "id").
+			Where("update_time < ?", olderThan).
+			Find(&expired).Error
+		if err != nil {
+			return fmt.Errorf("failed to list expired sessions: %w", err)
+		}
+		for _, es := range expired {
+			err := tx.Where("app_name = ? AND user_id = ? AND session_id = ?", es.AppName, es.UserID, es.ID).
+				Delete(&storageEvent{}).Error
+			if err != nil {
+				return fmt.Errorf("failed to delete events: %w", err)
+			}
+			err = tx.Where(&storageSession{AppName: es.AppName, UserID: es.UserID, ID: es.ID}).
+				Delete(&storageSession{}).Error
+			if err != nil {
+				return fmt.Errorf("failed to delete session: %w", err)
+			}
+		}
+		deleted = len(expired)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
 func (s *databaseService) AppendEvent(ctx context.Context, curSession session.Session, event *session.Event) error {
 	if curSession == nil {
 		return fmt.Errorf("session is nil")
@@ -884,6 +884,84 @@ func Test_databaseService_StateManagement(t *testing.T) {
 	})
 }
 
+func Test_databaseService_ListEvents(t *testing.T) {
+	ctx := t.Context()
+	service := emptyService(t)
+	resp, err := service.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, author := range []string{"user", "agent", "user", "agent", "user"} {
+		event := &session.Event{
+			ID:        "e" + strconv.Itoa(i),
+			Author:    author,
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+		}
+		if err := service.AppendEvent(ctx, resp.Session, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		req  session.ListEventsRequest
+		want [][]string
+	}{
+		{
+			name: "all",
+			want: [][]string{{"e0", "e1", "e2", "e3", "e4"}},
+		},
+		{
+			name: "pages",
+			req:  session.ListEventsRequest{PageSize: 2},
+			want: [][]string{{"e0", "e1"}, {"e2", "e3"}, {"e4"}},
+		},
+		{
+			name: "author",
+			req:  session.ListEventsRequest{PageSize: 2, Authors: []string{"user"}},
+			want: [][]string{{"e0", "e2"}, {"e4"}},
+		},
+		{
+			name: "after",
+			req:  session.ListEventsRequest{PageSize: 2, After: start.Add(3 * time.Second)},
+			want: [][]string{{"e3", "e4"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			req.AppName, req.UserID, req.SessionID = "app", "user", "session"
+			var got [][]string
+			for {
+				resp, err := service.ListEvents(ctx, &req)
+				if err != nil {
+					t.Fatalf("ListEvents() error = %v", err)
+				}
+				var ids []string
+				for _, event := range resp.Events {
+					ids = append(ids, event.ID)
+				}
+				got = append(got, ids)
+				if resp.NextPageToken == "" {
+					break
+				}
+				req.PageToken = resp.NextPageToken
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ListEvents() pages mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := service.ListEvents(ctx, &session.ListEventsRequest{AppName: "app", UserID: "user", SessionID: "missing"}); err == nil {
+		t.Errorf("ListEvents() for a missing session succeeded, want error")
+	}
+	if _, err := service.ListEvents(ctx, &session.ListEventsRequest{AppName: "app", UserID: "user", SessionID: "session", PageToken: "!"}); err == nil {
+		t.Errorf("ListEvents() with an invalid page token succeeded, want error")
+	}
+}
+
 func serviceDbWithData(t *testing.T) *databaseService {
 	t.Helper()
 
@@ -1015,3 +1093,48 @@ func emptyService(t *testing.T) *databaseService {
 	}
 	return dbservice
 }
+
+func Test_databaseService_Cleanup(t *testing.T) {
+	ctx := t.Context()
+	service := emptyService(t)
+	for _, id := range []string{"old", "new"} {
+		if _, err := service.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old, err := service.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "old"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := &session.Event{ID: "e1", Author: "user", Timestamp: time.Now().Add(-2 * time.Hour)}
+	if err := service.AppendEvent(ctx, old.Session, event); err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, err := service.Cleanup(ctx, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Cleanup() error = %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("Cleanup() = %d, want 1", deleted)
+	}
+
+	resp, err := service.List(ctx, &session.ListRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, s := range resp.Sessions {
+		got = append(got, s.ID())
+	}
+	if diff := cmp.Diff([]string{"new"}, got); diff != "" {
+		t.Errorf("List() after Cleanup() mismatch (-want +got):\n%s", diff)
+	}
+	var events int64
+	if err := service.db.Model(&storageEvent{}).Count(&events).Error; err != nil {
+		t.Fatal(err)
+	}
+	if events != 0 {
+		t.Errorf("got %d events after Cleanup(), want 0", events)
+	}
+}
@@ -17,6 +17,7 @@ package database
 import (
 	"fmt"
 	"iter"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -108,6 +109,45 @@ func (e events) At(i int) *session.Event {
 	return nil
 }
 
+// ReverseAll implements [session.EventQuerier].
+func (e events) ReverseAll() iter.Seq[*session.Event] {
+	return func(yield func(*session.Event) bool) {
+		for i := len(e) - 1; i >= 0; i-- {
+			if !yield(e[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Filter implements [session.EventQuerier]. The events are in chronological
+// order, so the ones before f.After are skipped with a binary search.
+func (e events) Filter(f session.EventFilter) iter.Seq[*session.Event] {
+	return func(yield func(*session.Event) bool) {
+		start := 0
+		if !f.After.IsZero() {
+			start = sort.Search(len(e), func(i int) bool {
+				return !e[i].Timestamp.Before(f.After)
+			})
+		}
+		for _, event := range e[start:] {
+			if f.Match(event) && !yield(event) {
+				return
+			}
+		}
+	}
+}
+
+// LastBy implements [session.EventQuerier].
+func (e events) LastBy(author string) *session.Event {
+	for i := len(e) - 1; i >= 0; i-- {
+		if e[i].Author == author {
+			return e[i]
+		}
+	}
+	return nil
+}
+
 type state struct {
 	mu    *sync.RWMutex
 	state map[string]any
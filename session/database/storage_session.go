@@ -83,11 +83,13 @@ type storageEvent struct {
 	Timestamp              time.Time
 
 	// Fields from llm_response
-	Content           dynamicJSON
-	GroundingMetadata dynamicJSON
-	CustomMetadata    dynamicJSON
-	UsageMetadata     dynamicJSON
-	CitationMetadata  dynamicJSON
+	Content             dynamicJSON
+	GroundingMetadata   dynamicJSON
+	CustomMetadata      dynamicJSON
+	UsageMetadata       dynamicJSON
+	CitationMetadata    dynamicJSON
+	InputTranscription  dynamicJSON
+	OutputTranscription dynamicJSON
 
 	Partial      *bool
 	TurnComplete *bool
@@ -183,6 +185,18 @@ func createStorageEvent(session session.Session, event *session.Event) (*storage
 			return nil, fmt.Errorf("failed to marshal citation metadata: %w", err)
 		}
 	}
+	if event.InputTranscription != nil {
+		storageEv.InputTranscription, err = json.Marshal(event.InputTranscription)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal input transcription: %w", err)
+		}
+	}
+	if event.OutputTranscription != nil {
+		storageEv.OutputTranscription, err = json.Marshal(event.OutputTranscription)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal output transcription: %w", err)
+		}
+	}
 
 	return storageEv, nil
 }
@@ -242,6 +256,18 @@ func createEventFromStorageEvent(se *storageEvent) (*session.Event, error) {
 		}
 	}
 
+	var inputTranscription, outputTranscription *genai.Transcription
+	if len(se.InputTranscription) > 0 {
+		if err := json.Unmarshal(se.InputTranscription, &inputTranscription); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal input transcription: %w", err)
+		}
+	}
+	if len(se.OutputTranscription) > 0 {
+		if err := json.Unmarshal(se.OutputTranscription, &outputTranscription); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal output transcription: %w", err)
+		}
+	}
+
 	// --- Handle JSON-encoded *string field ---
 	var toolIDs []string
 	if se.LongRunningToolIDsJSON != nil {
@@ -269,16 +295,18 @@ func createEventFromStorageEvent(se *storageEvent) (*session.Event, error) {
 		LongRunningToolIDs: toolIDs,
 		Branch:             branch,
 		LLMResponse: model.LLMResponse{
-			Content:           content,
-			GroundingMetadata: groundingMetadata,
-			CustomMetadata:    customMetadata,
-			UsageMetadata:     usageMetadata,
-			CitationMetadata:  citationMetadata,
-			ErrorCode:         errorCode,
-			ErrorMessage:      errorMessage,
-			Partial:           partial,
-			TurnComplete:      turnComplete,
-			Interrupted:       interrupted,
+			Content:             content,
+			GroundingMetadata:   groundingMetadata,
+			CustomMetadata:      customMetadata,
+			UsageMetadata:       usageMetadata,
+			CitationMetadata:    citationMetadata,
+			ErrorCode:           errorCode,
+			ErrorMessage:        errorMessage,
+			Partial:             partial,
+			TurnComplete:        turnComplete,
+			Interrupted:         interrupted,
+			InputTranscription:  inputTranscription,
+			OutputTranscription: outputTranscription,
 		},
 	}
 
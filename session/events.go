@@ -0,0 +1,97 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"iter"
+	"time"
+)
+
+// EventFilter selects the events of a session. The zero value selects all
+// the events.
+type EventFilter struct {
+	// Author, if set, selects the events of the given author.
+	Author string
+	// After, if set, selects the events with timestamp >= After.
+	After time.Time
+}
+
+// Match reports whether event is selected by f.
+func (f EventFilter) Match(event *Event) bool {
+	if event == nil {
+		return false
+	}
+	if f.Author != "" && event.Author != f.Author {
+		return false
+	}
+	return f.After.IsZero() || !event.Timestamp.Before(f.After)
+}
+
+// EventQuerier is implemented by the [Events] that can answer the queries of
+// [ReverseAll], [Filter] and [LastBy] more efficiently than by walking the
+// events with Len and At, e.g. with an indexed lookup.
+type EventQuerier interface {
+	// ReverseAll returns an iterator that yields the events from the most
+	// recent to the oldest.
+	ReverseAll() iter.Seq[*Event]
+	// Filter returns an iterator that yields the events selected by f, in
+	// order.
+	Filter(f EventFilter) iter.Seq[*Event]
+	// LastBy returns the most recent event of author, or nil if there is
+	// none.
+	LastBy(author string) *Event
+}
+
+// ReverseAll returns an iterator that yields the events from the most recent
+// to the oldest.
+func ReverseAll(events Events) iter.Seq[*Event] {
+	if q, ok := events.(EventQuerier); ok {
+		return q.ReverseAll()
+	}
+	return func(yield func(*Event) bool) {
+		for i := events.Len() - 1; i >= 0; i-- {
+			if !yield(events.At(i)) {
+				return
+			}
+		}
+	}
+}
+
+// Filter returns an iterator that yields the events selected by f, in order.
+func Filter(events Events, f EventFilter) iter.Seq[*Event] {
+	if q, ok := events.(EventQuerier); ok {
+		return q.Filter(f)
+	}
+	return func(yield func(*Event) bool) {
+		for event := range events.All() {
+			if f.Match(event) && !yield(event) {
+				return
+			}
+		}
+	}
+}
+
+// LastBy returns the most recent event of author, or nil if there is none.
+func LastBy(events Events, author string) *Event {
+	if q, ok := events.(EventQuerier); ok {
+		return q.LastBy(author)
+	}
+	for event := range ReverseAll(events) {
+		if event != nil && event.Author == author {
+			return event
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// plainEvents hides the EventQuerier implementation of the wrapped events.
+type plainEvents struct {
+	Events
+}
+
+func eventIDs(events []*Event) []string {
+	ids := make([]string, len(events))
+	for i, e := range events {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+func TestEventQueries(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	stored := events{
+		{ID: "1", Author: "user", Timestamp: start},
+		{ID: "2", Author: "agent", Timestamp: start.Add(time.Second)},
+		{ID: "3", Author: "user", Timestamp: start.Add(2 * time.Second)},
+		{ID: "4", Author: "tool_agent", Timestamp: start.Add(3 * time.Second)},
+		{ID: "5", Author: "agent", Timestamp: start.Add(4 * time.Second)},
+	}
+
+	for _, tc := range []struct {
+		name   string
+		events Events
+	}{
+		{name: "querier", events: stored},
+		{name: "fallback", events: plainEvents{stored}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := cmp.Diff([]string{"5", "4", "3", "2", "1"}, eventIDs(slices.Collect(ReverseAll(tc.events)))); diff != "" {
+				t.Errorf("ReverseAll() mismatch (-want +got):\n%s", diff)
+			}
+
+			for _, f := range []struct {
+				filter EventFilter
+				want   []string
+			}{
+				{filter: EventFilter{}, want: []string{"1", "2", "3", "4", "5"}},
+				{filter: EventFilter{Author: "agent"}, want: []string{"2", "5"}},
+				{filter: EventFilter{After: start.Add(2 * time.Second)}, want: []string{"3", "4", "5"}},
+				{filter: EventFilter{Author: "user", After: start.Add(time.Second)}, want: []string{"3"}},
+				{filter: EventFilter{After: start.Add(time.Minute)}, want: []string{}},
+			} {
+				if diff := cmp.Diff(f.want, eventIDs(slices.Collect(Filter(tc.events, f.filter)))); diff != "" {
+					t.Errorf("Filter(%+v) mismatch (-want +got):\n%s", f.filter, diff)
+				}
+			}
+
+			if got := LastBy(tc.events, "user"); got == nil || got.ID != "3" {
+				t.Errorf("LastBy(user) = %v, want event 3", got)
+			}
+			if got := LastBy(tc.events, "unknown"); got != nil {
+				t.Errorf("LastBy(unknown) = %v, want nil", got)
+			}
+		})
+	}
+}
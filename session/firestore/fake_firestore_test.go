@@ -0,0 +1,238 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firestore
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	firestoreapi "google.golang.org/api/firestore/v1"
+)
+
+// fakeFirestore implements the subset of the Firestore REST API used by the
+// service.
+type fakeFirestore struct {
+	mu   sync.Mutex
+	docs map[string]*firestoreapi.Document
+	// clock is advanced on every commit, so that update times are distinct.
+	clock time.Time
+}
+
+func newFakeFirestore() *fakeFirestore {
+	return &fakeFirestore{
+		docs:  map[string]*firestoreapi.Document{},
+		clock: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func (f *fakeFirestore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/v1/")
+	switch {
+	case r.Method == http.MethodGet:
+		doc, ok := f.docs[path]
+		if !ok {
+			writeError(w, http.StatusNotFound, "NOT_FOUND")
+			return
+		}
+		writeJSON(w, doc)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, ":commit"):
+		var req firestoreapi.CommitRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		f.commit(w, &req)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, ":runQuery"):
+		var req firestoreapi.RunQueryRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		f.runQuery(w, strings.TrimSuffix(path, ":runQuery"), req.StructuredQuery)
+	default:
+		writeError(w, http.StatusNotImplemented, "UNIMPLEMENTED")
+	}
+}
+
+func (f *fakeFirestore) commit(w http.ResponseWriter, req *firestoreapi.CommitRequest) {
+	for _, write := range req.Writes {
+		name := write.Delete
+		if write.Update != nil {
+			name = write.Update.Name
+		}
+		doc, exists := f.docs[name]
+		if pre := write.CurrentDocument; pre != nil {
+			if pre.UpdateTime != "" && (!exists || doc.UpdateTime != pre.UpdateTime) {
+				writeError(w, http.StatusBadRequest, "FAILED_PRECONDITION")
+				return
+			}
+			if pre.UpdateTime == "" && !pre.Exists && exists {
+				writeError(w, http.StatusConflict, "ALREADY_EXISTS")
+				return
+			}
+		}
+	}
+
+	f.clock = f.clock.Add(time.Millisecond)
+	now := f.clock.Format(time.RFC3339Nano)
+	resp := &firestoreapi.CommitResponse{CommitTime: now}
+	for _, write := range req.Writes {
+		if write.Delete != "" {
+			delete(f.docs, write.Delete)
+			resp.WriteResults = append(resp.WriteResults, &firestoreapi.WriteResult{UpdateTime: now})
+			continue
+		}
+		doc, ok := f.docs[write.Update.Name]
+		if !ok || write.UpdateMask == nil {
+			doc = &firestoreapi.Document{Name: write.Update.Name, Fields: map[string]firestoreapi.Value{}, CreateTime: now}
+		}
+		if write.UpdateMask == nil {
+			for k, v := range write.Update.Fields {
+				doc.Fields[k] = v
+			}
+		}
+		if write.UpdateMask != nil {
+			for _, path := range write.UpdateMask.FieldPaths {
+				field, key, nested := strings.Cut(path, ".")
+				value := write.Update.Fields[field]
+				if !nested {
+					doc.Fields[field] = value
+					continue
+				}
+				key = strings.Trim(key, "`")
+				m := doc.Fields[field]
+				if m.MapValue == nil {
+					m.MapValue = &firestoreapi.MapValue{}
+				}
+				if m.MapValue.Fields == nil {
+					m.MapValue.Fields = map[string]firestoreapi.Value{}
+				}
+				m.MapValue.Fields[key] = value.MapValue.Fields[key]
+				doc.Fields[field] = m
+			}
+		}
+		result := &firestoreapi.WriteResult{UpdateTime: now}
+		for _, transform := range write.UpdateTransforms {
+			doc.Fields[transform.FieldPath] = firestoreapi.Value{TimestampValue: now}
+			result.TransformResults = append(result.TransformResults, &firestoreapi.Value{TimestampValue: now})
+		}
+		doc.UpdateTime = now
+		f.docs[doc.Name] = doc
+		resp.WriteResults = append(resp.WriteResults, result)
+	}
+	writeJSON(w, resp)
+}
+
+func (f *fakeFirestore) runQuery(w http.ResponseWriter, parent string, query *firestoreapi.StructuredQuery) {
+	collection := query.From[0]
+	var docs []*firestoreapi.Document
+	for name, doc := range f.docs {
+		rest, ok := strings.CutPrefix(name, parent+"/")
+		if !ok {
+			continue
+		}
+		segments := strings.Split(rest, "/")
+		if len(segments) < 2 || segments[len(segments)-2] != collection.CollectionId {
+			continue
+		}
+		if len(segments) != 2 && !collection.AllDescendants {
+			continue
+		}
+		if query.Where != nil && !matches(doc, query.Where) {
+			continue
+		}
+		docs = append(docs, doc)
+	}
+	slices.SortFunc(docs, func(a, b *firestoreapi.Document) int {
+		for _, order := range query.OrderBy {
+			c := compareField(a, b, order.Field.FieldPath)
+			if order.Direction == "DESCENDING" {
+				c = -c
+			}
+			if c != 0 {
+				return c
+			}
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	docs = docs[min(int(query.Offset), len(docs)):]
+	if query.Limit > 0 && len(docs) > int(query.Limit) {
+		docs = docs[:query.Limit]
+	}
+	results := []*firestoreapi.RunQueryResponse{{ReadTime: f.clock.Format(time.RFC3339Nano)}}
+	for _, doc := range docs {
+		results = append(results, &firestoreapi.RunQueryResponse{Document: doc})
+	}
+	writeJSON(w, results)
+}
+
+func matches(doc *firestoreapi.Document, filter *firestoreapi.Filter) bool {
+	if filter.CompositeFilter != nil {
+		for _, f := range filter.CompositeFilter.Filters {
+			if !matches(doc, f) {
+				return false
+			}
+		}
+		return true
+	}
+	ff := filter.FieldFilter
+	value := doc.Fields[ff.Field.FieldPath]
+	switch ff.Op {
+	case "LESS_THAN":
+		return compareValues(value, *ff.Value) < 0
+	case "GREATER_THAN_OR_EQUAL":
+		return compareValues(value, *ff.Value) >= 0
+	case "IN":
+		return slices.ContainsFunc(ff.Value.ArrayValue.Values, func(v *firestoreapi.Value) bool {
+			return compareValues(value, *v) == 0
+		})
+	}
+	panic(fmt.Sprintf("unsupported filter op %q", ff.Op))
+}
+
+func compareField(a, b *firestoreapi.Document, path string) int {
+	if path == "__name__" {
+		return strings.Compare(a.Name, b.Name)
+	}
+	return compareValues(a.Fields[path], b.Fields[path])
+}
+
+func compareValues(a, b firestoreapi.Value) int {
+	if a.TimestampValue != "" || b.TimestampValue != "" {
+		ta, _ := time.Parse(time.RFC3339Nano, a.TimestampValue)
+		tb, _ := time.Parse(time.RFC3339Nano, b.TimestampValue)
+		return ta.Compare(tb)
+	}
+	return strings.Compare(a.StringValue, b.StringValue)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, status string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	fmt.Fprintf(w, `{"error": {"code": %d, "message": %q, "status": %q}}`, code, status, status)
+}
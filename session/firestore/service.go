@@ -0,0 +1,701 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package firestore provides a [session.Service] backed by Cloud Firestore.
+//
+// Sessions are stored as documents named
+// "{collection}/{appName}/users/{userID}/sessions/{sessionID}", and their
+// events in the "events" subcollection of the session document. App and user
+// state are stored in the app and user documents. App names, user IDs and
+// session IDs must be valid Firestore document IDs, in particular they can't
+// contain "/".
+//
+// Listing events filtered by author requires a composite index on the
+// "events" collection group with the fields "author" and "timestamp".
+// [session.Service.Cleanup] requires a single field index on the "updateTime"
+// field of the "sessions" collection group.
+package firestore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	firestoreapi "google.golang.org/api/firestore/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+
+	"google.golang.org/adk/internal/sessionutils"
+	"google.golang.org/adk/session"
+)
+
+const (
+	// DefaultCollection is the root collection used when none is configured.
+	DefaultCollection = "adk_apps"
+	// DefaultDatabase is the Firestore database used when none is configured.
+	DefaultDatabase = "(default)"
+
+	usersCollection    = "users"
+	sessionsCollection = "sessions"
+	eventsCollection   = "events"
+
+	stateField        = "state"
+	updateTimeField   = "updateTime"
+	createTimeField   = "createTime"
+	lastEventIDField  = "lastEventId"
+	eventField        = "event"
+	authorField       = "author"
+	timestampField    = "timestamp"
+	invocationIDField = "invocationId"
+
+	// maxWritesPerCommit is the maximum number of writes in a Firestore commit.
+	maxWritesPerCommit = 500
+)
+
+// Config defines the configuration of a Firestore session service.
+type Config struct {
+	// ProjectID is the Google Cloud project of the Firestore database.
+	ProjectID string
+	// Database is the ID of the Firestore database. If empty,
+	// DefaultDatabase is used.
+	Database string
+	// Collection is the root collection storing the apps. If empty,
+	// DefaultCollection is used.
+	Collection string
+	// ClientOptions are passed to the underlying Firestore client.
+	ClientOptions []option.ClientOption
+}
+
+// firestoreService is a Firestore implementation of session.Service.
+type firestoreService struct {
+	documents  *firestoreapi.ProjectsDatabasesDocumentsService
+	httpClient *http.Client
+	basePath   string
+	// database is "projects/{project}/databases/{database}".
+	database   string
+	collection string
+}
+
+// NewSessionService creates a [session.Service] storing sessions in Cloud
+// Firestore.
+func NewSessionService(ctx context.Context, cfg Config) (session.Service, error) {
+	if cfg.ProjectID == "" {
+		return nil, errors.New("project ID is required")
+	}
+	database := cfg.Database
+	if database == "" {
+		database = DefaultDatabase
+	}
+	collection := cfg.Collection
+	if collection == "" {
+		collection = DefaultCollection
+	}
+	// The generated client can't decode the streamed response of RunQuery,
+	// so queries are sent with the same authenticated HTTP client.
+	opts := append([]option.ClientOption{option.WithScopes(firestoreapi.DatastoreScope)}, cfg.ClientOptions...)
+	httpClient, _, err := htransport.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create firestore client: %w", err)
+	}
+	svc, err := firestoreapi.NewService(ctx, append(slices.Clone(cfg.ClientOptions), option.WithHTTPClient(httpClient))...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create firestore client: %w", err)
+	}
+	return &firestoreService{
+		documents:  svc.Projects.Databases.Documents,
+		httpClient: httpClient,
+		basePath:   svc.BasePath,
+		database:   fmt.Sprintf("projects/%s/databases/%s", cfg.ProjectID, database),
+		collection: collection,
+	}, nil
+}
+
+func (s *firestoreService) appDoc(appName string) string {
+	return fmt.Sprintf("%s/documents/%s/%s", s.database, s.collection, appName)
+}
+
+func (s *firestoreService) userDoc(appName, userID string) string {
+	return fmt.Sprintf("%s/%s/%s", s.appDoc(appName), usersCollection, userID)
+}
+
+func (s *firestoreService) sessionDoc(appName, userID, sessionID string) string {
+	return fmt.Sprintf("%s/%s/%s", s.userDoc(appName, userID), sessionsCollection, sessionID)
+}
+
+func (s *firestoreService) eventDoc(sessionDoc, eventID string) string {
+	return fmt.Sprintf("%s/%s/%s", sessionDoc, eventsCollection, eventID)
+}
+
+// validateIDs checks that the IDs can be used as document IDs.
+func validateIDs(ids ...string) error {
+	for _, id := range ids {
+		if strings.Contains(id, "/") {
+			return fmt.Errorf("invalid ID %q: must not contain '/'", id)
+		}
+	}
+	return nil
+}
+
+// Create creates a session document, implements session.Service
+func (s *firestoreService) Create(ctx context.Context, req *session.CreateRequest) (*session.CreateResponse, error) {
+	if req.AppName == "" || req.UserID == "" {
+		return nil, fmt.Errorf("app_name and user_id are required")
+	}
+	sessionID := req.SessionID
+	if sessionID == "" {
+		sessionID = uuid.NewString()
+	}
+	if err := validateIDs(req.AppName, req.UserID, sessionID); err != nil {
+		return nil, err
+	}
+
+	appDelta, userDelta, sessionState := sessionutils.ExtractStateDeltas(req.State)
+	writes, err := s.stateWrites(req.AppName, req.UserID, appDelta, userDelta)
+	if err != nil {
+		return nil, err
+	}
+	state, err := encodeState(sessionState)
+	if err != nil {
+		return nil, err
+	}
+	sessionWrite := &firestoreapi.Write{
+		Update: &firestoreapi.Document{
+			Name: s.sessionDoc(req.AppName, req.UserID, sessionID),
+			Fields: map[string]firestoreapi.Value{
+				stateField: *state,
+			},
+		},
+		CurrentDocument: &firestoreapi.Precondition{Exists: false, ForceSendFields: []string{"Exists"}},
+		UpdateTransforms: []*firestoreapi.FieldTransform{
+			{FieldPath: createTimeField, SetToServerValue: "REQUEST_TIME"},
+			{FieldPath: updateTimeField, SetToServerValue: "REQUEST_TIME"},
+		},
+	}
+	writes = append(writes, sessionWrite)
+
+	resp, err := s.commit(ctx, writes)
+	if err != nil {
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusConflict {
+			return nil, fmt.Errorf("session %q already exists", sessionID)
+		}
+		return nil, fmt.Errorf("error creating session: %w", err)
+	}
+	updatedAt, revision, err := writeTimes(resp.WriteResults[len(writes)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	appState, userState, err := s.scopedState(ctx, req.AppName, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("error on create session: %w", err)
+	}
+	return &session.CreateResponse{
+		Session: &localSession{
+			appName:   req.AppName,
+			userID:    req.UserID,
+			sessionID: sessionID,
+			state:     sessionutils.MergeStates(appState, userState, sessionState),
+			updatedAt: updatedAt,
+			revision:  revision,
+		},
+	}, nil
+}
+
+// Get retrieves a session and its events, implements session.Service
+func (s *firestoreService) Get(ctx context.Context, req *session.GetRequest) (*session.GetResponse, error) {
+	appName, userID, sessionID := req.AppName, req.UserID, req.SessionID
+	if appName == "" || userID == "" || sessionID == "" {
+		return nil, fmt.Errorf("app_name, user_id, session_id are required, got app_name: %q, user_id: %q, session_id: %q", appName, userID, sessionID)
+	}
+	if err := validateIDs(appName, userID, sessionID); err != nil {
+		return nil, err
+	}
+
+	sessionDoc := s.sessionDoc(appName, userID, sessionID)
+	doc, err := s.getDocument(ctx, sessionDoc)
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching session: %w", err)
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("session %q not found", sessionID)
+	}
+	sess, err := s.sessionFromDocument(doc, appName, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := &firestoreapi.StructuredQuery{
+		From:    []*firestoreapi.CollectionSelector{{CollectionId: eventsCollection}},
+		OrderBy: []*firestoreapi.Order{{Field: fieldRef(timestampField), Direction: "DESCENDING"}},
+	}
+	if !req.After.IsZero() {
+		query.Where = fieldFilter(timestampField, "GREATER_THAN_OR_EQUAL", timestampValue(req.After))
+	}
+	if req.NumRecentEvents > 0 {
+		query.Limit = int64(req.NumRecentEvents)
+	}
+	events, err := s.queryEvents(ctx, sessionDoc, query)
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching events: %w", err)
+	}
+	// The events were fetched in DESC order to get the most recent ones,
+	// the session lists them in chronological order.
+	slices.Reverse(events)
+	sess.events = events
+
+	appState, userState, err := s.scopedState(ctx, appName, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error on get session: %w", err)
+	}
+	sess.state = sessionutils.MergeStates(appState, userState, sess.state)
+	return &session.GetResponse{Session: sess}, nil
+}
+
+// ListEvents returns a page of the events of a session, implements session.Service
+func (s *firestoreService) ListEvents(ctx context.Context, req *session.ListEventsRequest) (*session.ListEventsResponse, error) {
+	appName, userID, sessionID := req.AppName, req.UserID, req.SessionID
+	if appName == "" || userID == "" || sessionID == "" {
+		return nil, fmt.Errorf("app_name, user_id, session_id are required, got app_name: %q, user_id: %q, session_id: %q", appName, userID, sessionID)
+	}
+	if err := validateIDs(appName, userID, sessionID); err != nil {
+		return nil, err
+	}
+	offset, err := sessionutils.DecodePageToken(req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionDoc := s.sessionDoc(appName, userID, sessionID)
+	doc, err := s.getDocument(ctx, sessionDoc)
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching session: %w", err)
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("session %q not found", sessionID)
+	}
+
+	var filters []*firestoreapi.Filter
+	if !req.After.IsZero() {
+		filters = append(filters, fieldFilter(timestampField, "GREATER_THAN_OR_EQUAL", timestampValue(req.After)))
+	}
+	if len(req.Authors) > 0 {
+		authors := make([]*firestoreapi.Value, len(req.Authors))
+		for i, author := range req.Authors {
+			v := stringValue(author)
+			authors[i] = &v
+		}
+		filters = append(filters, fieldFilter(authorField, "IN", firestoreapi.Value{
+			ArrayValue: &firestoreapi.ArrayValue{Values: authors},
+		}))
+	}
+	query := &firestoreapi.StructuredQuery{
+		From: []*firestoreapi.CollectionSelector{{CollectionId: eventsCollection}},
+		OrderBy: []*firestoreapi.Order{
+			{Field: fieldRef(timestampField), Direction: "ASCENDING"},
+			{Field: fieldRef("__name__"), Direction: "ASCENDING"},
+		},
+		Offset: int64(offset),
+	}
+	switch len(filters) {
+	case 0:
+	case 1:
+		query.Where = filters[0]
+	default:
+		query.Where = &firestoreapi.Filter{CompositeFilter: &firestoreapi.CompositeFilter{Op: "AND", Filters: filters}}
+	}
+	if req.PageSize > 0 {
+		// Fetch one more event to know whether there is a next page.
+		query.Limit = int64(req.PageSize + 1)
+	}
+
+	events, err := s.queryEvents(ctx, sessionDoc, query)
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching events: %w", err)
+	}
+	resp := &session.ListEventsResponse{Events: events}
+	if req.PageSize > 0 && len(events) > req.PageSize {
+		resp.Events = events[:req.PageSize]
+		resp.NextPageToken = sessionutils.EncodePageToken(offset + req.PageSize)
+	}
+	return resp, nil
+}
+
+// List lists the sessions of an app, and of a user if set, implements session.Service
+func (s *firestoreService) List(ctx context.Context, req *session.ListRequest) (*session.ListResponse, error) {
+	appName, userID := req.AppName, req.UserID
+	if appName == "" {
+		return nil, fmt.Errorf("app_name is required, got app_name: %q", req.AppName)
+	}
+	if err := validateIDs(appName, userID); err != nil {
+		return nil, err
+	}
+
+	parent, allDescendants := s.appDoc(appName), true
+	if userID != "" {
+		parent, allDescendants = s.userDoc(appName, userID), false
+	}
+	docs, err := s.runQuery(ctx, parent, &firestoreapi.StructuredQuery{
+		From: []*firestoreapi.CollectionSelector{{CollectionId: sessionsCollection, AllDescendants: allDescendants}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error while listing sessions: %w", err)
+	}
+
+	appDoc, err := s.getDocument(ctx, s.appDoc(appName))
+	if err != nil {
+		return nil, fmt.Errorf("error on list sessions: %w", err)
+	}
+	appState, err := documentState(appDoc)
+	if err != nil {
+		return nil, err
+	}
+	userStates := map[string]map[string]any{}
+
+	sessions := make([]session.Session, 0, len(docs))
+	for _, doc := range docs {
+		// The name ends with {userID}/sessions/{sessionID}.
+		segments := strings.Split(doc.Name, "/")
+		if len(segments) < 3 {
+			return nil, fmt.Errorf("unexpected session document name %q", doc.Name)
+		}
+		docUserID, sessionID := segments[len(segments)-3], segments[len(segments)-1]
+		sess, err := s.sessionFromDocument(doc, appName, docUserID, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		userState, ok := userStates[docUserID]
+		if !ok {
+			userDoc, err := s.getDocument(ctx, s.userDoc(appName, docUserID))
+			if err != nil {
+				return nil, fmt.Errorf("error on list sessions: %w", err)
+			}
+			if userState, err = documentState(userDoc); err != nil {
+				return nil, err
+			}
+			userStates[docUserID] = userState
+		}
+		sess.state = sessionutils.MergeStates(appState, userState, sess.state)
+		sessions = append(sessions, sess)
+	}
+	return &session.ListResponse{Sessions: sessions}, nil
+}
+
+// Delete deletes a session and its events, implements session.Service
+func (s *firestoreService) Delete(ctx context.Context, req *session.DeleteRequest) error {
+	appName, userID, sessionID := req.AppName, req.UserID, req.SessionID
+	if appName == "" || userID == "" || sessionID == "" {
+		return fmt.Errorf("app_name, user_id, session_id are required, got app_name: %q, user_id: %q, session_id: %q", appName, userID, sessionID)
+	}
+	if err := validateIDs(appName, userID, sessionID); err != nil {
+		return err
+	}
+
+	return s.deleteSession(ctx, s.sessionDoc(appName, userID, sessionID))
+}
+
+// Cleanup deletes the sessions last updated before olderThan, implements session.Service
+func (s *firestoreService) Cleanup(ctx context.Context, olderThan time.Time) (int, error) {
+	docs, err := s.runQuery(ctx, s.database+"/documents", &firestoreapi.StructuredQuery{
+		From:   []*firestoreapi.CollectionSelector{{CollectionId: sessionsCollection, AllDescendants: true}},
+		Where:  fieldFilter(updateTimeField, "LESS_THAN", timestampValue(olderThan)),
+		Select: &firestoreapi.Projection{Fields: []*firestoreapi.FieldReference{fieldRef("__name__")}},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error while listing expired sessions: %w", err)
+	}
+	// The collection group query spans the whole database, only the
+	// sessions under the configured collection are deleted.
+	prefix := fmt.Sprintf("%s/documents/%s/", s.database, s.collection)
+	deleted := 0
+	for _, doc := range docs {
+		if !strings.HasPrefix(doc.Name, prefix) {
+			continue
+		}
+		if err := s.deleteSession(ctx, doc.Name); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// deleteSession deletes the session document and its events.
+func (s *firestoreService) deleteSession(ctx context.Context, sessionDoc string) error {
+	// Firestore does not delete subcollections with their parent document.
+	eventDocs, err := s.runQuery(ctx, sessionDoc, &firestoreapi.StructuredQuery{
+		From:   []*firestoreapi.CollectionSelector{{CollectionId: eventsCollection}},
+		Select: &firestoreapi.Projection{Fields: []*firestoreapi.FieldReference{fieldRef("__name__")}},
+	})
+	if err != nil {
+		return fmt.Errorf("error while fetching events: %w", err)
+	}
+	writes := make([]*firestoreapi.Write, 0, len(eventDocs)+1)
+	for _, doc := range eventDocs {
+		writes = append(writes, &firestoreapi.Write{Delete: doc.Name})
+	}
+	writes = append(writes, &firestoreapi.Write{Delete: sessionDoc})
+	for batch := range slices.Chunk(writes, maxWritesPerCommit) {
+		if _, err := s.commit(ctx, batch); err != nil {
+			return fmt.Errorf("error during session deletion: %w", err)
+		}
+	}
+	return nil
+}
+
+// AppendEvent persists the event and applies its state delta, implements session.Service
+func (s *firestoreService) AppendEvent(ctx context.Context, curSession session.Session, event *session.Event) error {
+	if curSession == nil {
+		return fmt.Errorf("session is nil")
+	}
+	if event == nil {
+		return fmt.Errorf("event is nil")
+	}
+	// ignore partial events
+	if event.Partial {
+		return nil
+	}
+
+	// Trim temp state before persisting
+	event = trimTempDeltaState(event)
+
+	sess, ok := curSession.(*localSession)
+	if !ok {
+		return fmt.Errorf("unexpected session type %T", curSession)
+	}
+
+	appDelta, userDelta, sessionDelta := sessionutils.ExtractStateDeltas(event.Actions.StateDelta)
+	writes, err := s.stateWrites(sess.AppName(), sess.UserID(), appDelta, userDelta)
+	if err != nil {
+		return err
+	}
+	state, err := encodeState(sessionDelta)
+	if err != nil {
+		return err
+	}
+	sessionDoc := s.sessionDoc(sess.AppName(), sess.UserID(), sess.ID())
+	// The precondition on the update time of the session document rejects
+	// events appended to a stale session.
+	sessionWrite := &firestoreapi.Write{
+		Update: &firestoreapi.Document{
+			Name: sessionDoc,
+			Fields: map[string]firestoreapi.Value{
+				stateField:       *state,
+				lastEventIDField: stringValue(event.ID),
+			},
+		},
+		UpdateMask:       &firestoreapi.DocumentMask{FieldPaths: append(stateFieldPaths(sessionDelta), lastEventIDField)},
+		UpdateTransforms: []*firestoreapi.FieldTransform{{FieldPath: updateTimeField, SetToServerValue: "REQUEST_TIME"}},
+		CurrentDocument:  &firestoreapi.Precondition{UpdateTime: sess.revision},
+	}
+	eventData, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	eventWrite := &firestoreapi.Write{
+		Update: &firestoreapi.Document{
+			Name: s.eventDoc(sessionDoc, event.ID),
+			Fields: map[string]firestoreapi.Value{
+				eventField:        stringValue(string(eventData)),
+				authorField:       stringValue(event.Author),
+				invocationIDField: stringValue(event.InvocationID),
+				timestampField:    timestampValue(event.Timestamp),
+			},
+		},
+		CurrentDocument: &firestoreapi.Precondition{Exists: false, ForceSendFields: []string{"Exists"}},
+	}
+	writes = append(writes, sessionWrite, eventWrite)
+
+	resp, err := s.commit(ctx, writes)
+	if err != nil {
+		return fmt.Errorf("failed to save event, the session may be stale: %w", err)
+	}
+	updatedAt, revision, err := writeTimes(resp.WriteResults[len(writes)-2])
+	if err != nil {
+		return err
+	}
+	return sess.appendEvent(event, updatedAt, revision)
+}
+
+// stateWrites returns the writes updating the app and user state.
+func (s *firestoreService) stateWrites(appName, userID string, appDelta, userDelta map[string]any) ([]*firestoreapi.Write, error) {
+	var writes []*firestoreapi.Write
+	for doc, delta := range map[string]map[string]any{
+		s.appDoc(appName):          appDelta,
+		s.userDoc(appName, userID): userDelta,
+	} {
+		if len(delta) == 0 {
+			continue
+		}
+		state, err := encodeState(delta)
+		if err != nil {
+			return nil, err
+		}
+		writes = append(writes, &firestoreapi.Write{
+			Update:     &firestoreapi.Document{Name: doc, Fields: map[string]firestoreapi.Value{stateField: *state}},
+			UpdateMask: &firestoreapi.DocumentMask{FieldPaths: stateFieldPaths(delta)},
+		})
+	}
+	return writes, nil
+}
+
+// scopedState returns the app and user state.
+func (s *firestoreService) scopedState(ctx context.Context, appName, userID string) (appState, userState map[string]any, err error) {
+	appDoc, err := s.getDocument(ctx, s.appDoc(appName))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch app state: %w", err)
+	}
+	if appState, err = documentState(appDoc); err != nil {
+		return nil, nil, err
+	}
+	userDoc, err := s.getDocument(ctx, s.userDoc(appName, userID))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch user state: %w", err)
+	}
+	if userState, err = documentState(userDoc); err != nil {
+		return nil, nil, err
+	}
+	return appState, userState, nil
+}
+
+func (s *firestoreService) sessionFromDocument(doc *firestoreapi.Document, appName, userID, sessionID string) (*localSession, error) {
+	state, err := documentState(doc)
+	if err != nil {
+		return nil, err
+	}
+	updateTime := doc.Fields[updateTimeField]
+	updatedAt, err := time.Parse(time.RFC3339Nano, updateTime.TimestampValue)
+	if err != nil {
+		return nil, fmt.Errorf("invalid update time of session %q: %w", sessionID, err)
+	}
+	return &localSession{
+		appName:   appName,
+		userID:    userID,
+		sessionID: sessionID,
+		state:     state,
+		updatedAt: updatedAt,
+		revision:  doc.UpdateTime,
+	}, nil
+}
+
+// documentState returns the state stored in doc, which may be nil.
+func documentState(doc *firestoreapi.Document) (map[string]any, error) {
+	if doc == nil {
+		return make(map[string]any), nil
+	}
+	state := doc.Fields[stateField]
+	return decodeState(&state)
+}
+
+// writeTimes returns the server update time and the revision of a document
+// written with an update time transform.
+func writeTimes(result *firestoreapi.WriteResult) (time.Time, string, error) {
+	if result == nil || len(result.TransformResults) == 0 {
+		return time.Time{}, "", errors.New("missing update time in write result")
+	}
+	updatedAt, err := time.Parse(time.RFC3339Nano, result.TransformResults[len(result.TransformResults)-1].TimestampValue)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("invalid update time: %w", err)
+	}
+	return updatedAt, result.UpdateTime, nil
+}
+
+// getDocument returns the document, or nil if it does not exist.
+func (s *firestoreService) getDocument(ctx context.Context, name string) (*firestoreapi.Document, error) {
+	doc, err := s.documents.Get(name).Context(ctx).Do()
+	if err != nil {
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return doc, nil
+}
+
+func (s *firestoreService) commit(ctx context.Context, writes []*firestoreapi.Write) (*firestoreapi.CommitResponse, error) {
+	return s.documents.Commit(s.database, &firestoreapi.CommitRequest{Writes: writes}).Context(ctx).Do()
+}
+
+// queryEvents runs a query on the events of a session.
+func (s *firestoreService) queryEvents(ctx context.Context, sessionDoc string, query *firestoreapi.StructuredQuery) ([]*session.Event, error) {
+	docs, err := s.runQuery(ctx, sessionDoc, query)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*session.Event, 0, len(docs))
+	for _, doc := range docs {
+		data := doc.Fields[eventField]
+		var event session.Event
+		if err := json.Unmarshal([]byte(data.StringValue), &event); err != nil {
+			return nil, fmt.Errorf("failed to decode event %q: %w", doc.Name, err)
+		}
+		events = append(events, &event)
+	}
+	return events, nil
+}
+
+// runQuery runs a structured query on the collections under parent and
+// returns the matching documents.
+func (s *firestoreService) runQuery(ctx context.Context, parent string, query *firestoreapi.StructuredQuery) ([]*firestoreapi.Document, error) {
+	body, err := json.Marshal(&firestoreapi.RunQueryRequest{StructuredQuery: query})
+	if err != nil {
+		return nil, err
+	}
+	u := strings.TrimSuffix(s.basePath, "/") + "/v1/" + (&url.URL{Path: parent + ":runQuery"}).EscapedPath()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err := googleapi.CheckResponse(resp); err != nil {
+		return nil, err
+	}
+	// The response is an array with one element per result.
+	var results []*firestoreapi.RunQueryResponse
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("failed to decode query response: %w", err)
+	}
+	docs := make([]*firestoreapi.Document, 0, len(results))
+	for _, result := range results {
+		if result.Document != nil {
+			docs = append(docs, result.Document)
+		}
+	}
+	return docs, nil
+}
+
+func fieldRef(path string) *firestoreapi.FieldReference {
+	return &firestoreapi.FieldReference{FieldPath: path}
+}
+
+func fieldFilter(path, op string, value firestoreapi.Value) *firestoreapi.Filter {
+	return &firestoreapi.Filter{FieldFilter: &firestoreapi.FieldFilter{Field: fieldRef(path), Op: op, Value: &value}}
+}
+
+var _ session.Service = (*firestoreService)(nil)
@@ -0,0 +1,401 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firestore
+
+import (
+	"maps"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	firestoreapi "google.golang.org/api/firestore/v1"
+	"google.golang.org/api/option"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+func newTestService(t *testing.T) *firestoreService {
+	t.Helper()
+	srv := httptest.NewServer(newFakeFirestore())
+	t.Cleanup(srv.Close)
+	s, err := NewSessionService(t.Context(), Config{
+		ProjectID:     "project",
+		ClientOptions: []option.ClientOption{option.WithEndpoint(srv.URL), option.WithoutAuthentication()},
+	})
+	if err != nil {
+		t.Fatalf("NewSessionService() failed: %v", err)
+	}
+	return s.(*firestoreService)
+}
+
+func newTestEvent(id, author string, ts time.Time, stateDelta map[string]any) *session.Event {
+	return &session.Event{
+		ID:           id,
+		InvocationID: "inv",
+		Author:       author,
+		Timestamp:    ts,
+		LLMResponse: model.LLMResponse{
+			Content: genai.NewContentFromText(id, genai.RoleModel),
+		},
+		Actions: session.EventActions{StateDelta: stateDelta},
+	}
+}
+
+func eventIDs(events []*session.Event) []string {
+	ids := make([]string, 0, len(events))
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+func TestNewSessionService_RequiresProject(t *testing.T) {
+	if _, err := NewSessionService(t.Context(), Config{}); err == nil {
+		t.Errorf("NewSessionService() succeeded, want error")
+	}
+}
+
+func Test_firestoreService_Create(t *testing.T) {
+	ctx := t.Context()
+	s := newTestService(t)
+
+	got, err := s.Create(ctx, &session.CreateRequest{
+		AppName:   "app",
+		UserID:    "user",
+		SessionID: "session",
+		State:     map[string]any{"k": 5, "app:a": "x", "user:u": true, "temp:t": 1},
+	})
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	want := map[string]any{"k": 5, "app:a": "x", "user:u": true}
+	if diff := cmp.Diff(want, maps.Collect(got.Session.State().All())); diff != "" {
+		t.Errorf("Create() state mismatch (-want +got):\n%s", diff)
+	}
+	if got.Session.LastUpdateTime().IsZero() {
+		t.Errorf("LastUpdateTime() is zero, want the server time")
+	}
+
+	if _, err := s.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "session"}); err == nil {
+		t.Errorf("Create() of an existing session succeeded, want error")
+	}
+	if _, err := s.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "a/b"}); err == nil {
+		t.Errorf("Create() with an invalid user ID succeeded, want error")
+	}
+
+	generated, err := s.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	if generated.Session.ID() == "" {
+		t.Errorf("SessionID was not generated on empty user input.")
+	}
+}
+
+func Test_firestoreService_AppendEventAndGet(t *testing.T) {
+	ctx := t.Context()
+	s := newTestService(t)
+	created, err := s.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	for i, ev := range []*session.Event{
+		newTestEvent("e1", "user", start, map[string]any{"k": "v1", "temp:t": 1}),
+		newTestEvent("e2", "agent", start.Add(time.Second), map[string]any{"k": "v2", "app:a": 1, "user:u": 2}),
+		newTestEvent("e3", "agent", start.Add(2*time.Second), nil),
+	} {
+		if err := s.AppendEvent(ctx, created.Session, ev); err != nil {
+			t.Fatalf("AppendEvent(%d) failed: %v", i, err)
+		}
+	}
+	partial := newTestEvent("partial", "agent", start.Add(3*time.Second), nil)
+	partial.Partial = true
+	if err := s.AppendEvent(ctx, created.Session, partial); err != nil {
+		t.Fatalf("AppendEvent(partial) failed: %v", err)
+	}
+
+	wantLocalState := map[string]any{"k": "v2", "app:a": 1, "user:u": 2}
+	if diff := cmp.Diff(wantLocalState, maps.Collect(created.Session.State().All())); diff != "" {
+		t.Errorf("local state mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err := s.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	// Stored values are normalized through JSON.
+	wantState := map[string]any{"k": "v2", "app:a": float64(1), "user:u": float64(2)}
+	if diff := cmp.Diff(wantState, maps.Collect(got.Session.State().All())); diff != "" {
+		t.Errorf("Get() state mismatch (-want +got):\n%s", diff)
+	}
+	gotEvents := slices.Collect(got.Session.Events().All())
+	if diff := cmp.Diff([]string{"e1", "e2", "e3"}, eventIDs(gotEvents)); diff != "" {
+		t.Errorf("Get() events mismatch (-want +got):\n%s", diff)
+	}
+	// Temporary state is not persisted.
+	if diff := cmp.Diff(map[string]any{"k": "v1"}, gotEvents[0].Actions.StateDelta); diff != "" {
+		t.Errorf("Get() first event state delta mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(genai.NewContentFromText("e2", genai.RoleModel), gotEvents[1].Content); diff != "" {
+		t.Errorf("Get() event content mismatch (-want +got):\n%s", diff)
+	}
+	if !gotEvents[2].Timestamp.Equal(start.Add(2 * time.Second)) {
+		t.Errorf("Get() event timestamp = %v, want %v", gotEvents[2].Timestamp, start.Add(2*time.Second))
+	}
+	if !got.Session.LastUpdateTime().Equal(created.Session.LastUpdateTime()) {
+		t.Errorf("LastUpdateTime() = %v, want %v", got.Session.LastUpdateTime(), created.Session.LastUpdateTime())
+	}
+
+	recent, err := s.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "s1", NumRecentEvents: 2})
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"e2", "e3"}, eventIDs(slices.Collect(recent.Session.Events().All()))); diff != "" {
+		t.Errorf("Get(NumRecentEvents) events mismatch (-want +got):\n%s", diff)
+	}
+	after, err := s.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "s1", After: start.Add(time.Second)})
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"e2", "e3"}, eventIDs(slices.Collect(after.Session.Events().All()))); diff != "" {
+		t.Errorf("Get(After) events mismatch (-want +got):\n%s", diff)
+	}
+
+	// App and user state are shared with the other sessions.
+	other, err := s.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "s2"})
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	wantOther := map[string]any{"app:a": float64(1), "user:u": float64(2)}
+	if diff := cmp.Diff(wantOther, maps.Collect(other.Session.State().All())); diff != "" {
+		t.Errorf("other session state mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := s.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "missing"}); err == nil {
+		t.Errorf("Get() of a missing session succeeded, want error")
+	}
+}
+
+func Test_firestoreService_AppendEvent_StaleSession(t *testing.T) {
+	ctx := t.Context()
+	s := newTestService(t)
+	created, err := s.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	stale, err := s.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if err := s.AppendEvent(ctx, created.Session, newTestEvent("e1", "user", time.Now(), nil)); err != nil {
+		t.Fatalf("AppendEvent() failed: %v", err)
+	}
+	if err := s.AppendEvent(ctx, stale.Session, newTestEvent("e2", "user", time.Now(), nil)); err == nil {
+		t.Errorf("AppendEvent() on a stale session succeeded, want error")
+	}
+}
+
+func Test_firestoreService_ListEvents(t *testing.T) {
+	ctx := t.Context()
+	s := newTestService(t)
+	created, err := s.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	for i, author := range []string{"user", "a", "b", "a", "user"} {
+		ev := newTestEvent("e"+string(rune('1'+i)), author, start.Add(time.Duration(i)*time.Second), nil)
+		if err := s.AppendEvent(ctx, created.Session, ev); err != nil {
+			t.Fatalf("AppendEvent() failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		req  session.ListEventsRequest
+		want [][]string
+	}{
+		{
+			name: "all",
+			want: [][]string{{"e1", "e2", "e3", "e4", "e5"}},
+		},
+		{
+			name: "pages",
+			req:  session.ListEventsRequest{PageSize: 2},
+			want: [][]string{{"e1", "e2"}, {"e3", "e4"}, {"e5"}},
+		},
+		{
+			name: "authors and after",
+			req:  session.ListEventsRequest{PageSize: 1, Authors: []string{"a", "b"}, After: start.Add(2 * time.Second)},
+			want: [][]string{{"e3"}, {"e4"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			req.AppName, req.UserID, req.SessionID = "app", "user", "s1"
+			var got [][]string
+			for {
+				resp, err := s.ListEvents(ctx, &req)
+				if err != nil {
+					t.Fatalf("ListEvents() failed: %v", err)
+				}
+				got = append(got, eventIDs(resp.Events))
+				if resp.NextPageToken == "" {
+					break
+				}
+				req.PageToken = resp.NextPageToken
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ListEvents() pages mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := s.ListEvents(ctx, &session.ListEventsRequest{AppName: "app", UserID: "user", SessionID: "missing"}); err == nil {
+		t.Errorf("ListEvents() of a missing session succeeded, want error")
+	}
+}
+
+func Test_firestoreService_ListAndDelete(t *testing.T) {
+	ctx := t.Context()
+	s := newTestService(t)
+	for _, req := range []*session.CreateRequest{
+		{AppName: "app", UserID: "u1", SessionID: "s1", State: map[string]any{"k": 1, "user:u": "u1"}},
+		{AppName: "app", UserID: "u1", SessionID: "s2"},
+		{AppName: "app", UserID: "u2", SessionID: "s3", State: map[string]any{"app:a": "x"}},
+		{AppName: "other", UserID: "u1", SessionID: "s4"},
+	} {
+		created, err := s.Create(ctx, req)
+		if err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+		if err := s.AppendEvent(ctx, created.Session, newTestEvent(req.SessionID+"-e", "user", time.Now(), nil)); err != nil {
+			t.Fatalf("AppendEvent() failed: %v", err)
+		}
+	}
+
+	type listed struct {
+		UserID, SessionID string
+		State             map[string]any
+	}
+	list := func(userID string) []listed {
+		t.Helper()
+		resp, err := s.List(ctx, &session.ListRequest{AppName: "app", UserID: userID})
+		if err != nil {
+			t.Fatalf("List() failed: %v", err)
+		}
+		var got []listed
+		for _, sess := range resp.Sessions {
+			got = append(got, listed{sess.UserID(), sess.ID(), maps.Collect(sess.State().All())})
+		}
+		slices.SortFunc(got, func(a, b listed) int { return strings.Compare(a.SessionID, b.SessionID) })
+		return got
+	}
+
+	want := []listed{
+		{"u1", "s1", map[string]any{"k": float64(1), "user:u": "u1", "app:a": "x"}},
+		{"u1", "s2", map[string]any{"user:u": "u1", "app:a": "x"}},
+		{"u2", "s3", map[string]any{"app:a": "x"}},
+	}
+	if diff := cmp.Diff(want, list("")); diff != "" {
+		t.Errorf("List() mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want[:2], list("u1")); diff != "" {
+		t.Errorf("List(u1) mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := s.Delete(ctx, &session.DeleteRequest{AppName: "app", UserID: "u1", SessionID: "s1"}); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if diff := cmp.Diff(want[1:2], list("u1")); diff != "" {
+		t.Errorf("List(u1) after delete mismatch (-want +got):\n%s", diff)
+	}
+	// The events are deleted with the session.
+	docs, err := s.runQuery(ctx, s.sessionDoc("app", "u1", "s1"), &firestoreapi.StructuredQuery{
+		From: []*firestoreapi.CollectionSelector{{CollectionId: eventsCollection}},
+	})
+	if err != nil {
+		t.Fatalf("runQuery() failed: %v", err)
+	}
+	if len(docs) != 0 {
+		t.Errorf("got %d events after delete, want 0", len(docs))
+	}
+}
+
+func Test_firestoreService_Cleanup(t *testing.T) {
+	ctx := t.Context()
+	srv := httptest.NewServer(newFakeFirestore())
+	t.Cleanup(srv.Close)
+	newService := func(collection string) *firestoreService {
+		s, err := NewSessionService(ctx, Config{
+			ProjectID:     "project",
+			Collection:    collection,
+			ClientOptions: []option.ClientOption{option.WithEndpoint(srv.URL), option.WithoutAuthentication()},
+		})
+		if err != nil {
+			t.Fatalf("NewSessionService() failed: %v", err)
+		}
+		return s.(*firestoreService)
+	}
+	s, other := newService(""), newService("other_apps")
+
+	if _, err := other.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "other"}); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	old, err := s.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "old"})
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	if err := s.AppendEvent(ctx, old.Session, newTestEvent("e1", "user", time.Now(), nil)); err != nil {
+		t.Fatalf("AppendEvent() failed: %v", err)
+	}
+	// The fake server clock advances on every commit, so only "new" is
+	// updated after olderThan.
+	olderThan := old.Session.LastUpdateTime().Add(time.Nanosecond)
+	if _, err := s.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "new"}); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	deleted, err := s.Cleanup(ctx, olderThan)
+	if err != nil {
+		t.Fatalf("Cleanup() failed: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("Cleanup() = %d, want 1", deleted)
+	}
+
+	resp, err := s.List(ctx, &session.ListRequest{AppName: "app"})
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	var got []string
+	for _, sess := range resp.Sessions {
+		got = append(got, sess.ID())
+	}
+	if diff := cmp.Diff([]string{"new"}, got); diff != "" {
+		t.Errorf("List() after Cleanup() mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := other.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "other"}); err != nil {
+		t.Errorf("Get() of a session in another collection failed: %v", err)
+	}
+}
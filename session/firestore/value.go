@@ -0,0 +1,81 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package firestore
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	firestoreapi "google.golang.org/api/firestore/v1"
+)
+
+// encodeState converts a state map to a Firestore map value. Each value is
+// stored as its JSON encoding, so that the keys can be updated individually
+// while the values round trip like in the other session services.
+func encodeState(state map[string]any) (*firestoreapi.Value, error) {
+	fields := make(map[string]firestoreapi.Value, len(state))
+	for k, v := range state {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode state key %q: %w", k, err)
+		}
+		fields[k] = stringValue(string(data))
+	}
+	return &firestoreapi.Value{MapValue: &firestoreapi.MapValue{Fields: fields}}, nil
+}
+
+// decodeState converts a Firestore map value written by encodeState back to
+// a state map.
+func decodeState(v *firestoreapi.Value) (map[string]any, error) {
+	state := make(map[string]any)
+	if v == nil || v.MapValue == nil {
+		return state, nil
+	}
+	for k, field := range v.MapValue.Fields {
+		var value any
+		if err := json.Unmarshal([]byte(field.StringValue), &value); err != nil {
+			return nil, fmt.Errorf("failed to decode state key %q: %w", k, err)
+		}
+		state[k] = value
+	}
+	return state, nil
+}
+
+// stateFieldPaths returns the field paths of the keys of delta in the state
+// field, so that the other keys are left untouched by an update.
+func stateFieldPaths(delta map[string]any) []string {
+	paths := make([]string, 0, len(delta))
+	for k := range delta {
+		paths = append(paths, stateField+"."+quoteFieldPath(k))
+	}
+	return paths
+}
+
+// quoteFieldPath quotes a field name so that it can contain any character.
+func quoteFieldPath(name string) string {
+	name = strings.ReplaceAll(name, `\`, `\\`)
+	name = strings.ReplaceAll(name, "`", "\\`")
+	return "`" + name + "`"
+}
+
+func stringValue(s string) firestoreapi.Value {
+	return firestoreapi.Value{StringValue: s, ForceSendFields: []string{"StringValue"}}
+}
+
+func timestampValue(t time.Time) firestoreapi.Value {
+	return firestoreapi.Value{TimestampValue: t.UTC().Format(time.RFC3339Nano)}
+}
@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"google.golang.org/genai"
 	"rsc.io/omap"
 	"rsc.io/ordered"
 
@@ -36,6 +37,10 @@ type stateMap map[string]any
 
 // inMemoryService is an in-memory implementation of sessionService.Service.
 // Thread-safe.
+//
+// The stored events are copies of the appended ones and are never modified,
+// and the sessions and events returned to the callers are copies as well, so
+// that the callers cannot change the stored data.
 type inMemoryService struct {
 	mu        sync.RWMutex
 	sessions  omap.Map[string, *session] // session.ID) -> storedSession
@@ -59,13 +64,7 @@ func (s *inMemoryService) Create(ctx context.Context, req *CreateRequest) (*Crea
 		sessionID: sessionID,
 	}
 
-	encodedKey := key.Encode()
-	_, ok := s.sessions.Get(encodedKey)
-	if ok {
-		return nil, fmt.Errorf("session %s already exists", req.SessionID)
-	}
-
-	state := req.State
+	state := maps.Clone(req.State)
 	if state == nil {
 		state = make(stateMap)
 	}
@@ -78,6 +77,10 @@ func (s *inMemoryService) Create(ctx context.Context, req *CreateRequest) (*Crea
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	encodedKey := key.Encode()
+	if _, ok := s.sessions.Get(encodedKey); ok {
+		return nil, fmt.Errorf("session %s already exists", req.SessionID)
+	}
 	s.sessions.Set(encodedKey, val)
 	appDelta, userDelta, _ := sessionutils.ExtractStateDeltas(req.State)
 	appState := s.updateAppState(appDelta, req.AppName)
@@ -86,7 +89,7 @@ func (s *inMemoryService) Create(ctx context.Context, req *CreateRequest) (*Crea
 
 	copiedSession := copySessionWithoutStateAndEvents(val)
 	copiedSession.state = maps.Clone(val.state)
-	copiedSession.events = slices.Clone(val.events)
+	copiedSession.events = cloneEvents(val.events)
 
 	return &CreateResponse{
 		Session: copiedSession,
@@ -131,8 +134,7 @@ func (s *inMemoryService) Get(ctx context.Context, req *GetRequest) (*GetRespons
 		filteredEvents = filteredEvents[firstIndexToKeep:]
 	}
 
-	copiedSession.events = make([]*Event, 0, len(filteredEvents))
-	copiedSession.events = append(copiedSession.events, filteredEvents...)
+	copiedSession.events = cloneEvents(filteredEvents)
 
 	return &GetResponse{
 		Session: copiedSession,
@@ -195,6 +197,69 @@ func (s *inMemoryService) Delete(ctx context.Context, req *DeleteRequest) error
 	return nil
 }
 
+func (s *inMemoryService) Cleanup(ctx context.Context, olderThan time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var expired []string
+	for k, storedSession := range s.sessions.All() {
+		if storedSession.LastUpdateTime().Before(olderThan) {
+			expired = append(expired, k)
+		}
+	}
+	for _, k := range expired {
+		s.sessions.Delete(k)
+	}
+	return len(expired), nil
+}
+
+func (s *inMemoryService) ListEvents(ctx context.Context, req *ListEventsRequest) (*ListEventsResponse, error) {
+	appName, userID, sessionID := req.AppName, req.UserID, req.SessionID
+	if appName == "" || userID == "" || sessionID == "" {
+		return nil, fmt.Errorf("app_name, user_id, session_id are required, got app_name: %q, user_id: %q, session_id: %q", appName, userID, sessionID)
+	}
+	offset, err := sessionutils.DecodePageToken(req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	id := id{
+		appName:   appName,
+		userID:    userID,
+		sessionID: sessionID,
+	}
+	res, ok := s.sessions.Get(id.Encode())
+	if !ok {
+		return nil, fmt.Errorf("session %+v not found", req.SessionID)
+	}
+
+	var filteredEvents []*Event
+	for _, event := range res.events {
+		if !req.After.IsZero() && event.Timestamp.Before(req.After) {
+			continue
+		}
+		if len(req.Authors) > 0 && !slices.Contains(req.Authors, event.Author) {
+			continue
+		}
+		filteredEvents = append(filteredEvents, event)
+	}
+
+	resp := &ListEventsResponse{Events: make([]*Event, 0)}
+	if offset >= len(filteredEvents) {
+		return resp, nil
+	}
+	end := len(filteredEvents)
+	if req.PageSize > 0 && offset+req.PageSize < end {
+		end = offset + req.PageSize
+		resp.NextPageToken = sessionutils.EncodePageToken(end)
+	}
+	resp.Events = cloneEvents(filteredEvents[offset:end])
+	return resp, nil
+}
+
 func (s *inMemoryService) AppendEvent(ctx context.Context, curSession Session, event *Event) error {
 	if curSession == nil {
 		return fmt.Errorf("session is nil")
@@ -224,8 +289,9 @@ func (s *inMemoryService) AppendEvent(ctx context.Context, curSession Session, e
 		return fmt.Errorf("fail to set state on appendEvent: %w", err)
 	}
 
-	// update the in-memory session service
-	stored_session.events = append(stored_session.events, event)
+	// update the in-memory session service with a copy of the event, which
+	// the caller keeps
+	stored_session.events = append(stored_session.events, cloneEvent(event))
 	stored_session.updatedAt = event.Timestamp
 	if len(event.Actions.StateDelta) > 0 {
 		appDelta, userDelta, sessionDelta := sessionutils.ExtractStateDeltas(event.Actions.StateDelta)
@@ -315,7 +381,10 @@ func (s *session) State() State {
 }
 
 func (s *session) Events() Events {
-	return events(s.events)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return events(slices.Clone(s.events))
 }
 
 func (s *session) LastUpdateTime() time.Time {
@@ -331,10 +400,11 @@ func (s *session) appendEvent(event *Event) error {
 	}
 
 	processedEvent := trimTempDeltaState(event)
-	if err := updateSessionState(s, processedEvent); err != nil {
-		return fmt.Errorf("error on appendEvent: %w", err)
-	}
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updateSessionState(s, processedEvent)
 	s.events = append(s.events, event)
 	s.updatedAt = event.Timestamp
 	return nil
@@ -363,6 +433,45 @@ func (e events) At(i int) *Event {
 	return nil
 }
 
+// ReverseAll implements [EventQuerier].
+func (e events) ReverseAll() iter.Seq[*Event] {
+	return func(yield func(*Event) bool) {
+		for i := len(e) - 1; i >= 0; i-- {
+			if !yield(e[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Filter implements [EventQuerier]. The events are in chronological
+// order, so the ones before f.After are skipped with a binary search.
+func (e events) Filter(f EventFilter) iter.Seq[*Event] {
+	return func(yield func(*Event) bool) {
+		start := 0
+		if !f.After.IsZero() {
+			start = sort.Search(len(e), func(i int) bool {
+				return !e[i].Timestamp.Before(f.After)
+			})
+		}
+		for _, event := range e[start:] {
+			if f.Match(event) && !yield(event) {
+				return
+			}
+		}
+	}
+}
+
+// LastBy implements [EventQuerier].
+func (e events) LastBy(author string) *Event {
+	for i := len(e) - 1; i >= 0; i-- {
+		if e[i].Author == author {
+			return e[i]
+		}
+	}
+	return nil
+}
+
 type state struct {
 	mu    *sync.RWMutex
 	state map[string]any
@@ -382,17 +491,16 @@ func (s *state) Get(key string) (any, error) {
 
 func (s *state) All() iter.Seq2[string, any] {
 	return func(yield func(key string, val any) bool) {
+		// The state is copied, so that it can be changed while iterating.
 		s.mu.RLock()
+		snapshot := maps.Clone(s.state)
+		s.mu.RUnlock()
 
-		for k, v := range s.state {
-			s.mu.RUnlock()
+		for k, v := range snapshot {
 			if !yield(k, v) {
 				return
 			}
-			s.mu.RLock()
 		}
-
-		s.mu.RUnlock()
 	}
 }
 
@@ -424,28 +532,23 @@ func trimTempDeltaState(event *Event) *Event {
 	return event
 }
 
-// updateSessionState updates the session state based on the event state delta.
-func updateSessionState(session *session, event *Event) error {
+// updateSessionState updates the session state based on the event state
+// delta. The caller must hold the lock of the session.
+func updateSessionState(session *session, event *Event) {
 	if event.Actions.StateDelta == nil {
-		return nil // Nothing to do
+		return // Nothing to do
 	}
 
 	// ensure the session state map is initialized
 	if session.state == nil {
 		session.state = make(map[string]any)
 	}
-
-	state := session.State()
 	for key, value := range event.Actions.StateDelta {
 		if strings.HasPrefix(key, KeyPrefixTemp) {
 			continue
 		}
-		err := state.Set(key, value)
-		if err != nil {
-			return fmt.Errorf("error on updateSessionState state: %w", err)
-		}
+		session.state[key] = value
 	}
-	return nil
 }
 
 func copySessionWithoutStateAndEvents(sess *session) *session {
@@ -459,4 +562,41 @@ func copySessionWithoutStateAndEvents(sess *session) *session {
 	}
 }
 
+// cloneEvents returns copies of the events, see cloneEvent.
+func cloneEvents(events []*Event) []*Event {
+	res := make([]*Event, len(events))
+	for i, event := range events {
+		res[i] = cloneEvent(event)
+	}
+	return res
+}
+
+// cloneEvent returns a copy of the event that can be modified without
+// changing the original one: its content, with its parts, its actions and
+// its metadata are copied. The values held by the parts, e.g. the arguments
+// of function calls, and by the state delta are shared.
+func cloneEvent(event *Event) *Event {
+	if event == nil {
+		return nil
+	}
+	clone := *event
+	if event.Content != nil {
+		content := *event.Content
+		content.Parts = make([]*genai.Part, len(event.Content.Parts))
+		for i, p := range event.Content.Parts {
+			if p != nil {
+				part := *p
+				content.Parts[i] = &part
+			}
+		}
+		clone.Content = &content
+	}
+	clone.CustomMetadata = maps.Clone(event.CustomMetadata)
+	clone.LongRunningToolIDs = slices.Clone(event.LongRunningToolIDs)
+	clone.Actions.StateDelta = maps.Clone(event.Actions.StateDelta)
+	clone.Actions.ArtifactDelta = maps.Clone(event.Actions.ArtifactDelta)
+	clone.Actions.RequestedAuthConfigs = maps.Clone(event.Actions.RequestedAuthConfigs)
+	return &clone
+}
+
 var _ Service = (*inMemoryService)(nil)
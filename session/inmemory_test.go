@@ -17,6 +17,7 @@ package session
 import (
 	"maps"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -1000,4 +1001,244 @@ func emptyService(t *testing.T) Service {
 	return InMemoryService()
 }
 
+func Test_inMemoryService_ListEvents(t *testing.T) {
+	ctx := t.Context()
+	service := emptyService(t)
+	resp, err := service.Create(ctx, &CreateRequest{AppName: "app", UserID: "user", SessionID: "session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, author := range []string{"user", "agent", "user", "agent", "user"} {
+		event := &Event{
+			ID:        "e" + strconv.Itoa(i),
+			Author:    author,
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+		}
+		if err := service.AppendEvent(ctx, resp.Session, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		req  ListEventsRequest
+		want [][]string
+	}{
+		{
+			name: "all",
+			want: [][]string{{"e0", "e1", "e2", "e3", "e4"}},
+		},
+		{
+			name: "pages",
+			req:  ListEventsRequest{PageSize: 2},
+			want: [][]string{{"e0", "e1"}, {"e2", "e3"}, {"e4"}},
+		},
+		{
+			name: "author",
+			req:  ListEventsRequest{PageSize: 2, Authors: []string{"user"}},
+			want: [][]string{{"e0", "e2"}, {"e4"}},
+		},
+		{
+			name: "after",
+			req:  ListEventsRequest{PageSize: 2, After: start.Add(3 * time.Second)},
+			want: [][]string{{"e3", "e4"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			req.AppName, req.UserID, req.SessionID = "app", "user", "session"
+			var got [][]string
+			for {
+				resp, err := service.ListEvents(ctx, &req)
+				if err != nil {
+					t.Fatalf("ListEvents() error = %v", err)
+				}
+				var ids []string
+				for _, event := range resp.Events {
+					ids = append(ids, event.ID)
+				}
+				got = append(got, ids)
+				if resp.NextPageToken == "" {
+					break
+				}
+				req.PageToken = resp.NextPageToken
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ListEvents() pages mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	if _, err := service.ListEvents(ctx, &ListEventsRequest{AppName: "app", UserID: "user", SessionID: "missing"}); err == nil {
+		t.Errorf("ListEvents() for a missing session succeeded, want error")
+	}
+	if _, err := service.ListEvents(ctx, &ListEventsRequest{AppName: "app", UserID: "user", SessionID: "session", PageToken: "!"}); err == nil {
+		t.Errorf("ListEvents() with an invalid page token succeeded, want error")
+	}
+}
+
 // TODO: test concurrency
+
+func Test_inMemoryService_Cleanup(t *testing.T) {
+	ctx := t.Context()
+	service := emptyService(t)
+	for _, id := range []string{"old", "new"} {
+		if _, err := service.Create(ctx, &CreateRequest{AppName: "app", UserID: "user", SessionID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old, err := service.Get(ctx, &GetRequest{AppName: "app", UserID: "user", SessionID: "old"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	event := &Event{ID: "e1", Author: "user", Timestamp: time.Now().Add(-2 * time.Hour)}
+	if err := service.AppendEvent(ctx, old.Session, event); err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, err := service.Cleanup(ctx, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Cleanup() error = %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("Cleanup() = %d, want 1", deleted)
+	}
+
+	resp, err := service.List(ctx, &ListRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, s := range resp.Sessions {
+		got = append(got, s.ID())
+	}
+	if diff := cmp.Diff([]string{"new"}, got); diff != "" {
+		t.Errorf("List() after Cleanup() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestInMemoryService_ConcurrentUse(t *testing.T) {
+	ctx := t.Context()
+	s := InMemoryService()
+	created, err := s.Create(ctx, &CreateRequest{AppName: "app", UserID: "user", SessionID: "session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const n = 50
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			event := NewEvent("invocation")
+			event.Actions.StateDelta = map[string]any{"key" + strconv.Itoa(i): i, "user:count": i}
+			if err := s.AppendEvent(ctx, created.Session, event); err != nil {
+				t.Errorf("AppendEvent() failed: %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range created.Session.State().All() {
+			}
+			for range created.Session.Events().All() {
+			}
+			got, err := s.Get(ctx, &GetRequest{AppName: "app", UserID: "user", SessionID: "session"})
+			if err != nil {
+				t.Errorf("Get() failed: %v", err)
+				return
+			}
+			for range got.Session.State().All() {
+			}
+			if _, err := s.ListEvents(ctx, &ListEventsRequest{AppName: "app", UserID: "user", SessionID: "session"}); err != nil {
+				t.Errorf("ListEvents() failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	got, err := s.Get(ctx, &GetRequest{AppName: "app", UserID: "user", SessionID: "session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Session.Events().Len() != n {
+		t.Errorf("got %d events, want %d", got.Session.Events().Len(), n)
+	}
+	if created.Session.Events().Len() != n {
+		t.Errorf("the appending session has %d events, want %d", created.Session.Events().Len(), n)
+	}
+}
+
+func TestInMemoryService_ConcurrentCreate(t *testing.T) {
+	ctx := t.Context()
+	s := InMemoryService()
+	const n = 20
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	created := 0
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.Create(ctx, &CreateRequest{AppName: "app", UserID: "user", SessionID: "session"}); err == nil {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if created != 1 {
+		t.Errorf("%d sessions created with the same ID, want 1", created)
+	}
+}
+
+func TestInMemoryService_ReturnsCopies(t *testing.T) {
+	ctx := t.Context()
+	s := InMemoryService()
+	initialState := map[string]any{"k": "v"}
+	created, err := s.Create(ctx, &CreateRequest{AppName: "app", UserID: "user", SessionID: "session", State: initialState})
+	if err != nil {
+		t.Fatal(err)
+	}
+	initialState["k"] = "changed"
+
+	event := &Event{
+		ID:          "e1",
+		LLMResponse: model.LLMResponse{Content: genai.NewContentFromText("original", genai.RoleModel)},
+		Actions:     EventActions{StateDelta: map[string]any{"a": 1}},
+	}
+	if err := s.AppendEvent(ctx, created.Session, event); err != nil {
+		t.Fatal(err)
+	}
+	// Changes of the appended event, and of the returned ones, are not
+	// stored.
+	event.Content.Parts[0].Text = "changed by the caller"
+	event.Actions.StateDelta["a"] = 2
+	got, err := s.Get(ctx, &GetRequest{AppName: "app", UserID: "user", SessionID: "session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.Session.Events().At(0).Content.Parts[0].Text = "changed by the reader"
+	listed, err := s.ListEvents(ctx, &ListEventsRequest{AppName: "app", UserID: "user", SessionID: "session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	listed.Events[0].Actions.StateDelta["b"] = 3
+
+	got, err = s.Get(ctx, &GetRequest{AppName: "app", UserID: "user", SessionID: "session"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := got.Session.Events().At(0)
+	if diff := cmp.Diff(genai.NewContentFromText("original", genai.RoleModel), stored.Content); diff != "" {
+		t.Errorf("stored content changed (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]any{"a": 1}, stored.Actions.StateDelta); diff != "" {
+		t.Errorf("stored state delta changed (-want +got):\n%s", diff)
+	}
+	if v, _ := got.Session.State().Get("k"); v != "v" {
+		t.Errorf("state k = %v, want %q", v, "v")
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// NewAppRouter returns a Service sending the requests of the apps of apps,
+// keyed by app name, to their service, and the requests of the other apps
+// to fallback. If fallback is nil, the requests of the other apps fail.
+func NewAppRouter(fallback Service, apps map[string]Service) Service {
+	return &appRouter{fallback: fallback, apps: apps}
+}
+
+type appRouter struct {
+	fallback Service
+	apps     map[string]Service
+}
+
+func (r *appRouter) service(appName string) (Service, error) {
+	if s, ok := r.apps[appName]; ok {
+		return s, nil
+	}
+	if r.fallback == nil {
+		return nil, fmt.Errorf("no session service for app %q", appName)
+	}
+	return r.fallback, nil
+}
+
+func (r *appRouter) Create(ctx context.Context, req *CreateRequest) (*CreateResponse, error) {
+	s, err := r.service(req.AppName)
+	if err != nil {
+		return nil, err
+	}
+	return s.Create(ctx, req)
+}
+
+func (r *appRouter) Get(ctx context.Context, req *GetRequest) (*GetResponse, error) {
+	s, err := r.service(req.AppName)
+	if err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, req)
+}
+
+func (r *appRouter) List(ctx context.Context, req *ListRequest) (*ListResponse, error) {
+	s, err := r.service(req.AppName)
+	if err != nil {
+		return nil, err
+	}
+	return s.List(ctx, req)
+}
+
+func (r *appRouter) Delete(ctx context.Context, req *DeleteRequest) error {
+	s, err := r.service(req.AppName)
+	if err != nil {
+		return err
+	}
+	return s.Delete(ctx, req)
+}
+
+func (r *appRouter) ListEvents(ctx context.Context, req *ListEventsRequest) (*ListEventsResponse, error) {
+	s, err := r.service(req.AppName)
+	if err != nil {
+		return nil, err
+	}
+	return s.ListEvents(ctx, req)
+}
+
+func (r *appRouter) AppendEvent(ctx context.Context, session Session, event *Event) error {
+	s, err := r.service(session.AppName())
+	if err != nil {
+		return err
+	}
+	return s.AppendEvent(ctx, session, event)
+}
+
+// Cleanup cleans up every service once, even if it serves several apps.
+func (r *appRouter) Cleanup(ctx context.Context, olderThan time.Time) (int, error) {
+	services := make([]Service, 0, len(r.apps)+1)
+	if r.fallback != nil {
+		services = append(services, r.fallback)
+	}
+	for _, s := range r.apps {
+		services = append(services, s)
+	}
+	total := 0
+	done := make(map[Service]bool)
+	for _, s := range services {
+		if done[s] {
+			continue
+		}
+		done[s] = true
+		n, err := s.Cleanup(ctx, olderThan)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAppRouter(t *testing.T) {
+	ctx := t.Context()
+	fallback := InMemoryService()
+	billing := InMemoryService()
+	router := NewAppRouter(fallback, map[string]Service{"billing": billing, "invoices": billing})
+
+	for _, app := range []string{"billing", "invoices", "weather"} {
+		created, err := router.Create(ctx, &CreateRequest{AppName: app, UserID: "user", SessionID: "s"})
+		if err != nil {
+			t.Fatalf("Create(%q) error = %v", app, err)
+		}
+		event := &Event{ID: "e1", Author: "user", Timestamp: time.Now().Add(-2 * time.Hour)}
+		if err := router.AppendEvent(ctx, created.Session, event); err != nil {
+			t.Fatalf("AppendEvent(%q) error = %v", app, err)
+		}
+	}
+
+	for _, tc := range []struct {
+		app     string
+		service Service
+	}{
+		{app: "billing", service: billing},
+		{app: "invoices", service: billing},
+		{app: "weather", service: fallback},
+	} {
+		got, err := tc.service.Get(ctx, &GetRequest{AppName: tc.app, UserID: "user", SessionID: "s"})
+		if err != nil {
+			t.Fatalf("Get(%q) error = %v, want the session in its service", tc.app, err)
+		}
+		if n := got.Session.Events().Len(); n != 1 {
+			t.Errorf("app %q has %d events, want 1", tc.app, n)
+		}
+	}
+	if _, err := fallback.Get(ctx, &GetRequest{AppName: "billing", UserID: "user", SessionID: "s"}); err == nil {
+		t.Errorf("the session of an overridden app is in the fallback service")
+	}
+
+	n, err := router.Cleanup(ctx, time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Cleanup() error = %v", err)
+	}
+	if n != 3 {
+		t.Errorf("Cleanup() = %d, want 3", n)
+	}
+}
+
+func TestAppRouter_NoFallback(t *testing.T) {
+	router := NewAppRouter(nil, map[string]Service{"billing": InMemoryService()})
+	if _, err := router.Create(t.Context(), &CreateRequest{AppName: "weather", UserID: "user"}); err == nil {
+		t.Errorf("Create() succeeded for an app without a service, want error")
+	}
+}
@@ -27,8 +27,15 @@ type Service interface {
 	Get(context.Context, *GetRequest) (*GetResponse, error)
 	List(context.Context, *ListRequest) (*ListResponse, error)
 	Delete(context.Context, *DeleteRequest) error
+	// ListEvents returns a page of the events of a session in chronological
+	// order.
+	ListEvents(context.Context, *ListEventsRequest) (*ListEventsResponse, error)
 	// AppendEvent is used to append an event to a session, and remove temporary state keys from the event.
 	AppendEvent(context.Context, Session, *Event) error
+	// Cleanup deletes the sessions, with their events, that were last
+	// updated before olderThan. App and user state are kept.
+	// It returns the number of deleted sessions.
+	Cleanup(ctx context.Context, olderThan time.Time) (int, error)
 }
 
 // InMemoryService returns an in-memory implementation of the session service.
@@ -91,3 +98,32 @@ type DeleteRequest struct {
 	UserID    string
 	SessionID string
 }
+
+// ListEventsRequest represents a request to list the events of a session.
+type ListEventsRequest struct {
+	AppName   string
+	UserID    string
+	SessionID string
+
+	// PageSize is the maximum number of events returned.
+	// Optional: if zero, all the remaining events are returned.
+	PageSize int
+	// PageToken is the NextPageToken of the previous response. The other
+	// filters must be the same as in the previous request.
+	// Optional: if empty, the first page is returned.
+	PageToken string
+	// After returns events with timestamp >= the given time.
+	// Optional: if zero, the filter is not applied.
+	After time.Time
+	// Authors returns only the events of the given authors.
+	// Optional: if empty, the filter is not applied.
+	Authors []string
+}
+
+// ListEventsResponse represents a response from [Service.ListEvents].
+type ListEventsResponse struct {
+	Events []*Event
+	// NextPageToken is used to request the next page. It is empty if there
+	// are no more events.
+	NextPageToken string
+}
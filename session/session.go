@@ -20,7 +20,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"google.golang.org/genai"
 
+	"google.golang.org/adk/auth"
 	"google.golang.org/adk/model"
 )
 
@@ -154,6 +156,24 @@ type EventActions struct {
 	TransferToAgent string
 	// The agent is escalating to a higher level agent.
 	Escalate bool
+
+	// Authentication configs requested by the tools, keyed by function call
+	// ID. The client is expected to provide the credentials.
+	RequestedAuthConfigs map[string]*auth.Config
+
+	// Compaction, if set, summarizes the earlier events of the session. The
+	// events are kept in the session but the summary replaces them when the
+	// conversation history is passed to the model.
+	Compaction *EventCompaction
+}
+
+// EventCompaction is the summary of the events of a session with timestamps
+// in the [StartTime, EndTime] range.
+type EventCompaction struct {
+	StartTime time.Time
+	EndTime   time.Time
+	// Content is the summary of the events.
+	Content *genai.Content
 }
 
 // Prefixes for defining session's state scopes
@@ -0,0 +1,42 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build cgo
+
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+
+	gormsqlite "gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// open opens the SQLite database at path with the cgo driver.
+func open(path string) (*sql.DB, gorm.Dialector, error) {
+	query := url.Values{}
+	query.Set("_journal_mode", "WAL")
+	query.Set("_busy_timeout", "5000")
+	query.Set("_foreign_keys", "on")
+	db, err := sql.Open("sqlite3", "file:"+path+"?"+query.Encode())
+	if err != nil {
+		return nil, nil, fmt.Errorf("error opening sqlite database: %w", err)
+	}
+	// SQLite allows a single writer, a single connection avoids "database is
+	// locked" errors between concurrent transactions.
+	db.SetMaxOpenConns(1)
+	return db, gormsqlite.New(gormsqlite.Config{Conn: db}), nil
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sqlite provides a [session.Service] persisting sessions in a
+// single SQLite file, for local runs that should keep their sessions between
+// restarts.
+//
+// The schema is created or migrated when the service is opened. Since events
+// accumulate over time, [Compact] can be used to delete old events and
+// reclaim the disk space.
+//
+// The database is accessed with github.com/mattn/go-sqlite3, which requires
+// cgo. In the binaries built without cgo, [NewSessionService] returns an
+// error.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/session/database"
+)
+
+// sqliteService is a database session service with the connection to the
+// SQLite file.
+type sqliteService struct {
+	session.Service
+	db *sql.DB
+}
+
+// NewSessionService opens the SQLite database at path, creating it if
+// needed, and migrates its schema.
+func NewSessionService(path string) (session.Service, error) {
+	if path == "" {
+		return nil, fmt.Errorf("database path is required")
+	}
+	db, dialector, err := open(path)
+	if err != nil {
+		return nil, err
+	}
+	service, err := database.NewSessionService(dialector, &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := database.AutoMigrate(service); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &sqliteService{Service: service, db: db}, nil
+}
+
+// Compact deletes the events selected by req from a service created by
+// [NewSessionService], and then shrinks the database file.
+//
+// It returns the number of deleted events.
+func Compact(ctx context.Context, service session.Service, req database.CompactRequest) (int64, error) {
+	s, ok := service.(*sqliteService)
+	if !ok {
+		return 0, fmt.Errorf("invalid session service type")
+	}
+	deleted, err := database.CompactEvents(ctx, s.Service, req)
+	if err != nil {
+		return 0, err
+	}
+	if deleted > 0 {
+		if _, err := s.db.ExecContext(ctx, "VACUUM"); err != nil {
+			return deleted, fmt.Errorf("failed to vacuum database: %w", err)
+		}
+	}
+	return deleted, nil
+}
+
+// Close closes the database of a service created by [NewSessionService].
+func Close(service session.Service) error {
+	s, ok := service.(*sqliteService)
+	if !ok {
+		return fmt.Errorf("invalid session service type")
+	}
+	return s.db.Close()
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build cgo
+
+package sqlite_test
+
+import (
+	"maps"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/session/database"
+	"google.golang.org/adk/session/sqlite"
+)
+
+func open(t *testing.T, path string) session.Service {
+	t.Helper()
+	s, err := sqlite.NewSessionService(path)
+	if err != nil {
+		t.Fatalf("NewSessionService() failed: %v", err)
+	}
+	t.Cleanup(func() { sqlite.Close(s) })
+	return s
+}
+
+func eventIDs(s session.Session) []string {
+	var ids []string
+	for e := range s.Events().All() {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+func TestSessionService_PersistsAndCompacts(t *testing.T) {
+	ctx := t.Context()
+	path := filepath.Join(t.TempDir(), "sessions.db")
+
+	s := open(t, path)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, id := range []string{"s1", "s2"} {
+		created, err := s.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: id})
+		if err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+		for i := range 4 {
+			ev := session.NewEvent("inv")
+			ev.ID = id + "-e" + string(rune('1'+i))
+			ev.Author = "user"
+			ev.Timestamp = start.Add(time.Duration(i) * time.Hour)
+			ev.Actions.StateDelta["k"] = i
+			if err := s.AppendEvent(ctx, created.Session, ev); err != nil {
+				t.Fatalf("AppendEvent() failed: %v", err)
+			}
+		}
+	}
+	if err := sqlite.Close(s); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	// The sessions are still there after reopening the file.
+	s = open(t, path)
+	got, err := s.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"s1-e1", "s1-e2", "s1-e3", "s1-e4"}, eventIDs(got.Session)); diff != "" {
+		t.Errorf("events mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]any{"k": float64(3)}, maps.Collect(got.Session.State().All())); diff != "" {
+		t.Errorf("state mismatch (-want +got):\n%s", diff)
+	}
+
+	deleted, err := sqlite.Compact(ctx, s, database.CompactRequest{Before: start.Add(3 * time.Hour), KeepRecent: 2})
+	if err != nil {
+		t.Fatalf("Compact() failed: %v", err)
+	}
+	if deleted != 4 {
+		t.Errorf("Compact() deleted %d events, want 4", deleted)
+	}
+	for _, id := range []string{"s1", "s2"} {
+		got, err := s.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: id})
+		if err != nil {
+			t.Fatalf("Get() failed: %v", err)
+		}
+		if diff := cmp.Diff([]string{id + "-e3", id + "-e4"}, eventIDs(got.Session)); diff != "" {
+			t.Errorf("events after compaction mismatch (-want +got):\n%s", diff)
+		}
+		// The state is kept.
+		if diff := cmp.Diff(map[string]any{"k": float64(3)}, maps.Collect(got.Session.State().All())); diff != "" {
+			t.Errorf("state after compaction mismatch (-want +got):\n%s", diff)
+		}
+	}
+
+	// KeepRecent keeps events that are older than Before.
+	deleted, err = sqlite.Compact(ctx, s, database.CompactRequest{Before: start.Add(10 * time.Hour), KeepRecent: 1})
+	if err != nil {
+		t.Fatalf("Compact() failed: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("Compact() deleted %d events, want 2", deleted)
+	}
+	resp, err := s.List(ctx, &session.ListRequest{AppName: "app"})
+	if err != nil {
+		t.Fatalf("List() failed: %v", err)
+	}
+	if got := len(resp.Sessions); got != 2 {
+		t.Errorf("List() returned %d sessions, want 2", got)
+	}
+	last, err := s.ListEvents(ctx, &session.ListEventsRequest{AppName: "app", UserID: "user", SessionID: "s2"})
+	if err != nil {
+		t.Fatalf("ListEvents() failed: %v", err)
+	}
+	var lastIDs []string
+	for _, e := range last.Events {
+		lastIDs = append(lastIDs, e.ID)
+	}
+	if diff := cmp.Diff([]string{"s2-e4"}, lastIDs); diff != "" {
+		t.Errorf("events after second compaction mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewSessionService_RequiresPath(t *testing.T) {
+	if _, err := sqlite.NewSessionService(""); err == nil {
+		t.Errorf("NewSessionService() succeeded, want error")
+	}
+}
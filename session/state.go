@@ -0,0 +1,67 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// StateGet returns the value of key in s as a T. Values that are not stored
+// as a T, e.g. a struct read back from a persistent session service as a
+// map[string]any, are converted through their JSON encoding.
+//
+// It returns an error wrapping [ErrStateKeyNotExist] if the key does not
+// exist, and an error if the value cannot be converted to a T.
+func StateGet[T any](s ReadonlyState, key string) (T, error) {
+	var zero T
+	v, err := s.Get(key)
+	if err != nil {
+		return zero, fmt.Errorf("failed to get state key %q: %w", key, err)
+	}
+	if t, ok := v.(T); ok {
+		return t, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return zero, fmt.Errorf("failed to encode state key %q: %w", key, err)
+	}
+	var t T
+	if err := json.Unmarshal(data, &t); err != nil {
+		return zero, fmt.Errorf("state key %q holds a %T, which cannot be converted to %T: %w", key, v, zero, err)
+	}
+	return t, nil
+}
+
+// StatePut sets key to value in s. The value is stored in its JSON form,
+// e.g. a struct as a map[string]any, so that it reads the same from every
+// session service; use [StateGet] to read it back as a T.
+//
+// When s is the state of a callback or tool context, the change is recorded
+// in the state delta of the event, like with State.Set.
+func StatePut[T any](s State, key string, value T) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode state key %q: %w", key, err)
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("failed to decode state key %q: %w", key, err)
+	}
+	if err := s.Set(key, v); err != nil {
+		return fmt.Errorf("failed to set state key %q: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type preferences struct {
+	Language string   `json:"language"`
+	Topics   []string `json:"topics,omitempty"`
+	Visits   int      `json:"visits"`
+}
+
+func TestStatePutGet(t *testing.T) {
+	s := &state{mu: &sync.RWMutex{}, state: map[string]any{
+		"count":    3,
+		"float":    2.0,
+		"fraction": 2.5,
+		"text":     "hello",
+		// The form of a struct read back from a persistent service.
+		"stored": map[string]any{"language": "en", "visits": float64(2)},
+	}}
+
+	want := preferences{Language: "fr", Topics: []string{"go"}, Visits: 1}
+	if err := StatePut(s, "prefs", want); err != nil {
+		t.Fatalf("StatePut() error = %v", err)
+	}
+	raw, _ := s.Get("prefs")
+	if diff := cmp.Diff(map[string]any{"language": "fr", "topics": []any{"go"}, "visits": float64(1)}, raw); diff != "" {
+		t.Errorf("stored value mismatch (-want +got):\n%s", diff)
+	}
+	got, err := StateGet[preferences](s, "prefs")
+	if err != nil {
+		t.Fatalf("StateGet(prefs) error = %v", err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("StateGet(prefs) mismatch (-want +got):\n%s", diff)
+	}
+
+	stored, err := StateGet[*preferences](s, "stored")
+	if err != nil {
+		t.Fatalf("StateGet(stored) error = %v", err)
+	}
+	if diff := cmp.Diff(&preferences{Language: "en", Visits: 2}, stored); diff != "" {
+		t.Errorf("StateGet(stored) mismatch (-want +got):\n%s", diff)
+	}
+
+	if got, err := StateGet[int](s, "count"); err != nil || got != 3 {
+		t.Errorf("StateGet(count) = %v, %v, want 3", got, err)
+	}
+	if got, err := StateGet[int](s, "float"); err != nil || got != 2 {
+		t.Errorf("StateGet(float) = %v, %v, want 2", got, err)
+	}
+	if got, err := StateGet[string](s, "text"); err != nil || got != "hello" {
+		t.Errorf("StateGet(text) = %q, %v, want hello", got, err)
+	}
+}
+
+func TestStateGet_Errors(t *testing.T) {
+	s := &state{mu: &sync.RWMutex{}, state: map[string]any{
+		"fraction": 2.5,
+		"text":     "hello",
+	}}
+
+	if _, err := StateGet[int](s, "missing"); !errors.Is(err, ErrStateKeyNotExist) {
+		t.Errorf("StateGet(missing) error = %v, want %v", err, ErrStateKeyNotExist)
+	}
+	if _, err := StateGet[int](s, "fraction"); err == nil {
+		t.Errorf("StateGet[int](fraction) succeeded, want error")
+	}
+	if _, err := StateGet[preferences](s, "text"); err == nil {
+		t.Errorf("StateGet[preferences](text) succeeded, want error")
+	}
+	if err := StatePut(s, "fn", func() {}); err == nil {
+		t.Errorf("StatePut(func) succeeded, want error")
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"google.golang.org/genai"
+)
+
+// Usage is the number of tokens used by model calls.
+type Usage struct {
+	// PromptTokens is the number of tokens in the prompts, including cached
+	// content and tool use prompts.
+	PromptTokens int64
+	// OutputTokens is the number of generated tokens, including thoughts.
+	OutputTokens int64
+	// TotalTokens is the total number of tokens billed for the calls.
+	TotalTokens int64
+}
+
+// Add adds the token counts reported in m to u.
+func (u *Usage) Add(m *genai.GenerateContentResponseUsageMetadata) {
+	if m == nil {
+		return
+	}
+	prompt := int64(m.PromptTokenCount) + int64(m.ToolUsePromptTokenCount)
+	output := int64(m.CandidatesTokenCount) + int64(m.ThoughtsTokenCount)
+	total := int64(m.TotalTokenCount)
+	if total == 0 {
+		total = prompt + output
+	}
+	u.PromptTokens += prompt
+	u.OutputTokens += output
+	u.TotalTokens += total
+}
+
+// Usage returns the tokens used by the model call that produced the event.
+// Partial events are not counted, as their usage is reported again in the
+// final event of the stream.
+func (e *Event) Usage() Usage {
+	var u Usage
+	if !e.Partial {
+		u.Add(e.UsageMetadata)
+	}
+	return u
+}
+
+// TotalUsage returns the tokens used by all events.
+func TotalUsage(events Events) Usage {
+	var u Usage
+	for e := range events.All() {
+		if !e.Partial {
+			u.Add(e.UsageMetadata)
+		}
+	}
+	return u
+}
+
+// InvocationUsage returns the tokens used by the events of each invocation,
+// keyed by invocation ID.
+func InvocationUsage(events Events) map[string]Usage {
+	result := make(map[string]Usage)
+	for e := range events.All() {
+		if e.UsageMetadata == nil || e.Partial {
+			continue
+		}
+		u := result[e.InvocationID]
+		u.Add(e.UsageMetadata)
+		result[e.InvocationID] = u
+	}
+	return result
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vertexai
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ResolveAgentEngine returns the resource name of an Agent Engine given as a
+// resource name or as an ID, with its project and location. The project and
+// the location of an ID are read from the GOOGLE_CLOUD_PROJECT and
+// GOOGLE_CLOUD_LOCATION environment variables.
+func ResolveAgentEngine(engine string) (name, project, location string, err error) {
+	if strings.HasPrefix(engine, "projects/") {
+		// The name is "projects/{project}/locations/{location}/reasoningEngines/{id}".
+		parts := strings.Split(engine, "/")
+		if len(parts) != 6 || parts[2] != "locations" || parts[4] != "reasoningEngines" || parts[5] == "" {
+			return "", "", "", fmt.Errorf("invalid agent engine %q, want projects/{project}/locations/{location}/reasoningEngines/{id}", engine)
+		}
+		return engine, parts[1], parts[3], nil
+	}
+	if engine == "" || strings.Contains(engine, "/") {
+		return "", "", "", fmt.Errorf("invalid agent engine %q", engine)
+	}
+	project, location = os.Getenv("GOOGLE_CLOUD_PROJECT"), os.Getenv("GOOGLE_CLOUD_LOCATION")
+	if project == "" || location == "" {
+		return "", "", "", errors.New("GOOGLE_CLOUD_PROJECT and GOOGLE_CLOUD_LOCATION must be set to use an agent engine ID")
+	}
+	return fmt.Sprintf("projects/%s/locations/%s/reasoningEngines/%s", project, location, engine), project, location, nil
+}
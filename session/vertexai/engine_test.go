@@ -0,0 +1,52 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vertexai
+
+import "testing"
+
+func TestResolveAgentEngine(t *testing.T) {
+	t.Setenv("GOOGLE_CLOUD_PROJECT", "env-project")
+	t.Setenv("GOOGLE_CLOUD_LOCATION", "europe-west1")
+	for _, tc := range []struct {
+		engine                  string
+		name, project, location string
+		wantErr                 bool
+	}{
+		{
+			engine:   "projects/p/locations/us-central1/reasoningEngines/123",
+			name:     "projects/p/locations/us-central1/reasoningEngines/123",
+			project:  "p",
+			location: "us-central1",
+		},
+		{
+			engine:   "123",
+			name:     "projects/env-project/locations/europe-west1/reasoningEngines/123",
+			project:  "env-project",
+			location: "europe-west1",
+		},
+		{engine: "projects/p/locations/us-central1", wantErr: true},
+		{engine: "a/b", wantErr: true},
+	} {
+		t.Run(tc.engine, func(t *testing.T) {
+			name, project, location, err := ResolveAgentEngine(tc.engine)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ResolveAgentEngine() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if name != tc.name || project != tc.project || location != tc.location {
+				t.Errorf("ResolveAgentEngine() = %q, %q, %q, want %q, %q, %q", name, project, location, tc.name, tc.project, tc.location)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vertexai
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"time"
+
+	"google.golang.org/api/aiplatform/v1beta1"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/session"
+)
+
+// eventToAPI converts an event to an Agent Engine session event.
+func eventToAPI(event *session.Event) (*aiplatform.GoogleCloudAiplatformV1beta1SessionEvent, error) {
+	apiEvent := &aiplatform.GoogleCloudAiplatformV1beta1SessionEvent{
+		Author:       event.Author,
+		InvocationId: event.InvocationID,
+		Timestamp:    event.Timestamp.UTC().Format(time.RFC3339Nano),
+		ErrorCode:    event.ErrorCode,
+		ErrorMessage: event.ErrorMessage,
+		Actions: &aiplatform.GoogleCloudAiplatformV1beta1EventActions{
+			ArtifactDelta:     event.Actions.ArtifactDelta,
+			SkipSummarization: event.Actions.SkipSummarization,
+			TransferAgent:     event.Actions.TransferToAgent,
+			Escalate:          event.Actions.Escalate,
+		},
+		EventMetadata: &aiplatform.GoogleCloudAiplatformV1beta1EventMetadata{
+			Partial:            event.Partial,
+			TurnComplete:       event.TurnComplete,
+			Interrupted:        event.Interrupted,
+			Branch:             event.Branch,
+			LongRunningToolIds: event.LongRunningToolIDs,
+		},
+	}
+	var err error
+	if apiEvent.Content, err = convert[aiplatform.GoogleCloudAiplatformV1beta1Content](event.Content); err != nil {
+		return nil, fmt.Errorf("failed to encode event content: %w", err)
+	}
+	if apiEvent.EventMetadata.GroundingMetadata, err = convert[aiplatform.GoogleCloudAiplatformV1beta1GroundingMetadata](event.GroundingMetadata); err != nil {
+		return nil, fmt.Errorf("failed to encode event grounding metadata: %w", err)
+	}
+	if apiEvent.Actions.StateDelta, err = rawMessage(event.Actions.StateDelta); err != nil {
+		return nil, fmt.Errorf("failed to encode event state delta: %w", err)
+	}
+	if apiEvent.Actions.RequestedAuthConfigs, err = rawMessage(event.Actions.RequestedAuthConfigs); err != nil {
+		return nil, fmt.Errorf("failed to encode event auth configs: %w", err)
+	}
+	if apiEvent.EventMetadata.CustomMetadata, err = rawMessage(event.CustomMetadata); err != nil {
+		return nil, fmt.Errorf("failed to encode event custom metadata: %w", err)
+	}
+	return apiEvent, nil
+}
+
+// eventFromAPI converts an Agent Engine session event. The ID of the event
+// is the last segment of its resource name.
+func eventFromAPI(apiEvent *aiplatform.GoogleCloudAiplatformV1beta1SessionEvent) (*session.Event, error) {
+	timestamp, err := time.Parse(time.RFC3339Nano, apiEvent.Timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp of event %q: %w", apiEvent.Name, err)
+	}
+	event := &session.Event{
+		ID:           path.Base(apiEvent.Name),
+		Timestamp:    timestamp,
+		InvocationID: apiEvent.InvocationId,
+		Author:       apiEvent.Author,
+	}
+	event.ErrorCode = apiEvent.ErrorCode
+	event.ErrorMessage = apiEvent.ErrorMessage
+	if event.Content, err = convert[genai.Content](apiEvent.Content); err != nil {
+		return nil, fmt.Errorf("failed to decode the content of event %q: %w", apiEvent.Name, err)
+	}
+	if actions := apiEvent.Actions; actions != nil {
+		event.Actions.ArtifactDelta = actions.ArtifactDelta
+		event.Actions.SkipSummarization = actions.SkipSummarization
+		event.Actions.TransferToAgent = actions.TransferAgent
+		event.Actions.Escalate = actions.Escalate
+		if err := unmarshalRaw(actions.StateDelta, &event.Actions.StateDelta); err != nil {
+			return nil, fmt.Errorf("failed to decode the state delta of event %q: %w", apiEvent.Name, err)
+		}
+		if err := unmarshalRaw(actions.RequestedAuthConfigs, &event.Actions.RequestedAuthConfigs); err != nil {
+			return nil, fmt.Errorf("failed to decode the auth configs of event %q: %w", apiEvent.Name, err)
+		}
+	}
+	if md := apiEvent.EventMetadata; md != nil {
+		event.Partial = md.Partial
+		event.TurnComplete = md.TurnComplete
+		event.Interrupted = md.Interrupted
+		event.Branch = md.Branch
+		event.LongRunningToolIDs = md.LongRunningToolIds
+		if event.GroundingMetadata, err = convert[genai.GroundingMetadata](md.GroundingMetadata); err != nil {
+			return nil, fmt.Errorf("failed to decode the grounding metadata of event %q: %w", apiEvent.Name, err)
+		}
+		if err := unmarshalRaw(md.CustomMetadata, &event.CustomMetadata); err != nil {
+			return nil, fmt.Errorf("failed to decode the custom metadata of event %q: %w", apiEvent.Name, err)
+		}
+	}
+	return event, nil
+}
+
+// convert converts src to a T with the same JSON encoding. It returns nil if
+// src is nil.
+func convert[T any, S any](src *S) (*T, error) {
+	if src == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(src)
+	if err != nil {
+		return nil, err
+	}
+	var dst T
+	if err := json.Unmarshal(data, &dst); err != nil {
+		return nil, err
+	}
+	return &dst, nil
+}
+
+// rawMessage encodes the map v, or returns nil if it is empty.
+func rawMessage[M ~map[K]V, K comparable, V any](v M) ([]byte, error) {
+	if len(v) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+func unmarshalRaw(data []byte, v any) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
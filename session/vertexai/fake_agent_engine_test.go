@@ -0,0 +1,206 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vertexai
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/api/aiplatform/v1beta1"
+)
+
+// eventsPageSize is the number of events returned per page by the fake, so
+// that the pagination of the service is exercised.
+const eventsPageSize = 2
+
+// fakeAgentEngine implements the subset of the Agent Engine sessions REST API
+// used by the service.
+type fakeAgentEngine struct {
+	mu       sync.Mutex
+	sessions map[string]*aiplatform.GoogleCloudAiplatformV1beta1Session
+	events   map[string][]*aiplatform.GoogleCloudAiplatformV1beta1SessionEvent
+	// pending are the names of the operations not yet reported as done.
+	pending map[string]bool
+	nextID  int
+	// clock is advanced on every write, so that update times are distinct.
+	clock time.Time
+}
+
+func newFakeAgentEngine() *fakeAgentEngine {
+	return &fakeAgentEngine{
+		sessions: map[string]*aiplatform.GoogleCloudAiplatformV1beta1Session{},
+		events:   map[string][]*aiplatform.GoogleCloudAiplatformV1beta1SessionEvent{},
+		pending:  map[string]bool{},
+		clock:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func (f *fakeAgentEngine) now() string {
+	f.clock = f.clock.Add(time.Second)
+	return f.clock.Format(time.RFC3339Nano)
+}
+
+func (f *fakeAgentEngine) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/v1beta1/")
+	switch {
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/sessions"):
+		var s aiplatform.GoogleCloudAiplatformV1beta1Session
+		if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		id := r.URL.Query().Get("sessionId")
+		if id == "" {
+			f.nextID++
+			id = strconv.Itoa(f.nextID)
+		}
+		s.Name = path + "/" + id
+		if _, ok := f.sessions[s.Name]; ok {
+			writeError(w, http.StatusConflict, "ALREADY_EXISTS")
+			return
+		}
+		s.CreateTime = f.now()
+		s.UpdateTime = s.CreateTime
+		f.sessions[s.Name] = &s
+		// The creation is reported as done on the first poll.
+		op := s.Name + "/operations/create"
+		f.pending[op] = true
+		writeJSON(w, &aiplatform.GoogleLongrunningOperation{Name: op})
+	case r.Method == http.MethodGet && strings.Contains(path, "/operations/"):
+		done := !f.pending[path]
+		delete(f.pending, path)
+		writeJSON(w, &aiplatform.GoogleLongrunningOperation{Name: path, Done: done})
+	case r.Method == http.MethodGet && strings.HasSuffix(path, "/sessions"):
+		f.listSessions(w, path, r.URL.Query().Get("filter"))
+	case r.Method == http.MethodGet && strings.HasSuffix(path, "/events"):
+		f.listEvents(w, strings.TrimSuffix(path, "/events"), r.URL.Query().Get("filter"), r.URL.Query().Get("pageToken"))
+	case r.Method == http.MethodGet:
+		s, ok := f.sessions[path]
+		if !ok {
+			writeError(w, http.StatusNotFound, "NOT_FOUND")
+			return
+		}
+		writeJSON(w, s)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, ":appendEvent"):
+		var event aiplatform.GoogleCloudAiplatformV1beta1SessionEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		f.appendEvent(w, strings.TrimSuffix(path, ":appendEvent"), &event)
+	case r.Method == http.MethodDelete:
+		if _, ok := f.sessions[path]; !ok {
+			writeError(w, http.StatusNotFound, "NOT_FOUND")
+			return
+		}
+		delete(f.sessions, path)
+		delete(f.events, path)
+		writeJSON(w, &aiplatform.GoogleLongrunningOperation{Name: path + "/operations/delete", Done: true})
+	default:
+		writeError(w, http.StatusNotImplemented, "UNIMPLEMENTED")
+	}
+}
+
+func (f *fakeAgentEngine) listSessions(w http.ResponseWriter, engine, filter string) {
+	userID := ""
+	if filter != "" {
+		if _, err := fmt.Sscanf(filter, "user_id=%q", &userID); err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+	}
+	resp := &aiplatform.GoogleCloudAiplatformV1beta1ListSessionsResponse{}
+	for name, s := range f.sessions {
+		if strings.HasPrefix(name, engine+"/") && (userID == "" || s.UserId == userID) {
+			resp.Sessions = append(resp.Sessions, s)
+		}
+	}
+	slices.SortFunc(resp.Sessions, func(a, b *aiplatform.GoogleCloudAiplatformV1beta1Session) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	writeJSON(w, resp)
+}
+
+func (f *fakeAgentEngine) listEvents(w http.ResponseWriter, sessionName, filter, pageToken string) {
+	var after time.Time
+	if filter != "" {
+		var ts string
+		if _, err := fmt.Sscanf(filter, "timestamp>=%q", &ts); err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_ARGUMENT")
+			return
+		}
+		after, _ = time.Parse(time.RFC3339Nano, ts)
+	}
+	var events []*aiplatform.GoogleCloudAiplatformV1beta1SessionEvent
+	for _, event := range f.events[sessionName] {
+		ts, _ := time.Parse(time.RFC3339Nano, event.Timestamp)
+		if !ts.Before(after) {
+			events = append(events, event)
+		}
+	}
+	offset, _ := strconv.Atoi(pageToken)
+	resp := &aiplatform.GoogleCloudAiplatformV1beta1ListEventsResponse{
+		SessionEvents: events[min(offset, len(events)):min(offset+eventsPageSize, len(events))],
+	}
+	if offset+eventsPageSize < len(events) {
+		resp.NextPageToken = strconv.Itoa(offset + eventsPageSize)
+	}
+	writeJSON(w, resp)
+}
+
+func (f *fakeAgentEngine) appendEvent(w http.ResponseWriter, sessionName string, event *aiplatform.GoogleCloudAiplatformV1beta1SessionEvent) {
+	s, ok := f.sessions[sessionName]
+	if !ok {
+		writeError(w, http.StatusNotFound, "NOT_FOUND")
+		return
+	}
+	if event.Actions != nil && len(event.Actions.StateDelta) > 0 {
+		state := map[string]any{}
+		if len(s.SessionState) > 0 {
+			json.Unmarshal(s.SessionState, &state)
+		}
+		var delta map[string]any
+		json.Unmarshal(event.Actions.StateDelta, &delta)
+		for k, v := range delta {
+			state[k] = v
+		}
+		s.SessionState, _ = json.Marshal(state)
+	}
+	f.nextID++
+	event.Name = fmt.Sprintf("%s/events/%d", sessionName, f.nextID)
+	f.events[sessionName] = append(f.events[sessionName], event)
+	s.UpdateTime = f.now()
+	writeJSON(w, struct{}{})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, status string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	fmt.Fprintf(w, `{"error": {"code": %d, "message": %q, "status": %q}}`, code, status, status)
+}
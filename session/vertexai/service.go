@@ -0,0 +1,431 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vertexai provides a [session.Service] backed by the sessions of
+// Vertex AI Agent Engine, so that agents written with ADK in different
+// languages can share the same managed session storage.
+//
+// Sessions are stored under a reasoning engine, the resource name of a
+// session is
+// "projects/{project}/locations/{location}/reasoningEngines/{engine}/sessions/{session}".
+// If [Config.AgentEngineID] is empty, the app name of the requests is used as
+// the reasoning engine, either as its ID or as its full resource name.
+//
+// Agent Engine stores the state per session: keys prefixed with
+// [session.KeyPrefixApp] and [session.KeyPrefixUser] are kept in the session
+// state and aren't shared with the other sessions of the app or the user.
+// Agent Engine assigns the IDs of the stored events, and the event fields
+// without an Agent Engine counterpart, like the usage metadata and the
+// compaction, aren't persisted.
+package vertexai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/api/aiplatform/v1beta1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+
+	"google.golang.org/adk/internal/sessionutils"
+	"google.golang.org/adk/session"
+)
+
+// maxPollInterval is the maximum delay between two polls of a long running
+// operation.
+const maxPollInterval = time.Second
+
+// Config defines the configuration of a Vertex AI Agent Engine session
+// service.
+type Config struct {
+	// ProjectID is the Google Cloud project of the reasoning engine.
+	ProjectID string
+	// Location is the region of the reasoning engine, e.g. "us-central1".
+	Location string
+	// AgentEngineID is the ID of the reasoning engine storing the sessions.
+	// If empty, the app name of each request is used instead.
+	AgentEngineID string
+	// SessionTTL, if set, is the time to live of the created sessions. Agent
+	// Engine deletes them once it is elapsed.
+	SessionTTL time.Duration
+	// ClientOptions are passed to the underlying Vertex AI client.
+	ClientOptions []option.ClientOption
+}
+
+// vertexAIService is a Vertex AI Agent Engine implementation of
+// session.Service.
+type vertexAIService struct {
+	cfg      Config
+	sessions *aiplatform.ProjectsLocationsReasoningEnginesSessionsService
+}
+
+// NewSessionService creates a [session.Service] storing sessions in Vertex
+// AI Agent Engine.
+func NewSessionService(ctx context.Context, cfg Config) (session.Service, error) {
+	if cfg.ProjectID == "" {
+		return nil, errors.New("project ID is required")
+	}
+	if cfg.Location == "" {
+		return nil, errors.New("location is required")
+	}
+	if cfg.SessionTTL < 0 {
+		return nil, fmt.Errorf("invalid session TTL %v", cfg.SessionTTL)
+	}
+	// The reasoning engines are served by the regional endpoints.
+	opts := append([]option.ClientOption{
+		option.WithEndpoint(fmt.Sprintf("https://%s-aiplatform.googleapis.com/", cfg.Location)),
+	}, cfg.ClientOptions...)
+	svc, err := aiplatform.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create vertex ai client: %w", err)
+	}
+	return &vertexAIService{
+		cfg:      cfg,
+		sessions: aiplatform.NewProjectsLocationsReasoningEnginesSessionsService(svc),
+	}, nil
+}
+
+// reasoningEngine returns the resource name of the reasoning engine storing
+// the sessions of appName.
+func (s *vertexAIService) reasoningEngine(appName string) (string, error) {
+	engine := s.cfg.AgentEngineID
+	if engine == "" {
+		engine = appName
+	}
+	if strings.HasPrefix(engine, "projects/") {
+		return engine, nil
+	}
+	if engine == "" || strings.Contains(engine, "/") {
+		return "", fmt.Errorf("invalid reasoning engine %q", engine)
+	}
+	return fmt.Sprintf("projects/%s/locations/%s/reasoningEngines/%s", s.cfg.ProjectID, s.cfg.Location, engine), nil
+}
+
+func (s *vertexAIService) sessionName(appName, sessionID string) (string, error) {
+	if strings.Contains(sessionID, "/") {
+		return "", fmt.Errorf("invalid session ID %q: must not contain '/'", sessionID)
+	}
+	engine, err := s.reasoningEngine(appName)
+	if err != nil {
+		return "", err
+	}
+	return engine + "/sessions/" + sessionID, nil
+}
+
+// Create creates a session in the reasoning engine, implements session.Service
+func (s *vertexAIService) Create(ctx context.Context, req *session.CreateRequest) (*session.CreateResponse, error) {
+	if req.AppName == "" || req.UserID == "" {
+		return nil, fmt.Errorf("app_name and user_id are required")
+	}
+	engine, err := s.reasoningEngine(req.AppName)
+	if err != nil {
+		return nil, err
+	}
+	initialState := make(map[string]any)
+	for key, value := range req.State {
+		if !strings.HasPrefix(key, session.KeyPrefixTemp) {
+			initialState[key] = value
+		}
+	}
+	state, err := json.Marshal(initialState)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode session state: %w", err)
+	}
+	apiSession := &aiplatform.GoogleCloudAiplatformV1beta1Session{
+		UserId:       req.UserID,
+		SessionState: state,
+	}
+	if s.cfg.SessionTTL > 0 {
+		apiSession.Ttl = fmt.Sprintf("%gs", s.cfg.SessionTTL.Seconds())
+	}
+	var callOpts []googleapi.CallOption
+	if req.SessionID != "" {
+		if strings.Contains(req.SessionID, "/") {
+			return nil, fmt.Errorf("invalid session ID %q: must not contain '/'", req.SessionID)
+		}
+		callOpts = append(callOpts, googleapi.QueryParameter("sessionId", req.SessionID))
+	}
+	op, err := s.sessions.Create(engine, apiSession).Context(ctx).Do(callOpts...)
+	if err != nil {
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusConflict {
+			return nil, fmt.Errorf("session %q already exists", req.SessionID)
+		}
+		return nil, fmt.Errorf("error creating session: %w", err)
+	}
+	// The operation is named "{session}/operations/{operation}".
+	name, _, ok := strings.Cut(op.Name, "/operations/")
+	if !ok {
+		return nil, fmt.Errorf("unexpected operation name %q", op.Name)
+	}
+	if err := s.wait(ctx, op); err != nil {
+		return nil, fmt.Errorf("error creating session: %w", err)
+	}
+	created, err := s.sessions.Get(name).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("error on create session: %w", err)
+	}
+	sess, err := sessionFromAPI(req.AppName, created)
+	if err != nil {
+		return nil, err
+	}
+	return &session.CreateResponse{Session: sess}, nil
+}
+
+// wait polls the operation until it is done.
+func (s *vertexAIService) wait(ctx context.Context, op *aiplatform.GoogleLongrunningOperation) error {
+	interval := 100 * time.Millisecond
+	for !op.Done {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+		interval = min(2*interval, maxPollInterval)
+		var err error
+		if op, err = s.sessions.Operations.Get(op.Name).Context(ctx).Do(); err != nil {
+			return fmt.Errorf("failed to poll operation: %w", err)
+		}
+	}
+	if op.Error != nil {
+		return fmt.Errorf("operation %q failed: %s", op.Name, op.Error.Message)
+	}
+	return nil
+}
+
+// getSession returns the session, checking that it belongs to userID.
+func (s *vertexAIService) getSession(ctx context.Context, appName, userID, sessionID string) (*localSession, error) {
+	name, err := s.sessionName(appName, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	apiSession, err := s.sessions.Get(name).Context(ctx).Do()
+	if err != nil {
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound {
+			return nil, fmt.Errorf("session %q not found", sessionID)
+		}
+		return nil, fmt.Errorf("error while fetching session: %w", err)
+	}
+	// Sessions are addressed by ID only, the user is checked to not leak
+	// the sessions of another user.
+	if apiSession.UserId != userID {
+		return nil, fmt.Errorf("session %q not found", sessionID)
+	}
+	return sessionFromAPI(appName, apiSession)
+}
+
+// Get retrieves a session and its events, implements session.Service
+func (s *vertexAIService) Get(ctx context.Context, req *session.GetRequest) (*session.GetResponse, error) {
+	appName, userID, sessionID := req.AppName, req.UserID, req.SessionID
+	if appName == "" || userID == "" || sessionID == "" {
+		return nil, fmt.Errorf("app_name, user_id, session_id are required, got app_name: %q, user_id: %q, session_id: %q", appName, userID, sessionID)
+	}
+	sess, err := s.getSession(ctx, appName, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.listEvents(ctx, sess.name, req.After)
+	if err != nil {
+		return nil, err
+	}
+	if req.NumRecentEvents > 0 && len(events) > req.NumRecentEvents {
+		events = events[len(events)-req.NumRecentEvents:]
+	}
+	sess.events = events
+	return &session.GetResponse{Session: sess}, nil
+}
+
+// ListEvents returns a page of the events of a session, implements session.Service
+func (s *vertexAIService) ListEvents(ctx context.Context, req *session.ListEventsRequest) (*session.ListEventsResponse, error) {
+	appName, userID, sessionID := req.AppName, req.UserID, req.SessionID
+	if appName == "" || userID == "" || sessionID == "" {
+		return nil, fmt.Errorf("app_name, user_id, session_id are required, got app_name: %q, user_id: %q, session_id: %q", appName, userID, sessionID)
+	}
+	offset, err := sessionutils.DecodePageToken(req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	sess, err := s.getSession(ctx, appName, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.listEvents(ctx, sess.name, req.After)
+	if err != nil {
+		return nil, err
+	}
+	// The authors are filtered here, the page token is an offset in the
+	// filtered events as for the other services.
+	if len(req.Authors) > 0 {
+		events = slices.DeleteFunc(events, func(e *session.Event) bool {
+			return !slices.Contains(req.Authors, e.Author)
+		})
+	}
+	events = events[min(offset, len(events)):]
+	resp := &session.ListEventsResponse{Events: events}
+	if req.PageSize > 0 && len(events) > req.PageSize {
+		resp.Events = events[:req.PageSize]
+		resp.NextPageToken = sessionutils.EncodePageToken(offset + req.PageSize)
+	}
+	return resp, nil
+}
+
+// listEvents returns the events of the session with a timestamp >= after,
+// in chronological order.
+func (s *vertexAIService) listEvents(ctx context.Context, sessionName string, after time.Time) ([]*session.Event, error) {
+	call := s.sessions.Events.List(sessionName)
+	if !after.IsZero() {
+		call = call.Filter(fmt.Sprintf("timestamp>=%q", after.UTC().Format(time.RFC3339Nano)))
+	}
+	var events []*session.Event
+	err := call.Pages(ctx, func(resp *aiplatform.GoogleCloudAiplatformV1beta1ListEventsResponse) error {
+		for _, apiEvent := range resp.SessionEvents {
+			event, err := eventFromAPI(apiEvent)
+			if err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching events: %w", err)
+	}
+	slices.SortStableFunc(events, func(a, b *session.Event) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+	return events, nil
+}
+
+// List lists the sessions of an app, and of a user if set, implements session.Service
+func (s *vertexAIService) List(ctx context.Context, req *session.ListRequest) (*session.ListResponse, error) {
+	if req.AppName == "" {
+		return nil, fmt.Errorf("app_name is required, got app_name: %q", req.AppName)
+	}
+	engine, err := s.reasoningEngine(req.AppName)
+	if err != nil {
+		return nil, err
+	}
+	call := s.sessions.List(engine)
+	if req.UserID != "" {
+		call = call.Filter(fmt.Sprintf("user_id=%q", req.UserID))
+	}
+	var sessions []session.Session
+	err = call.Pages(ctx, func(resp *aiplatform.GoogleCloudAiplatformV1beta1ListSessionsResponse) error {
+		for _, apiSession := range resp.Sessions {
+			sess, err := sessionFromAPI(req.AppName, apiSession)
+			if err != nil {
+				return err
+			}
+			sessions = append(sessions, sess)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error while listing sessions: %w", err)
+	}
+	return &session.ListResponse{Sessions: sessions}, nil
+}
+
+// Delete deletes a session and its events, implements session.Service
+func (s *vertexAIService) Delete(ctx context.Context, req *session.DeleteRequest) error {
+	appName, userID, sessionID := req.AppName, req.UserID, req.SessionID
+	if appName == "" || userID == "" || sessionID == "" {
+		return fmt.Errorf("app_name, user_id, session_id are required, got app_name: %q, user_id: %q, session_id: %q", appName, userID, sessionID)
+	}
+	sess, err := s.getSession(ctx, appName, userID, sessionID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.sessions.Delete(sess.name).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("error during session deletion: %w", err)
+	}
+	return nil
+}
+
+// Cleanup deletes the sessions last updated before olderThan, implements
+// session.Service. It requires [Config.AgentEngineID], since the reasoning
+// engines of the apps aren't known otherwise. [Config.SessionTTL] lets Agent
+// Engine expire the sessions instead.
+func (s *vertexAIService) Cleanup(ctx context.Context, olderThan time.Time) (int, error) {
+	if s.cfg.AgentEngineID == "" {
+		return 0, errors.New("cleanup requires an agent engine ID")
+	}
+	engine, err := s.reasoningEngine("")
+	if err != nil {
+		return 0, err
+	}
+	var expired []string
+	err = s.sessions.List(engine).Pages(ctx, func(resp *aiplatform.GoogleCloudAiplatformV1beta1ListSessionsResponse) error {
+		for _, apiSession := range resp.Sessions {
+			updatedAt, err := time.Parse(time.RFC3339Nano, apiSession.UpdateTime)
+			if err != nil {
+				return fmt.Errorf("invalid update time of session %q: %w", apiSession.Name, err)
+			}
+			if updatedAt.Before(olderThan) {
+				expired = append(expired, apiSession.Name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("error while listing expired sessions: %w", err)
+	}
+	for i, name := range expired {
+		if _, err := s.sessions.Delete(name).Context(ctx).Do(); err != nil {
+			return i, fmt.Errorf("error during session deletion: %w", err)
+		}
+	}
+	return len(expired), nil
+}
+
+// AppendEvent persists the event, implements session.Service. Agent Engine
+// applies the state delta of the event to the stored session state.
+func (s *vertexAIService) AppendEvent(ctx context.Context, curSession session.Session, event *session.Event) error {
+	if curSession == nil {
+		return fmt.Errorf("session is nil")
+	}
+	if event == nil {
+		return fmt.Errorf("event is nil")
+	}
+	// ignore partial events
+	if event.Partial {
+		return nil
+	}
+
+	// Trim temp state before persisting
+	event = trimTempDeltaState(event)
+
+	sess, ok := curSession.(*localSession)
+	if !ok {
+		return fmt.Errorf("unexpected session type %T", curSession)
+	}
+	apiEvent, err := eventToAPI(event)
+	if err != nil {
+		return err
+	}
+	if _, err := s.sessions.AppendEvent(sess.name, apiEvent).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("failed to save event: %w", err)
+	}
+	sess.appendEvent(event)
+	return nil
+}
+
+var _ session.Service = (*vertexAIService)(nil)
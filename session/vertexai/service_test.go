@@ -0,0 +1,344 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vertexai
+
+import (
+	"maps"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/api/option"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/session"
+)
+
+const testEngine = "projects/project/locations/us-central1/reasoningEngines/engine"
+
+func newTestService(t *testing.T, cfg Config) (*vertexAIService, *fakeAgentEngine) {
+	t.Helper()
+	fake := newFakeAgentEngine()
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+	cfg.ProjectID = "project"
+	cfg.Location = "us-central1"
+	cfg.ClientOptions = []option.ClientOption{option.WithEndpoint(srv.URL), option.WithoutAuthentication()}
+	s, err := NewSessionService(t.Context(), cfg)
+	if err != nil {
+		t.Fatalf("NewSessionService() failed: %v", err)
+	}
+	return s.(*vertexAIService), fake
+}
+
+func newTestEvent(text, author string, ts time.Time, stateDelta map[string]any) *session.Event {
+	return &session.Event{
+		ID:           text,
+		InvocationID: "inv",
+		Author:       author,
+		Timestamp:    ts,
+		LLMResponse: model.LLMResponse{
+			Content: genai.NewContentFromText(text, genai.RoleModel),
+		},
+		Actions: session.EventActions{StateDelta: stateDelta},
+	}
+}
+
+// eventTexts returns the texts of the events, since Agent Engine assigns
+// their IDs.
+func eventTexts(events []*session.Event) []string {
+	texts := make([]string, 0, len(events))
+	for _, e := range events {
+		texts = append(texts, e.Content.Parts[0].Text)
+	}
+	return texts
+}
+
+func TestNewSessionService_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "no project", cfg: Config{Location: "us-central1"}},
+		{name: "no location", cfg: Config{ProjectID: "project"}},
+		{name: "negative TTL", cfg: Config{ProjectID: "project", Location: "us-central1", SessionTTL: -time.Second}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewSessionService(t.Context(), tc.cfg); err == nil {
+				t.Errorf("NewSessionService() succeeded, want error")
+			}
+		})
+	}
+}
+
+func Test_vertexAIService_Create(t *testing.T) {
+	ctx := t.Context()
+	s, fake := newTestService(t, Config{AgentEngineID: "engine", SessionTTL: time.Hour})
+
+	got, err := s.Create(ctx, &session.CreateRequest{
+		AppName:   "app",
+		UserID:    "user",
+		SessionID: "session",
+		State:     map[string]any{"k": "v", "temp:t": 1},
+	})
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	if got.Session.ID() != "session" {
+		t.Errorf("ID() = %q, want %q", got.Session.ID(), "session")
+	}
+	if diff := cmp.Diff(map[string]any{"k": "v"}, maps.Collect(got.Session.State().All())); diff != "" {
+		t.Errorf("Create() state mismatch (-want +got):\n%s", diff)
+	}
+	if got.Session.LastUpdateTime().IsZero() {
+		t.Errorf("LastUpdateTime() is zero, want the server time")
+	}
+	stored := fake.sessions[testEngine+"/sessions/session"]
+	if stored == nil {
+		t.Fatalf("session was not stored under the reasoning engine, got %v", slices.Collect(maps.Keys(fake.sessions)))
+	}
+	if stored.UserId != "user" || stored.Ttl != "3600s" {
+		t.Errorf("stored session user = %q, ttl = %q, want %q, %q", stored.UserId, stored.Ttl, "user", "3600s")
+	}
+
+	if _, err := s.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "session"}); err == nil {
+		t.Errorf("Create() of an existing session succeeded, want error")
+	}
+	generated, err := s.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	if generated.Session.ID() == "" {
+		t.Errorf("SessionID was not generated on empty user input.")
+	}
+}
+
+func Test_vertexAIService_AppNameAsEngine(t *testing.T) {
+	ctx := t.Context()
+	s, fake := newTestService(t, Config{})
+
+	if _, err := s.Create(ctx, &session.CreateRequest{AppName: "123", UserID: "user", SessionID: "s1"}); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	if _, err := s.Create(ctx, &session.CreateRequest{AppName: testEngine, UserID: "user", SessionID: "s2"}); err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	want := []string{
+		"projects/project/locations/us-central1/reasoningEngines/123/sessions/s1",
+		testEngine + "/sessions/s2",
+	}
+	if diff := cmp.Diff(want, slices.Sorted(maps.Keys(fake.sessions))); diff != "" {
+		t.Errorf("stored sessions mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := s.Create(ctx, &session.CreateRequest{AppName: "a/b", UserID: "user"}); err == nil {
+		t.Errorf("Create() with an invalid app name succeeded, want error")
+	}
+}
+
+func Test_vertexAIService_AppendEventAndGet(t *testing.T) {
+	ctx := t.Context()
+	s, _ := newTestService(t, Config{AgentEngineID: "engine"})
+	created, err := s.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	for i, ev := range []*session.Event{
+		newTestEvent("e1", "user", start, map[string]any{"k": "v1", "temp:t": 1}),
+		newTestEvent("e2", "agent", start.Add(time.Second), map[string]any{"k": "v2", "n": 1}),
+		newTestEvent("e3", "agent", start.Add(2*time.Second), nil),
+	} {
+		if err := s.AppendEvent(ctx, created.Session, ev); err != nil {
+			t.Fatalf("AppendEvent(%d) failed: %v", i, err)
+		}
+	}
+	partial := newTestEvent("partial", "agent", start.Add(3*time.Second), nil)
+	partial.Partial = true
+	if err := s.AppendEvent(ctx, created.Session, partial); err != nil {
+		t.Fatalf("AppendEvent(partial) failed: %v", err)
+	}
+
+	wantLocalState := map[string]any{"k": "v2", "n": 1}
+	if diff := cmp.Diff(wantLocalState, maps.Collect(created.Session.State().All())); diff != "" {
+		t.Errorf("local state mismatch (-want +got):\n%s", diff)
+	}
+
+	got, err := s.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	// Stored values are normalized through JSON.
+	wantState := map[string]any{"k": "v2", "n": float64(1)}
+	if diff := cmp.Diff(wantState, maps.Collect(got.Session.State().All())); diff != "" {
+		t.Errorf("Get() state mismatch (-want +got):\n%s", diff)
+	}
+	gotEvents := slices.Collect(got.Session.Events().All())
+	if diff := cmp.Diff([]string{"e1", "e2", "e3"}, eventTexts(gotEvents)); diff != "" {
+		t.Errorf("Get() events mismatch (-want +got):\n%s", diff)
+	}
+	// Temporary state is not persisted.
+	if diff := cmp.Diff(map[string]any{"k": "v1"}, gotEvents[0].Actions.StateDelta); diff != "" {
+		t.Errorf("Get() first event state delta mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(genai.NewContentFromText("e2", genai.RoleModel), gotEvents[1].Content); diff != "" {
+		t.Errorf("Get() event content mismatch (-want +got):\n%s", diff)
+	}
+	if gotEvents[1].Author != "agent" || gotEvents[1].InvocationID != "inv" || gotEvents[1].ID == "" {
+		t.Errorf("Get() event = %+v, want author, invocation ID and ID set", gotEvents[1])
+	}
+	if !gotEvents[2].Timestamp.Equal(start.Add(2 * time.Second)) {
+		t.Errorf("Get() event timestamp = %v, want %v", gotEvents[2].Timestamp, start.Add(2*time.Second))
+	}
+
+	recent, err := s.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "s1", NumRecentEvents: 2})
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"e2", "e3"}, eventTexts(slices.Collect(recent.Session.Events().All()))); diff != "" {
+		t.Errorf("Get(NumRecentEvents) events mismatch (-want +got):\n%s", diff)
+	}
+	after, err := s.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "s1", After: start.Add(time.Second)})
+	if err != nil {
+		t.Fatalf("Get() failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"e2", "e3"}, eventTexts(slices.Collect(after.Session.Events().All()))); diff != "" {
+		t.Errorf("Get(After) events mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err := s.Get(ctx, &session.GetRequest{AppName: "app", UserID: "user", SessionID: "missing"}); err == nil {
+		t.Errorf("Get() of a missing session succeeded, want error")
+	}
+	if _, err := s.Get(ctx, &session.GetRequest{AppName: "app", UserID: "other", SessionID: "s1"}); err == nil {
+		t.Errorf("Get() of the session of another user succeeded, want error")
+	}
+}
+
+func Test_vertexAIService_ListEvents(t *testing.T) {
+	ctx := t.Context()
+	s, _ := newTestService(t, Config{AgentEngineID: "engine"})
+	created, err := s.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "s1"})
+	if err != nil {
+		t.Fatalf("Create() failed: %v", err)
+	}
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	for i, author := range []string{"user", "agent", "user", "tool", "agent"} {
+		ev := newTestEvent(string(rune('a'+i)), author, start.Add(time.Duration(i)*time.Second), nil)
+		if err := s.AppendEvent(ctx, created.Session, ev); err != nil {
+			t.Fatalf("AppendEvent(%d) failed: %v", i, err)
+		}
+	}
+
+	var pages [][]string
+	token := ""
+	for {
+		resp, err := s.ListEvents(ctx, &session.ListEventsRequest{
+			AppName:   "app",
+			UserID:    "user",
+			SessionID: "s1",
+			PageSize:  2,
+			PageToken: token,
+			After:     start.Add(time.Second),
+			Authors:   []string{"agent", "user"},
+		})
+		if err != nil {
+			t.Fatalf("ListEvents() failed: %v", err)
+		}
+		pages = append(pages, eventTexts(resp.Events))
+		if token = resp.NextPageToken; token == "" {
+			break
+		}
+	}
+	want := [][]string{{"b", "c"}, {"e"}}
+	if diff := cmp.Diff(want, pages); diff != "" {
+		t.Errorf("ListEvents() pages mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_vertexAIService_ListAndDelete(t *testing.T) {
+	ctx := t.Context()
+	s, _ := newTestService(t, Config{AgentEngineID: "engine"})
+	for _, req := range []*session.CreateRequest{
+		{AppName: "app", UserID: "u1", SessionID: "s1"},
+		{AppName: "app", UserID: "u1", SessionID: "s2"},
+		{AppName: "app", UserID: "u2", SessionID: "s3"},
+	} {
+		if _, err := s.Create(ctx, req); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+	}
+
+	list := func(userID string) []string {
+		t.Helper()
+		resp, err := s.List(ctx, &session.ListRequest{AppName: "app", UserID: userID})
+		if err != nil {
+			t.Fatalf("List() failed: %v", err)
+		}
+		var ids []string
+		for _, sess := range resp.Sessions {
+			ids = append(ids, sess.UserID()+"/"+sess.ID())
+		}
+		return ids
+	}
+	if diff := cmp.Diff([]string{"u1/s1", "u1/s2"}, list("u1")); diff != "" {
+		t.Errorf("List(u1) mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"u1/s1", "u1/s2", "u2/s3"}, list("")); diff != "" {
+		t.Errorf("List() mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := s.Delete(ctx, &session.DeleteRequest{AppName: "app", UserID: "u2", SessionID: "s1"}); err == nil {
+		t.Errorf("Delete() of the session of another user succeeded, want error")
+	}
+	if err := s.Delete(ctx, &session.DeleteRequest{AppName: "app", UserID: "u1", SessionID: "s1"}); err != nil {
+		t.Fatalf("Delete() failed: %v", err)
+	}
+	if diff := cmp.Diff([]string{"u1/s2", "u2/s3"}, list("")); diff != "" {
+		t.Errorf("List() after Delete() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func Test_vertexAIService_Cleanup(t *testing.T) {
+	ctx := t.Context()
+
+	t.Run("requires agent engine", func(t *testing.T) {
+		s, _ := newTestService(t, Config{})
+		if _, err := s.Cleanup(ctx, time.Now()); err == nil {
+			t.Errorf("Cleanup() succeeded, want error")
+		}
+	})
+
+	s, fake := newTestService(t, Config{AgentEngineID: "engine"})
+	for _, id := range []string{"old1", "old2", "new"} {
+		if _, err := s.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: id}); err != nil {
+			t.Fatalf("Create() failed: %v", err)
+		}
+	}
+	// The fake advances its clock by one second per write.
+	cutoff := fake.clock
+	n, err := s.Cleanup(ctx, cutoff)
+	if err != nil {
+		t.Fatalf("Cleanup() failed: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Cleanup() = %d, want 2", n)
+	}
+	if diff := cmp.Diff([]string{testEngine + "/sessions/new"}, slices.Collect(maps.Keys(fake.sessions))); diff != "" {
+		t.Errorf("remaining sessions mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vertexai
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/api/aiplatform/v1beta1"
+
+	"google.golang.org/adk/session"
+)
+
+// localSession is a session read from Agent Engine.
+type localSession struct {
+	// name is the resource name of the session.
+	name      string
+	appName   string
+	userID    string
+	sessionID string
+
+	// guards all mutable fields
+	mu        sync.RWMutex
+	events    []*session.Event
+	state     map[string]any
+	updatedAt time.Time
+}
+
+// sessionFromAPI converts a session returned by Agent Engine.
+func sessionFromAPI(appName string, s *aiplatform.GoogleCloudAiplatformV1beta1Session) (*localSession, error) {
+	state := make(map[string]any)
+	if len(s.SessionState) > 0 {
+		if err := json.Unmarshal(s.SessionState, &state); err != nil {
+			return nil, fmt.Errorf("failed to decode the state of session %q: %w", s.Name, err)
+		}
+		if state == nil {
+			state = make(map[string]any)
+		}
+	}
+	updatedAt, err := time.Parse(time.RFC3339Nano, s.UpdateTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid update time of session %q: %w", s.Name, err)
+	}
+	return &localSession{
+		name:      s.Name,
+		appName:   appName,
+		userID:    s.UserId,
+		sessionID: path.Base(s.Name),
+		state:     state,
+		updatedAt: updatedAt,
+	}, nil
+}
+
+func (s *localSession) ID() string {
+	return s.sessionID
+}
+
+func (s *localSession) AppName() string {
+	return s.appName
+}
+
+func (s *localSession) UserID() string {
+	return s.userID
+}
+
+func (s *localSession) State() session.State {
+	return &state{
+		mu:    &s.mu,
+		state: s.state,
+	}
+}
+
+func (s *localSession) Events() session.Events {
+	return events(s.events)
+}
+
+func (s *localSession) LastUpdateTime() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.updatedAt
+}
+
+// appendEvent adds the persisted event to the session and applies its state
+// delta, as Agent Engine does for the stored session.
+func (s *localSession) appendEvent(event *session.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, value := range event.Actions.StateDelta {
+		if strings.HasPrefix(key, session.KeyPrefixTemp) {
+			continue
+		}
+		s.state[key] = value
+	}
+	s.events = append(s.events, event)
+	s.updatedAt = event.Timestamp
+}
+
+type events []*session.Event
+
+func (e events) All() iter.Seq[*session.Event] {
+	return func(yield func(*session.Event) bool) {
+		for _, event := range e {
+			if !yield(event) {
+				return
+			}
+		}
+	}
+}
+
+func (e events) Len() int {
+	return len(e)
+}
+
+func (e events) At(i int) *session.Event {
+	if i >= 0 && i < len(e) {
+		return e[i]
+	}
+	return nil
+}
+
+type state struct {
+	mu    *sync.RWMutex
+	state map[string]any
+}
+
+func (s *state) Get(key string) (any, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	val, ok := s.state[key]
+	if !ok {
+		return nil, session.ErrStateKeyNotExist
+	}
+
+	return val, nil
+}
+
+func (s *state) All() iter.Seq2[string, any] {
+	return func(yield func(key string, val any) bool) {
+		s.mu.RLock()
+
+		for k, v := range s.state {
+			s.mu.RUnlock()
+			if !yield(k, v) {
+				return
+			}
+			s.mu.RLock()
+		}
+
+		s.mu.RUnlock()
+	}
+}
+
+func (s *state) Set(key string, value any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state[key] = value
+	return nil
+}
+
+// trimTempDeltaState removes temporary state delta keys from the event.
+func trimTempDeltaState(event *session.Event) *session.Event {
+	if len(event.Actions.StateDelta) == 0 {
+		return event
+	}
+
+	filteredStateDelta := make(map[string]any)
+	for key, value := range event.Actions.StateDelta {
+		if !strings.HasPrefix(key, session.KeyPrefixTemp) {
+			filteredStateDelta[key] = value
+		}
+	}
+	event.Actions.StateDelta = filteredStateDelta
+
+	return event
+}
+
+var (
+	_ session.Session = (*localSession)(nil)
+	_ session.Events  = (*events)(nil)
+	_ session.State   = (*state)(nil)
+)
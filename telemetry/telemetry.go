@@ -17,8 +17,12 @@
 package telemetry
 
 import (
+	"net/http"
+
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
 
+	"google.golang.org/adk/internal/metrics"
 	internaltelemetry "google.golang.org/adk/internal/telemetry"
 )
 
@@ -30,3 +34,18 @@ import (
 func RegisterSpanProcessor(processor sdktrace.SpanProcessor) {
 	internaltelemetry.AddSpanProcessor(processor)
 }
+
+// SetTracerProvider sets the tracer provider that receives the spans of the
+// runner, agents, model calls and tools, in addition to the local trace
+// provider. If it is not set, the global tracer provider is used.
+func SetTracerProvider(tp trace.TracerProvider) {
+	internaltelemetry.SetTracerProvider(tp)
+}
+
+// MetricsHandler returns an HTTP handler serving the ADK metrics in the
+// Prometheus text format. The metrics count the invocations, agent runs,
+// model calls, with their latency and token usage, and tool calls, with
+// their errors, per agent, model and tool.
+func MetricsHandler() http.Handler {
+	return metrics.Default.Handler()
+}
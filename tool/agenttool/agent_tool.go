@@ -232,14 +232,17 @@ func (t *agentTool) Run(toolCtx tool.Context, args any) (map[string]any, error)
 		if !ok {
 			return nil, fmt.Errorf("internal error: failed to convert to llm agent")
 		}
-		if agentOutputSchema := llminternal.Reveal(internalLlmAgent).OutputSchema; agentOutputSchema != nil {
-			// Assuming schemautils.ValidateOutputSchema parses the JSON string outputText
-			// and validates it against the agentOutputSchema, returning a map[string]any.
-			parsedOutput, err := utils.ValidateOutputSchema(outputText, agentOutputSchema)
+		if state := llminternal.Reveal(internalLlmAgent); state.OutputSchema != nil || state.OutputJSONSchema != nil {
+			// ParseOutput parses the JSON string outputText and validates it
+			// against the agent's output schema.
+			parsedOutput, err := state.ParseOutput(outputText)
 			if err != nil {
 				return nil, fmt.Errorf("output validation failed for sub-agent %s: %w", t.agent.Name(), err)
 			}
-			return parsedOutput, nil
+			if m, ok := parsedOutput.(map[string]any); ok {
+				return m, nil
+			}
+			return map[string]any{"result": parsedOutput}, nil
 		}
 	}
 
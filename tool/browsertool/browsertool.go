@@ -0,0 +1,412 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package browsertool provides tools that let the model browse the web: it
+// can navigate to a page, read its text, click on its elements and take
+// screenshots, which are saved as artifacts.
+//
+// The tools drive a [Browser]. [StartChrome] starts a headless Chrome
+// controlled with the Chrome DevTools Protocol, and [ConnectChrome] connects
+// to a running one. Only the pages of the allowed domains can be visited,
+// and the number of actions of an invocation is limited. When the Browser is
+// also a [RequestBlocker], as Chrome is, the requests to the other domains,
+// e.g. the ones made by scripts, redirects or forms, are blocked too.
+//
+// For example:
+//
+//	b, err := browsertool.StartChrome(ctx, browsertool.ChromeConfig{})
+//	...
+//	defer b.Close()
+//	ts, err := browsertool.New(browsertool.Config{
+//		Browser:        b,
+//		AllowedDomains: []string{"example.com", "*.example.org"},
+//	})
+//
+// The agent using the screenshot tool must run with an artifact service.
+package browsertool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const (
+	// DefaultMaxSteps is the default number of browser actions allowed in an
+	// invocation.
+	DefaultMaxSteps = 20
+	// DefaultMaxTextLength is the default limit of the page text returned to
+	// the model, in bytes.
+	DefaultMaxTextLength = 32 << 10
+)
+
+// Page describes the page displayed by a Browser.
+type Page struct {
+	// URL of the page.
+	URL string `json:"url"`
+	// Title of the page.
+	Title string `json:"title"`
+	// Text is the visible text of the page.
+	Text string `json:"text"`
+}
+
+// Browser is a web browser displaying a single page at a time.
+type Browser interface {
+	// Navigate loads the page at url and waits for it to load.
+	Navigate(ctx context.Context, url string) error
+	// Page returns the page currently displayed.
+	Page(ctx context.Context) (*Page, error)
+	// Click clicks on the first element matching the CSS selector.
+	Click(ctx context.Context, selector string) error
+	// Screenshot returns a PNG image of the visible part of the page.
+	Screenshot(ctx context.Context) ([]byte, error)
+}
+
+// RequestBlocker is implemented by the browsers that can block the requests
+// of their pages.
+type RequestBlocker interface {
+	// BlockRequests makes the browser fail the requests whose URL is not
+	// allowed.
+	BlockRequests(ctx context.Context, allowed func(*url.URL) bool) error
+}
+
+// Config defines the configuration of the browser tools.
+type Config struct {
+	// Browser is driven by the tools. If it implements RequestBlocker, its
+	// requests are restricted to AllowedDomains before its first use.
+	Browser Browser
+	// AllowedDomains lists the hosts of the pages the model may visit. An
+	// entry matches the host exactly, unless it starts with "*.", in which
+	// case it matches any subdomain of the rest of the entry. At least one
+	// domain is required.
+	AllowedDomains []string
+	// MaxSteps is the number of browser actions allowed in an invocation.
+	// Defaults to DefaultMaxSteps.
+	MaxSteps int
+	// MaxTextLength limits the page text returned to the model. Longer
+	// texts are truncated. Defaults to DefaultMaxTextLength.
+	MaxTextLength int
+}
+
+// New creates the toolset named "browser" with the browser_navigate,
+// browser_read, browser_click and browser_screenshot tools.
+func New(cfg Config) (tool.Toolset, error) {
+	if cfg.Browser == nil {
+		return nil, errors.New("browser is required")
+	}
+	if len(cfg.AllowedDomains) == 0 {
+		return nil, errors.New("at least one allowed domain is required")
+	}
+	b := &browser{browser: cfg.Browser, maxSteps: cfg.MaxSteps, maxTextLength: cfg.MaxTextLength}
+	for _, d := range cfg.AllowedDomains {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if d == "" || d == "*." {
+			return nil, fmt.Errorf("invalid allowed domain %q", d)
+		}
+		b.domains = append(b.domains, d)
+	}
+	if b.maxSteps <= 0 {
+		b.maxSteps = DefaultMaxSteps
+	}
+	if b.maxTextLength <= 0 {
+		b.maxTextLength = DefaultMaxTextLength
+	}
+
+	domains := strings.Join(b.domains, ", ")
+	navigate, err := functiontool.New(functiontool.Config{
+		Name:        "browser_navigate",
+		Description: "Opens the page at the given URL in the browser. Only the following domains are allowed: " + domains + ".",
+	}, b.navigate)
+	if err != nil {
+		return nil, err
+	}
+	read, err := functiontool.New(functiontool.Config{
+		Name:        "browser_read",
+		Description: "Returns the URL, title and text of the page displayed in the browser.",
+		IsReadOnly:  true,
+	}, b.read)
+	if err != nil {
+		return nil, err
+	}
+	click, err := functiontool.New(functiontool.Config{
+		Name:        "browser_click",
+		Description: "Clicks on the first element of the page matching the CSS selector.",
+	}, b.click)
+	if err != nil {
+		return nil, err
+	}
+	screenshot, err := functiontool.New(functiontool.Config{
+		Name:        "browser_screenshot",
+		Description: "Takes a screenshot of the page displayed in the browser and saves it as an artifact.",
+		IsReadOnly:  true,
+	}, b.screenshot)
+	if err != nil {
+		return nil, err
+	}
+	tools := []tool.Tool{navigate, read, click, screenshot}
+	return tool.NewToolset("browser", func(agent.ReadonlyContext) ([]tool.Tool, error) {
+		return tools, nil
+	}), nil
+}
+
+// NavigateArgs are the arguments of the browser_navigate tool.
+type NavigateArgs struct {
+	// URL of the page to open.
+	URL string `json:"url"`
+}
+
+// ClickArgs are the arguments of the browser_click tool.
+type ClickArgs struct {
+	// Selector is the CSS selector of the element to click.
+	Selector string `json:"selector"`
+}
+
+// ScreenshotArgs are the arguments of the browser_screenshot tool.
+type ScreenshotArgs struct {
+	// Filename is the name of the artifact. If empty, a unique name is used.
+	Filename string `json:"filename,omitempty"`
+}
+
+// PageInfo is the result of the browser_navigate and browser_click tools.
+type PageInfo struct {
+	// URL of the page displayed after the action.
+	URL string `json:"url"`
+	// Title of the page displayed after the action.
+	Title string `json:"title"`
+}
+
+// ReadResult is the result of the browser_read tool.
+type ReadResult struct {
+	Page
+	// Truncated reports whether the text was truncated.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Screenshot references a screenshot saved as an artifact.
+type Screenshot struct {
+	// Filename is the name of the artifact.
+	Filename string `json:"filename"`
+	// Version is the version of the artifact.
+	Version int64 `json:"version"`
+	// URL of the page.
+	URL string `json:"url"`
+}
+
+type browser struct {
+	browser       Browser
+	domains       []string
+	maxSteps      int
+	maxTextLength int
+
+	mu sync.Mutex
+	// steps are the actions of the recent invocations, by invocation ID.
+	steps map[string]int
+	// invocations are the IDs of the invocations in steps, oldest first.
+	invocations []string
+
+	blockMu sync.Mutex
+	// blocking reports whether the browser blocks the requests outside of
+	// the allowed domains.
+	blocking bool
+}
+
+// maxInvocations is the number of invocations whose steps are counted. The
+// budget of an older invocation starts over if it uses the browser again.
+const maxInvocations = 1000
+
+// step counts an action of the invocation of ctx against the budget. The
+// invocations sharing the browser have their own budgets.
+func (b *browser) step(ctx tool.Context) error {
+	if err := b.blockRequests(ctx); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := ctx.InvocationID()
+	steps, ok := b.steps[id]
+	if !ok {
+		if b.steps == nil {
+			b.steps = make(map[string]int)
+		}
+		b.invocations = append(b.invocations, id)
+		if len(b.invocations) > maxInvocations {
+			delete(b.steps, b.invocations[0])
+			b.invocations = b.invocations[1:]
+		}
+	}
+	if steps >= b.maxSteps {
+		return fmt.Errorf("the budget of %d browser steps of the invocation is exhausted", b.maxSteps)
+	}
+	b.steps[id] = steps + 1
+	return nil
+}
+
+// blockRequests makes the browser block the requests outside of the allowed
+// domains, if it can. It is retried on the next step if it fails.
+func (b *browser) blockRequests(ctx context.Context) error {
+	blocker, ok := b.browser.(RequestBlocker)
+	if !ok {
+		return nil
+	}
+	b.blockMu.Lock()
+	defer b.blockMu.Unlock()
+	if b.blocking {
+		return nil
+	}
+	err := blocker.BlockRequests(ctx, func(u *url.URL) bool {
+		return b.checkURL(u) == nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to block requests outside of the allowed domains: %w", err)
+	}
+	b.blocking = true
+	return nil
+}
+
+func (b *browser) navigate(ctx tool.Context, args NavigateArgs) (PageInfo, error) {
+	u, err := url.Parse(args.URL)
+	if err != nil {
+		return PageInfo{}, fmt.Errorf("invalid url: %w", err)
+	}
+	if err := b.checkURL(u); err != nil {
+		return PageInfo{}, err
+	}
+	if err := b.step(ctx); err != nil {
+		return PageInfo{}, err
+	}
+	if err := b.browser.Navigate(ctx, u.String()); err != nil {
+		return PageInfo{}, fmt.Errorf("failed to navigate: %w", err)
+	}
+	p, err := b.page(ctx)
+	if err != nil {
+		return PageInfo{}, err
+	}
+	return PageInfo{URL: p.URL, Title: p.Title}, nil
+}
+
+func (b *browser) read(ctx tool.Context, _ struct{}) (ReadResult, error) {
+	if err := b.step(ctx); err != nil {
+		return ReadResult{}, err
+	}
+	p, err := b.page(ctx)
+	if err != nil {
+		return ReadResult{}, err
+	}
+	res := ReadResult{Page: *p}
+	if len(res.Text) > b.maxTextLength {
+		res.Text = strings.ToValidUTF8(res.Text[:b.maxTextLength], "")
+		res.Truncated = true
+	}
+	return res, nil
+}
+
+func (b *browser) click(ctx tool.Context, args ClickArgs) (PageInfo, error) {
+	if args.Selector == "" {
+		return PageInfo{}, errors.New("selector is required")
+	}
+	if err := b.step(ctx); err != nil {
+		return PageInfo{}, err
+	}
+	if err := b.browser.Click(ctx, args.Selector); err != nil {
+		return PageInfo{}, fmt.Errorf("failed to click: %w", err)
+	}
+	p, err := b.page(ctx)
+	if err != nil {
+		return PageInfo{}, err
+	}
+	return PageInfo{URL: p.URL, Title: p.Title}, nil
+}
+
+func (b *browser) screenshot(ctx tool.Context, args ScreenshotArgs) (Screenshot, error) {
+	if ctx.Artifacts() == nil {
+		return Screenshot{}, errors.New("artifact service is not configured")
+	}
+	if err := b.step(ctx); err != nil {
+		return Screenshot{}, err
+	}
+	p, err := b.page(ctx)
+	if err != nil {
+		return Screenshot{}, err
+	}
+	img, err := b.browser.Screenshot(ctx)
+	if err != nil {
+		return Screenshot{}, fmt.Errorf("failed to take screenshot: %w", err)
+	}
+	name := args.Filename
+	if name == "" {
+		name = "screenshot_" + uuid.NewString()
+	}
+	if !strings.HasSuffix(name, ".png") {
+		name += ".png"
+	}
+	saved, err := ctx.Artifacts().Save(ctx, name, genai.NewPartFromBytes(img, "image/png"))
+	if err != nil {
+		return Screenshot{}, fmt.Errorf("failed to save screenshot %q: %w", name, err)
+	}
+	return Screenshot{Filename: name, Version: saved.Version, URL: p.URL}, nil
+}
+
+// page returns the displayed page. If the page is not in an allowed domain,
+// e.g. after a redirect, the browser is sent to a blank page and an error is
+// returned.
+func (b *browser) page(ctx context.Context) (*Page, error) {
+	p, err := b.browser.Page(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page: %w", err)
+	}
+	if p.URL == "about:blank" {
+		return p, nil
+	}
+	u, err := url.Parse(p.URL)
+	if err == nil {
+		err = b.checkURL(u)
+	}
+	if err != nil {
+		if navErr := b.browser.Navigate(ctx, "about:blank"); navErr != nil {
+			return nil, fmt.Errorf("failed to leave page %q: %w", p.URL, navErr)
+		}
+		return nil, fmt.Errorf("the browser left the allowed domains: %w", err)
+	}
+	return p, nil
+}
+
+// checkURL returns an error if u may not be visited.
+func (b *browser) checkURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported url scheme %q", u.Scheme)
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, d := range b.domains {
+		if suffix, ok := strings.CutPrefix(d, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return nil
+			}
+			continue
+		}
+		if host == d {
+			return nil
+		}
+	}
+	return fmt.Errorf("domain %q is not allowed", host)
+}
@@ -0,0 +1,298 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package browsertool_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/artifact"
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/browsertool"
+)
+
+// fakeBrowser serves pages from a map. Clicks navigate to the URL of the
+// selector.
+type fakeBrowser struct {
+	pages   map[string]string
+	links   map[string]string
+	current string
+	visited []string
+}
+
+func (b *fakeBrowser) Navigate(ctx context.Context, url string) error {
+	b.current = url
+	b.visited = append(b.visited, url)
+	return nil
+}
+
+func (b *fakeBrowser) Page(ctx context.Context) (*browsertool.Page, error) {
+	if b.current == "" {
+		return &browsertool.Page{URL: "about:blank"}, nil
+	}
+	return &browsertool.Page{URL: b.current, Title: "title of " + b.current, Text: b.pages[b.current]}, nil
+}
+
+func (b *fakeBrowser) Click(ctx context.Context, selector string) error {
+	url, ok := b.links[selector]
+	if !ok {
+		return fmt.Errorf("no element matches %q", selector)
+	}
+	return b.Navigate(ctx, url)
+}
+
+func (b *fakeBrowser) Screenshot(ctx context.Context) ([]byte, error) {
+	return []byte("png of " + b.current), nil
+}
+
+func TestBrowserTools(t *testing.T) {
+	ctx := t.Context()
+	b := &fakeBrowser{
+		pages: map[string]string{
+			"https://example.com/":          "Welcome to example.com. It has a very long text.",
+			"https://docs.example.com/next": "Next page",
+		},
+		links: map[string]string{
+			"#next": "https://docs.example.com/next",
+			"#out":  "https://evil.com/",
+		},
+	}
+	ts, err := browsertool.New(browsertool.Config{
+		Browser:        b,
+		AllowedDomains: []string{"example.com", "*.example.com"},
+		MaxSteps:       5,
+		MaxTextLength:  24,
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	call := func(id, name string, args map[string]any) *genai.Part {
+		return &genai.Part{FunctionCall: &genai.FunctionCall{ID: id, Name: name, Args: args}}
+	}
+	mockModel := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromParts([]*genai.Part{
+			call("nav", "browser_navigate", map[string]any{"url": "https://example.com/"}),
+			call("nav_denied", "browser_navigate", map[string]any{"url": "https://evil.com/"}),
+			call("nav_scheme", "browser_navigate", map[string]any{"url": "file:///etc/passwd"}),
+			call("read", "browser_read", map[string]any{}),
+			call("click", "browser_click", map[string]any{"selector": "#next"}),
+			call("click_out", "browser_click", map[string]any{"selector": "#out"}),
+			call("screenshot", "browser_screenshot", map[string]any{"filename": "shot"}),
+			call("over_budget", "browser_read", map[string]any{}),
+		}, genai.RoleModel),
+		genai.NewContentFromText("done", genai.RoleModel),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:     "browser_agent",
+		Model:    mockModel,
+		Toolsets: []tool.Toolset{ts},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService := session.InMemoryService()
+	artifactService := artifact.InMemoryService()
+	r, err := runner.New(runner.Config{AppName: "app", Agent: a, SessionService: sessionService, ArtifactService: artifactService})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := r.RunAndCollect(ctx, "user", "session", genai.NewContentFromText("browse", genai.RoleUser), agent.RunConfig{})
+	if err != nil {
+		t.Fatalf("RunAndCollect() failed: %v", err)
+	}
+
+	responses := map[string]map[string]any{}
+	for _, ev := range result.Events {
+		if ev.Content == nil {
+			continue
+		}
+		for _, p := range ev.Content.Parts {
+			if p.FunctionResponse != nil {
+				responses[p.FunctionResponse.ID] = p.FunctionResponse.Response
+			}
+		}
+	}
+	wantResults := map[string]map[string]any{
+		"nav":        {"url": "https://example.com/", "title": "title of https://example.com/"},
+		"read":       {"url": "https://example.com/", "title": "title of https://example.com/", "text": "Welcome to example.com. ", "truncated": true},
+		"click":      {"url": "https://docs.example.com/next", "title": "title of https://docs.example.com/next"},
+		"screenshot": {"filename": "shot.png", "version": float64(1), "url": "about:blank"},
+	}
+	wantErrors := map[string]string{
+		"nav_denied":  `domain "evil.com" is not allowed`,
+		"nav_scheme":  `unsupported url scheme "file"`,
+		"click_out":   "the browser left the allowed domains",
+		"over_budget": "budget of 5 browser steps",
+	}
+	for id, want := range wantResults {
+		if diff := cmp.Diff(want, responses[id]); diff != "" {
+			t.Errorf("response %q mismatch (-want +got):\n%s", id, diff)
+		}
+	}
+	for id, want := range wantErrors {
+		if got := fmt.Sprint(responses[id]["error"]); !strings.Contains(got, want) {
+			t.Errorf("response %q error = %q, want it to contain %q", id, got, want)
+		}
+	}
+
+	wantVisited := []string{"https://example.com/", "https://docs.example.com/next", "https://evil.com/", "about:blank"}
+	if diff := cmp.Diff(wantVisited, b.visited); diff != "" {
+		t.Errorf("visited pages mismatch (-want +got):\n%s", diff)
+	}
+	loaded, err := artifactService.Load(ctx, &artifact.LoadRequest{AppName: "app", UserID: "user", SessionID: "session", FileName: "shot.png"})
+	if err != nil {
+		t.Fatalf("failed to load screenshot: %v", err)
+	}
+	if got, want := string(loaded.Part.InlineData.Data), "png of about:blank"; got != want {
+		t.Errorf("screenshot = %q, want %q", got, want)
+	}
+}
+
+func TestBrowserTools_InvocationBudgets(t *testing.T) {
+	b := &fakeBrowser{pages: map[string]string{"https://example.com/": "Welcome"}}
+	ts, err := browsertool.New(browsertool.Config{Browser: b, AllowedDomains: []string{"example.com"}, MaxSteps: 2})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	tools, err := ts.Tools(nil)
+	if err != nil {
+		t.Fatalf("Tools() failed: %v", err)
+	}
+	var read toolinternal.FunctionTool
+	for _, tl := range tools {
+		if tl.Name() == "browser_read" {
+			read = tl.(toolinternal.FunctionTool)
+		}
+	}
+	readAs := func(invocationID string) error {
+		ctx := toolinternal.NewToolContext(icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{InvocationID: invocationID}), "", nil)
+		_, err := read.Run(ctx, map[string]any{})
+		return err
+	}
+
+	// Interleaved invocations do not reset the budgets of each other.
+	for _, id := range []string{"a", "b", "a", "b"} {
+		if err := readAs(id); err != nil {
+			t.Fatalf("read of invocation %q failed: %v", id, err)
+		}
+	}
+	for _, id := range []string{"a", "b"} {
+		if err := readAs(id); err == nil || !strings.Contains(err.Error(), "budget of 2 browser steps") {
+			t.Errorf("read of invocation %q over budget = %v, want budget error", id, err)
+		}
+	}
+	if err := readAs("c"); err != nil {
+		t.Errorf("read of a new invocation failed: %v", err)
+	}
+}
+
+// blockingBrowser is a fakeBrowser implementing RequestBlocker.
+type blockingBrowser struct {
+	fakeBrowser
+	allowed func(*url.URL) bool
+	calls   int
+}
+
+func (b *blockingBrowser) BlockRequests(ctx context.Context, allowed func(*url.URL) bool) error {
+	b.calls++
+	if b.calls == 1 {
+		return errors.New("not ready")
+	}
+	b.allowed = allowed
+	return nil
+}
+
+func TestBrowserTools_BlockRequests(t *testing.T) {
+	b := &blockingBrowser{fakeBrowser: fakeBrowser{pages: map[string]string{"https://example.com/": "Welcome"}}}
+	ts, err := browsertool.New(browsertool.Config{Browser: b, AllowedDomains: []string{"example.com", "*.example.org"}})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	tools, err := ts.Tools(nil)
+	if err != nil {
+		t.Fatalf("Tools() failed: %v", err)
+	}
+	var read toolinternal.FunctionTool
+	for _, tl := range tools {
+		if tl.Name() == "browser_read" {
+			read = tl.(toolinternal.FunctionTool)
+		}
+	}
+	ctx := toolinternal.NewToolContext(icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{InvocationID: "inv"}), "", nil)
+
+	// The browser is not used until its requests are blocked.
+	if _, err := read.Run(ctx, map[string]any{}); err == nil || !strings.Contains(err.Error(), "not ready") {
+		t.Errorf("read with a failing blocker = %v, want error", err)
+	}
+	for range 2 {
+		if _, err := read.Run(ctx, map[string]any{}); err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+	}
+	if b.calls != 2 {
+		t.Errorf("BlockRequests() called %d times, want 2", b.calls)
+	}
+
+	for rawURL, want := range map[string]bool{
+		"https://example.com/page":     true,
+		"https://docs.example.org/":    true,
+		"https://evil.com/":            false,
+		"https://example.com.evil.com": false,
+		"file:///etc/passwd":           false,
+	} {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := b.allowed(u); got != want {
+			t.Errorf("allowed(%q) = %v, want %v", rawURL, got, want)
+		}
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  browsertool.Config
+	}{
+		{name: "no browser", cfg: browsertool.Config{AllowedDomains: []string{"example.com"}}},
+		{name: "no domains", cfg: browsertool.Config{Browser: &fakeBrowser{}}},
+		{name: "invalid domain", cfg: browsertool.Config{Browser: &fakeBrowser{}, AllowedDomains: []string{"*."}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := browsertool.New(tc.cfg); err == nil {
+				t.Errorf("New() succeeded, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,289 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package browsertool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	// pageLoadTimeout bounds the wait for a page to load after an action.
+	// Pages still loading afterwards are used as they are.
+	pageLoadTimeout = 10 * time.Second
+	// pageLoadPollInterval is the interval of the checks of the page state.
+	pageLoadPollInterval = 100 * time.Millisecond
+)
+
+// ChromeConfig defines how Chrome is started.
+type ChromeConfig struct {
+	// ExecPath is the path of the Chrome executable. If empty, the usual
+	// names of Chrome and Chromium are looked up in PATH.
+	ExecPath string
+	// ShowWindow runs Chrome with a window instead of headless.
+	ShowWindow bool
+	// Width and Height of the viewport, in pixels. Default to 1280x800.
+	Width, Height int
+	// Args are additional command line flags, in the "--name" or
+	// "--name=value" form.
+	Args []string
+}
+
+// Chrome is a Browser controlling a Chrome tab with the Chrome DevTools
+// Protocol.
+type Chrome struct {
+	// ctx is the chromedp context of the tab.
+	ctx context.Context
+	// cancel closes the tab, and stops the browser if it was started by
+	// StartChrome.
+	cancel context.CancelFunc
+
+	mu sync.Mutex
+	// allowRequest reports whether a request of the tab can continue. If
+	// nil, the requests are not intercepted.
+	allowRequest func(*url.URL) bool
+}
+
+var _ Browser = (*Chrome)(nil)
+
+// StartChrome starts Chrome and opens a tab to control. The browser is
+// stopped by Close.
+func StartChrome(ctx context.Context, cfg ChromeConfig) (*Chrome, error) {
+	width, height := cfg.Width, cfg.Height
+	if width <= 0 || height <= 0 {
+		width, height = 1280, 800
+	}
+	opts := append(chromedp.DefaultExecAllocatorOptions[:], chromedp.WindowSize(width, height))
+	if cfg.ExecPath != "" {
+		opts = append(opts, chromedp.ExecPath(cfg.ExecPath))
+	}
+	if cfg.ShowWindow {
+		opts = append(opts, chromedp.Flag("headless", false))
+	}
+	for _, arg := range cfg.Args {
+		name, value, ok := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !ok {
+			opts = append(opts, chromedp.Flag(name, true))
+		} else {
+			opts = append(opts, chromedp.Flag(name, value))
+		}
+	}
+
+	// The browser outlives ctx, which only bounds the start.
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(context.Background(), opts...)
+	tabCtx, cancelTab := chromedp.NewContext(allocCtx)
+	c := &Chrome{ctx: tabCtx, cancel: func() {
+		cancelTab()
+		cancelAlloc()
+	}}
+	if err := c.start(ctx, emulation.SetDeviceMetricsOverride(int64(width), int64(height), 1, false)); err != nil {
+		return nil, fmt.Errorf("failed to start chrome: %w", err)
+	}
+	return c, nil
+}
+
+// ConnectChrome opens a tab to control in the running Chrome whose DevTools
+// server is at debugURL, e.g. "http://localhost:9222". Close closes the tab
+// but leaves the browser running.
+func ConnectChrome(ctx context.Context, debugURL string) (*Chrome, error) {
+	allocCtx, cancelAlloc := chromedp.NewRemoteAllocator(context.Background(), debugURL)
+	tabCtx, cancelTab := chromedp.NewContext(allocCtx)
+	c := &Chrome{ctx: tabCtx, cancel: func() {
+		cancelTab()
+		cancelAlloc()
+	}}
+	if err := c.start(ctx); err != nil {
+		return nil, fmt.Errorf("failed to open tab: %w", err)
+	}
+	return c, nil
+}
+
+// start allocates the browser and the tab, and runs the actions. The
+// browser is tied to the context of the first run, so it runs in the tab
+// context itself, canceled if ctx is done before the tab is ready.
+func (c *Chrome) start(ctx context.Context, actions ...chromedp.Action) error {
+	stop := context.AfterFunc(ctx, c.cancel)
+	err := chromedp.Run(c.ctx, actions...)
+	if !stop() || err != nil {
+		c.Close()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	return nil
+}
+
+// Close closes the tab, and stops the browser if it was started by
+// StartChrome.
+func (c *Chrome) Close() error {
+	c.cancel()
+	return nil
+}
+
+// run runs the actions in the tab. They are canceled with ctx.
+func (c *Chrome) run(ctx context.Context, actions ...chromedp.Action) error {
+	runCtx, cancel := context.WithCancel(c.ctx)
+	defer cancel()
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+	if err := chromedp.Run(runCtx, actions...); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	return nil
+}
+
+// Navigate implements Browser.
+func (c *Chrome) Navigate(ctx context.Context, url string) error {
+	err := c.run(ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		_, _, errorText, _, err := page.Navigate(url).Do(ctx)
+		if err != nil {
+			return err
+		}
+		if errorText != "" {
+			return errors.New(errorText)
+		}
+		return nil
+	}))
+	if err != nil {
+		return err
+	}
+	return c.waitLoad(ctx)
+}
+
+// BlockRequests implements RequestBlocker. The requests of the tab are
+// intercepted with the Fetch domain of the protocol.
+func (c *Chrome) BlockRequests(ctx context.Context, allowed func(*url.URL) bool) error {
+	c.mu.Lock()
+	listening := c.allowRequest != nil
+	c.allowRequest = allowed
+	c.mu.Unlock()
+	if !listening {
+		chromedp.ListenTarget(c.ctx, func(ev any) {
+			if paused, ok := ev.(*fetch.EventRequestPaused); ok {
+				// The listeners must not block the processing of the
+				// events.
+				go c.resumeRequest(paused)
+			}
+		})
+	}
+	return c.run(ctx, fetch.Enable().WithPatterns([]*fetch.RequestPattern{
+		{URLPattern: "*", RequestStage: fetch.RequestStageRequest},
+	}))
+}
+
+// resumeRequest continues or fails a request paused by the Fetch domain.
+func (c *Chrome) resumeRequest(paused *fetch.EventRequestPaused) {
+	c.mu.Lock()
+	allowed := c.allowRequest
+	c.mu.Unlock()
+	u, err := url.Parse(paused.Request.URL)
+	var action chromedp.Action = fetch.ContinueRequest(paused.RequestID)
+	if err != nil || !allowed(u) {
+		action = fetch.FailRequest(paused.RequestID, network.ErrorReasonBlockedByClient)
+	}
+	// The request is dropped with the tab if it is closed.
+	_ = chromedp.Run(c.ctx, action)
+}
+
+// Page implements Browser.
+func (c *Chrome) Page(ctx context.Context) (*Page, error) {
+	var p Page
+	if err := c.run(ctx, chromedp.Evaluate(`({url: location.href, title: document.title, text: document.body ? document.body.innerText : ""})`, &p)); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Click implements Browser.
+func (c *Chrome) Click(ctx context.Context, selector string) error {
+	sel, err := json.Marshal(selector)
+	if err != nil {
+		return err
+	}
+	var found bool
+	// The element is looked up once, the query actions of chromedp would
+	// wait for it to appear.
+	expr := fmt.Sprintf(`(() => {
+	const el = document.querySelector(%s);
+	if (!el) return false;
+	el.scrollIntoView({block: "center"});
+	el.click();
+	return true;
+})()`, sel)
+	if err := c.run(ctx, chromedp.Evaluate(expr, &found)); err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("no element matches %q", selector)
+	}
+	// Give a navigation started by the click the time to begin.
+	select {
+	case <-time.After(pageLoadPollInterval):
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return c.waitLoad(ctx)
+}
+
+// Screenshot implements Browser.
+func (c *Chrome) Screenshot(ctx context.Context) ([]byte, error) {
+	var img []byte
+	if err := c.run(ctx, chromedp.CaptureScreenshot(&img)); err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
+// waitLoad waits for the document to be loaded, for at most pageLoadTimeout.
+// The timeout is checked between the calls, which are only interrupted by
+// the cancellation of ctx.
+func (c *Chrome) waitLoad(ctx context.Context) error {
+	deadline := time.Now().Add(pageLoadTimeout)
+	for {
+		var state string
+		if err := c.run(ctx, chromedp.Evaluate("document.readyState", &state)); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+		} else if state == "complete" {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			// A slow page is used as it is.
+			return nil
+		}
+		select {
+		case <-time.After(pageLoadPollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
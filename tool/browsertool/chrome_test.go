@@ -0,0 +1,273 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package browsertool_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/websocket"
+
+	"google.golang.org/adk/tool/browsertool"
+)
+
+// fakeDevTools is a DevTools server with a single tab. It answers the
+// commands sent by Chrome and records the methods of the tab commands, except
+// the ones setting up the tab. Once the Fetch domain is enabled, the
+// navigations are paused until Chrome continues or fails them.
+type fakeDevTools struct {
+	mu      sync.Mutex
+	methods []string
+	url     string
+	fetch   bool
+	blocked bool
+}
+
+// setupMethods are the methods sent by chromedp to set up a tab.
+var setupMethods = []string{
+	"Log.enable", "Network.enable", "Inspector.enable", "Page.enable", "DOM.enable", "CSS.enable",
+	"Runtime.enable", "Page.setLifecycleEventsEnabled",
+}
+
+func (d *fakeDevTools) result(method string, params map[string]any) (any, map[string]any) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch {
+	case method == "Target.createTarget":
+		return map[string]any{"targetId": "tab"}, nil
+	case method == "Target.attachToTarget":
+		return map[string]any{"sessionId": "session"}, nil
+	case strings.HasPrefix(method, "Target."), slices.Contains(setupMethods, method):
+		return map[string]any{}, nil
+	case method == "Runtime.evaluate" && params["expression"] == "self":
+		return map[string]any{"result": map[string]any{"type": "object", "className": "Window"}}, nil
+	}
+	d.methods = append(d.methods, method)
+	switch method {
+	case "Fetch.enable":
+		d.fetch = true
+		return map[string]any{}, nil
+	case "Fetch.continueRequest":
+		d.blocked = false
+		return map[string]any{}, nil
+	case "Fetch.failRequest":
+		d.blocked = params["errorReason"] == "BlockedByClient"
+		return map[string]any{}, nil
+	case "Page.navigate":
+		if d.blocked {
+			return map[string]any{"frameId": "frame", "errorText": "net::ERR_BLOCKED_BY_CLIENT"}, nil
+		}
+		if params["url"] == "https://unreachable.example.com/" {
+			return map[string]any{"frameId": "frame", "errorText": "net::ERR_NAME_NOT_RESOLVED"}, nil
+		}
+		d.url = params["url"].(string)
+		return map[string]any{"frameId": "frame"}, nil
+	case "Page.captureScreenshot":
+		return map[string]any{"data": base64.StdEncoding.EncodeToString([]byte("png"))}, nil
+	case "Runtime.evaluate":
+		expr := params["expression"].(string)
+		var value any
+		switch {
+		case expr == "document.readyState":
+			value = "complete"
+		case strings.Contains(expr, "location.href"):
+			value = map[string]any{"url": d.url, "title": "Example", "text": "Hello"}
+		case strings.Contains(expr, `querySelector("#missing")`):
+			value = false
+		case strings.Contains(expr, "querySelector"):
+			d.url = "https://example.com/next"
+			value = true
+		}
+		return map[string]any{"result": map[string]any{"type": "object", "value": value}}, nil
+	}
+	return nil, map[string]any{"code": -32601, "message": "method not found"}
+}
+
+func (d *fakeDevTools) handler(t *testing.T) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /json/version", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"webSocketDebuggerUrl": "ws://" + r.Host + "/devtools/browser/1"})
+	})
+	mux.HandleFunc("/devtools/browser/1", func(w http.ResponseWriter, r *http.Request) {
+		ws, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade failed: %v", err)
+			return
+		}
+		defer ws.Close()
+		type command struct {
+			ID        int64          `json:"id"`
+			SessionID string         `json:"sessionId,omitempty"`
+			Method    string         `json:"method"`
+			Params    map[string]any `json:"params"`
+		}
+		reply := func(cmd command) error {
+			result, cdpErr := d.result(cmd.Method, cmd.Params)
+			msg := map[string]any{"id": cmd.ID, "sessionId": cmd.SessionID, "result": result}
+			if cdpErr != nil {
+				msg = map[string]any{"id": cmd.ID, "sessionId": cmd.SessionID, "error": cdpErr}
+			}
+			return ws.WriteJSON(msg)
+		}
+		for {
+			var cmd command
+			if err := ws.ReadJSON(&cmd); err != nil {
+				return
+			}
+			if cmd.SessionID != "" {
+				// Events are sent between the results and must be ignored.
+				ws.WriteJSON(map[string]any{"method": "Page.loadEventFired", "sessionId": cmd.SessionID, "params": map[string]any{"timestamp": 1}})
+			}
+			d.mu.Lock()
+			fetch := d.fetch
+			d.mu.Unlock()
+			if fetch && cmd.Method == "Page.navigate" {
+				// The request of the navigation is paused and must be
+				// continued or failed before the navigation completes.
+				ws.WriteJSON(map[string]any{"method": "Fetch.requestPaused", "sessionId": cmd.SessionID, "params": map[string]any{
+					"requestId":    "request",
+					"frameId":      "frame",
+					"resourceType": "Document",
+					"request": map[string]any{
+						"url":             cmd.Params["url"],
+						"method":          "GET",
+						"headers":         map[string]any{},
+						"initialPriority": "VeryHigh",
+						"referrerPolicy":  "strict-origin-when-cross-origin",
+					},
+				}})
+				var resume command
+				if err := ws.ReadJSON(&resume); err != nil {
+					return
+				}
+				if err := reply(resume); err != nil {
+					return
+				}
+			}
+			if err := reply(cmd); err != nil {
+				return
+			}
+		}
+	})
+	return mux
+}
+
+func TestChrome(t *testing.T) {
+	ctx := t.Context()
+	devTools := &fakeDevTools{}
+	srv := httptest.NewServer(devTools.handler(t))
+	defer srv.Close()
+
+	c, err := browsertool.ConnectChrome(ctx, srv.URL)
+	if err != nil {
+		t.Fatalf("ConnectChrome() failed: %v", err)
+	}
+	defer c.Close()
+
+	if err := c.Navigate(ctx, "https://example.com/"); err != nil {
+		t.Fatalf("Navigate() failed: %v", err)
+	}
+	page, err := c.Page(ctx)
+	if err != nil {
+		t.Fatalf("Page() failed: %v", err)
+	}
+	if diff := cmp.Diff(&browsertool.Page{URL: "https://example.com/", Title: "Example", Text: "Hello"}, page); diff != "" {
+		t.Errorf("Page() mismatch (-want +got):\n%s", diff)
+	}
+	if err := c.Click(ctx, "#next"); err != nil {
+		t.Fatalf("Click() failed: %v", err)
+	}
+	page, err = c.Page(ctx)
+	if err != nil {
+		t.Fatalf("Page() failed: %v", err)
+	}
+	if got, want := page.URL, "https://example.com/next"; got != want {
+		t.Errorf("URL after click = %q, want %q", got, want)
+	}
+	img, err := c.Screenshot(ctx)
+	if err != nil {
+		t.Fatalf("Screenshot() failed: %v", err)
+	}
+	if got := string(img); got != "png" {
+		t.Errorf("Screenshot() = %q, want %q", got, "png")
+	}
+
+	if err := c.Click(ctx, "#missing"); err == nil {
+		t.Errorf("Click() on a missing element succeeded, want error")
+	}
+	if err := c.Navigate(ctx, "https://unreachable.example.com/"); err == nil || !strings.Contains(err.Error(), "ERR_NAME_NOT_RESOLVED") {
+		t.Errorf("Navigate() = %v, want ERR_NAME_NOT_RESOLVED", err)
+	}
+
+	devTools.mu.Lock()
+	defer devTools.mu.Unlock()
+	wantMethods := []string{
+		"Page.navigate", "Runtime.evaluate",
+		"Runtime.evaluate",
+		"Runtime.evaluate", "Runtime.evaluate",
+		"Runtime.evaluate",
+		"Page.captureScreenshot",
+		"Runtime.evaluate",
+		"Page.navigate",
+	}
+	if diff := cmp.Diff(wantMethods, devTools.methods); diff != "" {
+		t.Errorf("methods mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestChrome_BlockRequests(t *testing.T) {
+	ctx := t.Context()
+	devTools := &fakeDevTools{}
+	srv := httptest.NewServer(devTools.handler(t))
+	defer srv.Close()
+
+	c, err := browsertool.ConnectChrome(ctx, srv.URL)
+	if err != nil {
+		t.Fatalf("ConnectChrome() failed: %v", err)
+	}
+	defer c.Close()
+
+	err = c.BlockRequests(ctx, func(u *url.URL) bool {
+		return u.Hostname() == "example.com"
+	})
+	if err != nil {
+		t.Fatalf("BlockRequests() failed: %v", err)
+	}
+	if err := c.Navigate(ctx, "https://evil.example.net/"); err == nil || !strings.Contains(err.Error(), "ERR_BLOCKED_BY_CLIENT") {
+		t.Errorf("Navigate() to a blocked domain = %v, want ERR_BLOCKED_BY_CLIENT", err)
+	}
+	if err := c.Navigate(ctx, "https://example.com/"); err != nil {
+		t.Errorf("Navigate() to an allowed domain failed: %v", err)
+	}
+
+	devTools.mu.Lock()
+	defer devTools.mu.Unlock()
+	wantMethods := []string{
+		"Fetch.enable",
+		"Fetch.failRequest", "Page.navigate",
+		"Fetch.continueRequest", "Page.navigate", "Runtime.evaluate",
+	}
+	if diff := cmp.Diff(wantMethods, devTools.methods); diff != "" {
+		t.Errorf("methods mismatch (-want +got):\n%s", diff)
+	}
+}
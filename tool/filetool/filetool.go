@@ -0,0 +1,510 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package filetool provides tools that let the model read and modify the
+// files of a workspace directory, so that coding agents can be built.
+//
+// The toolset has tools to read, write, list, glob and patch files. Paths
+// are relative to the workspace root, and the files outside of it can't be
+// accessed, even through symbolic links. The content of the modified files
+// can be saved as artifacts, which keeps a version of each modification.
+//
+// For example:
+//
+//	ts, err := filetool.New(filetool.Config{
+//		Root:              "/path/to/workspace",
+//		SnapshotArtifacts: true,
+//	})
+package filetool
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const (
+	// DefaultMaxReadBytes is the default limit of the content returned by a
+	// read.
+	DefaultMaxReadBytes = 64 << 10
+	// SnapshotPrefix is the prefix of the names of the artifacts holding the
+	// snapshots of the modified files.
+	SnapshotPrefix = "workspace/"
+
+	// maxEntries limits the number of entries returned by the list and glob
+	// tools.
+	maxEntries = 1000
+)
+
+// Config defines the configuration of the file tools.
+type Config struct {
+	// Root is the workspace directory.
+	Root string
+	// ReadOnly only provides the tools that don't modify the files.
+	ReadOnly bool
+	// MaxReadBytes limits the content returned by a read. Longer contents
+	// are truncated. Defaults to DefaultMaxReadBytes.
+	MaxReadBytes int
+	// SnapshotArtifacts saves the content of each modified file as the
+	// artifact named SnapshotPrefix followed by its path. The agent must
+	// run with an artifact service.
+	SnapshotArtifacts bool
+	// AllowGitWrites allows writing to the .git directories of the
+	// workspace and of its nested repositories. By default it is rejected, since the git hooks and settings written
+	// there could run commands.
+	AllowGitWrites bool
+}
+
+// New creates the toolset named "files" with the read_file, list_files and
+// glob_files tools and, unless it is read-only, the write_file and
+// patch_file tools.
+func New(cfg Config) (tool.Toolset, error) {
+	if cfg.Root == "" {
+		return nil, errors.New("root is required")
+	}
+	root, err := os.OpenRoot(cfg.Root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open workspace: %w", err)
+	}
+	w := &workspace{root: root, maxReadBytes: cfg.MaxReadBytes, snapshot: cfg.SnapshotArtifacts, allowGitWrites: cfg.AllowGitWrites}
+	if w.maxReadBytes <= 0 {
+		w.maxReadBytes = DefaultMaxReadBytes
+	}
+
+	tools, err := w.tools(cfg.ReadOnly)
+	if err != nil {
+		root.Close()
+		return nil, err
+	}
+	return tool.NewToolset("files", func(agent.ReadonlyContext) ([]tool.Tool, error) {
+		return tools, nil
+	}), nil
+}
+
+func (w *workspace) tools(readOnly bool) ([]tool.Tool, error) {
+	read, err := functiontool.New(functiontool.Config{
+		Name:        "read_file",
+		Description: "Reads a file of the workspace. A range of lines can be selected.",
+		IsReadOnly:  true,
+	}, w.read)
+	if err != nil {
+		return nil, err
+	}
+	list, err := functiontool.New(functiontool.Config{
+		Name:        "list_files",
+		Description: "Lists the entries of a directory of the workspace.",
+		IsReadOnly:  true,
+	}, w.list)
+	if err != nil {
+		return nil, err
+	}
+	glob, err := functiontool.New(functiontool.Config{
+		Name: "glob_files",
+		Description: "Returns the paths of the files of the workspace matching a glob pattern. " +
+			`"*" matches any part of a name, and "**" matches any number of directories.`,
+		IsReadOnly: true,
+	}, w.glob)
+	if err != nil {
+		return nil, err
+	}
+	if readOnly {
+		return []tool.Tool{read, list, glob}, nil
+	}
+	write, err := functiontool.New(functiontool.Config{
+		Name:        "write_file",
+		Description: "Writes a file of the workspace, replacing its content. Missing directories are created.",
+	}, w.write)
+	if err != nil {
+		return nil, err
+	}
+	patch, err := functiontool.New(functiontool.Config{
+		Name: "patch_file",
+		Description: "Modifies a file of the workspace by replacing texts. " +
+			"Each text to replace must occur exactly once in the file.",
+	}, w.patch)
+	if err != nil {
+		return nil, err
+	}
+	return []tool.Tool{read, list, glob, write, patch}, nil
+}
+
+// ReadArgs are the arguments of the read_file tool.
+type ReadArgs struct {
+	// Path of the file.
+	Path string `json:"path"`
+	// StartLine is the first line to read, starting at 1.
+	StartLine int `json:"start_line,omitempty"`
+	// EndLine is the last line to read. If zero, the file is read to the
+	// end.
+	EndLine int `json:"end_line,omitempty"`
+}
+
+// ReadResult is the result of the read_file tool.
+type ReadResult struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+	// Truncated reports whether the content was truncated.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// ListArgs are the arguments of the list_files tool.
+type ListArgs struct {
+	// Path of the directory. Defaults to the workspace root.
+	Path string `json:"path,omitempty"`
+}
+
+// Entry is an entry of a directory.
+type Entry struct {
+	Name  string `json:"name"`
+	IsDir bool   `json:"is_dir,omitempty"`
+	// Size of the regular files.
+	Size int64 `json:"size,omitempty"`
+}
+
+// ListResult is the result of the list_files tool.
+type ListResult struct {
+	Entries   []Entry `json:"entries"`
+	Truncated bool    `json:"truncated,omitempty"`
+}
+
+// GlobArgs are the arguments of the glob_files tool.
+type GlobArgs struct {
+	// Pattern matched against the paths of the files, e.g. "**/*.go".
+	Pattern string `json:"pattern"`
+}
+
+// GlobResult is the result of the glob_files tool.
+type GlobResult struct {
+	Paths     []string `json:"paths"`
+	Truncated bool     `json:"truncated,omitempty"`
+}
+
+// WriteArgs are the arguments of the write_file tool.
+type WriteArgs struct {
+	// Path of the file.
+	Path string `json:"path"`
+	// Content of the file.
+	Content string `json:"content"`
+}
+
+// Edit replaces a text of a file.
+type Edit struct {
+	// OldText is the text to replace. It must occur exactly once.
+	OldText string `json:"old_text"`
+	// NewText replaces OldText.
+	NewText string `json:"new_text"`
+}
+
+// PatchArgs are the arguments of the patch_file tool.
+type PatchArgs struct {
+	// Path of the file.
+	Path string `json:"path"`
+	// Edits are applied in order.
+	Edits []Edit `json:"edits"`
+}
+
+// WriteResult is the result of the write_file and patch_file tools.
+type WriteResult struct {
+	Path string `json:"path"`
+	Size int    `json:"size"`
+	// Snapshot is the name of the artifact holding the content of the file,
+	// if snapshots are enabled.
+	Snapshot string `json:"snapshot,omitempty"`
+	// Version of the snapshot.
+	Version int64 `json:"version,omitempty"`
+}
+
+type workspace struct {
+	root           *os.Root
+	maxReadBytes   int
+	snapshot       bool
+	allowGitWrites bool
+}
+
+// localPath converts a slash-separated path relative to the workspace root
+// to a path of the OS. A leading slash is ignored.
+func localPath(p string) (string, error) {
+	clean := path.Clean("/" + p)[1:]
+	if clean == "" {
+		clean = "."
+	}
+	local, err := filepath.Localize(clean)
+	if err != nil {
+		return "", fmt.Errorf("invalid path %q", p)
+	}
+	return local, nil
+}
+
+func (w *workspace) read(ctx tool.Context, args ReadArgs) (ReadResult, error) {
+	p, err := localPath(args.Path)
+	if err != nil {
+		return ReadResult{}, err
+	}
+	if args.StartLine < 0 || args.EndLine < 0 || (args.EndLine > 0 && args.EndLine < args.StartLine) {
+		return ReadResult{}, fmt.Errorf("invalid line range %d-%d", args.StartLine, args.EndLine)
+	}
+	f, err := w.root.Open(p)
+	if err != nil {
+		return ReadResult{}, err
+	}
+	defer f.Close()
+
+	var sb strings.Builder
+	res := ReadResult{Path: filepath.ToSlash(p)}
+	r := bufio.NewReader(f)
+	// The lines are read in chunks of at most the buffer size, so that a
+	// long line doesn't have to be held in memory.
+	for n := 1; args.EndLine == 0 || n <= args.EndLine; {
+		chunk, err := r.ReadSlice('\n')
+		if n >= args.StartLine {
+			if sb.Len()+len(chunk) > w.maxReadBytes {
+				sb.WriteString(strings.ToValidUTF8(string(chunk[:w.maxReadBytes-sb.Len()]), ""))
+				res.Truncated = true
+				break
+			}
+			sb.Write(chunk)
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return ReadResult{}, err
+		}
+		n++
+	}
+	res.Content = sb.String()
+	return res, nil
+}
+
+func (w *workspace) list(ctx tool.Context, args ListArgs) (ListResult, error) {
+	p, err := localPath(args.Path)
+	if err != nil {
+		return ListResult{}, err
+	}
+	f, err := w.root.Open(p)
+	if err != nil {
+		return ListResult{}, err
+	}
+	defer f.Close()
+	dirEntries, err := f.ReadDir(-1)
+	if err != nil {
+		return ListResult{}, err
+	}
+	slices.SortFunc(dirEntries, func(a, b os.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+
+	res := ListResult{Entries: []Entry{}}
+	for _, de := range dirEntries {
+		if len(res.Entries) == maxEntries {
+			res.Truncated = true
+			break
+		}
+		e := Entry{Name: de.Name(), IsDir: de.IsDir()}
+		if info, err := de.Info(); err == nil && de.Type().IsRegular() {
+			e.Size = info.Size()
+		}
+		res.Entries = append(res.Entries, e)
+	}
+	return res, nil
+}
+
+func (w *workspace) glob(ctx tool.Context, args GlobArgs) (GlobResult, error) {
+	pattern := strings.TrimPrefix(path.Clean("/"+args.Pattern), "/")
+	if args.Pattern == "" || pattern == "" {
+		return GlobResult{}, errors.New("pattern is required")
+	}
+	for _, part := range strings.Split(pattern, "/") {
+		if _, err := path.Match(part, ""); err != nil {
+			return GlobResult{}, fmt.Errorf("invalid pattern %q: %w", args.Pattern, err)
+		}
+	}
+
+	res := GlobResult{Paths: []string{}}
+	err := fs.WalkDir(w.root.FS(), ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !matchGlob(pattern, p) {
+			return nil
+		}
+		if len(res.Paths) == maxEntries {
+			res.Truncated = true
+			return fs.SkipAll
+		}
+		res.Paths = append(res.Paths, p)
+		return nil
+	})
+	if err != nil {
+		return GlobResult{}, err
+	}
+	return res, nil
+}
+
+// matchGlob reports whether the slash-separated name matches pattern, whose
+// "**" parts match any number of path elements.
+func matchGlob(pattern, name string) bool {
+	return matchParts(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchParts(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(parts); i++ {
+				if matchParts(pattern[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
+
+// writablePath converts the path of a file to modify to a path of the OS,
+// rejecting the files of the .git directories, including the ones of the
+// nested repositories, unless they are allowed.
+func (w *workspace) writablePath(p string) (string, error) {
+	local, err := localPath(p)
+	if err != nil {
+		return "", err
+	}
+	if w.allowGitWrites {
+		return local, nil
+	}
+	for _, name := range strings.Split(filepath.ToSlash(local), "/") {
+		// The name is compared case-insensitively for the file systems
+		// which are.
+		if strings.EqualFold(name, ".git") {
+			return "", fmt.Errorf("path %q is in a protected .git directory", p)
+		}
+	}
+	return local, nil
+}
+
+func (w *workspace) write(ctx tool.Context, args WriteArgs) (WriteResult, error) {
+	p, err := w.writablePath(args.Path)
+	if err != nil {
+		return WriteResult{}, err
+	}
+	return w.save(ctx, p, []byte(args.Content))
+}
+
+func (w *workspace) patch(ctx tool.Context, args PatchArgs) (WriteResult, error) {
+	p, err := w.writablePath(args.Path)
+	if err != nil {
+		return WriteResult{}, err
+	}
+	if len(args.Edits) == 0 {
+		return WriteResult{}, errors.New("at least one edit is required")
+	}
+	f, err := w.root.Open(p)
+	if err != nil {
+		return WriteResult{}, err
+	}
+	data, err := io.ReadAll(f)
+	f.Close()
+	if err != nil {
+		return WriteResult{}, err
+	}
+	content := string(data)
+	for i, e := range args.Edits {
+		if e.OldText == "" {
+			return WriteResult{}, fmt.Errorf("edit %d: old_text is required", i)
+		}
+		if n := strings.Count(content, e.OldText); n != 1 {
+			return WriteResult{}, fmt.Errorf("edit %d: old_text occurs %d times, want exactly once", i, n)
+		}
+		content = strings.Replace(content, e.OldText, e.NewText, 1)
+	}
+	return w.save(ctx, p, []byte(content))
+}
+
+// save writes the file at p, creating its directories, and saves its
+// snapshot.
+func (w *workspace) save(ctx tool.Context, p string, data []byte) (WriteResult, error) {
+	if p == "." {
+		return WriteResult{}, errors.New("path is required")
+	}
+	if w.snapshot && ctx.Artifacts() == nil {
+		return WriteResult{}, errors.New("artifact service is not configured")
+	}
+	if err := w.mkdirAll(filepath.Dir(p)); err != nil {
+		return WriteResult{}, err
+	}
+	f, err := w.root.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return WriteResult{}, err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return WriteResult{}, err
+	}
+	if err := f.Close(); err != nil {
+		return WriteResult{}, err
+	}
+
+	res := WriteResult{Path: filepath.ToSlash(p), Size: len(data)}
+	if w.snapshot {
+		name := SnapshotPrefix + res.Path
+		saved, err := ctx.Artifacts().Save(ctx, name, genai.NewPartFromBytes(data, "text/plain"))
+		if err != nil {
+			return WriteResult{}, fmt.Errorf("failed to save snapshot %q: %w", name, err)
+		}
+		res.Snapshot, res.Version = name, saved.Version
+	}
+	return res, nil
+}
+
+// mkdirAll creates the directory dir of the workspace and its missing
+// parents.
+func (w *workspace) mkdirAll(dir string) error {
+	if dir == "." {
+		return nil
+	}
+	if err := w.mkdirAll(filepath.Dir(dir)); err != nil {
+		return err
+	}
+	if err := w.root.Mkdir(dir, 0o755); err != nil && !errors.Is(err, fs.ErrExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filetool_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/filetool"
+)
+
+func TestFileTools(t *testing.T) {
+	ctx := t.Context()
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "README.md"), []byte("# Project\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "secret"), filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(root, ".git"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "long.txt"), []byte(strings.Repeat("x", 10000)+"\nend\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	const gitConfig = "[core]\n\tbare = false\n"
+	if err := os.WriteFile(filepath.Join(root, ".git", "config"), []byte(gitConfig), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ts, err := filetool.New(filetool.Config{Root: root, SnapshotArtifacts: true, MaxReadBytes: 30})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	call := func(id, name string, args map[string]any) *genai.Part {
+		return &genai.Part{FunctionCall: &genai.FunctionCall{ID: id, Name: name, Args: args}}
+	}
+	mockModel := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromParts([]*genai.Part{
+			call("write", "write_file", map[string]any{"path": "src/main.go", "content": "package main\n\nfunc main() {}\n"}),
+			call("patch", "patch_file", map[string]any{"path": "/src/main.go", "edits": []any{
+				map[string]any{"old_text": "func main() {}", "new_text": "func main() {\n\tprintln(1)\n}"},
+			}}),
+			call("patch_ambiguous", "patch_file", map[string]any{"path": "src/main.go", "edits": []any{
+				map[string]any{"old_text": "main", "new_text": "other"},
+			}}),
+			call("read", "read_file", map[string]any{"path": "src/main.go", "start_line": 3, "end_line": 4}),
+			call("read_truncated", "read_file", map[string]any{"path": "src/main.go"}),
+			call("read_long_line", "read_file", map[string]any{"path": "long.txt"}),
+			call("read_after_long_line", "read_file", map[string]any{"path": "long.txt", "start_line": 2}),
+			call("list", "list_files", map[string]any{}),
+			call("glob", "glob_files", map[string]any{"pattern": "**/*.go"}),
+			call("read_escape", "read_file", map[string]any{"path": "link"}),
+			call("write_escape", "write_file", map[string]any{"path": "link", "content": "overwritten"}),
+			call("write_git_hook", "write_file", map[string]any{"path": ".git/hooks/post-checkout", "content": "#!/bin/sh\n"}),
+			call("write_git_upper", "write_file", map[string]any{"path": "src/../.GIT/config", "content": "[core]\n"}),
+			call("write_nested_git", "write_file", map[string]any{"path": "vendor/lib/.Git/hooks/pre-commit", "content": "#!/bin/sh\n"}),
+			call("patch_git_config", "patch_file", map[string]any{"path": "/.git/config", "edits": []any{
+				map[string]any{"old_text": "bare = false", "new_text": "fsmonitor = evil"},
+			}}),
+		}, genai.RoleModel),
+		genai.NewContentFromText("done", genai.RoleModel),
+	}}
+	a, err := llmagent.New(llmagent.Config{
+		Name:     "coder",
+		Model:    mockModel,
+		Toolsets: []tool.Toolset{ts},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionService := session.InMemoryService()
+	artifactService := artifact.InMemoryService()
+	r, err := runner.New(runner.Config{AppName: "app", Agent: a, SessionService: sessionService, ArtifactService: artifactService})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := r.RunAndCollect(ctx, "user", "session", genai.NewContentFromText("code", genai.RoleUser), agent.RunConfig{})
+	if err != nil {
+		t.Fatalf("RunAndCollect() failed: %v", err)
+	}
+
+	responses := map[string]map[string]any{}
+	for _, ev := range result.Events {
+		if ev.Content == nil {
+			continue
+		}
+		for _, p := range ev.Content.Parts {
+			if p.FunctionResponse != nil {
+				responses[p.FunctionResponse.ID] = p.FunctionResponse.Response
+			}
+		}
+	}
+	const patched = "package main\n\nfunc main() {\n\tprintln(1)\n}\n"
+	wantResults := map[string]map[string]any{
+		"write":                {"path": "src/main.go", "size": float64(29), "snapshot": "workspace/src/main.go", "version": float64(1)},
+		"patch":                {"path": "src/main.go", "size": float64(len(patched)), "snapshot": "workspace/src/main.go", "version": float64(2)},
+		"read":                 {"path": "src/main.go", "content": "func main() {\n\tprintln(1)\n"},
+		"read_truncated":       {"path": "src/main.go", "content": "package main\n\nfunc main() {\n\tp", "truncated": true},
+		"read_long_line":       {"path": "long.txt", "content": strings.Repeat("x", 30), "truncated": true},
+		"read_after_long_line": {"path": "long.txt", "content": "end\n"},
+		"list": {"entries": []any{
+			map[string]any{"name": ".git", "is_dir": true},
+			map[string]any{"name": "README.md", "size": float64(10)},
+			map[string]any{"name": "link"},
+			map[string]any{"name": "long.txt", "size": float64(10005)},
+			map[string]any{"name": "src", "is_dir": true},
+		}},
+		"glob": {"paths": []any{"src/main.go"}},
+	}
+	wantErrors := map[string]string{
+		"patch_ambiguous":  "occurs 2 times",
+		"read_escape":      "escapes",
+		"write_escape":     "escapes",
+		"write_git_hook":   "protected .git directory",
+		"write_git_upper":  "protected .git directory",
+		"write_nested_git": "protected .git directory",
+		"patch_git_config": "protected .git directory",
+	}
+	for id, want := range wantResults {
+		if diff := cmp.Diff(want, responses[id]); diff != "" {
+			t.Errorf("response %q mismatch (-want +got):\n%s", id, diff)
+		}
+	}
+	for id, want := range wantErrors {
+		if got := fmt.Sprint(responses[id]["error"]); !strings.Contains(got, want) {
+			t.Errorf("response %q error = %q, want it to contain %q", id, got, want)
+		}
+	}
+
+	if got, err := os.ReadFile(filepath.Join(root, "src", "main.go")); err != nil || string(got) != patched {
+		t.Errorf("src/main.go = %q, %v, want %q", got, err, patched)
+	}
+	if got, err := os.ReadFile(filepath.Join(outside, "secret")); err != nil || string(got) != "secret" {
+		t.Errorf("file outside of the workspace = %q, %v, want it unchanged", got, err)
+	}
+	if got, err := os.ReadFile(filepath.Join(root, ".git", "config")); err != nil || string(got) != gitConfig {
+		t.Errorf(".git/config = %q, %v, want it unchanged", got, err)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".git", "hooks")); err == nil {
+		t.Errorf(".git/hooks was created")
+	}
+	if _, err := os.Stat(filepath.Join(root, "vendor")); err == nil {
+		t.Errorf("vendor/lib/.Git/hooks was created")
+	}
+	loaded, err := artifactService.Load(ctx, &artifact.LoadRequest{AppName: "app", UserID: "user", SessionID: "session", FileName: "workspace/src/main.go", Version: 1})
+	if err != nil {
+		t.Fatalf("failed to load snapshot: %v", err)
+	}
+	if got, want := string(loaded.Part.InlineData.Data), "package main\n\nfunc main() {}\n"; got != want {
+		t.Errorf("snapshot version 1 = %q, want %q", got, want)
+	}
+}
+
+func TestNew_ReadOnly(t *testing.T) {
+	ts, err := filetool.New(filetool.Config{Root: t.TempDir(), ReadOnly: true})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	tools, err := ts.Tools(nil)
+	if err != nil {
+		t.Fatalf("Tools() failed: %v", err)
+	}
+	var got []string
+	for _, tl := range tools {
+		got = append(got, tl.Name())
+		if !tool.ReadOnlyPredicate(nil, tl) {
+			t.Errorf("tool %q is not read-only", tl.Name())
+		}
+	}
+	if diff := cmp.Diff([]string{"read_file", "list_files", "glob_files"}, got); diff != "" {
+		t.Errorf("tools mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, cfg := range []filetool.Config{{}, {Root: filepath.Join(t.TempDir(), "missing")}} {
+		if _, err := filetool.New(cfg); err == nil {
+			t.Errorf("New(%+v) succeeded, want error", cfg)
+		}
+	}
+}
@@ -16,11 +16,16 @@
 package functiontool
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"runtime/debug"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"google.golang.org/genai"
 
+	"google.golang.org/adk/internal/logging"
 	"google.golang.org/adk/internal/toolinternal/toolutils"
 	"google.golang.org/adk/internal/typeutil"
 	"google.golang.org/adk/model"
@@ -29,6 +34,15 @@ import (
 
 // FunctionTool: borrow implementation from MCP go.
 
+var (
+	// ErrTimeout is wrapped by the errors of the calls exceeding
+	// Config.Timeout.
+	ErrTimeout = errors.New("tool call timed out")
+	// ErrPanic is wrapped by the errors of the calls whose function
+	// panicked.
+	ErrPanic = errors.New("tool panicked")
+)
+
 // Config is the input to the NewFunctionTool function.
 type Config struct {
 	// The name of this tool.
@@ -36,13 +50,24 @@ type Config struct {
 	// A human-readable description of the tool.
 	Description string
 	// An optional JSON schema object defining the expected parameters for the tool.
-	// If it is nil, FunctionTool tries to infer the schema based on the handler type.
+	// If it is nil, FunctionTool tries to infer the schema based on the handler type,
+	// reading the description, enum, min and max struct tags of the fields,
+	// see [New]. The arguments given by the model are validated against the
+	// schema before the function is called.
 	InputSchema *jsonschema.Schema
 	// An optional JSON schema object defining the structure of the tool's output.
 	// If it is nil, FunctionTool tries to infer the schema based on the handler type.
 	OutputSchema *jsonschema.Schema
 	// IsLongRunning makes a FunctionTool a long-running operation.
 	IsLongRunning bool
+	// IsReadOnly reports that the function only reads data, see
+	// tool.ReadOnlyPredicate.
+	IsReadOnly bool
+	// Timeout, if positive, bounds the duration of a call. The context of
+	// the function is canceled when it expires, and the call fails with an
+	// error wrapping ErrTimeout. The function must honor the cancellation
+	// of its context to be interrupted.
+	Timeout time.Duration
 }
 
 // Func represents a Go function that can be wrapped in a tool.
@@ -51,6 +76,23 @@ type Func[TArgs, TResults any] func(tool.Context, TArgs) (TResults, error)
 
 // New creates a new tool with a name, description, and the provided handler.
 // Input schema is automatically inferred from the input and output types.
+//
+// On top of the json and jsonschema struct tags, the inference reads the
+// following tags of the struct fields:
+//
+//   - description: the description of the field.
+//   - enum: the comma-separated values allowed for the field, or for the
+//     elements of a slice field.
+//   - min, max: the bounds of a number field, of the length of a string
+//     field, or of the number of elements of a slice field.
+//
+// The pointer fields are optional. For example:
+//
+//	type Args struct {
+//		Unit  string `json:"unit" enum:"celsius,fahrenheit"`
+//		Days  int    `json:"days" min:"1" max:"7" description:"number of days of the forecast"`
+//		Hour  *int   `json:"hour" min:"0" max:"23"`
+//	}
 func New[TArgs, TResults any](cfg Config, handler Func[TArgs, TResults]) (tool.Tool, error) {
 	// TODO: How can we improve UX for functions that does not require an argument, returns a simple type value, or returns a no result?
 	//  https://github.com/modelcontextprotocol/go-sdk/discussions/37
@@ -99,6 +141,11 @@ func (f *functionTool[TArgs, TResults]) IsLongRunning() bool {
 	return f.cfg.IsLongRunning
 }
 
+// IsReadOnly implements tool.ReadOnlyTool.
+func (f *functionTool[TArgs, TResults]) IsReadOnly() bool {
+	return f.cfg.IsReadOnly
+}
+
 // ProcessRequest packs the function tool's declaration into the LLM request.
 func (f *functionTool[TArgs, TResults]) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
 	return toolutils.PackTool(req, f)
@@ -130,18 +177,19 @@ func (f *functionTool[TArgs, TResults]) Declaration() *genai.FunctionDeclaration
 }
 
 // Run executes the tool with the provided context and yields events.
+// The panics of the function are returned as errors wrapping ErrPanic, so
+// that they are reported to the model instead of crashing the process.
 func (f *functionTool[TArgs, TResults]) Run(ctx tool.Context, args any) (map[string]any, error) {
 	// TODO: Handle function call request from tc.InvocationContext.
-	// TODO: Handle panic -> convert to error.
 	m, ok := args.(map[string]any)
 	if !ok {
 		return nil, fmt.Errorf("unexpected args type, got: %T", args)
 	}
 	input, err := typeutil.ConvertToWithJSONSchema[map[string]any, TArgs](m, f.inputSchema)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid arguments: %w", err)
 	}
-	output, err := f.handler(ctx, input)
+	output, err := f.call(ctx, input)
 	if err != nil {
 		return nil, err
 	}
@@ -163,6 +211,59 @@ func (f *functionTool[TArgs, TResults]) Run(ctx tool.Context, args any) (map[str
 	return wrappedOutput, nil
 }
 
+// call calls the function, bounded by the configured timeout.
+func (f *functionTool[TArgs, TResults]) call(ctx tool.Context, input TArgs) (output TResults, err error) {
+	if ctx != nil {
+		if err := ctx.Err(); err != nil {
+			return output, err
+		}
+		if f.cfg.Timeout > 0 {
+			timeoutCtx, cancel := context.WithTimeoutCause(ctx, f.cfg.Timeout, ErrTimeout)
+			defer cancel()
+			ctx = &contextOverride{Context: ctx, ctx: timeoutCtx}
+		}
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			var logCtx context.Context = context.Background()
+			if ctx != nil {
+				logCtx = ctx
+			}
+			logging.FromContext(logCtx).Error("tool panicked", "tool", f.Name(), "panic", p, "stack", string(debug.Stack()))
+			err = fmt.Errorf("%w: %v", ErrPanic, p)
+		}
+	}()
+	output, err = f.handler(ctx, input)
+	if ctx != nil && errors.Is(context.Cause(ctx), ErrTimeout) {
+		// The result of a function ignoring the cancellation is dropped too.
+		return output, fmt.Errorf("%w after %v", ErrTimeout, f.cfg.Timeout)
+	}
+	return output, err
+}
+
+// contextOverride is a tool.Context whose context.Context methods are
+// served by ctx.
+type contextOverride struct {
+	tool.Context
+	ctx context.Context
+}
+
+func (c *contextOverride) Deadline() (time.Time, bool) {
+	return c.ctx.Deadline()
+}
+
+func (c *contextOverride) Done() <-chan struct{} {
+	return c.ctx.Done()
+}
+
+func (c *contextOverride) Err() error {
+	return c.ctx.Err()
+}
+
+func (c *contextOverride) Value(key any) any {
+	return c.ctx.Value(key)
+}
+
 // ** NOTE FOR REVIEWERS **
 // Initially I started to borrow the design of the MCP ServerTool and
 // ToolHandlerFor/ToolHandler [1], but got diverged.
@@ -186,7 +287,7 @@ func resolvedSchema[T any](override *jsonschema.Schema) (*jsonschema.Resolved, e
 	if override != nil {
 		return override.Resolve(nil)
 	}
-	schema, err := jsonschema.For[T](nil)
+	schema, err := inferSchema[T]()
 	if err != nil {
 		return nil, err
 	}
@@ -15,18 +15,22 @@
 package functiontool_test
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"iter"
 	"net/http"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/jsonschema-go/jsonschema"
 	"google.golang.org/genai"
 
+	icontext "google.golang.org/adk/internal/context"
 	"google.golang.org/adk/internal/httprr"
 	"google.golang.org/adk/internal/testutil"
 	"google.golang.org/adk/internal/toolinternal"
@@ -508,3 +512,137 @@ func stringify(v any) string {
 	}
 	return string(x)
 }
+
+func TestFunctionTool_TimeoutAndPanic(t *testing.T) {
+	type Args struct {
+		Mode string `json:"mode"`
+	}
+	handler := func(ctx tool.Context, args Args) (map[string]any, error) {
+		switch args.Mode {
+		case "panic":
+			panic("boom")
+		case "block":
+			<-ctx.Done()
+			return nil, ctx.Err()
+		case "ignore":
+			time.Sleep(50 * time.Millisecond)
+		}
+		return map[string]any{"ok": true}, nil
+	}
+	fnTool, err := functiontool.New(functiontool.Config{Name: "fn", Timeout: 10 * time.Millisecond}, handler)
+	if err != nil {
+		t.Fatalf("functiontool.New() failed: %v", err)
+	}
+	funcTool, ok := fnTool.(toolinternal.FunctionTool)
+	if !ok {
+		t.Fatal("fnTool does not implement toolinternal.FunctionTool")
+	}
+
+	canceled, cancel := context.WithCancel(t.Context())
+	cancel()
+	for _, tc := range []struct {
+		name    string
+		ctx     context.Context
+		mode    string
+		want    map[string]any
+		wantErr error
+	}{
+		{name: "ok", ctx: t.Context(), want: map[string]any{"ok": true}},
+		{name: "panic", ctx: t.Context(), mode: "panic", wantErr: functiontool.ErrPanic},
+		{name: "timeout", ctx: t.Context(), mode: "block", wantErr: functiontool.ErrTimeout},
+		{name: "timeout ignored", ctx: t.Context(), mode: "ignore", wantErr: functiontool.ErrTimeout},
+		{name: "canceled", ctx: canceled, wantErr: context.Canceled},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := toolinternal.NewToolContext(icontext.NewInvocationContext(tc.ctx, icontext.InvocationContextParams{}), "", nil)
+			got, err := funcTool.Run(ctx, map[string]any{"mode": tc.mode})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("Run() error = %v, want %v", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFunctionTool_SchemaTags(t *testing.T) {
+	type Window struct {
+		From int `json:"from" min:"0" max:"23"`
+	}
+	type Args struct {
+		City   string   `json:"city" description:"name of the city" min:"1" max:"50"`
+		Unit   string   `json:"unit" enum:"celsius,fahrenheit"`
+		Days   int      `json:"days" enum:"1,3,7"`
+		Fields []string `json:"fields" enum:"wind,rain" max:"2"`
+		Hour   *int     `json:"hour" min:"0" max:"23"`
+		Window *Window  `json:"window"`
+	}
+	fnTool, err := functiontool.New(functiontool.Config{Name: "forecast"}, func(ctx tool.Context, args Args) (map[string]any, error) {
+		return map[string]any{"city": args.City}, nil
+	})
+	if err != nil {
+		t.Fatalf("functiontool.New() failed: %v", err)
+	}
+	funcTool, ok := fnTool.(toolinternal.FunctionTool)
+	if !ok {
+		t.Fatal("fnTool does not implement toolinternal.FunctionTool")
+	}
+
+	got, ok := funcTool.Declaration().ParametersJsonSchema.(*jsonschema.Schema)
+	if !ok {
+		t.Fatalf("ParametersJsonSchema has type %T, want *jsonschema.Schema", funcTool.Declaration().ParametersJsonSchema)
+	}
+	ptr := jsonschema.Ptr[int]
+	num := jsonschema.Ptr[float64]
+	want := &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"city":   {Type: "string", Description: "name of the city", MinLength: ptr(1), MaxLength: ptr(50)},
+			"unit":   {Type: "string", Enum: []any{"celsius", "fahrenheit"}},
+			"days":   {Type: "integer", Enum: []any{int64(1), int64(3), int64(7)}},
+			"fields": {Type: "array", Items: &jsonschema.Schema{Type: "string", Enum: []any{"wind", "rain"}}, MaxItems: ptr(2)},
+			"hour":   {Types: []string{"null", "integer"}, Minimum: num(0), Maximum: num(23)},
+			"window": {
+				Types:                []string{"null", "object"},
+				Properties:           map[string]*jsonschema.Schema{"from": {Type: "integer", Minimum: num(0), Maximum: num(23)}},
+				Required:             []string{"from"},
+				AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+			},
+		},
+		Required:             []string{"city", "unit", "days", "fields"},
+		AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ParametersJsonSchema mismatch (-want +got):\n%s", diff)
+	}
+
+	valid := map[string]any{"city": "Paris", "unit": "celsius", "days": 3, "fields": []any{"rain"}}
+	if _, err := funcTool.Run(nil, valid); err != nil {
+		t.Errorf("Run(%v) failed: %v", valid, err)
+	}
+	for _, args := range []map[string]any{
+		{"city": "Paris", "unit": "kelvin", "days": 3, "fields": []any{}},
+		{"city": "Paris", "unit": "celsius", "days": 2, "fields": []any{}},
+		{"city": "", "unit": "celsius", "days": 3, "fields": []any{}},
+		{"city": "Paris", "unit": "celsius", "days": 3, "fields": []any{"snow"}},
+		{"city": "Paris", "unit": "celsius", "days": 3, "fields": []any{}, "hour": 24},
+		{"city": "Paris", "unit": "celsius", "days": 3, "fields": []any{}, "window": map[string]any{"from": -1}},
+	} {
+		if _, err := funcTool.Run(nil, args); err == nil {
+			t.Errorf("Run(%v) succeeded, want a validation error", args)
+		}
+	}
+}
+
+func TestFunctionTool_InvalidSchemaTags(t *testing.T) {
+	type Args struct {
+		Days int `json:"days" enum:"one,two"`
+	}
+	_, err := functiontool.New(functiontool.Config{Name: "forecast"}, func(ctx tool.Context, args Args) (map[string]any, error) {
+		return nil, nil
+	})
+	if err == nil {
+		t.Errorf("functiontool.New() succeeded, want error")
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package functiontool
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// inferSchema infers the JSON schema of T with jsonschema.For, and updates
+// it with the struct tags read by New.
+func inferSchema[T any]() (*jsonschema.Schema, error) {
+	s, err := jsonschema.For[T](nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyFieldTags(reflect.TypeFor[T](), s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// applyFieldTags updates s, the schema inferred for t, with the struct tags
+// of the fields of t and of its nested types.
+func applyFieldTags(t reflect.Type, s *jsonschema.Schema) error {
+	if s == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return applyFieldTags(t.Elem(), s.Items)
+	case reflect.Map:
+		return applyFieldTags(t.Elem(), s.AdditionalProperties)
+	case reflect.Struct:
+	default:
+		return nil
+	}
+	for _, field := range reflect.VisibleFields(t) {
+		if field.Anonymous || !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			if n, _, _ := strings.Cut(tag, ","); n != "" {
+				name = n
+			}
+		}
+		fs, ok := s.Properties[name]
+		if !ok {
+			continue
+		}
+		if err := applyFieldTags(field.Type, fs); err != nil {
+			return err
+		}
+		if err := applyTags(field, fs); err != nil {
+			return fmt.Errorf("field %s.%s: %w", t, field.Name, err)
+		}
+		if field.Type.Kind() == reflect.Pointer {
+			s.Required = slices.DeleteFunc(s.Required, func(r string) bool { return r == name })
+		}
+	}
+	return nil
+}
+
+// applyTags updates fs, the schema of field, with the tags of field.
+func applyTags(field reflect.StructField, fs *jsonschema.Schema) error {
+	if desc, ok := field.Tag.Lookup("description"); ok {
+		fs.Description = desc
+	}
+	t := field.Type
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		nullable = true
+	}
+	if enum, ok := field.Tag.Lookup("enum"); ok {
+		target, kind := fs, t.Kind()
+		if kind == reflect.Slice || kind == reflect.Array {
+			target, kind, nullable = fs.Items, t.Elem().Kind(), false
+		}
+		if target == nil {
+			return fmt.Errorf("enum tag on unsupported type %s", field.Type)
+		}
+		for _, v := range strings.Split(enum, ",") {
+			value, err := parseValue(kind, strings.TrimSpace(v))
+			if err != nil {
+				return fmt.Errorf("invalid enum value %q: %w", v, err)
+			}
+			target.Enum = append(target.Enum, value)
+		}
+		if nullable {
+			target.Enum = append(target.Enum, nil)
+		}
+	}
+	for _, bound := range []string{"min", "max"} {
+		tag, ok := field.Tag.Lookup(bound)
+		if !ok {
+			continue
+		}
+		if err := applyBound(t, fs, bound == "min", tag); err != nil {
+			return fmt.Errorf("invalid %s tag %q: %w", bound, tag, err)
+		}
+	}
+	return nil
+}
+
+// applyBound sets the lower or upper bound of the value, the length or the
+// number of elements of fs, depending on the kind of t.
+func applyBound(t reflect.Type, fs *jsonschema.Schema, lower bool, tag string) error {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(tag, 64)
+		if err != nil {
+			return err
+		}
+		if lower {
+			fs.Minimum = &v
+		} else {
+			fs.Maximum = &v
+		}
+		return nil
+	case reflect.String, reflect.Slice, reflect.Array:
+		n, err := strconv.Atoi(tag)
+		if err != nil {
+			return err
+		}
+		switch {
+		case t.Kind() == reflect.String && lower:
+			fs.MinLength = &n
+		case t.Kind() == reflect.String:
+			fs.MaxLength = &n
+		case lower:
+			fs.MinItems = &n
+		default:
+			fs.MaxItems = &n
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported type %s", t)
+}
+
+// parseValue parses the enum value v of a field of the given kind.
+func parseValue(kind reflect.Kind, v string) (any, error) {
+	switch kind {
+	case reflect.String:
+		return v, nil
+	case reflect.Bool:
+		return strconv.ParseBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(v, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(v, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(v, 64)
+	}
+	return nil, fmt.Errorf("unsupported kind %s", kind)
+}
@@ -48,3 +48,8 @@ func (s GoogleSearch) ProcessRequest(ctx tool.Context, req *model.LLMRequest) er
 func (t GoogleSearch) IsLongRunning() bool {
 	return false
 }
+
+// IsBuiltin reports that the tool is run by the model itself.
+func (t GoogleSearch) IsBuiltin() bool {
+	return true
+}
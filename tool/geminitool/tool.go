@@ -72,6 +72,11 @@ func (t *geminiTool) IsLongRunning() bool {
 	return false
 }
 
+// IsBuiltin reports that the tool is run by the model itself.
+func (t *geminiTool) IsBuiltin() bool {
+	return true
+}
+
 func setTool(req *model.LLMRequest, t *genai.Tool) error {
 	if req == nil {
 		return fmt.Errorf("llm request is nil")
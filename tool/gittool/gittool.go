@@ -0,0 +1,443 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gittool provides tools that let the model manage the changes of a
+// git repository: show the status, the diff and the log, commit and switch
+// branches.
+//
+// The tools run the git command in a workspace directory, usually the root
+// of the file tools, see the filetool package. The commits are made with the
+// configured author identity, and the hooks of the repository are not run,
+// since the model may be able to write them. For the same reason, the tools
+// refuse to run in the repositories configuring filter drivers, whose
+// commands git would run on the files. In dry-run mode, the tools
+// which modify the repository return the commands they would run instead.
+//
+// For example:
+//
+//	ts, err := gittool.New(gittool.Config{
+//		Root:        "/path/to/workspace",
+//		AuthorName:  "Coding Agent",
+//		AuthorEmail: "agent@example.com",
+//	})
+package gittool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const (
+	// DefaultMaxOutputBytes is the default limit of the diffs returned to
+	// the model.
+	DefaultMaxOutputBytes = 64 << 10
+	// DefaultLogCount is the number of commits returned by git_log when the
+	// model does not ask for a number.
+	DefaultLogCount = 10
+)
+
+// Config defines the configuration of the git tools.
+type Config struct {
+	// Root is the workspace directory, within a git repository.
+	Root string
+	// GitPath is the path of the git executable. Defaults to "git" looked
+	// up in PATH.
+	GitPath string
+	// AuthorName and AuthorEmail identify the author and committer of the
+	// commits. If empty, the identity configured in git is used.
+	AuthorName, AuthorEmail string
+	// DryRun makes git_commit and git_branch return the commands they would
+	// run instead of running them.
+	DryRun bool
+	// MaxOutputBytes limits the diffs returned to the model. Longer diffs
+	// are truncated. Defaults to DefaultMaxOutputBytes.
+	MaxOutputBytes int
+}
+
+// New creates the toolset named "git" with the git_status, git_diff,
+// git_log, git_commit and git_branch tools.
+func New(cfg Config) (tool.Toolset, error) {
+	if cfg.Root == "" {
+		return nil, errors.New("root is required")
+	}
+	if info, err := os.Stat(cfg.Root); err != nil {
+		return nil, fmt.Errorf("invalid root: %w", err)
+	} else if !info.IsDir() {
+		return nil, fmt.Errorf("root %q is not a directory", cfg.Root)
+	}
+	r := &repo{
+		root:     cfg.Root,
+		git:      cfg.GitPath,
+		dryRun:   cfg.DryRun,
+		maxBytes: cfg.MaxOutputBytes,
+	}
+	if r.git == "" {
+		r.git = "git"
+	}
+	if r.maxBytes <= 0 {
+		r.maxBytes = DefaultMaxOutputBytes
+	}
+	if cfg.AuthorName != "" {
+		r.env = append(r.env, "GIT_AUTHOR_NAME="+cfg.AuthorName, "GIT_COMMITTER_NAME="+cfg.AuthorName)
+	}
+	if cfg.AuthorEmail != "" {
+		r.env = append(r.env, "GIT_AUTHOR_EMAIL="+cfg.AuthorEmail, "GIT_COMMITTER_EMAIL="+cfg.AuthorEmail)
+	}
+
+	tools, err := r.tools()
+	if err != nil {
+		return nil, err
+	}
+	return tool.NewToolset("git", func(agent.ReadonlyContext) ([]tool.Tool, error) {
+		return tools, nil
+	}), nil
+}
+
+func (r *repo) tools() ([]tool.Tool, error) {
+	status, err := functiontool.New(functiontool.Config{
+		Name:        "git_status",
+		Description: "Returns the current branch and the changed files of the git repository.",
+		IsReadOnly:  true,
+	}, r.status)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := functiontool.New(functiontool.Config{
+		Name:        "git_diff",
+		Description: "Returns the diff of the uncommitted changes of the git repository.",
+		IsReadOnly:  true,
+	}, r.diff)
+	if err != nil {
+		return nil, err
+	}
+	log, err := functiontool.New(functiontool.Config{
+		Name:        "git_log",
+		Description: "Returns the latest commits of the current branch.",
+		IsReadOnly:  true,
+	}, r.log)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := functiontool.New(functiontool.Config{
+		Name:        "git_commit",
+		Description: "Stages the changes of the given paths, or all the changes, and commits them.",
+	}, r.commit)
+	if err != nil {
+		return nil, err
+	}
+	branch, err := functiontool.New(functiontool.Config{
+		Name:        "git_branch",
+		Description: "Lists the branches, or switches to a branch, creating it if requested.",
+	}, r.branch)
+	if err != nil {
+		return nil, err
+	}
+	return []tool.Tool{status, diff, log, commit, branch}, nil
+}
+
+// FileStatus is the status of a changed file, in the short format of git
+// status, e.g. "M " for a staged modification or "??" for an untracked file.
+type FileStatus struct {
+	Path   string `json:"path"`
+	Status string `json:"status"`
+}
+
+// StatusResult is the result of the git_status tool.
+type StatusResult struct {
+	// Branch is the current branch.
+	Branch string       `json:"branch"`
+	Files  []FileStatus `json:"files"`
+}
+
+// DiffArgs are the arguments of the git_diff tool.
+type DiffArgs struct {
+	// Staged shows the staged changes instead of the unstaged ones.
+	Staged bool `json:"staged,omitempty"`
+	// Paths restricts the diff to these paths.
+	Paths []string `json:"paths,omitempty"`
+}
+
+// DiffResult is the result of the git_diff tool.
+type DiffResult struct {
+	Diff      string `json:"diff"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+// LogArgs are the arguments of the git_log tool.
+type LogArgs struct {
+	// Count is the number of commits. Defaults to DefaultLogCount.
+	Count int `json:"count,omitempty"`
+	// Path restricts the log to the commits changing this path.
+	Path string `json:"path,omitempty"`
+}
+
+// Commit describes a commit.
+type Commit struct {
+	Hash    string `json:"hash"`
+	Author  string `json:"author"`
+	Date    string `json:"date"`
+	Subject string `json:"subject"`
+}
+
+// LogResult is the result of the git_log tool.
+type LogResult struct {
+	Commits []Commit `json:"commits"`
+}
+
+// CommitArgs are the arguments of the git_commit tool.
+type CommitArgs struct {
+	// Message of the commit.
+	Message string `json:"message"`
+	// Paths to stage. If empty, all the changes are staged.
+	Paths []string `json:"paths,omitempty"`
+}
+
+// BranchArgs are the arguments of the git_branch tool.
+type BranchArgs struct {
+	// Name of the branch to switch to. If empty, the branches are listed.
+	Name string `json:"name,omitempty"`
+	// Create creates the branch from the current commit.
+	Create bool `json:"create,omitempty"`
+}
+
+// ChangeResult is the result of the git_commit and git_branch tools.
+type ChangeResult struct {
+	// Output of the commands, or of their dry run.
+	Output string `json:"output"`
+	// Commands run, or which would be run in dry-run mode.
+	Commands []string `json:"commands,omitempty"`
+	// DryRun reports that the repository was not modified.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+type repo struct {
+	root     string
+	git      string
+	env      []string
+	dryRun   bool
+	maxBytes int
+}
+
+// run runs git with args in the workspace and returns its standard output.
+// safeOptions disable the git settings running commands, which the model
+// could set by writing to the .git directory.
+var safeOptions = []string{
+	"--no-pager",
+	"-c", "core.hooksPath=" + os.DevNull,
+	"-c", "core.fsmonitor=false",
+	"-c", "commit.gpgsign=false",
+	"-c", "tag.gpgsign=false",
+}
+
+func (r *repo) run(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, r.git, append(slices.Clone(safeOptions), args...)...)
+	cmd.Dir = r.root
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_CONFIG_NOSYSTEM=1")
+	cmd.Env = append(cmd.Env, r.env...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s failed: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+	return stdout.String(), nil
+}
+
+// checkFilters refuses the repositories configuring filter drivers: git runs
+// their commands when it reads or writes the files of the working tree, and
+// they can't be disabled from the command line.
+func (r *repo) checkFilters(ctx context.Context) error {
+	out, err := r.run(ctx, "config", "--name-only", "--get-regexp", `^filter\.`)
+	if exitErr := (*exec.ExitError)(nil); errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		// No filter is configured.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("the repository configures filter drivers, refusing to run git: %s", strings.Join(strings.Fields(out), ", "))
+}
+
+// checkPaths rejects the paths which git would read as options.
+func checkPaths(paths ...string) error {
+	for _, p := range paths {
+		if p == "" || strings.HasPrefix(p, "-") {
+			return fmt.Errorf("invalid path %q", p)
+		}
+	}
+	return nil
+}
+
+func (r *repo) status(ctx tool.Context, _ struct{}) (StatusResult, error) {
+	if err := r.checkFilters(ctx); err != nil {
+		return StatusResult{}, err
+	}
+	out, err := r.run(ctx, "status", "--porcelain=v1", "--branch", "--untracked-files=all")
+	if err != nil {
+		return StatusResult{}, err
+	}
+	res := StatusResult{Files: []FileStatus{}}
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		if branch, ok := strings.CutPrefix(line, "## "); ok {
+			branch, _, _ = strings.Cut(branch, "...")
+			res.Branch = strings.TrimPrefix(branch, "No commits yet on ")
+			continue
+		}
+		if len(line) > 3 {
+			res.Files = append(res.Files, FileStatus{Path: line[3:], Status: line[:2]})
+		}
+	}
+	return res, nil
+}
+
+func (r *repo) diff(ctx tool.Context, args DiffArgs) (DiffResult, error) {
+	if err := checkPaths(args.Paths...); err != nil {
+		return DiffResult{}, err
+	}
+	if err := r.checkFilters(ctx); err != nil {
+		return DiffResult{}, err
+	}
+	cmd := []string{"diff", "--no-ext-diff", "--no-textconv"}
+	if args.Staged {
+		cmd = append(cmd, "--staged")
+	}
+	cmd = append(append(cmd, "--"), args.Paths...)
+	out, err := r.run(ctx, cmd...)
+	if err != nil {
+		return DiffResult{}, err
+	}
+	res := DiffResult{Diff: out}
+	if len(out) > r.maxBytes {
+		res.Diff = strings.ToValidUTF8(out[:r.maxBytes], "")
+		res.Truncated = true
+	}
+	return res, nil
+}
+
+func (r *repo) log(ctx tool.Context, args LogArgs) (LogResult, error) {
+	count := args.Count
+	if count <= 0 {
+		count = DefaultLogCount
+	}
+	cmd := []string{"log", fmt.Sprintf("--max-count=%d", count), "--format=%H%x1f%an <%ae>%x1f%aI%x1f%s"}
+	if args.Path != "" {
+		if err := checkPaths(args.Path); err != nil {
+			return LogResult{}, err
+		}
+		cmd = append(cmd, "--", args.Path)
+	}
+	out, err := r.run(ctx, cmd...)
+	if err != nil {
+		return LogResult{}, err
+	}
+	res := LogResult{Commits: []Commit{}}
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 4 {
+			continue
+		}
+		res.Commits = append(res.Commits, Commit{Hash: fields[0], Author: fields[1], Date: fields[2], Subject: fields[3]})
+	}
+	return res, nil
+}
+
+func (r *repo) commit(ctx tool.Context, args CommitArgs) (ChangeResult, error) {
+	if strings.TrimSpace(args.Message) == "" {
+		return ChangeResult{}, errors.New("message is required")
+	}
+	if err := checkPaths(args.Paths...); err != nil {
+		return ChangeResult{}, err
+	}
+	if err := r.checkFilters(ctx); err != nil {
+		return ChangeResult{}, err
+	}
+	add := []string{"add", "--all", "--"}
+	if len(args.Paths) > 0 {
+		add = append(add, args.Paths...)
+	} else {
+		add = append(add, ".")
+	}
+	commit := []string{"commit", "--no-verify", "--message", args.Message}
+
+	if r.dryRun {
+		out, err := r.run(ctx, append([]string{"add", "--dry-run"}, add[1:]...)...)
+		if err != nil {
+			return ChangeResult{}, err
+		}
+		return ChangeResult{Output: out, Commands: []string{formatCommand(add), formatCommand(commit)}, DryRun: true}, nil
+	}
+	if _, err := r.run(ctx, add...); err != nil {
+		return ChangeResult{}, err
+	}
+	out, err := r.run(ctx, commit...)
+	if err != nil {
+		return ChangeResult{}, err
+	}
+	return ChangeResult{Output: out, Commands: []string{formatCommand(add), formatCommand(commit)}}, nil
+}
+
+func (r *repo) branch(ctx tool.Context, args BranchArgs) (ChangeResult, error) {
+	if args.Name == "" {
+		out, err := r.run(ctx, "branch", "--list")
+		if err != nil {
+			return ChangeResult{}, err
+		}
+		return ChangeResult{Output: out}, nil
+	}
+	if strings.HasPrefix(args.Name, "-") {
+		return ChangeResult{}, fmt.Errorf("invalid branch name %q", args.Name)
+	}
+	if _, err := r.run(ctx, "check-ref-format", "--branch", args.Name); err != nil {
+		return ChangeResult{}, fmt.Errorf("invalid branch name %q", args.Name)
+	}
+	cmd := []string{"switch", args.Name}
+	if args.Create {
+		cmd = []string{"switch", "--create", args.Name}
+	}
+	if r.dryRun {
+		return ChangeResult{Commands: []string{formatCommand(cmd)}, DryRun: true}, nil
+	}
+	if err := r.checkFilters(ctx); err != nil {
+		return ChangeResult{}, err
+	}
+	if _, err := r.run(ctx, cmd...); err != nil {
+		return ChangeResult{}, err
+	}
+	// git switch only reports on stderr.
+	return ChangeResult{Output: fmt.Sprintf("switched to branch %q\n", args.Name), Commands: []string{formatCommand(cmd)}}, nil
+}
+
+// formatCommand returns the command line of git with args, for the model.
+func formatCommand(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if a == "" || strings.ContainsAny(a, " \t\n'\"") {
+			a = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+		}
+		quoted[i] = a
+	}
+	return "git " + strings.Join(quoted, " ")
+}
@@ -0,0 +1,264 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gittool_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/gittool"
+)
+
+// newRepo returns a new git repository with the files a.txt and b.txt.
+func newRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "--initial-branch=main", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init failed: %v: %s", err, out)
+	}
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// call runs the tool of ts named name.
+func call(t *testing.T, ts tool.Toolset, name string, args map[string]any) (map[string]any, error) {
+	t.Helper()
+	tools, err := ts.Tools(nil)
+	if err != nil {
+		t.Fatalf("Tools() failed: %v", err)
+	}
+	for _, tl := range tools {
+		if tl.Name() == name {
+			ctx := toolinternal.NewToolContext(icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{}), "", nil)
+			return tl.(toolinternal.FunctionTool).Run(ctx, args)
+		}
+	}
+	t.Fatalf("no tool %q", name)
+	return nil, nil
+}
+
+func mustCall(t *testing.T, ts tool.Toolset, name string, args map[string]any) map[string]any {
+	t.Helper()
+	res, err := call(t, ts, name, args)
+	if err != nil {
+		t.Fatalf("%s failed: %v", name, err)
+	}
+	return res
+}
+
+func TestGitTools(t *testing.T) {
+	dir := newRepo(t)
+	ts, err := gittool.New(gittool.Config{Root: dir, AuthorName: "Agent", AuthorEmail: "agent@example.com"})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	status := mustCall(t, ts, "git_status", map[string]any{})
+	wantStatus := map[string]any{"branch": "main", "files": []any{
+		map[string]any{"path": "a.txt", "status": "??"},
+		map[string]any{"path": "b.txt", "status": "??"},
+	}}
+	if diff := cmp.Diff(wantStatus, status); diff != "" {
+		t.Errorf("git_status mismatch (-want +got):\n%s", diff)
+	}
+
+	commit := mustCall(t, ts, "git_commit", map[string]any{"message": "Add a", "paths": []any{"a.txt"}})
+	if out := commit["output"].(string); !strings.Contains(out, "Add a") {
+		t.Errorf("git_commit output = %q, want it to contain the message", out)
+	}
+	mustCall(t, ts, "git_branch", map[string]any{"name": "feature", "create": true})
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a.txt changed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	status = mustCall(t, ts, "git_status", map[string]any{})
+	wantStatus = map[string]any{"branch": "feature", "files": []any{
+		map[string]any{"path": "a.txt", "status": " M"},
+		map[string]any{"path": "b.txt", "status": "??"},
+	}}
+	if diff := cmp.Diff(wantStatus, status); diff != "" {
+		t.Errorf("git_status mismatch (-want +got):\n%s", diff)
+	}
+	diff := mustCall(t, ts, "git_diff", map[string]any{"paths": []any{"a.txt"}})
+	if d := diff["diff"].(string); !strings.Contains(d, "-a.txt\n+a.txt changed\n") {
+		t.Errorf("git_diff = %q, want the change of a.txt", d)
+	}
+
+	log := mustCall(t, ts, "git_log", map[string]any{})
+	commits := log["commits"].([]any)
+	if len(commits) != 1 {
+		t.Fatalf("git_log returned %d commits, want 1", len(commits))
+	}
+	c := commits[0].(map[string]any)
+	if c["author"] != "Agent <agent@example.com>" || c["subject"] != "Add a" {
+		t.Errorf("git_log commit = %v, want the commit of Agent", c)
+	}
+
+	branches := mustCall(t, ts, "git_branch", map[string]any{})
+	if got, want := branches["output"], "* feature\n  main\n"; got != want {
+		t.Errorf("git_branch output = %q, want %q", got, want)
+	}
+
+	for _, tc := range []struct {
+		tool string
+		args map[string]any
+	}{
+		{tool: "git_commit", args: map[string]any{"message": " "}},
+		{tool: "git_commit", args: map[string]any{"message": "m", "paths": []any{"--exec=evil"}}},
+		{tool: "git_diff", args: map[string]any{"paths": []any{"-p"}}},
+		{tool: "git_branch", args: map[string]any{"name": "-D"}},
+		{tool: "git_branch", args: map[string]any{"name": "bad..name"}},
+		{tool: "git_branch", args: map[string]any{"name": "missing"}},
+	} {
+		if _, err := call(t, ts, tc.tool, tc.args); err == nil {
+			t.Errorf("%s(%v) succeeded, want error", tc.tool, tc.args)
+		}
+	}
+}
+
+func TestGitTools_Hooks(t *testing.T) {
+	dir := newRepo(t)
+	// The script leaves a file named after the hook or the setting running
+	// it.
+	script := func(name string) string {
+		path := filepath.Join(dir, ".git", name+".sh")
+		if err := os.WriteFile(path, []byte("#!/bin/sh\ntouch \""+filepath.Join(dir, name+"-ran")+"\"\nexit 1\n"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	for _, hook := range []string{"pre-commit", "post-checkout", "post-commit"} {
+		if err := os.Rename(script(hook), filepath.Join(dir, ".git", "hooks", hook)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gitConfig(t, dir, "core.fsmonitor", script("fsmonitor"))
+	gitConfig(t, dir, "diff.external", script("external-diff"))
+	gitConfig(t, dir, "commit.gpgsign", "true")
+	gitConfig(t, dir, "gpg.program", script("gpg"))
+
+	ts, err := gittool.New(gittool.Config{Root: dir, AuthorName: "Agent", AuthorEmail: "agent@example.com"})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	mustCall(t, ts, "git_commit", map[string]any{"message": "Add files"})
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("changed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mustCall(t, ts, "git_status", map[string]any{})
+	mustCall(t, ts, "git_diff", map[string]any{})
+	mustCall(t, ts, "git_branch", map[string]any{"name": "feature", "create": true})
+
+	for _, name := range []string{"pre-commit", "post-checkout", "post-commit", "fsmonitor", "external-diff", "gpg"} {
+		if _, err := os.Stat(filepath.Join(dir, name+"-ran")); err == nil {
+			t.Errorf("%s ran", name)
+		}
+	}
+}
+
+func TestGitTools_Filters(t *testing.T) {
+	dir := newRepo(t)
+	ran := filepath.Join(dir, "filter-ran")
+	gitConfig(t, dir, "filter.model.clean", "touch "+ran+"; cat")
+	gitConfig(t, dir, "filter.model.smudge", "touch "+ran+"; cat")
+	if err := os.WriteFile(filepath.Join(dir, ".gitattributes"), []byte("* filter=model\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ts, err := gittool.New(gittool.Config{Root: dir, AuthorName: "Agent", AuthorEmail: "agent@example.com"})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	for _, tc := range []struct {
+		name string
+		args map[string]any
+	}{
+		{name: "git_status", args: map[string]any{}},
+		{name: "git_diff", args: map[string]any{}},
+		{name: "git_commit", args: map[string]any{"message": "Add files"}},
+		{name: "git_branch", args: map[string]any{"name": "feature", "create": true}},
+	} {
+		if _, err := call(t, ts, tc.name, tc.args); err == nil || !strings.Contains(err.Error(), "filter.model.clean") {
+			t.Errorf("%s error = %v, want the filter drivers refused", tc.name, err)
+		}
+	}
+	if _, err := os.Stat(ran); err == nil {
+		t.Errorf("the filter ran")
+	}
+}
+
+func gitConfig(t *testing.T, dir, key, value string) {
+	t.Helper()
+	if out, err := exec.Command("git", "-C", dir, "config", key, value).CombinedOutput(); err != nil {
+		t.Fatalf("git config %s failed: %v: %s", key, err, out)
+	}
+}
+
+func TestGitTools_DryRun(t *testing.T) {
+	dir := newRepo(t)
+	ts, err := gittool.New(gittool.Config{Root: dir, DryRun: true})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	commit := mustCall(t, ts, "git_commit", map[string]any{"message": "Add files"})
+	wantCommit := map[string]any{
+		"output": "add 'a.txt'\nadd 'b.txt'\n",
+		"commands": []any{
+			"git add --all -- .",
+			"git commit --no-verify --message 'Add files'",
+		},
+		"dry_run": true,
+	}
+	if diff := cmp.Diff(wantCommit, commit); diff != "" {
+		t.Errorf("git_commit mismatch (-want +got):\n%s", diff)
+	}
+	branch := mustCall(t, ts, "git_branch", map[string]any{"name": "feature", "create": true})
+	wantBranch := map[string]any{"output": "", "commands": []any{"git switch --create feature"}, "dry_run": true}
+	if diff := cmp.Diff(wantBranch, branch); diff != "" {
+		t.Errorf("git_branch mismatch (-want +got):\n%s", diff)
+	}
+
+	status := mustCall(t, ts, "git_status", map[string]any{})
+	if got := len(status["files"].([]any)); got != 2 {
+		t.Errorf("git_status returned %d files, want the 2 untracked files", got)
+	}
+	if got := status["branch"]; got != "main" {
+		t.Errorf("git_status branch = %q, want %q", got, "main")
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	for _, cfg := range []gittool.Config{{}, {Root: filepath.Join(t.TempDir(), "missing")}} {
+		if _, err := gittool.New(cfg); err == nil {
+			t.Errorf("New(%+v) succeeded, want error", cfg)
+		}
+	}
+}
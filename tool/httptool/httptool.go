@@ -0,0 +1,288 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package httptool provides a tool that lets the model issue HTTP requests to
+// a set of domains allowed by the operator.
+//
+// Requests to any other domain, including redirects to it, are rejected.
+// Response bodies are truncated to a configurable size, and HTML responses
+// are converted to plain text before they are returned to the model.
+//
+// For example:
+//
+//	t, err := httptool.New(httptool.Config{
+//		AllowedDomains: []string{"example.com", "*.example.org"},
+//	})
+package httptool
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/toolinternal/toolutils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+const (
+	// DefaultMaxResponseBytes is the default limit of the response body size.
+	DefaultMaxResponseBytes = 64 << 10
+	// DefaultTimeout is the default timeout of a single request.
+	DefaultTimeout = 30 * time.Second
+
+	maxRedirects = 10
+)
+
+// Config defines the configuration of an HTTP tool.
+type Config struct {
+	// Name of the tool. Defaults to "http_request".
+	Name string
+	// Description of the tool. If empty, a description listing the allowed
+	// domains is used.
+	Description string
+	// AllowedDomains lists the hosts the model may send requests to.
+	// An entry matches the host exactly, unless it starts with "*.", in which
+	// case it matches any subdomain of the rest of the entry.
+	// At least one domain is required.
+	AllowedDomains []string
+	// AllowedMethods lists the HTTP methods the model may use.
+	// Defaults to GET and POST.
+	AllowedMethods []string
+	// MaxResponseBytes limits the size of the response body returned to the
+	// model. Longer bodies are truncated. Defaults to DefaultMaxResponseBytes.
+	MaxResponseBytes int64
+	// Timeout of a single request, including redirects.
+	// Defaults to DefaultTimeout.
+	Timeout time.Duration
+	// Client is used to send the requests. Defaults to http.DefaultClient.
+	// The tool does not modify the client, it uses a copy with the allow-list
+	// enforced on redirects.
+	Client *http.Client
+}
+
+// New creates an HTTP request tool.
+func New(cfg Config) (tool.Tool, error) {
+	if len(cfg.AllowedDomains) == 0 {
+		return nil, errors.New("at least one allowed domain is required")
+	}
+	domains := make([]string, 0, len(cfg.AllowedDomains))
+	for _, d := range cfg.AllowedDomains {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if d == "" || d == "*." {
+			return nil, fmt.Errorf("invalid allowed domain %q", d)
+		}
+		domains = append(domains, d)
+	}
+	methods := []string{http.MethodGet, http.MethodPost}
+	if len(cfg.AllowedMethods) > 0 {
+		methods = make([]string, 0, len(cfg.AllowedMethods))
+		for _, m := range cfg.AllowedMethods {
+			methods = append(methods, strings.ToUpper(m))
+		}
+	}
+	t := &httpTool{
+		name:        cfg.Name,
+		description: cfg.Description,
+		domains:     domains,
+		methods:     methods,
+		maxBytes:    cfg.MaxResponseBytes,
+		timeout:     cfg.Timeout,
+	}
+	if t.name == "" {
+		t.name = "http_request"
+	}
+	if t.description == "" {
+		t.description = fmt.Sprintf("Sends an HTTP request and returns the response. "+
+			"Only the following domains are allowed: %s.", strings.Join(domains, ", "))
+	}
+	if t.maxBytes <= 0 {
+		t.maxBytes = DefaultMaxResponseBytes
+	}
+	if t.timeout <= 0 {
+		t.timeout = DefaultTimeout
+	}
+
+	client := http.DefaultClient
+	if cfg.Client != nil {
+		client = cfg.Client
+	}
+	c := *client
+	c.Timeout = t.timeout
+	c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		return t.checkURL(req.URL)
+	}
+	t.client = &c
+	return t, nil
+}
+
+type httpTool struct {
+	name        string
+	description string
+	domains     []string
+	methods     []string
+	maxBytes    int64
+	timeout     time.Duration
+	client      *http.Client
+}
+
+// Name implements tool.Tool.
+func (t *httpTool) Name() string {
+	return t.name
+}
+
+// Description implements tool.Tool.
+func (t *httpTool) Description() string {
+	return t.description
+}
+
+// IsLongRunning implements tool.Tool.
+func (t *httpTool) IsLongRunning() bool {
+	return false
+}
+
+// Declaration returns the GenAI FunctionDeclaration for the HTTP tool.
+func (t *httpTool) Declaration() *genai.FunctionDeclaration {
+	return &genai.FunctionDeclaration{
+		Name:        t.name,
+		Description: t.description,
+		Parameters: &genai.Schema{
+			Type: "OBJECT",
+			Properties: map[string]*genai.Schema{
+				"url": {
+					Type:        "STRING",
+					Description: "The absolute http or https URL to request.",
+				},
+				"method": {
+					Type:        "STRING",
+					Description: "The HTTP method. Defaults to GET.",
+					Enum:        t.methods,
+				},
+				"headers": {
+					Type:        "OBJECT",
+					Description: "Optional request headers.",
+				},
+				"body": {
+					Type:        "STRING",
+					Description: "Optional request body.",
+				},
+			},
+			Required: []string{"url"},
+		},
+	}
+}
+
+// Run implements tool.Tool.
+func (t *httpTool) Run(ctx tool.Context, args any) (map[string]any, error) {
+	m, ok := args.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected args type, got: %T", args)
+	}
+	rawURL, _ := m["url"].(string)
+	if rawURL == "" {
+		return nil, errors.New("url is required")
+	}
+	method := http.MethodGet
+	if v, ok := m["method"].(string); ok && v != "" {
+		method = strings.ToUpper(v)
+	}
+	if !slices.Contains(t.methods, method) {
+		return nil, fmt.Errorf("method %q is not allowed", method)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url %q: %w", rawURL, err)
+	}
+	if err := t.checkURL(u); err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+	if v, ok := m["body"].(string); ok && v != "" {
+		body = strings.NewReader(v)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if headers, ok := m["headers"].(map[string]any); ok {
+		for k, v := range headers {
+			req.Header.Set(k, fmt.Sprint(v))
+		}
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, t.maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	truncated := int64(len(data)) > t.maxBytes
+	if truncated {
+		data = data[:t.maxBytes]
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	text := string(data)
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == "text/html" {
+		text = htmlToText(text)
+	}
+
+	return map[string]any{
+		"status_code":  resp.StatusCode,
+		"content_type": contentType,
+		"body":         text,
+		"truncated":    truncated,
+	}, nil
+}
+
+// ProcessRequest packs the HTTP tool into the LLM request.
+func (t *httpTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	return toolutils.PackTool(req, t)
+}
+
+// checkURL returns an error if u may not be requested.
+func (t *httpTool) checkURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported url scheme %q", u.Scheme)
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, d := range t.domains {
+		if suffix, ok := strings.CutPrefix(d, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return nil
+			}
+			continue
+		}
+		if host == d {
+			return nil
+		}
+	}
+	return fmt.Errorf("domain %q is not allowed", host)
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httptool_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	icontext "google.golang.org/adk/internal/context"
+	"google.golang.org/adk/internal/toolinternal"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/httptool"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/html", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, `<html><head><title>T</title><style>p{}</style></head>
+<body><h1>Hello</h1><script>alert(1)</script><p>Some   text</p><ul><li>one</li><li>two</li></ul></body></html>`)
+	})
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		body, _ := io.ReadAll(r.Body)
+		io.WriteString(w, r.Method+" "+r.Header.Get("X-Test")+" "+string(body))
+	})
+	mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		io.WriteString(w, strings.Repeat("a", 100))
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		u, _ := url.Parse("http://" + r.Host)
+		http.Redirect(w, r, "http://localhost:"+u.Port()+"/echo", http.StatusFound)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func run(t *testing.T, tl tool.Tool, args map[string]any) (map[string]any, error) {
+	t.Helper()
+	ft, ok := tl.(toolinternal.FunctionTool)
+	if !ok {
+		t.Fatalf("tool does not implement FunctionTool")
+	}
+	ctx := toolinternal.NewToolContext(icontext.NewInvocationContext(t.Context(), icontext.InvocationContextParams{}), "", nil)
+	return ft.Run(ctx, args)
+}
+
+func TestHTTPTool(t *testing.T) {
+	srv := newServer(t)
+
+	for _, tc := range []struct {
+		name     string
+		maxBytes int64
+		args     map[string]any
+		want     map[string]any
+	}{
+		{
+			name: "html is converted to text",
+			args: map[string]any{"url": srv.URL + "/html"},
+			want: map[string]any{
+				"status_code":  200,
+				"content_type": "text/html; charset=utf-8",
+				"body":         "Hello\nSome text\none\ntwo",
+				"truncated":    false,
+			},
+		},
+		{
+			name: "post with headers and body",
+			args: map[string]any{"url": srv.URL + "/echo", "method": "post", "headers": map[string]any{"X-Test": "h"}, "body": "b"},
+			want: map[string]any{
+				"status_code":  200,
+				"content_type": "text/plain",
+				"body":         "POST h b",
+				"truncated":    false,
+			},
+		},
+		{
+			name:     "large body is truncated",
+			maxBytes: 10,
+			args:     map[string]any{"url": srv.URL + "/large"},
+			want: map[string]any{
+				"status_code":  200,
+				"content_type": "text/plain",
+				"body":         "aaaaaaaaaa",
+				"truncated":    true,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tl, err := httptool.New(httptool.Config{
+				AllowedDomains:   []string{"127.0.0.1"},
+				MaxResponseBytes: tc.maxBytes,
+			})
+			if err != nil {
+				t.Fatalf("New() failed: %v", err)
+			}
+			got, err := run(t, tl, tc.args)
+			if err != nil {
+				t.Fatalf("Run() failed: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Run() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHTTPTool_Rejects(t *testing.T) {
+	srv := newServer(t)
+	tl, err := httptool.New(httptool.Config{
+		AllowedDomains: []string{"127.0.0.1", "*.example.com"},
+		AllowedMethods: []string{"GET"},
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		args map[string]any
+	}{
+		{name: "missing url", args: map[string]any{}},
+		{name: "disallowed domain", args: map[string]any{"url": "http://other.com/"}},
+		{name: "wildcard does not match apex", args: map[string]any{"url": "http://example.com/"}},
+		{name: "wildcard suffix trick", args: map[string]any{"url": "http://badexample.com/"}},
+		{name: "disallowed scheme", args: map[string]any{"url": "file:///etc/passwd"}},
+		{name: "disallowed method", args: map[string]any{"url": srv.URL + "/echo", "method": "POST"}},
+		{name: "redirect to disallowed domain", args: map[string]any{"url": srv.URL + "/redirect"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got, err := run(t, tl, tc.args); err == nil {
+				t.Errorf("Run() = %v, want error", got)
+			}
+		})
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  httptool.Config
+	}{
+		{name: "no domains", cfg: httptool.Config{}},
+		{name: "empty domain", cfg: httptool.Config{AllowedDomains: []string{" "}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := httptool.New(tc.cfg); err == nil {
+				t.Errorf("New() succeeded, want error")
+			}
+		})
+	}
+}
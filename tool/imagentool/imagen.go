@@ -0,0 +1,218 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package imagentool provides a tool that generates images with the Imagen
+// models, saves them to the artifact service and returns references to the
+// artifacts.
+//
+// For example:
+//
+//	t, err := imagentool.New(ctx, imagentool.Config{
+//		ClientConfig: &genai.ClientConfig{Backend: genai.BackendVertexAI},
+//	})
+//
+// The agent using the tool must run with an artifact service.
+package imagentool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const (
+	// DefaultName is the name of the tool when none is configured.
+	DefaultName = "generate_image"
+	// DefaultModel is the Imagen model used when none is configured.
+	DefaultModel = "imagen-3.0-generate-002"
+	// MaxImages is the maximum number of images generated by a call.
+	MaxImages = 4
+)
+
+// Config defines the configuration of an image generation tool.
+type Config struct {
+	// Name of the tool, as exposed to the model.
+	// If empty, DefaultName is used.
+	Name string
+	// Description of the tool. If empty, a generic description is used.
+	Description string
+	// ClientConfig configures the genai client calling Imagen. If nil, the
+	// client is configured from the environment.
+	ClientConfig *genai.ClientConfig
+	// Model is the Imagen model version. If empty, DefaultModel is used.
+	Model string
+	// SafetyFilterLevel is the level of the filtering of the generated
+	// images. If empty, the model default is used.
+	SafetyFilterLevel genai.SafetyFilterLevel
+	// PersonGeneration controls the generation of people. If empty, the
+	// model default is used.
+	PersonGeneration genai.PersonGeneration
+	// OutputMIMEType is the MIME type of the images, "image/png" or
+	// "image/jpeg". If empty, "image/png" is used.
+	OutputMIMEType string
+	// AddWatermark adds a SynthID watermark to the images.
+	AddWatermark bool
+}
+
+// Args are the arguments of the tool, set by the model.
+type Args struct {
+	// Prompt describes the images to generate.
+	Prompt string `json:"prompt"`
+	// NegativePrompt describes what to avoid in the images.
+	NegativePrompt string `json:"negative_prompt,omitempty"`
+	// Filename is the name of the artifact. When several images are
+	// generated, an index is added to it. If empty, a unique name is used.
+	Filename string `json:"filename,omitempty"`
+	// NumberOfImages is the number of images to generate, 1 by default.
+	NumberOfImages int `json:"number_of_images,omitempty"`
+	// AspectRatio of the images, e.g. "1:1" or "16:9".
+	AspectRatio string `json:"aspect_ratio,omitempty"`
+}
+
+// Image references a generated image saved as an artifact.
+type Image struct {
+	// Filename is the name of the artifact.
+	Filename string `json:"filename"`
+	// Version is the version of the artifact.
+	Version int64 `json:"version"`
+	// MIMEType of the image.
+	MIMEType string `json:"mime_type"`
+	// EnhancedPrompt is the prompt used by the model, if it was rewritten.
+	EnhancedPrompt string `json:"enhanced_prompt,omitempty"`
+}
+
+// Result is the result of the tool.
+type Result struct {
+	// Images are the generated images.
+	Images []Image `json:"images"`
+	// Model is the Imagen model that generated the images.
+	Model string `json:"model"`
+	// FilteredReasons explains why some of the requested images were
+	// filtered out by the safety filters.
+	FilteredReasons []string `json:"filtered_reasons,omitempty"`
+}
+
+// New creates an image generation tool from the given configuration.
+func New(ctx context.Context, cfg Config) (tool.Tool, error) {
+	client, err := genai.NewClient(ctx, cfg.ClientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create genai client: %w", err)
+	}
+	if cfg.Name == "" {
+		cfg.Name = DefaultName
+	}
+	if cfg.Description == "" {
+		cfg.Description = "Generates images from a text prompt and saves them as artifacts."
+	}
+	if cfg.Model == "" {
+		cfg.Model = DefaultModel
+	}
+	if cfg.OutputMIMEType == "" {
+		cfg.OutputMIMEType = "image/png"
+	}
+	g := &generator{client: client, cfg: cfg}
+	return functiontool.New(functiontool.Config{Name: cfg.Name, Description: cfg.Description}, g.generate)
+}
+
+type generator struct {
+	client *genai.Client
+	cfg    Config
+}
+
+func (g *generator) generate(ctx tool.Context, args Args) (Result, error) {
+	if args.Prompt == "" {
+		return Result{}, errors.New("prompt is required")
+	}
+	if ctx.Artifacts() == nil {
+		return Result{}, errors.New("artifact service is not configured")
+	}
+	n := args.NumberOfImages
+	if n <= 0 {
+		n = 1
+	}
+	if n > MaxImages {
+		return Result{}, fmt.Errorf("at most %d images can be generated, got %d", MaxImages, n)
+	}
+
+	resp, err := g.client.Models.GenerateImages(ctx, g.cfg.Model, args.Prompt, &genai.GenerateImagesConfig{
+		NegativePrompt:    args.NegativePrompt,
+		NumberOfImages:    int32(n),
+		AspectRatio:       args.AspectRatio,
+		SafetyFilterLevel: g.cfg.SafetyFilterLevel,
+		PersonGeneration:  g.cfg.PersonGeneration,
+		OutputMIMEType:    g.cfg.OutputMIMEType,
+		AddWatermark:      g.cfg.AddWatermark,
+		IncludeRAIReason:  true,
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to generate images: %w", err)
+	}
+
+	result := Result{Images: []Image{}, Model: g.cfg.Model}
+	var images []*genai.GeneratedImage
+	for _, img := range resp.GeneratedImages {
+		if img == nil || img.Image == nil || len(img.Image.ImageBytes) == 0 {
+			if img != nil && img.RAIFilteredReason != "" {
+				result.FilteredReasons = append(result.FilteredReasons, img.RAIFilteredReason)
+			}
+			continue
+		}
+		images = append(images, img)
+	}
+	base := args.Filename
+	if base == "" {
+		base = "image_" + uuid.NewString()
+	}
+	for i, img := range images {
+		mimeType := img.Image.MIMEType
+		if mimeType == "" {
+			mimeType = g.cfg.OutputMIMEType
+		}
+		name := artifactName(base, i, len(images), mimeType)
+		saved, err := ctx.Artifacts().Save(ctx, name, genai.NewPartFromBytes(img.Image.ImageBytes, mimeType))
+		if err != nil {
+			return Result{}, fmt.Errorf("failed to save image %q: %w", name, err)
+		}
+		result.Images = append(result.Images, Image{
+			Filename:       name,
+			Version:        saved.Version,
+			MIMEType:       mimeType,
+			EnhancedPrompt: img.EnhancedPrompt,
+		})
+	}
+	return result, nil
+}
+
+// artifactName returns the name of the i-th of n images. An index is added
+// to base if there are several images, and the extension of the MIME type
+// if base has none.
+func artifactName(base string, i, n int, mimeType string) string {
+	ext := path.Ext(base)
+	name := strings.TrimSuffix(base, ext)
+	if n > 1 {
+		name = fmt.Sprintf("%s_%d", name, i+1)
+	}
+	if ext == "" {
+		ext = "." + strings.TrimPrefix(mimeType, "image/")
+	}
+	return name + ext
+}
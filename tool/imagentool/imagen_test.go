@@ -0,0 +1,135 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imagentool_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/imagentool"
+)
+
+func TestImagenTool(t *testing.T) {
+	var gotPath string
+	var gotReq map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &gotReq); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"predictions": []map[string]any{
+			{"bytesBase64Encoded": base64.StdEncoding.EncodeToString([]byte("first")), "mimeType": "image/png"},
+			{"raiFilteredReason": "filtered"},
+			{"bytesBase64Encoded": base64.StdEncoding.EncodeToString([]byte("second")), "mimeType": "image/png"},
+		}})
+	}))
+	defer srv.Close()
+
+	ctx := t.Context()
+	imagen, err := imagentool.New(ctx, imagentool.Config{
+		ClientConfig: &genai.ClientConfig{
+			APIKey:      "fakekey",
+			Backend:     genai.BackendGeminiAPI,
+			HTTPOptions: genai.HTTPOptions{BaseURL: srv.URL},
+		},
+		Model:            "imagen-test",
+		PersonGeneration: "DONT_ALLOW",
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	llm := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromFunctionCall(imagentool.DefaultName, map[string]any{
+			"prompt":           "a cat",
+			"filename":         "cat",
+			"number_of_images": 3,
+		}, genai.RoleModel),
+		genai.NewContentFromText("done", genai.RoleModel),
+	}}
+	a, err := llmagent.New(llmagent.Config{Name: "agent", Model: llm, Tools: []tool.Tool{imagen}})
+	if err != nil {
+		t.Fatalf("llmagent.New() failed: %v", err)
+	}
+	sessionService := session.InMemoryService()
+	artifactService := artifact.InMemoryService()
+	r, err := runner.New(runner.Config{AppName: "app", Agent: a, SessionService: sessionService, ArtifactService: artifactService})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := r.RunAndCollect(ctx, "user", "session", genai.NewContentFromText("draw a cat", genai.RoleUser), agent.RunConfig{})
+	if err != nil {
+		t.Fatalf("RunAndCollect() failed: %v", err)
+	}
+
+	var resp map[string]any
+	for _, ev := range got.Events {
+		if ev.Content == nil {
+			continue
+		}
+		for _, p := range ev.Content.Parts {
+			if p.FunctionResponse != nil {
+				resp = p.FunctionResponse.Response
+			}
+		}
+	}
+	wantResp := map[string]any{
+		"images": []any{
+			map[string]any{"filename": "cat_1.png", "version": float64(1), "mime_type": "image/png"},
+			map[string]any{"filename": "cat_2.png", "version": float64(1), "mime_type": "image/png"},
+		},
+		"model":            "imagen-test",
+		"filtered_reasons": []any{"filtered"},
+	}
+	if diff := cmp.Diff(wantResp, resp); diff != "" {
+		t.Errorf("function response mismatch (-want +got):\n%s", diff)
+	}
+	if want := "/v1beta/models/imagen-test:predict"; gotPath != want {
+		t.Errorf("request path = %q, want %q", gotPath, want)
+	}
+	params, _ := gotReq["parameters"].(map[string]any)
+	if params["sampleCount"] != float64(3) || params["personGeneration"] != "DONT_ALLOW" {
+		t.Errorf("request parameters = %v, want sampleCount 3 and personGeneration DONT_ALLOW", params)
+	}
+
+	for name, want := range map[string]string{"cat_1.png": "first", "cat_2.png": "second"} {
+		loaded, err := artifactService.Load(ctx, &artifact.LoadRequest{AppName: "app", UserID: "user", SessionID: "session", FileName: name})
+		if err != nil {
+			t.Fatalf("Load(%q) failed: %v", name, err)
+		}
+		if got := string(loaded.Part.InlineData.Data); got != want {
+			t.Errorf("artifact %q = %q, want %q", name, got, want)
+		}
+	}
+}
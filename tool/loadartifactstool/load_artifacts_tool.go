@@ -15,35 +15,81 @@
 // Package loadartifactstool defines a tool for loading artifacts.
 // This tool informs the model about available artifacts and provides their content when
 // requested by the model through a function call.
+//
+// [NewWithConfig] restricts the artifacts which can be loaded, by name and
+// MIME type, and their size, so that large binaries don't blow up the prompt.
 package loadartifactstool
 
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"path"
+	"slices"
 
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/genai"
 
 	"google.golang.org/adk/agent"
+	"google.golang.org/adk/artifact"
 	"google.golang.org/adk/internal/toolinternal/toolutils"
 	"google.golang.org/adk/internal/utils"
 	"google.golang.org/adk/model"
 	"google.golang.org/adk/tool"
 )
 
+// Config restricts the artifacts loaded by the tool. The zero value loads
+// every artifact.
+type Config struct {
+	// Names are glob patterns, as supported by [path.Match], matching the
+	// names of the artifacts the model can see and load. If empty, all the
+	// artifacts can be loaded.
+	Names []string
+	// MIMETypes are glob patterns matching the MIME types of the artifacts
+	// which can be loaded, e.g. "text/*" or "application/pdf". Text
+	// artifacts have the "text/plain" MIME type. If empty, all the types can
+	// be loaded.
+	MIMETypes []string
+	// MaxBytes is the maximum size of an artifact. If zero, the size is not
+	// limited.
+	MaxBytes int
+	// MaxTotalBytes is the maximum total size of the artifacts loaded by a
+	// single call. The artifacts are loaded in the requested order until the
+	// limit is reached. If zero, the total size is not limited.
+	MaxTotalBytes int
+}
+
 // artifactsTool is a tool that loads artifacts and adds them to the session.
 type artifactsTool struct {
 	name        string
 	description string
+	cfg         Config
 }
 
 // New creates a new loadArtifactsTool.
 func New() tool.Tool {
+	t, _ := NewWithConfig(Config{})
+	return t
+}
+
+// NewWithConfig creates a new loadArtifactsTool loading the artifacts allowed
+// by cfg. The artifacts which are not allowed are replaced by a note
+// explaining why they weren't loaded.
+func NewWithConfig(cfg Config) (tool.Tool, error) {
+	for _, pattern := range slices.Concat(cfg.Names, cfg.MIMETypes) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	if cfg.MaxBytes < 0 || cfg.MaxTotalBytes < 0 {
+		return nil, errors.New("size limits must not be negative")
+	}
 	return &artifactsTool{
 		name:        "load_artifacts",
 		description: "Loads the artifacts and adds them to the session.",
-	}
+		cfg:         cfg,
+	}, nil
 }
 
 // Name implements tool.Tool.
@@ -79,6 +125,18 @@ func (t *artifactsTool) Declaration() *genai.FunctionDeclaration {
 						Type: "STRING",
 					},
 				},
+				"artifacts": {
+					Type:        "ARRAY",
+					Description: "Artifacts to load at a specific version.",
+					Items: &genai.Schema{
+						Type: "OBJECT",
+						Properties: map[string]*genai.Schema{
+							"name":    {Type: "STRING"},
+							"version": {Type: "INTEGER"},
+						},
+						Required: []string{"name"},
+					},
+				},
 			},
 		},
 	}
@@ -112,9 +170,31 @@ func (t *artifactsTool) Run(ctx tool.Context, args any) (map[string]any, error)
 	result := map[string]any{
 		"artifact_names": artifactNames,
 	}
+	if raw, ok := m["artifacts"]; ok && raw != nil {
+		var versioned []artifactVersion
+		if err := remarshal(raw, &versioned); err != nil {
+			return nil, fmt.Errorf("invalid artifacts: %w", err)
+		}
+		result["artifacts"] = versioned
+	}
 	return result, nil
 }
 
+// artifactVersion is an artifact requested at a specific version.
+type artifactVersion struct {
+	Name    string `json:"name"`
+	Version int    `json:"version,omitempty"`
+}
+
+// remarshal converts from to the type of to through JSON.
+func remarshal(from, to any) error {
+	b, err := json.Marshal(from)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, to)
+}
+
 // ProcessRequest processes the LLM request. It packs the tool, appends initial
 // instructions, and processes any load artifacts function calls.
 func (t *artifactsTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
@@ -132,10 +212,13 @@ func (t *artifactsTool) appendInitialInstructions(ctx tool.Context, req *model.L
 	if err != nil {
 		return fmt.Errorf("failed to list artifacts: %w", err)
 	}
-	if len(resp.FileNames) == 0 {
+	names := slices.DeleteFunc(slices.Clone(resp.FileNames), func(name string) bool {
+		return !t.allowedName(name)
+	})
+	if len(names) == 0 {
 		return nil
 	}
-	artifactNamesJSON, err := json.Marshal(resp.FileNames)
+	artifactNamesJSON, err := json.Marshal(names)
 	if err != nil {
 		return fmt.Errorf("failed to marshal artifact names: %w", err)
 	}
@@ -169,30 +252,39 @@ func (t *artifactsTool) processLoadArtifactsFunctionCall(ctx tool.Context, req *
 	if functionResponse.Name != "load_artifacts" {
 		return nil
 	}
-	artifactNamesRaw, ok := functionResponse.Response["artifact_names"]
-	if !ok {
-		return nil
+	var requested []artifactVersion
+	if artifactNamesRaw, ok := functionResponse.Response["artifact_names"]; ok {
+		var artifactNames []string
+		if err := remarshal(artifactNamesRaw, &artifactNames); err != nil {
+			return fmt.Errorf("invalid artifact names type: %T, expected []string", artifactNamesRaw)
+		}
+		for _, name := range artifactNames {
+			requested = append(requested, artifactVersion{Name: name})
+		}
 	}
-	artifactNames, ok := artifactNamesRaw.([]string)
-	if !ok {
-		return fmt.Errorf("invalid artifact names type: %T, expected []string", artifactNamesRaw)
+	if versionedRaw, ok := functionResponse.Response["artifacts"]; ok {
+		var versioned []artifactVersion
+		if err := remarshal(versionedRaw, &versioned); err != nil {
+			return fmt.Errorf("invalid artifacts: %w", err)
+		}
+		requested = append(requested, versioned...)
 	}
-	if len(artifactNames) == 0 {
+	if len(requested) == 0 {
 		return nil
 	}
 
-	results := make([]*genai.Content, len(artifactNames))
+	results := make([]loadedArtifact, len(requested))
 	group, childCtx := errgroup.WithContext(ctx)
 	artifactsService := ctx.Artifacts()
 
-	for i, artifactName := range artifactNames {
+	for i, a := range requested {
 		group.Go(func() error {
 			// Although not used, we need to pass childCtx for early return in case of an error.
-			content, err := t.loadIndividualArtifact(childCtx, artifactsService, artifactName)
+			loaded, err := t.loadIndividualArtifact(childCtx, artifactsService, a)
 			if err != nil {
-				return fmt.Errorf("failed to load artifact %s: %w", artifactName, err)
+				return fmt.Errorf("failed to load artifact %s: %w", a.Name, err)
 			}
-			results[i] = content
+			results[i] = loaded
 			return nil
 		})
 	}
@@ -201,20 +293,94 @@ func (t *artifactsTool) processLoadArtifactsFunctionCall(ctx tool.Context, req *
 		return err
 	}
 
-	req.Contents = append(req.Contents, results...)
+	total := 0
+	for i, a := range requested {
+		loaded := results[i]
+		if loaded.skipReason == "" && t.cfg.MaxTotalBytes > 0 {
+			if size := partSize(loaded.part); total+size > t.cfg.MaxTotalBytes {
+				loaded.skipReason = fmt.Sprintf("the loaded artifacts would exceed the limit of %d bytes", t.cfg.MaxTotalBytes)
+			} else {
+				total += size
+			}
+		}
+		if loaded.skipReason != "" {
+			req.Contents = append(req.Contents, &genai.Content{
+				Parts: []*genai.Part{genai.NewPartFromText("Artifact " + a.Name + " was not loaded: " + loaded.skipReason + ".")},
+				Role:  genai.RoleUser,
+			})
+			continue
+		}
+		req.Contents = append(req.Contents, &genai.Content{
+			Parts: []*genai.Part{
+				genai.NewPartFromText("Artifact " + a.Name + " is:"),
+				loaded.part,
+			},
+			Role: genai.RoleUser,
+		})
+	}
 	return nil
 }
 
-func (t *artifactsTool) loadIndividualArtifact(ctx context.Context, artifactsService agent.Artifacts, artifactName string) (*genai.Content, error) {
-	resp, err := artifactsService.Load(ctx, artifactName)
+// loadedArtifact is the result of the loading of an artifact: its part, or
+// the reason why it was not loaded.
+type loadedArtifact struct {
+	part       *genai.Part
+	skipReason string
+}
+
+func (t *artifactsTool) loadIndividualArtifact(ctx context.Context, artifactsService agent.Artifacts, a artifactVersion) (loadedArtifact, error) {
+	if !t.allowedName(a.Name) {
+		return loadedArtifact{skipReason: "it is not available"}, nil
+	}
+	var resp *artifact.LoadResponse
+	var err error
+	if a.Version > 0 {
+		resp, err = artifactsService.LoadVersion(ctx, a.Name, a.Version)
+	} else {
+		resp, err = artifactsService.Load(ctx, a.Name)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to load artifact %s: %w", artifactName, err)
+		return loadedArtifact{}, fmt.Errorf("failed to load artifact %s: %w", a.Name, err)
 	}
-	return &genai.Content{
-		Parts: []*genai.Part{
-			genai.NewPartFromText("Artifact " + artifactName + " is:"),
-			resp.Part,
-		},
-		Role: genai.RoleUser,
-	}, nil
+	part := resp.Part
+	if mimeType := partMIMEType(part); len(t.cfg.MIMETypes) > 0 && !matchAny(t.cfg.MIMETypes, mimeType) {
+		return loadedArtifact{skipReason: fmt.Sprintf("its type %s is not supported", mimeType)}, nil
+	}
+	if size := partSize(part); t.cfg.MaxBytes > 0 && size > t.cfg.MaxBytes {
+		return loadedArtifact{skipReason: fmt.Sprintf("its size, %d bytes, exceeds the limit of %d bytes", size, t.cfg.MaxBytes)}, nil
+	}
+	return loadedArtifact{part: part}, nil
+}
+
+func (t *artifactsTool) allowedName(name string) bool {
+	return len(t.cfg.Names) == 0 || matchAny(t.cfg.Names, name)
+}
+
+func matchAny(patterns []string, s string) bool {
+	for _, pattern := range patterns {
+		// The patterns are validated by NewWithConfig.
+		if ok, _ := path.Match(pattern, s); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func partMIMEType(part *genai.Part) string {
+	switch {
+	case part.InlineData != nil:
+		return part.InlineData.MIMEType
+	case part.FileData != nil:
+		return part.FileData.MIMEType
+	}
+	return "text/plain"
+}
+
+// partSize returns the size of the content of the part sent to the model.
+// Parts referencing files have no size.
+func partSize(part *genai.Part) int {
+	if part.InlineData != nil {
+		return len(part.InlineData.Data)
+	}
+	return len(part.Text)
 }
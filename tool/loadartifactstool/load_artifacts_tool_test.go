@@ -15,6 +15,7 @@
 package loadartifactstool_test
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 
@@ -169,6 +170,41 @@ func TestLoadArtifactsTool_ProcessRequest(t *testing.T) {
 	}
 }
 
+func TestLoadArtifactsTool_ProcessRequest_UserScopedArtifacts(t *testing.T) {
+	loadArtifactsTool := loadartifactstool.New()
+
+	service := artifact.InMemoryService()
+	tc := createToolContextWithService(t, service)
+	// User scoped artifacts saved from another session of the same user are
+	// visible in every session.
+	if _, err := tc.Artifacts().Save(t.Context(), "session.txt", &genai.Part{Text: "session"}); err != nil {
+		t.Fatalf("Failed to save artifact: %v", err)
+	}
+	if _, err := service.Save(t.Context(), &artifact.SaveRequest{
+		AppName:   "app",
+		UserID:    "user",
+		SessionID: "other_session",
+		FileName:  "user:profile.txt",
+		Part:      &genai.Part{Text: "profile"},
+	}); err != nil {
+		t.Fatalf("Failed to save user scoped artifact: %v", err)
+	}
+
+	llmRequest := &model.LLMRequest{}
+	requestProcessor, ok := loadArtifactsTool.(toolinternal.RequestProcessor)
+	if !ok {
+		t.Fatal("loadArtifactsTool does not implement RequestProcessor")
+	}
+	if err := requestProcessor.ProcessRequest(tc, llmRequest); err != nil {
+		t.Fatalf("ProcessRequest failed: %v", err)
+	}
+
+	instruction := llmRequest.Config.SystemInstruction.Parts[0].Text
+	if !strings.Contains(instruction, `"session.txt"`) || !strings.Contains(instruction, `"user:profile.txt"`) {
+		t.Errorf("Instruction should contain session and user scoped artifact names, but got: %v", instruction)
+	}
+}
+
 func TestLoadArtifactsTool_ProcessRequest_Artifacts_LoadArtifactsFunctionCall(t *testing.T) {
 	loadArtifactsTool := loadartifactstool.New()
 
@@ -277,11 +313,121 @@ func TestLoadArtifactsTool_ProcessRequest_Artifacts_OtherFunctionCall(t *testing
 	}
 }
 
+func TestLoadArtifactsTool_Run_Versions(t *testing.T) {
+	toolImpl, ok := loadartifactstool.New().(toolinternal.FunctionTool)
+	if !ok {
+		t.Fatal("loadArtifactsTool does not implement FunctionTool")
+	}
+	got, err := toolImpl.Run(createToolContext(t), map[string]any{
+		"artifacts": []any{map[string]any{"name": "doc.txt", "version": float64(1)}},
+	})
+	if err != nil {
+		t.Fatalf("Run() failed: %v", err)
+	}
+	gotJSON, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"artifact_names":[],"artifacts":[{"name":"doc.txt","version":1}]}`
+	if string(gotJSON) != want {
+		t.Errorf("Run() = %s, want %s", gotJSON, want)
+	}
+}
+
+func TestNewWithConfig(t *testing.T) {
+	tc := createToolContext(t)
+	saves := []struct {
+		name string
+		part *genai.Part
+	}{
+		{"notes.txt", genai.NewPartFromText("first version")},
+		{"notes.txt", genai.NewPartFromText("second version")},
+		{"large.txt", genai.NewPartFromText(strings.Repeat("x", 100))},
+		{"image.png", genai.NewPartFromBytes([]byte("png"), "image/png")},
+		{"secret.key", genai.NewPartFromText("secret")},
+		{"medium.txt", genai.NewPartFromText(strings.Repeat("y", 20))},
+	}
+	for _, save := range saves {
+		if _, err := tc.Artifacts().Save(t.Context(), save.name, save.part); err != nil {
+			t.Fatalf("Failed to save artifact %s: %v", save.name, err)
+		}
+	}
+	loadArtifactsTool, err := loadartifactstool.NewWithConfig(loadartifactstool.Config{
+		Names:         []string{"*.txt", "*.png"},
+		MIMETypes:     []string{"text/*"},
+		MaxBytes:      50,
+		MaxTotalBytes: 30,
+	})
+	if err != nil {
+		t.Fatalf("NewWithConfig() failed: %v", err)
+	}
+
+	llmRequest := &model.LLMRequest{
+		Contents: []*genai.Content{
+			genai.NewContentFromParts([]*genai.Part{
+				genai.NewPartFromFunctionResponse("load_artifacts", map[string]any{
+					"artifact_names": []any{"notes.txt", "large.txt", "image.png", "secret.key", "medium.txt"},
+					"artifacts":      []any{map[string]any{"name": "notes.txt", "version": 1}},
+				}),
+			}, genai.RoleUser),
+		},
+	}
+	if err := loadArtifactsTool.(toolinternal.RequestProcessor).ProcessRequest(tc, llmRequest); err != nil {
+		t.Fatalf("ProcessRequest failed: %v", err)
+	}
+
+	instruction := llmRequest.Config.SystemInstruction.Parts[0].Text
+	if strings.Contains(instruction, "secret.key") {
+		t.Errorf("Instruction should not list secret.key, but got: %v", instruction)
+	}
+	var got []string
+	for _, c := range llmRequest.Contents[1:] {
+		var texts []string
+		for _, p := range c.Parts {
+			texts = append(texts, p.Text)
+		}
+		got = append(got, strings.Join(texts, " "))
+	}
+	want := []string{
+		"Artifact notes.txt is: second version",
+		"Artifact large.txt was not loaded: its size, 100 bytes, exceeds the limit of 50 bytes.",
+		"Artifact image.png was not loaded: its type image/png is not supported.",
+		"Artifact secret.key was not loaded: it is not available.",
+		"Artifact medium.txt was not loaded: the loaded artifacts would exceed the limit of 30 bytes.",
+		"Artifact notes.txt is: first version",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("loaded contents mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewWithConfig_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  loadartifactstool.Config
+	}{
+		{name: "bad name pattern", cfg: loadartifactstool.Config{Names: []string{"["}}},
+		{name: "bad MIME type pattern", cfg: loadartifactstool.Config{MIMETypes: []string{"["}}},
+		{name: "negative size", cfg: loadartifactstool.Config{MaxBytes: -1}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := loadartifactstool.NewWithConfig(tc.cfg); err == nil {
+				t.Errorf("NewWithConfig() succeeded, want error")
+			}
+		})
+	}
+}
+
 func createToolContext(t *testing.T) tool.Context {
 	t.Helper()
+	return createToolContextWithService(t, artifact.InMemoryService())
+}
+
+func createToolContextWithService(t *testing.T, service artifact.Service) tool.Context {
+	t.Helper()
 
 	artifacts := &artifactinternal.Artifacts{
-		Service:   artifact.InMemoryService(),
+		Service:   service,
 		AppName:   "app",
 		UserID:    "user",
 		SessionID: "session",
@@ -269,41 +269,63 @@ func (r *testAgentRunner) Run(t *testing.T, sessionID, newMessage string) iter.S
 func TestToolFilter(t *testing.T) {
 	const toolDescription = "returns weather in the given city"
 
-	clientTransport, serverTransport := mcp.NewInMemoryTransports()
-
-	server := mcp.NewServer(&mcp.Implementation{Name: "weather_server", Version: "v1.0.0"}, nil)
-	mcp.AddTool(server, &mcp.Tool{Name: "get_weather", Description: toolDescription}, weatherFunc)
-	mcp.AddTool(server, &mcp.Tool{Name: "get_weather1", Description: toolDescription}, weatherFunc)
-	_, err := server.Connect(t.Context(), serverTransport, nil)
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	ts, err := mcptoolset.New(mcptoolset.Config{
-		Transport:  clientTransport,
-		ToolFilter: tool.StringPredicate([]string{"get_weather"}),
-	})
-	if err != nil {
-		t.Fatalf("Failed to create MCP tool set: %v", err)
-	}
-
-	tools, err := ts.Tools(icontext.NewReadonlyContext(
-		icontext.NewInvocationContext(
-			t.Context(),
-			icontext.InvocationContextParams{},
-		),
-	))
-	if err != nil {
-		t.Fatalf("Failed to get tools: %v", err)
-	}
-
-	gotToolNames := make([]string, len(tools))
-	for i, tool := range tools {
-		gotToolNames[i] = tool.Name()
-	}
-	wantToolNames := []string{"get_weather"}
-
-	if diff := cmp.Diff(wantToolNames, gotToolNames); diff != "" {
-		t.Errorf("tools mismatch (-want +got):\n%s", diff)
+	for _, tc := range []struct {
+		name   string
+		filter tool.Predicate
+		want   []string
+	}{
+		{
+			name:   "by name",
+			filter: tool.StringPredicate([]string{"get_weather"}),
+			want:   []string{"get_weather"},
+		},
+		{
+			name:   "read-only",
+			filter: tool.ReadOnlyPredicate,
+			want:   []string{"get_weather1"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			clientTransport, serverTransport := mcp.NewInMemoryTransports()
+
+			server := mcp.NewServer(&mcp.Implementation{Name: "weather_server", Version: "v1.0.0"}, nil)
+			mcp.AddTool(server, &mcp.Tool{Name: "get_weather", Description: toolDescription}, weatherFunc)
+			mcp.AddTool(server, &mcp.Tool{
+				Name:        "get_weather1",
+				Description: toolDescription,
+				Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+			}, weatherFunc)
+			_, err := server.Connect(t.Context(), serverTransport, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ts, err := mcptoolset.New(mcptoolset.Config{
+				Transport:  clientTransport,
+				ToolFilter: tc.filter,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create MCP tool set: %v", err)
+			}
+
+			tools, err := ts.Tools(icontext.NewReadonlyContext(
+				icontext.NewInvocationContext(
+					t.Context(),
+					icontext.InvocationContextParams{},
+				),
+			))
+			if err != nil {
+				t.Fatalf("Failed to get tools: %v", err)
+			}
+
+			gotToolNames := make([]string, len(tools))
+			for i, tool := range tools {
+				gotToolNames[i] = tool.Name()
+			}
+
+			if diff := cmp.Diff(tc.want, gotToolNames); diff != "" {
+				t.Errorf("tools mismatch (-want +got):\n%s", diff)
+			}
+		})
 	}
 }
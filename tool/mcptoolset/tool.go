@@ -41,6 +41,7 @@ func convertTool(t *mcp.Tool, getSessionFunc getSessionFunc) (tool.Tool, error)
 			ParametersJsonSchema: t.InputSchema,
 			ResponseJsonSchema:   t.OutputSchema,
 		},
+		readOnly:       t.Annotations != nil && t.Annotations.ReadOnlyHint,
 		getSessionFunc: getSessionFunc,
 	}, nil
 }
@@ -49,6 +50,7 @@ type mcpTool struct {
 	name            string
 	description     string
 	funcDeclaration *genai.FunctionDeclaration
+	readOnly        bool
 
 	getSessionFunc getSessionFunc
 }
@@ -68,6 +70,12 @@ func (t *mcpTool) IsLongRunning() bool {
 	return false
 }
 
+// IsReadOnly implements tool.ReadOnlyTool. It reports the read-only hint
+// of the tool annotations.
+func (t *mcpTool) IsReadOnly() bool {
+	return t.readOnly
+}
+
 func (t *mcpTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
 	return toolutils.PackTool(req, t)
 }
@@ -0,0 +1,90 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrievaltool
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"google.golang.org/adk/embedding"
+)
+
+// NewEmbeddingRetriever returns a Retriever ranking chunks by the semantic
+// similarity of their content to the query. The chunks are embedded once,
+// when the retriever is created, and their scores are set by each retrieval.
+func NewEmbeddingRetriever(ctx context.Context, embedder embedding.Embedder, chunks []*Chunk) (Retriever, error) {
+	if embedder == nil {
+		return nil, errors.New("embedder is required")
+	}
+	texts := make([]string, len(chunks))
+	for i, c := range chunks {
+		if c == nil {
+			return nil, fmt.Errorf("chunk %d is nil", i)
+		}
+		texts[i] = c.Content
+	}
+	var embeddings [][]float32
+	if len(texts) > 0 {
+		var err error
+		embeddings, err = embedder.Embed(ctx, texts, embedding.TaskTypeRetrievalDocument)
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed chunks: %w", err)
+		}
+		if len(embeddings) != len(chunks) {
+			return nil, fmt.Errorf("got %d embeddings for %d chunks", len(embeddings), len(chunks))
+		}
+	}
+	return &embeddingRetriever{embedder: embedder, chunks: chunks, embeddings: embeddings}, nil
+}
+
+type embeddingRetriever struct {
+	embedder   embedding.Embedder
+	chunks     []*Chunk
+	embeddings [][]float32
+}
+
+// Retrieve implements Retriever.
+func (r *embeddingRetriever) Retrieve(ctx context.Context, query string, topK int) ([]*Chunk, error) {
+	if len(r.chunks) == 0 {
+		return nil, nil
+	}
+	q, err := r.embedder.Embed(ctx, []string{query}, embedding.TaskTypeRetrievalQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+	if len(q) != 1 {
+		return nil, fmt.Errorf("got %d embeddings for the query", len(q))
+	}
+	res := make([]*Chunk, len(r.chunks))
+	for i, c := range r.chunks {
+		score, err := embedding.CosineSimilarity(q[0], r.embeddings[i])
+		if err != nil {
+			return nil, err
+		}
+		scored := *c
+		scored.Score = score
+		res[i] = &scored
+	}
+	slices.SortStableFunc(res, func(a, b *Chunk) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	if topK > 0 && len(res) > topK {
+		res = res[:topK]
+	}
+	return res, nil
+}
@@ -0,0 +1,217 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retrievaltool provides a tool that lets the model query a document
+// corpus and receive the most relevant chunks as the tool response.
+//
+// The corpus is accessed through the [Retriever] interface, so any search
+// backend can be plugged in. [NewVertexAISearch] returns a Retriever backed
+// by Vertex AI Search, and [NewEmbeddingRetriever] one ranking a fixed set of
+// chunks by semantic similarity, using an [embedding.Embedder].
+//
+// For example:
+//
+//	r, err := retrievaltool.NewVertexAISearch(ctx, retrievaltool.VertexAISearchConfig{
+//		ServingConfig: "projects/p/locations/global/collections/default_collection/dataStores/ds/servingConfigs/default_search",
+//	})
+//	...
+//	t, err := retrievaltool.New(retrievaltool.Config{
+//		Name:        "search_docs",
+//		Description: "Searches the product documentation.",
+//		Retriever:   r,
+//	})
+package retrievaltool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/internal/toolinternal/toolutils"
+	"google.golang.org/adk/model"
+	"google.golang.org/adk/tool"
+)
+
+// DefaultTopK is the number of chunks returned when no limit is configured.
+const DefaultTopK = 5
+
+// Chunk is a piece of a document returned by a Retriever.
+type Chunk struct {
+	// ID identifies the chunk within the corpus.
+	ID string `json:"id,omitempty"`
+	// Content is the text of the chunk.
+	Content string `json:"content"`
+	// Title of the document the chunk belongs to.
+	Title string `json:"title,omitempty"`
+	// URI of the document the chunk belongs to.
+	URI string `json:"uri,omitempty"`
+	// Score is the relevance of the chunk to the query. Higher is better.
+	Score float64 `json:"score"`
+}
+
+// Retriever finds the chunks of a corpus that are relevant to a query.
+type Retriever interface {
+	// Retrieve returns at most topK chunks relevant to query.
+	Retrieve(ctx context.Context, query string, topK int) ([]*Chunk, error)
+}
+
+// Config defines the configuration of a retrieval tool.
+type Config struct {
+	// Name of the tool, as exposed to the model.
+	Name string
+	// Description of the corpus, used by the model to decide when to call
+	// the tool.
+	Description string
+	// Retriever is used to look up the chunks.
+	Retriever Retriever
+	// TopK is the maximum number of chunks returned to the model.
+	// If zero, DefaultTopK is used.
+	TopK int
+}
+
+// New creates a retrieval tool from the given configuration.
+func New(cfg Config) (tool.Tool, error) {
+	if cfg.Name == "" {
+		return nil, errors.New("retrieval tool name is required")
+	}
+	if cfg.Retriever == nil {
+		return nil, errors.New("retriever is required")
+	}
+	if cfg.TopK < 0 {
+		return nil, fmt.Errorf("invalid TopK %d", cfg.TopK)
+	}
+	topK := cfg.TopK
+	if topK == 0 {
+		topK = DefaultTopK
+	}
+	return &retrievalTool{
+		name:        cfg.Name,
+		description: cfg.Description,
+		retriever:   cfg.Retriever,
+		topK:        topK,
+	}, nil
+}
+
+type retrievalTool struct {
+	name        string
+	description string
+	retriever   Retriever
+	topK        int
+}
+
+// Name implements tool.Tool.
+func (t *retrievalTool) Name() string {
+	return t.name
+}
+
+// Description implements tool.Tool.
+func (t *retrievalTool) Description() string {
+	return t.description
+}
+
+// IsLongRunning implements tool.Tool.
+func (t *retrievalTool) IsLongRunning() bool {
+	return false
+}
+
+// Declaration returns the GenAI FunctionDeclaration for the retrieval tool.
+func (t *retrievalTool) Declaration() *genai.FunctionDeclaration {
+	return &genai.FunctionDeclaration{
+		Name:        t.name,
+		Description: t.description,
+		Parameters: &genai.Schema{
+			Type: "OBJECT",
+			Properties: map[string]*genai.Schema{
+				"query": {
+					Type:        "STRING",
+					Description: "The query to retrieve.",
+				},
+			},
+			Required: []string{"query"},
+		},
+	}
+}
+
+// Run implements tool.Tool.
+func (t *retrievalTool) Run(ctx tool.Context, args any) (map[string]any, error) {
+	m, ok := args.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected args type, got: %T", args)
+	}
+	query, _ := m["query"].(string)
+	if query == "" {
+		return nil, errors.New("query is required")
+	}
+	chunks, err := Retrieve(ctx, t.retriever, query, t.topK)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"chunks": chunks}, nil
+}
+
+// ProcessRequest packs the retrieval tool into the LLM request.
+func (t *retrievalTool) ProcessRequest(ctx tool.Context, req *model.LLMRequest) error {
+	return toolutils.PackTool(req, t)
+}
+
+// Retrieve queries r and returns at most topK chunks ordered from the most
+// to the least relevant.
+func Retrieve(ctx context.Context, r Retriever, query string, topK int) ([]*Chunk, error) {
+	chunks, err := r.Retrieve(ctx, query, topK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve chunks: %w", err)
+	}
+	chunks = slices.DeleteFunc(slices.Clone(chunks), func(c *Chunk) bool { return c == nil })
+	slices.SortStableFunc(chunks, func(a, b *Chunk) int {
+		switch {
+		case a.Score > b.Score:
+			return -1
+		case a.Score < b.Score:
+			return 1
+		}
+		return 0
+	})
+	if topK > 0 && len(chunks) > topK {
+		chunks = chunks[:topK]
+	}
+	return chunks, nil
+}
+
+// FormatChunks renders chunks as text suitable for a system instruction.
+// It returns an empty string if there are no chunks.
+func FormatChunks(chunks []*Chunk) string {
+	if len(chunks) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("Use the following retrieved context to answer the user. " +
+		"If the context is not relevant, ignore it.\n")
+	for i, c := range chunks {
+		fmt.Fprintf(&sb, "\n[%d]", i+1)
+		if c.Title != "" {
+			fmt.Fprintf(&sb, " %s", c.Title)
+		}
+		if c.URI != "" {
+			fmt.Fprintf(&sb, " (%s)", c.URI)
+		}
+		sb.WriteString("\n")
+		sb.WriteString(c.Content)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
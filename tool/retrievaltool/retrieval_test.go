@@ -0,0 +1,252 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retrievaltool_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"google.golang.org/api/option"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/embedding"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/retrievaltool"
+)
+
+type fakeRetriever struct {
+	chunks  []*retrievaltool.Chunk
+	queries []string
+}
+
+func (r *fakeRetriever) Retrieve(ctx context.Context, query string, topK int) ([]*retrievaltool.Chunk, error) {
+	r.queries = append(r.queries, query)
+	return r.chunks, nil
+}
+
+func TestNew_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  retrievaltool.Config
+	}{
+		{name: "no name", cfg: retrievaltool.Config{Retriever: &fakeRetriever{}}},
+		{name: "no retriever", cfg: retrievaltool.Config{Name: "search"}},
+		{name: "negative topK", cfg: retrievaltool.Config{Name: "search", Retriever: &fakeRetriever{}, TopK: -1}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := retrievaltool.New(tc.cfg); err == nil {
+				t.Errorf("New() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestRetrievalTool_ReturnsRankedChunks(t *testing.T) {
+	retriever := &fakeRetriever{chunks: []*retrievaltool.Chunk{
+		{ID: "a", Content: "low", Score: 0.1},
+		{ID: "b", Content: "high", Score: 0.9},
+		{ID: "c", Content: "mid", Score: 0.5},
+	}}
+	searchTool, err := retrievaltool.New(retrievaltool.Config{
+		Name:        "search_docs",
+		Description: "Searches the docs.",
+		Retriever:   retriever,
+		TopK:        2,
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	model := &testutil.MockModel{
+		Responses: []*genai.Content{
+			genai.NewContentFromFunctionCall("search_docs", map[string]any{"query": "what is adk"}, "model"),
+			genai.NewContentFromText("done", "model"),
+		},
+	}
+	a, err := llmagent.New(llmagent.Config{
+		Name:  "agent",
+		Model: model,
+		Tools: []tool.Tool{searchTool},
+	})
+	if err != nil {
+		t.Fatalf("llmagent.New() failed: %v", err)
+	}
+
+	events, err := testutil.CollectEvents(testutil.NewTestAgentRunner(t, a).Run(t, "session", "question"))
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	var got map[string]any
+	for _, ev := range events {
+		if ev.Content == nil {
+			continue
+		}
+		for _, p := range ev.Content.Parts {
+			if p.FunctionResponse != nil {
+				got = p.FunctionResponse.Response
+			}
+		}
+	}
+	want := map[string]any{"chunks": []*retrievaltool.Chunk{
+		{ID: "b", Content: "high", Score: 0.9},
+		{ID: "c", Content: "mid", Score: 0.5},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("function response mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"what is adk"}, retriever.queries); diff != "" {
+		t.Errorf("queries mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLLMAgent_RetrievalInjectsContext(t *testing.T) {
+	retriever := &fakeRetriever{chunks: []*retrievaltool.Chunk{
+		{Content: "ADK is an agent development kit.", Title: "Overview", URI: "https://example.com/adk", Score: 1},
+	}}
+	model := &testutil.MockModel{
+		Responses: []*genai.Content{genai.NewContentFromText("answer", "model")},
+	}
+	a, err := llmagent.New(llmagent.Config{
+		Name:      "agent",
+		Model:     model,
+		Retrieval: &llmagent.RetrievalConfig{Retriever: retriever},
+	})
+	if err != nil {
+		t.Fatalf("llmagent.New() failed: %v", err)
+	}
+
+	if _, err := testutil.CollectEvents(testutil.NewTestAgentRunner(t, a).Run(t, "session", "what is adk")); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+
+	if len(model.Requests) != 1 {
+		t.Fatalf("got %d model requests, want 1", len(model.Requests))
+	}
+	si := model.Requests[0].Config.SystemInstruction
+	if si == nil || len(si.Parts) == 0 {
+		t.Fatalf("system instruction is empty")
+	}
+	want := retrievaltool.FormatChunks(retriever.chunks)
+	if !strings.Contains(si.Parts[0].Text, want) {
+		t.Errorf("system instruction = %q, want it to contain %q", si.Parts[0].Text, want)
+	}
+	if diff := cmp.Diff([]string{"what is adk"}, retriever.queries); diff != "" {
+		t.Errorf("queries mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// keywordEmbedder embeds texts as the number of occurrences of each of its
+// keywords.
+type keywordEmbedder struct {
+	keywords []string
+}
+
+func (e *keywordEmbedder) Embed(ctx context.Context, texts []string, taskType embedding.TaskType) ([][]float32, error) {
+	res := make([][]float32, len(texts))
+	for i, text := range texts {
+		res[i] = make([]float32, len(e.keywords))
+		for j, k := range e.keywords {
+			res[i][j] = float32(strings.Count(text, k))
+		}
+	}
+	return res, nil
+}
+
+func TestEmbeddingRetriever(t *testing.T) {
+	ctx := t.Context()
+	r, err := retrievaltool.NewEmbeddingRetriever(ctx, &keywordEmbedder{keywords: []string{"go", "rust"}}, []*retrievaltool.Chunk{
+		{ID: "a", Content: "rust"},
+		{ID: "b", Content: "go"},
+		{ID: "c", Content: "go and rust"},
+	})
+	if err != nil {
+		t.Fatalf("NewEmbeddingRetriever() failed: %v", err)
+	}
+	got, err := r.Retrieve(ctx, "go", 2)
+	if err != nil {
+		t.Fatalf("Retrieve() failed: %v", err)
+	}
+	want := []*retrievaltool.Chunk{
+		{ID: "b", Content: "go", Score: 1},
+		{ID: "c", Content: "go and rust", Score: 1 / math.Sqrt2},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.EquateApprox(0, 1e-6)); diff != "" {
+		t.Errorf("Retrieve() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestVertexAISearch(t *testing.T) {
+	const servingConfig = "projects/p/locations/global/collections/default_collection/dataStores/ds/servingConfigs/default_search"
+	var gotPath string
+	var gotReq map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &gotReq); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"results": [
+			{"chunk": {"id": "c1", "content": "first", "relevanceScore": 0.8, "documentMetadata": {"title": "Doc", "uri": "gs://b/doc"}}},
+			{"document": {"id": "d1"}},
+			{"chunk": {"id": "c2", "content": "second", "relevanceScore": 0.3}}
+		]}`)
+	}))
+	defer srv.Close()
+
+	ctx := t.Context()
+	r, err := retrievaltool.NewVertexAISearch(ctx, retrievaltool.VertexAISearchConfig{
+		ServingConfig: servingConfig,
+		Filter:        `lang: ANY("en")`,
+		ClientOptions: []option.ClientOption{option.WithEndpoint(srv.URL), option.WithoutAuthentication()},
+	})
+	if err != nil {
+		t.Fatalf("NewVertexAISearch() failed: %v", err)
+	}
+	got, err := r.Retrieve(ctx, "query", 3)
+	if err != nil {
+		t.Fatalf("Retrieve() failed: %v", err)
+	}
+
+	want := []*retrievaltool.Chunk{
+		{ID: "c1", Content: "first", Title: "Doc", URI: "gs://b/doc", Score: 0.8},
+		{ID: "c2", Content: "second", Score: 0.3},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Retrieve() mismatch (-want +got):\n%s", diff)
+	}
+	if wantPath := "/v1/" + servingConfig + ":search"; gotPath != wantPath {
+		t.Errorf("request path = %q, want %q", gotPath, wantPath)
+	}
+	wantReq := map[string]any{
+		"query":             "query",
+		"pageSize":          float64(3),
+		"filter":            `lang: ANY("en")`,
+		"contentSearchSpec": map[string]any{"searchResultMode": "CHUNKS"},
+	}
+	if diff := cmp.Diff(wantReq, gotReq); diff != "" {
+		t.Errorf("request mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"context"
 
 	"google.golang.org/adk/agent"
+	"google.golang.org/adk/auth"
 	"google.golang.org/adk/memory"
 	"google.golang.org/adk/session"
 )
@@ -51,10 +52,21 @@ type Context interface {
 	Actions() *session.EventActions
 	// SearchMemory performs a semantic search on the agent's memory.
 	SearchMemory(context.Context, string) (*memory.SearchResponse, error)
+
+	// RequestCredential asks the client for the credential described by cfg.
+	// The invocation pauses after the tool call and the tool is called again
+	// once the client has provided the credential.
+	RequestCredential(cfg *auth.Config)
+	// Credentials returns the credentials provided by the client.
+	Credentials() auth.Credentials
 }
 
 // Toolset is an interface for a collection of tools. It allows grouping
 // related tools together and providing them to an agent.
+//
+// The tools of a toolset are listed again before each model call, so that
+// dynamic toolsets, e.g. backed by an MCP server or a database, can refresh
+// them and select them for the user of the invocation.
 type Toolset interface {
 	// Name returns the name of the toolset.
 	Name() string
@@ -64,6 +76,14 @@ type Toolset interface {
 	Tools(ctx agent.ReadonlyContext) ([]Tool, error)
 }
 
+// ReadOnlyTool is implemented by the tools reporting whether they modify
+// their environment.
+type ReadOnlyTool interface {
+	Tool
+	// IsReadOnly reports whether the tool only reads data.
+	IsReadOnly() bool
+}
+
 // Predicate is a function which decides whether a tool should be exposed to LLM.
 type Predicate func(ctx agent.ReadonlyContext, tool Tool) bool
 
@@ -0,0 +1,68 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool
+
+import "google.golang.org/adk/agent"
+
+// NewToolset returns a Toolset named name whose tools are listed by fn, e.g.
+// to build the tools of an invocation from the current user or state.
+func NewToolset(name string, fn func(ctx agent.ReadonlyContext) ([]Tool, error)) Toolset {
+	return &funcToolset{name: name, fn: fn}
+}
+
+type funcToolset struct {
+	name string
+	fn   func(ctx agent.ReadonlyContext) ([]Tool, error)
+}
+
+// Name implements Toolset.
+func (s *funcToolset) Name() string {
+	return s.name
+}
+
+// Tools implements Toolset.
+func (s *funcToolset) Tools(ctx agent.ReadonlyContext) ([]Tool, error) {
+	return s.fn(ctx)
+}
+
+// FilterToolset returns a Toolset with the tools of ts for which predicate
+// returns true. The predicate is evaluated for each invocation, so it can
+// depend on its user, e.g.:
+//
+//	tool.FilterToolset(ts, func(ctx agent.ReadonlyContext, t tool.Tool) bool {
+//		return isAdmin(ctx.UserID()) || tool.ReadOnlyPredicate(ctx, t)
+//	})
+func FilterToolset(ts Toolset, predicate Predicate) Toolset {
+	return NewToolset(ts.Name(), func(ctx agent.ReadonlyContext) ([]Tool, error) {
+		tools, err := ts.Tools(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var filtered []Tool
+		for _, t := range tools {
+			if predicate(ctx, t) {
+				filtered = append(filtered, t)
+			}
+		}
+		return filtered, nil
+	})
+}
+
+// ReadOnlyPredicate is a Predicate selecting the tools which implement
+// ReadOnlyTool and report that they only read data.
+func ReadOnlyPredicate(ctx agent.ReadonlyContext, t Tool) bool {
+	ro, ok := t.(ReadOnlyTool)
+	return ok && ro.IsReadOnly()
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tool_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+type userContext struct {
+	agent.ReadonlyContext
+	userID string
+}
+
+func (c *userContext) UserID() string {
+	return c.userID
+}
+
+func TestFilterToolset(t *testing.T) {
+	newTool := func(name string, readOnly bool) tool.Tool {
+		ft, err := functiontool.New(functiontool.Config{Name: name, IsReadOnly: readOnly}, func(tool.Context, struct{}) (struct{}, error) {
+			return struct{}{}, nil
+		})
+		if err != nil {
+			t.Fatalf("functiontool.New() failed: %v", err)
+		}
+		return ft
+	}
+	calls := 0
+	ts := tool.NewToolset("db", func(ctx agent.ReadonlyContext) ([]tool.Tool, error) {
+		calls++
+		if ctx.UserID() == "" {
+			return nil, errors.New("no user")
+		}
+		return []tool.Tool{newTool("query", true), newTool("update", false), newTool("export_"+ctx.UserID(), true)}, nil
+	})
+	filtered := tool.FilterToolset(ts, func(ctx agent.ReadonlyContext, t tool.Tool) bool {
+		return ctx.UserID() == "admin" || tool.ReadOnlyPredicate(ctx, t)
+	})
+	if got := filtered.Name(); got != "db" {
+		t.Errorf("Name() = %q, want %q", got, "db")
+	}
+
+	for _, tc := range []struct {
+		user string
+		want []string
+	}{
+		{user: "alice", want: []string{"query", "export_alice"}},
+		{user: "admin", want: []string{"query", "update", "export_admin"}},
+	} {
+		t.Run(tc.user, func(t *testing.T) {
+			tools, err := filtered.Tools(&userContext{userID: tc.user})
+			if err != nil {
+				t.Fatalf("Tools() failed: %v", err)
+			}
+			var got []string
+			for _, ft := range tools {
+				got = append(got, ft.Name())
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Tools() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+	if calls != 2 {
+		t.Errorf("toolset listed %d times, want 2", calls)
+	}
+	if _, err := filtered.Tools(&userContext{}); err == nil {
+		t.Errorf("Tools() succeeded, want error")
+	}
+}
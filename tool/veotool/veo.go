@@ -0,0 +1,250 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package veotool provides a tool that generates videos with the Veo models,
+// saves them to the artifact service and returns references to the
+// artifacts.
+//
+// For example:
+//
+//	t, err := veotool.New(ctx, veotool.Config{
+//		ClientConfig: &genai.ClientConfig{Backend: genai.BackendVertexAI},
+//	})
+//
+// Video generation is a long-running operation which can take minutes, the
+// tool call waits until it completes. The agent using the tool must run with
+// an artifact service.
+package veotool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/functiontool"
+)
+
+const (
+	// DefaultName is the name of the tool when none is configured.
+	DefaultName = "generate_video"
+	// DefaultModel is the Veo model used when none is configured.
+	DefaultModel = "veo-2.0-generate-001"
+	// DefaultPollInterval is the interval between two checks of the
+	// generation operation when none is configured.
+	DefaultPollInterval = 10 * time.Second
+)
+
+// Config defines the configuration of a video generation tool.
+type Config struct {
+	// Name of the tool, as exposed to the model.
+	// If empty, DefaultName is used.
+	Name string
+	// Description of the tool. If empty, a generic description is used.
+	Description string
+	// ClientConfig configures the genai client calling Veo. If nil, the
+	// client is configured from the environment.
+	ClientConfig *genai.ClientConfig
+	// Model is the Veo model version. If empty, DefaultModel is used.
+	Model string
+	// PersonGeneration controls the generation of people, e.g.
+	// "dont_allow" or "allow_adult". If empty, the model default is used.
+	PersonGeneration string
+	// Resolution of the videos, e.g. "720p". If empty, the model default is
+	// used.
+	Resolution string
+	// GenerateAudio makes the models that support it generate audio.
+	GenerateAudio *bool
+	// PollInterval is the interval between two checks of the generation
+	// operation. If zero, DefaultPollInterval is used.
+	PollInterval time.Duration
+}
+
+// Args are the arguments of the tool, set by the model.
+type Args struct {
+	// Prompt describes the video to generate.
+	Prompt string `json:"prompt"`
+	// NegativePrompt describes what to avoid in the video.
+	NegativePrompt string `json:"negative_prompt,omitempty"`
+	// Filename is the name of the artifact. When several videos are
+	// generated, an index is added to it. If empty, a unique name is used.
+	Filename string `json:"filename,omitempty"`
+	// ImageFilename is the name of an image artifact used as the first
+	// frame of the video.
+	ImageFilename string `json:"image_filename,omitempty"`
+	// AspectRatio of the video, e.g. "16:9" or "9:16".
+	AspectRatio string `json:"aspect_ratio,omitempty"`
+	// DurationSeconds is the length of the video.
+	DurationSeconds int `json:"duration_seconds,omitempty"`
+}
+
+// Video references a generated video saved as an artifact.
+type Video struct {
+	// Filename is the name of the artifact.
+	Filename string `json:"filename"`
+	// Version is the version of the artifact.
+	Version int64 `json:"version"`
+	// MIMEType of the video.
+	MIMEType string `json:"mime_type"`
+}
+
+// Result is the result of the tool.
+type Result struct {
+	// Videos are the generated videos.
+	Videos []Video `json:"videos"`
+	// Model is the Veo model that generated the videos.
+	Model string `json:"model"`
+	// FilteredReasons explains why some of the videos were filtered out by
+	// the safety filters.
+	FilteredReasons []string `json:"filtered_reasons,omitempty"`
+}
+
+// New creates a video generation tool from the given configuration.
+func New(ctx context.Context, cfg Config) (tool.Tool, error) {
+	client, err := genai.NewClient(ctx, cfg.ClientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create genai client: %w", err)
+	}
+	if cfg.Name == "" {
+		cfg.Name = DefaultName
+	}
+	if cfg.Description == "" {
+		cfg.Description = "Generates a video from a text prompt, and optionally a first frame image, and saves it as an artifact."
+	}
+	if cfg.Model == "" {
+		cfg.Model = DefaultModel
+	}
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+	g := &generator{client: client, cfg: cfg}
+	return functiontool.New(functiontool.Config{Name: cfg.Name, Description: cfg.Description}, g.generate)
+}
+
+type generator struct {
+	client *genai.Client
+	cfg    Config
+}
+
+func (g *generator) generate(ctx tool.Context, args Args) (Result, error) {
+	if args.Prompt == "" {
+		return Result{}, errors.New("prompt is required")
+	}
+	if ctx.Artifacts() == nil {
+		return Result{}, errors.New("artifact service is not configured")
+	}
+	var image *genai.Image
+	if args.ImageFilename != "" {
+		resp, err := ctx.Artifacts().Load(ctx, args.ImageFilename)
+		if err != nil {
+			return Result{}, fmt.Errorf("failed to load image %q: %w", args.ImageFilename, err)
+		}
+		if resp.Part == nil || resp.Part.InlineData == nil {
+			return Result{}, fmt.Errorf("artifact %q is not an image", args.ImageFilename)
+		}
+		image = &genai.Image{ImageBytes: resp.Part.InlineData.Data, MIMEType: resp.Part.InlineData.MIMEType}
+	}
+	videoCfg := &genai.GenerateVideosConfig{
+		NegativePrompt:   args.NegativePrompt,
+		AspectRatio:      args.AspectRatio,
+		PersonGeneration: g.cfg.PersonGeneration,
+		Resolution:       g.cfg.Resolution,
+		GenerateAudio:    g.cfg.GenerateAudio,
+	}
+	if args.DurationSeconds > 0 {
+		videoCfg.DurationSeconds = genai.Ptr(int32(args.DurationSeconds))
+	}
+
+	op, err := g.client.Models.GenerateVideos(ctx, g.cfg.Model, args.Prompt, image, videoCfg)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to generate video: %w", err)
+	}
+	for !op.Done {
+		timer := time.NewTimer(g.cfg.PollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return Result{}, fmt.Errorf("video generation %q: %w", op.Name, context.Cause(ctx))
+		case <-timer.C:
+		}
+		op, err = g.client.Operations.GetVideosOperation(ctx, op, nil)
+		if err != nil {
+			return Result{}, fmt.Errorf("failed to get video generation operation: %w", err)
+		}
+	}
+	if op.Error != nil {
+		return Result{}, fmt.Errorf("video generation failed: %v", op.Error["message"])
+	}
+
+	result := Result{Videos: []Video{}, Model: g.cfg.Model}
+	var videos []*genai.GeneratedVideo
+	if op.Response != nil {
+		result.FilteredReasons = op.Response.RAIMediaFilteredReasons
+		for _, v := range op.Response.GeneratedVideos {
+			if v != nil && v.Video != nil {
+				videos = append(videos, v)
+			}
+		}
+	}
+	base := args.Filename
+	if base == "" {
+		base = "video_" + uuid.NewString()
+	}
+	for i, v := range videos {
+		data := v.Video.VideoBytes
+		if len(data) == 0 {
+			if v.Video.URI == "" {
+				continue
+			}
+			// The Gemini API returns the URI of the video instead of its
+			// content.
+			data, err = g.client.Files.Download(ctx, genai.NewDownloadURIFromGeneratedVideo(v), nil)
+			if err != nil {
+				return Result{}, fmt.Errorf("failed to download video: %w", err)
+			}
+		}
+		mimeType := v.Video.MIMEType
+		if mimeType == "" {
+			mimeType = "video/mp4"
+		}
+		name := artifactName(base, i, len(videos), mimeType)
+		saved, err := ctx.Artifacts().Save(ctx, name, genai.NewPartFromBytes(data, mimeType))
+		if err != nil {
+			return Result{}, fmt.Errorf("failed to save video %q: %w", name, err)
+		}
+		result.Videos = append(result.Videos, Video{Filename: name, Version: saved.Version, MIMEType: mimeType})
+	}
+	return result, nil
+}
+
+// artifactName returns the name of the i-th of n videos. An index is added
+// to base if there are several videos, and the extension of the MIME type
+// if base has none.
+func artifactName(base string, i, n int, mimeType string) string {
+	ext := path.Ext(base)
+	name := strings.TrimSuffix(base, ext)
+	if n > 1 {
+		name = fmt.Sprintf("%s_%d", name, i+1)
+	}
+	if ext == "" {
+		ext = "." + strings.TrimPrefix(mimeType, "video/")
+	}
+	return name + ext
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package veotool_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/genai"
+
+	"google.golang.org/adk/agent"
+	"google.golang.org/adk/agent/llmagent"
+	"google.golang.org/adk/artifact"
+	"google.golang.org/adk/internal/testutil"
+	"google.golang.org/adk/runner"
+	"google.golang.org/adk/session"
+	"google.golang.org/adk/tool"
+	"google.golang.org/adk/tool/veotool"
+)
+
+func TestVeoTool(t *testing.T) {
+	var gotReq map[string]any
+	var polls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1beta/models/veo-test:predictLongRunning":
+			body, _ := io.ReadAll(r.Body)
+			if err := json.Unmarshal(body, &gotReq); err != nil {
+				t.Errorf("failed to decode request: %v", err)
+			}
+			io.WriteString(w, `{"name": "operations/op1", "done": false}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/v1beta/operations/op1":
+			polls++
+			if polls < 2 {
+				io.WriteString(w, `{"name": "operations/op1", "done": false}`)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{
+				"name": "operations/op1",
+				"done": true,
+				"response": map[string]any{"generateVideoResponse": map[string]any{
+					"generatedSamples": []map[string]any{
+						{"video": map[string]any{"encodedVideo": base64.StdEncoding.EncodeToString([]byte("video")), "encoding": "video/mp4"}},
+					},
+				}},
+			})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := t.Context()
+	veo, err := veotool.New(ctx, veotool.Config{
+		ClientConfig: &genai.ClientConfig{
+			APIKey:      "fakekey",
+			Backend:     genai.BackendGeminiAPI,
+			HTTPOptions: genai.HTTPOptions{BaseURL: srv.URL},
+		},
+		Model:        "veo-test",
+		PollInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	llm := &testutil.MockModel{Responses: []*genai.Content{
+		genai.NewContentFromFunctionCall(veotool.DefaultName, map[string]any{
+			"prompt":           "a cat",
+			"filename":         "cat",
+			"aspect_ratio":     "16:9",
+			"duration_seconds": 5,
+		}, genai.RoleModel),
+		genai.NewContentFromText("done", genai.RoleModel),
+	}}
+	a, err := llmagent.New(llmagent.Config{Name: "agent", Model: llm, Tools: []tool.Tool{veo}})
+	if err != nil {
+		t.Fatalf("llmagent.New() failed: %v", err)
+	}
+	sessionService := session.InMemoryService()
+	artifactService := artifact.InMemoryService()
+	r, err := runner.New(runner.Config{AppName: "app", Agent: a, SessionService: sessionService, ArtifactService: artifactService})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessionService.Create(ctx, &session.CreateRequest{AppName: "app", UserID: "user", SessionID: "session"}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := r.RunAndCollect(ctx, "user", "session", genai.NewContentFromText("film a cat", genai.RoleUser), agent.RunConfig{})
+	if err != nil {
+		t.Fatalf("RunAndCollect() failed: %v", err)
+	}
+
+	var resp map[string]any
+	for _, ev := range got.Events {
+		if ev.Content == nil {
+			continue
+		}
+		for _, p := range ev.Content.Parts {
+			if p.FunctionResponse != nil {
+				resp = p.FunctionResponse.Response
+			}
+		}
+	}
+	wantResp := map[string]any{
+		"videos": []any{
+			map[string]any{"filename": "cat.mp4", "version": float64(1), "mime_type": "video/mp4"},
+		},
+		"model": "veo-test",
+	}
+	if diff := cmp.Diff(wantResp, resp); diff != "" {
+		t.Errorf("function response mismatch (-want +got):\n%s", diff)
+	}
+	if polls != 2 {
+		t.Errorf("got %d polls, want 2", polls)
+	}
+	params, _ := gotReq["parameters"].(map[string]any)
+	if params["aspectRatio"] != "16:9" || params["durationSeconds"] != float64(5) {
+		t.Errorf("request parameters = %v, want aspectRatio 16:9 and durationSeconds 5", params)
+	}
+
+	loaded, err := artifactService.Load(ctx, &artifact.LoadRequest{AppName: "app", UserID: "user", SessionID: "session", FileName: "cat.mp4"})
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if got := string(loaded.Part.InlineData.Data); got != "video" {
+		t.Errorf("artifact content = %q, want %q", got, "video")
+	}
+}